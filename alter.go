@@ -0,0 +1,631 @@
+// Copyright 2023 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/disco"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/roaring"
+	"github.com/featurebasedb/featurebase/v3/tracing"
+	"github.com/pkg/errors"
+)
+
+// The type of a field is changed by rewriting its values, shard by shard, into
+// a new field which takes the field's place once every shard has been
+// rewritten. Until then, queries keep reading the field as it was, and writes
+// to it are refused on every node, so that none of them is lost. A value
+// which can't be represented in the new type fails the whole alteration, and
+// the field is left as it was.
+
+// alterFieldSuffix is appended to the name of a field to name the field into
+// which its values are rewritten.
+const alterFieldSuffix = "__alter"
+
+// AlterFieldName returns the name of the field into which the values of field
+// are rewritten while its type is being changed.
+func AlterFieldName(field string) string {
+	return field + alterFieldSuffix
+}
+
+// alterWriteBlockTimeout is how long writes to a field being altered stay
+// blocked on a node unless the block is renewed, so that a node doesn't go on
+// refusing them if the node altering the field goes away.
+const alterWriteBlockTimeout = time.Minute
+
+// Field alteration states.
+const (
+	FieldAlterationRunning = "running"
+	FieldAlterationDone    = "done"
+	FieldAlterationFailed  = "failed"
+)
+
+// FieldAlteration describes the progress of a change to the type of a field.
+type FieldAlteration struct {
+	Index       string
+	Field       string
+	Status      string
+	ShardsDone  int64
+	ShardsTotal int64
+	Error       string
+	Start       time.Time
+	End         time.Time
+}
+
+// fieldAlterations holds the field alterations started on this node.
+type fieldAlterations struct {
+	mu   sync.Mutex
+	list []*FieldAlteration
+}
+
+// add registers fa, unless the field is already being altered.
+func (a *fieldAlterations) add(fa *FieldAlteration) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, other := range a.list {
+		if other.Index == fa.Index && other.Field == fa.Field && other.Status == FieldAlterationRunning {
+			return newConflictError(errors.Errorf("field '%s' is already being altered", fa.Field))
+		}
+	}
+	a.list = append(a.list, fa)
+	return nil
+}
+
+// update applies fn to fa while holding the lock.
+func (a *fieldAlterations) update(fa *FieldAlteration, fn func(fa *FieldAlteration)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	fn(fa)
+}
+
+// all returns a copy of every alteration, in the order they were started.
+func (a *fieldAlterations) all() []FieldAlteration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]FieldAlteration, len(a.list))
+	for i, fa := range a.list {
+		out[i] = *fa
+	}
+	return out
+}
+
+// FieldAlterations returns the field alterations started on this node.
+func (api *API) FieldAlterations() []FieldAlteration {
+	return api.alterations.all()
+}
+
+// AlterField changes the named field to the type described by opts. The
+// field's values are rewritten in the background; FieldAlterations reports
+// the progress. Writes to the field are refused until it's done.
+func (api *API) AlterField(ctx context.Context, indexName, fieldName string, opts ...FieldOption) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.AlterField")
+	defer span.Finish()
+
+	if err := api.validate(apiAlterField); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return newNotFoundError(ErrIndexNotFound, indexName)
+	}
+	field := index.Field(fieldName)
+	if field == nil || fieldName == existenceFieldName {
+		return newNotFoundError(ErrFieldNotFound, fieldName)
+	}
+
+	// The rewritten field tracks existence if the original did.
+	if field.Options().TrackExistence {
+		opts = append(opts, OptFieldTrackExistence())
+	}
+	dst, err := newFieldOptions(opts...)
+	if err != nil {
+		return NewBadRequestError(errors.Wrap(err, "applying option"))
+	}
+	conv, err := newFieldConversion(field.Options(), *dst)
+	if err != nil {
+		return NewBadRequestError(err)
+	}
+
+	target := AlterFieldName(fieldName)
+	fa := &FieldAlteration{
+		Index:  indexName,
+		Field:  fieldName,
+		Status: FieldAlterationRunning,
+		Start:  time.Now().UTC(),
+	}
+	if err := api.alterations.add(fa); err != nil {
+		return err
+	}
+	fail := func(err error) error {
+		api.alterations.update(fa, func(fa *FieldAlteration) {
+			fa.Status = FieldAlterationFailed
+			fa.Error = err.Error()
+			fa.End = time.Now().UTC()
+		})
+		return err
+	}
+
+	unblock, err := api.blockFieldWrites(ctx, indexName, fieldName)
+	if err != nil {
+		return fail(errors.Wrap(err, "blocking writes"))
+	}
+
+	// A target left behind by an alteration which didn't finish is
+	// replaced.
+	if index.Field(target) != nil {
+		if err := api.DeleteField(ctx, indexName, target); err != nil {
+			unblock()
+			return fail(errors.Wrap(err, "deleting previous alteration"))
+		}
+	}
+	if _, err := api.CreateField(ctx, indexName, target, opts...); err != nil {
+		unblock()
+		return fail(errors.Wrap(err, "creating field to rewrite into"))
+	}
+
+	if ok := api.server.addToWaitGroup(1); !ok {
+		unblock()
+		return fail(errors.New("server closing"))
+	}
+	go func() {
+		defer api.server.wg.Done()
+		defer unblock()
+
+		// The block is renewed until the rewrite is done. If it can't be,
+		// writes may have been let through, so the rewrite is abandoned.
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			ticker := time.NewTicker(alterWriteBlockTimeout / 3)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if _, err := api.blockFieldWrites(ctx, indexName, fieldName); err != nil {
+						api.server.logger.Errorf("renewing block on writes to %s/%s: %v", indexName, fieldName, err)
+						cancel()
+						return
+					}
+				}
+			}
+		}()
+
+		if err := api.rewriteField(ctx, fa, conv, index, field, target); err != nil {
+			api.server.logger.Errorf("altering field %s/%s: %v", indexName, fieldName, err)
+			// When the server is closing, the target is left for the next
+			// alteration of the field to replace.
+			select {
+			case <-api.server.closing:
+			default:
+				if err := api.DeleteField(context.Background(), indexName, target); err != nil {
+					api.server.logger.Errorf("deleting field %s/%s: %v", indexName, target, err)
+				}
+			}
+			_ = fail(err)
+			return
+		}
+		api.alterations.update(fa, func(fa *FieldAlteration) {
+			fa.Status = FieldAlterationDone
+			fa.End = time.Now().UTC()
+		})
+	}()
+	return nil
+}
+
+// rewriteField rewrites every shard of field into target, then replaces field
+// with target.
+func (api *API) rewriteField(ctx context.Context, fa *FieldAlteration, conv *fieldConversion, index *Index, field *Field, target string) error {
+	shards := field.AvailableShards(includeRemote).Slice()
+	api.alterations.update(fa, func(fa *FieldAlteration) {
+		fa.ShardsTotal = int64(len(shards))
+	})
+
+	for _, shard := range shards {
+		select {
+		case <-api.server.closing:
+			return errors.New("server closing")
+		default:
+		}
+		if err := api.rewriteFieldShard(ctx, conv, index, field, target, shard); err != nil {
+			return errors.Wrapf(err, "shard %d", shard)
+		}
+		api.alterations.update(fa, func(fa *FieldAlteration) {
+			fa.ShardsDone++
+		})
+	}
+
+	// Queries are held off on every node while target takes the field's
+	// place, so none of them finds it missing. Writes to it are still
+	// blocked.
+	id := fmt.Sprintf("alter-%s-%s-%d", index.Name(), field.Name(), fa.Start.UnixNano())
+	release, err := NewOnPremImporter(api).holdIndexes(ctx, id, []string{index.Name()})
+	if err != nil {
+		return errors.Wrap(err, "holding off queries")
+	}
+	defer release()
+
+	if err := api.DeleteField(ctx, index.Name(), field.Name()); err != nil {
+		return errors.Wrap(err, "deleting altered field")
+	}
+	return api.RenameField(ctx, index.Name(), target, field.Name())
+}
+
+// blockFieldWrites blocks writes to a field on every node, and returns the
+// function which unblocks them again.
+func (api *API) blockFieldWrites(ctx context.Context, indexName, fieldName string) (func(), error) {
+	client := api.holder.executor.client
+	var blocked []*disco.Node
+	unblock := func() {
+		ctx := detachContext(ctx)
+		for _, node := range blocked {
+			var err error
+			if node.ID == api.NodeID() {
+				err = api.UnblockFieldWrites(ctx, indexName, fieldName)
+			} else {
+				err = client.UnblockFieldWrites(ctx, &node.URI, indexName, fieldName)
+			}
+			if err != nil {
+				api.server.logger.Errorf("unblocking writes to %s/%s on node %s: %v", indexName, fieldName, node.ID, err)
+			}
+		}
+	}
+	for _, node := range api.cluster.Nodes() {
+		var err error
+		if node.ID == api.NodeID() {
+			err = api.BlockFieldWrites(ctx, indexName, fieldName, alterWriteBlockTimeout)
+		} else {
+			err = client.BlockFieldWrites(ctx, &node.URI, indexName, fieldName, alterWriteBlockTimeout)
+		}
+		if err != nil {
+			unblock()
+			return nil, errors.Wrapf(err, "blocking writes on node %s", node.ID)
+		}
+		blocked = append(blocked, node)
+	}
+	return unblock, nil
+}
+
+// BlockFieldWriteRequest is the body of a request to block writes to a
+// field on a node.
+type BlockFieldWriteRequest struct {
+	Timeout time.Duration `json:"timeout"`
+}
+
+// BlockFieldWrites refuses writes to the named field on this node until
+// UnblockFieldWrites is called, or timeout has passed.
+func (api *API) BlockFieldWrites(ctx context.Context, indexName, fieldName string, timeout time.Duration) error {
+	if err := api.validate(apiBlockFieldWrites); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return newNotFoundError(ErrIndexNotFound, indexName)
+	}
+	index.blockFieldWrites(fieldName, timeout)
+	return nil
+}
+
+// UnblockFieldWrites lets writes to the named field through again.
+func (api *API) UnblockFieldWrites(ctx context.Context, indexName, fieldName string) error {
+	if err := api.validate(apiBlockFieldWrites); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+	if index := api.holder.Index(indexName); index != nil {
+		index.unblockFieldWrites(fieldName)
+	}
+	return nil
+}
+
+func (i *Index) blockFieldWrites(name string, timeout time.Duration) {
+	i.writeBlockMu.Lock()
+	defer i.writeBlockMu.Unlock()
+	i.writeBlocks[name] = time.Now().Add(timeout)
+}
+
+func (i *Index) unblockFieldWrites(name string) {
+	i.writeBlockMu.Lock()
+	defer i.writeBlockMu.Unlock()
+	delete(i.writeBlocks, name)
+}
+
+// checkFieldWritable returns an error if writes to the named field are
+// blocked. An empty name stands for every field, as when records are
+// cleared.
+func (i *Index) checkFieldWritable(name string) error {
+	i.writeBlockMu.Lock()
+	defer i.writeBlockMu.Unlock()
+	now := time.Now()
+	for field, expires := range i.writeBlocks {
+		if now.After(expires) {
+			delete(i.writeBlocks, field)
+			continue
+		}
+		if name == "" || name == field {
+			return newConflictError(errors.Errorf("field '%s' is being altered, and can't be written to until that's done", field))
+		}
+	}
+	return nil
+}
+
+// checkShardImportWritable returns an error if req writes to a field whose
+// writes are blocked.
+func (i *Index) checkShardImportWritable(req *ImportRoaringShardRequest) error {
+	for _, u := range req.Views {
+		if u.Field == "" && !u.ClearRecords {
+			continue
+		}
+		if err := i.checkFieldWritable(u.Field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCallWritable returns an error if c writes to a field whose writes are
+// blocked.
+func (i *Index) checkCallWritable(c *pql.Call) error {
+	switch c.Name {
+	case "Set", "Clear", "ClearRow", "Store":
+		name, err := c.FieldArg()
+		if err != nil {
+			// left for the call itself to report
+			return nil
+		}
+		return i.checkFieldWritable(name)
+	case "Delete":
+		return i.checkFieldWritable("")
+	}
+	return nil
+}
+
+// rewriteFieldShard converts the values of field in a shard, and imports them
+// into target.
+func (api *API) rewriteFieldShard(ctx context.Context, conv *fieldConversion, index *Index, field *Field, target string, shard uint64) error {
+	// Extract(All(), Rows(<field>))
+	c := &pql.Call{
+		Name: "Extract",
+		Children: []*pql.Call{
+			{Name: "All"},
+			{Name: "Rows", Args: map[string]interface{}{"field": field.Name()}},
+		},
+	}
+	qcx := api.Txf().NewQcx()
+	res, err := api.server.executor.executeExtract(ctx, qcx, index.Name(), c, []uint64{shard}, &ExecOptions{})
+	qcx.Abort()
+	if err != nil {
+		return errors.Wrap(err, "extracting values")
+	}
+	m, ok := res.(ExtractedIDMatrix)
+	if !ok {
+		return errors.Errorf("unexpected extract result type %T", res)
+	}
+
+	targetField := index.Field(target)
+	if targetField == nil {
+		return newNotFoundError(ErrFieldNotFound, target)
+	}
+
+	var view string
+	bm := roaring.NewBitmap()
+	exists := roaring.NewBitmap()
+	if conv.bsi {
+		bsig := targetField.bsiGroup(target)
+		if bsig == nil {
+			return ErrBSIGroupNotFound
+		}
+		view = viewBSIGroupPrefix + target
+		for _, col := range m.Columns {
+			if len(col.Rows) == 0 || len(col.Rows[0]) == 0 {
+				continue
+			}
+			v, err := conv.value(int64(col.Rows[0][0]))
+			if err != nil {
+				return errors.Wrapf(err, "record %d", col.ColumnID)
+			}
+			addBSIValue(bm, col.ColumnID%ShardWidth, v-bsig.Base)
+		}
+	} else {
+		view = viewStandard
+		rowIDs, err := api.convertRowIDs(ctx, conv, index, field, target, m)
+		if err != nil {
+			return err
+		}
+		for _, col := range m.Columns {
+			if len(col.Rows) == 0 {
+				continue
+			}
+			if conv.dst.Type == FieldTypeMutex && len(col.Rows[0]) > 1 {
+				return errors.Errorf("record %d has %d values, which can't be held in a %s field", col.ColumnID, len(col.Rows[0]), FieldTypeMutex)
+			}
+			for _, id := range col.Rows[0] {
+				bm.DirectAdd(rowIDs[id]*ShardWidth + col.ColumnID%ShardWidth)
+			}
+			if conv.dst.TrackExistence && len(col.Rows[0]) > 0 {
+				exists.DirectAdd(bsiExistsBit*ShardWidth + col.ColumnID%ShardWidth)
+			}
+		}
+	}
+	if !bm.Any() {
+		return nil
+	}
+
+	req := &ImportRoaringShardRequest{Remote: true}
+	for _, u := range []struct {
+		view string
+		bm   *roaring.Bitmap
+	}{{view, bm}, {viewExistence, exists}} {
+		if !u.bm.Any() {
+			continue
+		}
+		buf := &bytes.Buffer{}
+		if _, err := u.bm.WriteTo(buf); err != nil {
+			return errors.Wrap(err, "serializing bitmap")
+		}
+		req.Views = append(req.Views, RoaringUpdate{
+			Field: target,
+			View:  u.view,
+			Set:   buf.Bytes(),
+		})
+	}
+	return NewOnPremImporter(api).ImportRoaringShard(ctx, dax.TableID(index.Name()), shard, req)
+}
+
+// convertRowIDs returns, for each row ID of field found in m, the row ID of
+// the same value in target, translating through keys where either field
+// uses them.
+func (api *API) convertRowIDs(ctx context.Context, conv *fieldConversion, index *Index, field *Field, target string, m ExtractedIDMatrix) (map[uint64]uint64, error) {
+	ids := make(map[uint64]struct{})
+	for _, col := range m.Columns {
+		if len(col.Rows) == 0 {
+			continue
+		}
+		for _, id := range col.Rows[0] {
+			ids[id] = struct{}{}
+		}
+	}
+	out := make(map[uint64]uint64, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	if conv.src.Keys == conv.dst.Keys && !conv.src.Keys {
+		for id := range ids {
+			out[id] = id
+		}
+		return out, nil
+	}
+
+	// The keys of the values, by row ID in field.
+	keys := make(map[uint64]string, len(ids))
+	if conv.src.Keys {
+		translated, err := api.cluster.translateFieldIDs(ctx, field, ids)
+		if err != nil {
+			return nil, errors.Wrap(err, "translating row ids")
+		}
+		keys = translated
+	} else {
+		for id := range ids {
+			keys[id] = strconv.FormatUint(id, 10)
+		}
+	}
+
+	if !conv.dst.Keys {
+		for id, key := range keys {
+			v, err := strconv.ParseUint(key, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("value '%s' can't be converted to an id", key)
+			}
+			out[id] = v
+		}
+		return out, nil
+	}
+
+	list := make([]string, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, key)
+	}
+	created, err := api.CreateFieldKeys(ctx, index.Name(), target, list...)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating keys")
+	}
+	for id, key := range keys {
+		out[id] = created[key]
+	}
+	return out, nil
+}
+
+// fieldConversion converts the values of a field to those of the field
+// replacing it.
+type fieldConversion struct {
+	src, dst FieldOptions
+
+	// bsi is true when both fields hold integer or decimal values; otherwise
+	// both hold sets of row IDs.
+	bsi bool
+}
+
+// newFieldConversion returns the conversion between two field types, or an
+// error if values can't be converted between them.
+func newFieldConversion(src, dst FieldOptions) (*fieldConversion, error) {
+	conv := &fieldConversion{src: src, dst: dst}
+	switch {
+	case src.TimeQuantum != "" || dst.TimeQuantum != "":
+		return nil, errors.New("a field with a time quantum can't be altered")
+	case src.JSON || src.GeoPoint || src.BitVector != 0 || dst.JSON || dst.GeoPoint || dst.BitVector != 0:
+		return nil, errors.New("a json, geopoint or bitvector field can't be altered")
+	case src.ForeignIndex != "" || dst.ForeignIndex != "":
+		return nil, errors.New("a field with a foreign index can't be altered")
+	case isIntOrDecimal(src.Type) && isIntOrDecimal(dst.Type):
+		conv.bsi = true
+	case isSetOrMutex(src.Type) && isSetOrMutex(dst.Type):
+	default:
+		return nil, errors.Errorf("a field of type %s can't be altered to %s", src.Type, dst.Type)
+	}
+	return conv, nil
+}
+
+func isIntOrDecimal(typ string) bool {
+	return typ == FieldTypeInt || typ == FieldTypeDecimal
+}
+
+func isSetOrMutex(typ string) bool {
+	return typ == FieldTypeSet || typ == FieldTypeMutex
+}
+
+// value converts a stored integer or decimal value, returning an error if it
+// can't be held exactly by the new field.
+func (c *fieldConversion) value(v int64) (int64, error) {
+	var srcScale, dstScale int64
+	if c.src.Type == FieldTypeDecimal {
+		srcScale = c.src.Scale
+	}
+	if c.dst.Type == FieldTypeDecimal {
+		dstScale = c.dst.Scale
+	}
+
+	out := v
+	if dstScale >= srcScale {
+		mul := pql.Pow10(dstScale - srcScale)
+		if v > math.MaxInt64/mul || v < math.MinInt64/mul {
+			return 0, errors.Errorf("value %s overflows the new type", pql.NewDecimal(v, srcScale))
+		}
+		out = v * mul
+	} else {
+		div := pql.Pow10(srcScale - dstScale)
+		if v%div != 0 {
+			return 0, errors.Errorf("value %s would lose precision", pql.NewDecimal(v, srcScale))
+		}
+		out = v / div
+	}
+
+	if d := pql.NewDecimal(out, dstScale); d.LessThan(c.dst.Min) || d.GreaterThan(c.dst.Max) {
+		return 0, errors.Errorf("value %s is out of range [%s, %s]", pql.NewDecimal(v, srcScale), c.dst.Min, c.dst.Max)
+	}
+	return out, nil
+}
+
+// addBSIValue adds the bits for v, already adjusted by the field's base, in
+// the given column of a BSI fragment bitmap.
+func addBSIValue(bm *roaring.Bitmap, col uint64, v int64) {
+	bm.DirectAdd(bsiExistsBit*ShardWidth + col)
+	uv := uint64(v)
+	if v < 0 {
+		bm.DirectAdd(bsiSignBit*ShardWidth + col)
+		uv = uint64(-v)
+	}
+	for i := uint64(0); uv != 0; i, uv = i+1, uv>>1 {
+		if uv&1 != 0 {
+			bm.DirectAdd((bsiOffsetBit+i)*ShardWidth + col)
+		}
+	}
+}
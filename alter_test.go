@@ -0,0 +1,260 @@
+// Copyright 2023 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/test"
+)
+
+// awaitFieldAlteration waits for the most recent alteration of field to stop
+// running, and returns it.
+func awaitFieldAlteration(t *testing.T, api *pilosa.API, index, field string) pilosa.FieldAlteration {
+	t.Helper()
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		var last *pilosa.FieldAlteration
+		for _, fa := range api.FieldAlterations() {
+			if fa.Index == index && fa.Field == field {
+				fa := fa
+				last = &fa
+			}
+		}
+		if last != nil && last.Status != pilosa.FieldAlterationRunning {
+			return *last
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("alteration of %s/%s didn't finish", index, field)
+	return pilosa.FieldAlteration{}
+}
+
+// queryColumns returns the columns of the row returned by query.
+func queryColumns(t *testing.T, m *test.Command, index, query string) []uint64 {
+	t.Helper()
+	resp := m.QueryAPI(t, &pilosa.QueryRequest{Index: index, Query: query})
+	return resp.Results[0].(*pilosa.Row).Columns()
+}
+
+func TestAPI_RenameField(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+	ctx := context.Background()
+	m0 := c.GetNode(0)
+	index := c.Idx()
+
+	m0.MustCreateIndex(t, index, pilosa.IndexOptions{TrackExistence: true})
+	m0.MustCreateField(t, index, "i", pilosa.OptFieldTypeInt(-100, 100))
+	m0.MustCreateField(t, index, "s")
+	m0.QueryAPI(t, &pilosa.QueryRequest{Index: index, Query: fmt.Sprintf(
+		"Set(1, i=10) Set(%d, i=-20) Set(1, s=3) Set(%[1]d, s=4)", pilosa.ShardWidth+2)})
+
+	if err := m0.API.RenameField(ctx, index, "i", "j"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m0.API.RenameField(ctx, index, "s", "t"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m0.API.RenameField(ctx, index, "t", "j"); err == nil {
+		t.Fatal("expected error renaming to an existing field")
+	}
+
+	for i := 0; i < 3; i++ {
+		node := c.GetNode(i)
+		idx := node.API.Holder().Index(index)
+		if idx.Field("i") != nil || idx.Field("s") != nil {
+			t.Fatalf("node %d: renamed fields still exist", i)
+		}
+		if got, exp := queryColumns(t, node, index, "Row(j > 0)"), []uint64{1}; !reflect.DeepEqual(got, exp) {
+			t.Fatalf("node %d: expected %v, got %v", i, exp, got)
+		}
+		if got, exp := queryColumns(t, node, index, "Row(j < 0)"), []uint64{pilosa.ShardWidth + 2}; !reflect.DeepEqual(got, exp) {
+			t.Fatalf("node %d: expected %v, got %v", i, exp, got)
+		}
+		if got, exp := queryColumns(t, node, index, "Union(Row(t=3), Row(t=4))"), []uint64{1, pilosa.ShardWidth + 2}; !reflect.DeepEqual(got, exp) {
+			t.Fatalf("node %d: expected %v, got %v", i, exp, got)
+		}
+	}
+
+	// The old name can be used for a new field.
+	m0.MustCreateField(t, index, "i")
+	if got := queryColumns(t, m0, index, "Row(i=1)"); len(got) != 0 {
+		t.Fatalf("expected new field to be empty, got %v", got)
+	}
+}
+
+func TestAPI_RenameFieldDerived(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	ctx := context.Background()
+	m0 := c.GetNode(0)
+	index := c.Idx()
+
+	m0.MustCreateIndex(t, index, pilosa.IndexOptions{TrackExistence: true})
+	m0.MustCreateField(t, index, "g", pilosa.OptFieldTypeInt(-1<<62, 1<<62), pilosa.OptFieldGeoPoint())
+	m0.MustCreateField(t, index, pilosa.GeoCellFieldName("g"))
+	m0.MustCreateField(t, index, pilosa.GeoCellFieldName("k"))
+
+	// The cells of a geopoint field are renamed along with it.
+	if err := m0.API.RenameField(ctx, index, "g", "h"); err != nil {
+		t.Fatal(err)
+	}
+	idx := m0.API.Holder().Index(index)
+	if idx.Field(pilosa.GeoCellFieldName("g")) != nil {
+		t.Fatal("expected cell field to be renamed")
+	} else if idx.Field(pilosa.GeoCellFieldName("h")) == nil {
+		t.Fatal("expected renamed cell field")
+	}
+
+	// Nothing is renamed if the new name of the cells is taken.
+	if err := m0.API.RenameField(ctx, index, "h", "k"); err == nil {
+		t.Fatal("expected error renaming to a field whose cells exist")
+	}
+	if idx.Field("h") == nil || idx.Field(pilosa.GeoCellFieldName("h")) == nil || idx.Field("k") != nil {
+		t.Fatal("expected fields to be left as they were")
+	}
+}
+
+func TestAPI_AlterField(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+	ctx := context.Background()
+	m0 := c.GetNode(0)
+	index := c.Idx()
+
+	m0.MustCreateIndex(t, index, pilosa.IndexOptions{TrackExistence: true})
+	m0.MustCreateField(t, index, "i", pilosa.OptFieldTypeInt(-100, 100))
+	m0.MustCreateField(t, index, "s")
+	m0.MustCreateField(t, index, "m")
+	m0.QueryAPI(t, &pilosa.QueryRequest{Index: index, Query: fmt.Sprintf(
+		"Set(1, i=10) Set(%d, i=-20) Set(1, s=1) Set(1, s=2) Set(%[1]d, s=2) Set(1, m=7) Set(%[1]d, m=8)", pilosa.ShardWidth+2)})
+
+	t.Run("IntToDecimal", func(t *testing.T) {
+		if err := m0.API.AlterField(ctx, index, "i", pilosa.OptFieldTypeDecimal(2)); err != nil {
+			t.Fatal(err)
+		}
+		fa := awaitFieldAlteration(t, m0.API, index, "i")
+		if fa.Status != pilosa.FieldAlterationDone {
+			t.Fatalf("unexpected alteration: %+v", fa)
+		} else if fa.ShardsDone != 2 || fa.ShardsTotal != 2 {
+			t.Fatalf("expected 2 shards done, got %d of %d", fa.ShardsDone, fa.ShardsTotal)
+		}
+		for i := 0; i < 3; i++ {
+			node := c.GetNode(i)
+			idx := node.API.Holder().Index(index)
+			if typ := idx.Field("i").Options().Type; typ != pilosa.FieldTypeDecimal {
+				t.Fatalf("node %d: expected decimal field, got %s", i, typ)
+			} else if idx.Field(pilosa.AlterFieldName("i")) != nil {
+				t.Fatalf("node %d: alteration target still exists", i)
+			}
+			if got, exp := queryColumns(t, node, index, "Row(i == 10.00)"), []uint64{1}; !reflect.DeepEqual(got, exp) {
+				t.Fatalf("node %d: expected %v, got %v", i, exp, got)
+			}
+			if got, exp := queryColumns(t, node, index, "Row(i < -19.5)"), []uint64{pilosa.ShardWidth + 2}; !reflect.DeepEqual(got, exp) {
+				t.Fatalf("node %d: expected %v, got %v", i, exp, got)
+			}
+		}
+	})
+
+	t.Run("Narrowing", func(t *testing.T) {
+		if err := m0.API.AlterField(ctx, index, "i", pilosa.OptFieldTypeInt(0, 100)); err != nil {
+			t.Fatal(err)
+		}
+		fa := awaitFieldAlteration(t, m0.API, index, "i")
+		if fa.Status != pilosa.FieldAlterationFailed || fa.Error == "" {
+			t.Fatalf("expected alteration to fail, got %+v", fa)
+		}
+		if typ := m0.API.Holder().Index(index).Field("i").Options().Type; typ != pilosa.FieldTypeDecimal {
+			t.Fatalf("expected field to stay decimal, got %s", typ)
+		}
+	})
+
+	t.Run("SetToMutex", func(t *testing.T) {
+		// Record 1 has two values, which a mutex can't hold.
+		if err := m0.API.AlterField(ctx, index, "s", pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize)); err != nil {
+			t.Fatal(err)
+		}
+		fa := awaitFieldAlteration(t, m0.API, index, "s")
+		if fa.Status != pilosa.FieldAlterationFailed {
+			t.Fatalf("expected alteration to fail, got %+v", fa)
+		}
+		idx := m0.API.Holder().Index(index)
+		if typ := idx.Field("s").Options().Type; typ != pilosa.FieldTypeSet {
+			t.Fatalf("expected field to stay a set, got %s", typ)
+		} else if idx.Field(pilosa.AlterFieldName("s")) != nil {
+			t.Fatal("alteration target still exists")
+		}
+		if got, exp := queryColumns(t, m0, index, "Row(s=2)"), []uint64{1, pilosa.ShardWidth + 2}; !reflect.DeepEqual(got, exp) {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+
+	t.Run("AddKeys", func(t *testing.T) {
+		if err := m0.API.AlterField(ctx, index, "m", pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize), pilosa.OptFieldKeys()); err != nil {
+			t.Fatal(err)
+		}
+		fa := awaitFieldAlteration(t, m0.API, index, "m")
+		if fa.Status != pilosa.FieldAlterationDone {
+			t.Fatalf("unexpected alteration: %+v", fa)
+		}
+		for i := 0; i < 3; i++ {
+			node := c.GetNode(i)
+			if !node.API.Holder().Index(index).Field("m").Keys() {
+				t.Fatalf("node %d: expected keyed field", i)
+			}
+			if got, exp := queryColumns(t, node, index, `Row(m="8")`), []uint64{pilosa.ShardWidth + 2}; !reflect.DeepEqual(got, exp) {
+				t.Fatalf("node %d: expected %v, got %v", i, exp, got)
+			}
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		if err := m0.API.AlterField(ctx, index, "s", pilosa.OptFieldTypeInt(0, 10)); err == nil {
+			t.Fatal("expected error altering a set field to int")
+		}
+		if err := m0.API.AlterField(ctx, index, "nope", pilosa.OptFieldTypeInt(0, 10)); err == nil {
+			t.Fatal("expected error altering a field which doesn't exist")
+		}
+	})
+
+	t.Run("WritesBlocked", func(t *testing.T) {
+		// Writes to a field are refused while it's being altered, and let
+		// through once it's done.
+		m1 := c.GetNode(1)
+		m1.QueryAPI(t, &pilosa.QueryRequest{Index: index, Query: `Set(3, m="9")`})
+		if err := m1.API.BlockFieldWrites(ctx, index, "s", time.Minute); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m1.API.Query(ctx, &pilosa.QueryRequest{Index: index, Query: "Set(5, s=1)"}); err == nil {
+			t.Fatal("expected error writing to a blocked field")
+		}
+		if _, err := m1.API.Query(ctx, &pilosa.QueryRequest{Index: index, Query: "Delete(Row(s=1))"}); err == nil {
+			t.Fatal("expected error deleting records with a blocked field")
+		}
+		if err := m1.API.ImportRoaringShard(ctx, index, 0, &pilosa.ImportRoaringShardRequest{
+			Remote: true,
+			Views:  []pilosa.RoaringUpdate{{Field: "s", View: "standard"}},
+		}); err == nil {
+			t.Fatal("expected error importing into a blocked field")
+		}
+		m1.QueryAPI(t, &pilosa.QueryRequest{Index: index, Query: "Set(5, i=1.5)"})
+
+		if err := m1.API.UnblockFieldWrites(ctx, index, "s"); err != nil {
+			t.Fatal(err)
+		}
+		m1.QueryAPI(t, &pilosa.QueryRequest{Index: index, Query: "Set(5, s=1)"})
+
+		// A block which isn't renewed expires.
+		if err := m1.API.BlockFieldWrites(ctx, index, "s", time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+		m1.QueryAPI(t, &pilosa.QueryRequest{Index: index, Query: "Set(6, s=1)"})
+	})
+}
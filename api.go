@@ -37,6 +37,7 @@ import (
 	planner_types "github.com/featurebasedb/featurebase/v3/sql3/planner/types"
 	"github.com/featurebasedb/featurebase/v3/tracing"
 	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -64,6 +65,9 @@ type API struct {
 	// isComputeNode is set to true if this node is running as a DAX compute
 	// node.
 	isComputeNode bool
+
+	// alterations holds the field alterations started on this node.
+	alterations fieldAlterations
 }
 
 func (api *API) Holder() *Holder {
@@ -139,7 +143,7 @@ func NewAPI(opts ...apiOption) (*API, error) {
 		}()
 	}
 
-	api.tracker = newQueryTracker(api.server.queryHistoryLength)
+	api.tracker = newQueryTracker(api.server.queryHistory)
 
 	return api, nil
 }
@@ -197,7 +201,6 @@ func (api *API) Close() error {
 
 	close(api.importWork)
 	api.importWorkersWG.Wait()
-	api.tracker.Stop()
 	return nil
 }
 
@@ -206,7 +209,7 @@ func (api *API) Txf() *TxFactory {
 }
 
 // Query parses a PQL query out of the request and executes it.
-func (api *API) Query(ctx context.Context, req *QueryRequest) (QueryResponse, error) {
+func (api *API) Query(ctx context.Context, req *QueryRequest) (resp QueryResponse, err error) {
 	start := time.Now()
 	span, ctx := tracing.StartSpanFromContext(ctx, "API.Query")
 	defer span.Finish()
@@ -216,7 +219,13 @@ func (api *API) Query(ctx context.Context, req *QueryRequest) (QueryResponse, er
 	}
 
 	if !req.Remote {
-		defer api.tracker.Finish(api.tracker.Start(req.Query, req.SQLQuery, api.server.nodeID, req.Index, start))
+		userID, _ := fbcontext.UserID(ctx)
+		stats := &QueryStats{}
+		ctx = WithQueryStats(ctx, stats)
+		q := api.tracker.Start(req.Query, req.SQLQuery, api.server.nodeID, req.Index, userID, start)
+		defer func() {
+			api.tracker.Finish(q, queryResultCount(resp.Results), stats.ShardsScanned(), err)
+		}()
 	}
 
 	return api.query(ctx, req)
@@ -263,6 +272,10 @@ func (api *API) CreateIndex(ctx context.Context, indexName string, options Index
 		return nil, errors.Wrap(err, "validating api method")
 	}
 
+	if err := validatePrimaryKey(options); err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
 	// Populate the create index message.
 	ts := timestamp()
 	cim := &CreateIndexMessage{
@@ -615,6 +628,10 @@ func combineForExistence(inputRoaringData []byte) ([]byte, error) {
 // (shard*ShardWidth)+(i%ShardWidth). That is to say that "data" represents all
 // of the rows in this shard of this field concatenated together in one long
 // bitmap.
+//
+// The data isn't checked against the field's NOT NULL, DEFAULT and CHECK
+// constraints; like ImportRoaringShard, it's expected to come from a batch,
+// which has already applied them.
 func (api *API) ImportRoaring(ctx context.Context, indexName, fieldName string, shard uint64, remote bool, req *ImportRoaringRequest) (err0 error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "API.ImportRoaring")
 	span.LogKV("index", indexName, "field", fieldName)
@@ -629,6 +646,9 @@ func (api *API) ImportRoaring(ctx context.Context, indexName, fieldName string,
 	if index == nil || field == nil {
 		return err
 	}
+	if err := index.checkFieldWritable(fieldName); err != nil {
+		return err
+	}
 
 	// This node only handles the shard(s) that it owns.
 	if api.isComputeNode {
@@ -741,6 +761,12 @@ func (api *API) DeleteField(ctx context.Context, indexName string, fieldName str
 		return newNotFoundError(ErrIndexNotFound, indexName)
 	}
 
+	for _, pk := range index.Options().PrimaryKey {
+		if pk == fieldName {
+			return NewBadRequestError(errors.Errorf("field '%s' is part of the primary key", fieldName))
+		}
+	}
+
 	// Delete field from the index.
 	if err := index.DeleteField(fieldName); err != nil {
 		return errors.Wrap(err, "deleting field")
@@ -760,6 +786,67 @@ func (api *API) DeleteField(ctx context.Context, indexName string, fieldName str
 	return nil
 }
 
+// RenameField renames the named field in the named index. The field's data is
+// kept as it is; only its name changes. The fields indexing the paths of a
+// JSON field, or the cells of a geopoint field, are renamed along with it.
+func (api *API) RenameField(ctx context.Context, indexName, fieldName, newName string) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.RenameField")
+	defer span.Finish()
+
+	if err := api.validate(apiRenameField); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	// Find index.
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return newNotFoundError(ErrIndexNotFound, indexName)
+	}
+
+	field := index.Field(fieldName)
+	if field == nil {
+		return newNotFoundError(ErrFieldNotFound, fieldName)
+	}
+
+	// The fields derived from a JSON or geopoint field are renamed along
+	// with it, so none of their new names can be taken either.
+	renames := [][2]string{}
+	newDerived := derivedFieldNames(newName, field.Options())
+	for i, dname := range derivedFieldNames(fieldName, field.Options()) {
+		if index.Field(dname) == nil {
+			continue
+		}
+		if index.Field(newDerived[i]) != nil {
+			return newConflictError(errors.Wrap(ErrFieldExists, newDerived[i]))
+		}
+		renames = append(renames, [2]string{dname, newDerived[i]})
+	}
+	if index.Field(newName) != nil {
+		return newConflictError(ErrFieldExists)
+	}
+	renames = append(renames, [2]string{fieldName, newName})
+
+	for _, r := range renames {
+		// Rename the field in the index.
+		if err := index.RenameField(ctx, r[0], r[1]); err != nil {
+			return errors.Wrapf(err, "renaming field: %s", r[0])
+		}
+
+		// Send the rename field message to all nodes.
+		err := api.server.SendSync(
+			&RenameFieldMessage{
+				Index:   indexName,
+				Field:   r[0],
+				NewName: r[1],
+			})
+		if err != nil {
+			api.server.logger.Errorf("problem sending RenameField message: %s", err)
+			return errors.Wrap(err, "sending RenameField message")
+		}
+	}
+	return nil
+}
+
 // DeleteAvailableShard a shard ID from the available shard set cache.
 func (api *API) DeleteAvailableShard(_ context.Context, indexName, fieldName string, shardID uint64) error {
 	if err := api.validate(apiDeleteAvailableShard); err != nil {
@@ -1316,6 +1403,16 @@ type ImportOptions struct {
 	Presorted      bool
 	suppressLog    bool
 
+	// validated is set once the values of an import have been checked
+	// against the field's constraints, so that the nodes it's forwarded to
+	// don't check them again. Unlike IgnoreKeyCheck, it's only accepted
+	// from other nodes.
+	validated bool
+
+	// Conflict is what ImportAtomicRecord does with records which already
+	// exist.
+	Conflict ImportConflict
+
 	// test Tx atomicity if > 0
 	SimPowerLossAfter int
 }
@@ -1323,6 +1420,67 @@ type ImportOptions struct {
 // ImportOption is a functional option type for API.Import.
 type ImportOption func(*ImportOptions) error
 
+// ImportConflict is what an import does with records which already exist.
+type ImportConflict int
+
+const (
+	// ImportConflictMerge merges the imported values into the existing
+	// record. This is the default.
+	ImportConflictMerge ImportConflict = iota
+
+	// ImportConflictDoNothing leaves existing records untouched, and only
+	// imports new ones.
+	ImportConflictDoNothing
+
+	// ImportConflictUpdate replaces the existing record's values in the
+	// fields being imported, leaving its other fields untouched.
+	ImportConflictUpdate
+
+	// ImportConflictReplace clears all of the existing record's values
+	// before importing.
+	ImportConflictReplace
+)
+
+// String returns the name ParseImportConflict accepts for c.
+func (c ImportConflict) String() string {
+	switch c {
+	case ImportConflictMerge:
+		return "merge"
+	case ImportConflictDoNothing:
+		return "nothing"
+	case ImportConflictUpdate:
+		return "update"
+	case ImportConflictReplace:
+		return "replace"
+	}
+	return fmt.Sprintf("ImportConflict(%d)", int(c))
+}
+
+// ParseImportConflict returns the ImportConflict named s. The empty string
+// is ImportConflictMerge.
+func ParseImportConflict(s string) (ImportConflict, error) {
+	switch strings.ToLower(s) {
+	case "", "merge":
+		return ImportConflictMerge, nil
+	case "nothing":
+		return ImportConflictDoNothing, nil
+	case "update":
+		return ImportConflictUpdate, nil
+	case "replace":
+		return ImportConflictReplace, nil
+	}
+	return ImportConflictMerge, errors.Errorf("invalid conflict action '%s'; expected merge, nothing, update or replace", s)
+}
+
+// OptImportOptionsConflict is a functional option on ImportOption used to
+// specify what ImportAtomicRecord does with records which already exist.
+func OptImportOptionsConflict(c ImportConflict) ImportOption {
+	return func(o *ImportOptions) error {
+		o.Conflict = c
+		return nil
+	}
+}
+
 // OptImportOptionsClear is a functional option on ImportOption
 // used to specify whether the import is a set or clear operation.
 func OptImportOptionsClear(c bool) ImportOption {
@@ -1348,6 +1506,15 @@ func OptImportOptionsPresorted(b bool) ImportOption {
 	}
 }
 
+// optImportOptionsValidated marks an import as already checked against the
+// field's constraints.
+func optImportOptionsValidated(b bool) ImportOption {
+	return func(o *ImportOptions) error {
+		o.validated = b
+		return nil
+	}
+}
+
 func OptImportOptionsSuppressLog(b bool) ImportOption {
 	return func(o *ImportOptions) error {
 		o.suppressLog = b
@@ -1389,6 +1556,14 @@ func (api *API) ImportAtomicRecord(ctx context.Context, qcx *Qcx, req *AtomicRec
 		return errors.Wrap(err, "setting up import options")
 	}
 
+	// Records which already exist are dealt with first, in the same Tx, so
+	// that nothing can come between the check and the import.
+	if options.Conflict != ImportConflictMerge {
+		if err := api.resolveAtomicRecordConflicts(qcx, idx, req, options.Conflict); err != nil {
+			return errors.Wrap(err, "ImportAtomicRecord resolving conflicts")
+		}
+	}
+
 	// BSIs (Values)
 	for _, ivr := range req.Ivr {
 		tot++
@@ -1419,6 +1594,105 @@ func (api *API) ImportAtomicRecord(ctx context.Context, qcx *Qcx, req *AtomicRec
 	return nil
 }
 
+// resolveAtomicRecordConflicts finds the records in req which already exist,
+// and then, depending on conflict, drops them from req, or clears their
+// existing values from the fields req writes to or from the whole shard.
+func (api *API) resolveAtomicRecordConflicts(qcx *Qcx, idx *Index, req *AtomicRecord, conflict ImportConflict) (err0 error) {
+	if idx.existenceField() == nil {
+		return errors.Errorf("index '%s' does not track existence, which is needed to find conflicting records", idx.Name())
+	}
+
+	var ids []uint64
+	for _, ivr := range req.Ivr {
+		if len(ivr.ColumnKeys) > 0 {
+			return errors.New("column keys must be translated before resolving conflicts")
+		}
+		ids = append(ids, ivr.ColumnIDs...)
+	}
+	for _, ir := range req.Ir {
+		if len(ir.ColumnKeys) > 0 {
+			return errors.New("column keys must be translated before resolving conflicts")
+		}
+		ids = append(ids, ir.ColumnIDs...)
+	}
+
+	tx, finisher, err := qcx.GetTx(Txo{Write: true, Index: idx, Shard: req.Shard})
+	if err != nil {
+		return err
+	}
+	defer finisher(&err0)
+
+	frag := api.holder.fragment(idx.Name(), existenceFieldName, viewStandard, req.Shard)
+	if frag == nil {
+		// Nothing exists in this shard yet.
+		return nil
+	}
+	existenceRow, err := frag.row(tx, 0)
+	if err != nil {
+		return errors.Wrap(err, "reading existence")
+	}
+	existing := make(map[uint64]struct{})
+	for _, id := range existenceRow.Intersect(NewRow(ids...)).Columns() {
+		existing[id] = struct{}{}
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+	isNew := func(id uint64) bool {
+		_, ok := existing[id]
+		return !ok
+	}
+
+	// columns holds the shard-relative positions of the existing records.
+	columns := roaring.NewBitmap()
+	for id := range existing {
+		columns.DirectAdd(id % ShardWidth)
+	}
+
+	switch conflict {
+	case ImportConflictDoNothing:
+		ivrs := req.Ivr[:0]
+		for _, ivr := range req.Ivr {
+			if ivr.filterColumns(isNew); len(ivr.ColumnIDs) > 0 {
+				ivrs = append(ivrs, ivr)
+			}
+		}
+		req.Ivr = ivrs
+		irs := req.Ir[:0]
+		for _, ir := range req.Ir {
+			if ir.filterColumns(isNew); len(ir.ColumnIDs) > 0 {
+				irs = append(irs, ir)
+			}
+		}
+		req.Ir = irs
+
+	case ImportConflictUpdate:
+		// BSI and single-valued fields are overwritten by the import
+		// anyway; it's only the other rows of set fields which would
+		// linger.
+		for _, ir := range req.Ir {
+			field := idx.Field(ir.Field)
+			if field == nil {
+				return newNotFoundError(ErrFieldNotFound, ir.Field)
+			}
+			switch field.Type() {
+			case FieldTypeSet, FieldTypeTime:
+				if err := clearFieldRecords(tx, field, req.Shard, columns); err != nil {
+					return err
+				}
+			}
+		}
+
+	case ImportConflictReplace:
+		for _, field := range idx.Fields() {
+			if err := clearFieldRecords(tx, field, req.Shard, columns); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func addClearToImportOptions(opts []ImportOption) []ImportOption {
 	var opt ImportOptions
 	for _, o := range opts {
@@ -1511,6 +1785,26 @@ func (api *API) Import(ctx context.Context, qcx *Qcx, req *ImportRequest, opts .
 	return nil
 }
 
+// validateImport checks the values of req against the constraints of field.
+// If the field has keys, but the request's keys have already been translated,
+// the IDs are translated back, since a CHECK is on the keys.
+func (api *API) validateImport(ctx context.Context, field *Field, req *ImportRequest) error {
+	fc, err := field.Constraints()
+	if err != nil {
+		return errors.Wrap(err, "compiling field constraints")
+	}
+	if fc == nil || fc.check == nil || !field.Keys() || len(req.RowKeys) > 0 || len(req.RowIDs) == 0 {
+		return fc.validateImport(req)
+	}
+	keys, err := api.cluster.translateFieldListIDs(ctx, field, req.RowIDs)
+	if err != nil {
+		return errors.Wrap(err, "translating row ids to check constraints")
+	}
+	keyed := *req
+	keyed.RowIDs, keyed.RowKeys = nil, keys
+	return fc.validateImport(&keyed)
+}
+
 // ImportWithTx bulk imports data into a particular index,field,shard.
 func (api *API) ImportWithTx(ctx context.Context, qcx *Qcx, req *ImportRequest, options *ImportOptions) error {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.Import")
@@ -1524,6 +1818,9 @@ func (api *API) ImportWithTx(ctx context.Context, qcx *Qcx, req *ImportRequest,
 	if err != nil {
 		return errors.Wrap(err, "getting index and field")
 	}
+	if err := idx.checkFieldWritable(req.Field); err != nil {
+		return err
+	}
 
 	// This node only handles the shard(s) that it owns.
 	if api.isComputeNode {
@@ -1537,6 +1834,15 @@ func (api *API) ImportWithTx(ctx context.Context, qcx *Qcx, req *ImportRequest,
 		return errors.Wrap(err, "validating import value request")
 	}
 
+	// Values are checked against the field's constraints where the request
+	// comes in, before it's split up by shard.
+	if !options.validated {
+		if err := api.validateImport(ctx, field, req); err != nil {
+			return err
+		}
+		options.validated = true
+	}
+
 	span.LogKV(
 		"index", req.Index,
 		"field", req.Field)
@@ -1644,11 +1950,18 @@ func (api *API) ImportWithTx(ctx context.Context, qcx *Qcx, req *ImportRequest,
 // bits. As a result, users of this endpoint are responsible for
 // providing corrected existence views for fields with existence
 // tracking. Our batch API does that.
+//
+// Nor does it check the field's NOT NULL, DEFAULT and CHECK constraints,
+// since the values can't be recovered from the encoded data cheaply. The
+// batch API applies them to each record before encoding it.
 func (api *API) ImportRoaringShard(ctx context.Context, indexName string, shard uint64, req *ImportRoaringShardRequest) error {
 	index, err := api.Index(ctx, indexName)
 	if err != nil {
 		return errors.Wrap(err, "getting index")
 	}
+	if err := index.checkShardImportWritable(req); err != nil {
+		return err
+	}
 
 	// we really only need a Tx, but getting a Qcx so that there's only one path for getting a Tx
 	qcx := api.Txf().NewQcx()
@@ -1665,8 +1978,24 @@ func (api *API) ImportRoaringShard(ctx context.Context, indexName string, shard
 		err1 = errors.New("forwarding unimplemented on this endpoint")
 		return err1
 	}
+	if req.Checkpoint != nil {
+		if shard != IngestCheckpointShard {
+			err1 = NewBadRequestError(errors.Errorf("checkpoints can only be imported into shard %d", IngestCheckpointShard))
+			return err1
+		}
+		if err1 = validateIngestCheckpointKey(req.Checkpoint.Key); err1 != nil {
+			return err1
+		}
+	}
 
 	for _, viewUpdate := range req.Views {
+		if viewUpdate.Field == "" && viewUpdate.ClearRecords {
+			if err1 = clearRecordsFromShard(tx, index, shard, viewUpdate.Clear); err1 != nil {
+				return err1
+			}
+			continue
+		}
+
 		field := index.Field(viewUpdate.Field)
 		if field == nil {
 			err1 = errors.Errorf("no field named '%s' found.", viewUpdate.Field)
@@ -1697,7 +2026,7 @@ func (api *API) ImportRoaringShard(ctx context.Context, indexName string, shard
 			} else {
 				err1 = frag.ImportRoaringSingleValued(ctx, tx, viewUpdate.Clear, viewUpdate.Set)
 			}
-		case FieldTypeInt, FieldTypeTimestamp, FieldTypeDecimal:
+		case FieldTypeInt, FieldTypeTimestamp, FieldTypeDecimal, FieldTypeFloat:
 			err1 = frag.ImportRoaringBSI(ctx, tx, viewUpdate.Clear, viewUpdate.Set)
 		case FieldTypeMutex, FieldTypeBool:
 			err1 = frag.ImportRoaringSingleValued(ctx, tx, viewUpdate.Clear, viewUpdate.Set)
@@ -1727,6 +2056,12 @@ func (api *API) ImportRoaringShard(ctx context.Context, indexName string, shard
 		}
 	}
 
+	if req.Checkpoint != nil {
+		if err1 = writeIngestCheckpoint(tx, indexName, req.Checkpoint); err1 != nil {
+			return err1
+		}
+	}
+
 	if api.isComputeNode && !req.SuppressLog {
 		partition := disco.ShardToShardPartition(indexName, shard, disco.DefaultPartitionN)
 		msg := &computer.ImportRoaringShardMessage{
@@ -1744,6 +2079,13 @@ func (api *API) ImportRoaringShard(ctx context.Context, indexName string, shard
 				ClearRecords: view.ClearRecords,
 			}
 		}
+		if req.Checkpoint != nil {
+			msg.Checkpoint = &computer.IngestCheckpoint{
+				Key:       req.Checkpoint.Key,
+				Offsets:   req.Checkpoint.Offsets,
+				Positions: req.Checkpoint.Positions,
+			}
+		}
 
 		tkey := dax.TableKey(indexName)
 		qtid := tkey.QualifiedTableID()
@@ -1861,6 +2203,9 @@ func (api *API) ImportValueWithTx(ctx context.Context, qcx *Qcx, req *ImportValu
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("getting index '%v' and field '%v'; shard=%v", req.Index, req.Field, req.Shard))
 	}
+	if err := idx.checkFieldWritable(req.Field); err != nil {
+		return err
+	}
 
 	// This node only handles the shard(s) that it owns.
 	if api.isComputeNode {
@@ -1874,6 +2219,18 @@ func (api *API) ImportValueWithTx(ctx context.Context, qcx *Qcx, req *ImportValu
 		return errors.Wrap(err, "validating import value request")
 	}
 
+	// As with Import, values are checked where the request comes in.
+	if !options.validated {
+		fc, err := field.Constraints()
+		if err != nil {
+			return errors.Wrap(err, "compiling field constraints")
+		}
+		if err := fc.validateImportValue(req); err != nil {
+			return err
+		}
+		options.validated = true
+	}
+
 	span.LogKV(
 		"index", req.Index,
 		"field", req.Field)
@@ -2340,6 +2697,18 @@ func (api *API) CreateFieldKeys(ctx context.Context, index, field string, keys .
 	return api.cluster.createFieldKeys(ctx, f, keys...)
 }
 
+// MatchIndexKeys finds the IDs of all column keys in the index beginning
+// with prefix.
+func (api *API) MatchIndexKeys(ctx context.Context, index string, prefix string) ([]uint64, error) {
+	return api.cluster.matchIndexKeys(ctx, index, prefix)
+}
+
+// matchPrimaryIndexKeys is MatchIndexKeys restricted to the partitions this
+// node is the primary for. It serves requests from other nodes.
+func (api *API) matchPrimaryIndexKeys(index string, prefix string) ([]uint64, error) {
+	return api.cluster.matchPrimaryIndexKeys(index, prefix)
+}
+
 // MatchField finds the IDs of all field keys matching a filter.
 func (api *API) MatchField(ctx context.Context, index, field string, like string) ([]uint64, error) {
 	f := api.holder.Field(index, field)
@@ -2434,7 +2803,10 @@ func (api *API) PastQueries(ctx context.Context, remote bool) ([]PastQueryStatus
 		return nil, errors.Wrap(err, "validating api method")
 	}
 
-	clusterQueries := api.tracker.PastQueries()
+	clusterQueries, err := api.tracker.PastQueries()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting query history")
+	}
 
 	if !remote {
 		nodes := api.cluster.Nodes()
@@ -2472,6 +2844,25 @@ func (api *API) ReserveIDs(key IDAllocKey, session [32]byte, offset uint64, coun
 	return api.holder.ida.reserve(key, session, offset, count)
 }
 
+// HoldIndexes keeps queries coordinated by this node from running against
+// the given indexes, once those already running have finished, until
+// ReleaseIndexes is called with the same id or timeout has passed.
+func (api *API) HoldIndexes(ctx context.Context, id string, indexes []string, timeout time.Duration) error {
+	if err := api.validate(apiHoldIndexes); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+	return api.holder.executor.HoldIndexes(ctx, id, indexes, timeout)
+}
+
+// ReleaseIndexes releases the indexes held by HoldIndexes for id.
+func (api *API) ReleaseIndexes(ctx context.Context, id string) error {
+	if err := api.validate(apiHoldIndexes); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+	api.holder.executor.ReleaseIndexes(id)
+	return nil
+}
+
 func (api *API) CommitIDs(key IDAllocKey, session [32]byte, count uint64) error {
 	if err := api.validate(apiIDCommit); err != nil {
 		return errors.Wrap(err, "validating api method")
@@ -2613,6 +3004,9 @@ func (api *API) RestoreShard(ctx context.Context, indexName string, shard uint64
 	}
 
 	for _, flv := range flvs {
+		if flv.Field == ingestCheckpointFieldName {
+			continue
+		}
 		fld := idx.field(flv.Field)
 		view := fld.view(flv.View)
 		if view == nil {
@@ -3015,6 +3409,39 @@ processing:
 	return result, ctx.Err()
 }
 
+// clearRecordsFromShard clears the records in the roaring encoded row
+// from every view of every field of the index in the given shard.
+func clearRecordsFromShard(tx Tx, index *Index, shard uint64, data []byte) error {
+	columns := roaring.NewBitmap()
+	if err := columns.UnmarshalBinary(data); err != nil {
+		return errors.Wrap(err, "decoding records to clear")
+	}
+	if !columns.Any() {
+		return nil
+	}
+	for _, field := range index.Fields() {
+		if err := clearFieldRecords(tx, field, shard, columns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearFieldRecords clears the records at the given shard-relative columns
+// from every view of field.
+func clearFieldRecords(tx Tx, field *Field, shard uint64, columns *roaring.Bitmap) error {
+	for _, view := range field.views() {
+		frag := view.Fragment(shard)
+		if frag == nil {
+			continue
+		}
+		if _, err := frag.clearRecordsByBitmap(tx, columns); err != nil {
+			return errors.Wrapf(err, "clearing records from %s/%s", field.Name(), view.name)
+		}
+	}
+	return nil
+}
+
 // CompilePlan takes a sql string and returns a PlanOperator. Note that this is
 // different from the internal CompilePlan() method on the CompilePlanner
 // interface, which takes a parser statement and returns a PlanOperator. In
@@ -3025,6 +3452,76 @@ func (api *API) CompilePlan(ctx context.Context, q string) (planner_types.PlanOp
 	return api.server.CompileExecutionPlan(ctx, q)
 }
 
+// Cursors returns the sql cursors open on any node of the cluster. A cursor
+// stays on the node it was opened on, and is read from there.
+func (api *API) Cursors() CursorsAPI {
+	return &clusterCursors{
+		CursorsAPI: api.server.SystemLayer.Cursors(),
+		api:        api,
+	}
+}
+
+// SystemLayer returns the internal state of the server, whose cursors are
+// those open on any node of the cluster.
+func (api *API) SystemLayer() SystemLayerAPI {
+	return &clusterSystemLayer{
+		SystemLayerAPI: api.server.SystemLayer,
+		cursors:        api.Cursors(),
+	}
+}
+
+// QuerySQLPage executes sql and returns the schema of the result, along with
+// at most pageSize rows of it. If there are rows left over, they are kept in a
+// cursor, and the id of that cursor is returned. The next page is read by
+// calling QuerySQLPage with that cursor id in place of the sql.
+func (api *API) QuerySQLPage(ctx context.Context, sql string, cursorID string, pageSize int) (planner_types.Schema, planner_types.Rows, string, error) {
+	if pageSize < 1 {
+		return nil, nil, "", errors.Errorf("invalid page size: %d", pageSize)
+	}
+
+	cursors := api.Cursors()
+	if cursorID != "" {
+		userID, _ := fbcontext.UserID(ctx)
+		cursor, err := cursors.GetCursor(ctx, userID, cursorID)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		rows, done, err := cursor.Fetch(pageSize)
+		if err != nil || done {
+			_ = cursors.CloseCursor(ctx, userID, cursorID)
+			return cursor.Schema, rows, "", err
+		}
+		return cursor.Schema, rows, cursorID, nil
+	}
+
+	requestID, err := uuid.NewV4()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	ctx = fbcontext.WithRequestID(ctx, requestID.String())
+
+	op, err := api.CompilePlan(ctx, sql)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	cursor, err := NewCursor(ctx, newCursorID(requestID.String()), sql, op.Schema(), func(ctx context.Context) (planner_types.RowIterator, error) {
+		return op.Iterator(ctx, nil)
+	})
+	if err != nil {
+		return nil, nil, "", err
+	}
+	rows, done, err := cursor.Fetch(pageSize)
+	if err != nil || done {
+		cursor.Close()
+		return cursor.Schema, rows, "", err
+	}
+	if err := cursors.AddCursor(cursor); err != nil {
+		cursor.Close()
+		return nil, nil, "", err
+	}
+	return cursor.Schema, rows, cursor.ID, nil
+}
+
 func (api *API) RehydratePlanOperator(ctx context.Context, reader io.Reader) (planner_types.PlanOperator, error) {
 	return api.server.RehydratePlanOperator(ctx, reader)
 }
@@ -3222,6 +3719,10 @@ const (
 	apiMutexCheck
 	apiApplyChangeset
 	apiDeleteDataframe
+	apiRenameField
+	apiAlterField
+	apiHoldIndexes
+	apiBlockFieldWrites
 )
 
 var methodsCommon = map[apiMethod]struct{}{
@@ -3253,12 +3754,15 @@ var methodsNormal = map[apiMethod]struct{}{
 	apiCreateField:          {},
 	apiCreateIndex:          {},
 	apiDeleteField:          {},
+	apiRenameField:          {},
+	apiAlterField:           {},
 	apiDeleteAvailableShard: {},
 	apiDeleteIndex:          {},
 	apiDeleteView:           {},
 	apiExportCSV:            {},
 	apiFragmentBlockData:    {},
 	apiFragmentBlocks:       {},
+	apiFragmentData:         {},
 	apiField:                {},
 	apiFieldTranslateData:   {},
 	apiImport:               {},
@@ -3284,6 +3788,8 @@ var methodsNormal = map[apiMethod]struct{}{
 	apiMutexCheck:           {},
 	apiApplyChangeset:       {},
 	apiDeleteDataframe:      {},
+	apiHoldIndexes:          {},
+	apiBlockFieldWrites:     {},
 }
 
 func shardInShards(i dax.ShardNum, s dax.ShardNums) bool {
@@ -3315,6 +3821,14 @@ type SchemaAPI interface {
 	DeleteField(ctx context.Context, tname dax.TableName, fname dax.FieldName) error
 }
 
+// FieldAlterer is implemented by SchemaAPIs which can rename fields and
+// change their types. It's used by ALTER TABLE ... RENAME COLUMN and ALTER
+// COLUMN.
+type FieldAlterer interface {
+	RenameField(ctx context.Context, tname dax.TableName, fname, newName dax.FieldName) error
+	AlterField(ctx context.Context, tname dax.TableName, fld *dax.Field) error
+}
+
 // Ensure type implements interface.
 var _ SchemaAPI = (*NopSchemaAPI)(nil)
 
@@ -3377,6 +3891,14 @@ type SystemAPI interface {
 
 	NodeID() string
 	ClusterNodes() []ClusterNode
+
+	TableStorage(ctx context.Context) ([]TableStorageInfo, error)
+	FragmentStorage(ctx context.Context) ([]FragmentStorageInfo, error)
+	RBFFiles(ctx context.Context) ([]RBFFileInfo, error)
+	TranslateStores(ctx context.Context) ([]TranslateStoreInfo, error)
+
+	QueryHistory() QueryHistoryAPI
+	FieldAlterations() []FieldAlteration
 }
 
 // CreateFieldObj is used to encapsulate the information required for creating a
@@ -3454,6 +3976,10 @@ func (fsapi *FeatureBaseSystemAPI) NodeID() string {
 	return fsapi.cluster.Node.ID
 }
 
+func (fsapi *FeatureBaseSystemAPI) QueryHistory() QueryHistoryAPI {
+	return fsapi.tracker
+}
+
 func (fsapi *FeatureBaseSystemAPI) ClusterNodes() []ClusterNode {
 	result := make([]ClusterNode, 0)
 
@@ -3523,3 +4049,36 @@ func (napi *NopSystemAPI) ClusterNodes() []ClusterNode {
 	result := make([]ClusterNode, 0)
 	return result
 }
+
+func (napi *NopSystemAPI) TableStorage(ctx context.Context) ([]TableStorageInfo, error) {
+	return []TableStorageInfo{}, nil
+}
+
+func (napi *NopSystemAPI) FragmentStorage(ctx context.Context) ([]FragmentStorageInfo, error) {
+	return []FragmentStorageInfo{}, nil
+}
+
+func (napi *NopSystemAPI) RBFFiles(ctx context.Context) ([]RBFFileInfo, error) {
+	return []RBFFileInfo{}, nil
+}
+
+func (napi *NopSystemAPI) TranslateStores(ctx context.Context) ([]TranslateStoreInfo, error) {
+	return []TranslateStoreInfo{}, nil
+}
+
+func (napi *NopSystemAPI) QueryHistory() QueryHistoryAPI {
+	return nopQueryHistory{}
+}
+
+func (napi *NopSystemAPI) FieldAlterations() []FieldAlteration {
+	return []FieldAlteration{}
+}
+
+// nopQueryHistory is a no-op implementation of the QueryHistoryAPI.
+type nopQueryHistory struct{}
+
+func (nopQueryHistory) AddQuery(q QueryHistoryEntry) {}
+
+func (nopQueryHistory) ListQueries() ([]QueryHistoryEntry, error) {
+	return []QueryHistoryEntry{}, nil
+}
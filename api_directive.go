@@ -628,6 +628,8 @@ func (api *API) loadShard(ctx context.Context, tkey dax.TableKey, shard dax.Shar
 					OptImportOptionsIgnoreKeyCheck(msg.IgnoreKeyCheck),
 					OptImportOptionsPresorted(msg.Presorted),
 					OptImportOptionsSuppressLog(true),
+					// the values were checked when they were first imported
+					optImportOptionsValidated(true),
 				}
 				if err := api.Import(ctx, qcx, req, opts...); err != nil {
 					return errors.Wrapf(err, "import, table: %s, field: %s, shard: %d", msg.Table, msg.Field, msg.Shard)
@@ -655,6 +657,8 @@ func (api *API) loadShard(ctx context.Context, tkey dax.TableKey, shard dax.Shar
 					OptImportOptionsIgnoreKeyCheck(msg.IgnoreKeyCheck),
 					OptImportOptionsPresorted(msg.Presorted),
 					OptImportOptionsSuppressLog(true),
+					// the values were checked when they were first imported
+					optImportOptionsValidated(true),
 				}
 				if err := api.ImportValue(ctx, qcx, req, opts...); err != nil {
 					return errors.Wrapf(err, "import value, table: %s, field: %s, shard: %d", msg.Table, msg.Field, msg.Shard)
@@ -674,6 +678,13 @@ func (api *API) loadShard(ctx context.Context, tkey dax.TableKey, shard dax.Shar
 						ClearRecords: view.ClearRecords,
 					}
 				}
+				if msg.Checkpoint != nil {
+					req.Checkpoint = &IngestCheckpoint{
+						Key:       msg.Checkpoint.Key,
+						Offsets:   msg.Checkpoint.Offsets,
+						Positions: msg.Checkpoint.Positions,
+					}
+				}
 				if err := api.ImportRoaringShard(ctx, msg.Table, msg.Shard, req); err != nil {
 					return errors.Wrapf(err, "import roaring shard table: %s, shard: %d", msg.Table, msg.Shard)
 				}
@@ -944,8 +955,11 @@ func (api *API) createTableAndFields(tbl *dax.QualifiedTable, partitions dax.Par
 		Index:     string(tbl.Key()),
 		CreatedAt: 0,
 		Meta: IndexOptions{
-			Keys:           tbl.StringKeys(),
-			TrackExistence: true,
+			Keys:            tbl.StringKeys(),
+			TrackExistence:  true,
+			RetentionField:  string(tbl.RetentionField),
+			RetentionPeriod: tbl.RetentionPeriod,
+			PrimaryKey:      primaryKeyNames(tbl.PrimaryKey),
 		},
 	}
 
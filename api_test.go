@@ -330,6 +330,32 @@ func TestAPI_ImportValue(t *testing.T) {
 
 	})
 
+	t.Run("ValCheckIgnoreKeyCheck", func(t *testing.T) {
+		ctx := context.Background()
+		index := c.Idx("valcheck")
+		field := "f"
+		createIndexForTest(index, coord, t)
+		_, err := coord.API.CreateField(ctx, index, field, pilosa.OptFieldTypeInt(math.MinInt64, math.MaxInt64), pilosa.OptFieldCheck("f < 100"))
+		if err != nil {
+			t.Fatalf("creating field: %v", err)
+		}
+
+		// Skipping key translation must not skip the CHECK constraint.
+		req := &pilosa.ImportValueRequest{
+			Index:     index,
+			Field:     field,
+			ColumnIDs: []uint64{1, 2},
+			Values:    []int64{5, 200},
+		}
+		qcx := coord.API.Txf().NewQcx()
+		defer qcx.Abort()
+		err = coord.API.ImportValue(ctx, qcx, req, pilosa.OptImportOptionsIgnoreKeyCheck(true))
+		var cerr *pilosa.ConstraintError
+		if !errors.As(err, &cerr) {
+			t.Fatalf("expected constraint error, got %v", err)
+		}
+	})
+
 	t.Run("ValDecimalField", func(t *testing.T) {
 		t.Skip() // skipping due to change partitioning strategy
 		ctx := context.Background()
@@ -1586,3 +1612,44 @@ func writeTestFile(t *testing.T, filename, content string) string {
 	}
 	return fname
 }
+
+func TestAPI_MatchIndexKeys(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	// the keys are spread across partitions whose primaries are on every
+	// node, so most of them are matched remotely
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{Keys: true, TrackExistence: true}, "h")
+	var pairs []test.KeyID
+	for i := 0; i < 50; i++ {
+		pairs = append(pairs,
+			test.KeyID{Key: fmt.Sprintf("east|%d", i), ID: 1},
+			test.KeyID{Key: fmt.Sprintf("west|%d", i), ID: 1},
+		)
+	}
+	c.ImportIDKey(t, c.Idx(), "h", pairs)
+
+	var keys []string
+	for i := 0; i < 50; i++ {
+		keys = append(keys, fmt.Sprintf("east|%d", i))
+	}
+	trans, err := c.GetNode(1).API.FindIndexKeys(context.Background(), c.Idx(), keys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := make([]uint64, 0, len(trans))
+	for _, id := range trans {
+		expect = append(expect, id)
+	}
+	sort.Slice(expect, func(i, j int) bool { return expect[i] < expect[j] })
+
+	for n := 0; n < 3; n++ {
+		got, err := c.GetNode(n).API.MatchIndexKeys(context.Background(), c.Idx(), "east|")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expect, got) {
+			t.Errorf("node %d: expected %v but got %v", n, expect, got)
+		}
+	}
+}
@@ -5,6 +5,7 @@ package batch
 import (
 	"bytes"
 	"context"
+	"math"
 	"math/bits"
 	"sort"
 	"sync"
@@ -60,8 +61,8 @@ type RecordBatch interface {
 	// Pilosa. It then resets internal data structures for the next
 	// batch. If we are using split batch mode, it saves the fragment
 	// data to the batch, resets all other internal structures, and
-	// continues.
-	// Split batch mode DOES NOT CURRENTLY SUPPORT MUTEX OR INT FIELDS!
+	// continues. Values saved for a record replace any saved by an
+	// earlier call.
 	Import() error
 
 	// Len reports the number of records which have been added to the
@@ -69,8 +70,8 @@ type RecordBatch interface {
 	Len() int
 
 	// Flush is only applicable in split batch mode where it actually
-	// imports the stored data to Pilosa. Otherwise it simply returns
-	// nil.
+	// imports the stored data to Pilosa with shard-transactional
+	// imports. Otherwise it simply returns nil.
 	Flush() error
 }
 
@@ -91,7 +92,7 @@ type agedTranslation struct {
 // | string | set               | keys=true |
 // | uint64 | set               | any       |
 // | int64  | int               | any       |
-// | float64| decimal           | scale     |
+// | float64| decimal, float    | scale     |
 // | bool   | bool              | any       |
 // | nil    | any               |           |
 //
@@ -102,6 +103,10 @@ type Batch struct {
 	header    []*featurebase.FieldInfo
 	headerMap map[string]*featurebase.FieldInfo
 
+	// constraints holds the NOT NULL, DEFAULT and CHECK constraints of each
+	// field in header. It is nil if none of the fields has any.
+	constraints []*featurebase.FieldConstraints
+
 	// prevDuration records the time that each doImport() takes. This
 	// is used to set the timeout for transactions to a reasonable
 	// value based on the last import. It starts with a conservative
@@ -184,11 +189,121 @@ type Batch struct {
 	frags          fragments
 	clearFrags     fragments
 
+	// mutexRows holds, in split batch mode, the row set in frags for each
+	// record of each mutex field, so that a later value can replace it.
+	mutexRows map[string]map[uint64]uint64
+
 	useShardTransactionalEndpoint bool
+
+	// checkpoint is imported into featurebase.IngestCheckpointShard
+	// after the rest of the batch, by the next call to Import.
+	checkpoint *featurebase.IngestCheckpoint
+
+	// clearIDs and clearKeys hold the records to clear, by ID or by key,
+	// before the next import's values are set.
+	clearIDs  []uint64
+	clearKeys []string
+
+	// replaceFields holds the names of the set fields whose values replace,
+	// rather than add to, those of the records in the batch.
+	replaceFields map[string]struct{}
 }
 
 func (b *Batch) Len() int { return len(b.ids) }
 
+// SetCheckpoint sets an ingest checkpoint to be imported by the next call
+// to Import. It's imported with the data for
+// featurebase.IngestCheckpointShard once the data for every other shard
+// has been, so it's only ever stored once the whole batch has been. It's
+// not atomic with the other shards' data, though: if importing into
+// another shard fails, the shards already imported into keep their data,
+// and resuming from the previous checkpoint imports it again.
+// It requires the shard-transactional endpoint.
+func (b *Batch) SetCheckpoint(checkpoint *featurebase.IngestCheckpoint) error {
+	if !b.useShardTransactionalEndpoint {
+		return errors.New("checkpoints require the shard-transactional endpoint")
+	}
+	if b.splitBatchMode {
+		return errors.New("checkpoints aren't supported in split batch mode")
+	}
+	b.checkpoint = checkpoint
+	return nil
+}
+
+// ClearRecord clears every field of the record with the given ID (a
+// uint64, or a string or []byte key if the table has keys) by the next
+// call to Import. The record is cleared in the same transaction as its
+// shard's values are set, so if the record is also added to the batch,
+// its values replace what it held, and otherwise it's deleted. It requires
+// the shard-transactional endpoint.
+func (b *Batch) ClearRecord(id interface{}) error {
+	if !b.useShardTransactionalEndpoint {
+		return errors.New("clearing records requires the shard-transactional endpoint")
+	}
+	if b.splitBatchMode {
+		return errors.New("clearing records isn't supported in split batch mode")
+	}
+	switch id := id.(type) {
+	case uint64:
+		if b.tbl.StringKeys() {
+			return errors.Errorf("table has keys, can't clear record %d by ID", id)
+		}
+		b.clearIDs = append(b.clearIDs, id)
+	case string:
+		if !b.tbl.StringKeys() {
+			return errors.Errorf("table doesn't have keys, can't clear record '%s' by key", id)
+		}
+		b.clearKeys = append(b.clearKeys, id)
+	case []byte:
+		return b.ClearRecord(string(id))
+	default:
+		return errors.Errorf("unsupported record ID type %T", id)
+	}
+	return nil
+}
+
+// clearedRecords returns the records to clear by shard, as shard-relative
+// columns. Keys which have no ID yet have no record to clear, so they're
+// only looked up if the importer can do that without creating them.
+func (b *Batch) clearedRecords() (map[uint64]*roaring.Bitmap, error) {
+	ids := b.clearIDs
+	var untranslated []string
+	for _, key := range b.clearKeys {
+		if id, ok := b.getColTranslation(key); ok {
+			ids = append(ids, id)
+		} else {
+			untranslated = append(untranslated, key)
+		}
+	}
+	if len(untranslated) > 0 {
+		var trans map[string]uint64
+		var err error
+		if finder, ok := b.importer.(featurebase.KeyFinder); ok {
+			trans, err = finder.FindTableKeys(context.Background(), b.tbl.ID, untranslated...)
+		} else {
+			trans, err = b.createIndexKeys(untranslated...)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "translating keys of records to clear")
+		}
+		for _, id := range trans {
+			ids = append(ids, id)
+		}
+	}
+
+	shards := make(map[uint64]*roaring.Bitmap)
+	for _, id := range ids {
+		shard := id / featurebase.ShardWidth
+		bm, ok := shards[shard]
+		if !ok {
+			bm = roaring.NewBitmap()
+			shards[shard] = bm
+		}
+		bm.DirectAdd(id % featurebase.ShardWidth)
+	}
+	return shards, nil
+}
+
 // BatchOption is a functional option for Batch objects.
 type BatchOption func(b *Batch) error
 
@@ -236,6 +351,23 @@ func OptUseShardTransactionalEndpoint(use bool) BatchOption {
 	}
 }
 
+// OptReplaceFields tells the batch that the values of the named set fields
+// replace those the batch's records held, rather than adding to them. Each
+// record's old values are cleared in the same transaction as its shard's
+// new values are set, including for records which have no value for the
+// field. It requires the shard-transactional endpoint.
+func OptReplaceFields(fields ...string) BatchOption {
+	return func(b *Batch) error {
+		if b.replaceFields == nil {
+			b.replaceFields = make(map[string]struct{})
+		}
+		for _, name := range fields {
+			b.replaceFields[name] = struct{}{}
+		}
+		return nil
+	}
+}
+
 func OptImporter(i featurebase.Importer) BatchOption {
 	return func(b *Batch) error {
 		b.importer = i
@@ -262,10 +394,21 @@ func NewBatch(importer featurebase.Importer, size int, tbl *dax.Table, fields []
 	tt := make(map[int]map[string][]int, len(fields))
 	ttSets := make(map[string]map[string][]int)
 	hasTime := false
+	var constraints []*featurebase.FieldConstraints
 	for i, field := range fields {
 		headerMap[field.Name] = field
 		opts := field.Options
 
+		fc, err := featurebase.NewFieldConstraints(field.Name, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing field constraints")
+		} else if fc != nil {
+			if constraints == nil {
+				constraints = make([]*featurebase.FieldConstraints, len(fields))
+			}
+			constraints[i] = fc
+		}
+
 		// The client package has a FieldTypeDefault, but featurebase does not.
 		// When this code was moved from the client package to the batch
 		// package, FieldTypeDefault was no longer available. It probably isn't
@@ -280,10 +423,11 @@ func NewBatch(importer featurebase.Importer, size int, tbl *dax.Table, fields []
 				ttSets[field.Name] = make(map[string][]int)
 			}
 			hasTime = typ == featurebase.FieldTypeTime || hasTime
-		case featurebase.FieldTypeInt, featurebase.FieldTypeDecimal, featurebase.FieldTypeTimestamp:
+		case featurebase.FieldTypeInt, featurebase.FieldTypeDecimal, featurebase.FieldTypeTimestamp, featurebase.FieldTypeFloat:
 			// tt line only needed if int field is string foreign key
 			tt[i] = make(map[string][]int)
 			values[field.Name] = make([]int64, 0, size)
+			hasTime = opts.TimeQuantum != "" || hasTime
 		case featurebase.FieldTypeMutex:
 			// similar to set/time fields, but no need to support sets
 			// of values (hence no ttSets)
@@ -302,6 +446,7 @@ func NewBatch(importer featurebase.Importer, size int, tbl *dax.Table, fields []
 		importer:              importer,
 		header:                fields,
 		headerMap:             headerMap,
+		constraints:           constraints,
 		prevDuration:          time.Minute * 11,
 		tbl:                   tbl,
 		ids:                   make([]uint64, 0, size),
@@ -336,9 +481,110 @@ func NewBatch(importer featurebase.Importer, size int, tbl *dax.Table, fields []
 		}
 	}
 
+	for name := range b.replaceFields {
+		field, ok := b.headerMap[name]
+		if !ok {
+			return nil, errors.Errorf("field '%s' to replace isn't in the batch", name)
+		}
+		if field.Options.Type != featurebase.FieldTypeSet {
+			return nil, errors.Errorf("field '%s' to replace isn't a set field", name)
+		}
+	}
+	if len(b.replaceFields) > 0 && (!b.useShardTransactionalEndpoint || b.splitBatchMode) {
+		return nil, errors.New("replacing fields requires the shard-transactional endpoint")
+	}
+
+	// the time views of int and decimal fields are only written through the
+	// shard-transactional endpoint, so without it they would silently be
+	// left out
+	if !b.useShardTransactionalEndpoint {
+		for _, field := range fields {
+			switch field.Options.Type {
+			case featurebase.FieldTypeInt, featurebase.FieldTypeDecimal:
+				if field.Options.TimeQuantum != "" {
+					return nil, errors.Errorf("field '%s' has a time quantum, which requires the shard-transactional endpoint", field.Name)
+				}
+			}
+		}
+	}
+
 	return b, nil
 }
 
+// applyConstraints replaces the NULL values in a record with the default of
+// their field, if it has one, and then checks the NOT NULL and CHECK
+// constraints of each value. It has to run before any of the record is added
+// to the batch, so that a record which is rejected leaves no trace.
+func (b *Batch) applyConstraints(values []interface{}) error {
+	for i, fc := range b.constraints {
+		if fc == nil {
+			continue
+		}
+		field := b.header[i]
+		val, err := constraintValue(field, fc, values[i])
+		if err != nil {
+			return err
+		}
+		if val == nil && fc.HasDefault() {
+			if val, err = fc.Default(); err != nil {
+				return err
+			}
+			if values[i], err = batchValue(field, val); err != nil {
+				return err
+			}
+		}
+		if err := fc.Validate(val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// constraintValue converts a value as passed to Add into the form in which
+// featurebase.FieldConstraints handles it. NULL values are returned as nil.
+func constraintValue(field *featurebase.FieldInfo, fc *featurebase.FieldConstraints, val interface{}) (interface{}, error) {
+	switch v := val.(type) {
+	case string:
+		// empty strings are treated as nulls by Add
+		if v == "" {
+			return nil, nil
+		}
+	case uint64:
+		return int64(v), nil
+	case int64:
+		return fc.FromInt64(v)
+	case []string:
+		if v == nil {
+			return nil, nil
+		}
+	case []uint64:
+		if v == nil {
+			return nil, nil
+		}
+	case pql.Decimal:
+		if field.Options.Type == featurebase.FieldTypeFloat {
+			return v.Float64(), nil
+		}
+	}
+	return val, nil
+}
+
+// batchValue is the inverse of constraintValue.
+func batchValue(field *featurebase.FieldInfo, val interface{}) (interface{}, error) {
+	opts := field.Options
+	switch v := val.(type) {
+	case time.Time:
+		if opts.Type == featurebase.FieldTypeTimestamp {
+			return featurebase.TimestampToVal(opts.TimeUnit, v) - opts.Base, nil
+		}
+	case int64:
+		if opts.Type == featurebase.FieldTypeMutex {
+			return uint64(v), nil
+		}
+	}
+	return val, nil
+}
+
 // Row represents a single record which can be added to a Batch.
 type Row struct {
 	ID interface{}
@@ -473,6 +719,11 @@ func (b *Batch) Add(rec Row) error {
 	if len(rec.Values) != len(b.header) {
 		return errors.Errorf("record needs to match up with batch fields, got %d fields and %d record", len(b.header), len(rec.Values))
 	}
+	if b.constraints != nil {
+		if err := b.applyConstraints(rec.Values); err != nil {
+			return errors.Wrapf(err, "record %v", rec.ID)
+		}
+	}
 
 	handleStringID := func(rid string) error {
 		if rid == "" {
@@ -630,7 +881,7 @@ func (b *Batch) Add(rec Row) error {
 			b.rowIDSets[field.Name] = append(rowIDSets, val)
 		case nil:
 			switch field.Options.Type {
-			case featurebase.FieldTypeInt, featurebase.FieldTypeDecimal, featurebase.FieldTypeTimestamp:
+			case featurebase.FieldTypeInt, featurebase.FieldTypeDecimal, featurebase.FieldTypeTimestamp, featurebase.FieldTypeFloat:
 				b.values[field.Name] = append(b.values[field.Name], 0)
 				nullIndices, ok := b.nullIndices[field.Name]
 				if !ok {
@@ -663,7 +914,24 @@ func (b *Batch) Add(rec Row) error {
 			b.boolValues[field.Name][curPos] = val
 
 		case pql.Decimal:
-			b.values[field.Name] = append(b.values[field.Name], val.ToInt64(field.Options.Scale))
+			if field.Options.Type == featurebase.FieldTypeFloat {
+				b.values[field.Name] = append(b.values[field.Name], featurebase.FloatToVal(val.Float64()))
+			} else {
+				b.values[field.Name] = append(b.values[field.Name], val.ToInt64(field.Options.Scale))
+			}
+
+		case float64:
+			switch field.Options.Type {
+			case featurebase.FieldTypeFloat:
+				if math.IsNaN(val) {
+					return errors.Wrapf(featurebase.ErrFloatNaN, "field %s", field.Name)
+				}
+				b.values[field.Name] = append(b.values[field.Name], featurebase.FloatToVal(val))
+			case featurebase.FieldTypeDecimal:
+				b.values[field.Name] = append(b.values[field.Name], int64(val*math.Pow10(int(field.Options.Scale))))
+			default:
+				return errors.Errorf("float64 value %v is not supported for field %s of type %s", val, field.Name, field.Options.Type)
+			}
 
 		default:
 			return errors.Errorf("Val %v Type %[1]T is not currently supported. Use string, uint64 (row id), or int64 (integer value)", val)
@@ -748,8 +1016,8 @@ var ErrBatchNowStale = errors.New("batch is stale and needs to be imported (howe
 // Pilosa. It then resets internal data structures for the next
 // batch. If we are using split batch mode, it saves the fragment
 // data to the batch, resets all other internal structures, and
-// continues.  split batch mode DOES NOT CURRENTLY SUPPORT MUTEX
-// OR INT FIELDS!
+// continues. Values saved for a record replace any saved by an
+// earlier call.
 func (b *Batch) Import() error {
 	ctx := context.Background()
 	start := time.Now()
@@ -784,7 +1052,7 @@ func (b *Batch) Import() error {
 	if err != nil {
 		return errors.Wrap(err, "making fragments (flush)")
 	}
-	if b.useShardTransactionalEndpoint {
+	if b.useShardTransactionalEndpoint || b.splitBatchMode {
 		frags, clearFrags, err = b.makeSingleValFragments(frags, clearFrags)
 		if err != nil {
 			return errors.Wrap(err, "making single val fragments")
@@ -821,39 +1089,29 @@ func (b *Batch) Import() error {
 }
 
 // Flush is only applicable in split batch mode where it actually
-// imports the stored data to Pilosa. Otherwise it simply returns
-// nil.
+// imports the stored data to Pilosa with shard-transactional imports,
+// which include the int, decimal, timestamp, mutex and bool data.
+// Otherwise it simply returns nil.
 func (b *Batch) Flush() error {
-	ctx := context.Background()
-
 	if !b.splitBatchMode {
 		return nil
 	}
 	start := time.Now()
-
-	trns, err := b.importer.StartTransaction(ctx, "", b.prevDuration*10, false, time.Hour)
-	if err != nil {
-		return errors.Wrap(err, "starting transaction")
-	}
 	defer func() {
-		trnsl, err := b.importer.FinishTransaction(ctx, trns.ID)
-		if err != nil {
-			b.log.Errorf("error finishing transaction: %v. trns: %+v", err, trnsl)
-		}
 		featurebase.SummaryBatchFlushDurationSeconds.Observe(time.Since(start).Seconds())
 	}()
 
-	importStart := time.Now()
-	err = b.doImport(b.frags, b.clearFrags)
+	err := b.doImportShardTransactional(b.frags, b.clearFrags)
 	if err != nil {
-		return errors.Wrap(err, "doing import (ImportFragments)")
+		return errors.Wrap(err, "doing shard transactional import (flush)")
 	}
 
-	b.log.Debugf("superbatch import took %v", time.Since(importStart))
+	b.log.Debugf("superbatch import took %v", time.Since(start))
 
 	b.reset()
 	b.frags = make(fragments)
 	b.clearFrags = make(fragments)
+	b.mutexRows = nil
 	return nil
 }
 
@@ -1160,6 +1418,48 @@ func (b *Batch) doImportShardTransactional(frags, clearFrags fragments) error {
 		return request
 	}
 
+	// Records are cleared before anything else in their shards is set.
+	cleared, err := b.clearedRecords()
+	if err != nil {
+		return err
+	}
+	for shard, bitmap := range cleared {
+		buf := &bytes.Buffer{}
+		if _, err := bitmap.WriteTo(buf); err != nil {
+			return errors.Wrap(err, "serializing cleared records")
+		}
+		request := getOrCreate(requests, shard)
+		request.Views = append(request.Views, featurebase.RoaringUpdate{ClearRecords: true, Clear: buf.Bytes()})
+	}
+
+	// The standard view of each field to replace clears the batch's records
+	// as the same update sets their values.
+	replaced := make(map[fragmentKey]*featurebase.RoaringUpdate)
+	if len(b.replaceFields) > 0 {
+		records := make(map[uint64]*roaring.Bitmap)
+		for _, col := range b.ids {
+			bm, ok := records[col/featurebase.ShardWidth]
+			if !ok {
+				bm = roaring.NewBitmap()
+				records[col/featurebase.ShardWidth] = bm
+			}
+			bm.DirectAdd(col % featurebase.ShardWidth)
+		}
+		for shard, bm := range records {
+			buf := &bytes.Buffer{}
+			if _, err := bm.WriteTo(buf); err != nil {
+				return errors.Wrap(err, "serializing replaced records")
+			}
+			for name := range b.replaceFields {
+				replaced[fragmentKey{shard: shard, field: name}] = &featurebase.RoaringUpdate{
+					Field:        name,
+					Clear:        buf.Bytes(),
+					ClearRecords: true,
+				}
+			}
+		}
+	}
+
 	for fragKey, viewMap := range frags {
 		request := getOrCreate(requests, fragKey.shard)
 
@@ -1169,6 +1469,10 @@ func (b *Batch) doImportShardTransactional(frags, clearFrags fragments) error {
 			if err != nil {
 				return errors.Wrap(err, "serializing bitmap")
 			}
+			if update, ok := replaced[fragKey]; ok && view == "" {
+				update.Set = buf.Bytes()
+				continue
+			}
 			request.Views = append(request.Views, featurebase.RoaringUpdate{Field: fragKey.field, View: view, Set: buf.Bytes()})
 
 			// handle clear bitmap now if it exists so we don't have to go searching later
@@ -1187,10 +1491,19 @@ func (b *Batch) doImportShardTransactional(frags, clearFrags fragments) error {
 		}
 	}
 
+	for key, update := range replaced {
+		request := getOrCreate(requests, key.shard)
+		request.Views = append(request.Views, *update)
+	}
+
 	for fragKey, viewMap := range clearFrags {
 		request := getOrCreate(requests, fragKey.shard)
 
 		for view, bitmap := range viewMap {
+			// the records are cleared from replaced fields altogether
+			if _, ok := replaced[fragKey]; ok && view == "" {
+				continue
+			}
 			buf := &bytes.Buffer{}
 			_, err := bitmap.WriteTo(buf)
 			if err != nil {
@@ -1200,6 +1513,15 @@ func (b *Batch) doImportShardTransactional(frags, clearFrags fragments) error {
 		}
 	}
 
+	// The checkpoint goes with the last request, so that it's only stored
+	// once everything before it has been.
+	var last *featurebase.ImportRoaringShardRequest
+	if b.checkpoint != nil {
+		last = getOrCreate(requests, featurebase.IngestCheckpointShard)
+		last.Checkpoint = b.checkpoint
+		delete(requests, featurebase.IngestCheckpointShard)
+	}
+
 	featurebase.SummaryBatchShardImportBuildRequestsSeconds.Observe(time.Since(start).Seconds())
 	start = time.Now()
 	eg := egpool.Group{PoolSize: 20}
@@ -1210,7 +1532,10 @@ func (b *Batch) doImportShardTransactional(frags, clearFrags fragments) error {
 			return b.importer.ImportRoaringShard(ctx, b.tbl.ID, shard, request)
 		})
 	}
-	err := eg.Wait()
+	err = eg.Wait()
+	if err == nil && last != nil {
+		err = b.importer.ImportRoaringShard(ctx, b.tbl.ID, featurebase.IngestCheckpointShard, last)
+	}
 	dur := time.Since(start)
 	featurebase.SummaryBatchImportDurationSeconds.Observe(dur.Seconds())
 	b.log.Printf("import shard took: %v\n", dur)
@@ -1491,6 +1816,15 @@ func (b *Batch) makeSingleValFragments(frags, clearFrags fragments) (fragments,
 	// -------------------------
 	for fieldName, bvalues := range b.values {
 		ids = ids[:len(b.ids)]
+		field := b.headerMap[fieldName]
+
+		// int and decimal fields with a time quantum also need each
+		// record's time, kept in step with its id and value.
+		var times []QuantizedTime
+		if field.Options.TimeQuantum != "" && b.times != nil {
+			times = make([]QuantizedTime, len(b.times))
+			copy(times, b.times)
+		}
 
 		// trim out null values from ids and values.
 		nullIndices := b.nullIndices[fieldName]
@@ -1498,23 +1832,31 @@ func (b *Batch) makeSingleValFragments(frags, clearFrags fragments) (fragments,
 		i, n := uint64(0), 0
 		for _, nullIndex := range nullIndices {
 			copy(ids[n:], b.ids[i:nullIndex])
+			if times != nil {
+				copy(times[n:], times[i:nullIndex])
+			}
 			n += copy(bvalues[n:], bvalues[i:nullIndex])
 			i = nullIndex + 1
 		}
 
 		copy(ids[n:], b.ids[i:])
+		if times != nil {
+			copy(times[n:], times[i:])
+		}
 		n += copy(bvalues[n:], bvalues[i:])
 		ids, bvalues = ids[:n], bvalues[:n]
+		if times != nil {
+			times = times[:n]
+		}
 
 		if len(ids) == 0 {
 			continue
 		}
 
-		sc := &valsByIDsSortable{ids: ids, vals: bvalues, width: shardWidth}
+		sc := &valsByIDsSortable{ids: ids, vals: bvalues, times: times, width: shardWidth}
 		if !sort.IsSorted(sc) {
 			sort.Stable(sc)
 		}
-		field := b.headerMap[fieldName]
 		base := field.Options.Base
 		if field.Options.Type == featurebase.FieldTypeTimestamp {
 			base = 0
@@ -1533,24 +1875,35 @@ func (b *Batch) makeSingleValFragments(frags, clearFrags fragments) (fragments,
 				shard = id / shardWidth
 				bitmap = frags.GetOrCreate(shard, fieldName, "bsig_"+fieldName)
 			}
-			fragmentColumn := id % shardWidth
-			bitmap.Add(fragmentColumn) // existence bit
-			svalue := bvalues[i] - base
-			negative := svalue < 0
-			var value uint64
-			if negative {
-				bitmap.Add(shardWidth + fragmentColumn) // set sign bit
-				value = uint64(svalue * -1)
-			} else {
-				value = uint64(svalue)
+			addBSIValue(bitmap, id%shardWidth, bvalues[i]-base, shardWidth)
+		}
+
+		if times == nil {
+			continue
+		}
+
+		// Each value also goes in the time views for its record's time.
+		// Walking backwards, the first value seen for a record in a
+		// view is the last one set, which is the one we want.
+		seen := make(map[string]struct{})
+		for i := len(ids) - 1; i >= 0; i-- {
+			id := ids[i]
+			if i+1 == len(ids) || ids[i+1] != id {
+				for view := range seen {
+					delete(seen, view)
+				}
 			}
-			lz := bits.LeadingZeros64(value)
-			row := uint64(2)
-			for mask := uint64(0x1); mask <= 1<<(64-lz) && mask != 0; mask = mask << 1 {
-				if value&mask > 0 {
-					bitmap.Add(row*shardWidth + fragmentColumn)
+			views, err := times[i].views(field.Options.TimeQuantum)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "calculating views")
+			}
+			for _, view := range views {
+				if _, ok := seen[view]; ok {
+					continue
 				}
-				row++
+				seen[view] = struct{}{}
+				tbm := frags.GetOrCreate(id/shardWidth, fieldName, "bsig_"+fieldName+"_"+view)
+				addBSIValue(tbm, id%shardWidth, bvalues[i]-base, shardWidth)
 			}
 		}
 	}
@@ -1565,6 +1918,19 @@ func (b *Batch) makeSingleValFragments(frags, clearFrags fragments) (fragments,
 		}
 		ids = ids[:0]
 
+		// In split batch mode, frags may already have a row set for a
+		// record from an earlier batch, which a new value replaces.
+		var prevRows map[uint64]uint64
+		if b.splitBatchMode {
+			if b.mutexRows == nil {
+				b.mutexRows = make(map[string]map[uint64]uint64)
+			}
+			if prevRows = b.mutexRows[field.Name]; prevRows == nil {
+				prevRows = make(map[uint64]uint64)
+				b.mutexRows[field.Name] = prevRows
+			}
+		}
+
 		// get slice of column ids for non-nil rowIDs and cut nil row
 		// IDs out of rowIDs.
 		idsIndex := 0
@@ -1615,14 +1981,22 @@ func (b *Batch) makeSingleValFragments(frags, clearFrags fragments) (fragments,
 			}
 			fragmentColumn := id % shardWidth
 			clearBM.Add(fragmentColumn) // Will use this to clear columns.
+			if prevRow, ok := prevRows[id]; ok {
+				_, _ = bitmap.Remove(prevRow*shardWidth + fragmentColumn)
+				delete(prevRows, id)
+			}
 			if row != clearSentinel {
 				// clearSentinel is used for deletion
 				// so this value should only be added if its not clearSentinel
 				bitmap.Add(row*shardWidth + fragmentColumn)
+				if prevRows != nil {
+					prevRows[id] = row
+				}
 				if field.Options.ActuallyTrackingExistence() {
 					existBM.Add(fragmentColumn)
 				}
 			} else if field.Options.ActuallyTrackingExistence() {
+				_, _ = existBM.Remove(fragmentColumn)
 				existClearBM.Add(fragmentColumn)
 			}
 		}
@@ -1652,10 +2026,18 @@ func (b *Batch) makeSingleValFragments(frags, clearFrags fragments) (fragments,
 			fragmentColumn := recID % shardWidth
 
 			clearBM.Add(fragmentColumn)
+			// In split batch mode, a value from an earlier batch may need
+			// to be unset.
+			if bitmap := frags[fragmentKey{shard, field.Name}]["standard"]; bitmap != nil {
+				_, _ = bitmap.Remove(falseRowOffset+fragmentColumn, trueRowOffset+fragmentColumn)
+			}
 			if field.Options.ActuallyTrackingExistence() {
 				existClearBM := clearFrags.GetOrCreate(shard, field.Name, existenceViewName)
 
 				existClearBM.Add(fragmentColumn)
+				if exist := frags[fragmentKey{shard, field.Name}][existenceViewName]; exist != nil {
+					_, _ = exist.Remove(fragmentColumn)
+				}
 			}
 		}
 	}
@@ -1684,6 +2066,7 @@ func (b *Batch) makeSingleValFragments(frags, clearFrags fragments) (fragments,
 				exist.Add(fragmentColumn)
 			}
 
+			_, _ = bitmap.Remove(falseRowOffset+fragmentColumn, trueRowOffset+fragmentColumn)
 			if boolVal {
 				bitmap.Add(trueRowOffset + fragmentColumn)
 			} else {
@@ -1698,6 +2081,8 @@ func (b *Batch) makeSingleValFragments(frags, clearFrags fragments) (fragments,
 type valsByIDsSortable struct {
 	ids  []uint64
 	vals []int64
+	// times, if set, are the record times for a field with a time quantum
+	times []QuantizedTime
 	// shard width so we can compare by shard instead of ID
 	width uint64
 }
@@ -1708,9 +2093,42 @@ func (v *valsByIDsSortable) Less(i, j int) bool { return v.ids[i] < v.ids[j] }
 func (v *valsByIDsSortable) Swap(i, j int) {
 	v.ids[i], v.ids[j] = v.ids[j], v.ids[i]
 	v.vals[i], v.vals[j] = v.vals[j], v.vals[i]
+	if v.times != nil {
+		v.times[i], v.times[j] = v.times[j], v.times[i]
+	}
+}
+
+// addBSIValue adds the bits for svalue, already adjusted by the field's
+// base, in the given column of a BSI fragment bitmap, replacing any value
+// already there (which split batch mode can have from an earlier batch).
+func addBSIValue(bitmap *roaring.Bitmap, fragmentColumn uint64, svalue int64, shardWidth uint64) {
+	if bitmap.Contains(fragmentColumn) {
+		for row := uint64(0); row <= bitmap.Max()/shardWidth; row++ {
+			_, _ = bitmap.Remove(row*shardWidth + fragmentColumn)
+		}
+	}
+	bitmap.Add(fragmentColumn) // existence bit
+	var value uint64
+	if svalue < 0 {
+		bitmap.Add(shardWidth + fragmentColumn) // set sign bit
+		value = uint64(svalue * -1)
+	} else {
+		value = uint64(svalue)
+	}
+	lz := bits.LeadingZeros64(value)
+	row := uint64(2)
+	for mask := uint64(0x1); mask <= 1<<(64-lz) && mask != 0; mask = mask << 1 {
+		if value&mask > 0 {
+			bitmap.Add(row*shardWidth + fragmentColumn)
+		}
+		row++
+	}
 }
 
-// importValueData imports data for int fields.
+// importValueData imports data for int fields. Only the BSI view is
+// written; NewBatch ensures there are no int or decimal fields with a time
+// quantum, whose time views are only written through the
+// shard-transactional endpoint.
 func (b *Batch) importValueData() error {
 	ctx := context.Background()
 
@@ -1884,6 +2302,9 @@ func (b *Batch) importMutexData() error {
 // reset is called at the end of importing to ready the batch for the
 // next round. Where possible it does not re-allocate memory.
 func (b *Batch) reset() {
+	b.checkpoint = nil
+	b.clearIDs = b.clearIDs[:0]
+	b.clearKeys = b.clearKeys[:0]
 	b.ids = b.ids[:0]
 	b.times = b.times[:0]
 	for i, rowIDs := range b.rowIDs {
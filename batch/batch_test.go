@@ -9,12 +9,16 @@ import (
 	"reflect"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	featurebase "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/client"
+	"github.com/featurebasedb/featurebase/v3/dax"
 	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/roaring"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/pkg/errors"
@@ -2411,3 +2415,291 @@ func TestConvert(t *testing.T) {
 		}
 	})
 }
+
+func TestNewBatchIntTimeQuantum(t *testing.T) {
+	idx := &featurebase.IndexInfo{
+		Name: "test-int-time-quantum",
+		Fields: []*featurebase.FieldInfo{
+			{
+				Name: "anint",
+				Options: featurebase.FieldOptions{
+					Type:        featurebase.FieldTypeInt,
+					Min:         pql.NewDecimal(0, 0),
+					Max:         pql.NewDecimal(100, 0),
+					TimeQuantum: "YMD",
+				},
+			},
+		},
+	}
+	tbl := featurebase.IndexInfoToTable(idx)
+
+	// the time views would be left out without the shard-transactional endpoint
+	if _, err := NewBatch(nil, 5, tbl, idx.Fields); err == nil || !strings.Contains(err.Error(), "shard-transactional") {
+		t.Fatalf("expected an error requiring the shard-transactional endpoint, got %v", err)
+	}
+	if _, err := NewBatch(nil, 5, tbl, idx.Fields, OptUseShardTransactionalEndpoint(true)); err != nil {
+		t.Fatalf("getting batch: %v", err)
+	}
+}
+
+// shardImporter records the shard-transactional imports sent to it, and
+// assigns keys the IDs of column 1 of shards 0, 1, 2, and so on, in the
+// order they're created. Imports into the shards in fail fail.
+type shardImporter struct {
+	featurebase.Importer
+	mu      sync.Mutex
+	keys    map[string]uint64
+	imports map[uint64]*featurebase.ImportRoaringShardRequest
+	order   []uint64
+	fail    map[uint64]bool
+}
+
+func newShardImporter() *shardImporter {
+	return &shardImporter{
+		keys:    make(map[string]uint64),
+		imports: make(map[uint64]*featurebase.ImportRoaringShardRequest),
+	}
+}
+
+func (i *shardImporter) CreateTableKeys(ctx context.Context, tid dax.TableID, keys ...string) (map[string]uint64, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	trans := make(map[string]uint64, len(keys))
+	for _, key := range keys {
+		id, ok := i.keys[key]
+		if !ok {
+			id = uint64(len(i.keys))*featurebase.ShardWidth + 1
+			i.keys[key] = id
+		}
+		trans[key] = id
+	}
+	return trans, nil
+}
+
+func (i *shardImporter) ImportRoaringShard(ctx context.Context, tid dax.TableID, shard uint64, request *featurebase.ImportRoaringShardRequest) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.fail[shard] {
+		return errors.Errorf("importing into shard %d failed", shard)
+	}
+	i.imports[shard] = request
+	i.order = append(i.order, shard)
+	return nil
+}
+
+// clearedRecords returns the records cleared by the first view of the
+// import into shard, or nil if that view doesn't clear records.
+func (i *shardImporter) clearedRecords(t *testing.T, shard uint64) []uint64 {
+	t.Helper()
+	request, ok := i.imports[shard]
+	if !ok {
+		t.Fatalf("nothing imported into shard %d", shard)
+	}
+	if len(request.Views) == 0 || !request.Views[0].ClearRecords {
+		return nil
+	}
+	bm := roaring.NewBitmap()
+	if err := bm.UnmarshalBinary(request.Views[0].Clear); err != nil {
+		t.Fatal(err)
+	}
+	return bm.Slice()
+}
+
+func TestBatchClearRecord(t *testing.T) {
+	for _, keys := range []bool{false, true} {
+		t.Run(fmt.Sprintf("keys=%v", keys), func(t *testing.T) {
+			idx := &featurebase.IndexInfo{
+				Name:    "test-clear-record",
+				Options: featurebase.IndexOptions{Keys: keys},
+				Fields: []*featurebase.FieldInfo{
+					{Name: "f", Options: featurebase.FieldOptions{Type: featurebase.FieldTypeSet}},
+				},
+			}
+			tbl := featurebase.IndexInfoToTable(idx)
+
+			if b, err := NewBatch(nil, 5, tbl, idx.Fields); err != nil {
+				t.Fatalf("getting batch: %v", err)
+			} else if err := b.ClearRecord(uint64(1)); err == nil || !strings.Contains(err.Error(), "shard-transactional") {
+				t.Fatalf("expected an error requiring the shard-transactional endpoint, got %v", err)
+			}
+
+			importer := newShardImporter()
+			b, err := NewBatch(importer, 5, tbl, idx.Fields, OptUseShardTransactionalEndpoint(true))
+			if err != nil {
+				t.Fatalf("getting batch: %v", err)
+			}
+			// the replaced record is in shard 0, and the deleted one in shard 1
+			var replaced, deleted interface{} = uint64(1), uint64(featurebase.ShardWidth + 1)
+			if keys {
+				replaced, deleted = []byte("a"), "b"
+				if err := b.ClearRecord(uint64(1)); err == nil {
+					t.Fatalf("expected an error clearing a record by ID from a keyed table")
+				}
+			} else if err := b.ClearRecord("a"); err == nil {
+				t.Fatalf("expected an error clearing a record by key from an unkeyed table")
+			}
+			if err := b.Add(Row{ID: replaced, Values: []interface{}{uint64(3)}}); err != nil {
+				t.Fatalf("adding row: %v", err)
+			}
+			for _, id := range []interface{}{replaced, deleted} {
+				if err := b.ClearRecord(id); err != nil {
+					t.Fatalf("clearing record %v: %v", id, err)
+				}
+			}
+			if err := b.Import(); err != nil {
+				t.Fatalf("importing: %v", err)
+			}
+
+			if cleared := importer.clearedRecords(t, 0); !reflect.DeepEqual(cleared, []uint64{1}) {
+				t.Errorf("expected record 1 cleared from shard 0 before its values are set, got %v", cleared)
+			}
+			if views := importer.imports[0].Views; len(views) != 3 {
+				t.Errorf("expected the existence and values of the record to follow, got %+v", views)
+			}
+			if cleared := importer.clearedRecords(t, 1); !reflect.DeepEqual(cleared, []uint64{1}) {
+				t.Errorf("expected record 1 cleared from shard 1, got %v", cleared)
+			}
+
+			// the next import clears nothing
+			importer.imports = make(map[uint64]*featurebase.ImportRoaringShardRequest)
+			if err := b.Add(Row{ID: replaced, Values: []interface{}{uint64(4)}}); err != nil {
+				t.Fatalf("adding row: %v", err)
+			}
+			if err := b.Import(); err != nil {
+				t.Fatalf("importing: %v", err)
+			}
+			if cleared := importer.clearedRecords(t, 0); cleared != nil {
+				t.Errorf("expected nothing cleared, got %v", cleared)
+			}
+			if _, ok := importer.imports[1]; ok {
+				t.Errorf("expected nothing imported into shard 1")
+			}
+		})
+	}
+}
+
+// The standard view of a replaced field clears the batch's records as it
+// sets their values, even for records which have no value for the field.
+func TestBatchReplaceFields(t *testing.T) {
+	idx := &featurebase.IndexInfo{
+		Name: "test-replace-fields",
+		Fields: []*featurebase.FieldInfo{
+			{Name: "f", Options: featurebase.FieldOptions{Type: featurebase.FieldTypeSet}},
+			{Name: "g", Options: featurebase.FieldOptions{Type: featurebase.FieldTypeSet}},
+		},
+	}
+	tbl := featurebase.IndexInfoToTable(idx)
+
+	if _, err := NewBatch(nil, 5, tbl, idx.Fields, OptReplaceFields("f")); err == nil || !strings.Contains(err.Error(), "shard-transactional") {
+		t.Fatalf("expected an error requiring the shard-transactional endpoint, got %v", err)
+	}
+	if _, err := NewBatch(nil, 5, tbl, idx.Fields, OptUseShardTransactionalEndpoint(true), OptReplaceFields("h")); err == nil {
+		t.Fatalf("expected an error replacing a field which isn't in the batch")
+	}
+
+	importer := newShardImporter()
+	b, err := NewBatch(importer, 5, tbl, idx.Fields, OptUseShardTransactionalEndpoint(true), OptReplaceFields("f"))
+	if err != nil {
+		t.Fatalf("getting batch: %v", err)
+	}
+	if err := b.Add(Row{ID: uint64(1), Values: []interface{}{uint64(3), uint64(4)}}); err != nil {
+		t.Fatalf("adding row: %v", err)
+	}
+	if err := b.Add(Row{ID: uint64(2), Values: []interface{}{nil, uint64(4)}}); err != nil {
+		t.Fatalf("adding row: %v", err)
+	}
+	if err := b.Import(); err != nil {
+		t.Fatalf("importing: %v", err)
+	}
+
+	var found bool
+	for _, view := range importer.imports[0].Views {
+		switch view.Field {
+		case "f":
+			if view.View != "" {
+				continue
+			}
+			found = true
+			if !view.ClearRecords {
+				t.Fatalf("expected field f to clear records, got %+v", importer.imports[0].Views)
+			}
+			clear, set := roaring.NewBitmap(), roaring.NewBitmap()
+			if err := clear.UnmarshalBinary(view.Clear); err != nil {
+				t.Fatal(err)
+			}
+			if err := set.UnmarshalBinary(view.Set); err != nil {
+				t.Fatal(err)
+			}
+			if cleared := clear.Slice(); !reflect.DeepEqual(cleared, []uint64{1, 2}) {
+				t.Errorf("expected records 1 and 2 cleared, got %v", cleared)
+			}
+			if bits := set.Slice(); !reflect.DeepEqual(bits, []uint64{3*featurebase.ShardWidth + 1}) {
+				t.Errorf("expected row 3 of record 1 set, got %v", bits)
+			}
+		case "g":
+			if view.ClearRecords {
+				t.Errorf("expected field g not to clear records")
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected an update of field f, got %+v", importer.imports[0].Views)
+	}
+}
+
+// The checkpoint is only imported with the data for IngestCheckpointShard,
+// after the data for every other shard has been, so a failure importing any
+// of them leaves the previous checkpoint, and the data for the shards which
+// were imported is imported again when the ingester resumes from it.
+func TestBatchCheckpointLast(t *testing.T) {
+	idx := &featurebase.IndexInfo{
+		Name: "test-checkpoint-last",
+		Fields: []*featurebase.FieldInfo{
+			{Name: "f", Options: featurebase.FieldOptions{Type: featurebase.FieldTypeSet}},
+		},
+	}
+	tbl := featurebase.IndexInfoToTable(idx)
+	importer := newShardImporter()
+	b, err := NewBatch(importer, 5, tbl, idx.Fields, OptUseShardTransactionalEndpoint(true))
+	if err != nil {
+		t.Fatalf("getting batch: %v", err)
+	}
+	add := func() {
+		t.Helper()
+		for _, id := range []uint64{1, featurebase.ShardWidth + 1, 2*featurebase.ShardWidth + 1} {
+			if err := b.Add(Row{ID: id, Values: []interface{}{uint64(3)}}); err != nil {
+				t.Fatalf("adding row: %v", err)
+			}
+		}
+		if err := b.SetCheckpoint(&featurebase.IngestCheckpoint{Key: "k", Offsets: map[string]uint64{"t:0": 3}}); err != nil {
+			t.Fatalf("setting checkpoint: %v", err)
+		}
+	}
+
+	add()
+	if err := b.Import(); err != nil {
+		t.Fatalf("importing: %v", err)
+	}
+	if len(importer.order) != 3 || importer.order[2] != featurebase.IngestCheckpointShard {
+		t.Fatalf("expected shard %d imported last, got %v", featurebase.IngestCheckpointShard, importer.order)
+	}
+	if importer.imports[featurebase.IngestCheckpointShard].Checkpoint == nil {
+		t.Fatalf("expected the checkpoint imported into shard %d", featurebase.IngestCheckpointShard)
+	}
+	for _, shard := range []uint64{1, 2} {
+		if importer.imports[shard].Checkpoint != nil {
+			t.Fatalf("expected no checkpoint imported into shard %d", shard)
+		}
+	}
+
+	importer = newShardImporter()
+	importer.fail = map[uint64]bool{2: true}
+	b.importer = importer
+	add()
+	if err := b.Import(); err == nil {
+		t.Fatalf("expected an error importing into shard 2")
+	}
+	if _, ok := importer.imports[featurebase.IngestCheckpointShard]; ok {
+		t.Fatalf("expected the checkpoint not to be imported after a failure")
+	}
+}
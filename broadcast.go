@@ -73,6 +73,7 @@ const (
 	messageTypeUNUSED3 // used to be ResizeAbortMessage
 	messageTypeUpdateField
 	messageTypeDeleteDataframe
+	messageTypeRenameField
 )
 
 // MarshalInternalMessage serializes the pilosa message and adds pilosa internal
@@ -120,6 +121,8 @@ func getMessage(typ byte) Message {
 		return &UpdateFieldMessage{}
 	case messageTypeDeleteDataframe:
 		return &DeleteDataframeMessage{}
+	case messageTypeRenameField:
+		return &RenameFieldMessage{}
 	default:
 		panic(fmt.Sprintf("unknown message type %d", typ))
 	}
@@ -159,6 +162,8 @@ func getMessageType(m Message) byte {
 		return messageTypeUpdateField
 	case *DeleteDataframeMessage:
 		return messageTypeDeleteDataframe
+	case *RenameFieldMessage:
+		return messageTypeRenameField
 	default:
 		panic(fmt.Sprintf("don't have type for message %#v", m))
 	}
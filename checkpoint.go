@@ -0,0 +1,137 @@
+// Copyright 2023 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ingestCheckpointFieldName is the name under which ingest checkpoints
+	// are stored in a shard's bitmaps, with a view per key. It isn't a field
+	// of the index, so queries, imports and record deletes never touch it.
+	ingestCheckpointFieldName = "_checkpoints"
+
+	// IngestCheckpointShard is the shard ingest checkpoints are stored in.
+	IngestCheckpointShard = 0
+)
+
+// ingestCheckpointKeyRegexp matches the keys which can be used as view
+// names.
+var ingestCheckpointKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9_.=+-]{1,230}$`)
+
+// IngestCheckpoint is the position an ingester has reached in its sources:
+// for each source (a Kafka partition, say), the offset of the next record
+// to read from it, and optionally where that source's last record came
+// from in the source's own terms. It's stored in the same transaction as
+// the data for IngestCheckpointShard imported from the records before it,
+// once the data for every other shard has been imported, so an ingester
+// that resumes from it skips none of them, whatever happened to the
+// offsets it committed to the sources themselves. The data for other
+// shards isn't in that transaction, so an ingester resuming from it
+// imports records at least once rather than exactly once: if an import
+// failed part of the way through, the records whose data was imported
+// into other shards are imported again.
+type IngestCheckpoint struct {
+	// Key identifies the ingester, so that several can store checkpoints
+	// in the same index.
+	Key     string            `json:"key"`
+	Offsets map[string]uint64 `json:"offsets"`
+	// Positions holds, for some of the sources in Offsets, a position
+	// which only the source understands, such as a change-data-capture
+	// connector's position in a database's log.
+	Positions map[string]string `json:"positions,omitempty"`
+}
+
+func validateIngestCheckpointKey(key string) error {
+	if !ingestCheckpointKeyRegexp.MatchString(key) {
+		return NewBadRequestError(errors.Errorf("invalid checkpoint key '%s', must match %s", key, ingestCheckpointKeyRegexp))
+	}
+	return nil
+}
+
+// IngestCheckpoint returns the ingest checkpoint stored under key in the
+// index, which has no offsets if none has been stored. It reads this node's
+// copy of IngestCheckpointShard.
+func (api *API) IngestCheckpoint(ctx context.Context, indexName, key string) (*IngestCheckpoint, error) {
+	index, err := api.Index(ctx, indexName)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting index")
+	}
+	if err := validateIngestCheckpointKey(key); err != nil {
+		return nil, err
+	}
+
+	tx := index.holder.txf.NewTx(Txo{Write: !writable, Index: index, Shard: IngestCheckpointShard})
+	defer tx.Rollback()
+	return readIngestCheckpoint(tx, indexName, key)
+}
+
+// readIngestCheckpoint reads the checkpoint stored under key. Each byte of
+// the JSON-encoded checkpoint is stored as a bit: the nth byte, b, is bit
+// n*256+b.
+func readIngestCheckpoint(tx Tx, index, key string) (*IngestCheckpoint, error) {
+	bm, err := tx.RoaringBitmap(index, ingestCheckpointFieldName, key, IngestCheckpointShard)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading checkpoint")
+	}
+	checkpoint := &IngestCheckpoint{Key: key, Offsets: make(map[string]uint64)}
+	bits := bm.Slice()
+	if len(bits) == 0 {
+		return checkpoint, nil
+	}
+	data := make([]byte, len(bits))
+	for i, bit := range bits {
+		if bit>>8 != uint64(i) {
+			return nil, errors.Errorf("checkpoint %s is corrupt at byte %d", key, i)
+		}
+		data[i] = byte(bit)
+	}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, errors.Wrapf(err, "decoding checkpoint %s", key)
+	}
+	return checkpoint, nil
+}
+
+// writeIngestCheckpoint merges the offsets and positions of checkpoint into
+// those stored under its key, so that ingesters reading different sources
+// can share a key.
+func writeIngestCheckpoint(tx Tx, index string, checkpoint *IngestCheckpoint) error {
+	stored, err := readIngestCheckpoint(tx, index, checkpoint.Key)
+	if err != nil {
+		return err
+	}
+	for source, offset := range checkpoint.Offsets {
+		stored.Offsets[source] = offset
+	}
+	for source, position := range checkpoint.Positions {
+		if stored.Positions == nil {
+			stored.Positions = make(map[string]string)
+		}
+		stored.Positions[source] = position
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return errors.Wrap(err, "encoding checkpoint")
+	}
+
+	bm, err := tx.RoaringBitmap(index, ingestCheckpointFieldName, checkpoint.Key, IngestCheckpointShard)
+	if err != nil {
+		return errors.Wrap(err, "reading checkpoint")
+	}
+	if _, err := tx.Remove(index, ingestCheckpointFieldName, checkpoint.Key, IngestCheckpointShard, bm.Slice()...); err != nil {
+		return errors.Wrap(err, "clearing checkpoint")
+	}
+	bits := make([]uint64, len(data))
+	for i, b := range data {
+		bits[i] = uint64(i)<<8 | uint64(b)
+	}
+	if _, err := tx.Add(index, ingestCheckpointFieldName, checkpoint.Key, IngestCheckpointShard, bits...); err != nil {
+		return errors.Wrap(err, "writing checkpoint")
+	}
+	return nil
+}
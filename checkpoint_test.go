@@ -0,0 +1,132 @@
+// Copyright 2023 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/batch"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/disco"
+	"github.com/featurebasedb/featurebase/v3/roaring"
+	"github.com/featurebasedb/featurebase/v3/test"
+)
+
+// Ensure ingest checkpoints are imported with a batch, merged with those
+// already stored, untouched by record deletes, and kept across restarts.
+func TestAPI_IngestCheckpoint(t *testing.T) {
+	c := test.MustRunUnsharedCluster(t, 1)
+	defer c.Close()
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "f", pilosa.OptFieldTypeDefault())
+
+	ctx := context.Background()
+	node := c.GetNode(0)
+	tbl, err := pilosa.NewOnPremSchema(node.API).TableByName(ctx, dax.TableName(c.Idx()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := []*pilosa.FieldInfo{pilosa.TableToIndexInfo(tbl).Field("f")}
+	b, err := batch.NewBatch(pilosa.NewOnPremImporter(node.API), 10, tbl, fields, batch.OptUseShardTransactionalEndpoint(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mustCheckpoint := func(t *testing.T, exp map[string]uint64, expPositions map[string]string) {
+		t.Helper()
+		checkpoint, err := node.API.IngestCheckpoint(ctx, c.Idx(), "ingester-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(checkpoint.Offsets, exp) {
+			t.Fatalf("expected offsets %v, got %v", exp, checkpoint.Offsets)
+		}
+		if !reflect.DeepEqual(checkpoint.Positions, expPositions) {
+			t.Fatalf("expected positions %v, got %v", expPositions, checkpoint.Positions)
+		}
+	}
+	mustCheckpoint(t, map[string]uint64{}, nil)
+
+	for _, id := range []uint64{1, ShardWidth + 1} {
+		if err := b.Add(batch.Row{ID: id, Values: []interface{}{uint64(3)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.SetCheckpoint(&pilosa.IngestCheckpoint{Key: "ingester-1", Offsets: map[string]uint64{"t:0": 10, "t:1": 4}, Positions: map[string]string{"t:0": "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Import(); err != nil {
+		t.Fatal(err)
+	}
+	if res := node.QueryAPI(t, &pilosa.QueryRequest{Index: c.Idx(), Query: "Count(Row(f=3))"}); res.Results[0] != uint64(2) {
+		t.Fatalf("expected 2 records, got %v", res.Results[0])
+	}
+	mustCheckpoint(t, map[string]uint64{"t:0": 10, "t:1": 4}, map[string]string{"t:0": "a"})
+
+	// A checkpoint alone, with nothing else in the batch, is merged with the
+	// one stored.
+	if err := b.SetCheckpoint(&pilosa.IngestCheckpoint{Key: "ingester-1", Offsets: map[string]uint64{"t:1": 9, "t:2": 1}, Positions: map[string]string{"t:1": "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Import(); err != nil {
+		t.Fatal(err)
+	}
+	mustCheckpoint(t, map[string]uint64{"t:0": 10, "t:1": 9, "t:2": 1}, map[string]string{"t:0": "a", "t:1": "b"})
+
+	// Deleting every record in the shard leaves it alone.
+	clear := &bytes.Buffer{}
+	if _, err := roaring.NewBitmap(0, 1, 2, 10, 256, 1000).WriteTo(clear); err != nil {
+		t.Fatal(err)
+	}
+	if err := node.API.ImportRoaringShard(ctx, c.Idx(), pilosa.IngestCheckpointShard, &pilosa.ImportRoaringShardRequest{
+		Remote: true,
+		Views:  []pilosa.RoaringUpdate{{ClearRecords: true, Clear: clear.Bytes()}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	mustCheckpoint(t, map[string]uint64{"t:0": 10, "t:1": 9, "t:2": 1}, map[string]string{"t:0": "a", "t:1": "b"})
+
+	for shard, checkpoint := range map[uint64]*pilosa.IngestCheckpoint{
+		1: {Key: "ingester-1"},
+		0: {Key: "bad:key"},
+	} {
+		if err := node.API.ImportRoaringShard(ctx, c.Idx(), shard, &pilosa.ImportRoaringShardRequest{Remote: true, Checkpoint: checkpoint}); err == nil {
+			t.Fatalf("expected error importing checkpoint %v into shard %d", checkpoint, shard)
+		}
+	}
+
+	if err := node.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AwaitState(disco.ClusterStateNormal, 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	mustCheckpoint(t, map[string]uint64{"t:0": 10, "t:1": 9, "t:2": 1}, map[string]string{"t:0": "a", "t:1": "b"})
+	if res := node.QueryAPI(t, &pilosa.QueryRequest{Index: c.Idx(), Query: "Count(Row(f=3))"}); res.Results[0] != uint64(1) {
+		t.Fatalf("expected 1 record after delete, got %v", res.Results[0])
+	}
+
+	req, err := http.NewRequest("GET", node.URL()+"/index/"+c.Idx()+"/checkpoint/ingester-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var checkpoint pilosa.IngestCheckpoint
+	if err := json.NewDecoder(resp.Body).Decode(&checkpoint); err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.Key != "ingester-1" || checkpoint.Offsets["t:1"] != 9 {
+		t.Fatalf("unexpected checkpoint over HTTP: %+v", checkpoint)
+	}
+}
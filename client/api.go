@@ -138,6 +138,8 @@ func (s *schemaAPI) addFieldToIndex(idx *Index, fieldName string, ffos featureba
 		)
 	case featurebase.FieldTypeTimestamp:
 		cfos = append(cfos, OptFieldTypeTimestamp(featurebase.DefaultEpoch, ffos.TimeUnit))
+	case featurebase.FieldTypeFloat:
+		cfos = append(cfos, OptFieldTypeFloat())
 	default:
 		return nil, errors.Errorf("unsupported field type: %s", ffos.Type)
 	}
@@ -679,6 +679,29 @@ func (c *Client) ImportRoaringShard(index string, shard uint64, request *pilosa.
 	return errors.Wrap(err, "importing")
 }
 
+// IngestCheckpoint returns the ingest checkpoint stored under key in the
+// index, which has no offsets if none has been stored.
+func (c *Client) IngestCheckpoint(index, key string) (*pilosa.IngestCheckpoint, error) {
+	uris, err := c.getURIsForShard(index, pilosa.IngestCheckpointShard)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting URIs for checkpoint")
+	}
+	if len(uris) == 0 {
+		return nil, errors.Errorf("no nodes hold shard %d of %s", pilosa.IngestCheckpointShard, index)
+	}
+
+	path := fmt.Sprintf("/index/%s/checkpoint/%s", index, url.PathEscape(key))
+	_, data, err := c.doRequest(uris[0], "GET", path, c.augmentHeaders(defaultJSONHeaders()), nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "requesting %s", path)
+	}
+	checkpoint := &pilosa.IngestCheckpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling checkpoint")
+	}
+	return checkpoint, nil
+}
+
 // ImportRoaringBitmap can import pre-made bitmaps for a number of
 // different views into the given field/shard. If the view name in the
 // map is an empty string, the standard view will be used.
@@ -1701,6 +1724,7 @@ type SchemaOptions struct {
 	Base           int64         `json:"base"`
 	Epoch          time.Time     `json:"epoch"`
 	ForeignIndex   string        `json:"foreignIndex"`
+	PrimaryKey     []string      `json:"primaryKey"`
 }
 
 func (so SchemaOptions) asIndexOptions() *IndexOptions {
@@ -1709,6 +1733,7 @@ func (so SchemaOptions) asIndexOptions() *IndexOptions {
 		keysSet:           true,
 		trackExistence:    so.TrackExistence,
 		trackExistenceSet: true,
+		primaryKey:        so.PrimaryKey,
 	}
 }
 
@@ -137,6 +137,10 @@ func ToClientField(index string, ff *featurebase.FieldInfo) (*Field, error) {
 		opts = append(opts,
 			OptFieldTypeTimestamp(epoch, ff.Options.TimeUnit),
 		)
+	case featurebase.FieldTypeFloat:
+		opts = append(opts,
+			OptFieldTypeFloat(),
+		)
 	}
 
 	return idx.Field(ff.Name, opts...), nil
@@ -212,6 +216,10 @@ func TableFieldToClientField(qtbl *dax.QualifiedTable, fname dax.FieldName) (*Fi
 		opts = append(opts,
 			OptFieldTypeTimestamp(fld.Options.Epoch, fld.Options.TimeUnit),
 		)
+	case dax.BaseTypeDouble:
+		opts = append(opts,
+			OptFieldTypeFloat(),
+		)
 	}
 
 	return idx.Field(string(fld.Name), opts...), nil
@@ -264,6 +264,7 @@ type IndexOptions struct {
 	keysSet           bool
 	trackExistence    bool
 	trackExistenceSet bool
+	primaryKey        []string
 }
 
 func (io *IndexOptions) withDefaults() (updated *IndexOptions) {
@@ -289,6 +290,12 @@ func (io IndexOptions) TrackExistence() bool {
 	return io.trackExistence
 }
 
+// PrimaryKey returns the fields whose values make up the keys of this index,
+// if it has a composite primary key.
+func (io IndexOptions) PrimaryKey() []string {
+	return io.primaryKey
+}
+
 // String serializes this index to a JSON string.
 func (io IndexOptions) String() string {
 	mopt := map[string]interface{}{}
@@ -298,6 +305,9 @@ func (io IndexOptions) String() string {
 	if io.trackExistenceSet {
 		mopt["trackExistence"] = io.trackExistence
 	}
+	if len(io.primaryKey) > 0 {
+		mopt["primaryKey"] = io.primaryKey
+	}
 	return fmt.Sprintf(`{"options":%s}`, encodeMap(mopt))
 }
 
@@ -329,6 +339,14 @@ func OptIndexTrackExistence(trackExistence bool) IndexOption {
 	}
 }
 
+// OptIndexPrimaryKey makes the keys of a keyed index from the values of the
+// given fields, so that records can be found by any prefix of them.
+func OptIndexPrimaryKey(fields ...string) IndexOption {
+	return func(options *IndexOptions) {
+		options.primaryKey = fields
+	}
+}
+
 // OptionsOptions is used to pass an option to Option call.
 type OptionsOptions struct {
 	shards []uint64
@@ -1004,6 +1022,13 @@ func OptFieldTypeDecimal(scale int64, minmax ...pql.Decimal) FieldOption {
 	}
 }
 
+// OptFieldTypeFloat adds a float field.
+func OptFieldTypeFloat() FieldOption {
+	return func(options *FieldOptions) {
+		options.fieldType = FieldTypeFloat
+	}
+}
+
 // OptFieldKeys sets whether field uses string keys.
 func OptFieldKeys(keys bool) FieldOption {
 	return func(options *FieldOptions) {
@@ -1245,6 +1270,9 @@ const (
 	// Molecula's Pilosa with enterprise extensions.
 	FieldTypeDecimal   FieldType = "decimal"
 	FieldTypeTimestamp FieldType = "timestamp"
+	// FieldTypeFloat stores IEEE-754 doubles in an order-preserving
+	// integer encoding.
+	FieldTypeFloat FieldType = "float"
 )
 
 // CacheType represents cache type for a field
@@ -136,6 +136,15 @@ func TestORM(t *testing.T) {
 		if target != index.options.String() {
 			t.Fatalf("%s != %s", target, index.options.String())
 		}
+
+		index = schemal.Index("index-primarykey", OptIndexKeys(true), OptIndexPrimaryKey("a", "b"))
+		if !reflect.DeepEqual([]string{"a", "b"}, index.Opts().PrimaryKey()) {
+			t.Fatalf("index primary key %v != %v", []string{"a", "b"}, index.Opts().PrimaryKey())
+		}
+		target = `{"options":{"keys":true,"primaryKey":["a","b"]}}`
+		if target != index.options.String() {
+			t.Fatalf("%s != %s", target, index.options.String())
+		}
 	})
 
 	t.Run("NilIndexOption", func(t *testing.T) {
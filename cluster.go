@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -460,6 +462,104 @@ func (c *cluster) matchField(ctx context.Context, field *Field, like string) ([]
 	return c.InternalClient.MatchFieldKeysNode(ctx, &primary.URI, field.Index(), field.Name(), like)
 }
 
+// matchIndexKeys finds the IDs of all of an index's keys beginning with
+// prefix. Each partition is matched by its primary node.
+func (c *cluster) matchIndexKeys(ctx context.Context, indexName string, prefix string) ([]uint64, error) {
+	idx := c.holder.Index(indexName)
+	if idx == nil {
+		return nil, ErrIndexNotFound
+	}
+	if !idx.Keys() {
+		return nil, errors.Errorf("cannot match keys on unkeyed index %q", indexName)
+	}
+
+	// A compute node only has, and only matches, the partitions it owns.
+	if c.isComputeNode {
+		return c.matchLocalIndexKeys(idx, prefix, idx.translatePartitions)
+	}
+
+	// Group the partitions by their primary nodes.
+	snap := c.NewSnapshot()
+	var local dax.PartitionNums
+	remote := make(map[*disco.Node]struct{})
+	for partitionID := 0; partitionID < c.partitionN; partitionID++ {
+		primary := snap.PrimaryPartitionNode(partitionID)
+		if primary == nil {
+			return nil, errors.Errorf("matching index(%s) keys on partition(%d) - cannot find primary node", indexName, partitionID)
+		}
+		if c.Node.ID == primary.ID {
+			local = append(local, dax.PartitionNum(partitionID))
+			continue
+		}
+		remote[primary] = struct{}{}
+	}
+
+	// Each remote node matches the partitions it is the primary for.
+	remoteResults := make(chan []uint64, len(remote))
+	var g errgroup.Group
+	defer g.Wait() //nolint:errcheck
+	for node := range remote {
+		node := node
+		g.Go(func() error {
+			ids, err := c.InternalClient.MatchIndexKeysNode(ctx, &node.URI, indexName, prefix)
+			if err != nil {
+				return errors.Wrapf(err, "matching index(%s) keys on node %s", indexName, node.ID)
+			}
+			remoteResults <- ids
+			return nil
+		})
+	}
+
+	ids, err := c.matchLocalIndexKeys(idx, prefix, local)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	close(remoteResults)
+	for remoteIDs := range remoteResults {
+		ids = append(ids, remoteIDs...)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// matchPrimaryIndexKeys finds the IDs of the index's keys beginning with
+// prefix in the partitions this node is the primary for.
+func (c *cluster) matchPrimaryIndexKeys(indexName string, prefix string) ([]uint64, error) {
+	idx := c.holder.Index(indexName)
+	if idx == nil {
+		return nil, ErrIndexNotFound
+	}
+	snap := c.NewSnapshot()
+	var partitions dax.PartitionNums
+	for partitionID := 0; partitionID < c.partitionN; partitionID++ {
+		if primary := snap.PrimaryPartitionNode(partitionID); primary != nil && primary.ID == c.Node.ID {
+			partitions = append(partitions, dax.PartitionNum(partitionID))
+		}
+	}
+	return c.matchLocalIndexKeys(idx, prefix, partitions)
+}
+
+func (c *cluster) matchLocalIndexKeys(idx *Index, prefix string, partitions dax.PartitionNums) ([]uint64, error) {
+	var ids []uint64
+	for _, partitionID := range partitions {
+		store := idx.TranslateStore(int(partitionID))
+		if store == nil {
+			return nil, ErrTranslateStoreNotFound
+		}
+		matches, err := store.Match(func(key []byte) bool {
+			return strings.HasPrefix(string(key), prefix)
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "matching index(%s) keys on partition(%d)", idx.Name(), partitionID)
+		}
+		ids = append(ids, matches...)
+	}
+	return ids, nil
+}
+
 func (c *cluster) translateFieldIDs(ctx context.Context, field *Field, ids map[uint64]struct{}) (map[uint64]string, error) {
 	idList := make([]uint64, len(ids))
 	{
@@ -949,6 +1049,13 @@ type DeleteFieldMessage struct {
 	Field string
 }
 
+// RenameFieldMessage is an internal message indicating a field rename.
+type RenameFieldMessage struct {
+	Index   string
+	Field   string
+	NewName string
+}
+
 // DeleteAvailableShardMessage is an internal message indicating available shard deletion.
 type DeleteAvailableShardMessage struct {
 	Index   string
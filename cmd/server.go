@@ -5,7 +5,10 @@ package cmd
 import (
 	"io"
 
+	pilosa "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/ctl"
+	"github.com/featurebasedb/featurebase/v3/idk"
+	idkapi "github.com/featurebasedb/featurebase/v3/idk/api"
 	"github.com/featurebasedb/featurebase/v3/server"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -43,7 +46,7 @@ This is only useful for diagnostic use.
 
 // newServeCmd creates a FeatureBase server and runs it with command line flags.
 func newServeCmd(stderr io.Writer) *cobra.Command {
-	Server = server.NewCommand(stderr)
+	Server = server.NewCommand(stderr, server.OptCommandRecordIngester(newRecordIngester))
 	serveCmd := &cobra.Command{
 		Use:   "server",
 		Short: "Run FeatureBase.",
@@ -65,3 +68,14 @@ on the configured port.`,
 	ctl.BuildServerFlags(serveCmd, Server)
 	return serveCmd
 }
+
+// newRecordIngester returns the ingester for records posted to the server,
+// which ingests them with idk.
+func newRecordIngester(api *pilosa.API, tls server.TLSConfig) pilosa.RecordIngester {
+	return idkapi.NewRecordIngester(api, idk.TLSConfig{
+		CertificatePath:    tls.CertificatePath,
+		CertificateKeyPath: tls.CertificateKeyPath,
+		CACertPath:         tls.CACertPath,
+		SkipVerify:         tls.SkipVerify,
+	})
+}
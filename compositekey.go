@@ -0,0 +1,120 @@
+// Copyright 2023 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CompositeKeyDelimiter separates the parts of a composite record key. It is
+// the delimiter idk has always used to join the values of PrimaryKeyFields.
+const CompositeKeyDelimiter = '|'
+
+// CompositeKeyEscape precedes a delimiter or escape character which is part
+// of a key part's value.
+const CompositeKeyEscape = '\\'
+
+// EncodeCompositeKey returns the record key made up of parts, the values of
+// an index's primary key fields in order. Parts may be strings, byte slices
+// or integers, the values of ID, INT and STRING fields, which are the only
+// types a primary key field can have. Delimiters within a part are escaped,
+// so a key can always be split back into its parts, and the keys sharing
+// their first parts share the prefix returned by CompositeKeyPrefix.
+//
+// Only indexes declaring a PrimaryKey have keys encoded this way. idk has
+// always joined the values of its PrimaryKeyFields with the delimiter without
+// escaping them, and keeps doing so for indexes which don't declare one, so
+// that their existing keys don't change.
+func EncodeCompositeKey(parts ...interface{}) (string, error) {
+	var buf strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			buf.WriteByte(CompositeKeyDelimiter)
+		}
+		if err := writeCompositeKeyPart(&buf, part); err != nil {
+			return "", errors.Wrapf(err, "encoding primary key part %d", i)
+		}
+	}
+	return buf.String(), nil
+}
+
+// CompositeKeyPrefix returns the prefix of every composite key whose first
+// parts are parts.
+func CompositeKeyPrefix(parts ...interface{}) (string, error) {
+	key, err := EncodeCompositeKey(parts...)
+	if err != nil {
+		return "", err
+	}
+	return key + string(CompositeKeyDelimiter), nil
+}
+
+// SplitCompositeKey returns the parts of a key encoded by EncodeCompositeKey.
+func SplitCompositeKey(key string) []string {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; {
+		case c == CompositeKeyEscape && i+1 < len(key):
+			i++
+			part.WriteByte(key[i])
+		case c == CompositeKeyDelimiter:
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(c)
+		}
+	}
+	return append(parts, part.String())
+}
+
+func writeCompositeKeyPart(buf *strings.Builder, part interface{}) error {
+	var s string
+	switch v := part.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case uint64:
+		s = strconv.FormatUint(v, 10)
+	case int:
+		s = strconv.Itoa(v)
+	case nil:
+		return errors.New("primary key values cannot be null")
+	default:
+		return errors.Errorf("unsupported primary key value %v of type %[1]T", v)
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c == CompositeKeyDelimiter || c == CompositeKeyEscape {
+			buf.WriteByte(CompositeKeyEscape)
+		}
+		buf.WriteByte(s[i])
+	}
+	return nil
+}
+
+// validatePrimaryKey returns an error if the primary key fields named in
+// opts can't make up the keys of the index.
+func validatePrimaryKey(opts IndexOptions) error {
+	if len(opts.PrimaryKey) == 0 {
+		return nil
+	}
+	if !opts.Keys {
+		return errors.New("an index with a primary key must have keys")
+	}
+	seen := make(map[string]struct{}, len(opts.PrimaryKey))
+	for _, name := range opts.PrimaryKey {
+		if name == "" {
+			return errors.New("primary key field names cannot be empty")
+		}
+		if _, ok := seen[name]; ok {
+			return errors.Errorf("duplicate primary key field '%s'", name)
+		}
+		seen[name] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+)
+
+func TestCompositeKey(t *testing.T) {
+	for _, tc := range []struct {
+		parts []interface{}
+		key   string
+		split []string
+	}{
+		{[]interface{}{"a", int64(1)}, "a|1", []string{"a", "1"}},
+		{[]interface{}{"a|b", uint64(2), 3}, `a\|b|2|3`, []string{"a|b", "2", "3"}},
+		{[]interface{}{`c\`, []byte("d")}, `c\\|d`, []string{`c\`, "d"}},
+		{[]interface{}{"", int64(-3)}, "|-3", []string{"", "-3"}},
+	} {
+		key, err := pilosa.EncodeCompositeKey(tc.parts...)
+		if err != nil {
+			t.Fatal(err)
+		} else if key != tc.key {
+			t.Fatalf("expected key %q, got %q", tc.key, key)
+		}
+		if split := pilosa.SplitCompositeKey(key); !reflect.DeepEqual(split, tc.split) {
+			t.Fatalf("expected parts %q, got %q", tc.split, split)
+		}
+		prefix, err := pilosa.CompositeKeyPrefix(tc.parts[0])
+		if err != nil {
+			t.Fatal(err)
+		} else if !strings.HasPrefix(key, prefix) {
+			t.Fatalf("expected key %q to have prefix %q", key, prefix)
+		}
+	}
+
+	// a part containing the delimiter doesn't share the prefix of its
+	// leading characters
+	prefix, err := pilosa.CompositeKeyPrefix("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key, _ := pilosa.EncodeCompositeKey("a|b", "c"); strings.HasPrefix(key, prefix) {
+		t.Fatalf("key %q unexpectedly has prefix %q", key, prefix)
+	}
+
+	if _, err := pilosa.EncodeCompositeKey("a", nil); err == nil || !strings.Contains(err.Error(), "cannot be null") {
+		t.Fatalf("expected null error, got %v", err)
+	}
+	for _, part := range []interface{}{1.5, true} {
+		if _, err := pilosa.EncodeCompositeKey(part); err == nil || !strings.Contains(err.Error(), "unsupported") {
+			t.Fatalf("expected unsupported error, got %v", err)
+		}
+	}
+}
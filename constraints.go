@@ -0,0 +1,289 @@
+// Copyright 2023 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa
+
+import (
+	"fmt"
+
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/pkg/errors"
+)
+
+// Names of the column constraints reported in a ConstraintError.
+const (
+	ConstraintNotNull = "NOT NULL"
+	ConstraintCheck   = "CHECK"
+)
+
+// ConstraintError is returned when a value written to a field violates one of
+// the field's constraints.
+type ConstraintError struct {
+	Field      string
+	Constraint string
+	Expr       string
+}
+
+func (e *ConstraintError) Error() string {
+	if e.Expr != "" {
+		return fmt.Sprintf("column '%s' violates %s constraint (%s)", e.Field, e.Constraint, e.Expr)
+	}
+	return fmt.Sprintf("column '%s' violates %s constraint", e.Field, e.Constraint)
+}
+
+// ConstraintExpr is a compiled DEFAULT or CHECK expression.
+type ConstraintExpr interface {
+	// Evaluate evaluates the expression, with val as the value of the
+	// column it constrains. A DEFAULT's value is returned in the form
+	// FieldConstraints handles values in; a CHECK's is a bool, or nil.
+	Evaluate(val interface{}) (interface{}, error)
+}
+
+// CompileConstraintExpr compiles the DEFAULT (check is false) or CHECK
+// (check is true) expression sql of the field named field, whose options are
+// opts. Only a CHECK can refer to a column, and only to field. It's set by
+// the sql3 planner, so that constraints are evaluated by its expression
+// engine, which this package can't import.
+var CompileConstraintExpr func(sql string, field string, opts FieldOptions, check bool) (ConstraintExpr, error)
+
+// FieldConstraints enforces the NOT NULL, DEFAULT and CHECK constraints of a
+// field. Values are handled in the form sql3 evaluates them to: int64 for int
+// and id columns, pql.Decimal for decimals, float64 for doubles, time.Time
+// for timestamps, string for keyed columns and bool for bools.
+//
+// Constraints are checked by the batch, which sql3 and idk import through,
+// and by Import and ImportValue. ImportRoaring and ImportRoaringShard take
+// data already encoded by the batch, so they aren't checked again.
+type FieldConstraints struct {
+	field   string
+	opts    FieldOptions
+	notNull bool
+	dflt    ConstraintExpr
+	check   ConstraintExpr
+}
+
+// NewFieldConstraints compiles the constraints in opts. It returns nil if
+// the field has none.
+func NewFieldConstraints(field string, opts FieldOptions) (*FieldConstraints, error) {
+	if !opts.NotNull && opts.Default == "" && opts.Check == "" {
+		return nil, nil
+	}
+	c := &FieldConstraints{
+		field:   field,
+		opts:    opts,
+		notNull: opts.NotNull,
+	}
+
+	if opts.Default != "" || opts.Check != "" {
+		switch {
+		case opts.Type == FieldTypeSet, opts.Type == FieldTypeTime:
+			return nil, errors.Errorf("column '%s': DEFAULT and CHECK constraints are not supported on set columns", field)
+		case opts.JSON, opts.GeoPoint, opts.BitVector != 0:
+			return nil, errors.Errorf("column '%s': DEFAULT and CHECK constraints are not supported on this column type", field)
+		case CompileConstraintExpr == nil:
+			return nil, errors.Errorf("column '%s': DEFAULT and CHECK constraints can't be compiled without the sql3 planner", field)
+		}
+	}
+
+	if opts.Default != "" {
+		expr, err := CompileConstraintExpr(opts.Default, field, opts, false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "DEFAULT of column '%s'", field)
+		}
+		c.dflt = expr
+		// Evaluating the default makes sure its value fits the column.
+		if _, err := c.Default(); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Check != "" {
+		expr, err := CompileConstraintExpr(opts.Check, field, opts, true)
+		if err != nil {
+			return nil, errors.Wrapf(err, "CHECK of column '%s'", field)
+		}
+		c.check = expr
+	}
+
+	return c, nil
+}
+
+// constraintOptions are the field options a field's constraints are compiled
+// from.
+type constraintOptions struct {
+	typ       string
+	keys      bool
+	scale     int64
+	timeUnit  string
+	base      int64
+	json      bool
+	geoPoint  bool
+	bitVector int64
+	notNull   bool
+	dflt      string
+	check     string
+}
+
+func newConstraintOptions(opts FieldOptions) constraintOptions {
+	return constraintOptions{
+		typ:       opts.Type,
+		keys:      opts.Keys,
+		scale:     opts.Scale,
+		timeUnit:  opts.TimeUnit,
+		base:      opts.Base,
+		json:      opts.JSON,
+		geoPoint:  opts.GeoPoint,
+		bitVector: opts.BitVector,
+		notNull:   opts.NotNull,
+		dflt:      opts.Default,
+		check:     opts.Check,
+	}
+}
+
+// HasDefault returns true if the field has a DEFAULT constraint.
+func (c *FieldConstraints) HasDefault() bool {
+	return c != nil && c.dflt != nil
+}
+
+// Default returns the value of the field's DEFAULT constraint, or nil if it
+// doesn't have one.
+func (c *FieldConstraints) Default() (interface{}, error) {
+	if c == nil || c.dflt == nil {
+		return nil, nil
+	}
+	v, err := c.dflt.Evaluate(nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "DEFAULT of column '%s'", c.field)
+	}
+	return v, nil
+}
+
+// Validate returns a *ConstraintError if val violates the NOT NULL or CHECK
+// constraint of the field. A nil val is NULL. As in SQL, a CHECK which
+// evaluates to NULL is satisfied.
+func (c *FieldConstraints) Validate(val interface{}) error {
+	if c == nil {
+		return nil
+	}
+	if val == nil {
+		if c.notNull {
+			return &ConstraintError{Field: c.field, Constraint: ConstraintNotNull}
+		}
+		return nil
+	}
+	if c.check == nil {
+		return nil
+	}
+	v, err := c.check.Evaluate(val)
+	if err != nil {
+		return errors.Wrapf(err, "evaluating CHECK of column '%s'", c.field)
+	}
+	if b, ok := v.(bool); ok && !b {
+		return &ConstraintError{Field: c.field, Constraint: ConstraintCheck, Expr: c.opts.Check}
+	}
+	return nil
+}
+
+// FromInt64 returns the value of the field whose integer representation is
+// v, in the form Validate takes.
+func (c *FieldConstraints) FromInt64(v int64) (interface{}, error) {
+	switch c.opts.Type {
+	case FieldTypeDecimal:
+		return pql.NewDecimal(v, c.opts.Scale), nil
+	case FieldTypeFloat:
+		return ValToFloat(v), nil
+	case FieldTypeTimestamp:
+		// integer timestamps are relative to the epoch
+		return ValToTimestamp(c.opts.TimeUnit, v+c.opts.Base)
+	}
+	return v, nil
+}
+
+// validateImport checks the values of an import into a mutex or bool field
+// against the field's constraints.
+func (c *FieldConstraints) validateImport(req *ImportRequest) error {
+	if c == nil {
+		return nil
+	}
+	if req.Clear {
+		if c.notNull {
+			return &ConstraintError{Field: c.field, Constraint: ConstraintNotNull}
+		}
+		return nil
+	}
+	if c.check == nil {
+		return nil
+	}
+	for i := range req.RowKeys {
+		if err := c.Validate(req.RowKeys[i]); err != nil {
+			return errors.Wrapf(err, "record %v", importRecord(req.ColumnIDs, req.ColumnKeys, i))
+		}
+	}
+	for i, id := range req.RowIDs {
+		var val interface{} = int64(id)
+		if c.opts.Type == FieldTypeBool {
+			val = id == trueRowID
+		}
+		if err := c.Validate(val); err != nil {
+			return errors.Wrapf(err, "record %v", importRecord(req.ColumnIDs, req.ColumnKeys, i))
+		}
+	}
+	return nil
+}
+
+// validateImportValue checks the values of an import into a BSI field against
+// the field's constraints.
+func (c *FieldConstraints) validateImportValue(req *ImportValueRequest) error {
+	if c == nil {
+		return nil
+	}
+	if req.Clear {
+		if c.notNull {
+			return &ConstraintError{Field: c.field, Constraint: ConstraintNotNull}
+		}
+		return nil
+	}
+	if c.check == nil {
+		return nil
+	}
+	validate := func(i int, val interface{}) error {
+		if err := c.Validate(val); err != nil {
+			return errors.Wrapf(err, "record %v", importRecord(req.ColumnIDs, req.ColumnKeys, i))
+		}
+		return nil
+	}
+	for i, v := range req.Values {
+		val, err := c.FromInt64(v)
+		if err != nil {
+			return err
+		}
+		if err := validate(i, val); err != nil {
+			return err
+		}
+	}
+	for i, v := range req.FloatValues {
+		if err := validate(i, v); err != nil {
+			return err
+		}
+	}
+	for i, v := range req.TimestampValues {
+		if err := validate(i, v); err != nil {
+			return err
+		}
+	}
+	for i, v := range req.StringValues {
+		if err := validate(i, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importRecord returns the identifier of the i'th record of an import.
+func importRecord(ids []uint64, keys []string, i int) interface{} {
+	if i < len(keys) {
+		return keys[i]
+	} else if i < len(ids) {
+		return ids[i]
+	}
+	return i
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/pql"
+
+	// compiles the DEFAULT and CHECK expressions
+	_ "github.com/featurebasedb/featurebase/v3/sql3/planner"
+)
+
+func TestFieldConstraints(t *testing.T) {
+	intOpts := pilosa.FieldOptions{Type: pilosa.FieldTypeInt}
+	decimalOpts := pilosa.FieldOptions{Type: pilosa.FieldTypeDecimal, Scale: 2}
+	stringOpts := pilosa.FieldOptions{Type: pilosa.FieldTypeMutex, Keys: true}
+	timestampOpts := pilosa.FieldOptions{Type: pilosa.FieldTypeTimestamp, TimeUnit: pilosa.TimeUnitSeconds}
+
+	t.Run("None", func(t *testing.T) {
+		fc, err := pilosa.NewFieldConstraints("i", intOpts)
+		if err != nil {
+			t.Fatal(err)
+		} else if fc != nil {
+			t.Fatalf("expected no constraints, got %v", fc)
+		}
+		// a nil *FieldConstraints accepts everything
+		if err := fc.Validate(nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("NotNull", func(t *testing.T) {
+		opts := intOpts
+		opts.NotNull = true
+		fc, err := pilosa.NewFieldConstraints("i", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := fc.Validate(int64(0)); err != nil {
+			t.Fatal(err)
+		}
+		var cerr *pilosa.ConstraintError
+		if err := fc.Validate(nil); !errors.As(err, &cerr) || cerr.Constraint != pilosa.ConstraintNotNull {
+			t.Fatalf("expected NOT NULL violation, got %v", err)
+		} else if exp := "column 'i' violates NOT NULL constraint"; err.Error() != exp {
+			t.Fatalf("expected %q, got %q", exp, err.Error())
+		}
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		for _, tc := range []struct {
+			opts pilosa.FieldOptions
+			expr string
+			exp  interface{}
+		}{
+			{intOpts, "-1", int64(-1)},
+			{intOpts, "(2 * 3 + 1)", int64(7)},
+			{decimalOpts, "1.5", pql.NewDecimal(150, 2)},
+			{decimalOpts, "2", pql.NewDecimal(200, 2)},
+			{stringOpts, "('a' || 'b')", "ab"},
+			{timestampOpts, "'2023-01-01'", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{pilosa.FieldOptions{Type: pilosa.FieldTypeBool}, "true", true},
+			{pilosa.FieldOptions{Type: pilosa.FieldTypeFloat}, "0.25", 0.25},
+		} {
+			opts := tc.opts
+			opts.Default = tc.expr
+			fc, err := pilosa.NewFieldConstraints("c", opts)
+			if err != nil {
+				t.Fatalf("%s: %v", tc.expr, err)
+			}
+			v, err := fc.Default()
+			if err != nil {
+				t.Fatalf("%s: %v", tc.expr, err)
+			}
+			switch exp := tc.exp.(type) {
+			case pql.Decimal:
+				if d, ok := v.(pql.Decimal); !ok || !d.EqualTo(exp) {
+					t.Fatalf("%s: expected %v, got %v", tc.expr, exp, v)
+				}
+			case time.Time:
+				if ts, ok := v.(time.Time); !ok || !ts.Equal(exp) {
+					t.Fatalf("%s: expected %v, got %v", tc.expr, exp, v)
+				}
+			default:
+				if v != exp {
+					t.Fatalf("%s: expected %v (%[2]T), got %v (%[3]T)", tc.expr, exp, v)
+				}
+			}
+		}
+	})
+
+	t.Run("Check", func(t *testing.T) {
+		for _, tc := range []struct {
+			opts pilosa.FieldOptions
+			expr string
+			ok   []interface{}
+			bad  []interface{}
+		}{
+			{intOpts, "i BETWEEN 0 AND 10", []interface{}{int64(0), int64(10), nil}, []interface{}{int64(-1), int64(11)}},
+			{intOpts, "i % 2 = 0 OR i > 100", []interface{}{int64(4), int64(101)}, []interface{}{int64(3)}},
+			{intOpts, "i NOT IN (1, 2)", []interface{}{int64(3)}, []interface{}{int64(2)}},
+			{decimalOpts, "i > 0.5", []interface{}{pql.NewDecimal(51, 2)}, []interface{}{pql.NewDecimal(50, 2)}},
+			{stringOpts, "i != ''", []interface{}{"a"}, []interface{}{""}},
+			{timestampOpts, "i < '2030-01-01'", []interface{}{time.Date(2029, 12, 31, 0, 0, 0, 0, time.UTC)}, []interface{}{time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		} {
+			opts := tc.opts
+			opts.Check = tc.expr
+			fc, err := pilosa.NewFieldConstraints("i", opts)
+			if err != nil {
+				t.Fatalf("%s: %v", tc.expr, err)
+			}
+			for _, v := range tc.ok {
+				if err := fc.Validate(v); err != nil {
+					t.Fatalf("%s: unexpected error for %v: %v", tc.expr, v, err)
+				}
+			}
+			for _, v := range tc.bad {
+				var cerr *pilosa.ConstraintError
+				if err := fc.Validate(v); !errors.As(err, &cerr) || cerr.Constraint != pilosa.ConstraintCheck {
+					t.Fatalf("%s: expected CHECK violation for %v, got %v", tc.expr, v, err)
+				}
+			}
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, tc := range []struct {
+			opts   pilosa.FieldOptions
+			exp    string
+			modify func(*pilosa.FieldOptions)
+		}{
+			{intOpts, "cannot be assigned", func(o *pilosa.FieldOptions) { o.Default = "'a'" }},
+			{intOpts, "column reference", func(o *pilosa.FieldOptions) { o.Default = "(i)" }},
+			{intOpts, "can't be referenced", func(o *pilosa.FieldOptions) { o.Check = "j > 0" }},
+			{intOpts, "boolean expression", func(o *pilosa.FieldOptions) { o.Check = "i + 1" }},
+			{intOpts, "not equatable", func(o *pilosa.FieldOptions) { o.Check = "i = 'a'" }},
+			{pilosa.FieldOptions{Type: pilosa.FieldTypeSet}, "set columns", func(o *pilosa.FieldOptions) { o.Check = "i = 1" }},
+		} {
+			opts := tc.opts
+			tc.modify(&opts)
+			if _, err := pilosa.NewFieldConstraints("i", opts); err == nil || !strings.Contains(err.Error(), tc.exp) {
+				t.Fatalf("expected error containing %q, got %v", tc.exp, err)
+			}
+		}
+	})
+}
@@ -1,7 +1,10 @@
 // Copyright 2022 Molecula Corp (DBA FeatureBase). All rights reserved.
 package context
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Empty struct to avoid allocations
 type contextKeyOriginalIP struct{}
@@ -36,3 +39,22 @@ func RequestID(ctx context.Context) (userID string, ok bool) {
 func WithRequestID(ctx context.Context, userID string) context.Context {
 	return context.WithValue(ctx, contextKeyRequestRequestID{}, userID)
 }
+
+// Detach makes a new context which carries all the values in ctx, but which is
+// never canceled and has no deadline. It is used for work, such as an open
+// cursor, which outlives the request that started it.
+func Detach(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) { return }
+func (detachedContext) Done() <-chan struct{}                   { return nil }
+func (detachedContext) Err() error                              { return nil }
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
@@ -231,6 +231,10 @@ type ImportRoaringShardMessage struct {
 	Partition int             `json:"partition"`
 	Shard     uint64          `json:"shard"`
 	Views     []RoaringUpdate `json:"views"`
+
+	// Checkpoint, if set, is the ingest checkpoint stored along with
+	// Views.
+	Checkpoint *IngestCheckpoint `json:"checkpoint,omitempty"`
 }
 
 // RoaringUpdate is identical to featurebase.RoaringUpdate, but we
@@ -245,6 +249,14 @@ type RoaringUpdate struct {
 	ClearRecords bool   `json:"clear-records"`
 }
 
+// IngestCheckpoint is identical to featurebase.IngestCheckpoint, for
+// the same reason as RoaringUpdate.
+type IngestCheckpoint struct {
+	Key       string            `json:"key"`
+	Offsets   map[string]uint64 `json:"offsets"`
+	Positions map[string]string `json:"positions,omitempty"`
+}
+
 // Ensure type implements interface.
 var _ logMessageEncoder = (*encoderJSON)(nil)
 
@@ -672,6 +672,10 @@ func (o *orchestrator) executeLimitCall(ctx context.Context, tableKeyer dax.Tabl
 	if err != nil {
 		return nil, errors.Wrap(err, "getting offset")
 	}
+	after, hasAfter, err := c.UintArg("after")
+	if err != nil {
+		return nil, errors.Wrap(err, "getting after")
+	}
 
 	if !hasLimit {
 		limit = math.MaxUint64
@@ -691,6 +695,22 @@ func (o *orchestrator) executeLimitCall(ctx context.Context, tableKeyer dax.Tabl
 		return nil, errors.Errorf("expected Row but got %T", result)
 	}
 
+	// only the columns after the one given are kept, so that a page can be
+	// read from where the last one ended without counting the columns before it
+	if hasAfter {
+		i := 0
+		var leadingBits []uint64
+		for i < len(result.Segments) && result.Segments[i].Shard() <= after/featurebase.ShardWidth {
+			if seg := result.Segments[i]; seg.Shard() == after/featurebase.ShardWidth {
+				data := seg.Columns()
+				leadingBits = data[sort.Search(len(data), func(j int) bool { return data[j] > after }):]
+			}
+			i++
+		}
+		row := featurebase.NewRow(leadingBits...)
+		row.Merge(&featurebase.Row{Segments: result.Segments[i:]})
+		result = row
+	}
 	if offset != 0 {
 		i := 0
 		var leadingBits []uint64
@@ -2018,6 +2038,10 @@ func (o *orchestrator) executeExtract(ctx context.Context, tableKeyer dax.TableK
 }
 
 func (o *orchestrator) executeConstRow(ctx context.Context, tableKeyer dax.TableKeyer, c *pql.Call) (res *featurebase.Row, err error) {
+	if _, ok := c.Args["keyPrefix"]; ok {
+		return nil, errors.New(errors.ErrUncoded, "ConstRow keyPrefix is not supported")
+	}
+
 	// Fetch user-provided columns list.
 	ids, ok := c.Args["columns"].([]uint64)
 	if !ok {
@@ -73,12 +73,16 @@ const PrefixTable = "tbl"
 
 // Base types.
 const (
+	BaseTypeBitVector  = "bitvector"  // set holding one row per bit
 	BaseTypeBool       = "bool"       //
 	BaseTypeDecimal    = "decimal"    //
+	BaseTypeDouble     = "double"     // float
+	BaseTypeGeoPoint   = "geopoint"   // int holding packed latitude/longitude
 	BaseTypeID         = "id"         // non-keyed mutex
 	BaseTypeIDSet      = "idset"      // non-keyed set
 	BaseTypeIDSetQ     = "idsetq"     // non-keyed set timequantum
 	BaseTypeInt        = "int"        //
+	BaseTypeJSON       = "json"       // non-keyed set holding JSON documents
 	BaseTypeString     = "string"     // keyed mutex
 	BaseTypeStringSet  = "stringset"  // keyed set
 	BaseTypeStringSetQ = "stringsetq" // keyed set timequantum
@@ -378,6 +382,16 @@ type Table struct {
 	Fields     []*Field  `json:"fields"`
 	PartitionN int       `json:"partitionN"`
 
+	// RetentionField, if set, names the timestamp field used to expire
+	// records older than RetentionPeriod.
+	RetentionField  FieldName     `json:"retentionField,omitempty"`
+	RetentionPeriod time.Duration `json:"retentionPeriod,omitempty"`
+
+	// PrimaryKey, if set, names the fields whose values together make up
+	// the string key of each record. The key parts are also stored in the
+	// fields themselves.
+	PrimaryKey []FieldName `json:"primaryKey,omitempty"`
+
 	Description string `json:"description,omitempty"`
 	Owner       string `json:"owner,omitempty"`
 	UpdatedBy   string `json:"updatedBy,omitempty"`
@@ -455,6 +469,17 @@ func (t *Table) HasValidPrimaryKey() bool {
 	return false
 }
 
+// PrimaryKeyPosition returns the position of the named field in the table's
+// composite primary key, or -1 if the field is not part of it.
+func (t *Table) PrimaryKeyPosition(name FieldName) int {
+	for i, pk := range t.PrimaryKey {
+		if pk == name {
+			return i
+		}
+	}
+	return -1
+}
+
 // FieldNames returns the list of field names associated with the table.
 func (t *Table) FieldNames() []FieldName {
 	ret := make([]FieldName, 0, len(t.Fields))
@@ -495,10 +520,21 @@ func (t *Table) CreateSQL() string {
 	for _, fld := range t.Fields {
 		cols = append(cols, fld.CreateSQL())
 	}
+	if len(t.PrimaryKey) > 0 {
+		pk := make([]string, len(t.PrimaryKey))
+		for i := range t.PrimaryKey {
+			pk[i] = string(t.PrimaryKey[i])
+		}
+		cols = append(cols, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pk, ", ")))
+	}
 	sql += strings.Join(cols, ", ")
 
 	sql += fmt.Sprintf(") KEYPARTITIONS %d", t.PartitionN)
 
+	if t.RetentionField != "" {
+		sql += fmt.Sprintf(" RETENTION %s TTL '%s'", t.RetentionField, t.RetentionPeriod)
+	}
+
 	return sql
 }
 
@@ -675,12 +711,16 @@ type BaseType string
 func BaseTypeFromString(s string) (BaseType, error) {
 	lowered := strings.ToLower(s)
 	switch lowered {
-	case BaseTypeBool,
+	case BaseTypeBitVector,
+		BaseTypeBool,
 		BaseTypeDecimal,
+		BaseTypeDouble,
+		BaseTypeGeoPoint,
 		BaseTypeID,
 		BaseTypeIDSet,
 		BaseTypeIDSetQ,
 		BaseTypeInt,
+		BaseTypeJSON,
 		BaseTypeString,
 		BaseTypeStringSet,
 		BaseTypeStringSetQ,
@@ -747,6 +787,8 @@ func (f *Field) FullType() string {
 	switch f.Type {
 	case BaseTypeDecimal:
 		return fmt.Sprintf("%s(%d)", f.Type, f.Options.Scale)
+	case BaseTypeBitVector:
+		return fmt.Sprintf("%s(%d)", f.Type, f.Options.Width)
 	default:
 		return string(f.Type)
 	}
@@ -771,6 +813,9 @@ func (f *Field) IsPrimaryKey() bool {
 // TABLE statement.
 func (f *Field) CreateSQL() string {
 	sql := fmt.Sprintf("%s %s", f.Name, f.Type)
+	if f.Type == BaseTypeBitVector {
+		sql = fmt.Sprintf("%s %s", f.Name, f.FullType())
+	}
 
 	// Apply constraints to all non-primarykey fields.
 	if !f.IsPrimaryKey() {
@@ -787,6 +832,9 @@ func (f *Field) constraints() string {
 	switch f.Type {
 	case BaseTypeInt:
 		sql += fmt.Sprintf(" MIN %d MAX %d", f.Options.Min.ToInt64(0), f.Options.Max.ToInt64(0))
+		sql += f.timeQuantumConstraint()
+	case BaseTypeDecimal:
+		sql += f.timeQuantumConstraint()
 	case BaseTypeID, BaseTypeString:
 		if f.Options.CacheType != "" {
 			sql += fmt.Sprintf(" CACHETYPE %s SIZE %d", f.Options.CacheType, f.Options.CacheSize)
@@ -795,18 +843,42 @@ func (f *Field) constraints() string {
 		if f.Options.CacheType != "" {
 			sql += fmt.Sprintf(" CACHETYPE %s SIZE %d", f.Options.CacheType, f.Options.CacheSize)
 		}
-		if f.Options.TimeQuantum != "" {
-			sql += fmt.Sprintf(" TIMEQUANTUM '%s'", f.Options.TimeQuantum)
-			if f.Options.TTL > 0 {
-				sql += fmt.Sprintf(" TTL '%s'", f.Options.TTL)
-			}
-		}
+		sql += f.timeQuantumConstraint()
 	case BaseTypeTimestamp:
 		if f.Options.TimeUnit != "" {
 			sql += fmt.Sprintf(" TIMEUNIT '%s'", f.Options.TimeUnit)
 		}
+	case BaseTypeJSON:
+		if len(f.Options.JSONPaths) > 0 {
+			paths := make([]string, len(f.Options.JSONPaths))
+			for i, p := range f.Options.JSONPaths {
+				paths[i] = "'" + strings.ReplaceAll(p, "'", "''") + "'"
+			}
+			sql += fmt.Sprintf(" INDEX PATHS (%s)", strings.Join(paths, ", "))
+		}
+	}
+
+	if f.Options.NotNull {
+		sql += " NOT NULL"
+	}
+	if f.Options.Default != "" {
+		sql += " DEFAULT " + f.Options.Default
 	}
+	if f.Options.Check != "" {
+		sql += fmt.Sprintf(" CHECK (%s)", f.Options.Check)
+	}
+
+	return sql
+}
 
+func (f *Field) timeQuantumConstraint() string {
+	if f.Options.TimeQuantum == "" {
+		return ""
+	}
+	sql := fmt.Sprintf(" TIMEQUANTUM '%s'", f.Options.TimeQuantum)
+	if f.Options.TTL > 0 {
+		sql += fmt.Sprintf(" TTL '%s'", f.Options.TTL)
+	}
 	return sql
 }
 
@@ -824,4 +896,9 @@ type FieldOptions struct {
 	TTL            time.Duration `json:"ttl,omitempty"`
 	ForeignIndex   string        `json:"foreign-index,omitempty"`
 	TrackExistence bool          `json:"track-existence"`
+	JSONPaths      []string      `json:"json-paths,omitempty"`
+	Width          int64         `json:"width,omitempty"`
+	NotNull        bool          `json:"not-null,omitempty"`
+	Default        string        `json:"default,omitempty"`
+	Check          string        `json:"check,omitempty"`
 }
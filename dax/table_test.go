@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/featurebasedb/featurebase/v3/dax"
 	"github.com/featurebasedb/featurebase/v3/pql"
@@ -198,6 +199,48 @@ func TestTable(t *testing.T) {
 				},
 				expSQL: "CREATE TABLE all_field_types_with_options (_id string, an_id id CACHETYPE ranked SIZE 500, a_string string CACHETYPE ranked SIZE 500, an_id_set idset CACHETYPE ranked SIZE 500, a_string_set stringset CACHETYPE ranked SIZE 500, an_int int MIN -100 MAX 200, a_decimal decimal, a_timestamp timestamp TIMEUNIT 's') KEYPARTITIONS 0",
 			},
+			{
+				tbl: dax.Table{
+					Name: "retained",
+					Fields: []*dax.Field{
+						{
+							Name: "_id",
+							Type: "id",
+						},
+						{
+							Name: "ts",
+							Type: "timestamp",
+							Options: dax.FieldOptions{
+								TimeUnit: "s",
+							},
+						},
+					},
+					RetentionField:  "ts",
+					RetentionPeriod: 2160 * time.Hour,
+				},
+				expSQL: "CREATE TABLE retained (_id id, ts timestamp TIMEUNIT 's') KEYPARTITIONS 0 RETENTION ts TTL '2160h0m0s'",
+			},
+			{
+				tbl: dax.Table{
+					Name: "composite",
+					Fields: []*dax.Field{
+						{
+							Name: "_id",
+							Type: "string",
+						},
+						{
+							Name: "region",
+							Type: "string",
+						},
+						{
+							Name: "num",
+							Type: "id",
+						},
+					},
+					PrimaryKey: []dax.FieldName{"region", "num"},
+				},
+				expSQL: "CREATE TABLE composite (_id string, region string, num id, PRIMARY KEY (region, num)) KEYPARTITIONS 0",
+			},
 		}
 		for i, test := range tests {
 			t.Run(fmt.Sprintf("test-%d", i), func(t *testing.T) {
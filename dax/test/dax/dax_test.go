@@ -153,6 +153,7 @@ func TestDAXIntegration(t *testing.T) {
 			"time_quantum_insert/stringset-rangeq", // orchestrator currently does not support to,from args on Rows()
 			"time_quantum_insert/idset-rangeq",
 			"select-having/string", // fails in DAX because the string isn't translated.
+			"alterTableColumns",    // DAX can't rename or alter columns.
 		}
 
 		doSkip := func(name string) bool {
@@ -48,6 +48,7 @@ type DBWrapper interface {
 	Close() error
 	DeleteFragment(index, field, view string, shard uint64, frag interface{}) error
 	DeleteField(index, field, fieldPath string) error
+	RenameField(index, field, newName string) error
 	OpenListString() string
 	Path() string
 	HasData() (has bool, err error)
@@ -331,6 +332,24 @@ func (per *DBPerShard) DeleteFieldFromStore(index, field, fieldPath string) (err
 	return err
 }
 
+// RenameFieldInStore renames the bitmaps of a field in every shard of the
+// index, leaving their contents in place.
+func (per *DBPerShard) RenameFieldInStore(index, field, newName string) (err error) {
+	per.Mu.Lock()
+	defer per.Mu.Unlock()
+
+	dbi, ok := per.dbh.Index[index]
+	if !ok {
+		return nil
+	}
+	for _, dbs := range dbi.Shard {
+		if e := dbs.W.RenameField(index, field, newName); e != nil && err == nil {
+			err = errors.Wrap(e, "RenameFieldInStore()")
+		}
+	}
+	return err
+}
+
 func (per *DBPerShard) DeleteFragment(index, field, view string, shard uint64, frag *fragment) error {
 
 	idx := per.txf.holder.Index(index)
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sort"
 	"time"
 
 	"github.com/apache/arrow/go/v10/arrow"
@@ -98,6 +99,14 @@ func (s Serializer) Unmarshal(buf []byte, m pilosa.Message) error {
 		}
 		s.decodeDeleteFieldMessage(msg, mt)
 		return nil
+	case *pilosa.RenameFieldMessage:
+		msg := &pb.RenameFieldMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling RenameFieldMessage")
+		}
+		s.decodeRenameFieldMessage(msg, mt)
+		return nil
 	case *pilosa.DeleteAvailableShardMessage:
 		msg := &pb.DeleteAvailableShardMessage{}
 		err := proto.Unmarshal(buf, msg)
@@ -325,6 +334,8 @@ func (s Serializer) encodeToProto(m pilosa.Message) proto.Message {
 		return s.encodeUpdateFieldMessage(mt)
 	case *pilosa.DeleteFieldMessage:
 		return s.encodeDeleteFieldMessage(mt)
+	case *pilosa.RenameFieldMessage:
+		return s.encodeRenameFieldMessage(mt)
 	case *pilosa.DeleteAvailableShardMessage:
 		return s.encodeDeleteAvailableShardMessage(mt)
 	case *pilosa.CreateViewMessage:
@@ -463,8 +474,28 @@ func (s Serializer) encodeImportRoaringShardRequest(m *pilosa.ImportRoaringShard
 		views[i] = s.encodeRoaringUpdate(view)
 	}
 	return &pb.ImportRoaringShardRequest{
-		Remote: m.Remote,
-		Views:  views,
+		Remote:     m.Remote,
+		Views:      views,
+		Checkpoint: s.encodeIngestCheckpoint(m.Checkpoint),
+	}
+}
+
+func (s Serializer) encodeIngestCheckpoint(m *pilosa.IngestCheckpoint) *pb.IngestCheckpoint {
+	if m == nil {
+		return nil
+	}
+	sources := make([]string, 0, len(m.Offsets))
+	for source := range m.Offsets {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	offsets := make([]*pb.IngestOffset, len(sources))
+	for i, source := range sources {
+		offsets[i] = &pb.IngestOffset{Source: source, Offset: m.Offsets[source], Position: m.Positions[source]}
+	}
+	return &pb.IngestCheckpoint{
+		Key:     m.Key,
+		Offsets: offsets,
 	}
 }
 
@@ -638,6 +669,13 @@ func (s Serializer) encodeFieldOptions(o *pilosa.FieldOptions) *pb.FieldOptions
 		ForeignIndex:   o.ForeignIndex,
 		NoStandardView: o.NoStandardView,
 		TrackExistence: o.TrackExistence,
+		JSON:           o.JSON,
+		JSONPaths:      o.JSONPaths,
+		GeoPoint:       o.GeoPoint,
+		BitVector:      o.BitVector,
+		NotNull:        o.NotNull,
+		Default:        o.Default,
+		Check:          o.Check,
 	}
 }
 
@@ -696,11 +734,17 @@ func (s Serializer) encodeCreateIndexMessage(m *pilosa.CreateIndexMessage) *pb.C
 }
 
 func (s Serializer) encodeIndexMeta(m *pilosa.IndexOptions) *pb.IndexMeta {
-	return &pb.IndexMeta{
+	pbm := &pb.IndexMeta{
 		Description:    m.Description,
 		Keys:           m.Keys,
 		TrackExistence: m.TrackExistence,
+		RetentionField: m.RetentionField,
+		PrimaryKey:     m.PrimaryKey,
 	}
+	if m.RetentionPeriod > 0 {
+		pbm.RetentionPeriod = m.RetentionPeriod.String()
+	}
+	return pbm
 }
 
 func (s Serializer) encodeDeleteIndexMessage(m *pilosa.DeleteIndexMessage) *pb.DeleteIndexMessage {
@@ -740,6 +784,14 @@ func (s Serializer) encodeDeleteFieldMessage(m *pilosa.DeleteFieldMessage) *pb.D
 	}
 }
 
+func (s Serializer) encodeRenameFieldMessage(m *pilosa.RenameFieldMessage) *pb.RenameFieldMessage {
+	return &pb.RenameFieldMessage{
+		Index:   m.Index,
+		Field:   m.Field,
+		NewName: m.NewName,
+	}
+}
+
 func (s Serializer) encodeDeleteAvailableShardMessage(m *pilosa.DeleteAvailableShardMessage) *pb.DeleteAvailableShardMessage {
 	return &pb.DeleteAvailableShardMessage{
 		Index:   m.Index,
@@ -975,6 +1027,13 @@ func (s Serializer) decodeFieldOptions(options *pb.FieldOptions, m *pilosa.Field
 	m.ForeignIndex = options.ForeignIndex
 	m.NoStandardView = options.NoStandardView
 	m.TrackExistence = options.TrackExistence
+	m.JSON = options.JSON
+	m.JSONPaths = options.JSONPaths
+	m.GeoPoint = options.GeoPoint
+	m.BitVector = options.BitVector
+	m.NotNull = options.NotNull
+	m.Default = options.Default
+	m.Check = options.Check
 }
 
 func (s Serializer) decodeDecimal(d *pb.Decimal, m *pql.Decimal) {
@@ -1044,6 +1103,12 @@ func (s Serializer) decodeIndexMeta(pb *pb.IndexMeta, m *pilosa.IndexOptions) {
 		m.Description = pb.Description
 		m.Keys = pb.Keys
 		m.TrackExistence = pb.TrackExistence
+		m.RetentionField = pb.RetentionField
+		m.PrimaryKey = pb.PrimaryKey
+		if pb.RetentionPeriod != "" {
+			// The period was written by encodeIndexMeta, so it always parses.
+			m.RetentionPeriod, _ = time.ParseDuration(pb.RetentionPeriod)
+		}
 	}
 }
 
@@ -1075,6 +1140,12 @@ func (s Serializer) decodeDeleteFieldMessage(pb *pb.DeleteFieldMessage, m *pilos
 	m.Field = pb.Field
 }
 
+func (s Serializer) decodeRenameFieldMessage(pb *pb.RenameFieldMessage, m *pilosa.RenameFieldMessage) {
+	m.Index = pb.Index
+	m.Field = pb.Field
+	m.NewName = pb.NewName
+}
+
 func (s Serializer) decodeDeleteAvailableShardMessage(pb *pb.DeleteAvailableShardMessage, m *pilosa.DeleteAvailableShardMessage) {
 	m.Index = pb.Index
 	m.Field = pb.Field
@@ -1208,6 +1279,24 @@ func (s Serializer) decodeImportRoaringShardRequest(pb *pb.ImportRoaringShardReq
 		s.decodeRoaringUpdate(viewUpdate, pru)
 		m.Views = append(m.Views, *pru)
 	}
+	if pb.Checkpoint != nil {
+		m.Checkpoint = &pilosa.IngestCheckpoint{}
+		s.decodeIngestCheckpoint(pb.Checkpoint, m.Checkpoint)
+	}
+}
+
+func (s Serializer) decodeIngestCheckpoint(pb *pb.IngestCheckpoint, m *pilosa.IngestCheckpoint) {
+	m.Key = pb.Key
+	m.Offsets = make(map[string]uint64, len(pb.Offsets))
+	for _, offset := range pb.Offsets {
+		m.Offsets[offset.Source] = offset.Offset
+		if offset.Position != "" {
+			if m.Positions == nil {
+				m.Positions = make(map[string]string)
+			}
+			m.Positions[offset.Source] = offset.Position
+		}
+	}
 }
 
 func (s Serializer) decodeRoaringUpdate(pb *pb.RoaringUpdate, m *pilosa.RoaringUpdate) {
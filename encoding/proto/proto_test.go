@@ -5,6 +5,7 @@ package proto
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/apache/arrow/go/v10/arrow"
 	"github.com/apache/arrow/go/v10/arrow/array"
@@ -68,6 +69,46 @@ func TestEncodeDecodeDistinctTimestamp(t *testing.T) {
 	}
 }
 
+func TestCreateIndexMessageRetention(t *testing.T) {
+	s := Serializer{}
+	testOneRoundTrip(t, s, &pilosa.CreateIndexMessage{
+		Index:     "i",
+		CreatedAt: 1,
+		Meta: pilosa.IndexOptions{
+			Keys:            true,
+			TrackExistence:  true,
+			RetentionField:  "ts",
+			RetentionPeriod: 90 * 24 * time.Hour,
+		},
+	}, nil, nil, nil)
+}
+
+func TestCreateIndexMessagePrimaryKey(t *testing.T) {
+	s := Serializer{}
+	testOneRoundTrip(t, s, &pilosa.CreateIndexMessage{
+		Index:     "i",
+		CreatedAt: 1,
+		Meta: pilosa.IndexOptions{
+			Keys:           true,
+			TrackExistence: true,
+			PrimaryKey:     []string{"region", "num"},
+		},
+	}, nil, nil, nil)
+}
+
+func TestImportRoaringShardRequestCheckpoint(t *testing.T) {
+	s := Serializer{}
+	testOneRoundTrip(t, s, &pilosa.ImportRoaringShardRequest{
+		Remote: true,
+		Views:  []pilosa.RoaringUpdate{{Field: "f", View: "standard", Clear: []byte{1}, Set: []byte{2}}},
+		Checkpoint: &pilosa.IngestCheckpoint{
+			Key:       "orders",
+			Offsets:   map[string]uint64{"orders:0": 10, "orders:1": 0},
+			Positions: map[string]string{"orders:0": "4,2,mysql-bin.000003"},
+		},
+	}, nil, nil, nil)
+}
+
 func TestDecodeQueryResult(t *testing.T) {
 	t.Run("DistinctTimestamp", func(t *testing.T) {
 		pbTime := pb.DistinctTimestamp{
@@ -21,6 +21,7 @@ import (
 	"github.com/featurebasedb/featurebase/v3/disco"
 	"github.com/featurebasedb/featurebase/v3/pql"
 	"github.com/featurebasedb/featurebase/v3/proto"
+	"github.com/featurebasedb/featurebase/v3/querycontext"
 	"github.com/featurebasedb/featurebase/v3/roaring"
 	"github.com/featurebasedb/featurebase/v3/shardwidth"
 	"github.com/featurebasedb/featurebase/v3/task"
@@ -51,6 +52,16 @@ type Executor interface {
 	Execute(context.Context, dax.TableKeyer, *pql.Query, []uint64, *ExecOptions) (QueryResponse, error)
 }
 
+// IndexLocker is implemented by executors which can hold off queries
+// against a set of indexes. It is used to make a group of writes, such as
+// the statements of a sql transaction, visible to queries all at once.
+type IndexLocker interface {
+	// LockIndexes waits for queries running against any of the given
+	// indexes to finish, then prevents new ones from starting until the
+	// returned release function is called.
+	LockIndexes(ctx context.Context, indexes ...string) (release func(), err error)
+}
+
 // executor recursively executes calls in a PQL query across all shards.
 type executor struct {
 	Holder *Holder
@@ -80,8 +91,19 @@ type executor struct {
 	// Temporary flag to be removed when stablized
 	dataframeEnabled   bool
 	datafameUseParquet bool
+
+	// scopes keeps queries from running against indexes that are
+	// locked by LockIndexes.
+	scopes *querycontext.ScopeLock
+
+	// holds are the locks taken by HoldIndexes, by their IDs.
+	holdMu sync.Mutex
+	holds  map[string]func()
 }
 
+// Ensure type implements interface.
+var _ IndexLocker = (*executor)(nil)
+
 // executorOption is a functional option type for pilosa.executor
 type executorOption func(e *executor) error
 
@@ -129,6 +151,8 @@ func newExecutor(opts ...executorOption) *executor {
 	e := &executor{
 		workerPoolSize: 2,
 		shutdown:       make(chan struct{}),
+		scopes:         querycontext.NewScopeLock(nil),
+		holds:          make(map[string]func()),
 	}
 	for _, opt := range opts {
 		err := opt(e)
@@ -146,6 +170,51 @@ func newExecutor(opts ...executorOption) *executor {
 	return e
 }
 
+// LockIndexes implements IndexLocker.
+func (e *executor) LockIndexes(ctx context.Context, indexes ...string) (func(), error) {
+	scope := e.scopes.Scope()
+	for _, index := range indexes {
+		scope.AddIndex(querycontext.IndexName(index))
+	}
+	return e.scopes.Lock(ctx, scope)
+}
+
+// HoldIndexes locks the given indexes like LockIndexes, but until
+// ReleaseIndexes is called with the same id, or timeout has passed, so
+// that a lock can be held on behalf of another node.
+func (e *executor) HoldIndexes(ctx context.Context, id string, indexes []string, timeout time.Duration) error {
+	release, err := e.LockIndexes(ctx, indexes...)
+	if err != nil {
+		return err
+	}
+	var once sync.Once
+	release = func(release func()) func() {
+		return func() { once.Do(release) }
+	}(release)
+
+	e.holdMu.Lock()
+	defer e.holdMu.Unlock()
+	if _, ok := e.holds[id]; ok {
+		release()
+		return errors.Errorf("indexes are already held for %s", id)
+	}
+	e.holds[id] = release
+	time.AfterFunc(timeout, func() { e.ReleaseIndexes(id) })
+	return nil
+}
+
+// ReleaseIndexes releases the indexes held by HoldIndexes for id, if
+// they're still held.
+func (e *executor) ReleaseIndexes(id string) {
+	e.holdMu.Lock()
+	release, ok := e.holds[id]
+	delete(e.holds, id)
+	e.holdMu.Unlock()
+	if ok {
+		release()
+	}
+}
+
 func (e *executor) Close() error {
 	select {
 	case <-e.shutdown:
@@ -204,6 +273,32 @@ func (e *executor) Execute(ctx context.Context, tableKeyer dax.TableKeyer, q *pq
 		return resp, newNotFoundError(ErrIndexNotFound, index)
 	}
 
+	// Default options.
+	if opt == nil {
+		opt = &ExecOptions{}
+	}
+
+	// Don't run while the index is locked by LockIndexes, so we never
+	// observe part of a group of writes. Only the node coordinating a query
+	// waits: a group of writes is applied once the index is locked on every
+	// node, and a query which got in first holds its coordinator's lock
+	// until its remote calls have all returned, so they see the data from
+	// before the writes.
+	if e.scopes != nil && !opt.Remote {
+		release, err := e.scopes.RLock(ctx, e.scopes.Scope().AddIndex(querycontext.IndexName(index)))
+		if err != nil {
+			return resp, err
+		}
+		defer release()
+	}
+
+	// Writes to a field which is being altered are refused.
+	for _, c := range q.Calls {
+		if err := idx.checkCallWritable(c); err != nil {
+			return resp, err
+		}
+	}
+
 	needWriteTxn := false
 	nw := q.WriteCallN()
 	if nw > 0 {
@@ -214,11 +309,6 @@ func (e *executor) Execute(ctx context.Context, tableKeyer dax.TableKeyer, q *pq
 	if e.MaxWritesPerRequest > 0 && nw > e.MaxWritesPerRequest {
 		return resp, ErrTooManyWrites
 	}
-
-	// Default options.
-	if opt == nil {
-		opt = &ExecOptions{}
-	}
 	// Default maximum memory, if not passed in.
 	if opt.MaxMemory == 0 && q.HasCall("Extract") {
 		opt.MaxMemory = e.maxMemory
@@ -541,6 +631,7 @@ func (e *executor) execute(ctx context.Context, qcx *Qcx, index string, q *pql.Q
 		}
 
 		lastWasWrite = call.IsWrite()
+		addShardsScanned(ctx, len(shards))
 
 		if err := validateQueryContext(ctx); err != nil {
 			return nil, err
@@ -768,6 +859,10 @@ func (e *executor) executeCall(ctx context.Context, qcx *Qcx, index string, c *p
 		statFn(CounterQueryTopKTotal)
 		res, err := e.executeTopK(ctx, qcx, index, c, shards, opt)
 		return res, errors.Wrap(err, "executeTopK")
+	case "Nearest":
+		statFn(CounterQueryNearestTotal)
+		res, err := e.executeNearest(ctx, qcx, index, c, shards, opt)
+		return res, errors.Wrap(err, "executeNearest")
 	case "TopN":
 		statFn(CounterQueryTopNTotal)
 		res, err := e.executeTopN(ctx, qcx, index, c, shards, opt)
@@ -1018,6 +1113,9 @@ func (e *executor) executeFieldValueCallShard(ctx context.Context, qcx *Qcx, fie
 			return ValCount{}, err
 		}
 		other.TimestampVal = ts
+	} else if field.Type() == FieldTypeFloat {
+		other.Val = value
+		other.FloatVal = ValToFloat(value)
 	}
 
 	return other, nil
@@ -1035,6 +1133,10 @@ func (e *executor) executeLimitCall(ctx context.Context, qcx *Qcx, index string,
 	if err != nil {
 		return nil, errors.Wrap(err, "getting offset")
 	}
+	after, hasAfter, err := c.UintArg("after")
+	if err != nil {
+		return nil, errors.Wrap(err, "getting after")
+	}
 
 	if !hasLimit {
 		limit = math.MaxUint64
@@ -1054,6 +1156,22 @@ func (e *executor) executeLimitCall(ctx context.Context, qcx *Qcx, index string,
 		return nil, errors.Errorf("expected Row but got %T", result)
 	}
 
+	// only the columns after the one given are kept, so that a page can be
+	// read from where the last one ended without counting the columns before it
+	if hasAfter {
+		i := 0
+		var leadingBits []uint64
+		for i < len(result.Segments) && result.Segments[i].Shard() <= after/ShardWidth {
+			if seg := result.Segments[i]; seg.Shard() == after/ShardWidth {
+				data := seg.Columns()
+				leadingBits = data[sort.Search(len(data), func(j int) bool { return data[j] > after }):]
+			}
+			i++
+		}
+		row := NewRow(leadingBits...)
+		row.Merge(&Row{Segments: result.Segments[i:]})
+		result = row
+	}
 	if offset != 0 {
 		i := 0
 		var leadingBits []uint64
@@ -1139,6 +1257,17 @@ func (e *executor) executeSum(ctx context.Context, qcx *Qcx, index string, c *pq
 		other, _ := prev.(ValCount)
 		return other.Add(v.(ValCount))
 	}
+	if field := e.Holder.Field(index, fieldName); field != nil && field.Type() == FieldTypeFloat {
+		// the shard sums of a float field are in FloatVal
+		reduceFn = func(ctx context.Context, prev, v interface{}) interface{} {
+			other, _ := prev.(ValCount)
+			vc := v.(ValCount)
+			return ValCount{
+				FloatVal: other.FloatVal + vc.FloatVal,
+				Count:    other.Count + vc.Count,
+			}
+		}
+	}
 
 	result, err := e.mapReduce(ctx, index, shards, c, opt, mapFn, reduceFn)
 	if err != nil {
@@ -1488,6 +1617,23 @@ func (e *executor) executePercentile(ctx context.Context, qcx *Qcx, index string
 		minValueOver = func(v interface{}) {
 			min = pql.AddDecimal(v.(pql.Decimal), one)
 		}
+	} else if field.options.Type == FieldTypeFloat {
+		// float values are searched for in their (order-preserving) integer
+		// encoding, and converted back for the range queries
+		min := FloatToVal(minVal.FloatVal)
+		max := FloatToVal(maxVal.FloatVal)
+		averageMinMax = func() interface{} {
+			return ValToFloat((min / 2) + (max / 2) + (((min % 2) + (max % 2)) / 2))
+		}
+		minLessthanMax = func() bool {
+			return min < max
+		}
+		maxValueUnder = func(v interface{}) {
+			max = FloatToVal(v.(float64)) - 1
+		}
+		minValueOver = func(v interface{}) {
+			min = FloatToVal(v.(float64)) + 1
+		}
 	} else {
 		// plain BSI field
 		min := minVal.Val
@@ -1546,6 +1692,8 @@ func (e *executor) executePercentile(ctx context.Context, qcx *Qcx, index string
 	var possibleNthVal interface{}
 	if minVal.DecimalVal != nil {
 		possibleNthVal = minVal.DecimalVal
+	} else if field.options.Type == FieldTypeFloat {
+		possibleNthVal = minVal.FloatVal
 	} else {
 		possibleNthVal = minVal.Val
 	}
@@ -1595,6 +1743,12 @@ func (e *executor) executePercentile(ctx context.Context, qcx *Qcx, index string
 			FloatVal:   v.Float64(),
 			Count:      1,
 		}, nil
+	case float64:
+		return ValCount{
+			Val:      FloatToVal(v),
+			FloatVal: v,
+			Count:    1,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unexpected percentile Nth value type %T", possibleNthVal)
 	}
@@ -2185,12 +2339,23 @@ func (e *executor) executeSumCountShard(ctx context.Context, qcx *Qcx, index str
 		return ValCount{}, nil
 	}
 
-	fragment := e.Holder.fragment(index, fieldName, viewBSIGroupPrefix+fieldName, shard)
-	if fragment == nil {
+	views, err := e.bsiViewsForCall(field, c)
+	if err != nil {
+		return ValCount{}, err
+	}
+
+	// A time range may cover several views, whose sums are added together.
+	fragments := make([]*fragment, 0, len(views))
+	for _, view := range views {
+		if fragment := e.Holder.fragment(index, fieldName, view, shard); fragment != nil {
+			fragments = append(fragments, fragment)
+		}
+	}
+	if len(fragments) == 0 {
 		return ValCount{}, nil
 	}
 
-	tx, finisher, err := qcx.GetTx(Txo{Write: !writable, Index: idx, Fragment: fragment, Shard: shard})
+	tx, finisher, err := qcx.GetTx(Txo{Write: !writable, Index: idx, Fragment: fragments[0], Shard: shard})
 	if err != nil {
 		return ValCount{}, err
 	}
@@ -2198,22 +2363,63 @@ func (e *executor) executeSumCountShard(ctx context.Context, qcx *Qcx, index str
 
 	sumspan, _ := tracing.StartSpanFromContext(ctx, "executor.executeSumCountShard_fragment.sum")
 	defer sumspan.Finish()
-	vsum, vcount, err := fragment.sum(tx, filter, bsig.BitDepth)
-	if err != nil {
-		return ValCount{}, errors.Wrap(err, "computing sum")
+	if field.Type() == FieldTypeFloat {
+		var out ValCount
+		for _, fragment := range fragments {
+			fsum, fcount, err := fragment.floatSum(tx, filter, bsig.BitDepth)
+			if err != nil {
+				return ValCount{}, errors.Wrap(err, "computing float sum")
+			}
+			out.FloatVal += fsum
+			out.Count += int64(fcount)
+		}
+		return out, nil
+	}
+	var total, count int64
+	for _, fragment := range fragments {
+		vsum, vcount, err := fragment.sum(tx, filter, bsig.BitDepth)
+		if err != nil {
+			return ValCount{}, errors.Wrap(err, "computing sum")
+		}
+		total += int64(vsum) + (int64(vcount) * bsig.Base)
+		count += int64(vcount)
 	}
 	out := ValCount{
-		Val:   int64(vsum) + (int64(vcount) * bsig.Base),
-		Count: int64(vcount),
+		Val:   total,
+		Count: count,
 	}
 	if field.Type() == FieldTypeDecimal {
-		out.FloatVal = float64(int64(vsum)+(int64(vcount)*bsig.Base)) / math.Pow(10, float64(bsig.Scale))
-		dec := pql.NewDecimal((int64(vsum) + (int64(vcount) * bsig.Base)), bsig.Scale)
+		out.FloatVal = float64(total) / math.Pow(10, float64(bsig.Scale))
+		dec := pql.NewDecimal(total, bsig.Scale)
 		out.DecimalVal = &dec
 	}
 	return out, nil
 }
 
+// bsiViewsForCall returns the views which a call on the BSI field f reads.
+// That is the field's BSI view, unless the call has "from" or "to"
+// arguments, in which case it is the field's time views covering that range.
+func (e *executor) bsiViewsForCall(f *Field, c *pql.Call) (_ []string, err error) {
+	fromArg, hasFrom := c.Args["from"]
+	toArg, hasTo := c.Args["to"]
+	if !hasFrom && !hasTo {
+		return []string{viewBSIGroupPrefix + f.name}, nil
+	}
+
+	var fromTime, toTime time.Time
+	if hasFrom {
+		if fromTime, err = parseTime(fromArg); err != nil {
+			return nil, errors.Wrap(err, "parsing from time")
+		}
+	}
+	if hasTo {
+		if toTime, err = parseTime(toArg); err != nil {
+			return nil, errors.Wrap(err, "parsing to time")
+		}
+	}
+	return f.viewsByTimeRange(fromTime, toTime)
+}
+
 // executeMinShard calculates the min for bsiGroups on a shard.
 func (e *executor) executeMinShard(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shard uint64) (_ ValCount, err0 error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeMinShard")
@@ -2237,7 +2443,24 @@ func (e *executor) executeMinShard(ctx context.Context, qcx *Qcx, index string,
 	if field == nil {
 		return ValCount{}, ErrFieldNotFound
 	}
-	return field.MinForShard(qcx, shard, filter)
+
+	views, err := e.bsiViewsForCall(field, c)
+	if err != nil {
+		return ValCount{}, err
+	}
+	var out ValCount
+	for i, view := range views {
+		vc, err := field.MinForShardView(qcx, shard, view, filter)
+		if err != nil {
+			return ValCount{}, err
+		}
+		if i == 0 {
+			out = vc
+		} else {
+			out = out.Smaller(vc)
+		}
+	}
+	return out, nil
 }
 
 // executeMaxShard calculates the max for bsiGroups on a shard.
@@ -2263,7 +2486,24 @@ func (e *executor) executeMaxShard(ctx context.Context, qcx *Qcx, index string,
 	if field == nil {
 		return ValCount{}, ErrFieldNotFound
 	}
-	return field.MaxForShard(qcx, shard, filter)
+
+	views, err := e.bsiViewsForCall(field, c)
+	if err != nil {
+		return ValCount{}, err
+	}
+	var out ValCount
+	for i, view := range views {
+		vc, err := field.MaxForShardView(qcx, shard, view, filter)
+		if err != nil {
+			return ValCount{}, err
+		}
+		if i == 0 {
+			out = vc
+		} else {
+			out = out.Larger(vc)
+		}
+	}
+	return out, nil
 }
 
 // executeMinRowShard returns the minimum row ID for a shard.
@@ -2566,6 +2806,230 @@ func topKFragments(ctx context.Context, tx Tx, filter *Row, fragments ...*fragme
 	return doTopK(ctx, it, filterData)
 }
 
+// executeNearest executes a Nearest() call, returning the k records whose
+// vectors in a bitvector field are the fewest bits away from a given vector.
+func (e *executor) executeNearest(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shards []uint64, opt *ExecOptions) (interface{}, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeNearest")
+	defer span.Finish()
+
+	fieldName, err := c.FirstStringArg("field", "_field")
+	if err != nil {
+		return nil, errors.New("Nearest(): field required")
+	}
+	k, hasK, err := c.UintArg("k")
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching k")
+	} else if !hasK {
+		return nil, errors.New("Nearest(): k required")
+	}
+
+	mapFn := func(ctx context.Context, shard uint64, mopt *mapOptions) (_ interface{}, err error) {
+		pairs, err := e.executeNearestShard(ctx, qcx, index, c, shard)
+		if pairs == nil {
+			return nil, err
+		}
+		return pairs, err
+	}
+
+	// Each shard returns its nearest records ordered by distance, so the
+	// closest k overall are among the closest k of every shard.
+	reduceFn := func(ctx context.Context, prev, v interface{}) interface{} {
+		x, _ := prev.(*PairsField)
+		y, _ := v.(*PairsField)
+		if x == nil {
+			return y
+		} else if y == nil {
+			return x
+		}
+		return &PairsField{
+			Pairs: mergeNearestPairs(x.Pairs, y.Pairs, k),
+			Field: fieldName,
+		}
+	}
+
+	result, err := e.mapReduce(ctx, index, shards, c, opt, mapFn, reduceFn)
+	if err != nil {
+		return nil, err
+	}
+	pairs, _ := result.(*PairsField)
+	if pairs == nil {
+		pairs = &PairsField{Pairs: []Pair{}, Field: fieldName}
+	}
+	return pairs, nil
+}
+
+// executeNearestShard returns the nearest records within a shard as pairs of
+// column and distance.
+//
+// Rather than reading each vector, the distance of every candidate record is
+// built up as a bit-sliced integer: for each bit of the query vector, the
+// records which differ in that bit are added to the sliced sum. The k smallest
+// sums are then selected from the slices, highest bit first.
+func (e *executor) executeNearestShard(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shard uint64) (_ *PairsField, err0 error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeNearestShard")
+	defer span.Finish()
+
+	idx := e.Holder.Index(index)
+	if idx == nil {
+		return nil, newNotFoundError(ErrIndexNotFound, index)
+	}
+	fieldName, err := c.FirstStringArg("field", "_field")
+	if err != nil {
+		return nil, errors.New("Nearest(): field required")
+	}
+	f := idx.Field(fieldName)
+	if f == nil {
+		return nil, newNotFoundError(ErrFieldNotFound, fieldName)
+	}
+	width := f.Options().BitVector
+	if width <= 0 {
+		return nil, errors.Errorf("Nearest(): field '%s' is not a bitvector field", fieldName)
+	}
+
+	vector, ok, err := c.StringArg("vector")
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching vector")
+	} else if !ok {
+		return nil, errors.New("Nearest(): vector required")
+	}
+	positions, err := ParseBitVector(vector, width)
+	if err != nil {
+		return nil, errors.Wrap(err, "Nearest()")
+	}
+	query := make([]bool, width)
+	for _, pos := range positions {
+		query[pos] = true
+	}
+
+	k, _, err := c.UintArg("k")
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching k")
+	}
+
+	var filterRow *Row
+	if filter, hasFilter, err := c.CallArg("filter"); err != nil {
+		return nil, err
+	} else if hasFilter {
+		if filterRow, err = e.executeBitmapCallShard(ctx, qcx, index, filter, shard); err != nil {
+			return nil, err
+		}
+	}
+
+	frag := e.Holder.fragment(index, fieldName, viewStandard, shard)
+	if frag == nil || k == 0 {
+		return nil, nil
+	}
+	tx, finisher, err := qcx.GetTx(Txo{Write: !writable, Fragment: frag, Index: idx, Shard: shard})
+	if err != nil {
+		return nil, err
+	}
+	defer finisher(&err0)
+
+	// The row past the last bit holds every record with a vector.
+	candidates, err := frag.row(tx, uint64(width))
+	if err != nil {
+		return nil, err
+	}
+	if filterRow != nil {
+		candidates = candidates.Intersect(filterRow)
+	}
+	if !candidates.Any() {
+		return nil, nil
+	}
+
+	var slices []*Row
+	for i := int64(0); i < width; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		plane, err := frag.row(tx, uint64(i))
+		if err != nil {
+			return nil, err
+		}
+		var diff *Row
+		if query[i] {
+			diff = candidates.Difference(plane)
+		} else {
+			diff = candidates.Intersect(plane)
+		}
+		// ripple the differing records up through the slices
+		for j := 0; diff.Any(); j++ {
+			if j == len(slices) {
+				slices = append(slices, diff)
+				break
+			}
+			carry := slices[j].Intersect(diff)
+			slices[j] = slices[j].Xor(diff)
+			diff = carry
+		}
+	}
+
+	// Narrow down to the records with the smallest distances. Records in
+	// nearer are certainly among the k nearest; those left in tied all share
+	// the same distance.
+	nearer, tied := NewRow(), candidates
+	if candidates.Count() > k {
+		for j := len(slices) - 1; j >= 0; j-- {
+			zero := tied.Difference(slices[j])
+			if n := nearer.Count() + zero.Count(); n >= k {
+				tied = zero
+			} else {
+				nearer = nearer.Union(zero)
+				tied = tied.Intersect(slices[j])
+			}
+		}
+	}
+
+	columns := nearer.Columns()
+	for _, col := range tied.Columns() {
+		if uint64(len(columns)) >= k {
+			break
+		}
+		columns = append(columns, col)
+	}
+
+	pairs := make([]Pair, len(columns))
+	for i, col := range columns {
+		var distance uint64
+		for j, slice := range slices {
+			if slice.Includes(col) {
+				distance |= 1 << uint(j)
+			}
+		}
+		pairs[i] = Pair{ID: col, Count: distance}
+	}
+	sortNearestPairs(pairs)
+
+	return &PairsField{
+		Pairs: pairs,
+		Field: fieldName,
+	}, nil
+}
+
+// sortNearestPairs orders pairs of column and distance by distance, then by
+// column.
+func sortNearestPairs(pairs []Pair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Count != pairs[j].Count {
+			return pairs[i].Count < pairs[j].Count
+		}
+		return pairs[i].ID < pairs[j].ID
+	})
+}
+
+// mergeNearestPairs merges two lists of pairs ordered by sortNearestPairs,
+// keeping the first k.
+func mergeNearestPairs(x, y []Pair, k uint64) []Pair {
+	merged := make([]Pair, 0, len(x)+len(y))
+	merged = append(merged, x...)
+	merged = append(merged, y...)
+	sortNearestPairs(merged)
+	if uint64(len(merged)) > k {
+		merged = merged[:k]
+	}
+	return merged
+}
+
 // mergerate returns a container iterator that unions many container iterators.
 func mergerate(iters ...roaring.ContainerIterator) *mergerator {
 	iterStates := make([]mergeState, len(iters))
@@ -2874,7 +3338,7 @@ func (e *executor) executeTopNShard(ctx context.Context, qcx *Qcx, index string,
 	n, _, err := c.UintArg("n")
 	if err != nil {
 		return nil, fmt.Errorf("executeTopNShard: %v", err)
-	} else if f := e.Holder.Field(index, fieldName); f != nil && (f.Type() == FieldTypeInt || f.Type() == FieldTypeDecimal || f.Type() == FieldTypeTimestamp) {
+	} else if f := e.Holder.Field(index, fieldName); f != nil && (f.Type() == FieldTypeInt || f.Type() == FieldTypeDecimal || f.Type() == FieldTypeTimestamp || f.Type() == FieldTypeFloat) {
 		return nil, fmt.Errorf("cannot compute TopN() on integer, decimal, or timestamp field: %q", fieldName)
 	}
 
@@ -4966,7 +5430,7 @@ func (e *executor) executeExtractShard(ctx context.Context, qcx *Qcx, index stri
 				}
 			}
 
-		case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp:
+		case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp, FieldTypeFloat:
 			// Handle an int/decimal field by rotating a BSI matrix.
 
 			// Extract the BSI view fragment.
@@ -5250,10 +5714,16 @@ func (e *executor) executeRowBSIGroupShard(ctx context.Context, qcx *Qcx, fld *F
 	span, _ := tracing.StartSpanFromContext(ctx, "executor.executeRowBSIGroupShard")
 	defer span.Finish()
 
-	// Only one conditional should be present.
-	if len(c.Args) == 0 {
+	// Only one conditional should be present, besides a time range.
+	nargs := len(c.Args)
+	for _, arg := range []string{"from", "to"} {
+		if _, ok := c.Args[arg]; ok {
+			nargs--
+		}
+	}
+	if nargs == 0 {
 		return nil, errors.New("Row(): condition required")
-	} else if len(c.Args) > 1 {
+	} else if nargs > 1 {
 		return nil, errors.New("Row(): too many arguments")
 	}
 
@@ -5261,7 +5731,13 @@ func (e *executor) executeRowBSIGroupShard(ctx context.Context, qcx *Qcx, fld *F
 	if err != nil {
 		return nil, err
 	}
-	viewName := viewBSIGroupPrefix + fld.name
+	if op == pql.EQ && value == nil && nargs != len(c.Args) {
+		return nil, errors.New("can't use a time range with a check for null")
+	}
+	views, err := e.bsiViewsForCall(fld, c)
+	if err != nil {
+		return nil, err
+	}
 
 	tx, finisher, err := qcx.GetTx(Txo{Write: !writable, Index: fld.idx, Shard: shard})
 	if err != nil {
@@ -5274,6 +5750,25 @@ func (e *executor) executeRowBSIGroupShard(ctx context.Context, qcx *Qcx, fld *F
 		}
 	}()
 
+	if len(views) == 1 {
+		return e.executeRowBSIGroupViewShard(ctx, tx, fld, bsig, op, value, views[0], shard)
+	}
+
+	// Union the matching records across all time-based views.
+	row := NewRow()
+	for _, view := range views {
+		r, err := e.executeRowBSIGroupViewShard(ctx, tx, fld, bsig, op, value, view, shard)
+		if err != nil {
+			return nil, err
+		}
+		row = row.Union(r)
+	}
+	return row, nil
+}
+
+// executeRowBSIGroupViewShard executes a range(bsiGroup) condition against
+// one of the field's BSI views for a local shard.
+func (e *executor) executeRowBSIGroupViewShard(ctx context.Context, tx Tx, fld *Field, bsig *bsiGroup, op pql.Token, value interface{}, viewName string, shard uint64) (*Row, error) {
 	// EQ null           _exists - frag.NotNull()
 	// NEQ null          frag.NotNull()
 	// BETWEEN a,b(in)   BETWEEN/frag.RowBetween()
@@ -5605,7 +6100,23 @@ func (e *executor) executeConstRow(ctx context.Context, qcx *Qcx, index string,
 	idx := e.Holder.Index(index)
 	if idx == nil {
 		return nil, newNotFoundError(ErrIndexNotFound, index)
-	} else if idx.existenceField() == nil {
+	}
+	// A key prefix selects the records whose keys begin with it. They are
+	// found in the translate stores, and passed on as the columns list.
+	if prefix, ok, err := c.StringArg("keyPrefix"); err != nil {
+		return nil, errors.Wrap(err, "getting key prefix")
+	} else if ok && !opt.Remote {
+		if _, ok := c.Args["columns"]; ok {
+			return nil, errors.New("ConstRow takes either columns or keyPrefix")
+		}
+		ids, err := e.Cluster.matchIndexKeys(ctx, index, prefix)
+		if err != nil {
+			return nil, errors.Wrap(err, "matching key prefix")
+		}
+		delete(c.Args, "keyPrefix")
+		c.Args["columns"] = ids
+	}
+	if idx.existenceField() == nil {
 		ids, ok := c.Args["columns"].([]uint64)
 		if !ok {
 			return nil, errors.New("missing columns list")
@@ -5921,7 +6432,7 @@ func (e *executor) executeClearBit(ctx context.Context, qcx *Qcx, index string,
 	}
 
 	// BSI field
-	if f.Type() == FieldTypeInt || f.Type() == FieldTypeDecimal || f.Type() == FieldTypeTimestamp {
+	if f.Type() == FieldTypeInt || f.Type() == FieldTypeDecimal || f.Type() == FieldTypeTimestamp || f.Type() == FieldTypeFloat {
 		return e.executeClearValueField(ctx, qcx, index, c, f, colID, opt)
 	}
 
@@ -6228,7 +6739,7 @@ func (e *executor) executeSet(ctx context.Context, qcx *Qcx, index string, c *pq
 	}
 
 	switch f.Type() {
-	case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp:
+	case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp, FieldTypeFloat:
 		// Fetch field
 		v, ok := c.Arg(fieldName)
 		if !ok {
@@ -6249,7 +6760,17 @@ func (e *executor) executeSet(ctx context.Context, qcx *Qcx, index string, c *pq
 		if err != nil {
 			return false, fmt.Errorf("reading Set() row (int/decimal): %v", err)
 		}
-		return e.executeSetValueField(ctx, qcx, index, c, f, colID, rowVal, opt)
+
+		var timestamp *time.Time
+		sTimestamp, ok := c.Args["_timestamp"].(string)
+		if ok {
+			t, err := time.Parse(TimeFormat, sTimestamp)
+			if err != nil {
+				return false, fmt.Errorf("invalid date: %s", sTimestamp)
+			}
+			timestamp = &t
+		}
+		return e.executeSetValueField(ctx, qcx, index, c, f, colID, rowVal, timestamp, opt)
 
 	default:
 		// Read row ID.
@@ -6313,7 +6834,7 @@ func (e *executor) executeSetBitField(ctx context.Context, qcx *Qcx, index strin
 }
 
 // executeSetValueField executes a Set() call for a specific int field.
-func (e *executor) executeSetValueField(ctx context.Context, qcx *Qcx, index string, c *pql.Call, f *Field, colID uint64, value int64, opt *ExecOptions) (_ bool, err0 error) {
+func (e *executor) executeSetValueField(ctx context.Context, qcx *Qcx, index string, c *pql.Call, f *Field, colID uint64, value int64, timestamp *time.Time, opt *ExecOptions) (_ bool, err0 error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeSetValueField")
 	defer span.Finish()
 
@@ -6326,7 +6847,7 @@ func (e *executor) executeSetValueField(ctx context.Context, qcx *Qcx, index str
 	for _, node := range snap.ShardNodes(index, shard) {
 		// Update locally if host matches.
 		if node.ID == e.Node.ID {
-			val, err := f.SetValue(qcx, colID, value)
+			val, err := f.SetValueTime(qcx, colID, value, timestamp)
 			if err != nil {
 				return false, err
 			} else if val {
@@ -7205,6 +7726,18 @@ func fieldValidateValue(f *Field, val interface{}) error {
 		default:
 			return errors.Errorf("invalid value %v for timestamp field %q", v, f.Name())
 		}
+	case FieldTypeFloat:
+		switch v := val.(type) {
+		case uint64:
+		case int64:
+		case float64:
+			if math.IsNaN(v) {
+				return errors.Errorf("invalid value NaN for float field %q", f.Name())
+			}
+		case pql.Decimal:
+		default:
+			return errors.Errorf("invalid value %v for float field %q", v, f.Name())
+		}
 	default:
 		return errors.Errorf("unsupported type %s of field %q", f.Type(), f.Name())
 	}
@@ -7266,7 +7799,7 @@ func (e *executor) translateCall(c *pql.Call, index string, columnKeys map[strin
 			}
 			if c.Name == "Row" {
 				switch f.Type() {
-				case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp:
+				case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp, FieldTypeFloat:
 					if _, ok := arg.(*pql.Condition); !ok {
 						// This is workaround to support pql.ASSIGN ('=') as condition ('==') for BSI fields.
 						arg = &pql.Condition{
@@ -7640,6 +8173,13 @@ func (e *executor) collectResultIDs(index string, idx *Index, call *pql.Call, re
 		for _, col := range result.Columns {
 			idSet[col.ColumnID] = struct{}{}
 		}
+	case *PairsField:
+		// The pairs of Nearest() hold columns rather than rows.
+		if call.Name == "Nearest" {
+			for _, p := range result.Pairs {
+				idSet[p.ID] = struct{}{}
+			}
+		}
 	}
 
 	return nil
@@ -7766,6 +8306,19 @@ func (e *executor) translateResult(ctx context.Context, index string, idx *Index
 		}
 
 	case *PairsField:
+		if call.Name == "Nearest" {
+			if !idx.Keys() {
+				return result, nil
+			}
+			other := make([]Pair, len(result.Pairs))
+			for i, p := range result.Pairs {
+				other[i] = Pair{Key: idSet[p.ID], Count: p.Count}
+			}
+			return &PairsField{
+				Pairs: other,
+				Field: result.Field,
+			}, nil
+		}
 		if fieldName := callArgString(call, "_field"); fieldName != "" {
 			field := idx.Field(fieldName)
 			if field == nil {
@@ -8060,6 +8613,18 @@ func (e *executor) translateResult(ctx context.Context, index string, idx *Index
 						return nil, errors.Errorf("BSI field %q has too many values: %v", field.Name(), ids)
 					}
 				}
+			case FieldTypeFloat:
+				datatype = "float64"
+				mapper = func(ids []uint64) (_ interface{}, err error) {
+					switch len(ids) {
+					case 0:
+						return nil, nil
+					case 1:
+						return ValToFloat(int64(ids[0])), nil
+					default:
+						return nil, errors.Errorf("BSI field %q has too many values: %v", field.Name(), ids)
+					}
+				}
 			default:
 				return nil, errors.Errorf("field type %q not yet supported", typ)
 			}
@@ -8147,6 +8712,30 @@ func TimestampToVal(unit string, ts time.Time) int64 {
 	return 0
 }
 
+// FloatToVal converts a float64 to the integer stored for it in a float
+// field. The encoding preserves order: the bits of a positive float are used
+// as they are, and all but the sign bit of a negative float are flipped, so
+// that larger magnitudes sort lower. Negative zero is stored as zero.
+func FloatToVal(f float64) int64 {
+	if f == 0 {
+		return 0
+	}
+	v := int64(math.Float64bits(f))
+	if v < 0 {
+		v ^= math.MaxInt64
+	}
+	return v
+}
+
+// ValToFloat takes an integer stored in a float field and converts it back
+// to a float64.
+func ValToFloat(val int64) float64 {
+	if val < 0 {
+		val ^= math.MaxInt64
+	}
+	return math.Float64frombits(uint64(val))
+}
+
 // detectRangeCall returns true if the call or one of its children contains a Range call
 // TODO: Remove at version 2.0
 func (e *executor) detectRangeCall(c *pql.Call) bool {
@@ -8517,6 +9106,7 @@ func (vc *ValCount) floatSmaller(other ValCount) ValCount {
 		extra += other.Count
 	}
 	return ValCount{
+		Val:      vc.Val,
 		FloatVal: vc.FloatVal,
 		Count:    vc.Count + extra,
 	}
@@ -8597,6 +9187,7 @@ func (vc *ValCount) floatLarger(other ValCount) ValCount {
 		extra += other.Count
 	}
 	return ValCount{
+		Val:      vc.Val,
 		FloatVal: vc.FloatVal,
 		Count:    vc.Count + extra,
 	}
@@ -9012,6 +9603,22 @@ func getScaledInt(f *Field, v interface{}) (int64, error) {
 		default:
 			return 0, errors.Errorf("unexpected timestamp value type %T, val %v", tv, tv)
 		}
+	} else if opt.Type == FieldTypeFloat {
+		switch tv := v.(type) {
+		case float64:
+			if math.IsNaN(tv) {
+				return 0, ErrFloatNaN
+			}
+			value = FloatToVal(tv)
+		case pql.Decimal:
+			value = FloatToVal(tv.Float64())
+		case int64:
+			value = FloatToVal(float64(tv))
+		case uint64:
+			value = FloatToVal(float64(tv))
+		default:
+			return 0, errors.Errorf("unexpected float value type %T, val %v", tv, tv)
+		}
 	} else {
 		switch tv := v.(type) {
 		case int64:
@@ -9472,7 +10079,7 @@ func (e *executor) executeSortShard(ctx context.Context, qcx *Qcx, index string,
 			Row:    filter,
 			RowKVs: rowKVs,
 		}, nil
-	case FieldTypeDecimal, FieldTypeInt, FieldTypeTimestamp:
+	case FieldTypeDecimal, FieldTypeInt, FieldTypeTimestamp, FieldTypeFloat:
 		return f.SortShardRow(tx, shard, filter, sort_desc)
 	case FieldTypeMutex:
 		fragment := e.Holder.fragment(index, f.name, viewStandard, shard)
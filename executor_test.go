@@ -24,7 +24,9 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/batch"
 	"github.com/featurebasedb/featurebase/v3/ctl"
+	"github.com/featurebasedb/featurebase/v3/dax"
 	"github.com/featurebasedb/featurebase/v3/disco"
 	"github.com/featurebasedb/featurebase/v3/logger"
 	"github.com/featurebasedb/featurebase/v3/pql"
@@ -1232,6 +1234,32 @@ func TestExecutor_Execute_ConstRowTrackExistence(t *testing.T) {
 	}
 }
 
+func TestExecutor_Execute_ConstRowKeyPrefix(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{Keys: true, TrackExistence: true, PrimaryKey: []string{"region", "num"}}, "h")
+	c.ImportIDKey(t, c.Idx(), "h", []test.KeyID{
+		{Key: "east|1", ID: 1},
+		{Key: "east|2", ID: 1},
+		{Key: "west|1", ID: 1},
+		{Key: `east\|x|1`, ID: 1},
+	})
+
+	resp := c.Query(t, c.Idx(), `ConstRow(keyPrefix="east|")`)
+	got := resp.Results[0].(*pilosa.Row).Keys
+	sort.Strings(got)
+	expect := []string{"east|1", "east|2"}
+	if !reflect.DeepEqual(expect, got) {
+		t.Errorf("expected %v but got %v", expect, got)
+	}
+
+	resp = c.Query(t, c.Idx(), `Count(ConstRow(keyPrefix="north|"))`)
+	if n := resp.Results[0].(uint64); n != 0 {
+		t.Errorf("expected no records, got %d", n)
+	}
+}
+
 // Ensure a difference query can be executed.
 func TestExecutor_Execute_Difference(t *testing.T) {
 	t.Run("RowIDColumnID", func(t *testing.T) {
@@ -3003,6 +3031,382 @@ func TestExecutor_DecimalArgs(t *testing.T) {
 	}
 }
 
+// Ensure FloatToVal preserves the ordering of floats and round trips.
+func TestFloatToVal(t *testing.T) {
+	vals := []float64{math.Inf(-1), -math.MaxFloat64, -1e10, -2.5, -math.SmallestNonzeroFloat64, 0, math.SmallestNonzeroFloat64, 1, 2.5, 1e300, math.Inf(1)}
+	for i, v := range vals {
+		if got := pilosa.ValToFloat(pilosa.FloatToVal(v)); got != v {
+			t.Fatalf("round trip of %v: got %v", v, got)
+		}
+		if i > 0 && pilosa.FloatToVal(vals[i-1]) >= pilosa.FloatToVal(v) {
+			t.Fatalf("expected encoding of %v to sort before %v", vals[i-1], v)
+		}
+	}
+	if pilosa.FloatToVal(math.Copysign(0, -1)) != 0 {
+		t.Fatalf("expected negative zero to be stored as zero")
+	}
+}
+
+// Ensure range, sum, min, max and percentile queries work on float fields.
+func TestExecutor_Execute_Float(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "f", pilosa.OptFieldTypeFloat())
+
+	if _, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: `
+		Set(1, f=-2.5)
+		Set(2, f=0.25)
+		Set(3, f=1.5)
+		Set(` + strconv.Itoa(ShardWidth+1) + `, f=123456789.5)
+		Set(` + strconv.Itoa(2*ShardWidth+1) + `, f=0.5)
+	`}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Range", func(t *testing.T) {
+		for q, exp := range map[string][]uint64{
+			`Row(f > 0)`:       {2, 3, ShardWidth + 1, 2*ShardWidth + 1},
+			`Row(f < 0.25)`:    {1},
+			`Row(f <= 0.25)`:   {1, 2},
+			`Row(f == 1.5)`:    {3},
+			`Row(f != 1.5)`:    {1, 2, ShardWidth + 1, 2*ShardWidth + 1},
+			`Row(-3 < f < 1)`:  {1, 2, 2*ShardWidth + 1},
+			`Row(f > 1000000)`: {ShardWidth + 1},
+		} {
+			res, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: q})
+			if err != nil {
+				t.Fatalf("%s: %v", q, err)
+			}
+			if got := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(got, exp) {
+				t.Fatalf("%s: expected %v, got %v", q, exp, got)
+			}
+		}
+	})
+
+	t.Run("Aggregates", func(t *testing.T) {
+		for q, exp := range map[string]pilosa.ValCount{
+			`Sum(field=f)`:                 {FloatVal: 123456789.25, Count: 5},
+			`Sum(Row(f < 1), field=f)`:     {FloatVal: -1.75, Count: 3},
+			`Min(field=f)`:                 {FloatVal: -2.5, Count: 1},
+			`Max(field=f)`:                 {FloatVal: 123456789.5, Count: 1},
+			`Max(Row(f < 1), field=f)`:     {FloatVal: 0.5, Count: 1},
+			`Percentile(field=f, nth=0)`:   {FloatVal: -2.5, Count: 1},
+			`Percentile(field=f, nth=100)`: {FloatVal: 123456789.5, Count: 1},
+		} {
+			res, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: q})
+			if err != nil {
+				t.Fatalf("%s: %v", q, err)
+			}
+			if got := res.Results[0].(pilosa.ValCount); !reflect.DeepEqual(got, exp) {
+				t.Fatalf("%s: expected %+v, got %+v", q, exp, got)
+			}
+		}
+	})
+}
+
+func TestExecutor_Execute_Nearest(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "v", pilosa.OptFieldTypeSet(pilosa.CacheTypeNone, 0), pilosa.OptFieldBitVector(16))
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "g")
+
+	// Set the bits of each vector, along with row 16 which marks the records
+	// holding one.
+	vectors := map[uint64]string{
+		1:                "ff00",
+		2:                "ff01",
+		3:                "0000",
+		ShardWidth + 1:   "ff03",
+		2*ShardWidth + 1: "7f00",
+	}
+	var q strings.Builder
+	for col, vec := range vectors {
+		positions, err := pilosa.ParseBitVector(vec, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, pos := range append(positions, 16) {
+			fmt.Fprintf(&q, "Set(%d, v=%d)\n", col, pos)
+		}
+	}
+	q.WriteString("Set(3, g=1)\nSet(4, g=1)\nSet(" + strconv.Itoa(ShardWidth+1) + ", g=1)\n")
+	if _, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: q.String()}); err != nil {
+		t.Fatal(err)
+	}
+
+	for query, exp := range map[string][]pilosa.Pair{
+		`Nearest(field=v, vector="ff00", k=3)`: {
+			{ID: 1, Count: 0}, {ID: 2, Count: 1}, {ID: 2*ShardWidth + 1, Count: 1},
+		},
+		`Nearest(field=v, vector="ff00", k=10)`: {
+			{ID: 1, Count: 0}, {ID: 2, Count: 1}, {ID: 2*ShardWidth + 1, Count: 1}, {ID: ShardWidth + 1, Count: 2}, {ID: 3, Count: 8},
+		},
+		`Nearest(field=v, vector="00ff", k=1)`: {
+			{ID: 3, Count: 8},
+		},
+		`Nearest(field=v, vector="ff00", k=5, filter=Row(g=1))`: {
+			{ID: ShardWidth + 1, Count: 2}, {ID: 3, Count: 8},
+		},
+	} {
+		res, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: query})
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		if got := res.Results[0].(*pilosa.PairsField).Pairs; !reflect.DeepEqual(got, exp) {
+			t.Fatalf("%s: expected %v, got %v", query, exp, got)
+		}
+	}
+
+	for query, exp := range map[string]string{
+		`Nearest(field=g, vector="ff00", k=3)`: "not a bitvector field",
+		`Nearest(field=v, vector="ff", k=3)`:   "bitvector has 8 bits, expected 16",
+		`Nearest(field=v, vector="ff00")`:      "k required",
+	} {
+		_, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: query})
+		if err == nil || !strings.Contains(err.Error(), exp) {
+			t.Fatalf("%s: expected error containing %q, got %v", query, exp, err)
+		}
+	}
+}
+
+// Ensure aggregates on int and decimal fields with a time quantum can be
+// limited to a time range.
+func TestExecutor_Execute_TimeQuantumBSI(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "rev", pilosa.OptFieldTypeInt(-1000, 1000), pilosa.OptFieldTimeQuantum("YM", "0"))
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "amt", pilosa.OptFieldTypeDecimal(2), pilosa.OptFieldTimeQuantum("YMD", "0"))
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "plain", pilosa.OptFieldTypeInt(0, 1000))
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "g")
+
+	c.Query(t, c.Idx(), fmt.Sprintf(`
+		Set(1, rev=10, 2023-01-05T00:00)
+		Set(2, rev=20, 2023-02-10T00:00)
+		Set(%[1]d, rev=-5, 2023-02-11T00:00)
+		Set(3, rev=7)
+		Set(1, rev=4, 2023-03-01T00:00)
+		Set(1, amt=1.50, 2023-01-05T00:00)
+		Set(2, amt=2.25, 2023-01-06T00:00)
+		Set(1, g=1)
+		Set(2, g=1)
+		Set(%[1]d, g=2)
+		Set(1, plain=1)
+	`, ShardWidth+1))
+
+	for query, exp := range map[string]pilosa.ValCount{
+		`Sum(field=rev)`: {Val: 26, Count: 4},
+		`Sum(field=rev, from=2023-01-01T00:00, to=2023-03-01T00:00)`: {Val: 25, Count: 3},
+		`Sum(field=rev, from=2023-02-01T00:00)`:                      {Val: 19, Count: 3},
+		`Sum(field=rev, to=2023-02-01T00:00)`:                        {Val: 10, Count: 1},
+		`Sum(field=rev, from=2024-01-01T00:00)`:                      {},
+		`Min(field=rev, from=2023-01-01T00:00, to=2023-02-01T00:00)`: {Val: 10, Count: 1},
+		`Min(field=rev, from=2023-01-01T00:00, to=2023-03-01T00:00)`: {Val: -5, Count: 1},
+		`Max(field=rev, from=2023-01-01T00:00, to=2023-03-01T00:00)`: {Val: 20, Count: 1},
+		`Max(field=rev)`: {Val: 20, Count: 1},
+	} {
+		res, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: query})
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		if got := res.Results[0].(pilosa.ValCount); got != exp {
+			t.Fatalf("%s: expected %v, got %v", query, exp, got)
+		}
+	}
+
+	res, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: `Sum(field=amt, from=2023-01-06T00:00, to=2023-01-07T00:00)`})
+	if err != nil {
+		t.Fatal(err)
+	} else if got := res.Results[0].(pilosa.ValCount); got.DecimalVal == nil || got.DecimalVal.String() != "2.25" || got.Count != 1 {
+		t.Fatalf("unexpected decimal sum: %v", got)
+	}
+
+	for query, exp := range map[string]uint64{
+		`Count(Row(rev > 0, from=2023-01-01T00:00, to=2023-03-01T00:00))`:     2,
+		`Count(Row(rev < 5, from=2023-01-01T00:00, to=2023-04-01T00:00))`:     2,
+		`Count(Row(rev != null, from=2023-02-01T00:00, to=2023-03-01T00:00))`: 2,
+		`Count(Row(rev > 0))`: 3,
+	} {
+		res, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: query})
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		if got := res.Results[0].(uint64); got != exp {
+			t.Fatalf("%s: expected %d, got %d", query, exp, got)
+		}
+	}
+
+	res, err = c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: `GroupBy(Rows(g), aggregate=Sum(field=rev, from=2023-01-01T00:00, to=2023-03-01T00:00))`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.CheckGroupBy(t, []pilosa.GroupCount{
+		{Group: []pilosa.FieldRow{{Field: "g", RowID: 1}}, Count: 2, Agg: 30},
+		{Group: []pilosa.FieldRow{{Field: "g", RowID: 2}}, Count: 1, Agg: -5},
+	}, res.Results[0].(*pilosa.GroupCounts).Groups())
+
+	for query, exp := range map[string]string{
+		`Sum(field=plain, from=2023-01-01T00:00)`:           "not a time-field",
+		`Count(Row(rev == null, from=2023-01-01T00:00))`:    "can't use a time range with a check for null",
+		`Sum(field=rev, from=2023-01-01T00:00, to="March")`: "parsing to time",
+	} {
+		_, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: query})
+		if err == nil || !strings.Contains(err.Error(), exp) {
+			t.Fatalf("%s: expected error containing %q, got %v", query, exp, err)
+		}
+	}
+}
+
+// Ensure batch imports write the time views of an int field with a time
+// quantum, keeping the last value set for a record in each view.
+func TestExecutor_Execute_TimeQuantumBSIBatch(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "rev", pilosa.OptFieldTypeInt(-1000, 1000), pilosa.OptFieldTimeQuantum("YM", "0"))
+
+	ctx := context.Background()
+	api := c.GetNode(0).API
+	tbl, err := pilosa.NewOnPremSchema(api).TableByName(ctx, dax.TableName(c.Idx()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := []*pilosa.FieldInfo{pilosa.TableToIndexInfo(tbl).Field("rev")}
+	b, err := batch.NewBatch(pilosa.NewOnPremImporter(api), 10, tbl, fields, batch.OptUseShardTransactionalEndpoint(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range []struct {
+		id  uint64
+		val int64
+		at  string
+	}{
+		{1, 10, "2023-01-05"},
+		{2, 20, "2023-02-10"},
+		{1, 5, "2023-01-20"},
+		{1, 4, "2023-03-01"},
+		{3, 8, ""},
+		{ShardWidth + 2, -5, "2023-02-11"},
+	} {
+		row := batch.Row{ID: rec.id, Values: []interface{}{rec.val}}
+		if rec.at != "" {
+			at, err := time.Parse("2006-01-02", rec.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			row.Time.Set(at)
+		}
+		if err := b.Add(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.Import(); err != nil {
+		t.Fatal(err)
+	}
+
+	for query, exp := range map[string]pilosa.ValCount{
+		`Sum(field=rev)`: {Val: 27, Count: 4},
+		`Sum(field=rev, from=2023-01-01T00:00, to=2023-02-01T00:00)`: {Val: 5, Count: 1},
+		`Sum(field=rev, from=2023-02-01T00:00, to=2023-03-01T00:00)`: {Val: 15, Count: 2},
+		`Sum(field=rev, from=2023-01-01T00:00, to=2023-04-01T00:00)`: {Val: 24, Count: 4},
+	} {
+		res, err := api.Query(ctx, &pilosa.QueryRequest{Index: c.Idx(), Query: query})
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		if got := res.Results[0].(pilosa.ValCount); got != exp {
+			t.Fatalf("%s: expected %v, got %v", query, exp, got)
+		}
+	}
+}
+
+// Ensure split batch mode accumulates int, decimal, timestamp, mutex and
+// bool values across batches, keeping the last one set for each record,
+// and imports them on Flush.
+func TestExecutor_Execute_SplitBatch(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	opts := pilosa.IndexOptions{TrackExistence: true}
+	c.CreateField(t, c.Idx(), opts, "s", pilosa.OptFieldTypeDefault())
+	c.CreateField(t, c.Idx(), opts, "i", pilosa.OptFieldTypeInt(-1000, 1000))
+	c.CreateField(t, c.Idx(), opts, "d", pilosa.OptFieldTypeDecimal(2))
+	c.CreateField(t, c.Idx(), opts, "ts", pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitSeconds))
+	c.CreateField(t, c.Idx(), opts, "m", pilosa.OptFieldTypeMutex(pilosa.CacheTypeNone, 0))
+	c.CreateField(t, c.Idx(), opts, "b", pilosa.OptFieldTypeBool())
+
+	ctx := context.Background()
+	api := c.GetNode(0).API
+	tbl, err := pilosa.NewOnPremSchema(api).TableByName(ctx, dax.TableName(c.Idx()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := pilosa.TableToIndexInfo(tbl)
+	fields := []*pilosa.FieldInfo{idx.Field("s"), idx.Field("i"), idx.Field("d"), idx.Field("ts"), idx.Field("m"), idx.Field("b")}
+	b, err := batch.NewBatch(pilosa.NewOnPremImporter(api), 10, tbl, fields, batch.OptSplitBatchMode(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := func(s string) int64 {
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v.Unix()
+	}
+	for _, rows := range [][]batch.Row{
+		{
+			{ID: uint64(1), Values: []interface{}{uint64(1), int64(10), 1.5, ts("2023-01-01T00:00:00Z"), uint64(2), true}},
+			{ID: uint64(2), Values: []interface{}{nil, int64(-3), nil, nil, uint64(1), false}},
+			{ID: uint64(ShardWidth + 1), Values: []interface{}{nil, int64(7), nil, nil, uint64(3), nil}},
+		},
+		{
+			{ID: uint64(1), Values: []interface{}{nil, int64(4), 2.25, ts("2022-06-01T00:00:00Z"), uint64(5), false}},
+			{ID: uint64(2), Values: []interface{}{nil, nil, nil, nil, nil, nil}, Clears: map[int]interface{}{4: nil}},
+			{ID: uint64(ShardWidth + 1), Values: []interface{}{nil, int64(-8), nil, nil, nil, nil}},
+		},
+	} {
+		for _, row := range rows {
+			if err := b.Add(row); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := b.Import(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if res := c.Query(t, c.Idx(), `Count(All())`); res.Results[0] != uint64(0) {
+		t.Fatalf("expected nothing imported before flush, got %v", res.Results[0])
+	}
+	if err := b.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	for query, exp := range map[string]uint64{
+		`Count(Row(s=1))`:                                 1,
+		`Count(Row(d==2.25))`:                             1,
+		`Count(Row(d==1.5))`:                              0,
+		`Count(Row(ts=="2022-06-01T00:00:00Z"))`:          1,
+		`Count(Row(ts>"2022-12-31T00:00:00Z"))`:           0,
+		`Count(Row(m=5))`:                                 1,
+		`Count(Row(m=2))`:                                 0,
+		`Count(Row(m=1))`:                                 0,
+		`Count(Row(m=3))`:                                 1,
+		`Count(Row(b=false))`:                             1,
+		`Count(Row(b=true))`:                              0,
+		`Count(Union(Row(i==4), Row(i==-8), Row(i==-3)))`: 3,
+	} {
+		if res := c.Query(t, c.Idx(), query); res.Results[0] != exp {
+			t.Errorf("%s: expected %d, got %v", query, exp, res.Results[0])
+		}
+	}
+	res, err := api.Query(ctx, &pilosa.QueryRequest{Index: c.Idx(), Query: `Sum(field=i)`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Results[0].(pilosa.ValCount); got != (pilosa.ValCount{Val: -7, Count: 3}) {
+		t.Fatalf("expected sum -7 over 3 records, got %v", got)
+	}
+}
+
 // Ensure a Row(bsiGroup) query can be executed.
 func TestExecutor_Execute_Row_BSIGroup(t *testing.T) {
 	c := test.MustRunCluster(t, 1)
@@ -4242,6 +4646,33 @@ func TestExecutor_Execute_Limit(t *testing.T) {
 		}
 	})
 
+	// Test with a limit and the column to start after specified.
+	t.Run("LimitAfter", func(t *testing.T) {
+		for _, tt := range []struct {
+			after  uint64
+			expect []uint64
+		}{
+			{after: 0, expect: []uint64{1, ShardWidth + 1}},
+			{after: 1, expect: []uint64{ShardWidth + 1}},
+			{after: 2, expect: []uint64{ShardWidth + 1}},
+			{after: ShardWidth, expect: []uint64{ShardWidth + 1}},
+			{after: ShardWidth + 1, expect: []uint64{}},
+		} {
+			resp := c.Query(t, c.Idx(), fmt.Sprintf("Limit(All(), limit=2, after=%d)", tt.after))
+			if len(resp.Results) != 1 {
+				t.Fatalf("after=%d: expected 1 result but got %v", tt.after, resp.Results)
+			}
+			row, ok := resp.Results[0].(*pilosa.Row)
+			if !ok {
+				t.Fatalf("after=%d: expected a row result but got %T", tt.after, resp.Results[0])
+			}
+			got := row.Columns()
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Errorf("after=%d: expected %v but got %v", tt.after, tt.expect, got)
+			}
+		}
+	})
+
 	t.Run("Nested", func(t *testing.T) {
 		for limit := 0; limit < 5; limit++ {
 			for offset := 0; offset < 5; offset++ {
@@ -5,6 +5,7 @@ package pilosa
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -47,6 +48,7 @@ const (
 	FieldTypeBool      = "bool"
 	FieldTypeDecimal   = "decimal"
 	FieldTypeTimestamp = "timestamp"
+	FieldTypeFloat     = "float"
 )
 
 type protected struct {
@@ -89,6 +91,12 @@ type Field struct {
 	// Field options.
 	options FieldOptions
 
+	// constraints caches the field's compiled constraints, which are
+	// recompiled if the options they depend on change.
+	constraintsMu   sync.Mutex
+	constraintsOpts constraintOptions
+	constraints     *FieldConstraints
+
 	bsiGroups []*bsiGroup
 
 	// Shards with data on any node in the cluster, according to this node.
@@ -322,6 +330,23 @@ func OptFieldTypeDecimal(scale int64, minmax ...pql.Decimal) FieldOption {
 	}
 }
 
+// OptFieldTypeFloat is a functional option for creating a `float` field.
+// Float values are IEEE-754 doubles, stored in the BSI as the order-preserving
+// integers returned by FloatToVal, so unlike decimal fields there is no scale
+// or range to choose up front. NaN can't be stored.
+func OptFieldTypeFloat() FieldOption {
+	return func(fo *FieldOptions) error {
+		if fo.Type != "" {
+			return errors.Errorf("can't set field type to 'float', already set to: %s", fo.Type)
+		}
+		fo.Type = FieldTypeFloat
+		fo.Min = pql.NewDecimal(math.MinInt64, 0)
+		fo.Max = pql.NewDecimal(math.MaxInt64, 0)
+		fo.Base = 0
+		return nil
+	}
+}
+
 // OptFieldTypeTime is a functional option on FieldOptions
 // used to specify the field as being type `time` and to
 // provide any respective configuration values.
@@ -349,6 +374,31 @@ func OptFieldTypeTime(timeQuantum TimeQuantum, ttl string, opt ...bool) FieldOpt
 	}
 }
 
+// OptFieldTimeQuantum is a functional option on FieldOptions used to give an
+// int or decimal field a time quantum. Values set with a timestamp are also
+// written to a BSI view for each period of the quantum, so that aggregates
+// can be limited to a time range. It must follow the option setting the type.
+func OptFieldTimeQuantum(timeQuantum TimeQuantum, ttl string) FieldOption {
+	return func(fo *FieldOptions) error {
+		if fo.Type != FieldTypeInt && fo.Type != FieldTypeDecimal {
+			return errors.Errorf("time quantum does not apply to field type: %s", fo.Type)
+		}
+		if !timeQuantum.Valid() {
+			return ErrInvalidTimeQuantum
+		}
+		ttlParsed, err := time.ParseDuration(ttl)
+		if err != nil {
+			return errors.Errorf("cannot parse ttl: %s", ttl)
+		}
+		if ttlParsed < 0 {
+			return errors.Errorf("ttl can't be negative: %s", ttl)
+		}
+		fo.TimeQuantum = timeQuantum
+		fo.TTL = ttlParsed
+		return nil
+	}
+}
+
 // OptFieldTypeMutex is a functional option on FieldOptions
 // used to specify the field as being type `mutex` and to
 // provide any respective configuration values.
@@ -377,6 +427,174 @@ func OptFieldTypeBool() FieldOption {
 	}
 }
 
+// OptFieldJSON is a functional option on FieldOptions used to mark a set
+// field as holding JSON documents. A record's document is stored in its own
+// column, a row per byte (see JSONDocumentRows), so that it takes space
+// only while the record holds it. Each of the given paths is indexed in a
+// keyed set field named by JSONPathFieldName.
+func OptFieldJSON(paths ...string) FieldOption {
+	return func(fo *FieldOptions) error {
+		fo.JSON = true
+		fo.JSONPaths = paths
+		return nil
+	}
+}
+
+// MaxJSONDocumentLength is the length, in bytes, of the longest JSON
+// document a JSON field will store. Each byte takes a row of its own, so
+// the cap bounds the number of rows in the field's fragments.
+const MaxJSONDocumentLength = 1 << 16
+
+// JSONDocumentRows returns the rows to set for the JSON document doc. The
+// nth byte of the document, b, is row n<<8|b. Documents are expected to be
+// no longer than MaxJSONDocumentLength.
+func JSONDocumentRows(doc string) []uint64 {
+	rows := make([]uint64, len(doc))
+	for i := 0; i < len(doc); i++ {
+		rows[i] = uint64(i)<<8 | uint64(doc[i])
+	}
+	return rows
+}
+
+// JSONDocumentFromRows is the inverse of JSONDocumentRows. The rows must be
+// in ascending order, as they're listed from a fragment.
+func JSONDocumentFromRows(rows []uint64) (string, error) {
+	doc := make([]byte, len(rows))
+	for i, row := range rows {
+		if row>>8 != uint64(i) {
+			return "", errors.Errorf("json document is corrupt at byte %d", i)
+		}
+		doc[i] = byte(row)
+	}
+	return string(doc), nil
+}
+
+// JSONPathFieldName returns the name of the set field which indexes path
+// for the JSON field named field. The path is reduced to the characters
+// allowed in a field name, so '$.plan.tier' on field "payload" becomes
+// "payload__plan_tier".
+func JSONPathFieldName(field, path string) string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	var b strings.Builder
+	b.WriteString(field)
+	b.WriteString("__")
+	for _, r := range strings.ToLower(path) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// OptFieldGeoPoint is a functional option on FieldOptions used to mark an
+// int field as holding geographic points. Each point is stored as its
+// latitude and longitude in the upper and lower 32 bits of the value, and the
+// grid cells containing it are kept in a set field named by GeoCellFieldName.
+func OptFieldGeoPoint() FieldOption {
+	return func(fo *FieldOptions) error {
+		fo.GeoPoint = true
+		return nil
+	}
+}
+
+// GeoCellFieldName returns the name of the set field which holds the grid
+// cells of the points in the geopoint field named field.
+func GeoCellFieldName(field string) string {
+	return field + "__cells"
+}
+
+// derivedFieldNames returns the names of the fields which are maintained
+// along with the field named name: those indexing the paths of a JSON field,
+// and the cells of a geopoint field.
+func derivedFieldNames(name string, opts FieldOptions) []string {
+	var names []string
+	if opts.JSON {
+		for _, path := range opts.JSONPaths {
+			names = append(names, JSONPathFieldName(name, path))
+		}
+	}
+	if opts.GeoPoint {
+		names = append(names, GeoCellFieldName(name))
+	}
+	return names
+}
+
+// OptFieldBitVector is a functional option on FieldOptions used to mark a
+// set field as holding binary vectors of width bits. Bit i of a vector is
+// stored in row i, and row width is set for every record holding a vector,
+// so that a null vector can be told apart from one of all zeros.
+func OptFieldBitVector(width int64) FieldOption {
+	return func(fo *FieldOptions) error {
+		fo.BitVector = width
+		return nil
+	}
+}
+
+// ParseBitVector returns the positions of the bits set in a vector of width
+// bits written in hex. The first bit of the vector is the high bit of the
+// first byte.
+func ParseBitVector(s string, width int64) ([]uint64, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding bitvector")
+	}
+	if int64(len(b))*8 != width {
+		return nil, errors.Errorf("bitvector has %d bits, expected %d", len(b)*8, width)
+	}
+	var positions []uint64
+	for i, c := range b {
+		for j := 0; j < 8; j++ {
+			if c&(0x80>>j) != 0 {
+				positions = append(positions, uint64(i*8+j))
+			}
+		}
+	}
+	return positions, nil
+}
+
+// FormatBitVector returns the hex form of a vector of width bits with the bits
+// at positions set. Positions at or past width are ignored.
+func FormatBitVector(positions []uint64, width int64) string {
+	b := make([]byte, width/8)
+	for _, pos := range positions {
+		if pos < uint64(width) {
+			b[pos/8] |= 0x80 >> (pos % 8)
+		}
+	}
+	return hex.EncodeToString(b)
+}
+
+// OptFieldNotNull is a functional option on FieldOptions used to reject
+// records which don't hold a value for the field.
+func OptFieldNotNull() FieldOption {
+	return func(fo *FieldOptions) error {
+		fo.NotNull = true
+		return nil
+	}
+}
+
+// OptFieldDefault is a functional option on FieldOptions used to set the SQL
+// expression whose value is stored for records which don't hold a value for
+// the field.
+func OptFieldDefault(expr string) FieldOption {
+	return func(fo *FieldOptions) error {
+		fo.Default = expr
+		return nil
+	}
+}
+
+// OptFieldCheck is a functional option on FieldOptions used to set a SQL
+// boolean expression which every value of the field must satisfy.
+func OptFieldCheck(expr string) FieldOption {
+	return func(fo *FieldOptions) error {
+		fo.Check = expr
+		return nil
+	}
+}
+
 // OptFieldTrackExistence exists mostly to allow the
 // FieldFromFieldOptions/FieldOptionsFromField round-trip to work.
 // If you are actually creating a field, via api.CreateField,
@@ -571,6 +789,25 @@ func (f *Field) Options() FieldOptions {
 	return f.options
 }
 
+// Constraints returns the field's compiled NOT NULL, DEFAULT and CHECK
+// constraints, or nil if it has none.
+func (f *Field) Constraints() (*FieldConstraints, error) {
+	opts := f.Options()
+	key := newConstraintOptions(opts)
+
+	f.constraintsMu.Lock()
+	defer f.constraintsMu.Unlock()
+	if f.constraints != nil && f.constraintsOpts == key {
+		return f.constraints, nil
+	}
+	fc, err := NewFieldConstraints(f.Name(), opts)
+	if err != nil {
+		return nil, err
+	}
+	f.constraints, f.constraintsOpts = fc, key
+	return fc, nil
+}
+
 // Open opens and initializes the field.
 func (f *Field) Open() error {
 	f.mu.Lock()
@@ -824,7 +1061,10 @@ func (f *Field) applyOptions(opt FieldOptions) error {
 		f.options.TTL = 0
 		f.options.Keys = opt.Keys
 		f.options.ForeignIndex = opt.ForeignIndex
-	case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp:
+		f.options.JSON = opt.JSON
+		f.options.JSONPaths = opt.JSONPaths
+		f.options.BitVector = opt.BitVector
+	case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp, FieldTypeFloat:
 		f.options.Type = opt.Type
 		f.options.CacheType = CacheTypeNone
 		f.options.CacheSize = 0
@@ -838,6 +1078,15 @@ func (f *Field) applyOptions(opt FieldOptions) error {
 		f.options.TTL = 0
 		f.options.Keys = opt.Keys
 		f.options.ForeignIndex = opt.ForeignIndex
+		f.options.GeoPoint = opt.GeoPoint
+		// Int and decimal fields may also keep their values in time views.
+		if opt.TimeQuantum != "" && (opt.Type == FieldTypeInt || opt.Type == FieldTypeDecimal) {
+			if !opt.TimeQuantum.Valid() {
+				return ErrInvalidTimeQuantum
+			}
+			f.options.TimeQuantum = opt.TimeQuantum
+			f.options.TTL = opt.TTL
+		}
 
 		// Create new bsiGroup.
 		bsig := &bsiGroup{
@@ -886,6 +1135,9 @@ func (f *Field) applyOptions(opt FieldOptions) error {
 	default:
 		return errors.New("invalid field type")
 	}
+	f.options.NotNull = opt.NotNull
+	f.options.Default = opt.Default
+	f.options.Check = opt.Check
 
 	return nil
 }
@@ -991,21 +1243,31 @@ func (f *Field) hasBSIGroup(name string) bool {
 // It yields an error if the view name given does not correspond
 // to a view which should exist. For instance, the "standard" or
 // "existence" views for a BSI field, or a time quantum view for
-// a non-time field.
+// a field without a time quantum. The time quantum views of an int
+// or decimal field are named after its BSI view.
 func (f *Field) cleanupViewName(viewName string) (string, error) {
 	if viewName == "" {
 		switch f.options.Type {
-		case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp:
+		case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp, FieldTypeFloat:
 			return "bsig_" + f.name, nil
 		default:
 			return viewStandard, nil
 		}
 	}
 	switch f.options.Type {
-	case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp:
+	case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp, FieldTypeFloat:
 		if viewName == "bsig_"+f.name {
 			return viewName, nil
 		}
+		if f.options.TimeQuantum != "" {
+			if unicode.IsDigit(rune(viewName[0])) {
+				viewName = "bsig_" + f.name + "_" + viewName
+			}
+			if f.isTimeView(viewName) {
+				return viewName, nil
+			}
+			return viewName, fmt.Errorf("BSI-type field view should be named bsig_[fieldname] or bsig_[fieldname]_[digits], got %q", viewName)
+		}
 		return viewName, fmt.Errorf("BSI-type field view should be named bsig_[fieldname], got %q", viewName)
 	case FieldTypeTime:
 		switch {
@@ -1055,6 +1317,28 @@ func (f *Field) TimeQuantum() TimeQuantum {
 	return f.options.TimeQuantum
 }
 
+// timeViewBase returns the name of the view which the field's time quantum
+// views are named after: the BSI view for int and decimal fields, and the
+// standard view otherwise.
+func (f *Field) timeViewBase() string {
+	switch f.Type() {
+	case FieldTypeInt, FieldTypeDecimal:
+		return viewBSIGroupPrefix + f.name
+	default:
+		return viewStandard
+	}
+}
+
+// isTimeView reports whether name is one of the field's time quantum views.
+func (f *Field) isTimeView(name string) bool {
+	prefix := f.timeViewBase() + "_"
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	part := name[len(prefix):]
+	return part != "" && viewTimePart(name) == part
+}
+
 // viewsByTimeRange is a wrapper on the non-method viewsByTimeRange,
 // which computes views for a specific field for a given time
 // range. The difference is that, it can return "standard" if from/to
@@ -1073,15 +1357,18 @@ func (f *Field) viewsByTimeRange(from, to time.Time) (views []string, err error)
 		return nil, fmt.Errorf("field %s is not a time-field, 'from' and 'to' are not valid options for this field type", f.name)
 	}
 
-	if from.IsZero() && to.IsZero() && !f.options.NoStandardView {
-		return []string{viewStandard}, nil
+	base := f.timeViewBase()
+	if from.IsZero() && to.IsZero() && (base != viewStandard || !f.options.NoStandardView) {
+		return []string{base}, nil
 	}
 
 	// Get min/max based on existing views.
 	fv := f.views()
 	vs := make([]string, 0, len(fv))
 	for _, v := range fv {
-		vs = append(vs, v.name)
+		if f.isTimeView(v.name) {
+			vs = append(vs, v.name)
+		}
 	}
 	min, max := minMaxViews(vs, q)
 
@@ -1106,7 +1393,7 @@ func (f *Field) viewsByTimeRange(from, to time.Time) (views []string, err error)
 	if to.IsZero() || to.After(maxTime) {
 		to = maxTime
 	}
-	return viewsByTimeRange(viewStandard, from, to, q), nil
+	return viewsByTimeRange(base, from, to, q), nil
 }
 
 // RowTime gets the row at the particular time with the granularity specified by
@@ -1493,6 +1780,13 @@ func (f *Field) Value(qcx *Qcx, columnID uint64) (value int64, exists bool, err
 
 // SetValue sets a field value for a column.
 func (f *Field) SetValue(qcx *Qcx, columnID uint64, value int64) (changed bool, err error) {
+	return f.SetValueTime(qcx, columnID, value, nil)
+}
+
+// SetValueTime sets a field value for a column. If a timestamp is given
+// and the field has a time quantum, the value is also set in the time
+// views covering the timestamp.
+func (f *Field) SetValueTime(qcx *Qcx, columnID uint64, value int64, t *time.Time) (changed bool, err error) {
 	// Fetch bsiGroup & validate min/max.
 	bsig := f.bsiGroup(f.name)
 	if bsig == nil {
@@ -1540,7 +1834,27 @@ func (f *Field) SetValue(qcx *Qcx, columnID uint64, value int64) (changed bool,
 	}
 	view.holder.addIndex(view.idx)
 
-	return view.setValue(qcx, columnID, bsig.BitDepth, baseValue)
+	changed, err = view.setValue(qcx, columnID, bsig.BitDepth, baseValue)
+	if err != nil || t == nil {
+		return changed, err
+	}
+
+	q := f.TimeQuantum()
+	if q == "" {
+		return changed, nil
+	}
+	for _, subname := range viewsByTime(view.name, *t, q) {
+		tview, err := f.createViewIfNotExists(subname)
+		if err != nil {
+			return changed, errors.Wrapf(err, "creating view %s", subname)
+		}
+		if c, err := tview.setValue(qcx, columnID, bsig.BitDepth, baseValue); err != nil {
+			return changed, errors.Wrapf(err, "setting on view %s", subname)
+		} else if c {
+			changed = true
+		}
+	}
+	return changed, nil
 }
 
 // ClearValue removes a field value for a column.
@@ -1565,6 +1879,12 @@ func (f *Field) ClearValue(qcx *Qcx, columnID uint64) (changed bool, err error)
 }
 
 func (f *Field) MaxForShard(qcx *Qcx, shard uint64, filter *Row) (ValCount, error) {
+	return f.MaxForShardView(qcx, shard, viewBSIGroupPrefix+f.name, filter)
+}
+
+// MaxForShardView is MaxForShard reading the named BSI view, such as one
+// of the field's time quantum views.
+func (f *Field) MaxForShardView(qcx *Qcx, shard uint64, viewName string, filter *Row) (ValCount, error) {
 	tx, finisher, err := qcx.GetTx(Txo{Write: false, Index: f.idx, Shard: shard})
 	defer finisher(&err)
 	bsig := f.bsiGroup(f.name)
@@ -1572,7 +1892,7 @@ func (f *Field) MaxForShard(qcx *Qcx, shard uint64, filter *Row) (ValCount, erro
 		return ValCount{}, ErrBSIGroupNotFound
 	}
 
-	view := f.view(viewBSIGroupPrefix + f.name)
+	view := f.view(viewName)
 	if view == nil {
 		return ValCount{}, nil
 	}
@@ -1595,6 +1915,12 @@ func (f *Field) MaxForShard(qcx *Qcx, shard uint64, filter *Row) (ValCount, erro
 // (this field must be an Int or Decimal field). It also returns the
 // number of times the minimum value appears.
 func (f *Field) MinForShard(qcx *Qcx, shard uint64, filter *Row) (ValCount, error) {
+	return f.MinForShardView(qcx, shard, viewBSIGroupPrefix+f.name, filter)
+}
+
+// MinForShardView is MinForShard reading the named BSI view, such as one
+// of the field's time quantum views.
+func (f *Field) MinForShardView(qcx *Qcx, shard uint64, viewName string, filter *Row) (ValCount, error) {
 	tx, finisher, err := qcx.GetTx(Txo{Write: false, Index: f.idx, Shard: shard})
 	defer finisher(&err)
 	bsig := f.bsiGroup(f.name)
@@ -1602,7 +1928,7 @@ func (f *Field) MinForShard(qcx *Qcx, shard uint64, filter *Row) (ValCount, erro
 		return ValCount{}, ErrBSIGroupNotFound
 	}
 
-	view := f.view(viewBSIGroupPrefix + f.name)
+	view := f.view(viewName)
 	if view == nil {
 		return ValCount{}, nil
 	}
@@ -1652,6 +1978,8 @@ func (f *Field) valCountize(val int64, cnt uint64, bsig *bsiGroup) (ValCount, er
 		}
 		valCount.TimestampVal = ts
 		// valCount.TimestampVal = time.Unix(0, (val+bsig.Base)*TimeUnitNanos(f.options.TimeUnit)).UTC()
+	} else if f.options.Type == FieldTypeFloat {
+		valCount.FloatVal = ValToFloat(val + bsig.Base)
 	}
 
 	valCount.Val = val + bsig.Base
@@ -1940,12 +2268,22 @@ func (f *Field) Import(qcx *Qcx, rowIDs, columnIDs []uint64, timestamps []int64,
 // should only ever be called with data for a single shard; the API calls
 // around this are splitting it up per shard.
 func (f *Field) importFloatValue(qcx *Qcx, columnIDs []uint64, values []float64, shard uint64, options *ImportOptions) error {
-	// convert values to int64 values based on scale
+	// convert values to int64 values based on scale, or to their
+	// encoding for float fields
 	ivalues := make([]int64, len(values))
 	bsig := f.bsiGroup(f.name)
 	if bsig == nil {
 		return errors.Wrap(ErrBSIGroupNotFound, f.name)
 	}
+	if f.Type() == FieldTypeFloat {
+		for i, fval := range values {
+			if math.IsNaN(fval) {
+				return errors.Wrapf(ErrFloatNaN, "column %d", columnIDs[i])
+			}
+			ivalues[i] = FloatToVal(fval)
+		}
+		return f.importValue(qcx, columnIDs, ivalues, shard, options)
+	}
 	mult := math.Pow10(int(bsig.Scale))
 	for i, fval := range values {
 		ivalues[i] = int64(fval * mult)
@@ -2128,7 +2466,7 @@ func (f *Field) importRoaringOverwrite(ctx context.Context, tx Tx, data []byte,
 	// If field is int, decimal, or timestamp, then we need to update
 	// field.options.BitDepth and bsiGroup.BitDepth based on the imported data.
 	switch f.Options().Type {
-	case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp:
+	case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp, FieldTypeFloat:
 		frag.mu.Lock()
 		maxRowID, _, err := frag.maxRow(tx, nil)
 		frag.mu.Unlock()
@@ -2195,6 +2533,13 @@ type FieldOptions struct {
 	TimeQuantum    TimeQuantum   `json:"timeQuantum,omitempty"`
 	ForeignIndex   string        `json:"foreignIndex"`
 	TTL            time.Duration `json:"ttl,omitempty"`
+	JSON           bool          `json:"json,omitempty"`
+	JSONPaths      []string      `json:"jsonPaths,omitempty"`
+	GeoPoint       bool          `json:"geoPoint,omitempty"`
+	BitVector      int64         `json:"bitVector,omitempty"`
+	NotNull        bool          `json:"notNull,omitempty"`
+	Default        string        `json:"default,omitempty"`
+	Check          string        `json:"check,omitempty"`
 }
 
 // newFieldOptions returns a new instance of FieldOptions
@@ -2218,9 +2563,24 @@ func newFieldOptions(opts ...FieldOption) (*FieldOptions, error) {
 
 		case FieldTypeTimestamp:
 			return nil, ErrTimestampFieldWithKeys
+
+		case FieldTypeFloat:
+			return nil, ErrFloatFieldWithKeys
 		}
 	}
 
+	if fo.JSON && (fo.Type != FieldTypeSet || fo.Keys) {
+		return nil, ErrJSONFieldNotSet
+	}
+
+	if fo.GeoPoint && fo.Type != FieldTypeInt {
+		return nil, ErrGeoPointFieldNotInt
+	}
+
+	if fo.BitVector != 0 && (fo.Type != FieldTypeSet || fo.Keys || fo.BitVector < 0 || fo.BitVector%8 != 0) {
+		return nil, ErrBitVectorFieldNotSet
+	}
+
 	return &fo, nil
 }
 
@@ -2247,7 +2607,7 @@ func (o *FieldOptions) ActuallyTrackingExistence() bool {
 	switch o.Type {
 	case FieldTypeTime:
 		return o.TrackExistence && !o.NoStandardView
-	case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp:
+	case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp, FieldTypeFloat:
 		return false
 	default:
 		return o.TrackExistence
@@ -2261,15 +2621,27 @@ func (o *FieldOptions) MarshalJSON() ([]byte, error) {
 	switch o.Type {
 	case FieldTypeSet, "":
 		return json.Marshal(struct {
-			Type      string `json:"type"`
-			CacheType string `json:"cacheType"`
-			CacheSize uint32 `json:"cacheSize"`
-			Keys      bool   `json:"keys"`
+			Type      string   `json:"type"`
+			CacheType string   `json:"cacheType"`
+			CacheSize uint32   `json:"cacheSize"`
+			Keys      bool     `json:"keys"`
+			BitVector int64    `json:"bitVector,omitempty"`
+			JSON      bool     `json:"json,omitempty"`
+			JSONPaths []string `json:"jsonPaths,omitempty"`
+			NotNull   bool     `json:"notNull,omitempty"`
+			Default   string   `json:"default,omitempty"`
+			Check     string   `json:"check,omitempty"`
 		}{
 			o.Type,
 			o.CacheType,
 			o.CacheSize,
 			o.Keys,
+			o.BitVector,
+			o.JSON,
+			o.JSONPaths,
+			o.NotNull,
+			o.Default,
+			o.Check,
 		})
 	case FieldTypeInt:
 		return json.Marshal(struct {
@@ -2280,6 +2652,10 @@ func (o *FieldOptions) MarshalJSON() ([]byte, error) {
 			Max          pql.Decimal `json:"max"`
 			Keys         bool        `json:"keys"`
 			ForeignIndex string      `json:"foreignIndex"`
+			GeoPoint     bool        `json:"geoPoint,omitempty"`
+			NotNull      bool        `json:"notNull,omitempty"`
+			Default      string      `json:"default,omitempty"`
+			Check        string      `json:"check,omitempty"`
 		}{
 			o.Type,
 			o.Base,
@@ -2288,6 +2664,10 @@ func (o *FieldOptions) MarshalJSON() ([]byte, error) {
 			o.Max,
 			o.Keys,
 			o.ForeignIndex,
+			o.GeoPoint,
+			o.NotNull,
+			o.Default,
+			o.Check,
 		})
 	case FieldTypeDecimal:
 		return json.Marshal(struct {
@@ -2298,6 +2678,9 @@ func (o *FieldOptions) MarshalJSON() ([]byte, error) {
 			Min      pql.Decimal `json:"min"`
 			Max      pql.Decimal `json:"max"`
 			Keys     bool        `json:"keys"`
+			NotNull  bool        `json:"notNull,omitempty"`
+			Default  string      `json:"default,omitempty"`
+			Check    string      `json:"check,omitempty"`
 		}{
 			o.Type,
 			o.Base,
@@ -2306,6 +2689,9 @@ func (o *FieldOptions) MarshalJSON() ([]byte, error) {
 			o.Min,
 			o.Max,
 			o.Keys,
+			o.NotNull,
+			o.Default,
+			o.Check,
 		})
 	case FieldTypeTimestamp:
 		epoch, err := ValToTimestamp(o.TimeUnit, o.Base)
@@ -2320,6 +2706,9 @@ func (o *FieldOptions) MarshalJSON() ([]byte, error) {
 			Min      pql.Decimal `json:"min"`
 			Max      pql.Decimal `json:"max"`
 			TimeUnit string      `json:"timeUnit"`
+			NotNull  bool        `json:"notNull,omitempty"`
+			Default  string      `json:"default,omitempty"`
+			Check    string      `json:"check,omitempty"`
 		}{
 			o.Type,
 			epoch,
@@ -2327,6 +2716,29 @@ func (o *FieldOptions) MarshalJSON() ([]byte, error) {
 			o.Min,
 			o.Max,
 			o.TimeUnit,
+			o.NotNull,
+			o.Default,
+			o.Check,
+		})
+	case FieldTypeFloat:
+		return json.Marshal(struct {
+			Type     string      `json:"type"`
+			Base     int64       `json:"base"`
+			BitDepth uint64      `json:"bitDepth"`
+			Min      pql.Decimal `json:"min"`
+			Max      pql.Decimal `json:"max"`
+			NotNull  bool        `json:"notNull,omitempty"`
+			Default  string      `json:"default,omitempty"`
+			Check    string      `json:"check,omitempty"`
+		}{
+			o.Type,
+			o.Base,
+			o.BitDepth,
+			o.Min,
+			o.Max,
+			o.NotNull,
+			o.Default,
+			o.Check,
 		})
 	case FieldTypeTime:
 		return json.Marshal(struct {
@@ -2335,12 +2747,18 @@ func (o *FieldOptions) MarshalJSON() ([]byte, error) {
 			Keys           bool          `json:"keys"`
 			NoStandardView bool          `json:"noStandardView"`
 			TTL            time.Duration `json:"ttl"`
+			NotNull        bool          `json:"notNull,omitempty"`
+			Default        string        `json:"default,omitempty"`
+			Check          string        `json:"check,omitempty"`
 		}{
 			o.Type,
 			o.TimeQuantum,
 			o.Keys,
 			o.NoStandardView,
 			o.TTL,
+			o.NotNull,
+			o.Default,
+			o.Check,
 		})
 	case FieldTypeMutex:
 		return json.Marshal(struct {
@@ -2348,17 +2766,29 @@ func (o *FieldOptions) MarshalJSON() ([]byte, error) {
 			CacheType string `json:"cacheType"`
 			CacheSize uint32 `json:"cacheSize"`
 			Keys      bool   `json:"keys"`
+			NotNull   bool   `json:"notNull,omitempty"`
+			Default   string `json:"default,omitempty"`
+			Check     string `json:"check,omitempty"`
 		}{
 			o.Type,
 			o.CacheType,
 			o.CacheSize,
 			o.Keys,
+			o.NotNull,
+			o.Default,
+			o.Check,
 		})
 	case FieldTypeBool:
 		return json.Marshal(struct {
-			Type string `json:"type"`
+			Type    string `json:"type"`
+			NotNull bool   `json:"notNull,omitempty"`
+			Default string `json:"default,omitempty"`
+			Check   string `json:"check,omitempty"`
 		}{
 			o.Type,
+			o.NotNull,
+			o.Default,
+			o.Check,
 		})
 	}
 	return nil, errors.Errorf("invalid field type: '%s'", o.Type)
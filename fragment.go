@@ -749,6 +749,48 @@ func (f *fragment) sum(tx Tx, filter *Row, bitDepth uint64) (sum int64, count ui
 	return sum, uint64(c32), nil
 }
 
+// floatSum returns the sum of a given float bsiGroup as well as the number
+// of columns involved. The bits hold the encoding of each float, which can't
+// be summed directly, so each value is reassembled and decoded first.
+func (f *fragment) floatSum(tx Tx, filter *Row, bitDepth uint64) (sum float64, count uint64, err error) {
+	consider, err := f.row(tx, bsiExistsBit)
+	if err != nil {
+		return sum, count, err
+	} else if filter != nil {
+		consider = consider.Intersect(filter)
+	}
+	if !consider.Any() {
+		return 0, 0, nil
+	}
+
+	data := make(map[uint64]uint64)
+	sign, err := f.row(tx, bsiSignBit)
+	if err != nil {
+		return sum, count, err
+	}
+	mergeBits(sign.Intersect(consider), 1<<63, data)
+	for i := uint64(0); i < bitDepth; i++ {
+		bits, err := f.row(tx, bsiOffsetBit+i)
+		if err != nil {
+			return sum, count, err
+		}
+		mergeBits(bits.Intersect(consider), 1<<i, data)
+	}
+
+	// add the values in column order, so that the rounding is the same
+	// every time
+	for _, col := range consider.Columns() {
+		v := data[col]
+		val := int64(v &^ (1 << 63))
+		if v&(1<<63) != 0 {
+			val = -val
+		}
+		sum += ValToFloat(val)
+		count++
+	}
+	return sum, count, nil
+}
+
 // min returns the min of a given bsiGroup as well as the number of columns involved.
 // A bitmap can be passed in to optionally filter the computed columns.
 func (f *fragment) min(tx Tx, filter *Row, bitDepth uint64) (min int64, count uint64, err error) {
@@ -177,6 +177,39 @@ func (ivr *ImportValueRequest) Clone() *ImportValueRequest {
 	return newIVR
 }
 
+// filterColumns drops the entries of ivr whose column ID keep rejects.
+func (ivr *ImportValueRequest) filterColumns(keep func(id uint64) bool) {
+	n := 0
+	for i, id := range ivr.ColumnIDs {
+		if !keep(id) {
+			continue
+		}
+		ivr.ColumnIDs[n] = id
+		switch {
+		case len(ivr.Values) > i:
+			ivr.Values[n] = ivr.Values[i]
+		case len(ivr.FloatValues) > i:
+			ivr.FloatValues[n] = ivr.FloatValues[i]
+		case len(ivr.TimestampValues) > i:
+			ivr.TimestampValues[n] = ivr.TimestampValues[i]
+		case len(ivr.StringValues) > i:
+			ivr.StringValues[n] = ivr.StringValues[i]
+		}
+		n++
+	}
+	ivr.ColumnIDs = ivr.ColumnIDs[:n]
+	switch {
+	case len(ivr.Values) > 0:
+		ivr.Values = ivr.Values[:n]
+	case len(ivr.FloatValues) > 0:
+		ivr.FloatValues = ivr.FloatValues[:n]
+	case len(ivr.TimestampValues) > 0:
+		ivr.TimestampValues = ivr.TimestampValues[:n]
+	case len(ivr.StringValues) > 0:
+		ivr.StringValues = ivr.StringValues[:n]
+	}
+}
+
 // AtomicRecord applies all its Ivr and Ivr atomically, in a Tx.
 // The top level Shard has to agree with Ivr[i].Shard and the Iv[i].Shard
 // for all i included (in Ivr and Ir). The same goes for the top level Index: all records
@@ -318,6 +351,37 @@ func (ir *ImportRequest) Clone() *ImportRequest {
 	return newIR
 }
 
+// filterColumns drops the entries of ir whose column ID keep rejects.
+func (ir *ImportRequest) filterColumns(keep func(id uint64) bool) {
+	n := 0
+	for i, id := range ir.ColumnIDs {
+		if !keep(id) {
+			continue
+		}
+		ir.ColumnIDs[n] = id
+		if len(ir.RowIDs) > i {
+			ir.RowIDs[n] = ir.RowIDs[i]
+		}
+		if len(ir.RowKeys) > i {
+			ir.RowKeys[n] = ir.RowKeys[i]
+		}
+		if len(ir.Timestamps) > i {
+			ir.Timestamps[n] = ir.Timestamps[i]
+		}
+		n++
+	}
+	ir.ColumnIDs = ir.ColumnIDs[:n]
+	if len(ir.RowIDs) > 0 {
+		ir.RowIDs = ir.RowIDs[:n]
+	}
+	if len(ir.RowKeys) > 0 {
+		ir.RowKeys = ir.RowKeys[:n]
+	}
+	if len(ir.Timestamps) > 0 {
+		ir.Timestamps = ir.Timestamps[:n]
+	}
+}
+
 // SortToShards takes an import request which has been translated, but may
 // not be sorted, and turns it into a map from shard IDs to individual import
 // requests. We don't sort the entries within each shard because the correct
@@ -468,6 +532,12 @@ type ImportRoaringShardRequest struct {
 	// that would be because this request is being replayed from a
 	// write log.
 	SuppressLog bool
+
+	// Checkpoint, if set, is stored in the same transaction as Views,
+	// so that an ingester's offsets are committed with the data
+	// imported from them. It's only accepted for IngestCheckpointShard,
+	// so it's only atomic with that shard's data.
+	Checkpoint *IngestCheckpoint
 }
 
 // RoaringUpdate represents the bits to clear and then set in a particular view.
@@ -488,7 +558,8 @@ type RoaringUpdate struct {
 
 	// ClearRecords, when true, denotes that Clear should be
 	// interpreted as a single row which will be subtracted from every
-	// row in this view.
+	// row in this view. If Field is empty, the row is subtracted from
+	// every view of every field in the shard, deleting those records.
 	ClearRecords bool
 }
 
@@ -497,6 +497,9 @@ func (h *Holder) Open() error {
 		index.createdAt = cim.CreatedAt
 		index.owner = cim.Owner
 		index.description = cim.Meta.Description
+		index.retentionField = cim.Meta.RetentionField
+		index.retentionPeriod = cim.Meta.RetentionPeriod
+		index.primaryKey = cim.Meta.PrimaryKey
 
 		err = index.OpenWithSchema(idx)
 		if err != nil {
@@ -995,6 +998,9 @@ func (h *Holder) createIndex(cim *CreateIndexMessage, broadcast bool) (*Index, e
 	index.createdAt = cim.CreatedAt
 	index.owner = cim.Owner
 	index.description = cim.Meta.Description
+	index.retentionField = cim.Meta.RetentionField
+	index.retentionPeriod = cim.Meta.RetentionPeriod
+	index.primaryKey = cim.Meta.PrimaryKey
 
 	if err = index.Open(); err != nil {
 		return nil, errors.Wrap(err, "opening")
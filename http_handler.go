@@ -57,6 +57,10 @@ import (
 const (
 	// HeaderRequestUserID is request userid header
 	HeaderRequestUserID = "X-Request-Userid"
+
+	// HeaderImportValidated marks an import forwarded by another node as
+	// already checked against the field's constraints.
+	HeaderImportValidated = "X-Import-Validated"
 )
 
 // Handler represents an HTTP handler.
@@ -92,6 +96,8 @@ type Handler struct {
 	auth *authn.Auth
 
 	permissions *authz.GroupPermissions
+
+	ingester RecordIngester
 }
 
 // externalPrefixFlag denotes endpoints that are intended to be exposed to clients.
@@ -176,6 +182,15 @@ func OptHandlerFileSystem(fs FileSystem) handlerOption {
 	}
 }
 
+// OptHandlerRecordIngester sets the ingester for records sent to
+// POST /index/{index}/ingest. Without one, that endpoint isn't available.
+func OptHandlerRecordIngester(ingester RecordIngester) handlerOption {
+	return func(h *Handler) error {
+		h.ingester = ingester
+		return nil
+	}
+}
+
 func OptHandlerLogger(logger logger.Logger) handlerOption {
 	return func(h *Handler) error {
 		h.logger = logger
@@ -304,7 +319,8 @@ func (h *Handler) populateValidators() {
 	h.validators["PostField"] = queryValidationSpecRequired()
 	h.validators["DeleteField"] = queryValidationSpecRequired()
 	h.validators["PostImport"] = queryValidationSpecRequired().Optional("clear", "ignoreKeyCheck")
-	h.validators["PostImportAtomicRecord"] = queryValidationSpecRequired().Optional("simPowerLossAfter")
+	h.validators["PostImportAtomicRecord"] = queryValidationSpecRequired().Optional("simPowerLossAfter", "onConflict")
+	h.validators["PostIngest"] = queryValidationSpecRequired().Optional("format", "header", "primaryKeyFields", "idField")
 	h.validators["PostImportRoaring"] = queryValidationSpecRequired().Optional("remote", "clear")
 	h.validators["PostQuery"] = queryValidationSpecRequired().Optional("shards", "excludeColumns", "profile", "remote")
 	h.validators["GetInfo"] = queryValidationSpecRequired()
@@ -503,11 +519,13 @@ func newRouter(handler *Handler) http.Handler {
 	router.HandleFunc("/index/{index}", handler.chkAuthZ(handler.handleGetIndex, authz.Read)).Methods("GET").Name("GetIndex")
 	router.HandleFunc("/index/{index}", handler.chkAuthZ(handler.handlePostIndex, authz.Admin)).Methods("POST").Name("PostIndex")
 	router.HandleFunc("/index/{index}", handler.chkAuthZ(handler.handleDeleteIndex, authz.Admin)).Methods("DELETE").Name("DeleteIndex")
+	router.HandleFunc("/index/{index}/checkpoint/{key}", handler.chkAuthZ(handler.handleGetIngestCheckpoint, authz.Read)).Methods("GET").Name("GetIngestCheckpoint")
 	router.HandleFunc("/index/{index}/dataframe/{shard}", handler.chkAuthZ(handler.handlePostDataframe, authz.Write)).Methods("POST").Name("PostDataframe")
 	router.HandleFunc("/index/{index}/dataframe/{shard}", handler.chkAuthZ(handler.handleGetDataframe, authz.Read)).Methods("GET").Name("GetDataframe")
 	router.HandleFunc("/index/{index}/dataframe", handler.chkAuthZ(handler.handleGetDataframeSchema, authz.Read)).Methods("GET").Name("GetDataframeSchema")
 	router.HandleFunc("/index/{index}/dataframe", handler.chkAuthZ(handler.handleDeleteDataframe, authz.Write)).Methods("DELETE").Name("DeleteDataframe")
 	router.HandleFunc("/index/{index}/field", handler.chkAuthZ(handler.handlePostField, authz.Write)).Methods("POST").Name("PostField")
+	router.HandleFunc("/index/{index}/ingest", handler.chkAuthZ(handler.handlePostIngest, authz.Write)).Methods("POST").Name("PostIngest")
 	router.HandleFunc("/index/{index}/field/", handler.chkAuthZ(handler.handlePostField, authz.Write)).Methods("POST").Name("PostField")
 	router.HandleFunc("/index/{index}/field/{field}/view", handler.chkAuthZ(handler.handleGetView, authz.Admin)).Methods("GET")
 	router.HandleFunc("/index/{index}/field/{field}/view/{view}", handler.chkAuthZ(handler.handleDeleteView, authz.Admin)).Methods("DELETE").Name("DeleteView")
@@ -565,6 +583,11 @@ func newRouter(handler *Handler) http.Handler {
 	router.HandleFunc("/internal/translate/keys", handler.chkAuthN(handler.handlePostTranslateKeys)).Methods("POST").Name("PostTranslateKeys")
 	router.HandleFunc("/internal/translate/ids", handler.chkAuthN(handler.handlePostTranslateIDs)).Methods("POST").Name("PostTranslateIDs")
 	router.HandleFunc("/internal/index/{index}/field/{field}/mutex-check", handler.chkAuthZ(handler.handleInternalGetMutexCheck, authz.Read)).Methods("GET").Name("InternalGetMutexCheck")
+	router.HandleFunc("/internal/index/{index}/field/{field}/write-block", handler.chkAuthN(handler.handlePostFieldWriteBlock)).Methods("POST").Name("PostFieldWriteBlock")
+	router.HandleFunc("/internal/index/{index}/field/{field}/write-block", handler.chkAuthN(handler.handleDeleteFieldWriteBlock)).Methods("DELETE").Name("DeleteFieldWriteBlock")
+	router.HandleFunc("/internal/sql/cursors/{id}", handler.chkAuthN(handler.handleGetCursor)).Methods("GET").Name("GetCursor")
+	router.HandleFunc("/internal/sql/cursors/{id}/fetch", handler.chkAuthN(handler.handlePostCursorFetch)).Methods("POST").Name("PostCursorFetch")
+	router.HandleFunc("/internal/sql/cursors/{id}", handler.chkAuthN(handler.handleDeleteCursor)).Methods("DELETE").Name("DeleteCursor")
 	router.HandleFunc("/internal/index/{index}/field/{field}/remote-available-shards/{shardID}", handler.chkAuthZ(handler.handleDeleteRemoteAvailableShard, authz.Admin)).Methods("DELETE")
 	router.HandleFunc("/internal/index/{index}/shard/{shard}/snapshot", handler.chkAuthZ(handler.handleGetIndexShardSnapshot, authz.Read)).Methods("GET").Name("GetIndexShardSnapshot")
 	router.HandleFunc("/internal/index/{index}/shards", handler.chkAuthZ(handler.handleGetIndexAvailableShards, authz.Read)).Methods("GET").Name("GetIndexAvailableShards")
@@ -578,7 +601,10 @@ func newRouter(handler *Handler) http.Handler {
 	router.HandleFunc("/internal/translate/field/{index}/{field}/keys/find", handler.chkAuthZ(handler.handleFindFieldKeys, authz.Admin)).Methods("POST").Name("FindFieldKeys")
 	router.HandleFunc("/internal/translate/field/{index}/{field}/keys/create", handler.chkAuthZ(handler.handleCreateFieldKeys, authz.Admin)).Methods("POST").Name("CreateFieldKeys")
 	router.HandleFunc("/internal/translate/field/{index}/{field}/keys/like", handler.chkAuthZ(handler.handleMatchField, authz.Read)).Methods("POST").Name("MatchFieldKeys")
+	router.HandleFunc("/internal/translate/index/{index}/keys/prefix", handler.chkAuthZ(handler.handleMatchIndexKeys, authz.Read)).Methods("POST").Name("MatchIndexKeys")
 
+	router.HandleFunc("/internal/indexes/hold/{id}", handler.chkAuthN(handler.handlePostHoldIndexes)).Methods("POST").Name("PostHoldIndexes")
+	router.HandleFunc("/internal/indexes/hold/{id}", handler.chkAuthN(handler.handleDeleteHoldIndexes)).Methods("DELETE").Name("DeleteHoldIndexes")
 	router.HandleFunc("/internal/idalloc/reserve", handler.chkAuthN(handler.handleReserveIDs)).Methods("POST").Name("ReserveIDs")
 	router.HandleFunc("/internal/idalloc/commit", handler.chkAuthN(handler.handleCommitIDs)).Methods("POST").Name("CommitIDs")
 	router.HandleFunc("/internal/idalloc/restore", handler.chkAuthN(handler.handleRestoreIDAlloc)).Methods("POST").Name("RestoreIDAllocData")
@@ -659,22 +685,29 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) chkInternal(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if h.auth != nil {
-			secret, ok := r.Header["X-Feature-Key"]
-			var secretString string
-			if ok {
-				secretString = secret[0]
-			}
-			decodedString, err := hex.DecodeString(secretString)
-			if err != nil || !ok || !bytes.Equal(decodedString, h.auth.SecretKey()) {
-				http.Error(w, "internal secret key validation failed", http.StatusUnauthorized)
-				return
-			}
+		if !h.isInternal(r) {
+			http.Error(w, "internal secret key validation failed", http.StatusUnauthorized)
+			return
 		}
 		handler.ServeHTTP(w, r)
 	}
 }
 
+// isInternal returns true if r comes from another node, which is to say it
+// has the secret key. Without authentication, every request is trusted.
+func (h *Handler) isInternal(r *http.Request) bool {
+	if h.auth == nil {
+		return true
+	}
+	secret, ok := r.Header["X-Feature-Key"]
+	var secretString string
+	if ok {
+		secretString = secret[0]
+	}
+	decodedString, err := hex.DecodeString(secretString)
+	return err == nil && ok && bytes.Equal(decodedString, h.auth.SecretKey())
+}
+
 func (h *Handler) chkAllowedNetworks(r *http.Request) (bool, context.Context) {
 	// for every request, get IP of the request and check against configured IPs
 	reqIP := GetIP(r)
@@ -1407,6 +1440,9 @@ func (h *Handler) handlePostSQLPlanOperator(w http.ResponseWriter, r *http.Reque
 		writeError(err)
 		return
 	}
+	if closer, ok := iter.(io.Closer); ok {
+		defer closer.Close()
+	}
 	// read schema & write to response.
 	columns := rootOperator.Schema()
 	b, err := wireprotocol.WriteSchema(columns)
@@ -1436,7 +1472,9 @@ func (h *Handler) handlePostSQLPlanOperator(w http.ResponseWriter, r *http.Reque
 
 // handlePostSQL handles /sql requests
 // supports a ?plan=true|false parameter to send back the plan in the
-// query response
+// query response, and a ?page_size=n parameter to return only the first n
+// rows, along with a cursor-id if there are more. The next page can then be
+// fetched with ?cursor_id=id&page_size=n (and no body)
 func (h *Handler) handlePostSQL(w http.ResponseWriter, r *http.Request) {
 	includePlan := false
 	includePlanValue := r.URL.Query().Get("plan")
@@ -1449,6 +1487,34 @@ func (h *Handler) handlePostSQL(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	pageSize := 0
+	pageSizeValue := r.URL.Query().Get("page_size")
+	if len(pageSizeValue) > 0 {
+		var err error
+		pageSize, err = strconv.Atoi(pageSizeValue)
+		if err != nil {
+			h.writeBadRequest(w, r, err)
+			return
+		}
+		if pageSize < 1 {
+			h.writeBadRequest(w, r, errors.Errorf("invalid page_size: %d", pageSize))
+			return
+		}
+	}
+
+	cursorID := r.URL.Query().Get("cursor_id")
+	if len(cursorID) > 0 {
+		if pageSize == 0 {
+			h.writeBadRequest(w, r, errors.New("page_size is required with cursor_id"))
+			return
+		}
+		userID, _ := fbcontext.UserID(r.Context())
+		if _, err := h.api.Cursors().GetCursor(r.Context(), userID, cursorID); err != nil {
+			h.writeBadRequest(w, r, err)
+			return
+		}
+	}
+
 	// get the body
 	b, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -1541,14 +1607,33 @@ func (h *Handler) handlePostSQL(w http.ResponseWriter, r *http.Request) {
 	}
 
 	sql := string(b)
+	if len(cursorID) > 0 {
+		// reading the next page from an existing cursor
+		sql = fmt.Sprintf("FETCH NEXT %d FROM %s", pageSize, cursorID)
+	}
 	rootOperator, err := h.api.CompilePlan(ctx, sql)
 	if err != nil {
 		writeError(err, false)
 		return
 	}
 
-	// Get a query iterator.
-	iter, err := rootOperator.Iterator(ctx, nil)
+	// Get a query iterator. If we are paging, the iterator is owned by a
+	// cursor, and we only read the first page from it.
+	var iter types.RowIterator
+	var cursor *Cursor
+	if pageSize > 0 && len(cursorID) == 0 {
+		cursor, err = NewCursor(ctx, newCursorID(requestID.String()), sql, rootOperator.Schema(), func(ctx context.Context) (types.RowIterator, error) {
+			return rootOperator.Iterator(ctx, nil)
+		})
+		if err == nil {
+			iter = cursor.Page(pageSize)
+		}
+	} else {
+		iter, err = rootOperator.Iterator(ctx, nil)
+		if closer, ok := iter.(io.Closer); ok {
+			defer closer.Close()
+		}
+	}
 	if err != nil {
 		writeError(err, false)
 		writeWarnings(rootOperator.Warnings())
@@ -1617,6 +1702,31 @@ func (h *Handler) handlePostSQL(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("]"))
 
 	writeError(rowErr, true)
+
+	// if there are rows left, park the cursor so they can be fetched later
+	if len(cursorID) > 0 {
+		// the cursor is closed by the fetch once it has been read to the end
+		userID, _ := fbcontext.UserID(ctx)
+		if _, err := h.api.Cursors().GetCursor(ctx, userID, cursorID); err == nil {
+			w.Write([]byte(`,"cursor-id":`))
+			cursorIDValue, _ := json.Marshal(cursorID)
+			w.Write(cursorIDValue)
+		}
+	} else if cursor != nil {
+		if rowErr == nil && !cursor.Done() {
+			if err := h.api.server.SystemLayer.Cursors().AddCursor(cursor); err != nil {
+				cursor.Close()
+				writeError(err, true)
+			} else {
+				w.Write([]byte(`,"cursor-id":`))
+				cursorID, _ := json.Marshal(cursor.ID)
+				w.Write(cursorID)
+			}
+		} else {
+			cursor.Close()
+		}
+	}
+
 	writeWarnings(rootOperator.Warnings())
 	writePlan(rootOperator.Plan())
 }
@@ -1691,6 +1801,117 @@ type getIndexAvailableShardsResponse struct {
 	Shards []uint64 `json:"shards"`
 }
 
+// handleGetIngestCheckpoint handles GET /index/{index}/checkpoint/{key}
+// requests, which must be sent to a node holding IngestCheckpointShard.
+func (h *Handler) handleGetIngestCheckpoint(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	checkpoint, err := h.api.IngestCheckpoint(r.Context(), vars["index"], vars["key"])
+	if err != nil {
+		switch errors.Cause(err).(type) {
+		case NotFoundError:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case BadRequestError:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(checkpoint); err != nil {
+		h.logger.Errorf("write checkpoint response error: %s", err)
+	}
+}
+
+// handlePostIngest handles POST /index/{index}/ingest requests, ingesting
+// the NDJSON or CSV records in the body.
+func (h *Handler) handlePostIngest(w http.ResponseWriter, r *http.Request) {
+	if h.ingester == nil {
+		http.Error(w, "ingest is not supported by this server", http.StatusNotImplemented)
+		return
+	}
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	indexName := mux.Vars(r)["index"]
+	if _, err := h.api.Index(r.Context(), indexName); err != nil {
+		if errors.Is(err, ErrIndexNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format == "" {
+		format = ingestFormatOf(r.Header.Get("Content-Type"))
+	}
+	switch format {
+	case IngestFormatNDJSON, IngestFormatCSV:
+	default:
+		http.Error(w, fmt.Sprintf("unsupported format %q: set Content-Type to application/x-ndjson or text/csv, or format to %s or %s", format, IngestFormatNDJSON, IngestFormatCSV), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if r.ContentLength > MaxIngestRequestSize {
+		http.Error(w, fmt.Sprintf("request body of %d bytes exceeds the maximum of %d", r.ContentLength, MaxIngestRequestSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	access, _ := getTokens(r)
+	req := &IngestRequest{
+		Index:     indexName,
+		Format:    format,
+		Body:      http.MaxBytesReader(w, r.Body, MaxIngestRequestSize),
+		IDField:   q.Get("idField"),
+		AuthToken: access,
+	}
+	if header := q.Get("header"); header != "" {
+		req.Header = strings.Split(header, ",")
+	}
+	if pk := q.Get("primaryKeyFields"); pk != "" {
+		req.PrimaryKeyFields = strings.Split(pk, ",")
+	}
+
+	resp, err := h.ingester.Ingest(r.Context(), req)
+	if err != nil {
+		if errors.As(err, &BadRequestError{}) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("write ingest response error: %s", err)
+	}
+}
+
+// ingestFormatOf returns the ingest format for a Content-Type, or "" if
+// there isn't one.
+func ingestFormatOf(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return IngestFormatNDJSON
+	case "text/csv":
+		return IngestFormatCSV
+	}
+	return ""
+}
+
 // handleGetShardsMax handles GET /internal/shards/max requests.
 func (h *Handler) handleGetShardsMax(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
@@ -2017,6 +2238,7 @@ func fieldOptionsToFunctionalOpts(opt fieldOptions) []FieldOption {
 			opt.Min = &min
 		}
 		fos = append(fos, OptFieldTypeInt(opt.Min.ToInt64(0), opt.Max.ToInt64(0)))
+		fos = appendTimeQuantumOption(fos, opt)
 	case FieldTypeDecimal:
 		scale := int64(0)
 		if opt.Scale != nil {
@@ -2056,12 +2278,15 @@ func fieldOptionsToFunctionalOpts(opt fieldOptions) []FieldOption {
 			}
 		}
 		fos = append(fos, OptFieldTypeDecimal(scale, minmax...))
+		fos = appendTimeQuantumOption(fos, opt)
 	case FieldTypeTimestamp:
 		if opt.Epoch == nil {
 			epoch := DefaultEpoch
 			opt.Epoch = &epoch
 		}
 		fos = append(fos, OptFieldTypeTimestamp(opt.Epoch.UTC(), *opt.TimeUnit))
+	case FieldTypeFloat:
+		fos = append(fos, OptFieldTypeFloat())
 	case FieldTypeTime:
 		if opt.TTL != nil {
 			fos = append(fos, OptFieldTypeTime(*opt.TimeQuantum, *opt.TTL, opt.NoStandardView))
@@ -2084,6 +2309,19 @@ func fieldOptionsToFunctionalOpts(opt fieldOptions) []FieldOption {
 	return fos
 }
 
+// appendTimeQuantumOption appends the time quantum option for an int or
+// decimal field, if one was given.
+func appendTimeQuantumOption(fos []FieldOption, opt fieldOptions) []FieldOption {
+	if opt.TimeQuantum == nil {
+		return fos
+	}
+	ttl := "0"
+	if opt.TTL != nil {
+		ttl = *opt.TTL
+	}
+	return append(fos, OptFieldTimeQuantum(*opt.TimeQuantum, ttl))
+}
+
 // handlePostField handles POST /field request.
 func (h *Handler) handlePostField(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
@@ -2228,10 +2466,8 @@ func (o *fieldOptions) validate() error {
 			return NewBadRequestError(errors.New("cacheType does not apply to field type int"))
 		} else if o.CacheSize != nil {
 			return NewBadRequestError(errors.New("cacheSize does not apply to field type int"))
-		} else if o.TimeQuantum != nil {
-			return NewBadRequestError(errors.New("timeQuantum does not apply to field type int"))
-		} else if o.TTL != nil {
-			return NewBadRequestError(errors.New("ttl does not apply to field type int"))
+		} else if o.TTL != nil && o.TimeQuantum == nil {
+			return NewBadRequestError(errors.New("ttl requires a timeQuantum on field type int"))
 		}
 	case FieldTypeDecimal:
 		if o.Scale == nil {
@@ -2240,10 +2476,8 @@ func (o *fieldOptions) validate() error {
 			return NewBadRequestError(errors.New("cacheType does not apply to field type int"))
 		} else if o.CacheSize != nil {
 			return NewBadRequestError(errors.New("cacheSize does not apply to field type int"))
-		} else if o.TimeQuantum != nil {
-			return NewBadRequestError(errors.New("timeQuantum does not apply to field type int"))
-		} else if o.TTL != nil {
-			return NewBadRequestError(errors.New("ttl does not apply to field type int"))
+		} else if o.TTL != nil && o.TimeQuantum == nil {
+			return NewBadRequestError(errors.New("ttl requires a timeQuantum on field type decimal"))
 		} else if o.ForeignIndex != nil && o.Type == FieldTypeDecimal {
 			return NewBadRequestError(errors.New("decimal field cannot be a foreign key"))
 		}
@@ -2263,6 +2497,24 @@ func (o *fieldOptions) validate() error {
 		} else if o.ForeignIndex != nil {
 			return NewBadRequestError(errors.New("timestamp field cannot be a foreign key"))
 		}
+	case FieldTypeFloat:
+		if o.CacheType != nil {
+			return NewBadRequestError(errors.New("cacheType does not apply to field type float"))
+		} else if o.CacheSize != nil {
+			return NewBadRequestError(errors.New("cacheSize does not apply to field type float"))
+		} else if o.Min != nil {
+			return NewBadRequestError(errors.New("min does not apply to field type float"))
+		} else if o.Max != nil {
+			return NewBadRequestError(errors.New("max does not apply to field type float"))
+		} else if o.Scale != nil {
+			return NewBadRequestError(errors.New("scale does not apply to field type float"))
+		} else if o.TimeQuantum != nil {
+			return NewBadRequestError(errors.New("timeQuantum does not apply to field type float"))
+		} else if o.TTL != nil {
+			return NewBadRequestError(errors.New("ttl does not apply to field type float"))
+		} else if o.ForeignIndex != nil {
+			return NewBadRequestError(errors.New("float field cannot be a foreign key"))
+		}
 	case FieldTypeTime:
 		if o.CacheType != nil {
 			return NewBadRequestError(errors.New("cacheType does not apply to field type time"))
@@ -3112,8 +3364,14 @@ func (h *Handler) handlePostImportAtomicRecord(w http.ResponseWriter, r *http.Re
 			http.Error(w, err.Error(), http.StatusBadRequest)
 		}
 	}
+	conflict, err := ParseImportConflict(q.Get("onConflict"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	opt := func(o *ImportOptions) error {
 		o.SimPowerLossAfter = loss
+		o.Conflict = conflict
 		return nil
 	}
 
@@ -3181,9 +3439,14 @@ func (h *Handler) handlePostImport(w http.ResponseWriter, r *http.Request) {
 	doClear := q.Get("clear") == "true"
 	doIgnoreKeyCheck := q.Get("ignoreKeyCheck") == "true"
 
+	// IgnoreKeyCheck only skips key translation; the values are checked
+	// against the field's constraints unless another node already has.
+	doValidated := r.Header.Get(HeaderImportValidated) == "true" && h.isInternal(r)
+
 	opts := []ImportOption{
 		OptImportOptionsClear(doClear),
 		OptImportOptionsIgnoreKeyCheck(doIgnoreKeyCheck),
+		optImportOptionsValidated(doValidated),
 	}
 
 	// Read entire body.
@@ -3193,7 +3456,7 @@ func (h *Handler) handlePostImport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Unmarshal request based on field type.
-	if field.Type() == FieldTypeInt || field.Type() == FieldTypeDecimal || field.Type() == FieldTypeTimestamp {
+	if field.Type() == FieldTypeInt || field.Type() == FieldTypeDecimal || field.Type() == FieldTypeTimestamp || field.Type() == FieldTypeFloat {
 		// Field type: Int
 		// Marshal into request object.
 		req := &ImportValueRequest{}
@@ -3726,6 +3989,189 @@ func (h *Handler) handleMatchField(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *Handler) handleMatchIndexKeys(w http.ResponseWriter, r *http.Request) {
+	// Verify output type.
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	indexName, ok := mux.Vars(r)["index"]
+	if !ok {
+		http.Error(w, "index name is required", http.StatusBadRequest)
+		return
+	}
+
+	bd, err := readBody(r)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	matches, err := h.api.matchPrimaryIndexKeys(indexName, string(bd))
+	if err != nil {
+		http.Error(w, "failed to match prefix", http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(matches)
+	if err != nil {
+		http.Error(w, "encoding result", http.StatusBadRequest)
+		return
+	}
+}
+
+// handlePostHoldIndexes handles POST /internal/indexes/hold/{id} requests,
+// which hold off queries against indexes on this node for another node.
+func (h *Handler) handlePostHoldIndexes(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "Unsupported media type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	bd, err := readBody(r)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	var req HoldIndexesRequest
+	if err := json.Unmarshal(bd, &req); err != nil {
+		http.Error(w, "failed to decode request", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.api.HoldIndexes(r.Context(), mux.Vars(r)["id"], req.Indexes, req.Timeout); err != nil {
+		http.Error(w, fmt.Sprintf("holding indexes: %v", err), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDeleteHoldIndexes handles DELETE /internal/indexes/hold/{id}
+// requests, which release the indexes held for id.
+func (h *Handler) handleDeleteHoldIndexes(w http.ResponseWriter, r *http.Request) {
+	if err := h.api.ReleaseIndexes(r.Context(), mux.Vars(r)["id"]); err != nil {
+		http.Error(w, fmt.Sprintf("releasing indexes: %v", err), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePostFieldWriteBlock handles POST
+// /internal/index/{index}/field/{field}/write-block requests, which block
+// writes to a field while it's altered.
+func (h *Handler) handlePostFieldWriteBlock(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "Unsupported media type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	bd, err := readBody(r)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	var req BlockFieldWriteRequest
+	if err := json.Unmarshal(bd, &req); err != nil {
+		http.Error(w, "failed to decode request", http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(r)
+	if err := h.api.BlockFieldWrites(r.Context(), vars["index"], vars["field"], req.Timeout); err != nil {
+		http.Error(w, fmt.Sprintf("blocking writes: %v", err), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDeleteFieldWriteBlock handles DELETE
+// /internal/index/{index}/field/{field}/write-block requests, which let
+// writes to a field through again.
+func (h *Handler) handleDeleteFieldWriteBlock(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := h.api.UnblockFieldWrites(r.Context(), vars["index"], vars["field"]); err != nil {
+		http.Error(w, fmt.Sprintf("unblocking writes: %v", err), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetCursor handles GET /internal/sql/cursors/{id} requests, which
+// return the schema of a cursor open on this node.
+func (h *Handler) handleGetCursor(w http.ResponseWriter, r *http.Request) {
+	userID, _ := fbcontext.UserID(r.Context())
+	cursor, err := h.api.server.SystemLayer.Cursors().GetCursor(r.Context(), userID, mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	b, err := wireprotocol.WriteSchema(cursor.Schema)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(b)
+	w.Write(wireprotocol.WriteDone())
+}
+
+// handlePostCursorFetch handles POST /internal/sql/cursors/{id}/fetch?n=n
+// requests, which read up to n rows from a cursor open on this node for
+// another node.
+func (h *Handler) handlePostCursorFetch(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.URL.Query().Get("n"))
+	if err != nil || n < 1 {
+		http.Error(w, fmt.Sprintf("invalid n: %s", r.URL.Query().Get("n")), http.StatusBadRequest)
+		return
+	}
+	userID, _ := fbcontext.UserID(r.Context())
+	cursor, err := h.api.server.SystemLayer.Cursors().GetCursor(r.Context(), userID, mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	rows, done, err := cursor.Fetch(n)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	b, err := wireprotocol.WriteSchema(cursor.Schema)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	buf.Write(b)
+	for _, row := range rows {
+		b, err := wireprotocol.WriteRow(row, cursor.Schema)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		buf.Write(b)
+	}
+	buf.Write(wireprotocol.WriteDone())
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("X-Cursor-Done", strconv.FormatBool(done))
+	w.Write(buf.Bytes())
+}
+
+// handleDeleteCursor handles DELETE /internal/sql/cursors/{id} requests,
+// which close a cursor open on this node.
+func (h *Handler) handleDeleteCursor(w http.ResponseWriter, r *http.Request) {
+	userID, _ := fbcontext.UserID(r.Context())
+	if err := h.api.server.SystemLayer.Cursors().CloseCursor(r.Context(), userID, mux.Vars(r)["id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *Handler) handleReserveIDs(w http.ResponseWriter, r *http.Request) {
 	// Verify input and output types
 	if r.Header.Get("Content-Type") != "application/json" {
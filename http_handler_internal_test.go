@@ -146,8 +146,13 @@ func TestFieldOptionValidation(t *testing.T) {
 		}}},
 		{json: `{"options": {"type": "int", "min": 0, "max": 1000, "cacheType": "ranked"}}`, err: "cacheType does not apply to field type int"},
 		{json: `{"options": {"type": "int", "min": 0, "max": 1000, "cacheSize": 1000}}`, err: "cacheSize does not apply to field type int"},
-		{json: `{"options": {"type": "int", "min": 0, "max": 1000, "timeQuantum": "YMD"}}`, err: "timeQuantum does not apply to field type int"},
-		{json: `{"options": {"type": "int", "min": 0, "max": 1000, "ttl": "1h"}}`, err: "ttl does not apply to field type int"},
+		{json: `{"options": {"type": "int", "min": 0, "max": 1001, "timeQuantum": "YMD"}}`, expected: postFieldRequest{Options: fieldOptions{
+			Type:        FieldTypeInt,
+			Min:         decimalPtr(pql.NewDecimal(0, 0)),
+			Max:         decimalPtr(pql.NewDecimal(1001, 0)),
+			TimeQuantum: &timeQuantum,
+		}}},
+		{json: `{"options": {"type": "int", "min": 0, "max": 1000, "ttl": "1h"}}`, err: "ttl requires a timeQuantum on field type int"},
 
 		// FieldType: Time
 		{json: `{"options": {"type": "time"}}`, err: "timeQuantum is required for field type time"},
@@ -603,6 +603,66 @@ func TestHandlerSQL(t *testing.T) {
 	}
 }
 
+// TestHandlerSQLPaging tests that a POST /sql request with a page_size returns
+// its results a page at a time, with a cursor-id to fetch the next page.
+func TestHandlerSQLPaging(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	m := c.GetPrimary()
+	sqlURL := fmt.Sprintf("%s/sql", m.URL())
+
+	for _, sql := range []string{
+		"create table paging (_id id, v int)",
+		"insert into paging (_id, v) values (1, 10), (2, 20), (3, 30), (4, 40), (5, 50)",
+	} {
+		resp := test.Do(t, "POST", sqlURL, sql)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("post sql, status: %d, body=%s", resp.StatusCode, resp.Body)
+		}
+	}
+
+	type page struct {
+		Data     [][]interface{} `json:"data"`
+		CursorID string          `json:"cursor-id"`
+		Error    string          `json:"error"`
+	}
+	fetch := func(url, sql string) page {
+		resp := test.Do(t, "POST", url, sql)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("post sql, status: %d, body=%s", resp.StatusCode, resp.Body)
+		}
+		var p page
+		assert.NoError(t, json.Unmarshal([]byte(resp.Body), &p))
+		assert.Empty(t, p.Error)
+		return p
+	}
+
+	p := fetch(sqlURL+"?page_size=2", "select _id, v from paging")
+	assert.Equal(t, [][]interface{}{{float64(1), float64(10)}, {float64(2), float64(20)}}, p.Data)
+	assert.NotEmpty(t, p.CursorID)
+
+	cursorID := p.CursorID
+	p = fetch(sqlURL+"?page_size=2&cursor_id="+cursorID, "")
+	assert.Equal(t, [][]interface{}{{float64(3), float64(30)}, {float64(4), float64(40)}}, p.Data)
+	assert.Equal(t, cursorID, p.CursorID)
+
+	// the last page has no cursor-id, and the cursor is gone
+	p = fetch(sqlURL+"?page_size=2&cursor_id="+cursorID, "")
+	assert.Equal(t, [][]interface{}{{float64(5), float64(50)}}, p.Data)
+	assert.Empty(t, p.CursorID)
+
+	resp := test.Do(t, "POST", sqlURL+"?page_size=2&cursor_id="+cursorID, "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got: %d, body=%s", http.StatusBadRequest, resp.StatusCode, resp.Body)
+	}
+
+	// a result which fits in one page has no cursor-id either
+	p = fetch(sqlURL+"?page_size=10", "select _id from paging where v > 30")
+	assert.Len(t, p.Data, 2)
+	assert.Empty(t, p.CursorID)
+}
+
 func TestTranslationHandlers(t *testing.T) {
 	// reusable data for the tests
 	nameBytes, err := json.Marshal([]string{"a", "b", "c"})
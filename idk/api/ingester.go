@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/pkg/errors"
+)
+
+// ingestBatchSize is the most records ingested in a batch for a request to
+// POST /index/{index}/ingest.
+const ingestBatchSize = 1 << 20
+
+// RecordIngester ingests the records sent to POST /index/{index}/ingest
+// with idk, importing them through the node of api. It sets up an idk
+// ingester for each request, which reads the request's records, ingests
+// them in one batch if there are no more than ingestBatchSize, and stops.
+type RecordIngester struct {
+	api *pilosa.API
+	tls idk.TLSConfig
+
+	mu      sync.Mutex
+	indexes map[string]*indexIngester
+}
+
+// indexIngester holds what the requests for one index share: the key IDs
+// are allocated under, and the lock which has them ingested one at a time,
+// so that they can share it. Each request is ingested by an idk ingester of
+// its own, which only lasts as long as the request.
+type indexIngester struct {
+	mu               sync.Mutex
+	idAllocKeyPrefix string
+}
+
+// NewRecordIngester returns a RecordIngester which ingests records through
+// the node of api, connecting to it with tls.
+func NewRecordIngester(api *pilosa.API, tls idk.TLSConfig) *RecordIngester {
+	return &RecordIngester{
+		api:     api,
+		tls:     tls,
+		indexes: make(map[string]*indexIngester),
+	}
+}
+
+// index returns the state shared by the requests for the named index,
+// which lasts as long as ri does.
+func (ri *RecordIngester) index(name string) *indexIngester {
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+	ii, ok := ri.indexes[name]
+	if !ok {
+		// The key is the same for every request to this node, so IDs
+		// reserved for a request which didn't commit them are reused by the
+		// next one rather than leaked.
+		ii = &indexIngester{idAllocKeyPrefix: fmt.Sprintf("http-ingest-%s", ri.api.NodeID())}
+		ri.indexes[name] = ii
+	}
+	return ii
+}
+
+// Ingest implements pilosa.RecordIngester.
+func (ri *RecordIngester) Ingest(ctx context.Context, req *pilosa.IngestRequest) (*pilosa.IngestResponse, error) {
+	idx, err := ri.api.Index(ctx, req.Index)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case idx.Keys() && len(req.PrimaryKeyFields) == 0:
+		return nil, pilosa.NewBadRequestError(errors.Errorf("index %s has keys, so primaryKeyFields must name the fields of the records' keys", req.Index))
+	case !idx.Keys() && len(req.PrimaryKeyFields) > 0:
+		return nil, pilosa.NewBadRequestError(errors.Errorf("index %s doesn't have keys, so records can't have primaryKeyFields", req.Index))
+	}
+
+	m := idk.NewMain()
+	m.PilosaHosts = []string{ri.api.Node().URI.String()}
+	m.Index = req.Index
+	m.AuthToken = req.AuthToken
+	m.BatchSize = ingestBatchSize
+	m.UseShardTransactionalEndpoint = true
+	m.Pprof = ""
+	m.Stats = ""
+	m.PackBools = ""
+	m.TLS = ri.tls
+
+	ii := ri.index(req.Index)
+	switch {
+	case len(req.PrimaryKeyFields) > 0:
+		m.PrimaryKeyFields = req.PrimaryKeyFields
+	case req.IDField != "":
+		m.IDField = req.IDField
+	default:
+		// Records without IDs are given new ones by the cluster's ID
+		// allocator.
+		m.AutoGenerate = true
+		m.ExternalGenerate = true
+		m.IDAllocKeyPrefix = ii.idAllocKeyPrefix
+	}
+
+	ii.mu.Lock()
+	defer ii.mu.Unlock()
+	return IngestRecords(ctx, m, req.Format, req.Header, req.Body)
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/server"
+	"github.com/featurebasedb/featurebase/v3/test"
+)
+
+// Ensure records posted to an index are ingested the way idk would ingest
+// them, with an error for each record which couldn't be.
+func TestRecordIngester(t *testing.T) {
+	var ingester *RecordIngester
+	cluster := test.MustRunUnsharedCluster(t, 1, []server.CommandOption{
+		server.OptCommandRecordIngester(func(api *pilosa.API, tls server.TLSConfig) pilosa.RecordIngester {
+			ingester = NewRecordIngester(api, idk.TLSConfig{})
+			return ingester
+		}),
+	})
+	defer cluster.Close()
+	cmd := cluster.GetNode(0)
+	h := cmd.Handler.(*pilosa.Handler).Handler
+
+	ingest := func(t *testing.T, path, contentType, body string) (int, pilosa.IngestResponse) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := test.MustNewHTTPRequest("POST", path, strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+		h.ServeHTTP(w, r)
+		var resp pilosa.IngestResponse
+		if w.Code == http.StatusOK {
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+		}
+		return w.Code, resp
+	}
+
+	test.Do(t, "POST", cmd.URL()+"/index/people", `{"options": {"keys": true}}`)
+	test.Do(t, "POST", cmd.URL()+"/index/people/field/age", `{"options": {"type": "int", "min": 0, "max": 150}}`)
+	test.Do(t, "POST", cmd.URL()+"/index/events", `{"options": {"keys": false}}`)
+
+	t.Run("NDJSON", func(t *testing.T) {
+		code, resp := ingest(t, "/index/people/ingest?primaryKeyFields=id", "application/x-ndjson",
+			`{"id": "a", "age": 30, "city": "nyc"}
+{"id": "b", "age": "old"}
+not json
+
+{"id": "c", "age": 40, "city": "sf", "tags": ["x", "y"]}
+`)
+		if code != http.StatusOK {
+			t.Fatalf("unexpected status code: %d", code)
+		}
+		if resp.Ingested != 2 || len(resp.Errors) != 2 {
+			t.Fatalf("unexpected response: %+v", resp)
+		}
+		if e := resp.Errors[0]; e.Line != 2 || e.Field != "age" {
+			t.Fatalf("unexpected error for bad value: %+v", e)
+		}
+		if e := resp.Errors[1]; e.Line != 3 || !strings.Contains(e.Error, "decoding JSON") {
+			t.Fatalf("unexpected error for bad JSON: %+v", e)
+		}
+		cmd.QueryExpect(t, "people", "", `Count(All())`, `{"results":[2]}`)
+		cmd.QueryExpect(t, "people", "", `Count(Row(city="sf"))`, `{"results":[1]}`)
+		cmd.QueryExpect(t, "people", "", `Count(Row(age > 35))`, `{"results":[1]}`)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		code, resp := ingest(t, "/index/events/ingest?idField=id", "text/csv",
+			"id,kind,n__Int\n1,click,5\n2,view,x\n3,click,7\n")
+		if code != http.StatusOK {
+			t.Fatalf("unexpected status code: %d", code)
+		}
+		if resp.Ingested != 2 || len(resp.Errors) != 1 || resp.Errors[0].Line != 3 || resp.Errors[0].Field != "n" {
+			t.Fatalf("unexpected response: %+v", resp)
+		}
+		cmd.QueryExpect(t, "events", "", `Row(kind="click")`, `{"results":[{"columns":[1,3]}]}`)
+	})
+
+	t.Run("AutoGenerate", func(t *testing.T) {
+		code, resp := ingest(t, "/index/events/ingest?format=csv", "", "kind\nbuy\nbuy\n")
+		if code != http.StatusOK || resp.Ingested != 2 || len(resp.Errors) != 0 {
+			t.Fatalf("unexpected response: %d %+v", code, resp)
+		}
+		cmd.QueryExpect(t, "events", "", `Count(Row(kind="buy"))`, `{"results":[2]}`)
+
+		// Every request for the index shares its allocation key, which gives
+		// the next request new IDs.
+		events := ingester.index("events")
+		code, resp = ingest(t, "/index/events/ingest?format=csv", "", "kind\nbuy\n")
+		if code != http.StatusOK || resp.Ingested != 1 || len(resp.Errors) != 0 {
+			t.Fatalf("unexpected response: %d %+v", code, resp)
+		}
+		if ingester.index("events") != events {
+			t.Fatal("expected the index's state to be kept")
+		}
+		cmd.QueryExpect(t, "events", "", `Count(Row(kind="buy"))`, `{"results":[3]}`)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := ingester.Ingest(ctx, &pilosa.IngestRequest{
+			Index:   "events",
+			Format:  pilosa.IngestFormatCSV,
+			IDField: "id",
+			Body:    strings.NewReader("id,kind\n9,skip\n"),
+		})
+		if err == nil {
+			t.Fatal("expected an error ingesting with a canceled context")
+		}
+		cmd.QueryExpect(t, "events", "", `Count(Row(kind="skip"))`, `{"results":[0]}`)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		body := "id,kind\n1," + strings.Repeat("a", pilosa.MaxIngestRequestSize)
+		if code, _ := ingest(t, "/index/events/ingest?idField=id", "text/csv", body); code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("expected status code %d, got %d", http.StatusRequestEntityTooLarge, code)
+		}
+
+		// A body without a length is cut off at the maximum.
+		w := httptest.NewRecorder()
+		r := test.MustNewHTTPRequest("POST", "/index/events/ingest?idField=id", strings.NewReader(body))
+		r.Header.Set("Content-Type", "text/csv")
+		r.ContentLength = -1
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "request body too large") {
+			t.Fatalf("unexpected response: %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, test := range []struct {
+			path, contentType string
+			code              int
+		}{
+			{"/index/nope/ingest", "text/csv", http.StatusNotFound},
+			{"/index/people/ingest", "text/csv", http.StatusBadRequest},
+			{"/index/events/ingest?primaryKeyFields=id", "text/csv", http.StatusBadRequest},
+			{"/index/events/ingest", "application/xml", http.StatusUnsupportedMediaType},
+		} {
+			if code, _ := ingest(t, test.path, test.contentType, "id\n1\n"); code != test.code {
+				t.Errorf("%s: expected status code %d, got %d", test.path, test.code, code)
+			}
+		}
+	})
+}
@@ -0,0 +1,364 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	pilosaclient "github.com/featurebasedb/featurebase/v3/client"
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/pkg/errors"
+)
+
+// IngestRecords ingests the NDJSON objects or CSV rows read from in into
+// the index of m, which must be set up to identify the records. The fields
+// of the records are taken from header, which is in idk's header format,
+// then from the existing fields of the index, and are otherwise inferred:
+// from an NDJSON key's first value which isn't null, or as strings for CSV
+// columns whose names (from the first row) aren't header specs themselves.
+//
+// A record which can't be decoded or ingested is left out, and its error is
+// returned in the response rather than stopping the others from being
+// ingested. Ingesting stops once ctx is done.
+func IngestRecords(ctx context.Context, m *idk.Main, format string, header []string, in io.Reader) (*pilosa.IngestResponse, error) {
+	log := m.Log()
+	given := make(map[string]idk.Field, len(header))
+	for _, spec := range header {
+		fld, err := idk.HeaderToField(spec, log)
+		if err != nil {
+			return nil, pilosa.NewBadRequestError(errors.Wrapf(err, "invalid header (%v)", spec))
+		}
+		given[fld.Name()] = fld
+	}
+
+	var rows *recordRows
+	var err error
+	switch format {
+	case pilosa.IngestFormatNDJSON:
+		rows, err = readNDJSONRecords(in)
+	case pilosa.IngestFormatCSV:
+		rows, err = readCSVRecords(in, given, log)
+	default:
+		err = errors.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, pilosa.NewBadRequestError(err)
+	}
+
+	if len(rows.rows) == 0 {
+		return &pilosa.IngestResponse{}, nil
+	} else if len(rows.rows) < m.BatchSize {
+		// The records have all been read, so there's no use for a bigger
+		// batch.
+		m.BatchSize = len(rows.rows)
+	}
+
+	errs := &recordErrors{}
+	src := &recordSource{ctx: ctx, rows: rows}
+	m.DeadLetters = errs
+	m.NewSource = func() (idk.Source, error) {
+		schema, err := m.SchemaManager.Schema()
+		if err != nil {
+			return nil, errors.Wrap(err, "getting schema")
+		}
+		src.setFields(rows.fields(given, schema.Index(m.Index).Fields(), m.IDField, log))
+		return src, nil
+	}
+	if err := m.RunContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// Records which couldn't be decoded are reported as they're read, ahead
+	// of those which couldn't be ingested.
+	sort.SliceStable(errs.errs, func(i, j int) bool { return errs.errs[i].Line < errs.errs[j].Line })
+	return &pilosa.IngestResponse{
+		Ingested: uint64(len(rows.rows) - len(errs.errs)),
+		Errors:   errs.errs,
+	}, nil
+}
+
+// recordRows are the records of a request, decoded up front so that the
+// fields can be worked out from all of them.
+type recordRows struct {
+	columns []string
+	rows    []recordRow
+
+	// infer returns the field for a column which the header and index
+	// don't give, or nil if it can't be inferred.
+	infer func(column int) (idk.Field, error)
+}
+
+// recordRow is a record, with its value for each column, or the error
+// decoding it.
+type recordRow struct {
+	line    uint64
+	values  []interface{}
+	payload []byte
+	err     error
+}
+
+// fields returns the fields of the columns, from those given in the header,
+// the existing fields of the index, or by inference. A column whose field
+// can't be inferred is left out, with a nil field.
+func (r *recordRows) fields(given map[string]idk.Field, existing map[string]*pilosaclient.Field, idField string, log logger.Logger) []idk.Field {
+	fields := make([]idk.Field, len(r.columns))
+	for i, column := range r.columns {
+		if fld, ok := given[column]; ok {
+			fields[i] = fld
+			continue
+		}
+		if column == idField {
+			fields[i] = idk.IDField{NameVal: column}
+			continue
+		}
+		if pFld, ok := existing[column]; ok {
+			if fld := fieldFromPilosa(column, pFld); fld != nil {
+				fields[i] = fld
+				continue
+			}
+		}
+		fld, err := r.infer(i)
+		if err != nil || fld == nil {
+			log.Printf("leaving out column %s, since its type can't be inferred (%v); give it in the header to ingest it", column, err)
+			continue
+		}
+		fields[i] = fld
+	}
+	return fields
+}
+
+// fieldFromPilosa returns the field which ingests values into a FeatureBase
+// field, or nil if there isn't one which can be worked out from its
+// options alone.
+func fieldFromPilosa(name string, pFld *pilosaclient.Field) idk.Field {
+	opts := pFld.Opts()
+	switch opts.Type() {
+	case pilosaclient.FieldTypeSet, pilosaclient.FieldTypeTime:
+		quantum := string(opts.TimeQuantum())
+		if opts.Keys() {
+			return idk.StringArrayField{NameVal: name, Quantum: quantum}
+		}
+		return idk.IDArrayField{NameVal: name, Quantum: quantum}
+	case pilosaclient.FieldTypeMutex:
+		if opts.Keys() {
+			return idk.StringField{NameVal: name, Mutex: true}
+		}
+		return idk.IDField{NameVal: name, Mutex: true}
+	case pilosaclient.FieldTypeBool:
+		return idk.BoolField{NameVal: name}
+	case pilosaclient.FieldTypeInt:
+		return idk.IntField{NameVal: name, ForeignIndex: opts.ForeignIndex()}
+	case pilosaclient.FieldTypeDecimal:
+		return idk.DecimalField{NameVal: name, Scale: opts.Scale()}
+	}
+	return nil
+}
+
+// readNDJSONRecords reads an object from each line which isn't blank. A
+// key's field is inferred from its first value which isn't null.
+func readNDJSONRecords(in io.Reader) (*recordRows, error) {
+	r := &recordRows{}
+	byName := make(map[string]int)
+	var objs []map[string]interface{}
+
+	reader := bufio.NewReader(in)
+	for line := uint64(1); ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, errors.Wrap(err, "reading records")
+		}
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 {
+			row := recordRow{line: line, payload: trimmed}
+			var obj map[string]interface{}
+			dec := json.NewDecoder(bytes.NewReader(trimmed))
+			dec.UseNumber()
+			if derr := dec.Decode(&obj); derr != nil {
+				row.err = errors.Wrap(derr, "decoding JSON")
+			} else if obj == nil {
+				row.err = errors.New("not a JSON object")
+			}
+			for key := range obj {
+				if _, ok := byName[key]; !ok {
+					byName[key] = len(r.columns)
+					r.columns = append(r.columns, key)
+				}
+			}
+			r.rows = append(r.rows, row)
+			objs = append(objs, obj)
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+
+	for i, obj := range objs {
+		values := make([]interface{}, len(r.columns))
+		for key, v := range obj {
+			values[byName[key]] = v
+		}
+		r.rows[i].values = values
+	}
+	r.infer = func(column int) (idk.Field, error) {
+		for _, row := range r.rows {
+			if v := row.values[column]; v != nil {
+				return idk.InferJSONField(r.columns[column], v)
+			}
+		}
+		return nil, nil
+	}
+	return r, nil
+}
+
+// readCSVRecords reads the rows after the first, which names the columns.
+// A column name which is a header spec, like "price__Decimal_2", is added
+// to given.
+func readCSVRecords(in io.Reader, given map[string]idk.Field, log logger.Logger) (*recordRows, error) {
+	reader := csv.NewReader(in)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading CSV header")
+	}
+
+	r := &recordRows{columns: make([]string, len(header))}
+	for i, column := range header {
+		fld, err := idk.HeaderToField(column, log)
+		switch {
+		case err == nil:
+			r.columns[i] = fld.Name()
+			given[fld.Name()] = fld
+		case errors.Is(err, idk.ErrNoFieldSpec):
+			r.columns[i] = column
+		default:
+			return nil, errors.Wrapf(err, "invalid CSV header (%v)", column)
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var row recordRow
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			row.line, row.err = uint64(perr.StartLine), err
+		} else if err != nil {
+			return nil, errors.Wrap(err, "reading CSV")
+		} else {
+			line, _ := reader.FieldPos(0)
+			row.line = uint64(line)
+			row.values = make([]interface{}, len(record))
+			for i, v := range record {
+				row.values[i] = v
+			}
+		}
+		r.rows = append(r.rows, row)
+	}
+	r.infer = func(column int) (idk.Field, error) {
+		return idk.StringField{NameVal: r.columns[column]}, nil
+	}
+	return r, nil
+}
+
+// recordSource delivers the records of a request to an idk ingester.
+type recordSource struct {
+	ctx  context.Context
+	rows *recordRows
+	next int
+
+	// fields are the fields of the columns, which are nil for those left
+	// out, and schema is the fields without them.
+	fields []idk.Field
+	schema []idk.Field
+}
+
+func (s *recordSource) setFields(fields []idk.Field) {
+	s.fields = fields
+	s.schema = make([]idk.Field, 0, len(fields))
+	for _, fld := range fields {
+		if fld != nil {
+			s.schema = append(s.schema, fld)
+		}
+	}
+}
+
+// Record returns the next record's values for the fields of the schema.
+func (s *recordSource) Record() (idk.Record, error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.next >= len(s.rows.rows) {
+		return nil, io.EOF
+	}
+	row := s.rows.rows[s.next]
+	s.next++
+	if row.err != nil {
+		return nil, &idk.BadRecordError{Offset: row.line, Payload: row.payload, Err: row.err}
+	}
+
+	data := make([]interface{}, 0, len(s.schema))
+	for i, fld := range s.fields {
+		if fld == nil {
+			continue
+		}
+		var v interface{}
+		if i < len(row.values) {
+			v = idk.JSONValue(fld, row.values[i])
+		}
+		data = append(data, v)
+	}
+	return ingestRecord{row: row, data: data}, nil
+}
+
+func (s *recordSource) Schema() []idk.Field {
+	return s.schema
+}
+
+func (s *recordSource) Close() error {
+	return nil
+}
+
+type ingestRecord struct {
+	row  recordRow
+	data []interface{}
+}
+
+func (r ingestRecord) Commit(ctx context.Context) error { return nil }
+
+func (r ingestRecord) Data() []interface{} { return r.data }
+
+func (r ingestRecord) Schema() interface{} { return nil }
+
+// StreamOffset locates the record by its line, for its error.
+func (r ingestRecord) StreamOffset() (string, uint64) { return "", r.row.line }
+
+func (r ingestRecord) Payload() []byte { return r.row.payload }
+
+// recordErrors is a dead-letter sink which keeps the errors of the records
+// which couldn't be ingested.
+type recordErrors struct {
+	mu   sync.Mutex
+	errs []pilosa.IngestRecordError
+}
+
+func (e *recordErrors) Write(dl *idk.DeadLetter) error {
+	rerr := pilosa.IngestRecordError{Field: dl.Field, Error: dl.Error}
+	if dl.Offset != nil {
+		rerr.Line = *dl.Offset
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errs = append(e.errs, rerr)
+	return nil
+}
+
+func (e *recordErrors) Flush(ctx context.Context) error { return nil }
+
+func (e *recordErrors) Close() error { return nil }
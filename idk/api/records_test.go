@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	pilosaclient "github.com/featurebasedb/featurebase/v3/client"
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/logger"
+)
+
+func TestRecordFields(t *testing.T) {
+	idx := pilosaclient.NewSchema().Index("i")
+	idx.Field("age", pilosaclient.OptFieldTypeInt(0, 150))
+	idx.Field("city", pilosaclient.OptFieldTypeMutex(pilosaclient.CacheTypeRanked, 1000), pilosaclient.OptFieldKeys(true))
+
+	t.Run("NDJSON", func(t *testing.T) {
+		rows, err := readNDJSONRecords(strings.NewReader(`{"id": 1, "age": 30, "city": "nyc", "note": null}
+
+{"id": 2, "price": "1.5", "tags": ["a"], "note": null}
+[1, 2]
+{"id": 3, "score": 1.5}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		given := map[string]idk.Field{"price": idk.DecimalField{NameVal: "price", Scale: 2}}
+		fields := rows.fields(given, idx.Fields(), "id", logger.NopLogger)
+		exp := map[string]idk.Field{
+			"id":    idk.IDField{NameVal: "id"},
+			"age":   idk.IntField{NameVal: "age"},
+			"city":  idk.StringField{NameVal: "city", Mutex: true},
+			"note":  nil,
+			"price": idk.DecimalField{NameVal: "price", Scale: 2},
+			"tags":  idk.StringArrayField{NameVal: "tags"},
+			"score": idk.FloatField{NameVal: "score"},
+		}
+		if len(fields) != len(exp) {
+			t.Fatalf("expected %d fields, got %+v", len(exp), fields)
+		}
+		for i, column := range rows.columns {
+			if !reflect.DeepEqual(fields[i], exp[column]) {
+				t.Errorf("column %s: expected %+v, got %+v", column, exp[column], fields[i])
+			}
+		}
+
+		src := &recordSource{ctx: context.Background(), rows: rows}
+		src.setFields(fields)
+		if len(src.Schema()) != len(exp)-1 {
+			t.Fatalf("expected the column without a field to be left out, got %+v", src.Schema())
+		}
+		var lines []uint64
+		for {
+			rec, err := src.Record()
+			if err == io.EOF {
+				break
+			}
+			var bre *idk.BadRecordError
+			if errors.As(err, &bre) {
+				if bre.Offset != 4 {
+					t.Errorf("expected the bad record on line 4, got %d", bre.Offset)
+				}
+				continue
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if len(rec.Data()) != len(src.Schema()) {
+				t.Fatalf("expected a value for each field, got %v", rec.Data())
+			}
+			_, line := rec.(idk.OffsetStreamRecord).StreamOffset()
+			lines = append(lines, line)
+		}
+		if !reflect.DeepEqual(lines, []uint64{1, 3, 5}) {
+			t.Fatalf("unexpected lines: %v", lines)
+		}
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		given := map[string]idk.Field{}
+		rows, err := readCSVRecords(strings.NewReader("id,age,name,n__Int_0_10\n1,30,x,5\n2,31\n3,32,y,\"7\n"), given, logger.NopLogger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fields := rows.fields(given, idx.Fields(), "", logger.NopLogger)
+		exp := []idk.Field{
+			idk.StringField{NameVal: "id"},
+			idk.IntField{NameVal: "age"},
+			idk.StringField{NameVal: "name"},
+			idk.IntField{NameVal: "n", DestNameVal: "n", Min: int64Ptr(0), Max: int64Ptr(10)},
+		}
+		if !reflect.DeepEqual(fields, exp) {
+			t.Fatalf("expected %+v, got %+v", exp, fields)
+		}
+		if len(rows.rows) != 3 {
+			t.Fatalf("expected 3 rows, got %+v", rows.rows)
+		}
+		for i, line := range []uint64{2, 3, 4} {
+			if row := rows.rows[i]; row.line != line || (row.err != nil) != (i > 0) {
+				t.Errorf("row %d: expected line %d, got %+v", i, line, row)
+			}
+		}
+	})
+}
+
+func int64Ptr(v int64) *int64 { return &v }
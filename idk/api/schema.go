@@ -157,6 +157,9 @@ func (f schemaField) applyToPilosa(idx *pilosaclient.Index) error {
 	case "decimal":
 		opts = []pilosaclient.FieldOption{pilosaclient.OptFieldTypeDecimal(int64(f.FieldOptions.Scale))}
 
+	case "float":
+		opts = []pilosaclient.FieldOption{pilosaclient.OptFieldTypeFloat()}
+
 	case "timestamp":
 		epoch := time.Unix(0, 0)
 		if f.FieldOptions.Epoch != "" {
@@ -175,7 +178,7 @@ func (f schemaField) applyToPilosa(idx *pilosaclient.Index) error {
 		opts = []pilosaclient.FieldOption{pilosaclient.OptFieldTypeTimestamp(epoch, unit)}
 
 	default:
-		return errors.Errorf(`invalid field-type %q, it must be "id", "string", "bool", "int", "decimal" or "timestamp"`, f.FieldType)
+		return errors.Errorf(`invalid field-type %q, it must be "id", "string", "bool", "int", "decimal", "float" or "timestamp"`, f.FieldType)
 	}
 
 	idx.Field(f.FieldName, opts...)
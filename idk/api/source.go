@@ -295,6 +295,30 @@ func IngestJSON(index *pilosaclient.Index, m idk.Main, in io.Reader) error {
 				},
 			}
 
+		case pilosaclient.FieldTypeFloat:
+			idkSchema = append(idkSchema, idk.FloatField{
+				NameVal: name,
+			})
+			fieldMappers[name] = mapper{
+				idx: i,
+				mapper: func(v interface{}) (interface{}, error) {
+					var raw string
+					switch v := v.(type) {
+					case json.Number:
+						raw = string(v)
+					case string:
+						raw = v
+					default:
+						return nil, TypeError{
+							Expected: typeDescriptionFloat,
+							Value:    v,
+						}
+					}
+
+					return strconv.ParseFloat(raw, 64)
+				},
+			}
+
 		/*
 				// Pilosa unfortunately does not return the epoch to us.
 				// As a result, this does not currently work.
@@ -355,6 +379,7 @@ const (
 	typeDescriptionStringSet = "set of " + typeDescriptionString + "s"
 	typeDescriptionInt       = "integer"
 	typeDescriptionDecimal   = "decimal"
+	typeDescriptionFloat     = "float"
 )
 
 func (t TypeError) Error() string {
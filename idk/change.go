@@ -0,0 +1,97 @@
+package idk
+
+import (
+	"strconv"
+
+	pilosabatch "github.com/featurebasedb/featurebase/v3/batch"
+	"github.com/pkg/errors"
+)
+
+// changeSet holds the records changed by the change records in a batch.
+// The batch clears them in the same transaction as it imports its values
+// for their shards, so that the batch's values replace what they held
+// rather than being added to it.
+type changeSet struct {
+	seen    map[string]struct{}
+	deletes int
+}
+
+func newChangeSet() *changeSet {
+	return &changeSet{seen: make(map[string]struct{})}
+}
+
+// Len returns the number of records in the set.
+func (c *changeSet) Len() int {
+	return len(c.seen)
+}
+
+// Has reports whether the record with the given ID, which is a key or an
+// integer ID as set on a batch row, is already in the set. A record can only
+// change once per batch, since the batch can't tell which of two sets of
+// values came last.
+func (c *changeSet) Has(id interface{}) bool {
+	key, err := changeKey(id)
+	if err != nil {
+		return false
+	}
+	_, ok := c.seen[key]
+	return ok
+}
+
+// Add adds the record with the given ID to the set. A record which is
+// deleted is counted, since there's no row for it in the batch.
+func (c *changeSet) Add(id interface{}, deleted bool) error {
+	key, err := changeKey(id)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.seen[key]; ok {
+		return errors.Errorf("record %s already changed in this batch", key)
+	}
+	c.seen[key] = struct{}{}
+	if deleted {
+		c.deletes++
+	}
+	return nil
+}
+
+func (c *changeSet) reset() {
+	c.seen = make(map[string]struct{})
+	c.deletes = 0
+}
+
+// changeKey returns the set key for a row ID.
+func changeKey(id interface{}) (string, error) {
+	switch id := id.(type) {
+	case []byte:
+		return string(id), nil
+	case string:
+		return id, nil
+	case uint64:
+		return strconv.FormatUint(id, 10), nil
+	default:
+		return "", errors.Errorf("change records must have a primary key or ID field, got ID %v of type %[1]T", id)
+	}
+}
+
+// recordClearingBatch is a batch which can clear records in the same
+// transaction as it imports its values.
+type recordClearingBatch interface {
+	ClearRecord(id interface{}) error
+}
+
+// clearChanged has the batch clear the record with the given ID, which a
+// change record replaces or deletes, when it's imported.
+func (m *Main) clearChanged(batch pilosabatch.RecordBatch, id interface{}) error {
+	if m.useController() {
+		return errors.New("change records are not supported when ingesting through a controller")
+	}
+	if !m.UseShardTransactionalEndpoint {
+		return errors.New("change records require --use-shard-transactional-endpoint")
+	}
+	cb, ok := batch.(recordClearingBatch)
+	if !ok {
+		return errors.Errorf("batch type %T doesn't support change records", batch)
+	}
+	return errors.Wrap(cb.ClearRecord(id), "clearing changed record")
+}
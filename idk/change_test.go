@@ -0,0 +1,52 @@
+package idk
+
+import (
+	"testing"
+)
+
+func TestChangeSet(t *testing.T) {
+	c := newChangeSet()
+	key := []byte(`a"b`)
+	if err := c.Add(key, false); err != nil {
+		t.Fatalf("adding key: %v", err)
+	}
+	// the batch row's ID may be reused, so the key must have been copied
+	key[0] = 'z'
+	if !c.Has([]byte(`a"b`)) || c.Has(key) {
+		t.Errorf("unexpected keys in change set: %v", c.seen)
+	}
+	if err := c.Add("c", true); err != nil {
+		t.Fatalf("adding key: %v", err)
+	}
+	if err := c.Add("c", false); err == nil {
+		t.Errorf("expected error adding the same record twice")
+	}
+	if c.Len() != 2 || c.deletes != 1 {
+		t.Errorf("unexpected length %d or deletes %d", c.Len(), c.deletes)
+	}
+
+	c.reset()
+	if c.Len() != 0 || c.Has("c") {
+		t.Errorf("expected empty change set after reset")
+	}
+	if err := c.Add(uint64(7), false); err != nil {
+		t.Fatalf("adding ID: %v", err)
+	}
+	if !c.Has(uint64(7)) || c.Len() != 1 {
+		t.Errorf("expected ID 7 in change set: %v", c.seen)
+	}
+	if err := c.Add(nil, false); err == nil {
+		t.Errorf("expected error adding a record without an ID")
+	}
+}
+
+func TestClearChanged(t *testing.T) {
+	m := NewMain()
+	if err := m.clearChanged(nil, uint64(1)); err == nil {
+		t.Errorf("expected an error clearing a record without the shard-transactional endpoint")
+	}
+	m.UseShardTransactionalEndpoint = true
+	if err := m.clearChanged(nil, uint64(1)); err == nil {
+		t.Errorf("expected an error clearing a record from a batch which can't")
+	}
+}
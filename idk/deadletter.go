@@ -0,0 +1,387 @@
+package idk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/pkg/errors"
+	segmentio "github.com/segmentio/kafka-go"
+)
+
+// DeadLetter is a record which could not be ingested, as it is written to a
+// dead-letter sink. It holds enough to find where the record came from, to
+// tell why it was rejected, and to ingest it again once the problem has been
+// fixed.
+type DeadLetter struct {
+	Time  time.Time `json:"time"`
+	Index string    `json:"index"`
+
+	// Stream and Offset locate the record in its source, if the source
+	// tracks offsets.
+	Stream string  `json:"stream,omitempty"`
+	Offset *uint64 `json:"offset,omitempty"`
+
+	// Field is the field whose value couldn't be ingested, if the error
+	// could be traced to one.
+	Field string `json:"field,omitempty"`
+	Error string `json:"error"`
+
+	// Schema and Data are the record as it was decoded by the source. The
+	// schema is in the same form as a JSON header, with each field's path
+	// being its name.
+	Schema []RawField    `json:"schema,omitempty"`
+	Data   []interface{} `json:"data,omitempty"`
+
+	// Payload is the record as the source received it, if the source keeps
+	// it. A payload which isn't JSON is kept in PayloadBytes instead.
+	Payload      json.RawMessage `json:"payload,omitempty"`
+	PayloadBytes []byte          `json:"payloadBytes,omitempty"`
+}
+
+// DeadLetterSink is somewhere to write records which could not be
+// ingested. Dead letters must be durable once Flush returns. Sinks are safe
+// for concurrent use.
+type DeadLetterSink interface {
+	Write(dl *DeadLetter) error
+	Flush(ctx context.Context) error
+	Close() error
+}
+
+// BadRecordError is returned from Source.Record when a record can't be
+// decoded, but the source can carry on with the records after it. If a
+// dead-letter sink is configured, the record is written to it; otherwise
+// BadRecordError is an error like any other.
+type BadRecordError struct {
+	Stream  string
+	Offset  uint64
+	Payload []byte
+	Err     error
+}
+
+func (e *BadRecordError) Error() string {
+	return fmt.Sprintf("bad record at %s:%d: %v", e.Stream, e.Offset, e.Err)
+}
+
+func (e *BadRecordError) Unwrap() error { return e.Err }
+
+// OpenDeadLetterSink opens the dead-letter sink at location, which is
+// either kafka://<host:port>[,<host:port>...]/<topic>, s3://<bucket>/<prefix>
+// or the path of a local file.
+func OpenDeadLetterSink(location string, s3Region string) (DeadLetterSink, error) {
+	switch {
+	case strings.HasPrefix(location, "kafka://"):
+		u, err := url.Parse(location)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing dead-letter sink %s", location)
+		}
+		topic := strings.TrimPrefix(u.Path, "/")
+		if u.Host == "" || topic == "" {
+			return nil, errors.Errorf("dead-letter sink %s must name brokers and a topic", location)
+		}
+		return &kafkaDeadLetterSink{
+			writer: &segmentio.Writer{
+				Addr:     segmentio.TCP(strings.Split(u.Host, ",")...),
+				Topic:    topic,
+				Balancer: &segmentio.LeastBytes{},
+			},
+		}, nil
+	case strings.HasPrefix(location, "s3://"):
+		u, err := url.Parse(location)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing dead-letter sink %s", location)
+		}
+		config := &aws.Config{}
+		if s3Region != "" {
+			config.Region = aws.String(s3Region)
+		}
+		sess, err := session.NewSession(config)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating S3 session")
+		}
+		return &s3DeadLetterSink{
+			uploader: s3manager.NewUploader(sess),
+			bucket:   u.Host,
+			prefix:   strings.TrimPrefix(u.Path, "/"),
+		}, nil
+	default:
+		path := strings.TrimPrefix(location, "file://")
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, errors.Wrap(err, "opening dead-letter file")
+		}
+		return &fileDeadLetterSink{file: f, w: bufio.NewWriter(f)}, nil
+	}
+}
+
+// fileDeadLetterSink appends dead letters to a local NDJSON file.
+type fileDeadLetterSink struct {
+	mu   sync.Mutex
+	file *os.File
+	w    *bufio.Writer
+}
+
+func (s *fileDeadLetterSink) Write(dl *DeadLetter) error {
+	line, err := marshalDeadLetter(dl)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(line)
+	return errors.Wrap(err, "writing dead letter")
+}
+
+func (s *fileDeadLetterSink) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.w.Flush(); err != nil {
+		return errors.Wrap(err, "flushing dead letters")
+	}
+	return errors.Wrap(s.file.Sync(), "syncing dead letters")
+}
+
+func (s *fileDeadLetterSink) Close() error {
+	if err := s.Flush(context.Background()); err != nil {
+		s.file.Close()
+		return err
+	}
+	return errors.Wrap(s.file.Close(), "closing dead-letter file")
+}
+
+// kafkaDeadLetterSink produces dead letters to a Kafka topic, one message
+// each, keyed by the stream they came from.
+type kafkaDeadLetterSink struct {
+	mu      sync.Mutex
+	writer  *segmentio.Writer
+	pending []segmentio.Message
+}
+
+func (s *kafkaDeadLetterSink) Write(dl *DeadLetter) error {
+	value, err := marshalDeadLetter(dl)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, segmentio.Message{Key: []byte(dl.Stream), Value: value})
+	return nil
+}
+
+func (s *kafkaDeadLetterSink) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) == 0 {
+		return nil
+	}
+	if err := s.writer.WriteMessages(ctx, s.pending...); err != nil {
+		return errors.Wrap(err, "producing dead letters")
+	}
+	s.pending = s.pending[:0]
+	return nil
+}
+
+func (s *kafkaDeadLetterSink) Close() error {
+	err := s.Flush(context.Background())
+	if cerr := s.writer.Close(); err == nil {
+		err = errors.Wrap(cerr, "closing dead-letter producer")
+	}
+	return err
+}
+
+// s3DeadLetterSink uploads the dead letters written between flushes as an
+// NDJSON object under a prefix.
+type s3DeadLetterSink struct {
+	mu       sync.Mutex
+	uploader *s3manager.Uploader
+	bucket   string
+	prefix   string
+	buf      bytes.Buffer
+	n        int
+}
+
+func (s *s3DeadLetterSink) Write(dl *DeadLetter) error {
+	line, err := marshalDeadLetter(dl)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buf.Write(line)
+	return nil
+}
+
+func (s *s3DeadLetterSink) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.buf.Len() == 0 {
+		return nil
+	}
+	s.n++
+	key := fmt.Sprintf("%s%s-%d.ndjson", s.prefix, time.Now().UTC().Format("20060102T150405.000000000Z"), s.n)
+	_, err := s.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(s.buf.Bytes()),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "uploading dead letters to s3://%s/%s", s.bucket, key)
+	}
+	s.buf.Reset()
+	return nil
+}
+
+func (s *s3DeadLetterSink) Close() error {
+	return s.Flush(context.Background())
+}
+
+func marshalDeadLetter(dl *DeadLetter) ([]byte, error) {
+	line, err := json.Marshal(dl)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding dead letter")
+	}
+	return append(line, '\n'), nil
+}
+
+// newDeadLetter returns the dead letter for a record of the given schema
+// which was rejected with err.
+func (m *Main) newDeadLetter(rec Record, schema []Field, err error) *DeadLetter {
+	dl := &DeadLetter{
+		Time:  time.Now().UTC(),
+		Index: m.Index,
+		Error: err.Error(),
+	}
+	if osr, ok := rec.(OffsetStreamRecord); ok {
+		stream, offset := osr.StreamOffset()
+		dl.Stream, dl.Offset = stream, &offset
+	}
+	if pr, ok := rec.(PayloadRecord); ok {
+		dl.setPayload(pr.Payload())
+	}
+
+	data := rec.Data()
+	for i, field := range schema {
+		if i >= len(data) {
+			break
+		}
+		raw, ok := fieldToRawField(field)
+		if !ok {
+			continue
+		}
+		dl.Schema = append(dl.Schema, raw)
+		dl.Data = append(dl.Data, deadLetterValue(data[i]))
+
+		// The first field whose value can't be converted is the one the
+		// record was rejected for.
+		if dl.Field == "" {
+			if _, ferr := field.PilosafyVal(data[i]); ferr != nil {
+				dl.Field = field.DestName()
+			}
+		}
+	}
+	return dl
+}
+
+// badRecordDeadLetter returns the dead letter for a record which the source
+// couldn't decode.
+func (m *Main) badRecordDeadLetter(bre *BadRecordError) *DeadLetter {
+	offset := bre.Offset
+	dl := &DeadLetter{
+		Time:   time.Now().UTC(),
+		Index:  m.Index,
+		Stream: bre.Stream,
+		Offset: &offset,
+		Error:  bre.Err.Error(),
+	}
+	dl.setPayload(bre.Payload)
+	return dl
+}
+
+// setPayload keeps payload in the dead letter, as it is if it's JSON.
+func (dl *DeadLetter) setPayload(payload []byte) {
+	switch {
+	case len(payload) == 0:
+	case json.Valid(payload):
+		dl.Payload = json.RawMessage(payload)
+	default:
+		dl.PayloadBytes = payload
+	}
+}
+
+// deadLetterValue returns a value as it should be written to JSON so that
+// the field it belongs to can interpret it again.
+func deadLetterValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case []byte:
+		return string(v)
+	case [][]byte:
+		strs := make([]string, len(v))
+		for i, b := range v {
+			strs[i] = string(b)
+		}
+		return strs
+	case pql.Decimal:
+		return v.String()
+	case DeleteSentinel:
+		return nil
+	default:
+		return val
+	}
+}
+
+// fieldToRawField returns the JSON header form of field, with the field's
+// name as its path, and false if the field can't be expressed that way.
+func fieldToRawField(field Field) (RawField, bool) {
+	var typ string
+	switch field.(type) {
+	case IDField:
+		typ = "id"
+	case IDArrayField:
+		typ = "ids"
+	case StringField:
+		typ = "string"
+	case StringArrayField:
+		typ = "strings"
+	case BoolField:
+		typ = "bool"
+	case RecordTimeField:
+		typ = "recordTime"
+	case IntField:
+		typ = "int"
+	case DecimalField:
+		typ = "decimal"
+	case FloatField:
+		typ = "float"
+	case SignedIntBoolKeyField:
+		typ = "signedIntBoolKey"
+	case DateIntField:
+		typ = "dateInt"
+	case TimestampField:
+		typ = "timestamp"
+	case LookupTextField:
+		typ = "lookupText"
+	default:
+		return RawField{}, false
+	}
+	config, err := json.Marshal(field)
+	if err != nil {
+		return RawField{}, false
+	}
+	return RawField{
+		Name:   field.Name(),
+		Path:   []string{field.Name()},
+		Type:   typ,
+		Config: config,
+	}, true
+}
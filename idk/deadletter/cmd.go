@@ -0,0 +1,37 @@
+package deadletter
+
+import (
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/pkg/errors"
+)
+
+type Main struct {
+	idk.Main           `flag:"!embed"`
+	Files              []string `help:"Dead-letter files, directories, or s3://<bucket>/<prefix> URIs to replay."`
+	Header             string   `help:"Optional path to a JSON header to decode the dead letters with, in place of the schema stored in them. May be a path on the local filesystem, or an S3 URI."`
+	S3Region           string   `help:"S3 Region, used when files or the header are S3 URIs. Alternatively, use environment variable AWS_REGION."`
+	AllowMissingFields bool     `help:"Will proceed with ingest even if a field is missing from a record but specified in the header. Default false"`
+}
+
+func NewMain() *Main {
+	m := &Main{
+		Main: *idk.NewMain(),
+	}
+	m.Main.Namespace = "ingester_deadletter"
+	m.Concurrency = 1
+	m.NewSource = func() (idk.Source, error) {
+		source := NewSource()
+		source.Files = m.Files
+		source.Header = m.Header
+		source.S3Region = m.S3Region
+		source.AllowMissingFields = m.AllowMissingFields
+		source.Log = m.Main.Log()
+
+		err := source.Open()
+		if err != nil {
+			return nil, errors.Wrap(err, "opening source")
+		}
+		return source, nil
+	}
+	return m
+}
@@ -0,0 +1,351 @@
+// Package deadletter replays the records written to an idk dead-letter sink,
+// so that they can be ingested again once whatever rejected them has been
+// fixed.
+package deadletter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/pkg/errors"
+)
+
+// Source reads dead letters from local NDJSON files or S3 objects and
+// delivers the records they hold.
+//
+// Without a Header, each record is decoded with the schema stored alongside
+// it. With a Header, the header's paths are looked up in the record's
+// original payload if that was a JSON object, and otherwise in the record's
+// decoded fields by name.
+type Source struct {
+	// Files are local files or directories, or s3://<bucket>/<prefix> URIs
+	// under which every object is read.
+	Files []string
+
+	// Header is an optional file or S3 URI containing a JSON header, which
+	// replaces the schema stored in the dead letters.
+	Header   string
+	S3Region string
+
+	AllowMissingFields bool
+	Log                logger.Logger
+
+	headerSchema []idk.Field
+	headerPaths  idk.PathTable
+
+	schema    []idk.Field
+	paths     idk.PathTable
+	schemaRaw []byte
+
+	locations []string
+	current   io.ReadCloser
+	reader    *bufio.Reader
+	name      string
+	line      int
+}
+
+// NewSource gets a new Source
+func NewSource() *Source {
+	return &Source{
+		Log: logger.NopLogger,
+	}
+}
+
+// Open parses the header, if there is one, and finds the files to read.
+func (s *Source) Open() error {
+	if len(s.Files) == 0 {
+		return errors.New("must provide at least one file, directory or S3 prefix to replay")
+	}
+	if s.Header != "" {
+		header, err := s.readFileOrURL(s.Header)
+		if err != nil {
+			return errors.Wrap(err, "reading header")
+		}
+		s.headerSchema, s.headerPaths, err = idk.ParseHeader(header)
+		if err != nil {
+			return errors.Wrap(err, "processing header")
+		}
+	}
+	for _, name := range s.Files {
+		locations, err := s.list(name)
+		if err != nil {
+			return errors.Wrapf(err, "listing %s", name)
+		}
+		s.locations = append(s.locations, locations...)
+	}
+	return nil
+}
+
+// Record returns the next dead letter's record.
+func (s *Source) Record() (idk.Record, error) {
+	for {
+		if s.reader == nil {
+			if len(s.locations) == 0 {
+				return nil, io.EOF
+			}
+			if err := s.openNext(); err != nil {
+				return nil, err
+			}
+		}
+		line, err := s.reader.ReadBytes('\n')
+		if err == io.EOF {
+			s.closeCurrent()
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", s.name)
+		}
+		s.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		return s.decode(line)
+	}
+}
+
+func (s *Source) decode(line []byte) (idk.Record, error) {
+	var dl idk.DeadLetter
+	if err := json.Unmarshal(line, &dl); err != nil {
+		return nil, errors.Wrapf(err, "decoding dead letter at %s:%d", s.name, s.line)
+	}
+	rec := &Record{letter: &dl}
+
+	var schemaRaw []byte
+	var fields []idk.Field
+	var paths idk.PathTable
+	var root interface{}
+	var err error
+	switch {
+	case s.headerSchema != nil:
+		schemaRaw = []byte(s.Header)
+		fields, paths = s.headerSchema, s.headerPaths
+		var payload map[string]interface{}
+		if len(dl.Payload) > 0 && json.Unmarshal(dl.Payload, &payload) == nil {
+			root = payload
+		} else {
+			root = letterFields(&dl)
+		}
+	case len(dl.Schema) > 0:
+		schemaRaw, err = json.Marshal(dl.Schema)
+		if err != nil {
+			return nil, errors.Wrap(err, "encoding dead letter schema")
+		}
+		if !bytes.Equal(schemaRaw, s.schemaRaw) {
+			fields, paths, err = idk.ParseHeader(schemaRaw)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing schema of dead letter at %s:%d", s.name, s.line)
+			}
+		} else {
+			fields, paths = s.schema, s.paths
+		}
+		root = letterFields(&dl)
+	default:
+		// The source couldn't decode the record in the first place, and
+		// there's no header saying how to now.
+		return nil, &idk.BadRecordError{
+			Stream:  dl.Stream,
+			Offset:  rec.offset(),
+			Payload: rec.Payload(),
+			Err:     errors.Errorf("dead letter at %s:%d has no schema; replay it with a header (original error: %s)", s.name, s.line, dl.Error),
+		}
+	}
+
+	rec.data, err = paths.Lookup(root, s.AllowMissingFields)
+	if err != nil {
+		return nil, &idk.BadRecordError{
+			Stream:  dl.Stream,
+			Offset:  rec.offset(),
+			Payload: rec.Payload(),
+			Err:     errors.Wrapf(err, "dead letter at %s:%d", s.name, s.line),
+		}
+	}
+
+	if !bytes.Equal(schemaRaw, s.schemaRaw) {
+		s.schema, s.paths, s.schemaRaw = fields, paths, schemaRaw
+		return rec, idk.ErrSchemaChange
+	}
+	return rec, nil
+}
+
+// letterFields returns the decoded fields of a dead letter by name.
+func letterFields(dl *idk.DeadLetter) map[string]interface{} {
+	fields := make(map[string]interface{}, len(dl.Schema))
+	for i, field := range dl.Schema {
+		if i >= len(dl.Data) {
+			break
+		}
+		fields[field.Name] = dl.Data[i]
+	}
+	return fields
+}
+
+// Schema returns the schema of the most recent record.
+func (s *Source) Schema() []idk.Field {
+	return s.schema
+}
+
+// Close closes the file being read, if any.
+func (s *Source) Close() error {
+	s.closeCurrent()
+	s.locations = nil
+	return nil
+}
+
+func (s *Source) openNext() error {
+	name := s.locations[0]
+	s.locations = s.locations[1:]
+	body, err := s.open(name)
+	if err != nil {
+		return errors.Wrapf(err, "opening %s", name)
+	}
+	s.Log.Printf("replaying dead letters from %s", name)
+	s.current, s.reader, s.name, s.line = body, bufio.NewReader(body), name, 0
+	return nil
+}
+
+func (s *Source) closeCurrent() {
+	if s.current != nil {
+		s.current.Close()
+	}
+	s.current, s.reader = nil, nil
+}
+
+// list returns the files under name, in order.
+func (s *Source) list(name string) ([]string, error) {
+	if strings.HasPrefix(name, "s3://") {
+		bucket, prefix, err := parseS3URI(name)
+		if err != nil {
+			return nil, err
+		}
+		client, err := s.s3Client()
+		if err != nil {
+			return nil, err
+		}
+		var locations []string
+		err = client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+			Bucket: aws.String(bucket),
+			Prefix: aws.String(prefix),
+		}, func(page *s3.ListObjectsV2Output, last bool) bool {
+			for _, obj := range page.Contents {
+				locations = append(locations, "s3://"+bucket+"/"+aws.StringValue(obj.Key))
+			}
+			return true
+		})
+		return locations, errors.Wrap(err, "listing S3 objects")
+	}
+
+	name = strings.TrimPrefix(name, "file://")
+	var locations []string
+	err := filepath.Walk(name, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			locations = append(locations, path)
+		}
+		return nil
+	})
+	return locations, err
+}
+
+func (s *Source) open(name string) (io.ReadCloser, error) {
+	if strings.HasPrefix(name, "s3://") {
+		bucket, key, err := parseS3URI(name)
+		if err != nil {
+			return nil, err
+		}
+		client, err := s.s3Client()
+		if err != nil {
+			return nil, err
+		}
+		result, err := client.GetObject(&s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching S3 object %v", name)
+		}
+		return result.Body, nil
+	}
+	return os.Open(strings.TrimPrefix(name, "file://"))
+}
+
+func (s *Source) readFileOrURL(name string) ([]byte, error) {
+	body, err := s.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+func (s *Source) s3Client() (*s3.S3, error) {
+	config := &aws.Config{}
+	if s.S3Region != "" {
+		config.Region = aws.String(s.S3Region)
+		// else, NewSession will use the default region.
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating S3 session")
+	}
+	return s3.New(sess), nil
+}
+
+func parseS3URI(name string) (bucket, key string, err error) {
+	u, err := url.Parse(name)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "parsing S3 URL %v", name)
+	}
+	return u.Host, strings.TrimPrefix(u.Path, "/"), nil
+}
+
+// Record is the record held by a dead letter. It keeps the stream, offset
+// and payload of the original record, so that if it is rejected again the
+// new dead letter still says where it came from.
+type Record struct {
+	letter *idk.DeadLetter
+	data   []interface{}
+}
+
+var _ idk.OffsetStreamRecord = (*Record)(nil)
+var _ idk.PayloadRecord = (*Record)(nil)
+
+// Commit does nothing; dead letters aren't removed once replayed.
+func (r *Record) Commit(ctx context.Context) error { return nil }
+
+func (r *Record) Data() []interface{} { return r.data }
+
+func (r *Record) Schema() interface{} { return nil }
+
+func (r *Record) StreamOffset() (string, uint64) {
+	return r.letter.Stream, r.offset()
+}
+
+func (r *Record) Payload() []byte {
+	if len(r.letter.Payload) > 0 {
+		return r.letter.Payload
+	}
+	return r.letter.PayloadBytes
+}
+
+func (r *Record) offset() uint64 {
+	if r.letter.Offset == nil {
+		return 0
+	}
+	return *r.letter.Offset
+}
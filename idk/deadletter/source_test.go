@@ -0,0 +1,127 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/pkg/errors"
+)
+
+func TestSource(t *testing.T) {
+	dir := t.TempDir()
+	offset := uint64(5)
+	letters := []idk.DeadLetter{
+		{
+			Index:  "idx",
+			Stream: "topic:0",
+			Offset: &offset,
+			Field:  "n",
+			Error:  "converting n",
+			Schema: []idk.RawField{
+				{Name: "name", Path: []string{"name"}, Type: "string"},
+				{Name: "n", Path: []string{"n"}, Type: "int"},
+			},
+			Data:    []interface{}{"a", "1.5"},
+			Payload: json.RawMessage(`{"user": {"name": "a"}, "n": 1.5}`),
+		},
+		{
+			Index:  "idx",
+			Stream: "topic:1",
+			Offset: &offset,
+			Error:  "decoding",
+		},
+	}
+	f, err := os.Create(filepath.Join(dir, "dead.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := json.NewEncoder(f)
+	for i := range letters {
+		if err := enc.Encode(&letters[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("StoredSchema", func(t *testing.T) {
+		source := NewSource()
+		source.Files = []string{dir}
+		if err := source.Open(); err != nil {
+			t.Fatalf("opening source: %v", err)
+		}
+		defer source.Close()
+
+		rec, err := source.Record()
+		if err != idk.ErrSchemaChange {
+			t.Fatalf("expected schema change, got %v", err)
+		}
+		if exp := []idk.Field{idk.StringField{NameVal: "name"}, idk.IntField{NameVal: "n"}}; !reflect.DeepEqual(source.Schema(), exp) {
+			t.Errorf("expected schema %v, got %v", exp, source.Schema())
+		}
+		if exp := []interface{}{"a", "1.5"}; !reflect.DeepEqual(rec.Data(), exp) {
+			t.Errorf("expected data %v, got %v", exp, rec.Data())
+		}
+		if stream, off := rec.(idk.OffsetStreamRecord).StreamOffset(); stream != "topic:0" || off != 5 {
+			t.Errorf("unexpected stream offset %s:%d", stream, off)
+		}
+
+		// a record the original source couldn't decode can't be replayed
+		// without a header
+		_, err = source.Record()
+		var bre *idk.BadRecordError
+		if !errors.As(err, &bre) || bre.Stream != "topic:1" {
+			t.Fatalf("expected bad record error, got %v", err)
+		}
+
+		if _, err := source.Record(); err != io.EOF {
+			t.Fatalf("expected EOF, got %v", err)
+		}
+	})
+
+	t.Run("Header", func(t *testing.T) {
+		header := filepath.Join(t.TempDir(), "header.json")
+		err := os.WriteFile(header, []byte(`[
+			{"name": "name", "path": ["user", "name"], "type": "string"},
+			{"name": "n", "path": ["n"], "type": "decimal", "config": {"Scale": 1}}
+		]`), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		source := NewSource()
+		source.Files = []string{filepath.Join(dir, "dead.ndjson")}
+		source.Header = header
+		if err := source.Open(); err != nil {
+			t.Fatalf("opening source: %v", err)
+		}
+		defer source.Close()
+
+		rec, err := source.Record()
+		if err != idk.ErrSchemaChange {
+			t.Fatalf("expected schema change, got %v", err)
+		}
+		if exp := []idk.Field{idk.StringField{NameVal: "name"}, idk.DecimalField{NameVal: "n", Scale: 1}}; !reflect.DeepEqual(source.Schema(), exp) {
+			t.Errorf("expected schema %v, got %v", exp, source.Schema())
+		}
+		if exp := []interface{}{"a", 1.5}; !reflect.DeepEqual(rec.Data(), exp) {
+			t.Errorf("expected data %v, got %v", exp, rec.Data())
+		}
+		if payload := rec.(idk.PayloadRecord).Payload(); string(payload) != `{"user":{"name":"a"},"n":1.5}` {
+			t.Errorf("unexpected payload %s", payload)
+		}
+
+		// without a JSON payload there's nothing for the header's paths
+		_, err = source.Record()
+		var bre *idk.BadRecordError
+		if !errors.As(err, &bre) {
+			t.Fatalf("expected bad record error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package idk
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestFileDeadLetterSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead.ndjson")
+	sink, err := OpenDeadLetterSink(path, "")
+	if err != nil {
+		t.Fatalf("opening sink: %v", err)
+	}
+
+	m := &Main{Index: "idx"}
+	schema := []Field{
+		StringField{NameVal: "name"},
+		IgnoreField{},
+		IntField{NameVal: "n"},
+	}
+	rec := &offsetRecord{groupKey: "topic:0", offset: 12, data: []interface{}{[]byte("a"), "x", "notanint"}}
+	if err := sink.Write(m.newDeadLetter(rec, schema, errors.New("converting n"))); err != nil {
+		t.Fatalf("writing dead letter: %v", err)
+	}
+	bre := &BadRecordError{Stream: "topic:1", Offset: 3, Payload: []byte{0xff, 0x00}, Err: errors.New("decoding")}
+	if err := sink.Write(m.badRecordDeadLetter(bre)); err != nil {
+		t.Fatalf("writing dead letter: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("closing sink: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var letters []DeadLetter
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var dl DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &dl); err != nil {
+			t.Fatalf("decoding %s: %v", scanner.Text(), err)
+		}
+		letters = append(letters, dl)
+	}
+	if len(letters) != 2 {
+		t.Fatalf("expected 2 dead letters, got %d", len(letters))
+	}
+
+	dl := letters[0]
+	if dl.Index != "idx" || dl.Stream != "topic:0" || dl.Offset == nil || *dl.Offset != 12 {
+		t.Errorf("unexpected source of dead letter: %+v", dl)
+	}
+	if dl.Field != "n" || dl.Error != "converting n" {
+		t.Errorf("unexpected field or error: %q %q", dl.Field, dl.Error)
+	}
+	if exp := []interface{}{"a", "notanint"}; !reflect.DeepEqual(dl.Data, exp) {
+		t.Errorf("expected data %v, got %v", exp, dl.Data)
+	}
+	if len(dl.Schema) != 2 || dl.Schema[0].Type != "string" || dl.Schema[1].Type != "int" || !reflect.DeepEqual(dl.Schema[1].Path, []string{"n"}) {
+		t.Errorf("unexpected schema: %+v", dl.Schema)
+	}
+	fields, _, err := ParseHeader(mustMarshal(t, dl.Schema))
+	if err != nil {
+		t.Fatalf("parsing dead letter schema: %v", err)
+	}
+	if exp := []Field{schema[0], schema[2]}; !reflect.DeepEqual(fields, exp) {
+		t.Errorf("expected schema to parse as %v, got %v", exp, fields)
+	}
+
+	dl = letters[1]
+	if dl.Stream != "topic:1" || *dl.Offset != 3 || dl.Error != "decoding" {
+		t.Errorf("unexpected bad record dead letter: %+v", dl)
+	}
+	if dl.Payload != nil || !reflect.DeepEqual(dl.PayloadBytes, []byte{0xff, 0x00}) {
+		t.Errorf("unexpected payload: %s %v", dl.Payload, dl.PayloadBytes)
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
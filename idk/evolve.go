@@ -0,0 +1,349 @@
+package idk
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	pilosacore "github.com/featurebasedb/featurebase/v3"
+	pilosaclient "github.com/featurebasedb/featurebase/v3/client"
+	"github.com/pkg/errors"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Schema-evolution policies, which decide what happens when a source's
+// schema changes.
+const (
+	// SchemaEvolutionStrict stops the ingester when a field is added to,
+	// removed from, or changed in the source's schema once ingest has
+	// started.
+	SchemaEvolutionStrict = "strict"
+
+	// SchemaEvolutionAdditive creates fields added to the source's schema,
+	// and reports those removed from it, but stops the ingester when a
+	// field no longer matches its FeatureBase field. It's the default.
+	SchemaEvolutionAdditive = "additive"
+
+	// SchemaEvolutionCoerce also alters FeatureBase fields to widen them
+	// when a field changes compatibly (an int's range grows, or a string
+	// becomes a string set, say), and ingests a field which changes
+	// incompatibly into a new versioned field, named like "price_v2".
+	SchemaEvolutionCoerce = "coerce"
+)
+
+// maxFieldVersion is the highest version a field changed incompatibly is
+// given before the ingester gives up.
+const maxFieldVersion = 100
+
+// alterPollInterval is how often an ingester checks whether a field it
+// altered has been rewritten.
+var alterPollInterval = time.Second
+
+// alterTimeout is how long an ingester waits for a field it altered to be
+// rewritten before it ingests into a new version of the field instead.
+var alterTimeout = 5 * time.Minute
+
+func validateSchemaEvolution(policy string) error {
+	switch policy {
+	case "", SchemaEvolutionStrict, SchemaEvolutionAdditive, SchemaEvolutionCoerce:
+		return nil
+	}
+	return errors.Errorf("unknown schema evolution policy '%s', must be %s, %s or %s",
+		policy, SchemaEvolutionStrict, SchemaEvolutionAdditive, SchemaEvolutionCoerce)
+}
+
+// schemaDiff is the difference between two schemas, by source field name.
+type schemaDiff struct {
+	added, removed, changed []string
+}
+
+func (d schemaDiff) empty() bool {
+	return len(d.added) == 0 && len(d.removed) == 0 && len(d.changed) == 0
+}
+
+func (d schemaDiff) String() string {
+	var parts []string
+	for _, p := range []struct {
+		what   string
+		fields []string
+	}{{"added", d.added}, {"removed", d.removed}, {"changed", d.changed}} {
+		if len(p.fields) > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", p.what, strings.Join(p.fields, ", ")))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// diffSchemas returns the fields added, removed and changed between prev
+// and next.
+func diffSchemas(prev, next []Field) schemaDiff {
+	var diff schemaDiff
+	byName := make(map[string]Field, len(prev))
+	for _, fld := range prev {
+		if _, ok := fld.(IgnoreField); !ok {
+			byName[fld.Name()] = fld
+		}
+	}
+	for _, fld := range next {
+		if _, ok := fld.(IgnoreField); ok {
+			continue
+		}
+		old, ok := byName[fld.Name()]
+		switch {
+		case !ok:
+			diff.added = append(diff.added, fld.Name())
+		case !FieldsEqual(old, fld):
+			diff.changed = append(diff.changed, fld.Name())
+		}
+		delete(byName, fld.Name())
+	}
+	for name := range byName {
+		diff.removed = append(diff.removed, name)
+	}
+	sort.Strings(diff.removed)
+	return diff
+}
+
+// checkSchemaChange reports how schema differs from the one this ingester
+// was using, stopping it if the policy is strict. A removed field is only
+// reported: its FeatureBase field is left as it is, and no longer set.
+func (m *Main) checkSchemaChange(schema []Field) error {
+	if m.prevSchema == nil {
+		return nil
+	}
+	diff := diffSchemas(m.prevSchema, schema)
+	if diff.empty() {
+		return nil
+	}
+	if m.SchemaEvolution == SchemaEvolutionStrict {
+		return errors.Errorf("source schema changed (%s) and the schema evolution policy is %s", diff, SchemaEvolutionStrict)
+	}
+	m.log.Printf("source schema changed: %s", diff)
+	CounterIngesterSchemaEvolutions.With(prom.Labels{"change": "added"}).Add(float64(len(diff.added)))
+	CounterIngesterSchemaEvolutions.With(prom.Labels{"change": "removed"}).Add(float64(len(diff.removed)))
+	return nil
+}
+
+// evolveFields resolves, under the coerce policy, the fields of schema
+// which no longer match their FeatureBase fields. A field which can be
+// widened is altered, and one which can't is renamed to a new version. The
+// fields at the indices in skips aren't ingested, so they're left alone.
+func (m *Main) evolveFields(schema []Field, skips map[int]struct{}) ([]Field, error) {
+	if m.SchemaEvolution != SchemaEvolutionCoerce {
+		return schema, nil
+	}
+	evolved := make([]Field, len(schema))
+	copy(evolved, schema)
+
+	altered := false
+	existing := m.index.Fields()
+	for i, fld := range evolved {
+		if _, ok := skips[i]; ok {
+			continue
+		}
+		switch fld.(type) {
+		case IgnoreField, LookupTextField, RecordTimeField, SignedIntBoolKeyField:
+			continue
+		case BoolField:
+			if m.PackBools != "" {
+				continue
+			}
+		}
+		pFld, ok := existing[fld.DestName()]
+		if !ok {
+			continue
+		}
+		errs, _, err := m.fieldIncompatibilities(pFld, fld, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "checking field %s", fld.DestName())
+		} else if len(errs) == 0 {
+			continue
+		}
+
+		// A field which FeatureBase can already hold is only coerced to
+		// match it; one which it can't hold yet is widened.
+		widened, columnType := widenField(pFld, fld)
+		switch {
+		case widened != nil && columnType == "":
+			m.log.Printf("coercing field %s: %s", fld.DestName(), strings.Join(errs, "; "))
+			evolved[i] = widened
+			CounterIngesterSchemaEvolutions.With(prom.Labels{"change": "coerced"}).Inc()
+			continue
+		case widened != nil:
+			m.log.Printf("altering field %s to %s: %s", fld.DestName(), columnType, strings.Join(errs, "; "))
+			if err := m.alterField(widened, columnType); err != nil {
+				m.log.Errorf("altering field %s: %v", fld.DestName(), err)
+				break
+			}
+			altered = true
+			evolved[i] = widened
+			CounterIngesterSchemaEvolutions.With(prom.Labels{"change": "widened"}).Inc()
+			continue
+		}
+
+		versioned, err := m.versionField(fld, evolved)
+		if err != nil {
+			return nil, err
+		}
+		m.log.Printf("ingesting field %s into %s: %s", fld.DestName(), versioned.DestName(), strings.Join(errs, "; "))
+		evolved[i] = versioned
+		CounterIngesterSchemaEvolutions.With(prom.Labels{"change": "versioned"}).Inc()
+	}
+
+	if altered {
+		schema, err := m.SchemaManager.Schema()
+		if err != nil {
+			return nil, errors.Wrap(err, "fetching altered schema")
+		}
+		m.index = schema.Index(m.Index)
+	}
+	return evolved, nil
+}
+
+// widenField returns fld changed to match pFld, if pFld can hold its
+// values, possibly once pFld has been altered to the returned column type.
+// It returns nil if the two aren't compatible.
+func widenField(pFld *pilosaclient.Field, fld Field) (Field, string) {
+	opts := pFld.Options()
+	switch f := fld.(type) {
+	case IntField:
+		if opts.Type() != pilosaclient.FieldTypeInt || f.ForeignIndex != opts.ForeignIndex() {
+			return nil, ""
+		}
+		pMin, pMax := opts.Min().ToInt64(0), opts.Max().ToInt64(0)
+		min, max := pMin, pMax
+		if f.Min != nil && *f.Min < min {
+			min = *f.Min
+		}
+		if f.Max != nil && *f.Max > max {
+			max = *f.Max
+		}
+		f.Min, f.Max = &min, &max
+		if min == pMin && max == pMax {
+			return f, ""
+		}
+		return f, fmt.Sprintf("int min %d max %d", min, max)
+	case DecimalField:
+		switch opts.Type() {
+		case pilosaclient.FieldTypeDecimal:
+			if f.Scale <= opts.Scale() {
+				f.Scale = opts.Scale()
+				return f, ""
+			}
+		case pilosaclient.FieldTypeInt:
+			if opts.ForeignIndex() != "" {
+				return nil, ""
+			}
+		default:
+			return nil, ""
+		}
+		return f, fmt.Sprintf("decimal(%d)", f.Scale)
+	case StringArrayField:
+		if opts.Type() == pilosaclient.FieldTypeMutex && opts.Keys() && f.Quantum == "" {
+			return f, "stringset"
+		}
+	case IDArrayField:
+		if opts.Type() == pilosaclient.FieldTypeMutex && !opts.Keys() && f.Quantum == "" {
+			return f, "idset"
+		}
+	case StringField:
+		if opts.Type() == pilosaclient.FieldTypeSet && opts.Keys() && f.Quantum == "" {
+			f.Mutex = false
+			return f, ""
+		}
+	case IDField:
+		if opts.Type() == pilosaclient.FieldTypeSet && !opts.Keys() && f.Quantum == "" {
+			f.Mutex = false
+			return f, ""
+		}
+	}
+	return nil, ""
+}
+
+// alterField alters the FeatureBase field of fld to columnType, and waits
+// up to alterTimeout for its values to be rewritten.
+func (m *Main) alterField(fld Field, columnType string) error {
+	name := fld.DestName()
+	sql := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", quoteIdent(m.Index), quoteIdent(name), columnType)
+	_, body, err := m.client.HTTPRequest("POST", "/sql", []byte(sql), nil)
+	if err != nil {
+		return errors.Wrap(err, "sending alter")
+	}
+	var resp struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return errors.Wrap(err, "decoding alter response")
+	} else if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+
+	// The field is replaced once its values have all been rewritten, and
+	// the field they're rewritten into is deleted if they can't be.
+	deadline := time.Now().Add(alterTimeout)
+	for {
+		schema, err := m.SchemaManager.Schema()
+		if err != nil {
+			return errors.Wrap(err, "fetching schema")
+		}
+		fields := schema.Index(m.Index).Fields()
+		if pFld, ok := fields[name]; ok {
+			errs, _, err := m.fieldIncompatibilities(pFld, fld, "")
+			if err != nil {
+				return err
+			} else if len(errs) == 0 {
+				return nil
+			}
+		}
+		if _, ok := fields[pilosacore.AlterFieldName(name)]; !ok {
+			return errors.Errorf("values of field %s couldn't be rewritten as %s", name, columnType)
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("values of field %s weren't rewritten as %s within %v", name, columnType, alterTimeout)
+		}
+		time.Sleep(alterPollInterval)
+	}
+}
+
+// quoteIdent quotes a table or column name for SQL.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// versionField returns fld renamed to the first version of its
+// FeatureBase field which is free, or compatible with it, and which isn't
+// the name of another field in schema.
+func (m *Main) versionField(fld Field, schema []Field) (Field, error) {
+	taken := make(map[string]struct{}, len(schema))
+	for _, f := range schema {
+		taken[f.DestName()] = struct{}{}
+	}
+	existing := m.index.Fields()
+	for v := 2; v <= maxFieldVersion; v++ {
+		name := fmt.Sprintf("%s_v%d", fld.DestName(), v)
+		if _, ok := taken[name]; ok {
+			continue
+		}
+		versioned := withDestName(fld, name)
+		pFld, ok := existing[name]
+		if !ok {
+			return versioned, nil
+		}
+		if errs, _, err := m.fieldIncompatibilities(pFld, versioned, ""); err == nil && len(errs) == 0 {
+			return versioned, nil
+		}
+	}
+	return nil, errors.Errorf("no free version of field %s up to v%d", fld.DestName(), maxFieldVersion)
+}
+
+// withDestName returns a copy of fld which is ingested into the field
+// named dest. Every Field is a struct with a DestNameVal.
+func withDestName(fld Field, dest string) Field {
+	v := reflect.New(reflect.TypeOf(fld)).Elem()
+	v.Set(reflect.ValueOf(fld))
+	v.FieldByName("DestNameVal").SetString(dest)
+	return v.Interface().(Field)
+}
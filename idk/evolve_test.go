@@ -0,0 +1,239 @@
+package idk
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pilosacore "github.com/featurebasedb/featurebase/v3"
+	pilosaclient "github.com/featurebasedb/featurebase/v3/client"
+	"github.com/featurebasedb/featurebase/v3/logger"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	prev := []Field{
+		StringField{NameVal: "a"},
+		IntField{NameVal: "b", Min: int64Ptr(0), Max: int64Ptr(10)},
+		BoolField{NameVal: "c"},
+		IgnoreField{},
+	}
+	next := []Field{
+		StringField{NameVal: "a"},
+		IntField{NameVal: "b", Min: int64Ptr(0), Max: int64Ptr(100)},
+		DecimalField{NameVal: "d", Scale: 2},
+	}
+	diff := diffSchemas(prev, next)
+	exp := schemaDiff{added: []string{"d"}, removed: []string{"c"}, changed: []string{"b"}}
+	if !reflect.DeepEqual(diff, exp) {
+		t.Fatalf("expected %+v, got %+v", exp, diff)
+	}
+	if s := diff.String(); s != "added d; removed c; changed b" {
+		t.Fatalf("unexpected description: %s", s)
+	}
+	if !diffSchemas(next, next).empty() {
+		t.Fatal("expected no difference between a schema and itself")
+	}
+}
+
+func TestCheckSchemaChangeStrict(t *testing.T) {
+	m := NewMain()
+	m.log = logger.NopLogger
+	m.SchemaEvolution = SchemaEvolutionStrict
+	schema := []Field{StringField{NameVal: "a"}}
+	if err := m.checkSchemaChange(schema); err != nil {
+		t.Fatalf("first schema: %v", err)
+	}
+	m.prevSchema = schema
+	if err := m.checkSchemaChange(schema); err != nil {
+		t.Fatalf("same schema: %v", err)
+	}
+	err := m.checkSchemaChange([]Field{StringField{NameVal: "a"}, IntField{NameVal: "b"}})
+	if err == nil || !strings.Contains(err.Error(), "added b") {
+		t.Fatalf("expected error for added field, got %v", err)
+	}
+
+	m.SchemaEvolution = SchemaEvolutionAdditive
+	if err := m.checkSchemaChange(nil); err != nil {
+		t.Fatalf("additive: %v", err)
+	}
+}
+
+func TestWidenField(t *testing.T) {
+	idx := pilosaclient.NewSchema().Index("i")
+	intFld := idx.Field("int", pilosaclient.OptFieldTypeInt(-10, 10))
+	decFld := idx.Field("dec", pilosaclient.OptFieldTypeDecimal(2))
+	mutexFld := idx.Field("mutex", pilosaclient.OptFieldTypeMutex(pilosaclient.CacheTypeRanked, 1000), pilosaclient.OptFieldKeys(true))
+	setFld := idx.Field("set", pilosaclient.OptFieldTypeSet(pilosaclient.CacheTypeRanked, 1000), pilosaclient.OptFieldKeys(true))
+
+	tests := []struct {
+		pFld       *pilosaclient.Field
+		fld        Field
+		exp        Field
+		columnType string
+	}{
+		{
+			pFld:       intFld,
+			fld:        IntField{NameVal: "int", Min: int64Ptr(0), Max: int64Ptr(100)},
+			exp:        IntField{NameVal: "int", Min: int64Ptr(-10), Max: int64Ptr(100)},
+			columnType: "int min -10 max 100",
+		},
+		{
+			pFld: intFld,
+			fld:  IntField{NameVal: "int", Min: int64Ptr(0), Max: int64Ptr(5)},
+			exp:  IntField{NameVal: "int", Min: int64Ptr(-10), Max: int64Ptr(10)},
+		},
+		{
+			pFld:       decFld,
+			fld:        DecimalField{NameVal: "dec", Scale: 4},
+			exp:        DecimalField{NameVal: "dec", Scale: 4},
+			columnType: "decimal(4)",
+		},
+		{
+			pFld: decFld,
+			fld:  DecimalField{NameVal: "dec", Scale: 1},
+			exp:  DecimalField{NameVal: "dec", Scale: 2},
+		},
+		{
+			pFld:       intFld,
+			fld:        DecimalField{NameVal: "int", Scale: 2},
+			exp:        DecimalField{NameVal: "int", Scale: 2},
+			columnType: "decimal(2)",
+		},
+		{
+			pFld:       mutexFld,
+			fld:        StringArrayField{NameVal: "mutex"},
+			exp:        StringArrayField{NameVal: "mutex"},
+			columnType: "stringset",
+		},
+		{
+			pFld: setFld,
+			fld:  StringField{NameVal: "set", Mutex: true},
+			exp:  StringField{NameVal: "set"},
+		},
+		{
+			pFld: intFld,
+			fld:  StringField{NameVal: "int"},
+		},
+		{
+			pFld: decFld,
+			fld:  IntField{NameVal: "dec"},
+		},
+		{
+			pFld: mutexFld,
+			fld:  IDArrayField{NameVal: "mutex"},
+		},
+	}
+	for i, test := range tests {
+		got, columnType := widenField(test.pFld, test.fld)
+		if test.exp == nil {
+			if got != nil {
+				t.Errorf("%d: expected %+v not to be widened, got %+v", i, test.fld, got)
+			}
+			continue
+		}
+		if got == nil || !FieldsEqual(got, test.exp) || columnType != test.columnType {
+			t.Errorf("%d: expected %+v %q, got %+v %q", i, test.exp, test.columnType, got, columnType)
+		}
+	}
+}
+
+func TestEvolveFieldsCoerce(t *testing.T) {
+	m := NewMain()
+	m.log = logger.NopLogger
+	m.SchemaEvolution = SchemaEvolutionCoerce
+	m.index = pilosaclient.NewSchema().Index("i")
+	m.index.Field("price", pilosaclient.OptFieldTypeInt(0, 100))
+	m.index.Field("price_v2", pilosaclient.OptFieldTypeSet(pilosaclient.CacheTypeRanked, 1000))
+	m.index.Field("qty", pilosaclient.OptFieldTypeInt(0, 100))
+	m.index.Field("pk", pilosaclient.OptFieldTypeInt(0, 100))
+
+	schema := []Field{
+		StringField{NameVal: "pk"},
+		StringField{NameVal: "price"},
+		IntField{NameVal: "qty", Min: int64Ptr(10), Max: int64Ptr(20)},
+		StringField{NameVal: "new"},
+	}
+	evolved, err := m.evolveFields(schema, map[int]struct{}{0: {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []Field{
+		StringField{NameVal: "pk"},
+		StringField{NameVal: "price", DestNameVal: "price_v3"},
+		IntField{NameVal: "qty", Min: int64Ptr(0), Max: int64Ptr(100)},
+		StringField{NameVal: "new"},
+	}
+	for i := range exp {
+		if !FieldsEqual(evolved[i], exp[i]) {
+			t.Errorf("field %d: expected %+v, got %+v", i, exp[i], evolved[i])
+		}
+	}
+	if !FieldsEqual(schema[1], StringField{NameVal: "price"}) {
+		t.Errorf("source schema was modified: %+v", schema[1])
+	}
+
+	m.SchemaEvolution = SchemaEvolutionAdditive
+	if evolved, err := m.evolveFields(schema, nil); err != nil || !reflect.DeepEqual(evolved, schema) {
+		t.Fatalf("expected additive policy to leave schema alone, got %+v, %v", evolved, err)
+	}
+}
+
+// alteringSchemaManager returns a schema in which a field is always being
+// altered.
+type alteringSchemaManager struct {
+	nopSchemaManager
+	schema *pilosaclient.Schema
+}
+
+func (a *alteringSchemaManager) Schema() (*pilosaclient.Schema, error) {
+	return a.schema, nil
+}
+
+func TestEvolveFieldsAlterTimeout(t *testing.T) {
+	defer func(interval, timeout time.Duration) {
+		alterPollInterval, alterTimeout = interval, timeout
+	}(alterPollInterval, alterTimeout)
+	alterPollInterval, alterTimeout = time.Millisecond, 20*time.Millisecond
+
+	var mu sync.Mutex
+	var statements []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		statements = append(statements, string(body))
+		mu.Unlock()
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	m := NewMain()
+	m.log = logger.NopLogger
+	m.SchemaEvolution = SchemaEvolutionCoerce
+	m.Index = "my-index"
+	client, err := pilosaclient.NewClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.client = client
+	schema := pilosaclient.NewSchema()
+	m.index = schema.Index(m.Index)
+	m.index.Field("price", pilosaclient.OptFieldTypeInt(0, 100))
+	m.index.Field(pilosacore.AlterFieldName("price"), pilosaclient.OptFieldTypeInt(0, 1000))
+	m.SchemaManager = &alteringSchemaManager{schema: schema}
+
+	evolved, err := m.evolveFields([]Field{IntField{NameVal: "price", Min: int64Ptr(0), Max: int64Ptr(1000)}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := `ALTER TABLE "my-index" ALTER COLUMN "price" int min 0 max 1000`; len(statements) != 1 || statements[0] != exp {
+		t.Errorf("expected statement %s, got %v", exp, statements)
+	}
+	if evolved[0].DestName() != "price_v2" {
+		t.Errorf("expected the field ingested into price_v2 once the alter timed out, got %+v", evolved[0])
+	}
+}
@@ -0,0 +1,292 @@
+package file
+
+import (
+	"context"
+	"io"
+	"math"
+
+	"github.com/apache/arrow/go/v10/arrow"
+	"github.com/apache/arrow/go/v10/arrow/array"
+	"github.com/apache/arrow/go/v10/arrow/ipc"
+	"github.com/apache/arrow/go/v10/arrow/memory"
+	pqfile "github.com/apache/arrow/go/v10/parquet/file"
+	"github.com/apache/arrow/go/v10/parquet/pqarrow"
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/pkg/errors"
+)
+
+// parquetBatchSize is the number of rows read from a row group at a time.
+const parquetBatchSize = 1 << 14
+
+// arrowAllocator allocates the buffers of the records read. Strings taken
+// from them refer to those buffers, and are held by batches after the
+// records are released, so this must be the Go allocator, which leaves
+// freeing them to the garbage collector rather than reusing them.
+var arrowAllocator = memory.NewGoAllocator()
+
+// parquetRowGroups returns the number of row groups in a Parquet file.
+func parquetRowGroups(r readAtSeeker) (int, error) {
+	pf, err := pqfile.NewParquetReader(r)
+	if err != nil {
+		return 0, err
+	}
+	return pf.NumRowGroups(), nil
+}
+
+// arrowRecordBatches returns the number of record batches in an Arrow IPC
+// file, or -1 if it's in the IPC streaming format, which can only be read
+// from start to finish.
+func arrowRecordBatches(r readAtSeeker) (int, error) {
+	fr, err := ipc.NewFileReader(r, ipc.WithAllocator(arrowAllocator))
+	if err != nil {
+		if _, serr := r.Seek(0, io.SeekStart); serr != nil {
+			return 0, serr
+		}
+		if _, serr := ipc.NewReader(r, ipc.WithAllocator(arrowAllocator)); serr != nil {
+			return 0, err
+		}
+		return -1, nil
+	}
+	defer fr.Close()
+	return fr.NumRecords(), nil
+}
+
+// arrowRows reads the rows of a sequence of Arrow records.
+type arrowRows struct {
+	schema *arrow.Schema
+	fields []idk.Field
+	read   func() (arrow.Record, error)
+	close  func() error
+
+	rec arrow.Record
+	row int
+}
+
+// newParquetRows reads a row group of a Parquet file.
+func newParquetRows(r readAtSeeker, rowGroup int) (*arrowRows, error) {
+	pf, err := pqfile.NewParquetReader(r)
+	if err != nil {
+		return nil, err
+	}
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{BatchSize: parquetBatchSize}, arrowAllocator)
+	if err != nil {
+		return nil, err
+	}
+	rr, err := fr.GetRecordReader(context.Background(), nil, []int{rowGroup})
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading row group %d", rowGroup)
+	}
+	return &arrowRows{
+		schema: rr.Schema(),
+		read:   rr.Read,
+		close: func() error {
+			rr.Release()
+			return nil
+		},
+	}, nil
+}
+
+// newArrowRows reads a record batch of an Arrow IPC file, or every
+// record batch of an Arrow IPC stream if batch is negative.
+func newArrowRows(r readAtSeeker, batch int) (*arrowRows, error) {
+	if batch < 0 {
+		sr, err := ipc.NewReader(r, ipc.WithAllocator(arrowAllocator))
+		if err != nil {
+			return nil, err
+		}
+		return &arrowRows{
+			schema: sr.Schema(),
+			read:   sr.Read,
+			close: func() error {
+				sr.Release()
+				return nil
+			},
+		}, nil
+	}
+
+	fr, err := ipc.NewFileReader(r, ipc.WithAllocator(arrowAllocator))
+	if err != nil {
+		return nil, err
+	}
+	done := false
+	return &arrowRows{
+		schema: fr.Schema(),
+		read: func() (arrow.Record, error) {
+			if done {
+				return nil, io.EOF
+			}
+			done = true
+			rec, err := fr.Record(batch)
+			return rec, errors.Wrapf(err, "reading record batch %d", batch)
+		},
+		close: fr.Close,
+	}, nil
+}
+
+// Fields returns the fields of the columns, as named in the header or
+// otherwise inferred from their types.
+func (r *arrowRows) Fields(h *header) ([]idk.Field, error) {
+	columns := make([]string, len(r.schema.Fields()))
+	for i, f := range r.schema.Fields() {
+		columns[i] = f.Name
+	}
+	fields, _, err := h.apply(columns, false, func(i int) (idk.Field, error) {
+		f := r.schema.Field(i)
+		return arrowToField(f.Name, f.Type)
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.fields = fields
+	return fields, nil
+}
+
+// Next returns the values of the next row.
+func (r *arrowRows) Next() ([]interface{}, []byte, error) {
+	for r.rec == nil || r.row >= int(r.rec.NumRows()) {
+		rec, err := r.read()
+		if err != nil {
+			r.rec = nil
+			return nil, nil, err
+		}
+		r.rec, r.row = rec, 0
+	}
+	data := make([]interface{}, len(r.fields))
+	for i, field := range r.fields {
+		if _, ok := field.(idk.IgnoreField); ok {
+			continue
+		}
+		val, err := arrowValue(r.rec.Column(i), r.row)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "reading column %s", r.schema.Field(i).Name)
+		}
+		data[i] = val
+	}
+	r.row++
+	return data, nil, nil
+}
+
+func (r *arrowRows) Close() error {
+	return r.close()
+}
+
+// arrowToField infers the field for a column of an Arrow type. Lists of
+// strings and integers become sets, and Arrow timestamps are kept at
+// their own granularity.
+func arrowToField(name string, typ arrow.DataType) (idk.Field, error) {
+	switch typ := typ.(type) {
+	case *arrow.BooleanType:
+		return idk.BoolField{NameVal: name}, nil
+	case *arrow.Int8Type, *arrow.Int16Type, *arrow.Int32Type, *arrow.Int64Type,
+		*arrow.Uint8Type, *arrow.Uint16Type, *arrow.Uint32Type, *arrow.Uint64Type:
+		return idk.IntField{NameVal: name}, nil
+	case *arrow.Float16Type, *arrow.Float32Type, *arrow.Float64Type:
+		return idk.FloatField{NameVal: name}, nil
+	case *arrow.Decimal128Type:
+		return idk.DecimalField{NameVal: name, Scale: int64(typ.Scale)}, nil
+	case *arrow.StringType, *arrow.LargeStringType, *arrow.BinaryType, *arrow.LargeBinaryType:
+		return idk.StringField{NameVal: name}, nil
+	case *arrow.TimestampType:
+		var granularity idk.Unit
+		switch typ.Unit {
+		case arrow.Second:
+			granularity = idk.Second
+		case arrow.Millisecond:
+			granularity = idk.Millisecond
+		case arrow.Microsecond:
+			granularity = idk.Microsecond
+		case arrow.Nanosecond:
+			granularity = idk.Nanosecond
+		}
+		return idk.TimestampField{NameVal: name, Granularity: string(granularity)}, nil
+	case *arrow.Date32Type, *arrow.Date64Type:
+		return idk.TimestampField{NameVal: name}, nil
+	case *arrow.DictionaryType:
+		return arrowToField(name, typ.ValueType)
+	case *arrow.ListType, *arrow.LargeListType:
+		elem, err := arrowToField(name, typ.(interface{ Elem() arrow.DataType }).Elem())
+		if err != nil {
+			return nil, err
+		}
+		switch elem.(type) {
+		case idk.StringField:
+			return idk.StringArrayField{NameVal: name}, nil
+		case idk.IntField:
+			return idk.IDArrayField{NameVal: name}, nil
+		}
+	}
+	return nil, errors.Errorf("unsupported type %s", typ)
+}
+
+// arrowValue returns the value at index i of an Arrow array in a form the
+// idk fields understand.
+func arrowValue(arr arrow.Array, i int) (interface{}, error) {
+	if arr.IsNull(i) {
+		return nil, nil
+	}
+	switch arr := arr.(type) {
+	case *array.Boolean:
+		return arr.Value(i), nil
+	case *array.Int8:
+		return arr.Value(i), nil
+	case *array.Int16:
+		return arr.Value(i), nil
+	case *array.Int32:
+		return arr.Value(i), nil
+	case *array.Int64:
+		return arr.Value(i), nil
+	case *array.Uint8:
+		return arr.Value(i), nil
+	case *array.Uint16:
+		return arr.Value(i), nil
+	case *array.Uint32:
+		return arr.Value(i), nil
+	case *array.Uint64:
+		return arr.Value(i), nil
+	case *array.Float16:
+		return float64(arr.Value(i).Float32()), nil
+	case *array.Float32:
+		return float64(arr.Value(i)), nil
+	case *array.Float64:
+		return arr.Value(i), nil
+	case *array.Decimal128:
+		num := arr.Value(i)
+		hi, lo := num.HighBits(), num.LowBits()
+		if (hi != 0 || lo > math.MaxInt64) && (hi != -1 || lo <= math.MaxInt64) {
+			return nil, errors.Errorf("decimal %s doesn't fit in 64 bits", num.BigInt())
+		}
+		return pql.NewDecimal(int64(lo), int64(arr.DataType().(*arrow.Decimal128Type).Scale)), nil
+	case *array.String:
+		return arr.Value(i), nil
+	case *array.LargeString:
+		return arr.Value(i), nil
+	case *array.Binary:
+		return string(arr.Value(i)), nil
+	case *array.LargeBinary:
+		return string(arr.Value(i)), nil
+	case *array.Timestamp:
+		return arr.Value(i).ToTime(arr.DataType().(*arrow.TimestampType).Unit), nil
+	case *array.Date32:
+		return arr.Value(i).ToTime(), nil
+	case *array.Date64:
+		return arr.Value(i).ToTime(), nil
+	case *array.Dictionary:
+		return arrowValue(arr.Dictionary(), arr.GetValueIndex(i))
+	case array.ListLike:
+		start, end := arr.ValueOffsets(i)
+		values := arr.ListValues()
+		vals := make([]interface{}, 0, end-start)
+		for j := int(start); j < int(end); j++ {
+			val, err := arrowValue(values, j)
+			if err != nil {
+				return nil, err
+			}
+			if val != nil {
+				vals = append(vals, val)
+			}
+		}
+		return vals, nil
+	}
+	return nil, errors.Errorf("unsupported type %s", arr.DataType())
+}
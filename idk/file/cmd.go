@@ -0,0 +1,105 @@
+package file
+
+import (
+	"sync"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/pkg/errors"
+)
+
+type Main struct {
+	idk.Main `flag:"!embed"`
+	Files    []string `help:"Files, directories, or s3://<bucket>/<prefix> URIs to ingest."`
+	Format   string   `help:"Format of the files: parquet, ndjson or arrow. By default each file's format is chosen by its extension, and files without a known extension are skipped."`
+	Header   []string `help:"Optional header-style field specs (e.g. price__Decimal_2) overriding the fields inferred for the columns they name. For NDJSON, keys named here are ingested even if they're missing from the first object of a file."`
+	S3Region string   `help:"S3 Region, used when files are S3 URIs. Alternatively, use environment variable AWS_REGION."`
+
+	units chan unit
+}
+
+func NewMain() *Main {
+	m := &Main{
+		Main:  *idk.NewMain(),
+		units: make(chan unit),
+	}
+	m.Main.Namespace = "ingester_file"
+
+	once := &sync.Once{}
+	m.NewSource = func() (idk.Source, error) {
+		if len(m.Files) == 0 {
+			return nil, errors.New("must provide at least one file, directory or S3 prefix with --files")
+		}
+		switch m.Format {
+		case "", FormatParquet, FormatNDJSON, FormatArrow:
+		default:
+			return nil, errors.Errorf("unknown format %q: must be %s, %s or %s", m.Format, FormatParquet, FormatNDJSON, FormatArrow)
+		}
+
+		source := NewSource()
+		source.Header = m.Header
+		source.S3Region = m.S3Region
+		source.Log = m.Main.Log()
+		source.units = m.units
+		if err := source.Open(); err != nil {
+			return nil, errors.Wrap(err, "opening source")
+		}
+		once.Do(func() { go m.streamUnits() })
+		return source, nil
+	}
+	return m
+}
+
+// streamUnits sends the units of every file to the sources. Parquet and
+// Arrow IPC files are opened here to count their row groups or record
+// batches, which only reads their footers.
+func (m *Main) streamUnits() {
+	defer close(m.units)
+
+	l := &locator{s3Region: m.S3Region}
+	for _, name := range m.Files {
+		locations, err := l.list(name)
+		if err != nil {
+			m.units <- unit{err: errors.Wrapf(err, "listing %s", name)}
+			return
+		}
+		for _, location := range locations {
+			format := m.Format
+			if format == "" {
+				format = formatOf(location)
+			}
+			if format == "" {
+				m.Log().Printf("skipping %s, which doesn't have the extension of a known format", location)
+				continue
+			}
+			n, err := countUnits(l, location, format)
+			if err != nil {
+				m.units <- unit{err: errors.Wrapf(err, "reading %s", location)}
+				return
+			}
+			if n < 0 {
+				m.units <- unit{name: location, format: format, index: -1}
+				continue
+			}
+			for i := 0; i < n; i++ {
+				m.units <- unit{name: location, format: format, index: i}
+			}
+		}
+	}
+}
+
+// countUnits returns the number of units in a file, or -1 if it can only
+// be read as a whole.
+func countUnits(l *locator, name, format string) (int, error) {
+	if format == FormatNDJSON {
+		return -1, nil
+	}
+	f, err := l.open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	if format == FormatParquet {
+		return parquetRowGroups(f)
+	}
+	return arrowRecordBatches(f)
+}
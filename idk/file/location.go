@@ -0,0 +1,188 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+)
+
+// readAtSeeker is what the Parquet and Arrow IPC file readers need to
+// read a file's footer and then only the parts of it they're asked for.
+type readAtSeeker interface {
+	io.Reader
+	io.ReaderAt
+	io.Seeker
+	io.Closer
+}
+
+// locator lists and opens local files and S3 objects.
+type locator struct {
+	s3Region string
+	client   *s3.S3
+}
+
+// list returns the files under name, which may be a file, a directory,
+// or an s3://<bucket>/<prefix> URI, in order.
+func (l *locator) list(name string) ([]string, error) {
+	if strings.HasPrefix(name, "s3://") {
+		bucket, prefix, err := parseS3URI(name)
+		if err != nil {
+			return nil, err
+		}
+		client, err := l.s3Client()
+		if err != nil {
+			return nil, err
+		}
+		var locations []string
+		err = client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+			Bucket: aws.String(bucket),
+			Prefix: aws.String(prefix),
+		}, func(page *s3.ListObjectsV2Output, last bool) bool {
+			for _, obj := range page.Contents {
+				locations = append(locations, "s3://"+bucket+"/"+aws.StringValue(obj.Key))
+			}
+			return true
+		})
+		return locations, errors.Wrap(err, "listing S3 objects")
+	}
+
+	name = strings.TrimPrefix(name, "file://")
+	var locations []string
+	err := filepath.Walk(name, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			locations = append(locations, path)
+		}
+		return nil
+	})
+	return locations, err
+}
+
+// open opens a file or S3 object. S3 objects are read with a ranged
+// request for each read, so that only the row groups or record batches
+// being ingested are fetched.
+func (l *locator) open(name string) (readAtSeeker, error) {
+	if !strings.HasPrefix(name, "s3://") {
+		return os.Open(strings.TrimPrefix(name, "file://"))
+	}
+	bucket, key, err := parseS3URI(name)
+	if err != nil {
+		return nil, err
+	}
+	client, err := l.s3Client()
+	if err != nil {
+		return nil, err
+	}
+	head, err := client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting size of S3 object %v", name)
+	}
+	return &s3Object{
+		client: client,
+		bucket: bucket,
+		key:    key,
+		size:   aws.Int64Value(head.ContentLength),
+	}, nil
+}
+
+func (l *locator) s3Client() (*s3.S3, error) {
+	if l.client != nil {
+		return l.client, nil
+	}
+	config := &aws.Config{}
+	if l.s3Region != "" {
+		config.Region = aws.String(l.s3Region)
+		// else, NewSession will use the default region.
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating S3 session")
+	}
+	l.client = s3.New(sess)
+	return l.client, nil
+}
+
+func parseS3URI(name string) (bucket, key string, err error) {
+	u, err := url.Parse(name)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "parsing S3 URL %v", name)
+	}
+	return u.Host, strings.TrimPrefix(u.Path, "/"), nil
+}
+
+// s3Object reads an S3 object with ranged GETs.
+type s3Object struct {
+	client *s3.S3
+	bucket string
+	key    string
+	size   int64
+	off    int64
+}
+
+func (o *s3Object) ReadAt(p []byte, off int64) (int, error) {
+	if off >= o.size {
+		return 0, io.EOF
+	}
+	end := off + int64(len(p))
+	if end > o.size {
+		end = o.size
+	}
+	if end == off {
+		return 0, nil
+	}
+	result, err := o.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(o.bucket),
+		Key:    aws.String(o.key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", off, end-1)),
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "fetching s3://%s/%s", o.bucket, o.key)
+	}
+	defer result.Body.Close()
+	n, err := io.ReadFull(result.Body, p[:end-off])
+	if err == nil && n < len(p) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (o *s3Object) Read(p []byte) (int, error) {
+	n, err := o.ReadAt(p, o.off)
+	o.off += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (o *s3Object) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.off
+	case io.SeekEnd:
+		offset += o.size
+	default:
+		return 0, errors.Errorf("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, errors.New("seeking before the start of the object")
+	}
+	o.off = offset
+	return offset, nil
+}
+
+func (o *s3Object) Close() error { return nil }
@@ -0,0 +1,127 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/pkg/errors"
+)
+
+// ndjsonRows reads the objects of an NDJSON file. Since there's no schema
+// to go by, the fields not named in the header are inferred from the
+// values in the first object, taking its keys in order.
+type ndjsonRows struct {
+	reader *bufio.Reader
+	log    logger.Logger
+
+	fields []idk.Field
+	names  []string
+
+	// pending are the rows read while looking for the first object, which
+	// are delivered before the rest.
+	pending []ndjsonRow
+}
+
+type ndjsonRow struct {
+	line []byte
+	obj  map[string]interface{}
+	err  error
+}
+
+func newNDJSONRows(r io.Reader, log logger.Logger) *ndjsonRows {
+	return &ndjsonRows{
+		reader: bufio.NewReaderSize(r, 1<<20),
+		log:    log,
+	}
+}
+
+// Fields returns the fields of the header, and those inferred from the
+// first object in the file for its other keys. Keys whose first values are
+// null or objects can't be inferred, and are left out.
+func (r *ndjsonRows) Fields(h *header) ([]idk.Field, error) {
+	var first map[string]interface{}
+	for first == nil {
+		row, err := r.read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		r.pending = append(r.pending, row)
+		first = row.obj
+	}
+
+	keys := make([]string, 0, len(first))
+	for key := range first {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fields, names, err := h.apply(keys, true, func(i int) (idk.Field, error) {
+		field, err := idk.InferJSONField(keys[i], first[keys[i]])
+		if field == nil && err == nil {
+			r.log.Printf("leaving out key %s, since its type can't be inferred from %v; give it in the header to ingest it", keys[i], first[keys[i]])
+		}
+		return field, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.fields, r.names = fields, names
+	return fields, nil
+}
+
+// Next returns the values of the next object's keys.
+func (r *ndjsonRows) Next() ([]interface{}, []byte, error) {
+	var row ndjsonRow
+	if len(r.pending) > 0 {
+		row, r.pending = r.pending[0], r.pending[1:]
+	} else {
+		var err error
+		row, err = r.read()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if row.err != nil {
+		return nil, nil, &badRow{payload: row.line, err: row.err}
+	}
+	data := make([]interface{}, len(r.fields))
+	for i, field := range r.fields {
+		data[i] = idk.JSONValue(field, row.obj[r.names[i]])
+	}
+	return data, row.line, nil
+}
+
+// read reads the next non-blank line, and decodes it. An error decoding it
+// is returned in the row, so that the rows after it can still be read.
+func (r *ndjsonRows) read() (ndjsonRow, error) {
+	for {
+		line, err := r.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return ndjsonRow{}, err
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err == io.EOF {
+				return ndjsonRow{}, io.EOF
+			}
+			continue
+		}
+		row := ndjsonRow{line: line}
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		if derr := dec.Decode(&row.obj); derr != nil {
+			row.err = errors.Wrap(derr, "decoding JSON")
+		} else if row.obj == nil {
+			row.err = errors.New("not a JSON object")
+		}
+		return row, nil
+	}
+}
+
+func (r *ndjsonRows) Close() error { return nil }
@@ -0,0 +1,330 @@
+// Package file ingests Parquet, NDJSON and Arrow IPC files from the local
+// filesystem or S3, inferring the fields from the files themselves.
+//
+// Files are split into units which are read independently: each row group
+// of a Parquet file, each record batch of an Arrow IPC file, and the whole
+// of an NDJSON file or Arrow IPC stream. The units of every file are shared
+// among the sources, so that concurrent ingest spreads across row groups as
+// well as files.
+package file
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/pkg/errors"
+)
+
+// Formats of the files which can be ingested.
+const (
+	FormatParquet = "parquet"
+	FormatNDJSON  = "ndjson"
+	FormatArrow   = "arrow"
+)
+
+// formatOf returns the format of a file from its extension, or "" if it
+// isn't one that's known.
+func formatOf(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".parquet", ".parq":
+		return FormatParquet
+	case ".ndjson", ".jsonl", ".json":
+		return FormatNDJSON
+	case ".arrow", ".arrows", ".feather", ".ipc":
+		return FormatArrow
+	}
+	return ""
+}
+
+// unit is the part of a file read by a single source at a time: a row group
+// of a Parquet file, a record batch of an Arrow IPC file, or, if index is
+// negative, the whole file.
+type unit struct {
+	name   string
+	format string
+	index  int
+	err    error
+}
+
+// stream names the unit for the records read from it.
+func (u unit) stream() string {
+	if u.index < 0 {
+		return u.name
+	}
+	return fmt.Sprintf("%s#%d", u.name, u.index)
+}
+
+// rowReader reads the rows of a unit.
+type rowReader interface {
+	// Fields returns the fields of the rows, taking those named in the
+	// header from it, and inferring the rest.
+	Fields(h *header) ([]idk.Field, error)
+
+	// Next returns the values of the next row in the order of its fields,
+	// and the bytes it was decoded from if the format has them. It returns
+	// io.EOF after the last row, and a *badRow for a row which couldn't be
+	// decoded, after which the following rows can still be read.
+	Next() ([]interface{}, []byte, error)
+
+	Close() error
+}
+
+// badRow is returned by a rowReader for a row it couldn't decode.
+type badRow struct {
+	payload []byte
+	err     error
+}
+
+func (e *badRow) Error() string { return e.err.Error() }
+
+// header holds the fields given by header-style specs such as
+// "price__Decimal_2", by the name of the column they're for.
+type header struct {
+	fields []idk.Field
+	names  []string
+	byName map[string]int
+}
+
+func newHeader(specs []string, log logger.Logger) (*header, error) {
+	h := &header{byName: make(map[string]int, len(specs))}
+	for _, spec := range specs {
+		field, err := idk.HeaderToField(spec, log)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid header (%v)", spec)
+		}
+		name := headerName(spec)
+		if _, ok := h.byName[name]; ok {
+			return nil, errors.Errorf("column %s is named in the header more than once", name)
+		}
+		h.byName[name] = len(h.fields)
+		h.fields = append(h.fields, field)
+		h.names = append(h.names, name)
+	}
+	return h, nil
+}
+
+// headerName returns the name of the column a header spec is for. That's
+// its field's name, except for Ignore specs, whose fields have none.
+func headerName(spec string) string {
+	if i := strings.LastIndex(spec, "___"); i >= 0 {
+		return spec[:i]
+	}
+	if i := strings.LastIndex(spec, "__"); i >= 0 {
+		return spec[:i]
+	}
+	return spec
+}
+
+// apply returns the fields of the given columns, from the header if it
+// names them, or from infer. A column for which infer returns no field is
+// left out, and the names of the columns of the fields are returned with
+// them. Header fields for columns which aren't present are an error unless
+// extra is set, in which case they're added to the end.
+func (h *header) apply(columns []string, extra bool, infer func(i int) (idk.Field, error)) ([]idk.Field, []string, error) {
+	fields := make([]idk.Field, 0, len(columns))
+	names := make([]string, 0, len(columns))
+	used := make([]bool, len(h.fields))
+	for i, column := range columns {
+		if j, ok := h.byName[column]; ok {
+			fields, names = append(fields, h.fields[j]), append(names, column)
+			used[j] = true
+			continue
+		}
+		field, err := infer(i)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "inferring field for column %s; give it in the header instead", column)
+		}
+		if field != nil {
+			fields, names = append(fields, field), append(names, column)
+		}
+	}
+	for j, ok := range used {
+		if ok {
+			continue
+		}
+		if !extra {
+			return nil, nil, errors.Errorf("header names column %s, which isn't in the file", h.names[j])
+		}
+		fields, names = append(fields, h.fields[j]), append(names, h.names[j])
+	}
+	return fields, names, nil
+}
+
+// Source reads the units of files sent to it by Main, and delivers their
+// rows. The schema changes whenever a unit's fields differ from the last.
+type Source struct {
+	Header   []string
+	S3Region string
+	Log      logger.Logger
+
+	units   <-chan unit
+	header  *header
+	locator *locator
+
+	schema []idk.Field
+	unit   unit
+	file   readAtSeeker
+	rows   rowReader
+	offset uint64
+
+	// schemaChanged is set when a unit's fields differ from the last's,
+	// until a record is delivered with ErrSchemaChange.
+	schemaChanged bool
+}
+
+// NewSource gets a new Source.
+func NewSource() *Source {
+	return &Source{
+		Log: logger.NopLogger,
+	}
+}
+
+// Open parses the header.
+func (s *Source) Open() (err error) {
+	s.header, err = newHeader(s.Header, s.Log)
+	if err != nil {
+		return errors.Wrap(err, "processing header")
+	}
+	s.locator = &locator{s3Region: s.S3Region}
+	return nil
+}
+
+// Record returns the next row of the current unit, moving on to the next
+// unit once it's done.
+func (s *Source) Record() (idk.Record, error) {
+	for {
+		if s.rows == nil {
+			u, ok := <-s.units
+			if !ok {
+				return nil, io.EOF
+			}
+			if u.err != nil {
+				return nil, u.err
+			}
+			if err := s.openUnit(u); err != nil {
+				return nil, errors.Wrapf(err, "opening %s", u.stream())
+			}
+		}
+
+		data, payload, err := s.rows.Next()
+		if err == io.EOF {
+			if err := s.closeUnit(); err != nil {
+				return nil, errors.Wrapf(err, "closing %s", s.unit.stream())
+			}
+			continue
+		}
+		offset := s.offset
+		s.offset++
+		if bad, ok := err.(*badRow); ok {
+			return nil, &idk.BadRecordError{
+				Stream:  s.unit.stream(),
+				Offset:  offset,
+				Payload: bad.payload,
+				Err:     bad.err,
+			}
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", s.unit.stream())
+		}
+
+		rec := &Record{
+			stream:  s.unit.stream(),
+			offset:  offset,
+			data:    data,
+			payload: payload,
+		}
+		if s.schemaChanged {
+			s.schemaChanged = false
+			return rec, idk.ErrSchemaChange
+		}
+		return rec, nil
+	}
+}
+
+// openUnit opens a unit and works out its fields.
+func (s *Source) openUnit(u unit) error {
+	f, err := s.locator.open(u.name)
+	if err != nil {
+		return err
+	}
+	var rows rowReader
+	switch u.format {
+	case FormatParquet:
+		rows, err = newParquetRows(f, u.index)
+	case FormatArrow:
+		rows, err = newArrowRows(f, u.index)
+	case FormatNDJSON:
+		rows = newNDJSONRows(f, s.Log)
+	default:
+		err = errors.Errorf("unknown format %q", u.format)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	fields, err := rows.Fields(s.header)
+	if err != nil {
+		rows.Close()
+		f.Close()
+		return err
+	}
+	s.Log.Debugf("reading %s", u.stream())
+	s.unit, s.file, s.rows, s.offset = u, f, rows, 0
+	if !reflect.DeepEqual(fields, s.schema) {
+		s.schema, s.schemaChanged = fields, true
+	}
+	return nil
+}
+
+func (s *Source) closeUnit() error {
+	if s.rows == nil {
+		return nil
+	}
+	err := s.rows.Close()
+	if ferr := s.file.Close(); err == nil {
+		err = ferr
+	}
+	s.rows, s.file = nil, nil
+	return err
+}
+
+// Schema returns the fields of the current unit.
+func (s *Source) Schema() []idk.Field {
+	return s.schema
+}
+
+// Close closes the unit being read, if any.
+func (s *Source) Close() error {
+	return s.closeUnit()
+}
+
+// Record is a row of a file. Its stream is the unit it was read from, and
+// its offset is its position in the unit, so IDs allocated by offset stay
+// the same if a file is ingested again.
+type Record struct {
+	stream  string
+	offset  uint64
+	data    []interface{}
+	payload []byte
+}
+
+var _ idk.OffsetStreamRecord = (*Record)(nil)
+var _ idk.PayloadRecord = (*Record)(nil)
+
+// Commit does nothing; there's nothing to keep track of in a file.
+func (r *Record) Commit(ctx context.Context) error { return nil }
+
+func (r *Record) Data() []interface{} { return r.data }
+
+func (r *Record) Schema() interface{} { return nil }
+
+func (r *Record) StreamOffset() (string, uint64) { return r.stream, r.offset }
+
+// Payload returns the line an NDJSON record was decoded from, and nil for
+// other formats.
+func (r *Record) Payload() []byte { return r.payload }
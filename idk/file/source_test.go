@@ -0,0 +1,294 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v10/arrow"
+	"github.com/apache/arrow/go/v10/arrow/array"
+	"github.com/apache/arrow/go/v10/arrow/decimal128"
+	"github.com/apache/arrow/go/v10/arrow/ipc"
+	"github.com/apache/arrow/go/v10/arrow/memory"
+	"github.com/apache/arrow/go/v10/parquet"
+	"github.com/apache/arrow/go/v10/parquet/pqarrow"
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/pkg/errors"
+)
+
+var testArrowSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+	{Name: "price", Type: &arrow.Decimal128Type{Precision: 10, Scale: 2}},
+	{Name: "score", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+	{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Millisecond}},
+}, nil)
+
+// testArrowRecord returns a record of the rows with IDs from start up to
+// end. The row with ID 3 has no name.
+func testArrowRecord(start, end int) arrow.Record {
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), testArrowSchema)
+	defer b.Release()
+	for i := start; i < end; i++ {
+		b.Field(0).(*array.Int64Builder).Append(int64(i))
+		if i == 3 {
+			b.Field(1).AppendNull()
+		} else {
+			b.Field(1).(*array.StringBuilder).Append(fmt.Sprintf("n%d", i))
+		}
+		b.Field(2).(*array.Decimal128Builder).Append(decimal128.FromI64(int64(i*100 + 5)))
+		b.Field(3).(*array.Float64Builder).Append(float64(i) + 0.5)
+		tags := b.Field(4).(*array.ListBuilder)
+		tags.Append(true)
+		tags.ValueBuilder().(*array.StringBuilder).AppendValues([]string{"a", fmt.Sprintf("t%d", i)}, nil)
+		b.Field(5).(*array.TimestampBuilder).Append(arrow.Timestamp(i * 1000))
+	}
+	return b.NewRecord()
+}
+
+// testRow returns the values expected for the row with ID i.
+func testRow(i int) []interface{} {
+	var name interface{} = fmt.Sprintf("n%d", i)
+	if i == 3 {
+		name = nil
+	}
+	return []interface{}{
+		int64(i),
+		name,
+		pql.NewDecimal(int64(i*100+5), 2),
+		float64(i) + 0.5,
+		[]interface{}{"a", fmt.Sprintf("t%d", i)},
+		time.UnixMilli(int64(i * 1000)).UTC(),
+	}
+}
+
+func testMain(t *testing.T, files []string, header []string) *Main {
+	t.Helper()
+	m := NewMain()
+	m.SetLog(logger.NopLogger)
+	m.Files = files
+	m.Header = header
+	return m
+}
+
+// readAll reads every record from the sources in turn, returning them by
+// stream and offset, and the schema of the first.
+func readAll(t *testing.T, sources ...idk.Source) (map[string][]interface{}, []idk.Field) {
+	t.Helper()
+	rows := make(map[string][]interface{})
+	var schema []idk.Field
+	done := make([]bool, len(sources))
+	for ndone := 0; ndone < len(sources); {
+		for i, src := range sources {
+			if done[i] {
+				continue
+			}
+			rec, err := src.Record()
+			if err == io.EOF {
+				done[i] = true
+				ndone++
+				continue
+			} else if err == idk.ErrSchemaChange {
+				if schema == nil {
+					schema = src.Schema()
+				}
+			} else if err != nil {
+				t.Fatalf("reading record: %v", err)
+			}
+			stream, offset := rec.(idk.OffsetStreamRecord).StreamOffset()
+			data := rec.Data()
+			if ts, ok := data[len(data)-1].(time.Time); ok {
+				data[len(data)-1] = ts.UTC()
+			}
+			rows[fmt.Sprintf("%s:%d", stream, offset)] = data
+		}
+	}
+	return rows, schema
+}
+
+func newSources(t *testing.T, m *Main, n int) []idk.Source {
+	t.Helper()
+	sources := make([]idk.Source, n)
+	for i := range sources {
+		var err error
+		sources[i], err = m.NewSource()
+		if err != nil {
+			t.Fatalf("getting source: %v", err)
+		}
+	}
+	return sources
+}
+
+func TestParquetSource(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "data.parquet")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := testArrowRecord(0, 5)
+	tbl := array.NewTableFromRecords(testArrowSchema, []arrow.Record{rec})
+	// two rows per row group
+	if err := pqarrow.WriteTable(tbl, f, 2, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps()); err != nil {
+		t.Fatalf("writing parquet: %v", err)
+	}
+	// files of unknown formats are skipped
+	if err := os.WriteFile(filepath.Join(dir, "_SUCCESS"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := testMain(t, []string{dir}, []string{"score__Decimal_1"})
+	rows, schema := readAll(t, newSources(t, m, 2)...)
+	exp := []idk.Field{
+		idk.IntField{NameVal: "id"},
+		idk.StringField{NameVal: "name"},
+		idk.DecimalField{NameVal: "price", Scale: 2},
+		idk.DecimalField{NameVal: "score", DestNameVal: "score", Scale: 1},
+		idk.StringArrayField{NameVal: "tags"},
+		idk.TimestampField{NameVal: "ts", Granularity: "ms"},
+	}
+	if !reflect.DeepEqual(schema, exp) {
+		t.Errorf("unexpected schema %v", schema)
+	}
+	if len(rows) != 5 {
+		t.Errorf("expected 5 rows, got %d: %v", len(rows), rows)
+	}
+	for i := 0; i < 5; i++ {
+		key := fmt.Sprintf("%s#%d:%d", name, i/2, i%2)
+		if !reflect.DeepEqual(rows[key], testRow(i)) {
+			t.Errorf("unexpected row %s: %v", key, rows[key])
+		}
+	}
+
+	m = testMain(t, []string{name}, []string{"missing__String"})
+	src := newSources(t, m, 1)[0]
+	if _, err := src.Record(); err == nil {
+		t.Errorf("expected error for header naming a column not in the file")
+	}
+}
+
+func TestArrowSource(t *testing.T) {
+	dir := t.TempDir()
+	recs := []arrow.Record{testArrowRecord(0, 2), testArrowRecord(2, 5)}
+
+	fileName := filepath.Join(dir, "data.arrow")
+	f, err := os.Create(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw, err := ipc.NewFileWriter(f, ipc.WithSchema(testArrowSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range recs {
+		if err := fw.Write(rec); err != nil {
+			t.Fatalf("writing arrow file: %v", err)
+		}
+	}
+	if err := fw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	streamName := filepath.Join(dir, "data.arrows")
+	f, err = os.Create(streamName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sw := ipc.NewWriter(f, ipc.WithSchema(testArrowSchema))
+	for _, rec := range recs {
+		if err := sw.Write(rec); err != nil {
+			t.Fatalf("writing arrow stream: %v", err)
+		}
+	}
+	if err := sw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	m := testMain(t, []string{dir}, nil)
+	rows, schema := readAll(t, newSources(t, m, 3)...)
+	if exp := (idk.FloatField{NameVal: "score"}); !reflect.DeepEqual(schema[3], exp) {
+		t.Errorf("unexpected field %v", schema[3])
+	}
+	if len(rows) != 10 {
+		t.Errorf("expected 10 rows, got %d: %v", len(rows), rows)
+	}
+	for i := 0; i < 5; i++ {
+		batch, offset := 0, i
+		if i >= 2 {
+			batch, offset = 1, i-2
+		}
+		for _, key := range []string{
+			fmt.Sprintf("%s#%d:%d", fileName, batch, offset),
+			fmt.Sprintf("%s:%d", streamName, i),
+		} {
+			if !reflect.DeepEqual(rows[key], testRow(i)) {
+				t.Errorf("unexpected row %s: %v", key, rows[key])
+			}
+		}
+	}
+}
+
+func TestNDJSONSource(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "data.json")
+	content := `{"b": 1, "a": "x", "c": [1, 2], "d": null, "e": 1.25, "f": 3}
+
+not json
+{"a": "y", "b": 2, "f": "2.50", "g": true}
+`
+	if err := os.WriteFile(name, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m := testMain(t, []string{name}, []string{"f__Decimal_2", "g__Bool"})
+	src := newSources(t, m, 1)[0]
+
+	rec, err := src.Record()
+	if err != idk.ErrSchemaChange {
+		t.Fatalf("expected schema change, got %v", err)
+	}
+	exp := []idk.Field{
+		idk.StringField{NameVal: "a"},
+		idk.IntField{NameVal: "b"},
+		idk.IDArrayField{NameVal: "c"},
+		idk.FloatField{NameVal: "e"},
+		idk.DecimalField{NameVal: "f", DestNameVal: "f", Scale: 2},
+		idk.BoolField{NameVal: "g", DestNameVal: "g"},
+	}
+	if !reflect.DeepEqual(src.Schema(), exp) {
+		t.Errorf("unexpected schema %v", src.Schema())
+	}
+	if exp := []interface{}{"x", int64(1), []interface{}{int64(1), int64(2)}, 1.25, "3", nil}; !reflect.DeepEqual(rec.Data(), exp) {
+		t.Errorf("unexpected data %v", rec.Data())
+	}
+
+	_, err = src.Record()
+	var bad *idk.BadRecordError
+	if !errors.As(err, &bad) {
+		t.Fatalf("expected bad record, got %v", err)
+	}
+	if bad.Stream != name || bad.Offset != 1 || string(bad.Payload) != "not json" {
+		t.Errorf("unexpected bad record %+v", bad)
+	}
+
+	rec, err = src.Record()
+	if err != nil {
+		t.Fatalf("reading record: %v", err)
+	}
+	if exp := []interface{}{"y", int64(2), nil, nil, "2.50", true}; !reflect.DeepEqual(rec.Data(), exp) {
+		t.Errorf("unexpected data %v", rec.Data())
+	}
+	if _, offset := rec.(idk.OffsetStreamRecord).StreamOffset(); offset != 2 {
+		t.Errorf("unexpected offset %d", offset)
+	}
+	if _, err := src.Record(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
@@ -21,6 +21,7 @@ const (
 	IntType              FieldType = "int"
 	ForeignKeyType       FieldType = "foreignkey"
 	DecimalType          FieldType = "decimal"
+	FloatType            FieldType = "float"
 	StringArrayType      FieldType = "stringarray"
 	IDArrayType          FieldType = "idarray"
 	DateIntType          FieldType = "dateint"
@@ -88,6 +89,11 @@ func HeaderToField(headerField string, log logger.Logger) (field Field, _ error)
 		field, err = headerToForeignKeyField(headerField, sourceName, destName, fieldspec, log)
 	case DecimalType:
 		field, err = headerToDecimalField(headerField, sourceName, destName, fieldspec, log)
+	case FloatType:
+		field = FloatField{NameVal: sourceName, DestNameVal: destName}
+		if len(fieldspec) > 1 {
+			log.Printf("ignoring extra arguments to FloatField %s: %v", headerField, fieldspec[1:])
+		}
 	case StringArrayType:
 		field, err = headerToStringArrayField(headerField, sourceName, destName, fieldspec, log)
 	case IDArrayType:
@@ -619,6 +625,17 @@ func ParseHeader(raw []byte) ([]Field, PathTable, error) {
 			field.NameVal = s.Name
 			fields[i] = field
 
+		case "float":
+			var field FloatField
+			if s.Config != nil {
+				err := json.Unmarshal(s.Config, &field)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, ErrDecodingConfig, s.Name)
+				}
+			}
+			field.NameVal = s.Name
+			fields[i] = field
+
 		case "signedIntBoolKey":
 			var field SignedIntBoolKeyField
 			if s.Config != nil {
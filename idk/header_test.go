@@ -163,6 +163,11 @@ func TestHeaderToField(t *testing.T) {
 			input: "myname__Decimal",
 			exp:   DecimalField{NameVal: "myname", DestNameVal: "myname"},
 		},
+		{
+			name:  "float",
+			input: "myname__Float",
+			exp:   FloatField{NameVal: "myname", DestNameVal: "myname"},
+		},
 		{
 			name:   "decimal-err",
 			input:  "myname__Decimal_!",
@@ -101,7 +101,7 @@ func TestOffsetBasedIDAllocation(t *testing.T) {
 
 			c := ingester.c
 			l := &msgCounter{MaxMsgs: 100}
-			err := ingester.m.runIngester(c, l)
+			err := ingester.m.runIngester(context.Background(), c, l)
 			ingester.err = err
 		}()
 	}
@@ -16,6 +16,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"reflect"
 	"runtime"
 	"strings"
 	"sync"
@@ -78,7 +79,7 @@ type Main struct {
 	Delete                   bool          `help:"If true, delete records rather than write them." flag:"-"`
 	Pprof                    string        `short:"o" help:"host:port on which to listen for pprof"`
 	Stats                    string        `short:"s" help:"host:port on which to host metrics"`
-	ExpSplitBatchMode        bool          `short:"x" help:"Tell featurebase client to build bitmaps locally over many batches and import them at the end. Experimental. Does not support int or mutex fields. Don't use this unless you know what you're doing."`
+	ExpSplitBatchMode        bool          `short:"x" help:"Tell featurebase client to build bitmaps locally over many batches and import them at the end. Experimental. Don't use this unless you know what you're doing."`
 	AssumeEmptyPilosa        bool          `short:"u" help:"Alias for --assume-empty-featurebase. Will be deprecated in the next major release."`
 	AssumeEmptyFeaturebase   bool          `short:"" help:"Setting this means that you're doing an initial bulk ingest which assumes that data does not need to be cleared/unset in FeatureBase. There are various performance enhancements that can be made in this case. For example, for booleans if a false value comes in, we'll just set the bit in the bools-exists field... we won't clear it in the bools field."`
 	WriteCSV                 string        `short:"" help:"Write data we're ingesting to a CSV file with the given name."`
@@ -95,6 +96,11 @@ type Main struct {
 	AllowDecimalOutOfRange   bool          `help:"Allow ingest to continue when it encounters out of range decimals in DecimalFields. (default false)"`
 	AllowTimestampOutOfRange bool          `help:"Allow ingest to continue when it encounters out of range timestamps in TimestampFields. (default false)"`
 	SkipBadRows              int           `help:"If you fail to process the first n rows without processing one successfully, fail."`
+	DeadLetterSink           string        `help:"Write records which can't be ingested to this dead-letter sink instead of failing or skipping them: a local NDJSON file, kafka://<host:port>[,<host:port>...]/<topic>, or s3://<bucket>/<prefix>."`
+	DeadLetterS3Region       string        `help:"AWS region of an s3:// dead-letter sink."`
+	Transforms               string        `help:"JSON file of transforms to apply to records before they're ingested: a filter, fields derived from SQL expressions, string fields to split into sets, and fields to hash."`
+	SchemaEvolution          string        `help:"What to do when the source's schema changes: strict (stop on any change), additive (create added fields, stop when a field no longer matches FeatureBase) or coerce (also alter FeatureBase fields to widen them where compatible, and ingest other changed fields into new versioned fields)."`
+	CheckpointKey            string        `help:"Store source offsets in FeatureBase under this key, once the data imported from them is, and resume from them rather than from offsets committed to the source, importing each record at least once. Requires use-shard-transactional-endpoint."`
 
 	UseShardTransactionalEndpoint bool `flag:"use-shard-transactional-endpoint" help:"Use alternate import endpoint that ingests data for all fields in a shard in a single atomic request. No negative performance impact and better consistency. Recommended."`
 
@@ -105,6 +111,13 @@ type Main struct {
 
 	csvWriter *csv.Writer
 	csvFile   *os.File
+
+	deadLetters DeadLetterSink
+	transforms  *transformConfig
+
+	// prevSchema is the schema of the last batch, which the next is
+	// compared with.
+	prevSchema []Field
 	// TODO implement the auto-generated IDs... hopefully using Pilosa to manage it.
 	TLS TLSConfig
 
@@ -116,6 +129,10 @@ type Main struct {
 	// cmd.go
 	NewSource func() (Source, error) `flag:"-"`
 
+	// DeadLetters, if set, is the dead-letter sink to write records which
+	// can't be ingested to, rather than one opened from DeadLetterSink.
+	DeadLetters DeadLetterSink `flag:"-"`
+
 	lookupClient *PostgresClient
 
 	client     *pilosaclient.Client
@@ -223,6 +240,7 @@ func NewMain() *Main {
 		Concurrency:      1,
 		CacheLength:      64,
 		PackBools:        "bools",
+		SchemaEvolution:  SchemaEvolutionAdditive,
 		Namespace:        "ingester", // this is now ignored and hardcoded in metrics.go
 		IDAllocKeyPrefix: "ingest",
 
@@ -253,6 +271,12 @@ func (m *Main) SetBasic() {
 }
 
 func (m *Main) Run() (err error) {
+	return m.RunContext(context.Background())
+}
+
+// RunContext is Run with a context for the ingesters, which stop importing
+// once it's done.
+func (m *Main) RunContext(ctx context.Context) (err error) {
 	if !m.basic {
 		m.log.Printf("Molecula Consumer %s, build time %s\n", Version, BuildTime)
 	}
@@ -262,14 +286,14 @@ func (m *Main) Run() (err error) {
 		return errors.Wrap(err, "setting up")
 	}
 	defer onFinishRun()
-	err = m.run()
+	err = m.run(ctx)
 	if err != nil && err != io.EOF {
 		return err
 	}
 	return nil
 }
 
-func (m *Main) run() error {
+func (m *Main) run(ctx context.Context) error {
 	m.log.Debugf("Ingest Config: %+v", m)
 
 	eg := errgroup.Group{}
@@ -288,7 +312,7 @@ func (m *Main) run() error {
 		for c := 0; c < m.Concurrency; c++ {
 			c := c
 			eg.Go(func() error {
-				err := m.runIngester(c, l)
+				err := m.runIngester(ctx, c, l)
 				if err != nil && err != io.EOF {
 					return err
 				}
@@ -316,10 +340,10 @@ func (m *Main) clone() (*Main, error) {
 	return &mClone, nil
 }
 
-func (m *Main) runIngester(c int, l *msgCounter) error {
+func (m *Main) runIngester(ctx context.Context, c int, l *msgCounter) error {
 	m.log.Printf("start ingester %d", c)
 	// TODO: actually implement cancellation and graceful shutdown
-	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), contextKeyToken, m.AuthToken))
+	ctx, cancel := context.WithCancel(context.WithValue(ctx, contextKeyToken, m.AuthToken))
 	defer cancel()
 
 	// get source
@@ -360,10 +384,12 @@ func (m *Main) ingest(ctx context.Context, source Source, nexter IDAllocator, so
 	}()
 	var batch pilosabatch.RecordBatch
 	var recordizers []Recordizer
+	var transform *transformer
 	var prevRec Record
 	var row *pilosabatch.Row
 	var errorCounter int // keeps track of consecuitive errors across records
 	var anyRecordSuccessful bool
+	changes := newChangeSet()
 	if m.progress != nil {
 		source = m.progress.Track(source)
 	}
@@ -399,6 +425,56 @@ initialFetch:
 		lookupRow = make([]interface{}, len(lookupFieldNames)+1) // re-use. +1 for id column
 	}
 	recordCounter := 0
+	// offsets are those following the records consumed since the last
+	// import, by stream, to be checkpointed with the next one, along with
+	// the positions of the records whose sources have them. Sources which
+	// resume from a checkpoint, like Kafka's, give each record the offset
+	// of the one after it.
+	var offsets map[string]uint64
+	var positions map[string]string
+	if m.CheckpointKey != "" {
+		offsets = make(map[string]uint64)
+		positions = make(map[string]string)
+	}
+	consumed := func(rec Record) error {
+		if offsets == nil {
+			return nil
+		}
+		osr, ok := rec.(OffsetStreamRecord)
+		if !ok {
+			return errors.New("checkpoint-key needs a source whose records have offsets")
+		}
+		stream, offset := osr.StreamOffset()
+		offsets[stream] = offset
+		if psr, ok := rec.(PositionRecord); ok {
+			if position := psr.StreamPosition(); position != "" {
+				positions[stream] = position
+			}
+		}
+		return nil
+	}
+	// importBatch imports the batch, with a checkpoint of the records
+	// consumed if there's a key for one. The records changed by change
+	// records in the batch are cleared in the same transactions.
+	importBatch := func() error {
+		if offsets != nil {
+			if err := m.setCheckpoint(ctx, batch, offsets, positions); err != nil {
+				return err
+			}
+		}
+		if err := m.importBatch(batch); err != nil {
+			return err
+		}
+		CounterIngesterChangeDeletes.Add(float64(changes.Len()))
+		changes.reset()
+		for stream := range offsets {
+			delete(offsets, stream)
+		}
+		for stream := range positions {
+			delete(positions, stream)
+		}
+		return nil
+	}
 	next := func() {
 		if limitCounter.IsDone() {
 			return
@@ -408,9 +484,9 @@ initialFetch:
 	for ; !limitCounter.IsDone(); next() {
 		recordCounter++
 		if err == ErrFlush {
-			if batch != nil && batch.Len() > 0 {
-				batchLen := batch.Len()
-				if err := m.importBatch(batch); err != nil {
+			if batch != nil && (batch.Len() > 0 || changes.Len() > 0) {
+				batchLen := batch.Len() + changes.deletes
+				if err := importBatch(); err != nil {
 					return errors.Wrap(err, "importing batch after timeout")
 				}
 
@@ -435,11 +511,22 @@ initialFetch:
 			}
 			continue
 		}
+		// A record the source couldn't decode is set aside, if there's
+		// somewhere to put it.
+		var badRecord *BadRecordError
+		if m.deadLetters != nil && errors.As(err, &badRecord) {
+			if werr := m.deadLetters.Write(m.badRecordDeadLetter(badRecord)); werr != nil {
+				return errors.Wrap(werr, "writing dead letter")
+			}
+			CounterIngesterDeadLetters.Inc()
+			m.log.Errorf("bad record written to dead-letter sink: %v", err)
+			continue
+		}
 		if err != nil {
 			// finish previous batch if this is not the first
-			if batch != nil && batch.Len() > 0 {
-				batchLen := batch.Len()
-				ierr := m.importBatch(batch)
+			if batch != nil && (batch.Len() > 0 || changes.Len() > 0) {
+				batchLen := batch.Len() + changes.deletes
+				ierr := importBatch()
 				if ierr != nil {
 					return errors.Wrapf(ierr, "importing after error getting record: %v", err)
 				} else if !errors.Is(err, io.EOF) {
@@ -476,6 +563,13 @@ initialFetch:
 				} else {
 					m.log.Printf("new schema: %#v", schema)
 				}
+				if m.transforms != nil {
+					transform, err = m.transforms.compile(ctx, schema, m.log)
+					if err != nil {
+						return errors.Wrap(err, "compiling transforms")
+					}
+					schema = transform.schema
+				}
 				recordizers, batch, row, lookupWriteIdxs, err = m.batchFromSchema(schema)
 				if err != nil {
 					return errors.Wrap(err, "batchFromSchema")
@@ -504,7 +598,32 @@ initialFetch:
 		}
 		data := rec.Data()
 
-		if m.csvWriter != nil {
+		// A transform that fails is handled like a record that can't be
+		// recordized.
+		rowHasError := false
+		if transform != nil {
+			change, _ := rec.(ChangeRecord)
+			var keep bool
+			data, keep, err = transform.apply(data, change != nil && change.Deleted())
+			if err != nil {
+				err = errors.Wrap(err, "transforming")
+				rowHasError = true
+				if m.deadLetters == nil {
+					if m.SkipBadRows == 0 {
+						return err
+					}
+					m.Log().Errorf("Bad record: %v, reason: %v\n", rec.Data(), err)
+				}
+			} else if !keep {
+				CounterIngesterFiltered.Inc()
+				if err := consumed(rec); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if m.csvWriter != nil && !rowHasError {
 			for i, item := range data {
 				var cerr error
 				csvSlice[i], cerr = toString(item)
@@ -518,15 +637,19 @@ initialFetch:
 			}
 		}
 
-		rowHasError := false
-		for _, rdz := range recordizers {
-			err = rdz(data, row)
+		for i := 0; i < len(recordizers) && !rowHasError; i++ {
+			err = recordizers[i](data, row)
 			if err != nil {
 				err = errors.Wrap(err, "recordizing")
 				// excludes 'out of range' errors so that ingest can continue importing the rest
 				// of the records
 				if !m.allowError(err) {
 					rowHasError = true
+					// rejected records go to the dead-letter sink, if
+					// there is one
+					if m.deadLetters != nil {
+						break
+					}
 					// must return error and exit idk when SkipBadRows is not defined (or set to 0)
 					if m.SkipBadRows == 0 {
 						return err
@@ -540,7 +663,14 @@ initialFetch:
 			}
 		}
 
-		if !anyRecordSuccessful && rowHasError {
+		if rowHasError && m.deadLetters != nil {
+			if werr := m.deadLetters.Write(m.newDeadLetter(rec, source.Schema(), err)); werr != nil {
+				return errors.Wrap(werr, "writing dead letter")
+			}
+			CounterIngesterDeadLetters.Inc()
+			m.Log().Errorf("Bad record written to dead-letter sink, reason: %v\n", err)
+			err = nil
+		} else if !anyRecordSuccessful && rowHasError {
 			// We cannot allow a certain number of consecutive errors in the beginning of ingest.
 			errorCounter++
 			if errorCounter > m.SkipBadRows {
@@ -576,6 +706,9 @@ initialFetch:
 					// implementing offset fast-forward will require extending a source's
 					// API which we should keep as minimal as possible
 					m.log.Printf(esync.Error())
+					if err := consumed(rec); err != nil {
+						return err
+					}
 					continue
 				}
 				return errors.Wrap(err, "getting next ID")
@@ -583,15 +716,47 @@ initialFetch:
 			row.ID = id
 		}
 
+		// A change record replaces or deletes the record with its ID, so
+		// the batch clears that record when it's imported. If the record
+		// has already changed in this batch, that batch is imported first.
+		deleted := false
+		if change, ok := rec.(ChangeRecord); ok && !rowHasError {
+			if nexter != nil {
+				return errors.New("change records need primary-key-fields or id-field to identify the records they change")
+			}
+			if changes.Has(row.ID) {
+				batchLen := batch.Len() + changes.deletes
+				if err := importBatch(); err != nil {
+					return errors.Wrap(err, "importing batch before repeated change")
+				}
+				if err := m.commitRecord(ctx, prevRec, limitCounter, uint64(batchLen)); err != nil {
+					return errors.Wrap(err, "committing before repeated change")
+				}
+			}
+			deleted = change.Deleted()
+			if err := changes.Add(row.ID, deleted); err != nil {
+				return errors.Wrap(err, "adding change")
+			}
+			if err := m.clearChanged(batch, row.ID); err != nil {
+				return err
+			}
+		}
+
+		// The record is consumed once it's in the batch, or set aside, so
+		// it's only checkpointed after any import of the batch before it.
+		if err := consumed(rec); err != nil {
+			return err
+		}
+
 		// skip bad rows only
-		if !rowHasError {
+		if !rowHasError && !deleted {
 			err = batch.Add(*row)
 			CounterIngesterRowsAdded.Inc()
 		}
 
 		if err == pilosabatch.ErrBatchNowFull || err == pilosabatch.ErrBatchNowStale {
-			batchLen := batch.Len()
-			err = m.importBatch(batch)
+			batchLen := batch.Len() + changes.deletes
+			err = importBatch()
 			if err != nil {
 				return errors.Wrap(err, "importing batch")
 			}
@@ -851,8 +1016,10 @@ func (m *Main) setup() (onFinishRun func(), err error) {
 		}()
 	}
 
-	if err := m.setupStats(); err != nil {
-		m.log.Printf("setting up stats: %v", err)
+	if m.Stats != "" {
+		if err := m.setupStats(); err != nil {
+			m.log.Printf("setting up stats: %v", err)
+		}
 	}
 
 	// set up Pilosa client
@@ -883,7 +1050,24 @@ func (m *Main) setup() (onFinishRun func(), err error) {
 	}
 
 	keyTranslation := len(m.PrimaryKeyFields) > 0
-	m.index = schema.Index(m.Index, pilosaclient.OptIndexKeys(keyTranslation))
+	indexOpts := []pilosaclient.IndexOption{pilosaclient.OptIndexKeys(keyTranslation)}
+	pkFields := make([]string, len(m.PrimaryKeyFields))
+	for i, pk := range m.PrimaryKeyFields {
+		pkFields[i] = strings.TrimSpace(pk)
+	}
+	if schema.HasIndex(m.Index) {
+		// An existing index keeps the keys it has. Only one which declares
+		// its primary key has composite keys; others have the values of
+		// PrimaryKeyFields joined as they always have been.
+		if existing := schema.Index(m.Index).Opts().PrimaryKey(); len(existing) > 0 && !reflect.DeepEqual(existing, pkFields) {
+			return nil, errors.Errorf("PrimaryKeyFields %v don't match the primary key %v of the existing index", pkFields, existing)
+		}
+	} else if len(pkFields) > 1 {
+		// Several primary key fields make up a composite key, whose parts
+		// can be queried separately.
+		indexOpts = append(indexOpts, pilosaclient.OptIndexPrimaryKey(pkFields...))
+	}
+	m.index = schema.Index(m.Index, indexOpts...)
 	if err := m.SchemaManager.SyncIndex(m.index); err != nil {
 		return nil, errors.Wrap(err, "syncing index")
 	}
@@ -904,6 +1088,22 @@ func (m *Main) setup() (onFinishRun func(), err error) {
 		m.csvWriter = csv.NewWriter(m.csvFile)
 	}
 
+	if m.DeadLetters != nil {
+		m.deadLetters = m.DeadLetters
+	} else if m.DeadLetterSink != "" {
+		m.deadLetters, err = OpenDeadLetterSink(m.DeadLetterSink, m.DeadLetterS3Region)
+		if err != nil {
+			return nil, errors.Wrap(err, "opening dead-letter sink")
+		}
+	}
+
+	if m.Transforms != "" {
+		m.transforms, err = loadTransformConfig(m.Transforms)
+		if err != nil {
+			return nil, errors.Wrap(err, "loading transforms")
+		}
+	}
+
 	if m.Delete {
 		grpcClient, err := pilosagrpc.NewGRPCClient(m.PilosaGRPCHosts, tlsConfig, m.log)
 		if err != nil {
@@ -1033,6 +1233,12 @@ func (m *Main) setup() (onFinishRun func(), err error) {
 				m.log.Printf("closing CSV file: %v", err)
 			}
 		}
+		if m.deadLetters != nil {
+			err := m.deadLetters.Close()
+			if err != nil {
+				m.log.Printf("closing dead-letter sink: %v", err)
+			}
+		}
 		if m.metricsServer != nil {
 			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 			defer cancel()
@@ -1065,6 +1271,12 @@ func (m *Main) commitRecord(ctx context.Context, rec Record, limitCounter *msgCo
 		ctx, cancel = context.WithTimeout(ctx, m.CommitTimeout)
 		defer cancel()
 	}
+	// Records set aside must be kept before the source moves past them.
+	if m.deadLetters != nil {
+		if err := m.deadLetters.Flush(ctx); err != nil {
+			return errors.Wrap(err, "flushing dead letters")
+		}
+	}
 	err := rec.Commit(ctx)
 	if err != nil {
 		return errors.Wrap(err, "committing")
@@ -1435,6 +1647,12 @@ func (m *Main) runDeleter(limitCounter *msgCounter) error {
 						return errors.Wrap(err, "clearing decimal")
 					}
 					CounterDeleterRowsAdded.With(prom.Labels{"type": "decimal"}).Inc()
+				case pilosaclient.FieldTypeFloat:
+					_, err := client.Query(field.Clear(0, recordID))
+					if err != nil {
+						return errors.Wrap(err, "clearing float")
+					}
+					CounterDeleterRowsAdded.With(prom.Labels{"type": "float"}).Inc()
 				case pilosaclient.FieldTypeTime:
 					return errors.Errorf("deletion on time fields unimplemented")
 				default:
@@ -1534,7 +1752,7 @@ func (m *Main) runDeleter(limitCounter *msgCounter) error {
 								return errors.Errorf("set field %s should have keys true or false", field.Name())
 							}
 						}
-					case pilosaclient.FieldTypeInt, pilosaclient.FieldTypeDecimal, pilosaclient.FieldTypeTimestamp:
+					case pilosaclient.FieldTypeInt, pilosaclient.FieldTypeDecimal, pilosaclient.FieldTypeFloat, pilosaclient.FieldTypeTimestamp:
 						if boolVal, ok := value.(bool); ok {
 							if boolVal {
 								bq.Add(field.Clear(0, recordID))
@@ -1696,6 +1914,38 @@ func (m *Main) findPrimary() (*url.URL, error) {
 	return nil, errors.New("primary node not found")
 }
 
+// checkpointBatch is a batch which can import an ingest checkpoint along
+// with its records.
+type checkpointBatch interface {
+	SetCheckpoint(*pilosacore.IngestCheckpoint) error
+}
+
+// setCheckpoint attaches a checkpoint of offsets and positions to the
+// batch. Dead letters are flushed first, since the records set aside are
+// consumed once the checkpoint is stored.
+func (m *Main) setCheckpoint(ctx context.Context, batch pilosabatch.RecordBatch, offsets map[string]uint64, positions map[string]string) error {
+	cb, ok := batch.(checkpointBatch)
+	if !ok {
+		return errors.Errorf("batch type %T doesn't support checkpoints", batch)
+	}
+	if m.deadLetters != nil {
+		if err := m.deadLetters.Flush(ctx); err != nil {
+			return errors.Wrap(err, "flushing dead letters")
+		}
+	}
+	return errors.Wrap(cb.SetCheckpoint(&pilosacore.IngestCheckpoint{Key: m.CheckpointKey, Offsets: offsets, Positions: positions}), "setting checkpoint")
+}
+
+// Checkpoint returns the offsets and positions stored under CheckpointKey,
+// by stream, for sources to resume from.
+func (m *Main) Checkpoint() (map[string]uint64, map[string]string, error) {
+	checkpoint, err := m.client.IngestCheckpoint(m.Index, m.CheckpointKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "reading checkpoint")
+	}
+	return checkpoint.Offsets, checkpoint.Positions, nil
+}
+
 // importBatch executes batch.Import() and saves its timing.
 func (m *Main) importBatch(batch pilosabatch.RecordBatch) error {
 	t := time.Now()
@@ -1722,6 +1972,10 @@ func (m *Main) batchFromSchema(schema []Field) ([]Recordizer, pilosabatch.Record
 			dedup[name] = struct{}{}
 		}
 	}
+	if err := m.checkSchemaChange(schema); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	sourceSchema := schema
 
 	// From the schema, and the configuration stored on Main, we need
 	// to create a []pilosacore.Field and a []Recordizer processing
@@ -1762,7 +2016,8 @@ func (m *Main) batchFromSchema(schema []Field) ([]Recordizer, pilosabatch.Record
 
 	// primary key stuff
 	if len(m.PrimaryKeyFields) != 0 {
-		rz, skips, err = getPrimaryKeyRecordizer(schema, m.PrimaryKeyFields)
+		composite := m.index != nil && len(m.index.Opts().PrimaryKey()) > 0
+		rz, skips, err = getPrimaryKeyRecordizer(schema, m.PrimaryKeyFields, composite)
 		if err != nil {
 			return nil, nil, nil, nil, errors.Wrap(err, "getting primary key recordizer")
 		}
@@ -1806,6 +2061,13 @@ func (m *Main) batchFromSchema(schema []Field) ([]Recordizer, pilosabatch.Record
 		recordizers = append(recordizers, rz)
 	}
 
+	// Fields which no longer match FeatureBase may be changed to match, or
+	// ingested into new fields, depending on the schema-evolution policy.
+	schema, err = m.evolveFields(schema, skips)
+	if err != nil {
+		return nil, nil, nil, nil, errors.Wrap(err, "evolving fields")
+	}
+
 	// set up bool fields
 	var boolField, boolFieldExists *pilosaclient.Field
 
@@ -1882,7 +2144,7 @@ func (m *Main) batchFromSchema(schema []Field) ([]Recordizer, pilosabatch.Record
 					rec.Time.Set(tyme.(time.Time))
 					return nil
 				})
-			case IntField, DecimalField, TimestampField:
+			case IntField, DecimalField, FloatField, TimestampField:
 				recordizers = append(recordizers, func(rawRec []interface{}, rec *pilosabatch.Row) (err error) {
 					switch rawRec[i].(type) {
 					case DeleteSentinel:
@@ -2062,6 +2324,18 @@ func (m *Main) batchFromSchema(schema []Field) ([]Recordizer, pilosabatch.Record
 				}
 				return errors.Wrapf(err, "converting field %d:%+v, val:%+v", i, idkField, rawRec[i])
 			})
+		case FloatField:
+			fields = append(fields, m.index.Field(fld.DestName(), pilosaclient.OptFieldTypeFloat()))
+			valIdx := len(fields) - 1
+			recordizers = append(recordizers, func(rawRec []interface{}, rec *pilosabatch.Row) (err error) {
+				switch rawRec[i].(type) {
+				case DeleteSentinel:
+					rec.Clears[valIdx] = uint64(0)
+				default:
+					rec.Values[valIdx], err = idkField.PilosafyVal(rawRec[i])
+				}
+				return errors.Wrapf(err, "converting field %d:%+v, val:%+v", i, idkField, rawRec[i])
+			})
 		case TimestampField:
 			fields = append(fields, m.index.Field(fld.DestName(), pilosaclient.OptFieldTypeTimestamp(fld.epoch(), string(fld.granularity()))))
 			valIdx := len(fields) - 1
@@ -2151,6 +2425,7 @@ func (m *Main) batchFromSchema(schema []Field) ([]Recordizer, pilosabatch.Record
 		Values: make([]interface{}, len(fields)),
 		Clears: make(map[int]interface{}),
 	}
+	m.prevSchema = sourceSchema
 	return recordizers, batch, row, lookupWriteIdxs, nil
 }
 
@@ -2225,6 +2500,30 @@ func (m *Main) validateField(fld Field) error {
 // then it will log the difference and return with no error. If it is
 // obviously incompatible then it returns an error.
 func (m *Main) checkFieldCompatibility(pFld *pilosaclient.Field, iFld Field, packFld string) error {
+	errs, logs, err := m.fieldIncompatibilities(pFld, iFld, packFld)
+	if err != nil {
+		return err
+	}
+
+	// Print any log-level incompatibilities.
+	if len(logs) > 0 {
+		for i := range logs {
+			m.log.Printf(logs[i])
+		}
+	}
+
+	// Return any error-level incompatibilities.
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// fieldIncompatibilities returns the differences between the IDK field and
+// the Pilosa field which make them incompatible, and those which are only
+// worth logging.
+func (m *Main) fieldIncompatibilities(pFld *pilosaclient.Field, iFld Field, packFld string) (errs, logs []string, err error) {
 	pFldOpts := pFld.Options()
 
 	// Populate an anonymous struct (which tracks *pilosaclient.FieldOptions)
@@ -2275,7 +2574,7 @@ func (m *Main) checkFieldCompatibility(pFld *pilosaclient.Field, iFld Field, pac
 
 				ttl, err := TTLOf(fld)
 				if err != nil {
-					return err
+					return nil, nil, err
 				} else {
 					iFldOpts.TTL = ttl
 				}
@@ -2289,7 +2588,7 @@ func (m *Main) checkFieldCompatibility(pFld *pilosaclient.Field, iFld Field, pac
 			if m.PackBools != "" {
 				// The compatibility check for packed bool fields
 				// is handled when packBool = true.
-				return nil
+				return nil, nil, nil
 			}
 			iFldOpts.fieldType = pilosaclient.FieldTypeBool
 		case IntField:
@@ -2320,6 +2619,10 @@ func (m *Main) checkFieldCompatibility(pFld *pilosaclient.Field, iFld Field, pac
 			max := pFldOpts.Max()
 			iFldOpts.min = min
 			iFldOpts.max = max
+		case FloatField:
+			iFldOpts.fieldType = pilosaclient.FieldTypeFloat
+			iFldOpts.min = pFldOpts.Min()
+			iFldOpts.max = pFldOpts.Max()
 		case TimestampField:
 			iFldOpts.fieldType = pilosaclient.FieldTypeTimestamp
 			iFldOpts.timeUnit = pFldOpts.TimeUnit()
@@ -2335,15 +2638,10 @@ func (m *Main) checkFieldCompatibility(pFld *pilosaclient.Field, iFld Field, pac
 			iFldOpts.cacheType = pilosaclient.CacheTypeRanked
 			iFldOpts.cacheSize = pilosacore.DefaultCacheSize
 		default:
-			return errors.Errorf("can't check compatibility of unknown field: %s (%T)", iFld.Name(), iFld)
+			return nil, nil, errors.Errorf("can't check compatibility of unknown field: %s (%T)", iFld.Name(), iFld)
 		}
 	}
 
-	// Keep a list of errors and a list of logs in order to
-	// display all logs and all errors at once.
-	var errs []string
-	var logs []string
-
 	// compare the two field option structs
 	if i, p := iFldOpts.fieldType, pFldOpts.Type(); i != p {
 		errs = append(errs, fmt.Sprintf("idk field type %s is incompatible with featurebase field type %s: %s", i, p, name))
@@ -2380,20 +2678,7 @@ func (m *Main) checkFieldCompatibility(pFld *pilosaclient.Field, iFld Field, pac
 	if i, p := iFldOpts.timeUnit, pFldOpts.TimeUnit(); i != p {
 		errs = append(errs, fmt.Sprintf("idk field type %s is incompatible with featurebase timeunit %s: %s", i, p, name))
 	}
-
-	// Print any log-level incompatibilities.
-	if len(logs) > 0 {
-		for i := range logs {
-			m.log.Printf(logs[i])
-		}
-	}
-
-	// Return any error-level incompatibilities.
-	if len(errs) > 0 {
-		return errors.New(strings.Join(errs, "; "))
-	}
-
-	return nil
+	return errs, logs, nil
 }
 
 // getPrimaryKeyRecordizer returns a Recordizer function which
@@ -2401,7 +2686,13 @@ func (m *Main) checkFieldCompatibility(pFld *pilosaclient.Field, iFld Field, pac
 // sets the ID on the record. If pkFields is a single field, and that
 // field is of type string, we'll return it in skipFields, because we
 // won't want to index it separately.
-func getPrimaryKeyRecordizer(schema []Field, pkFields []string) (recordizer Recordizer, skipFields map[int]struct{}, err error) {
+//
+// If composite is set, the index declares pkFields as its primary key, and
+// the ID is encoded by pilosacore.EncodeCompositeKey, the way sql3 encodes
+// it, so that the index can be queried by the key's parts. Otherwise the
+// values are joined as they always have been, so that the keys of existing
+// indexes don't change.
+func getPrimaryKeyRecordizer(schema []Field, pkFields []string, composite bool) (recordizer Recordizer, skipFields map[int]struct{}, err error) {
 	if len(schema) == 0 {
 		return nil, nil, errors.New("can't call getPrimaryKeyRecordizer with empty schema")
 	}
@@ -2416,6 +2707,13 @@ func getPrimaryKeyRecordizer(schema []Field, pkFields []string) (recordizer Reco
 				switch field.(type) {
 				case StringArrayField:
 					return nil, nil, errors.Errorf("field %s cannot be a primary key field because it is a StringArray field.", pk)
+				case IDField, IntField, StringField:
+				default:
+					// These are the types sql3 allows a primary key
+					// column to have.
+					if composite {
+						return nil, nil, errors.Errorf("field %s cannot be part of a composite primary key because it is a %T; it must be an ID, Int or String field.", pk, field)
+					}
 				}
 				fieldIndices = append(fieldIndices, fieldIndex)
 				break
@@ -2425,6 +2723,9 @@ func getPrimaryKeyRecordizer(schema []Field, pkFields []string) (recordizer Reco
 			return nil, nil, errors.Errorf("no field with primary key field name %s found. fields: %+v", pk, schema)
 		}
 	}
+	if composite {
+		return getCompositeKeyRecordizer(schema, fieldIndices), nil, nil
+	}
 	if len(pkFields) == 1 {
 		if _, ok := schema[fieldIndices[0]].(StringField); ok {
 			skipFields = make(map[int]struct{}, 1)
@@ -2479,6 +2780,33 @@ func getPrimaryKeyRecordizer(schema []Field, pkFields []string) (recordizer Reco
 	return recordizer, skipFields, nil
 }
 
+// getCompositeKeyRecordizer returns a Recordizer which sets the ID of a
+// record to the composite key made up of the fields at fieldIndices. Each
+// value is converted by its field first, so it's encoded the same way
+// whatever the source's representation of it.
+func getCompositeKeyRecordizer(schema []Field, fieldIndices []int) Recordizer {
+	return func(rawRec []interface{}, rec *pilosabatch.Row) error {
+		parts := make([]interface{}, len(fieldIndices))
+		for i, fieldIdx := range fieldIndices {
+			field := schema[fieldIdx]
+			val, err := field.PilosafyVal(rawRec[fieldIdx])
+			if err != nil {
+				return errors.Wrapf(err, "converting primary key field %s", field.DestName())
+			} else if val == nil {
+				return errors.New("data for primary key should not be nil or missing")
+			}
+			parts[i] = val
+		}
+		key, err := pilosacore.EncodeCompositeKey(parts...)
+		if err != nil {
+			return errors.Wrap(err, "encoding primary key")
+		}
+		idbytes, _ := rec.ID.([]byte)
+		rec.ID = append(idbytes[:0], key...)
+		return nil
+	}
+}
+
 func (m *Main) validate() error {
 	IDSpecCount := 0
 	if len(m.PrimaryKeyFields) != 0 {
@@ -2506,6 +2834,25 @@ func (m *Main) validate() error {
 		return errors.New("must set an index with --pilosa.index")
 	}
 
+	if err := validateSchemaEvolution(m.SchemaEvolution); err != nil {
+		return err
+	} else if m.SchemaEvolution == SchemaEvolutionCoerce && m.useController() {
+		return errors.New("--schema-evolution=coerce can't be used with a controller")
+	}
+
+	if m.CheckpointKey != "" {
+		switch {
+		case !m.UseShardTransactionalEndpoint:
+			return errors.New("--checkpoint-key requires --use-shard-transactional-endpoint")
+		case m.ExpSplitBatchMode:
+			return errors.New("--checkpoint-key can't be used with split batch mode")
+		case m.Delete:
+			return errors.New("--checkpoint-key can't be used when deleting records")
+		case m.useController():
+			return errors.New("--checkpoint-key can't be used with a controller")
+		}
+	}
+
 	if m.NewSource == nil {
 		return errors.New("must set a NewSource function on IDK ingester")
 	}
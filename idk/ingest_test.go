@@ -443,7 +443,7 @@ func TestForeignKeyRegression(t *testing.T) {
 
 	// this previously panicked because Batch.toTranslate wasn't
 	// getting cleared for int fields.
-	err = ingester.run()
+	err = ingester.run(context.Background())
 	if err != nil {
 		t.Fatalf("%s: %v", idktest.ErrRunningIngest, err)
 	}
@@ -698,7 +698,7 @@ func TestDelete(t *testing.T) {
 		t.Errorf("unexpected keys for s2: %v", resp.Result().Row().Keys)
 	}
 
-	if err := deleter.run(); err != nil && errors.Cause(err) != io.EOF {
+	if err := deleter.run(context.Background()); err != nil && errors.Cause(err) != io.EOF {
 		t.Fatalf("running deleter: %v", err)
 	}
 
@@ -718,13 +718,14 @@ func TestDelete(t *testing.T) {
 
 func TestGetPrimaryKeyRecordizer(t *testing.T) {
 	tests := []struct {
-		name     string
-		schema   []Field
-		pkFields []string
-		expErr   string
-		expSkip  map[int]struct{}
-		rawRec   []interface{}
-		expID    interface{}
+		name      string
+		schema    []Field
+		pkFields  []string
+		composite bool
+		expErr    string
+		expSkip   map[int]struct{}
+		rawRec    []interface{}
+		expID     interface{}
 	}{
 		{
 			name:   "no schema",
@@ -774,11 +775,41 @@ func TestGetPrimaryKeyRecordizer(t *testing.T) {
 			rawRec:   []interface{}{"a", uint32(1), uint32(2), uint32(4)},
 			expID:    []byte("2|4|1"),
 		},
+		{
+			name:     "primaries not escaped",
+			schema:   []Field{StringField{NameVal: "a"}, StringField{NameVal: "b"}, IntField{NameVal: "c"}},
+			pkFields: []string{"a", "b", "c"},
+			rawRec:   []interface{}{[]byte(`x|y`), []byte(`z\`), uint32(3)},
+			expID:    []byte(`x|y|z\|3`),
+		},
+		{
+			name:      "composite primaries escaped",
+			schema:    []Field{StringField{NameVal: "a"}, StringField{NameVal: "b"}, IntField{NameVal: "c"}},
+			pkFields:  []string{"a", "b", "c"},
+			composite: true,
+			rawRec:    []interface{}{[]byte(`x|y`), []byte(`z\`), "3"},
+			expID:     []byte(`x\|y|z\\|3`),
+		},
+		{
+			name:      "composite primary string not skipped",
+			schema:    []Field{StringField{NameVal: "a"}, IntField{NameVal: "b"}},
+			pkFields:  []string{"a"},
+			composite: true,
+			rawRec:    []interface{}{"a|b", 9},
+			expID:     []byte(`a\|b`),
+		},
+		{
+			name:      "composite primary is Decimal",
+			schema:    []Field{StringField{NameVal: "a"}, DecimalField{NameVal: "b"}},
+			pkFields:  []string{"a", "b"},
+			composite: true,
+			expErr:    "field b cannot be part of a composite primary key because it is a idk.DecimalField",
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			rdz, skips, err := getPrimaryKeyRecordizer(test.schema, test.pkFields)
+			rdz, skips, err := getPrimaryKeyRecordizer(test.schema, test.pkFields, test.composite)
 			if test.expErr != "" {
 				if err == nil {
 					t.Fatalf("nil err, expected %s", test.expErr)
@@ -1999,3 +2030,92 @@ func stringSetFn(name string, fo dax.FieldOptions) Field {
 func timestampFn(name string, fo dax.FieldOptions) Field {
 	return TimestampField{NameVal: name}
 }
+
+// checkpointTestSource is a source of records in a single stream, each of
+// which gives the offset of the record after it, as Kafka's do. It resumes
+// from the offset in its checkpoint.
+type checkpointTestSource struct {
+	schema     []Field
+	records    [][]interface{}
+	checkpoint func() (map[string]uint64, map[string]string, error)
+	next       int
+	first      int
+	resumed    bool
+}
+
+func (s *checkpointTestSource) Record() (Record, error) {
+	if !s.resumed {
+		offsets, _, err := s.checkpoint()
+		if err != nil {
+			return nil, err
+		}
+		s.next, s.first, s.resumed = int(offsets["s"]), int(offsets["s"]), true
+	}
+	if s.next >= len(s.records) {
+		return nil, io.EOF
+	}
+	s.next++
+	return &offsetRecord{groupKey: "s", offset: s.next, data: s.records[s.next-1]}, nil
+}
+
+func (s *checkpointTestSource) Schema() []Field { return s.schema }
+
+func (s *checkpointTestSource) Close() error { return nil }
+
+func TestCheckpointResume(t *testing.T) {
+	schema := []Field{IDField{NameVal: "id"}, IntField{NameVal: "n"}}
+	records := make([][]interface{}, 10)
+	for i := range records {
+		records[i] = []interface{}{uint64(i), int64(i)}
+	}
+	rand.Seed(time.Now().UTC().UnixNano())
+	index := fmt.Sprintf("checkpointresume%d", rand.Intn(100000))
+
+	// ingest records, the first five, then all of them, resuming from the
+	// checkpoint
+	run := func(records [][]interface{}) (*Main, *checkpointTestSource) {
+		ingester := NewMain()
+		configureTestFlags(ingester)
+		ingester.Index = index
+		ingester.BatchSize = 3
+		ingester.UseShardTransactionalEndpoint = true
+		ingester.CheckpointKey = "resume"
+		ingester.IDField = "id"
+		ts := &checkpointTestSource{schema: schema, records: records, checkpoint: ingester.Checkpoint}
+		ingester.NewSource = func() (Source, error) { return ts, nil }
+		if err := ingester.Run(); err != nil {
+			t.Fatalf("%s: %v", idktest.ErrRunningIngest, err)
+		}
+		return ingester, ts
+	}
+	ingester, _ := run(records[:5])
+
+	client := ingester.PilosaClient()
+	defer func() {
+		if err := client.DeleteIndexByName(index); err != nil {
+			t.Logf("%s for index %s: %v", idktest.ErrDeletingIndex, index, err)
+		}
+	}()
+	checkpoint, err := client.IngestCheckpoint(index, "resume")
+	if err != nil {
+		t.Fatalf("reading checkpoint: %v", err)
+	}
+	if checkpoint.Offsets["s"] != 5 {
+		t.Fatalf("expected the checkpoint at offset 5, got %v", checkpoint.Offsets)
+	}
+
+	if _, ts := run(records); ts.first != 5 {
+		t.Fatalf("expected to resume from record 5, got %d", ts.first)
+	}
+	schemaInfo, err := client.Schema()
+	if err != nil {
+		t.Fatalf("getting schema: %v", err)
+	}
+	qr, err := client.Query(schemaInfo.Index(index).RawQuery("Count(Row(n >= 0))"))
+	if err != nil {
+		t.Fatalf("querying: %v", err)
+	}
+	if qr.Results()[0].Count() != 10 {
+		t.Fatalf("expected 10 records, got %d", qr.Results()[0].Count())
+	}
+}
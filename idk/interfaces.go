@@ -81,6 +81,38 @@ type (
 		StreamOffset() (key string, offset uint64)
 	}
 
+	// PositionRecord is an extension of the offset stream record type for
+	// sources which also know where a record came from in their own terms,
+	// such as a change-data-capture connector's position in a database's
+	// log. The position is checkpointed with the record's offset.
+	PositionRecord interface {
+		OffsetStreamRecord
+
+		// StreamPosition returns the record's position, encoded by its
+		// source, or "" if it has none.
+		StreamPosition() string
+	}
+
+	// PayloadRecord is an extension of the record type which also keeps the
+	// payload the record was decoded from.
+	PayloadRecord interface {
+		Record
+
+		// Payload returns the record as it was received by the source.
+		Payload() []byte
+	}
+
+	// ChangeRecord is an extension of the record type for change-data-capture
+	// sources. Rather than adding to the record with its ID or primary key,
+	// it replaces the whole record with its data, or deletes it.
+	ChangeRecord interface {
+		Record
+
+		// Deleted returns true if the record was deleted rather than
+		// created or updated.
+		Deleted() bool
+	}
+
 	Metadata interface {
 		// SchemaMetadata returns a string representation of source-specific details
 		// about the schema.
@@ -116,7 +148,7 @@ func FieldsEqual(f1, f2 Field) bool {
 		return false
 	}
 	switch f1t := f1.(type) {
-	case IgnoreField, IDField, BoolField, RecordTimeField, StringField, LookupTextField, DecimalField, SignedIntBoolKeyField, StringArrayField, IDArrayField, TimestampField, DateIntField:
+	case IgnoreField, IDField, BoolField, RecordTimeField, StringField, LookupTextField, DecimalField, FloatField, SignedIntBoolKeyField, StringArrayField, IDArrayField, TimestampField, DateIntField:
 		return f1 == f2
 	case IntField:
 		f2t := f2.(IntField)
@@ -682,6 +714,64 @@ func (d DecimalField) PilosafyVal(val interface{}) (interface{}, error) {
 	}
 }
 
+// FloatField is ingested into a Pilosa float field, which stores the
+// full range of IEEE-754 doubles.
+type FloatField struct {
+	NameVal     string
+	DestNameVal string
+}
+
+func (f FloatField) Name() string { return f.NameVal }
+func (f FloatField) DestName() string {
+	if f.DestNameVal == "" {
+		return f.NameVal
+	}
+
+	return f.DestNameVal
+}
+
+// PilosafyVal for FloatField always returns a float64. Strings are
+// parsed as floats, and byte slices of length 8 are interpreted as the
+// big-endian IEEE-754 bits of the value. NaN is rejected.
+func (f FloatField) PilosafyVal(val interface{}) (interface{}, error) {
+	if val == nil {
+		return nil, nil
+	}
+	var v float64
+	switch vt := val.(type) {
+	case string:
+		if vt == "" {
+			return nil, nil
+		}
+		var err error
+		v, err = strconv.ParseFloat(vt, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing '%s' for float field %s", vt, f.Name())
+		}
+	case float64:
+		v = vt
+	case float32:
+		v = float64(vt)
+	case pql.Decimal:
+		v = vt.Float64()
+	case []byte:
+		if len(vt) != 8 {
+			return nil, errors.Errorf("float values must be 8 bytes, got %d for %s", len(vt), f.Name())
+		}
+		v = math.Float64frombits(binary.BigEndian.Uint64(vt))
+	default:
+		i, err := toInt64(val)
+		if err != nil {
+			return nil, errors.Wrapf(err, "couldn't convert %v to float64 for float field", val)
+		}
+		v = float64(i)
+	}
+	if math.IsNaN(v) {
+		return nil, errors.Errorf("NaN is not supported for float field %s", f.Name())
+	}
+	return v, nil
+}
+
 // SignedIntBoolKeyField translates a signed integer value to a (rowID, bool)
 // pair corresponding to the magnitude and sign of the original value. This
 // may be used to specify whether a bool value is to be set (positive/true)
@@ -97,6 +97,23 @@ func TestPilosafyVal(t *testing.T) {
 				nil,
 			},
 		},
+		{
+			field: FloatField{},
+			vals: []interface{}{
+				1,
+				"-1.5",
+				float32(0.5),
+				"",
+				nil,
+			},
+			exps: []interface{}{
+				float64(1),
+				float64(-1.5),
+				float64(0.5),
+				nil,
+				nil,
+			},
+		},
 		{
 			field:   IDField{},
 			vals:    []interface{}{""},
@@ -15,6 +15,7 @@ type Main struct {
 	Timeout              time.Duration `help:"Time to wait for more records from Kafka before flushing a batch. 0 to disable."`
 	SkipOld              bool          `short:"" help:"False sets kafka consumer configuration auto.offset.reset to earliest, True sets it to latest."`
 	ConsumerCloseTimeout int           `help:"The amount of time in seconds to wait for the consumer to close properly."`
+	CDC                  string        `short:"" help:"Change-data-capture mode. With \"debezium\", messages are Debezium change events: creates and updates replace the record with the event row's primary key, and deletes delete it. Requires primary-key-fields or id-field, and checkpoint-key so that the events' positions are stored with the changes."`
 }
 
 func NewMain() (*Main, error) {
@@ -53,6 +54,13 @@ func NewMain() (*Main, error) {
 		source.KafkaDebug = m.KafkaDebug
 		source.KafkaSocketKeepaliveEnable = m.KafkaSocketKeepaliveEnable
 		source.consumerCloseTimeout = m.ConsumerCloseTimeout
+		source.CDC = m.CDC
+		if m.CDC != "" && m.CheckpointKey == "" {
+			return nil, errors.New("--cdc requires --checkpoint-key")
+		}
+		if m.CheckpointKey != "" {
+			source.Checkpoint = m.Main.Checkpoint
+		}
 
 		if err := source.Open(); err != nil {
 			return nil, errors.Wrap(err, "opening source")
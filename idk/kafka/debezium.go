@@ -0,0 +1,189 @@
+package kafka
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/go-avro/avro"
+	"github.com/pkg/errors"
+)
+
+// CDCDebezium is the CDC mode in which messages are Debezium change events.
+// Each event's envelope holds the row before and after the change, the
+// operation, and where the change was read from the source database's log.
+const CDCDebezium = "debezium"
+
+// debeziumRowSchema returns the schema of the rows in a Debezium change
+// event envelope, which is the type of its nullable "after" field.
+func debeziumRowSchema(codec avro.Schema) (avro.Schema, error) {
+	envelope, ok := codec.(*avro.RecordSchema)
+	if !ok {
+		return nil, errors.Errorf("Debezium change events must be records, got %s", codec.GetName())
+	}
+	for _, field := range envelope.Fields {
+		if field.Name != "after" {
+			continue
+		}
+		types := []avro.Schema{field.Type}
+		if union, ok := field.Type.(*avro.UnionSchema); ok {
+			types = union.Types
+		}
+		for _, typ := range types {
+			switch typ := typ.(type) {
+			case *avro.RecordSchema:
+				return typ, nil
+			case *avro.RecursiveSchema:
+				// the row type is defined by the before field, and
+				// only named here
+				return typ.Actual, nil
+			}
+		}
+		return nil, errors.New("the after field of a Debezium change event must be a record")
+	}
+	return nil, errors.Errorf("%s is not a Debezium change event envelope: it has no after field", envelope.GetName())
+}
+
+// debeziumChange is a decoded Debezium change event.
+type debeziumChange struct {
+	row      map[string]interface{}
+	deleted  bool
+	position debeziumPosition
+	// ordered is false if the connector's positions can't be compared.
+	ordered bool
+}
+
+// decodeDebeziumChange unwraps the row from a change event. Creates,
+// updates and snapshot reads have the row after the change, and deletes
+// have it before, which must at least hold the row's primary key.
+func decodeDebeziumChange(val map[string]interface{}) (*debeziumChange, error) {
+	op, _ := val["op"].(string)
+	change := &debeziumChange{}
+	var ok bool
+	switch op {
+	case "c", "r", "u":
+		change.row, ok = val["after"].(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("change event with op %q has no after", op)
+		}
+	case "d":
+		change.row, ok = val["before"].(map[string]interface{})
+		if !ok {
+			return nil, errors.New("delete event has no before; the table's replica identity must include its primary key")
+		}
+		change.deleted = true
+	default:
+		return nil, errors.Errorf("unsupported change event op %q", op)
+	}
+	if source, ok := val["source"].(map[string]interface{}); ok {
+		change.position, change.ordered = debeziumSourcePosition(source)
+	}
+	return change, nil
+}
+
+// debeziumPosition is where a change was read from the source database's
+// log. For MySQL it's the binlog file, the position in it, and the row of
+// the binlog event. For Postgres, the file is empty, and the position and
+// row are the LSNs of the commit and of the change itself, since changes
+// arrive in commit order rather than the order they were written in.
+type debeziumPosition struct {
+	file string
+	pos  int64
+	row  int64
+}
+
+// String encodes the position for a checkpoint, as its pos, row and file,
+// separated by commas.
+func (p debeziumPosition) String() string {
+	return strconv.FormatInt(p.pos, 10) + "," + strconv.FormatInt(p.row, 10) + "," + p.file
+}
+
+// parseDebeziumPosition decodes a position encoded by String.
+func parseDebeziumPosition(s string) (debeziumPosition, error) {
+	parts := strings.SplitN(s, ",", 3)
+	if len(parts) != 3 {
+		return debeziumPosition{}, errors.Errorf("invalid Debezium position %q", s)
+	}
+	pos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return debeziumPosition{}, errors.Wrapf(err, "parsing Debezium position %q", s)
+	}
+	row, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return debeziumPosition{}, errors.Wrapf(err, "parsing Debezium position %q", s)
+	}
+	return debeziumPosition{file: parts[2], pos: pos, row: row}, nil
+}
+
+func (p debeziumPosition) before(o debeziumPosition) bool {
+	if p.file != o.file {
+		return p.file < o.file
+	}
+	if p.pos != o.pos {
+		return p.pos < o.pos
+	}
+	return p.row < o.row
+}
+
+// debeziumSourcePosition returns the position in the source block of a
+// change event from the Postgres or MySQL connectors.
+func debeziumSourcePosition(source map[string]interface{}) (debeziumPosition, bool) {
+	// Postgres gives the LSNs of the last commit and of the change as a
+	// JSON array of strings in sequence, and only the change's in lsn.
+	if seq, ok := source["sequence"].(string); ok {
+		var lsns []*string
+		if err := json.Unmarshal([]byte(seq), &lsns); err == nil && len(lsns) == 2 && lsns[1] != nil {
+			var p debeziumPosition
+			if lsns[0] != nil {
+				p.pos, _ = strconv.ParseInt(*lsns[0], 10, 64)
+			}
+			if lsn, err := strconv.ParseInt(*lsns[1], 10, 64); err == nil {
+				p.row = lsn
+				return p, true
+			}
+		}
+	}
+	if lsn, ok := debeziumInt(source["lsn"]); ok {
+		return debeziumPosition{row: lsn}, true
+	}
+	if file, ok := source["file"].(string); ok {
+		pos, ok := debeziumInt(source["pos"])
+		if !ok {
+			return debeziumPosition{}, false
+		}
+		row, _ := debeziumInt(source["row"])
+		return debeziumPosition{file: file, pos: pos, row: row}, true
+	}
+	return debeziumPosition{}, false
+}
+
+func debeziumInt(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// changeRecord is a Record holding a Debezium change event's row. Its
+// position is checkpointed, so that changes sent again after a restart are
+// still recognized as stale.
+type changeRecord struct {
+	*Record
+	deleted  bool
+	position string
+}
+
+var _ idk.ChangeRecord = &changeRecord{}
+var _ idk.PositionRecord = &changeRecord{}
+
+func (r *changeRecord) Deleted() bool { return r.deleted }
+
+func (r *changeRecord) StreamPosition() string { return r.position }
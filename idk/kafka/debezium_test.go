@@ -0,0 +1,206 @@
+//go:build !kafka_sasl
+// +build !kafka_sasl
+
+package kafka
+
+import (
+	"reflect"
+	"testing"
+
+	confluent "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/featurebasedb/featurebase/v3/idk"
+	liavro "github.com/linkedin/goavro/v2"
+)
+
+const testDebeziumSchema = `{
+	"type": "record",
+	"name": "Envelope",
+	"namespace": "db.public.users",
+	"fields": [
+		{"name": "before", "type": ["null", {
+			"type": "record",
+			"name": "Value",
+			"fields": [
+				{"name": "id", "type": "long"},
+				{"name": "name", "type": ["null", "string"]}
+			]
+		}]},
+		{"name": "after", "type": ["null", "Value"]},
+		{"name": "source", "type": {
+			"type": "record",
+			"name": "Source",
+			"namespace": "io.debezium.connector.postgresql",
+			"fields": [
+				{"name": "sequence", "type": ["null", "string"]},
+				{"name": "lsn", "type": ["null", "long"]}
+			]
+		}},
+		{"name": "op", "type": "string"},
+		{"name": "ts_ms", "type": ["null", "long"]}
+	]
+}`
+
+func TestDebeziumToPDKSchema(t *testing.T) {
+	server := newTestRegistry(t, map[string]*Schema{
+		"/schemas/ids/5": {Schema: testDebeziumSchema},
+	})
+	src := newTestRegistrySource(server.URL)
+	src.CDC = CDCDebezium
+
+	codec, err := liavro.NewCodec(testDebeziumSchema)
+	if err != nil {
+		t.Fatalf("parsing schema: %v", err)
+	}
+	event := func(op string, before, after map[string]interface{}, lsn int64) []byte {
+		t.Helper()
+		native := map[string]interface{}{
+			"before": nil,
+			"after":  nil,
+			"source": map[string]interface{}{
+				"sequence": nil,
+				"lsn":      map[string]interface{}{"long": lsn},
+			},
+			"op":    op,
+			"ts_ms": nil,
+		}
+		if before != nil {
+			native["before"] = map[string]interface{}{"db.public.users.Value": before}
+		}
+		if after != nil {
+			native["after"] = map[string]interface{}{"db.public.users.Value": after}
+		}
+		value, err := codec.BinaryFromNative(nil, native)
+		if err != nil {
+			t.Fatalf("encoding event: %v", err)
+		}
+		return confluentFrame(5, nil, value)
+	}
+
+	val, _, err := src.decodeValueWithSchemaRegistry(event("c", nil, map[string]interface{}{"id": int64(1), "name": map[string]interface{}{"string": "a"}}, 10))
+	if err != idk.ErrSchemaChange {
+		t.Fatalf("expected schema change, got %v", err)
+	}
+	if exp := []idk.Field{idk.IntField{NameVal: "id"}, idk.StringField{NameVal: "name"}}; !reflect.DeepEqual(src.lastSchema, exp) {
+		t.Errorf("unexpected schema %v", src.lastSchema)
+	}
+	change, err := decodeDebeziumChange(val)
+	if err != nil {
+		t.Fatalf("decoding change: %v", err)
+	}
+	if change.deleted || !change.ordered || change.position.row != 10 {
+		t.Errorf("unexpected change %+v", change)
+	}
+	if data := src.toPDKRecord(change.row); !reflect.DeepEqual(data, []interface{}{int64(1), "a"}) {
+		t.Errorf("unexpected data %v", data)
+	}
+
+	val, _, err = src.decodeValueWithSchemaRegistry(event("d", map[string]interface{}{"id": int64(1), "name": nil}, nil, 12))
+	if err != nil {
+		t.Fatalf("decoding delete: %v", err)
+	}
+	change, err = decodeDebeziumChange(val)
+	if err != nil {
+		t.Fatalf("decoding change: %v", err)
+	}
+	if !change.deleted {
+		t.Errorf("expected delete")
+	}
+	if data := src.toPDKRecord(change.row); !reflect.DeepEqual(data, []interface{}{int64(1), nil}) {
+		t.Errorf("unexpected data %v", data)
+	}
+
+	val, _, err = src.decodeValueWithSchemaRegistry(event("u", nil, nil, 13))
+	if err != nil {
+		t.Fatalf("decoding update: %v", err)
+	}
+	if _, err := decodeDebeziumChange(val); err == nil {
+		t.Errorf("expected error decoding update without after")
+	}
+}
+
+func TestDebeziumOrdering(t *testing.T) {
+	src := NewSource()
+	topic := "users"
+	tp := func(partition int32) confluent.TopicPartition {
+		return confluent.TopicPartition{Topic: &topic, Partition: partition}
+	}
+	postgres := func(seq string) *debeziumChange {
+		change, err := decodeDebeziumChange(map[string]interface{}{
+			"op":     "u",
+			"after":  map[string]interface{}{},
+			"source": map[string]interface{}{"sequence": seq, "lsn": int64(0)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return change
+	}
+
+	// Changes are ordered by the LSN of the commit before the one of the
+	// change, since a later transaction's changes may have been written
+	// first.
+	if src.stale(tp(0), postgres(`["100","120"]`)) {
+		t.Errorf("first change can't be stale")
+	}
+	if src.stale(tp(0), postgres(`["130","110"]`)) {
+		t.Errorf("change in a later transaction isn't stale")
+	}
+	if !src.stale(tp(0), postgres(`["100","120"]`)) {
+		t.Errorf("expected repeated change to be stale")
+	}
+	if src.stale(tp(1), postgres(`["100","120"]`)) {
+		t.Errorf("partitions are ordered separately")
+	}
+
+	mysql := func(file string, pos int64, row int32) *debeziumChange {
+		return &debeziumChange{ordered: true, position: mustDebeziumPosition(t, map[string]interface{}{"file": file, "pos": pos, "row": row})}
+	}
+	if src.stale(tp(2), mysql("mysql-bin.000002", 10, 0)) {
+		t.Errorf("first change can't be stale")
+	}
+	if src.stale(tp(2), mysql("mysql-bin.000002", 10, 1)) {
+		t.Errorf("next row isn't stale")
+	}
+	if !src.stale(tp(2), mysql("mysql-bin.000001", 900, 0)) {
+		t.Errorf("expected change in earlier binlog file to be stale")
+	}
+	// snapshot reads share a position
+	if src.stale(tp(2), mysql("mysql-bin.000002", 10, 1)) {
+		t.Errorf("change at the same position isn't stale")
+	}
+
+	if src.stale(tp(3), &debeziumChange{}) {
+		t.Errorf("changes without positions are never stale")
+	}
+}
+
+func TestDebeziumPositionString(t *testing.T) {
+	for _, p := range []debeziumPosition{
+		{row: 120},
+		{pos: 100, row: 120},
+		{file: "mysql-bin.000002", pos: 10, row: 1},
+		{file: "odd,name", pos: 1},
+	} {
+		got, err := parseDebeziumPosition(p.String())
+		if err != nil {
+			t.Fatalf("parsing %q: %v", p.String(), err)
+		}
+		if got != p {
+			t.Errorf("expected %v, got %v", p, got)
+		}
+	}
+	for _, s := range []string{"", "1,2", "a,2,f", "1,b,f"} {
+		if _, err := parseDebeziumPosition(s); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+}
+
+func mustDebeziumPosition(t *testing.T, source map[string]interface{}) debeziumPosition {
+	t.Helper()
+	p, ok := debeziumSourcePosition(source)
+	if !ok {
+		t.Fatalf("no position in %v", source)
+	}
+	return p
+}
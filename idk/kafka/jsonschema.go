@@ -0,0 +1,313 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/pkg/errors"
+)
+
+// jsonSchemaDecoder decodes JSON values described by a JSON Schema. The
+// schema must be an object whose properties are the fields, and the
+// properties of each field's schema are used as Avro field properties are.
+type jsonSchemaDecoder struct {
+	schema string
+	fields []idk.Field
+}
+
+func newJSONSchemaDecoder(schema string) (*jsonSchemaDecoder, error) {
+	var root struct {
+		Type        interface{}                `json:"type"`
+		Properties  json.RawMessage            `json:"properties"`
+		Definitions map[string]json.RawMessage `json:"definitions"`
+		Defs        map[string]json.RawMessage `json:"$defs"`
+	}
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, errors.Wrap(err, "parsing JSON Schema")
+	}
+	if root.Type != "object" || root.Properties == nil {
+		return nil, errors.Errorf("unsupported JSON Schema type: %v, must be an object with properties", root.Type)
+	}
+
+	names, err := jsonObjectKeys(root.Properties)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading properties")
+	}
+	var properties map[string]props
+	if err := json.Unmarshal(root.Properties, &properties); err != nil {
+		return nil, errors.Wrap(err, "parsing properties")
+	}
+	defs := make(map[string]json.RawMessage)
+	for name, def := range root.Definitions {
+		defs["#/definitions/"+name] = def
+	}
+	for name, def := range root.Defs {
+		defs["#/$defs/"+name] = def
+	}
+
+	d := &jsonSchemaDecoder{schema: schema}
+	for _, name := range names {
+		field, err := jsonSchemaToPDKField(name, properties[name], defs)
+		if err != nil {
+			return nil, errors.Wrap(err, "converting JSON Schema property to pdk")
+		}
+		d.fields = append(d.fields, field)
+	}
+	return d, nil
+}
+
+func (d *jsonSchemaDecoder) Fields() []idk.Field {
+	return d.fields
+}
+
+func (d *jsonSchemaDecoder) String() string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(d.schema)); err != nil {
+		return d.schema
+	}
+	return buf.String()
+}
+
+// Decode decodes a JSON object. Numbers are decoded as int64 where they're
+// integers, and as float64 otherwise, except for decimal fields, which get
+// the number as it was written so that no precision is lost.
+func (d *jsonSchemaDecoder) Decode(data []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	ret := make(map[string]interface{})
+	if err := dec.Decode(&ret); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling JSON value")
+	}
+	for _, field := range d.fields {
+		val, ok := ret[field.Name()]
+		if !ok {
+			continue
+		}
+		_, decimal := field.(idk.DecimalField)
+		ret[field.Name()] = jsonNumbers(val, decimal)
+	}
+	return ret, nil
+}
+
+func jsonNumbers(val interface{}, decimal bool) interface{} {
+	switch v := val.(type) {
+	case json.Number:
+		if decimal {
+			return v.String()
+		}
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i := range v {
+			v[i] = jsonNumbers(v[i], decimal)
+		}
+	}
+	return val
+}
+
+// jsonSchemaToPDKField converts the schema of an object property to an
+// idk.Field. A property may be nullable, through a type list or a oneOf or
+// anyOf, as long as it has one type besides null, just as Avro unions may.
+func jsonSchemaToPDKField(name string, p props, defs map[string]json.RawMessage) (idk.Field, error) {
+	p, err := resolveJSONSchema(p, defs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "property %s", name)
+	}
+	typ, err := jsonSchemaType(p)
+	if err != nil {
+		return nil, errors.Wrapf(err, "property %s", name)
+	}
+
+	switch typ {
+	case "string":
+		if _, err := stringProp(p, "fieldType"); err == errNotFound {
+			switch format, _ := stringProp(p, "format"); format {
+			case "date-time":
+				granularity, _ := stringProp(p, "granularity")
+				return idk.TimestampField{
+					NameVal:     name,
+					Granularity: granularity,
+					Layout:      time.RFC3339Nano,
+				}, nil
+			case "date":
+				granularity, _ := stringProp(p, "granularity")
+				return idk.TimestampField{
+					NameVal:     name,
+					Granularity: granularity,
+					Layout:      "2006-01-02",
+				}, nil
+			}
+			if _, ok := p.Prop("enum"); ok {
+				return idk.StringField{
+					NameVal: name,
+					Mutex:   true,
+				}, nil
+			}
+		}
+		return textToPDKField(name, p)
+
+	case "integer":
+		return intToPDKField(name, p)
+
+	case "number":
+		return floatToPDKField(name, p)
+
+	case "boolean":
+		return idk.BoolField{
+			NameVal: name,
+		}, nil
+
+	case "array":
+		items, ok := p["items"].(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("array property %s must have a single items schema", name)
+		}
+		itemProps, err := resolveJSONSchema(props(items), defs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "items of property %s", name)
+		}
+		itemType, err := jsonSchemaType(itemProps)
+		if err != nil {
+			return nil, errors.Wrapf(err, "items of property %s", name)
+		}
+		switch itemType {
+		case "string":
+			return arrayToPDKField(name, itemProps, false)
+		case "integer":
+			return arrayToPDKField(name, itemProps, true)
+		default:
+			return nil, errors.Errorf("array items type of %s is unsupported", itemType)
+		}
+
+	case "object":
+		return nil, errors.Errorf("nested fields are not currently supported, so the field type cannot be object.")
+
+	case "null":
+		return nil, errors.Errorf("null fields are not supported except alongside one other type")
+
+	default:
+		return nil, errors.Errorf("unknown JSON Schema type: %s", typ)
+	}
+}
+
+// resolveJSONSchema returns the schema p refers to, if it's a reference to
+// a definition, or the one non-null schema of its oneOf or anyOf. The
+// properties of p itself take precedence over those of the schema it
+// resolves to.
+func resolveJSONSchema(p props, defs map[string]json.RawMessage) (props, error) {
+	var target props
+	if ref, err := stringProp(p, "$ref"); err == nil {
+		def, ok := defs[ref]
+		if !ok {
+			return nil, errors.Errorf("unsupported or unknown reference %s", ref)
+		}
+		if err := json.Unmarshal(def, &target); err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", ref)
+		}
+	} else {
+		for _, key := range []string{"oneOf", "anyOf"} {
+			options, ok := p[key].([]interface{})
+			if !ok {
+				continue
+			}
+			for _, opt := range options {
+				opt, ok := opt.(map[string]interface{})
+				if !ok {
+					return nil, errors.Errorf("%s must be a list of schemas", key)
+				}
+				if opt["type"] == "null" {
+					continue
+				}
+				if target != nil {
+					return nil, errors.Errorf("%s is only supported with a single schema plus optionally null", key)
+				}
+				target = opt
+			}
+		}
+	}
+	if target == nil {
+		return p, nil
+	}
+
+	target, err := resolveJSONSchema(target, defs)
+	if err != nil {
+		return nil, err
+	}
+	merged := make(props, len(p)+len(target))
+	for k, v := range target {
+		merged[k] = v
+	}
+	for k, v := range p {
+		switch k {
+		case "$ref", "oneOf", "anyOf":
+		default:
+			merged[k] = v
+		}
+	}
+	return merged, nil
+}
+
+// jsonSchemaType returns the type of a schema, which may be a list of a
+// type and null.
+func jsonSchemaType(p props) (string, error) {
+	switch typ := p["type"].(type) {
+	case string:
+		return typ, nil
+	case []interface{}:
+		var ret string
+		for _, t := range typ {
+			ts, ok := t.(string)
+			if !ok {
+				return "", errors.Errorf("type must be a string or a list of strings, got %v", typ)
+			}
+			if ts == "null" {
+				continue
+			}
+			if ret != "" {
+				return "", errors.Errorf("type lists are only supported with a single type plus optionally null, got %v", typ)
+			}
+			ret = ts
+		}
+		if ret == "" {
+			return "null", nil
+		}
+		return ret, nil
+	case nil:
+		return "", errors.New("no type")
+	default:
+		return "", errors.Errorf("type must be a string or a list of strings, got %v", typ)
+	}
+}
+
+// jsonObjectKeys returns the keys of a JSON object in the order they're
+// written, since a schema's fields should be in the order of its properties.
+func jsonObjectKeys(raw json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, errors.Errorf("expected an object, got %v", tok)
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, errors.Errorf("expected a key, got %v", tok)
+		}
+		keys = append(keys, key)
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,96 @@
+//go:build !kafka_sasl
+// +build !kafka_sasl
+
+package kafka
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+)
+
+const testJSONSchema = `{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "mutex": true},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"ids": {"type": "array", "items": {"type": "integer"}},
+		"at": {"type": "string", "format": "date-time", "granularity": "D"},
+		"price": {"type": ["null", "number"], "fieldType": "decimal", "scale": 2},
+		"n": {"$ref": "#/definitions/id"},
+		"color": {"type": "string", "enum": ["RED", "BLUE"]},
+		"ok": {"oneOf": [{"type": "null"}, {"type": "boolean"}]},
+		"f": {"type": "number"}
+	},
+	"definitions": {
+		"id": {"type": "integer", "fieldType": "id"}
+	}
+}`
+
+func TestJSONSchemaToPDKSchema(t *testing.T) {
+	dec, err := newJSONSchemaDecoder(testJSONSchema)
+	if err != nil {
+		t.Fatalf("parsing schema: %v", err)
+	}
+	exp := []idk.Field{
+		idk.StringField{NameVal: "name", Mutex: true},
+		idk.StringArrayField{NameVal: "tags"},
+		idk.IDArrayField{NameVal: "ids"},
+		idk.TimestampField{NameVal: "at", Granularity: "D", Layout: time.RFC3339Nano},
+		idk.DecimalField{NameVal: "price", Scale: 2},
+		idk.IDField{NameVal: "n"},
+		idk.StringField{NameVal: "color", Mutex: true},
+		idk.BoolField{NameVal: "ok"},
+		idk.DecimalField{NameVal: "f"},
+	}
+	if got := dec.Fields(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("unexpected fields:\n got: %#v\nwant: %#v", got, exp)
+	}
+
+	for _, schema := range []string{
+		`{"type": "array", "items": {"type": "string"}}`,
+		`{"type": "object", "properties": {"o": {"type": "object"}}}`,
+		`{"type": "object", "properties": {"u": {"type": ["string", "integer"]}}}`,
+		`{"type": "object", "properties": {"r": {"$ref": "#/definitions/missing"}}}`,
+	} {
+		if _, err := newJSONSchemaDecoder(schema); err == nil {
+			t.Errorf("expected error parsing %s", schema)
+		}
+	}
+}
+
+func TestDecodeJSONSchemaWithSchemaRegistry(t *testing.T) {
+	server := newTestRegistry(t, map[string]*Schema{
+		"/schemas/ids/4": {SchemaType: "JSON", Schema: testJSONSchema},
+	})
+	src := newTestRegistrySource(server.URL)
+
+	value := []byte(`{"name": "a", "tags": ["x"], "ids": [1, 2], "at": "2020-09-13T12:26:40Z", "price": 12.345678901234567, "n": 5, "color": "RED", "ok": null, "f": 1.5}`)
+	rec, _, err := src.decodeValueWithSchemaRegistry(confluentFrame(4, nil, value))
+	if err != idk.ErrSchemaChange {
+		t.Fatalf("expected schema change, got %v", err)
+	}
+	if len(src.lastSchema) != 9 {
+		t.Fatalf("unexpected schema: %v", src.lastSchema)
+	}
+	exp := map[string]interface{}{
+		"name":  "a",
+		"tags":  []interface{}{"x"},
+		"ids":   []interface{}{int64(1), int64(2)},
+		"at":    "2020-09-13T12:26:40Z",
+		"price": "12.345678901234567",
+		"n":     int64(5),
+		"color": "RED",
+		"ok":    nil,
+		"f":     "1.5",
+	}
+	if !reflect.DeepEqual(rec, exp) {
+		t.Errorf("unexpected record:\n got: %v\nwant: %v", rec, exp)
+	}
+
+	if _, _, err := src.decodeValueWithSchemaRegistry(confluentFrame(4, nil, []byte(`{"n": 6}`))); err != nil {
+		t.Fatalf("decoding again: %v", err)
+	}
+}
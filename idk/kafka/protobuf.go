@@ -0,0 +1,561 @@
+package kafka
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	// Register the well-known types which schemas may import.
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Protobuf schemas are fetched from the registry as serialized
+// FileDescriptorProtos, so that they needn't be parsed from .proto source.
+// The registry doesn't register the files which Confluent's serializers
+// supply themselves, so those which describe values (confluent.type.Decimal)
+// are built in here, and the rest (confluent/meta.proto, whose field options
+// are read from their wire format) are left unresolved.
+
+const (
+	confluentDecimalName = "confluent.type.Decimal"
+	timestampName        = "google.protobuf.Timestamp"
+
+	// confluentFieldMetaNumber is the field number of the
+	// (confluent.field_meta) field option, and confluentMetaParamsNumber
+	// that of the params map in it.
+	confluentFieldMetaNumber  = 1001
+	confluentMetaParamsNumber = 2
+)
+
+var confluentDecimalFile = &descriptorpb.FileDescriptorProto{
+	Name:    proto.String("confluent/type/decimal.proto"),
+	Package: proto.String("confluent.type"),
+	Syntax:  proto.String("proto3"),
+	MessageType: []*descriptorpb.DescriptorProto{{
+		Name: proto.String("Decimal"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			{Name: proto.String("value"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_BYTES.Enum(), JsonName: proto.String("value")},
+			{Name: proto.String("precision"), Number: proto.Int32(2), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_UINT32.Enum(), JsonName: proto.String("precision")},
+			{Name: proto.String("scale"), Number: proto.Int32(3), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(), JsonName: proto.String("scale")},
+		},
+	}},
+}
+
+// protobufDecoder decodes values of a Protobuf schema. Each value is framed
+// with the indexes of its message type within the schema's file.
+type protobufDecoder struct {
+	file   protoreflect.FileDescriptor
+	fields []idk.Field
+
+	// messages holds the descriptors of the message types seen so far, by
+	// their indexes, and their fields.
+	messages map[string]*protobufMessage
+}
+
+type protobufMessage struct {
+	desc   protoreflect.MessageDescriptor
+	fields []idk.Field
+}
+
+// newProtobufDecoder gets the Protobuf schema with the given ID, and those
+// it references, from the registry.
+func (s *Source) newProtobufDecoder(id int32) (*protobufDecoder, error) {
+	files := &protoregistry.Files{}
+	fd, err := s.protobufFile(files, fmt.Sprintf("schemas/ids/%d", id), "")
+	if err != nil {
+		return nil, err
+	}
+	return newProtobufDecoder(fd)
+}
+
+func newProtobufDecoder(fd protoreflect.FileDescriptor) (*protobufDecoder, error) {
+	if fd.Messages().Len() == 0 {
+		return nil, errors.New("schema has no message types")
+	}
+	d := &protobufDecoder{
+		file:     fd,
+		messages: make(map[string]*protobufMessage),
+	}
+	// The first message type is the default, and the only one most
+	// schemas have.
+	msg, err := d.message([]int{0})
+	if err != nil {
+		return nil, err
+	}
+	d.fields = msg.fields
+	return d, nil
+}
+
+// protobufFile gets the serialized schema at urlPath from the registry, and
+// everything it references, and builds its file descriptor. If name is set,
+// it's the name other files import the schema as.
+func (s *Source) protobufFile(files *protoregistry.Files, urlPath, name string) (protoreflect.FileDescriptor, error) {
+	schema := &Schema{}
+	err := s.registryGet(urlPath, url.Values{"format": []string{"serialized"}}, schema)
+	if err != nil {
+		return nil, err
+	}
+	if schema.SchemaType != "PROTOBUF" {
+		return nil, errors.Errorf("%s is a %s schema, not PROTOBUF", urlPath, schema.SchemaType)
+	}
+	raw, err := base64.StdEncoding.DecodeString(schema.Schema)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding serialized schema")
+	}
+	fdp := &descriptorpb.FileDescriptorProto{}
+	if err := proto.Unmarshal(raw, fdp); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling serialized schema")
+	}
+	if name != "" {
+		fdp.Name = proto.String(name)
+	}
+
+	for _, ref := range schema.References {
+		if _, err := files.FindFileByPath(ref.Name); err == nil {
+			continue
+		}
+		refPath := fmt.Sprintf("subjects/%s/versions/%d", ref.Subject, ref.Version)
+		if _, err := s.protobufFile(files, refPath, ref.Name); err != nil {
+			return nil, errors.Wrapf(err, "getting referenced schema %s", ref.Name)
+		}
+	}
+	return buildProtobufFile(files, fdp)
+}
+
+// buildProtobufFile builds and registers the file descriptor for fdp, whose
+// references must already be registered in files.
+func buildProtobufFile(files *protoregistry.Files, fdp *descriptorpb.FileDescriptorProto) (protoreflect.FileDescriptor, error) {
+	for _, dep := range fdp.GetDependency() {
+		if _, err := files.FindFileByPath(dep); err == nil {
+			continue
+		}
+		var depFile protoreflect.FileDescriptor
+		if dep == confluentDecimalFile.GetName() {
+			var err error
+			depFile, err = protodesc.NewFile(confluentDecimalFile, files)
+			if err != nil {
+				return nil, errors.Wrap(err, "building confluent decimal type")
+			}
+		} else if global, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+			depFile = global
+		} else {
+			continue
+		}
+		if err := files.RegisterFile(depFile); err != nil {
+			return nil, errors.Wrapf(err, "registering %s", dep)
+		}
+	}
+
+	fd, err := protodesc.FileOptions{AllowUnresolvable: true}.New(fdp, files)
+	if err != nil {
+		return nil, errors.Wrap(err, "building file descriptor")
+	}
+	if err := files.RegisterFile(fd); err != nil {
+		return nil, errors.Wrapf(err, "registering %s", fd.Path())
+	}
+	return fd, nil
+}
+
+func (d *protobufDecoder) Fields() []idk.Field {
+	return d.fields
+}
+
+func (d *protobufDecoder) String() string {
+	b, err := protojson.Marshal(protodesc.ToFileDescriptorProto(d.file))
+	if err != nil {
+		return string(d.file.Path())
+	}
+	return string(b)
+}
+
+// Decode decodes a value framed, as Confluent's serializers do, by the
+// indexes of its message type: a zigzag varint count followed by that many
+// zigzag varint indexes, where a lone 0 means the first message type.
+func (d *protobufDecoder) Decode(data []byte) (map[string]interface{}, error) {
+	n, l := binary.Varint(data)
+	if l <= 0 {
+		return nil, errors.New("reading message index count")
+	}
+	data = data[l:]
+	indexes := []int{0}
+	if n > 0 {
+		indexes = make([]int, n)
+		for i := range indexes {
+			idx, l := binary.Varint(data)
+			if l <= 0 {
+				return nil, errors.New("reading message index")
+			}
+			indexes[i] = int(idx)
+			data = data[l:]
+		}
+	}
+
+	msg, err := d.message(indexes)
+	if err != nil {
+		return nil, err
+	}
+	// Every message type of a schema must have the same fields, since
+	// there's no way to tell the ingester which one a record has.
+	if len(indexes) > 1 || indexes[0] != 0 {
+		if !reflect.DeepEqual(msg.fields, d.fields) {
+			return nil, errors.Errorf("message type %s has different fields from %s", msg.desc.FullName(), d.file.Messages().Get(0).FullName())
+		}
+	}
+
+	m := dynamicpb.NewMessage(msg.desc)
+	if err := proto.Unmarshal(data, m); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling protobuf message")
+	}
+
+	ret := make(map[string]interface{}, msg.desc.Fields().Len())
+	fields := msg.desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.HasPresence() && !m.Has(fd) {
+			ret[string(fd.Name())] = nil
+			continue
+		}
+		val, err := protobufValue(fd, m.Get(fd))
+		if err != nil {
+			return nil, errors.Wrapf(err, "field %s", fd.Name())
+		}
+		ret[string(fd.Name())] = val
+	}
+	return ret, nil
+}
+
+// message returns the message type at indexes, where the first index is
+// that of a top-level message type in the file and each further one that of
+// a message type nested in the previous one.
+func (d *protobufDecoder) message(indexes []int) (*protobufMessage, error) {
+	key := fmt.Sprint(indexes)
+	if msg, ok := d.messages[key]; ok {
+		return msg, nil
+	}
+	msgs := d.file.Messages()
+	var desc protoreflect.MessageDescriptor
+	for _, idx := range indexes {
+		if idx < 0 || idx >= msgs.Len() {
+			return nil, errors.Errorf("no message type at index %v", indexes)
+		}
+		desc = msgs.Get(idx)
+		msgs = desc.Messages()
+	}
+	fields, err := protobufToPDKSchema(desc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "converting %s to FeatureBase schema", desc.FullName())
+	}
+	msg := &protobufMessage{desc: desc, fields: fields}
+	d.messages[key] = msg
+	return msg, nil
+}
+
+// protobufToPDKSchema converts a Protobuf message type to []idk.Field. As
+// with Avro, only flat messages are supported, with the exception of a few
+// well-known message types which hold a single value.
+func protobufToPDKSchema(desc protoreflect.MessageDescriptor) ([]idk.Field, error) {
+	fields := desc.Fields()
+	pdkFields := make([]idk.Field, 0, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		pdkField, err := protobufToPDKField(fields.Get(i))
+		if err != nil {
+			return nil, errors.Wrap(err, "converting protobuf field to pdk")
+		}
+		pdkFields = append(pdkFields, pdkField)
+	}
+	return pdkFields, nil
+}
+
+func protobufToPDKField(fd protoreflect.FieldDescriptor) (idk.Field, error) {
+	return protobufValueToPDKField(string(fd.Name()), fd, protobufFieldProps(fd))
+}
+
+// protobufValueToPDKField returns the idk.Field named name for values of
+// field fd with the properties p.
+func protobufValueToPDKField(name string, fd protoreflect.FieldDescriptor, p props) (idk.Field, error) {
+	if fd.IsMap() {
+		return nil, errors.Errorf("nested fields are not currently supported, so the field type cannot be map.")
+	}
+	if fd.IsList() {
+		switch fd.Kind() {
+		case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.EnumKind:
+			return arrayToPDKField(name, p, false)
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+			protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+			protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			return arrayToPDKField(name, p, true)
+		default:
+			return nil, errors.Errorf("repeated field type of %s is unsupported", fd.Kind())
+		}
+	}
+
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return textToPDKField(name, p)
+
+	case protoreflect.BytesKind:
+		return bytesToPDKField(name, p)
+
+	case protoreflect.EnumKind:
+		return idk.StringField{
+			NameVal: name,
+			Mutex:   true,
+		}, nil
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return intToPDKField(name, p)
+
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return floatToPDKField(name, p)
+
+	case protoreflect.BoolKind:
+		return idk.BoolField{
+			NameVal: name,
+		}, nil
+
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		msg := fd.Message()
+		switch fullName := string(msg.FullName()); {
+		case fullName == timestampName:
+			granularity, err := stringProp(p, "granularity")
+			if err == errWrongType {
+				return nil, errors.Errorf("property provided in wrong type for TimestampField: granularity, err:%v", err)
+			}
+			return idk.TimestampField{
+				NameVal:     name,
+				Granularity: granularity,
+			}, nil
+
+		case fullName == confluentDecimalName:
+			scale, err := intProp(p, "scale")
+			if scale > 18 || err == errWrongType {
+				return nil, errors.Errorf("0<=scale<=18, got:%d err:%v", scale, err)
+			}
+			return idk.DecimalField{
+				NameVal: name,
+				Scale:   scale,
+			}, nil
+
+		case isProtobufWrapper(msg):
+			// A wrapper is a nullable scalar, so it is treated as
+			// its value would be.
+			return protobufValueToPDKField(name, msg.Fields().ByName("value"), p)
+		}
+		return nil, errors.Errorf("nested fields are not currently supported, so the field type cannot be message %s.", msg.FullName())
+
+	default:
+		return nil, errors.Errorf("unknown protobuf field type: %s", fd.Kind())
+	}
+}
+
+func isProtobufWrapper(msg protoreflect.MessageDescriptor) bool {
+	if msg.ParentFile() == nil || msg.ParentFile().Path() != "google/protobuf/wrappers.proto" {
+		return false
+	}
+	return msg.Fields().Len() == 1 && msg.Fields().ByName("value") != nil
+}
+
+// protobufValue converts a Protobuf value to what the idk.Field for its
+// field expects.
+func protobufValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) (interface{}, error) {
+	if fd.IsList() {
+		list := v.List()
+		vals := make([]interface{}, list.Len())
+		for i := range vals {
+			val, err := protobufScalar(fd, list.Get(i))
+			if err != nil {
+				return nil, err
+			}
+			vals[i] = val
+		}
+		return vals, nil
+	}
+
+	if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+		msg := v.Message()
+		desc := msg.Descriptor()
+		switch {
+		case desc.FullName() == timestampName:
+			secs := msg.Get(desc.Fields().ByName("seconds")).Int()
+			nanos := msg.Get(desc.Fields().ByName("nanos")).Int()
+			return time.Unix(secs, nanos).UTC(), nil
+
+		case desc.FullName() == confluentDecimalName:
+			return protobufDecimal(msg)
+
+		case isProtobufWrapper(desc):
+			inner := desc.Fields().ByName("value")
+			return protobufScalar(inner, msg.Get(inner))
+		}
+		return nil, errors.Errorf("unsupported message type %s", desc.FullName())
+	}
+	return protobufScalar(fd, v)
+}
+
+func protobufScalar(fd protoreflect.FieldDescriptor, v protoreflect.Value) (interface{}, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return v.String(), nil
+	case protoreflect.BytesKind:
+		return v.Bytes(), nil
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name()), nil
+		}
+		return strconv.Itoa(int(v.Enum())), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return v.Int(), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return v.Uint(), nil
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float(), nil
+	case protoreflect.BoolKind:
+		return v.Bool(), nil
+	default:
+		return nil, errors.Errorf("unsupported protobuf value type %s", fd.Kind())
+	}
+}
+
+// protobufDecimal converts a confluent.type.Decimal, whose value is the
+// big-endian two's complement of the unscaled value, to a pql.Decimal.
+func protobufDecimal(msg protoreflect.Message) (interface{}, error) {
+	desc := msg.Descriptor()
+	raw := msg.Get(desc.Fields().ByName("value")).Bytes()
+	scale := msg.Get(desc.Fields().ByName("scale")).Int()
+	if len(raw) == 0 {
+		return pql.NewDecimal(0, scale), nil
+	}
+	unscaled := new(big.Int).SetBytes(raw)
+	if raw[0]&0x80 != 0 {
+		unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(raw)*8)))
+	}
+	if !unscaled.IsInt64() {
+		return nil, errors.Errorf("decimal value %s is out of range", unscaled)
+	}
+	return pql.NewDecimal(unscaled.Int64(), scale), nil
+}
+
+// protobufFieldProps returns the params of a field's (confluent.field_meta)
+// option, which are how Protobuf schemas give the properties Avro schemas
+// put on fields. Params are all strings, so "true" and "false" are taken to
+// be booleans.
+func protobufFieldProps(fd protoreflect.FieldDescriptor) props {
+	p := props{}
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return p
+	}
+	for b := []byte(opts.ProtoReflect().GetUnknown()); len(b) > 0; {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return p
+		}
+		b = b[n:]
+		if num == confluentFieldMetaNumber && typ == protowire.BytesType {
+			meta, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return p
+			}
+			b = b[n:]
+			protobufMetaParams(meta, p)
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return p
+		}
+		b = b[n:]
+	}
+	return p
+}
+
+// protobufMetaParams adds the entries of the params map in a serialized
+// confluent.Meta to p.
+func protobufMetaParams(meta []byte, p props) {
+	for len(meta) > 0 {
+		num, typ, n := protowire.ConsumeTag(meta)
+		if n < 0 {
+			return
+		}
+		meta = meta[n:]
+		if num != confluentMetaParamsNumber || typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, meta)
+			if n < 0 {
+				return
+			}
+			meta = meta[n:]
+			continue
+		}
+		entry, n := protowire.ConsumeBytes(meta)
+		if n < 0 {
+			return
+		}
+		meta = meta[n:]
+
+		var key, val string
+		for len(entry) > 0 {
+			num, typ, n := protowire.ConsumeTag(entry)
+			if n < 0 {
+				return
+			}
+			entry = entry[n:]
+			if typ != protowire.BytesType {
+				n = protowire.ConsumeFieldValue(num, typ, entry)
+				if n < 0 {
+					return
+				}
+				entry = entry[n:]
+				continue
+			}
+			s, n := protowire.ConsumeString(entry)
+			if n < 0 {
+				return
+			}
+			entry = entry[n:]
+			switch num {
+			case 1:
+				key = s
+			case 2:
+				val = s
+			}
+		}
+		switch val {
+		case "true":
+			p[key] = true
+		case "false":
+			p[key] = false
+		default:
+			p[key] = val
+		}
+	}
+}
+
+// props holds the properties of a field in schema types which, unlike
+// Avro, have no properties of their own.
+type props map[string]interface{}
+
+func (p props) Prop(key string) (interface{}, bool) {
+	v, ok := p[key]
+	return v, ok
+}
@@ -0,0 +1,237 @@
+//go:build !kafka_sasl
+// +build !kafka_sasl
+
+package kafka
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// newTestRegistry serves the given schemas by path, as the schema registry
+// would.
+func newTestRegistry(t *testing.T, schemas map[string]*Schema) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		schema, ok := schemas[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(schema); err != nil {
+			t.Errorf("encoding schema: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestRegistrySource(url string) *Source {
+	src := NewSource()
+	src.SchemaRegistryURL = url
+	src.httpClient = http.DefaultClient
+	return src
+}
+
+// confluentFrame prefixes a value with the magic byte and schema ID, and
+// any further header bytes.
+func confluentFrame(id uint32, header []byte, value []byte) []byte {
+	framed := []byte{0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(framed[1:], id)
+	framed = append(framed, header...)
+	return append(framed, value...)
+}
+
+// fieldMeta returns field options with a (confluent.field_meta) option
+// holding params.
+func fieldMeta(params map[string]string) *descriptorpb.FieldOptions {
+	var meta []byte
+	for k, v := range params {
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, k)
+		entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+		entry = protowire.AppendString(entry, v)
+		meta = protowire.AppendTag(meta, confluentMetaParamsNumber, protowire.BytesType)
+		meta = protowire.AppendBytes(meta, entry)
+	}
+	var unknown []byte
+	unknown = protowire.AppendTag(unknown, confluentFieldMetaNumber, protowire.BytesType)
+	unknown = protowire.AppendBytes(unknown, meta)
+
+	opts := &descriptorpb.FieldOptions{}
+	opts.ProtoReflect().SetUnknown(unknown)
+	return opts
+}
+
+func protoField(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string, repeated bool, opts *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+	label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	if repeated {
+		label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	}
+	fd := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    label.Enum(),
+		Type:     typ.Enum(),
+		JsonName: proto.String(name),
+		Options:  opts,
+	}
+	if typeName != "" {
+		fd.TypeName = proto.String(typeName)
+	}
+	return fd
+}
+
+func testProtobufFile() *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("row.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		Dependency: []string{
+			"google/protobuf/timestamp.proto",
+			"google/protobuf/wrappers.proto",
+			"confluent/type/decimal.proto",
+			"confluent/meta.proto",
+		},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Color"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("RED"), Number: proto.Int32(0)},
+				{Name: proto.String("BLUE"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Row"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				protoField("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", false, fieldMeta(map[string]string{"mutex": "true"})),
+				protoField("tags", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", true, nil),
+				protoField("ids", 3, descriptorpb.FieldDescriptorProto_TYPE_INT64, "", true, nil),
+				protoField("at", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp", false, fieldMeta(map[string]string{"granularity": "D"})),
+				protoField("price", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".confluent.type.Decimal", false, fieldMeta(map[string]string{"scale": "2"})),
+				protoField("n", 6, descriptorpb.FieldDescriptorProto_TYPE_INT64, "", false, fieldMeta(map[string]string{"fieldType": "id"})),
+				protoField("color", 7, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".test.Color", false, nil),
+				protoField("maybe", 8, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Int64Value", false, nil),
+			},
+		}},
+	}
+}
+
+func TestProtobufToPDKSchema(t *testing.T) {
+	fd, err := buildProtobufFile(&protoregistry.Files{}, testProtobufFile())
+	if err != nil {
+		t.Fatalf("building file: %v", err)
+	}
+	fields, err := protobufToPDKSchema(fd.Messages().Get(0))
+	if err != nil {
+		t.Fatalf("converting schema: %v", err)
+	}
+	exp := []idk.Field{
+		idk.StringField{NameVal: "name", Mutex: true},
+		idk.StringArrayField{NameVal: "tags"},
+		idk.IDArrayField{NameVal: "ids"},
+		idk.TimestampField{NameVal: "at", Granularity: "D"},
+		idk.DecimalField{NameVal: "price", Scale: 2},
+		idk.IDField{NameVal: "n"},
+		idk.StringField{NameVal: "color", Mutex: true},
+		idk.IntField{NameVal: "maybe"},
+	}
+	if !reflect.DeepEqual(fields, exp) {
+		t.Errorf("unexpected fields:\n got: %#v\nwant: %#v", fields, exp)
+	}
+
+	// nested messages aren't supported
+	fdp := testProtobufFile()
+	fdp.MessageType = append(fdp.MessageType, &descriptorpb.DescriptorProto{
+		Name:  proto.String("Outer"),
+		Field: []*descriptorpb.FieldDescriptorProto{protoField("row", 1, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".test.Row", false, nil)},
+	})
+	fd, err = buildProtobufFile(&protoregistry.Files{}, fdp)
+	if err != nil {
+		t.Fatalf("building file: %v", err)
+	}
+	if _, err := protobufToPDKSchema(fd.Messages().Get(1)); err == nil {
+		t.Errorf("expected error converting nested message")
+	}
+}
+
+func TestDecodeProtobufWithSchemaRegistry(t *testing.T) {
+	fdp := testProtobufFile()
+	raw, err := proto.Marshal(fdp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newTestRegistry(t, map[string]*Schema{
+		"/schemas/ids/3": {SchemaType: "PROTOBUF", Schema: base64.StdEncoding.EncodeToString(raw)},
+	})
+	src := newTestRegistrySource(server.URL)
+
+	fd, err := buildProtobufFile(&protoregistry.Files{}, fdp)
+	if err != nil {
+		t.Fatalf("building file: %v", err)
+	}
+	desc := fd.Messages().Get(0)
+	msg := dynamicpb.NewMessage(desc)
+	set := func(name string, v protoreflect.Value) { msg.Set(desc.Fields().ByName(protoreflect.Name(name)), v) }
+	set("name", protoreflect.ValueOfString("a"))
+	tags := msg.Mutable(desc.Fields().ByName("tags")).List()
+	tags.Append(protoreflect.ValueOfString("x"))
+	tags.Append(protoreflect.ValueOfString("y"))
+	msg.Mutable(desc.Fields().ByName("ids")).List().Append(protoreflect.ValueOfInt64(7))
+	at := msg.Mutable(desc.Fields().ByName("at")).Message()
+	at.Set(at.Descriptor().Fields().ByName("seconds"), protoreflect.ValueOfInt64(1600000000))
+	price := msg.Mutable(desc.Fields().ByName("price")).Message()
+	price.Set(price.Descriptor().Fields().ByName("value"), protoreflect.ValueOfBytes([]byte{0xfb, 0x2e})) // -1234
+	price.Set(price.Descriptor().Fields().ByName("scale"), protoreflect.ValueOfInt32(2))
+	set("n", protoreflect.ValueOfInt64(5))
+	set("color", protoreflect.ValueOfEnum(1))
+	value, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec, _, err := src.decodeValueWithSchemaRegistry(confluentFrame(3, []byte{0}, value))
+	if err != idk.ErrSchemaChange {
+		t.Fatalf("expected schema change, got %v", err)
+	}
+	if len(src.lastSchema) != 8 {
+		t.Fatalf("unexpected schema: %v", src.lastSchema)
+	}
+	exp := map[string]interface{}{
+		"name":  "a",
+		"tags":  []interface{}{"x", "y"},
+		"ids":   []interface{}{int64(7)},
+		"at":    time.Unix(1600000000, 0).UTC(),
+		"price": pql.NewDecimal(-1234, 2),
+		"n":     int64(5),
+		"color": "BLUE",
+		"maybe": nil,
+	}
+	if !reflect.DeepEqual(rec, exp) {
+		t.Errorf("unexpected record:\n got: %v\nwant: %v", rec, exp)
+	}
+
+	// the same schema again isn't a schema change, and a message index of
+	// [0] written in full is the same as the short form
+	if _, _, err := src.decodeValueWithSchemaRegistry(confluentFrame(3, []byte{2, 0}, value)); err != nil {
+		t.Fatalf("decoding again: %v", err)
+	}
+	if _, _, err := src.decodeValueWithSchemaRegistry(confluentFrame(3, []byte{2, 4}, value)); err == nil {
+		t.Errorf("expected error decoding nonexistent message type")
+	}
+}
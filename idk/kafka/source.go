@@ -43,6 +43,21 @@ type Source struct {
 	TLS                  idk.TLSConfig
 	consumerCloseTimeout int
 
+	// CDC is the change-data-capture mode, if messages are change events
+	// rather than records. The only mode is CDCDebezium.
+	CDC string
+
+	// positions holds the position of the latest change event from each
+	// partition, so that changes older than those already delivered are
+	// skipped. It starts from the positions in the checkpoint.
+	positions map[string]debeziumPosition
+
+	// Checkpoint, if set, returns the offsets and positions stored with the
+	// data already ingested, keyed like Record.StreamOffset, from which
+	// assigned partitions resume rather than from the group's committed
+	// offsets.
+	Checkpoint func() (map[string]uint64, map[string]string, error)
+
 	spoolBase     uint64
 	spool         []confluent.TopicPartition
 	highmarks     []confluent.TopicPartition
@@ -58,8 +73,10 @@ type Source struct {
 	lastSchema   []idk.Field
 
 	// cache is a schema cache so we don't have to look up the same
-	// schema from the registry each time.
+	// schema from the registry each time. Schemas which aren't Avro
+	// are cached in decoders.
 	cache      map[int32]avro.Schema
+	decoders   map[int32]registryDecoder
 	httpClient *http.Client
 	// synchronize closing
 	quit   chan struct{}
@@ -80,6 +97,8 @@ func NewSource() *Source {
 
 		lastSchemaID:  -1,
 		cache:         make(map[int32]avro.Schema),
+		decoders:      make(map[int32]registryDecoder),
+		positions:     make(map[string]debeziumPosition),
 		recordChannel: make(chan recordWithError),
 		quit:          make(chan struct{}),
 		ConfigMap:     &confluent.ConfigMap{},
@@ -94,44 +113,108 @@ func NewSource() *Source {
 // object may be used by successive calls to Record, so it should not
 // be retained.
 func (s *Source) Record() (idk.Record, error) {
-	rec := s.fetch()
-	switch rec.Err {
-	case nil:
-	case io.EOF:
-		return nil, io.EOF
-	case context.DeadlineExceeded:
-		return nil, idk.ErrFlush
-	default:
-		return nil, errors.Wrap(rec.Err, "failed to fetch record from Kafka")
-	}
+	for {
+		rec := s.fetch()
+		switch rec.Err {
+		case nil:
+		case io.EOF:
+			return nil, io.EOF
+		case context.DeadlineExceeded:
+			return nil, idk.ErrFlush
+		default:
+			return nil, errors.Wrap(rec.Err, "failed to fetch record from Kafka")
+		}
 
-	if rec.Record == nil {
-		return nil, idk.ErrFlush
-	}
+		if rec.Record == nil {
+			return nil, idk.ErrFlush
+		}
+		msg := rec.Record
+
+		// Debezium follows each delete with a tombstone, so that compaction
+		// can remove the deleted row's messages. There's nothing in it to
+		// ingest.
+		if s.CDC != "" && len(msg.Value) == 0 {
+			s.skip(msg)
+			continue
+		}
+
+		val, avroSchema, err := s.decodeValueWithSchemaRegistry(msg.Value)
+		if err != nil && err != idk.ErrSchemaChange {
+			return nil, errors.Wrap(err, "decoding with schema registry")
+		}
 
-	val, avroSchema, err := s.decodeAvroValueWithSchemaRegistry(rec.Record.Value)
-	if err != nil && err != idk.ErrSchemaChange {
-		return nil, errors.Wrap(err, "decoding with schema registry")
+		var change *debeziumChange
+		if s.CDC == CDCDebezium {
+			var derr error
+			change, derr = decodeDebeziumChange(val)
+			if derr != nil {
+				return nil, errors.Wrap(derr, "decoding Debezium change event")
+			}
+			if s.stale(msg.TopicPartition, change) {
+				s.Log.Infof("skipping change event at %s behind one already ingested", msg.TopicPartition)
+				if err == idk.ErrSchemaChange {
+					// the next record must still report the change
+					s.lastSchemaID = -1
+				}
+				s.skip(msg)
+				continue
+			}
+			val = change.row
+		}
+		data := s.toPDKRecord(val)
+
+		// with librdkafka, committing an offset means that offset is
+		// where we should pick up from... we don't want to re-read this
+		// message, so we add 1
+		msg.TopicPartition.Offset++
+		s.mu.Lock()
+		s.spool = append(s.spool, msg.TopicPartition)
+		r := &Record{
+			src:        s,
+			topic:      *msg.TopicPartition.Topic,
+			partition:  int(msg.TopicPartition.Partition),
+			offset:     int64(msg.TopicPartition.Offset),
+			idx:        s.spoolBase + uint64(len(s.spool)),
+			data:       data,
+			avroSchema: avroSchema,
+			payload:    msg.Value,
+		}
+		s.mu.Unlock()
+		if change != nil {
+			cr := &changeRecord{Record: r, deleted: change.deleted}
+			if change.ordered {
+				cr.position = change.position.String()
+			}
+			return cr, err
+		}
+		return r, err
 	}
-	data := s.toPDKRecord(val.(map[string]interface{})) // val must be map[string]interface{} because we only accept schemas which are Record type at the top level.
+}
 
-	msg := rec.Record
-	// with librdkafka, committing an offset means that offset is
-	// where we should pick up from... we don't want to re-read this
-	// message, so we add 1
-	msg.TopicPartition.Offset++
+// skip spools a message which isn't delivered as a record, so that it's
+// committed along with the next record which is.
+func (s *Source) skip(msg *confluent.Message) {
+	tp := msg.TopicPartition
+	tp.Offset++
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.spool = append(s.spool, msg.TopicPartition)
-	return &Record{
-		src:        s,
-		topic:      *msg.TopicPartition.Topic,
-		partition:  int(msg.TopicPartition.Partition),
-		offset:     int64(msg.TopicPartition.Offset),
-		idx:        s.spoolBase + uint64(len(s.spool)),
-		data:       data,
-		avroSchema: avroSchema,
-	}, err
+	s.spool = append(s.spool, tp)
+}
+
+// stale reports whether a change event from the given partition was read
+// from further back in the source database's log than the latest one
+// delivered from it, as happens when a connector restarts and sends changes
+// again. Otherwise, the event's position becomes the latest.
+func (s *Source) stale(tp confluent.TopicPartition, change *debeziumChange) bool {
+	if !change.ordered {
+		return false
+	}
+	key := *tp.Topic + ":" + strconv.Itoa(int(tp.Partition))
+	if latest, ok := s.positions[key]; ok && change.position.before(latest) {
+		return true
+	}
+	s.positions[key] = change.position
+	return false
 }
 
 type recordWithError struct {
@@ -172,6 +255,9 @@ func (s *Source) SchemaID() int {
 }
 
 func (s *Source) SchemaMetadata() string {
+	if dec, ok := s.decoders[s.lastSchemaID]; ok {
+		return dec.String()
+	}
 	var buf bytes.Buffer
 	err := json.Compact(&buf, []byte(s.cache[s.lastSchemaID].String()))
 	if err != nil {
@@ -197,6 +283,7 @@ type Record struct {
 	idx        uint64
 	data       []interface{}
 	avroSchema avro.Schema
+	payload    []byte
 }
 
 func (r *Record) StreamOffset() (string, uint64) {
@@ -205,6 +292,39 @@ func (r *Record) StreamOffset() (string, uint64) {
 
 var _ idk.OffsetStreamRecord = &Record{}
 
+// resume sets the offset of each of the partitions which has one in the
+// checkpoint, and the position of the latest change event from it.
+func (s *Source) resume(partitions []confluent.TopicPartition) error {
+	if s.Checkpoint == nil {
+		return nil
+	}
+	offsets, positions, err := s.Checkpoint()
+	if err != nil {
+		return errors.Wrap(err, "getting checkpoint")
+	}
+	for i, tp := range partitions {
+		if tp.Topic == nil {
+			continue
+		}
+		key := *tp.Topic + ":" + strconv.Itoa(int(tp.Partition))
+		if offset, ok := offsets[key]; ok {
+			partitions[i].Offset = confluent.Offset(offset)
+		}
+		if position, ok := positions[key]; ok {
+			p, err := parseDebeziumPosition(position)
+			if err != nil {
+				return errors.Wrapf(err, "resuming %s", key)
+			}
+			s.positions[key] = p
+		}
+	}
+	return nil
+}
+
+func (r *Record) Payload() []byte { return r.payload }
+
+var _ idk.PayloadRecord = &Record{}
+
 func (r *Record) Commit(ctx context.Context) error {
 	r.src.mu.Lock()
 	defer r.src.mu.Unlock()
@@ -268,6 +388,9 @@ func (s *Source) CommitMessages(recs []confluent.TopicPartition) ([]confluent.To
 // The configuration options for the confluentinc/confluent-kafka-go/kafka
 // libarary are: https://github.com/confluentinc/librdkafka/blob/master/CONFIGURATION.md
 func (s *Source) Open() error {
+	if s.CDC != "" && s.CDC != CDCDebezium {
+		return errors.Errorf("unknown CDC mode %q, must be %q", s.CDC, CDCDebezium)
+	}
 	cfg, err := common.SetupConfluent(&s.ConfluentCommand)
 	if err != nil {
 		return err
@@ -381,6 +504,13 @@ func (c *Source) generator() {
 			// If we received an `AssignedPartitions` event, we need to make sure we
 			// assign the currently running consumer to the right partitions.
 			case confluent.AssignedPartitions:
+				if err := c.resume(e.Partitions); err != nil {
+					select {
+					case c.recordChannel <- recordWithError{Err: err}:
+					case <-c.quit:
+					}
+					return
+				}
 				err := c.client.Assign(e.Partitions)
 				if err != nil {
 					if c.Verbose {
@@ -474,24 +604,46 @@ func (s *Source) Close() error {
 	return nil
 }
 
-// TODO change name
-func (s *Source) decodeAvroValueWithSchemaRegistry(val []byte) (interface{}, avro.Schema, error) {
+// decodeValueWithSchemaRegistry decodes a value framed with the ID of its
+// schema in the schema registry, dispatching on the type of the schema.
+func (s *Source) decodeValueWithSchemaRegistry(val []byte) (map[string]interface{}, avro.Schema, error) {
 	if len(val) < 6 || val[0] != 0 {
-		return nil, nil, errors.Errorf("unexpected magic byte or length in avro kafka value, should be 0x00, but got %x", val)
+		return nil, nil, errors.Errorf("unexpected magic byte or length in kafka value, should be 0x00, but got %x", val)
 	}
 	id := int32(binary.BigEndian.Uint32(val[1:]))
-	codec, err := s.getCodec(id)
+	codec, dec, err := s.getDecoder(id)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "getting avro codec")
+		return nil, nil, errors.Wrap(err, "getting schema")
 	}
-	ret, err := avroDecode(codec, val[5:])
-	if err != nil {
-		return nil, codec, errors.Wrap(err, "decoding avro record")
+
+	var ret map[string]interface{}
+	if codec != nil {
+		ret, err = avroDecode(codec, val[5:])
+		if err != nil {
+			return nil, codec, errors.Wrap(err, "decoding avro record")
+		}
+	} else {
+		ret, err = dec.Decode(val[5:])
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "decoding record")
+		}
 	}
+
 	if id != s.lastSchemaID {
-		s.lastSchema, err = avroToPDKSchema(codec)
-		if err != nil {
-			return nil, codec, errors.Wrap(err, "converting to FeatureBase schema")
+		if codec != nil {
+			rowCodec := codec
+			if s.CDC == CDCDebezium {
+				rowCodec, err = debeziumRowSchema(codec)
+				if err != nil {
+					return nil, codec, err
+				}
+			}
+			s.lastSchema, err = avroToPDKSchema(rowCodec)
+			if err != nil {
+				return nil, codec, errors.Wrap(err, "converting to FeatureBase schema")
+			}
+		} else {
+			s.lastSchema = dec.Fields()
 		}
 		s.lastSchemaID = id
 		return ret, codec, idk.ErrSchemaChange
@@ -500,6 +652,18 @@ func (s *Source) decodeAvroValueWithSchemaRegistry(val []byte) (interface{}, avr
 	return ret, codec, nil
 }
 
+// registryDecoder decodes values whose registry schema isn't Avro.
+type registryDecoder interface {
+	// Fields returns the fields of the schema.
+	Fields() []idk.Field
+
+	// Decode decodes a value, less the registry's framing, into the
+	// values of its fields by name.
+	Decode(data []byte) (map[string]interface{}, error)
+
+	String() string
+}
+
 // avroToPDKSchema converts a full avro schema to the much more
 // constrained []idk.Field which maps pretty directly onto
 // Pilosa. Many features of avro are unsupported and will cause this
@@ -532,25 +696,7 @@ func avroToPDKField(aField *avro.SchemaField) (idk.Field, error) {
 		return nil, errors.Errorf("nested fields are not currently supported, so the field type cannot be record.")
 
 	case avro.String:
-		fld := idk.StringField{NameVal: aField.Name}
-
-		if prop, ok := aField.Prop("mutex"); ok {
-			if mtx, ok := prop.(bool); ok {
-				fld.Mutex = mtx
-			}
-		}
-
-		if quantum, err := stringProp(aField, "quantum"); err == nil {
-			fld.Quantum = quantum
-			if ttl, err := stringProp(aField, "ttl"); err == nil {
-				fld.TTL = ttl
-			}
-		}
-
-		if cacheConfig, err := cacheConfigProp(aField); err == nil {
-			fld.CacheConfig = cacheConfig
-		}
-		return fld, nil
+		return stringToPDKField(aField.Name, aField), nil
 
 	case avro.Enum:
 		return idk.StringField{
@@ -559,266 +705,302 @@ func avroToPDKField(aField *avro.SchemaField) (idk.Field, error) {
 		}, nil
 
 	case avro.Bytes, avro.Fixed:
-		var ft string
+		return bytesToPDKField(aField.Name, aField)
+
+	case avro.Union:
+		return avroUnionToPDKField(aField)
 
-		// This is here to support the native avro logicalType.decimal,
-		// but we have also implemented fieldType.decimal to be consistent
-		// with the other fieldType options.
-		if ft, _ = stringProp(aField, "logicalType"); ft != "decimal" {
-			ft, _ = stringProp(aField, "fieldType")
+	case avro.Array:
+		itemSchema := aField.Type.(*avro.ArraySchema).Items
+
+		switch typ := itemSchema.Type(); typ {
+		case avro.String, avro.Bytes, avro.Fixed, avro.Enum:
+			return arrayToPDKField(aField.Name, itemSchema, false)
+
+		case avro.Long, avro.Int:
+			return arrayToPDKField(aField.Name, itemSchema, true)
+
+		default:
+			return nil, errors.Errorf("array items type of %d is unsupported", itemSchema.Type())
 		}
 
-		switch ft {
-		case "decimal":
-			scale, err := intProp(aField, "scale")
-			if scale > 18 || err == errWrongType {
-				return nil, errors.Errorf("0<=scale<=18, got:%d err:%v", scale, err)
-			}
-			return idk.DecimalField{
-				NameVal: aField.Name,
-				Scale:   int64(scale),
-			}, nil
+	case avro.Int, avro.Long:
+		return intToPDKField(aField.Name, aField)
 
-		case "dateInt":
-			layout, err := stringProp(aField, "layout")
-			if err != nil {
-				return nil, errors.Errorf("required property for DateIntField: layout, err:%v", err)
-			}
+	case avro.Float, avro.Double:
+		return floatToPDKField(aField.Name, aField)
 
-			strEpoch, err := stringProp(aField, "epoch")
-			if err != nil {
-				return nil, errors.Errorf("required property for DateIntField: epoch, err:%v", err)
-			}
-			epoch, err := time.Parse(layout, strEpoch)
-			if err != nil {
-				return nil, errors.Wrapf(err, "parsing epoch: %s", strEpoch)
-			}
+	case avro.Boolean:
+		return idk.BoolField{
+			NameVal: aField.Name,
+		}, nil
 
-			unit, err := stringProp(aField, "unit")
-			if err != nil {
-				return nil, errors.Errorf("required property for DateIntField: unit, err:%v", err)
-			}
+	case avro.Null:
+		return nil, errors.Errorf("null fields are not supported except inside Union")
 
-			customUnit, _ := stringProp(aField, "customUnit")
-
-			return idk.DateIntField{
-				NameVal:    aField.Name,
-				Layout:     layout,
-				Epoch:      epoch,
-				Unit:       idk.Unit(unit),
-				CustomUnit: customUnit,
-			}, nil
-		case "timestamp":
-			layout, err := stringProp(aField, "layout")
-			if err == errWrongType {
-				return nil, errors.Errorf("property provided in wrong type for TimestampField: layout, err:%v", err)
-			}
+	case avro.Map:
+		return nil, errors.Errorf("nested fields are not currently supported, so the field type cannot be map.")
 
-			unit, err := stringProp(aField, "unit")
-			if err == errWrongType {
-				return nil, errors.Errorf("property provided in wrong type for TimestampField: unit, err:%v", err)
-			}
+	case avro.Recursive:
+		return nil, errors.Errorf("recursive schema fields are not currently supported.")
 
-			if layout == "" && unit == "" {
-				return nil, errors.New("either layout or unit required for TimestampField")
-			}
+	default:
+		return nil, errors.Errorf("unknown schema type: %+v", aField.Type)
+	}
+}
 
-			granularity, err := stringProp(aField, "granularity")
-			if err == errWrongType {
-				return nil, errors.Errorf("property provided in wrong type for TimestampField: unit, err:%v", err)
-			}
+// The functions below map a field to an idk.Field given the kind of value
+// it holds and its properties. They're shared by the Avro, Protobuf and JSON
+// Schema decoders so that a property means the same thing whatever the
+// schema type.
 
-			var epoch time.Time
-			strEpoch, err := stringProp(aField, "epoch")
-			if err == nil {
-				epoch, err = time.Parse(layout, strEpoch)
-				if err != nil {
-					return nil, errors.Wrapf(err, "parsing epoch: %s", strEpoch)
-				}
-			}
+func stringToPDKField(name string, p propper) idk.StringField {
+	fld := idk.StringField{NameVal: name}
 
-			return idk.TimestampField{
-				NameVal:     aField.Name,
-				Granularity: granularity,
-				Layout:      layout,
-				Epoch:       epoch,
-				Unit:        idk.Unit(unit),
-			}, nil
-		case "recordTime":
-			layout, err := stringProp(aField, "layout")
-			if err != nil {
-				return nil, errors.Errorf("required property for RecordTimeField: layout, err:%v", err)
-			}
-			return idk.RecordTimeField{
-				NameVal: aField.Name,
-				Layout:  layout,
-			}, nil
+	if prop, ok := p.Prop("mutex"); ok {
+		if mtx, ok := prop.(bool); ok {
+			fld.Mutex = mtx
 		}
+	}
 
-		// If field type was not specified, then treat as string.
-		var mutex bool
-		if mtx, ok := aField.Prop("mutex"); ok {
-			if mtxb, ok := mtx.(bool); ok {
-				mutex = mtxb
-			}
+	if quantum, err := stringProp(p, "quantum"); err == nil {
+		fld.Quantum = quantum
+		if ttl, err := stringProp(p, "ttl"); err == nil {
+			fld.TTL = ttl
 		}
-		quantum, _ := stringProp(aField, "quantum")
-		ttl := ""
-		if quantum != "" {
-			ttl, _ = stringProp(aField, "ttl")
-		}
-		return idk.StringField{
-			NameVal: aField.Name,
-			Mutex:   mutex,
-			Quantum: quantum,
-			TTL:     ttl,
-		}, nil
+	}
 
-	case avro.Union:
-		return avroUnionToPDKField(aField)
+	if cacheConfig, err := cacheConfigProp(p); err == nil {
+		fld.CacheConfig = cacheConfig
+	}
+	return fld
+}
 
-	case avro.Array:
-		itemSchema := aField.Type.(*avro.ArraySchema).Items
+// textToPDKField is for string values in schema types which, unlike Avro,
+// have no bytes type to hang a fieldType on. A string with a fieldType or
+// logicalType is treated as Avro bytes would be, and as an Avro string
+// otherwise.
+func textToPDKField(name string, p propper) (idk.Field, error) {
+	_, errFT := stringProp(p, "fieldType")
+	_, errLT := stringProp(p, "logicalType")
+	if errFT == errNotFound && errLT == errNotFound {
+		return stringToPDKField(name, p), nil
+	}
+	return bytesToPDKField(name, p)
+}
 
-		quantum, _ := stringProp(itemSchema, "quantum")
-		ttl := ""
-		if quantum != "" {
-			ttl, _ = stringProp(itemSchema, "ttl")
+func bytesToPDKField(name string, p propper) (idk.Field, error) {
+	var ft string
+
+	// This is here to support the native avro logicalType.decimal,
+	// but we have also implemented fieldType.decimal to be consistent
+	// with the other fieldType options.
+	if ft, _ = stringProp(p, "logicalType"); ft != "decimal" {
+		ft, _ = stringProp(p, "fieldType")
+	}
+
+	switch ft {
+	case "decimal":
+		scale, err := intProp(p, "scale")
+		if scale > 18 || err == errWrongType {
+			return nil, errors.Errorf("0<=scale<=18, got:%d err:%v", scale, err)
 		}
-		cacheConfig, _ := cacheConfigProp(itemSchema)
+		return idk.DecimalField{
+			NameVal: name,
+			Scale:   int64(scale),
+		}, nil
 
-		switch typ := itemSchema.Type(); typ {
-		case avro.String, avro.Bytes, avro.Fixed, avro.Enum:
-			if ft, _ := stringProp(itemSchema, "fieldType"); ft == "decimal" {
-				return nil, errors.New("arrays of decimal are not supported")
-			}
-			return idk.StringArrayField{
-				NameVal:     aField.Name,
-				Quantum:     quantum,
-				TTL:         ttl,
-				CacheConfig: cacheConfig,
-			}, nil
+	case "dateInt":
+		return dateIntToPDKField(name, p)
 
-		case avro.Long, avro.Int:
-			if ft, _ := stringProp(itemSchema, "fieldType"); ft == "decimal" {
-				return nil, errors.New("arrays of decimal are not supported")
-			}
-			return idk.IDArrayField{
-				NameVal:     aField.Name,
-				Quantum:     quantum,
-				TTL:         ttl,
-				CacheConfig: cacheConfig,
-			}, nil
+	case "timestamp":
+		layout, err := stringProp(p, "layout")
+		if err == errWrongType {
+			return nil, errors.Errorf("property provided in wrong type for TimestampField: layout, err:%v", err)
+		}
 
-		default:
-			return nil, errors.Errorf("array items type of %d is unsupported", itemSchema.Type())
+		unit, err := stringProp(p, "unit")
+		if err == errWrongType {
+			return nil, errors.Errorf("property provided in wrong type for TimestampField: unit, err:%v", err)
 		}
 
-	case avro.Int, avro.Long:
-		ft, _ := stringProp(aField, "fieldType")
-		switch ft {
-		case "id":
-			fld := idk.IDField{NameVal: aField.Name}
-
-			if prop, ok := aField.Prop("mutex"); ok {
-				if mtx, ok := prop.(bool); ok {
-					fld.Mutex = mtx
-				}
-			}
+		if layout == "" && unit == "" {
+			return nil, errors.New("either layout or unit required for TimestampField")
+		}
 
-			if quantum, err := stringProp(aField, "quantum"); err == nil {
-				fld.Quantum = quantum
-				if ttl, err := stringProp(aField, "ttl"); err == nil {
-					fld.TTL = ttl
-				}
-			}
+		granularity, err := stringProp(p, "granularity")
+		if err == errWrongType {
+			return nil, errors.Errorf("property provided in wrong type for TimestampField: unit, err:%v", err)
+		}
 
-			if cacheConfig, err := cacheConfigProp(aField); err == nil {
-				fld.CacheConfig = cacheConfig
+		var epoch time.Time
+		strEpoch, err := stringProp(p, "epoch")
+		if err == nil {
+			epoch, err = time.Parse(layout, strEpoch)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing epoch: %s", strEpoch)
 			}
+		}
 
-			return fld, nil
+		return idk.TimestampField{
+			NameVal:     name,
+			Granularity: granularity,
+			Layout:      layout,
+			Epoch:       epoch,
+			Unit:        idk.Unit(unit),
+		}, nil
+	case "recordTime":
+		layout, err := stringProp(p, "layout")
+		if err != nil {
+			return nil, errors.Errorf("required property for RecordTimeField: layout, err:%v", err)
+		}
+		return idk.RecordTimeField{
+			NameVal: name,
+			Layout:  layout,
+		}, nil
+	}
 
-		case "int":
-			fld := idk.IntField{NameVal: aField.Name}
+	// If field type was not specified, then treat as string.
+	var mutex bool
+	if mtx, ok := p.Prop("mutex"); ok {
+		if mtxb, ok := mtx.(bool); ok {
+			mutex = mtxb
+		}
+	}
+	quantum, _ := stringProp(p, "quantum")
+	ttl := ""
+	if quantum != "" {
+		ttl, _ = stringProp(p, "ttl")
+	}
+	return idk.StringField{
+		NameVal: name,
+		Mutex:   mutex,
+		Quantum: quantum,
+		TTL:     ttl,
+	}, nil
+}
 
-			if min, err := intProp(aField, "min"); err == nil {
-				fld.Min = intptr(min)
-			}
-			if max, err := intProp(aField, "max"); err == nil {
-				fld.Max = intptr(max)
-			}
-			return fld, nil
+// arrayToPDKField returns a StringArrayField, or an IDArrayField if ids is
+// set, for an array whose items have the properties of items.
+func arrayToPDKField(name string, items propper, ids bool) (idk.Field, error) {
+	quantum, _ := stringProp(items, "quantum")
+	ttl := ""
+	if quantum != "" {
+		ttl, _ = stringProp(items, "ttl")
+	}
+	cacheConfig, _ := cacheConfigProp(items)
 
-		case "signedIntBoolKey":
-			return idk.SignedIntBoolKeyField{
-				NameVal: aField.Name,
-			}, nil
+	if ft, _ := stringProp(items, "fieldType"); ft == "decimal" {
+		return nil, errors.New("arrays of decimal are not supported")
+	}
+	if ids {
+		return idk.IDArrayField{
+			NameVal:     name,
+			Quantum:     quantum,
+			TTL:         ttl,
+			CacheConfig: cacheConfig,
+		}, nil
+	}
+	return idk.StringArrayField{
+		NameVal:     name,
+		Quantum:     quantum,
+		TTL:         ttl,
+		CacheConfig: cacheConfig,
+	}, nil
+}
 
-		case "dateInt":
-			layout, err := stringProp(aField, "layout")
-			if err != nil {
-				return nil, errors.Errorf("required property for DateIntField: layout, err:%v", err)
-			}
+func intToPDKField(name string, p propper) (idk.Field, error) {
+	ft, _ := stringProp(p, "fieldType")
+	switch ft {
+	case "id":
+		fld := idk.IDField{NameVal: name}
 
-			strEpoch, err := stringProp(aField, "epoch")
-			if err != nil {
-				return nil, errors.Errorf("required property for DateIntField: epoch, err:%v", err)
-			}
-			epoch, err := time.Parse(layout, strEpoch)
-			if err != nil {
-				return nil, errors.Wrapf(err, "parsing epoch: %s", strEpoch)
+		if prop, ok := p.Prop("mutex"); ok {
+			if mtx, ok := prop.(bool); ok {
+				fld.Mutex = mtx
 			}
+		}
 
-			unit, err := stringProp(aField, "unit")
-			if err != nil {
-				return nil, errors.Errorf("required property for DateIntField: unit, err:%v", err)
+		if quantum, err := stringProp(p, "quantum"); err == nil {
+			fld.Quantum = quantum
+			if ttl, err := stringProp(p, "ttl"); err == nil {
+				fld.TTL = ttl
 			}
-			customUnit, _ := stringProp(aField, "customUnit")
-
-			return idk.DateIntField{
-				NameVal:    aField.Name,
-				Layout:     layout,
-				Epoch:      epoch,
-				Unit:       idk.Unit(unit),
-				CustomUnit: customUnit,
-			}, nil
 		}
-		return idk.IntField{
-			NameVal: aField.Name,
-		}, nil
 
-	case avro.Float, avro.Double:
-		// TODO should probably require a logicalType if we're going
-		// to treat a float as a decimal.
-		field := idk.DecimalField{
-			NameVal: aField.Name,
+		if cacheConfig, err := cacheConfigProp(p); err == nil {
+			fld.CacheConfig = cacheConfig
 		}
-		scale, err := intProp(aField, "scale")
-		if err == errWrongType {
-			return nil, errors.Wrap(err, "getting scale")
-		} else if err == nil {
-			field.Scale = scale
+
+		return fld, nil
+
+	case "int":
+		fld := idk.IntField{NameVal: name}
+
+		if min, err := intProp(p, "min"); err == nil {
+			fld.Min = intptr(min)
+		}
+		if max, err := intProp(p, "max"); err == nil {
+			fld.Max = intptr(max)
 		}
-		return field, nil
+		return fld, nil
 
-	case avro.Boolean:
-		return idk.BoolField{
-			NameVal: aField.Name,
+	case "signedIntBoolKey":
+		return idk.SignedIntBoolKeyField{
+			NameVal: name,
 		}, nil
 
-	case avro.Null:
-		return nil, errors.Errorf("null fields are not supported except inside Union")
+	case "dateInt":
+		return dateIntToPDKField(name, p)
+	}
+	return idk.IntField{
+		NameVal: name,
+	}, nil
+}
 
-	case avro.Map:
-		return nil, errors.Errorf("nested fields are not currently supported, so the field type cannot be map.")
+func dateIntToPDKField(name string, p propper) (idk.Field, error) {
+	layout, err := stringProp(p, "layout")
+	if err != nil {
+		return nil, errors.Errorf("required property for DateIntField: layout, err:%v", err)
+	}
 
-	case avro.Recursive:
-		return nil, errors.Errorf("recursive schema fields are not currently supported.")
+	strEpoch, err := stringProp(p, "epoch")
+	if err != nil {
+		return nil, errors.Errorf("required property for DateIntField: epoch, err:%v", err)
+	}
+	epoch, err := time.Parse(layout, strEpoch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing epoch: %s", strEpoch)
+	}
 
-	default:
-		return nil, errors.Errorf("unknown schema type: %+v", aField.Type)
+	unit, err := stringProp(p, "unit")
+	if err != nil {
+		return nil, errors.Errorf("required property for DateIntField: unit, err:%v", err)
+	}
+	customUnit, _ := stringProp(p, "customUnit")
+
+	return idk.DateIntField{
+		NameVal:    name,
+		Layout:     layout,
+		Epoch:      epoch,
+		Unit:       idk.Unit(unit),
+		CustomUnit: customUnit,
+	}, nil
+}
+
+func floatToPDKField(name string, p propper) (idk.Field, error) {
+	// TODO should probably require a logicalType if we're going
+	// to treat a float as a decimal.
+	field := idk.DecimalField{
+		NameVal: name,
 	}
+	scale, err := intProp(p, "scale")
+	if err == errWrongType {
+		return nil, errors.Wrap(err, "getting scale")
+	} else if err == nil {
+		field.Scale = scale
+	}
+	return field, nil
 }
 
 func stringProp(p propper, s string) (string, error) {
@@ -982,10 +1164,20 @@ func propertiesFromSchema(sch avro.Schema, origProps map[string]interface{}) map
 
 // The Schema type is an object produced by the schema registry.
 type Schema struct {
-	Schema  string `json:"schema"`  // The actual AVRO schema
-	Subject string `json:"subject"` // Subject where the schema is registered for
-	Version int    `json:"version"` // Version within this subject
-	ID      int    `json:"id"`      // Registry's unique id
+	Schema     string            `json:"schema"`     // The actual schema
+	SchemaType string            `json:"schemaType"` // AVRO (which the registry leaves out), PROTOBUF or JSON
+	References []SchemaReference `json:"references"` // Other schemas this one imports
+	Subject    string            `json:"subject"`    // Subject where the schema is registered for
+	Version    int               `json:"version"`    // Version within this subject
+	ID         int               `json:"id"`         // Registry's unique id
+}
+
+// SchemaReference is a reference from a schema to another schema
+// registered under a subject, by the name the first schema imports it as.
+type SchemaReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
 }
 
 func (s *Source) codecURL(id int32, urlPath string) (string, error) {
@@ -1000,43 +1192,56 @@ func (s *Source) codecURL(id int32, urlPath string) (string, error) {
 	return url.String(), nil
 }
 
-func (s *Source) getCodec(id int32) (avro.Schema, error) {
+// getDecoder returns the Avro codec for the schema with the given ID or,
+// if the schema is of another type, its registryDecoder.
+func (s *Source) getDecoder(id int32) (avro.Schema, registryDecoder, error) {
 	if codec, ok := s.cache[id]; ok {
-		return codec, nil
+		return codec, nil, nil
 	}
-	s.Log.Debugf("Source: new avro schema ID: %d", id)
-	// r, err := s.httpClient.Get(s.codecURL___OLD(id))
-	schemaUrl, err := s.codecURL(id, "schemas/ids/%d")
-	if err != nil {
-		return nil, errors.Wrap(err, "getting schema url")
+	if dec, ok := s.decoders[id]; ok {
+		return nil, dec, nil
 	}
-
-	//	schemaUrlResponse, err := s.httpClient.Get(schemaUrl)
-	req, err := http.NewRequest(http.MethodGet, schemaUrl, http.NoBody)
+	s.Log.Debugf("Source: new schema ID: %d", id)
+	schema, err := s.getSchema(id)
 	if err != nil {
-		return nil, errors.Wrap(err, "building request for getting schema from registry")
-	}
-	if s.SchemaRegistryUsername != "" {
-		req.SetBasicAuth(s.SchemaRegistryUsername, s.SchemaRegistryPassword)
+		return nil, nil, err
 	}
 
-	schemaUrlResponse, err := s.httpClient.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "getting schema from registry")
+	if s.CDC != "" && schema.SchemaType != "" && schema.SchemaType != "AVRO" {
+		return nil, nil, errors.Errorf("%s change events are not supported, only AVRO", schema.SchemaType)
 	}
-	defer schemaUrlResponse.Body.Close()
-	if schemaUrlResponse.StatusCode >= 300 {
-		bod, err := io.ReadAll(schemaUrlResponse.Body)
+
+	var dec registryDecoder
+	switch schema.SchemaType {
+	case "", "AVRO":
+		codec, err := avro.ParseSchema(schema.Schema)
 		if err != nil {
-			return nil, errors.Wrapf(err, "Failed to get schema, code: %d, no body", schemaUrlResponse.StatusCode)
+			return nil, nil, errors.Wrap(err, "parsing schema")
 		}
-		return nil, errors.Errorf("Failed to get schema, code: %d, resp: %s", schemaUrlResponse.StatusCode, bod)
+		s.Log.Debugf("Source: successfully got new avro schema %d: %s", id, codec.String())
+		s.cache[id] = codec
+		return codec, nil, nil
+	case "PROTOBUF":
+		dec, err = s.newProtobufDecoder(id)
+	case "JSON":
+		dec, err = newJSONSchemaDecoder(schema.Schema)
+	default:
+		return nil, nil, errors.Errorf("unsupported schema type: %s", schema.SchemaType)
 	}
-	dec := json.NewDecoder(schemaUrlResponse.Body)
-	schema := &Schema{}
-	err = dec.Decode(schema)
 	if err != nil {
-		return nil, errors.Wrap(err, "decoding schema from registry")
+		return nil, nil, errors.Wrapf(err, "parsing %s schema", schema.SchemaType)
+	}
+	s.Log.Debugf("Source: successfully got new %s schema %d: %s", schema.SchemaType, id, dec)
+	s.decoders[id] = dec
+	return nil, dec, nil
+}
+
+// getSchema gets the schema with the given ID from the registry, along with
+// its subject and version if the registry can tell us them.
+func (s *Source) getSchema(id int32) (*Schema, error) {
+	schema := &Schema{}
+	if err := s.registryGet(fmt.Sprintf("schemas/ids/%d", id), nil, schema); err != nil {
+		return nil, err
 	}
 
 	// get Subject and Version from url
@@ -1044,7 +1249,7 @@ func (s *Source) getCodec(id int32) (avro.Schema, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "getting schema subject & version url")
 	}
-	req, err = http.NewRequest(http.MethodGet, schemaSubVerUrl, http.NoBody)
+	req, err := http.NewRequest(http.MethodGet, schemaSubVerUrl, http.NoBody)
 	if err != nil {
 		return nil, errors.Wrap(err, "building request for getting schema from registry")
 	}
@@ -1086,15 +1291,45 @@ func (s *Source) getCodec(id int32) (avro.Schema, error) {
 	schema.ID = int(id)
 	// save schema object on s
 	s.schema = *schema
+	return schema, nil
+}
+
+// registryGet gets urlPath, relative to the registry URL, from the schema
+// registry and decodes the response into v.
+func (s *Source) registryGet(urlPath string, query url.Values, v interface{}) error {
+	u, err := url.Parse(s.SchemaRegistryURL)
+	if err != nil {
+		// this should be impossible since the Registry URL is created
+		// from URL.String() in Source.Open
+		return errors.Wrap(err, "parsing pre-validated registry url: "+s.SchemaRegistryURL)
+	}
+	u.Path = path.Join(u.Path, urlPath)
+	u.RawQuery = query.Encode()
 
-	codec, err := avro.ParseSchema(schema.Schema)
+	req, err := http.NewRequest(http.MethodGet, u.String(), http.NoBody)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing schema")
+		return errors.Wrap(err, "building request for getting schema from registry")
+	}
+	if s.SchemaRegistryUsername != "" {
+		req.SetBasicAuth(s.SchemaRegistryUsername, s.SchemaRegistryPassword)
 	}
-	s.Log.Debugf("Source: successfully got new avro schema %d: %s", id, codec.String())
 
-	s.cache[id] = codec
-	return codec, nil
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "getting schema from registry")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		bod, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to get schema, code: %d, no body", resp.StatusCode)
+		}
+		return errors.Errorf("Failed to get schema, code: %d, resp: %s", resp.StatusCode, bod)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return errors.Wrap(err, "decoding schema from registry")
+	}
+	return nil
 }
 
 func avroDecode(codec avro.Schema, data []byte) (map[string]interface{}, error) {
@@ -1165,3 +1165,31 @@ func TestGetKeyFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestSourceResume(t *testing.T) {
+	topic := "t"
+	partitions := []confluent.TopicPartition{
+		{Topic: &topic, Partition: 0, Offset: confluent.OffsetStored},
+		{Topic: &topic, Partition: 1, Offset: confluent.OffsetStored},
+	}
+	s := NewSource()
+	s.Checkpoint = func() (map[string]uint64, map[string]string, error) {
+		return map[string]uint64{"t:1": 42, "u:0": 7}, map[string]string{"t:1": "4,2,mysql-bin.000003"}, nil
+	}
+	if err := s.resume(partitions); err != nil {
+		t.Fatal(err)
+	}
+	if partitions[0].Offset != confluent.OffsetStored {
+		t.Errorf("expected partition 0 at the stored offset, got %v", partitions[0].Offset)
+	}
+	if partitions[1].Offset != 42 {
+		t.Errorf("expected partition 1 at offset 42, got %v", partitions[1].Offset)
+	}
+	// a change sent again from before the checkpointed position is stale
+	if exp := (debeziumPosition{file: "mysql-bin.000003", pos: 4, row: 2}); s.positions["t:1"] != exp {
+		t.Errorf("expected partition 1 at position %v, got %v", exp, s.positions["t:1"])
+	}
+	if !s.stale(partitions[1], &debeziumChange{position: debeziumPosition{file: "mysql-bin.000003", pos: 4, row: 1}, ordered: true}) {
+		t.Errorf("expected a change from before the checkpoint to be stale")
+	}
+}
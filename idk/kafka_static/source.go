@@ -85,7 +85,13 @@ func (s *Source) Record() (idk.Record, error) {
 
 	data, err := s.decodeMessage(msg.Value)
 	if err != nil {
-		return nil, errors.Wrap(err, "decoding with schema registry")
+		// The message is bad, but the ones after it needn't be.
+		return nil, &idk.BadRecordError{
+			Stream:  msg.Topic + ":" + strconv.Itoa(msg.Partition),
+			Offset:  uint64(msg.Offset),
+			Payload: msg.Value,
+			Err:     errors.Wrap(err, "decoding message"),
+		}
 	}
 
 	s.spool = append(s.spool, msg)
@@ -97,6 +103,7 @@ func (s *Source) Record() (idk.Record, error) {
 		offset:    msg.Offset,
 		idx:       s.spoolBase + uint64(len(s.spool)),
 		data:      data,
+		payload:   msg.Value,
 	}, err
 }
 
@@ -137,6 +144,7 @@ type Record struct {
 	offset    int64
 	idx       uint64
 	data      []interface{}
+	payload   []byte
 }
 
 func (r *Record) StreamOffset() (string, uint64) {
@@ -145,6 +153,10 @@ func (r *Record) StreamOffset() (string, uint64) {
 
 var _ idk.OffsetStreamRecord = &Record{}
 
+func (r *Record) Payload() []byte { return r.payload }
+
+var _ idk.PayloadRecord = &Record{}
+
 func (r *Record) Schema() interface{} { return nil }
 
 func (r *Record) Commit(ctx context.Context) error {
@@ -7,6 +7,11 @@ const (
 	MetricIngesterRowsAdded     = "ingester_rows_added_total"
 	MetricIngesterSchemaChanges = "ingester_schema_changes_total"
 	MetricCommittedRecords      = "committed_records"
+	MetricIngesterDeadLetters   = "ingester_dead_letters_total"
+	MetricIngesterChangeDeletes = "ingester_change_deletes_total"
+	MetricIngesterFiltered      = "ingester_filtered_total"
+
+	MetricIngesterSchemaEvolutions = "ingester_schema_evolutions_total"
 )
 
 var CounterIngesterSchemaChanges = prometheus.NewCounter(
@@ -33,6 +38,41 @@ var CounterCommittedRecords = prometheus.NewCounter(
 	},
 )
 
+var CounterIngesterDeadLetters = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "ingester",
+		Name:      MetricIngesterDeadLetters,
+		Help:      "Number of records written to the dead-letter sink.",
+	},
+)
+
+var CounterIngesterChangeDeletes = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "ingester",
+		Name:      MetricIngesterChangeDeletes,
+		Help:      "Number of records cleared to be replaced or removed by change records.",
+	},
+)
+
+var CounterIngesterFiltered = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "ingester",
+		Name:      MetricIngesterFiltered,
+		Help:      "Number of records dropped by the transform filter.",
+	},
+)
+
+var CounterIngesterSchemaEvolutions = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "ingester",
+		Name:      MetricIngesterSchemaEvolutions,
+		Help:      "Number of source fields added, removed, coerced, widened or versioned by schema evolution.",
+	},
+	[]string{
+		"change",
+	},
+)
+
 var CounterDeleterRowsAdded = prometheus.NewCounterVec(
 	prometheus.CounterOpts{
 		Namespace: "ingester",
@@ -49,4 +89,8 @@ func init() {
 	prometheus.MustRegister(CounterIngesterRowsAdded)
 	prometheus.MustRegister(CounterCommittedRecords)
 	prometheus.MustRegister(CounterDeleterRowsAdded)
+	prometheus.MustRegister(CounterIngesterDeadLetters)
+	prometheus.MustRegister(CounterIngesterChangeDeletes)
+	prometheus.MustRegister(CounterIngesterFiltered)
+	prometheus.MustRegister(CounterIngesterSchemaEvolutions)
 }
@@ -0,0 +1,310 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/idk/internal"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/pkg/errors"
+)
+
+// incremental holds the state of a Source in incremental mode. The row
+// expression is polled for the rows whose watermark column is beyond the
+// last value seen, in order of that column, and the last value which has
+// been committed is kept in an offsets file so that polling resumes from it.
+type incremental struct {
+	conn         *sql.DB
+	driver       string
+	expr         string
+	column       string // the name of the watermark column in the results
+	columnIdx    int
+	offsetsPath  string
+	pollInterval time.Duration
+
+	// seen is the watermark of the latest row delivered, and done the
+	// latest watermark of which every row has been delivered. Since rows
+	// arrive in order, that's the watermark before seen, or seen itself
+	// once a poll has returned every row.
+	seen      *Watermark
+	done      *Watermark
+	committed *Watermark
+
+	vals     []interface{}
+	lastPoll time.Time
+	lastRec  *incrementalRecord
+}
+
+// Watermark is the contents of the offsets file of an incremental SQL
+// source: the value of the watermark column up to which every row has been
+// ingested.
+type Watermark struct {
+	Column        string `json:"column"`
+	Type          string `json:"type"`
+	Value         string `json:"value"`
+	CommittedTime string `json:"committed_time,omitempty"`
+}
+
+// Watermark types.
+const (
+	watermarkInt       = "int"
+	watermarkFloat     = "float"
+	watermarkString    = "string"
+	watermarkTimestamp = "timestamp"
+)
+
+// newWatermark returns the watermark for a value of the watermark column,
+// or nil if it's null.
+func newWatermark(column string, v interface{}) (*Watermark, error) {
+	w := &Watermark{Column: column}
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case int64:
+		w.Type, w.Value = watermarkInt, strconv.FormatInt(v, 10)
+	case float64:
+		w.Type, w.Value = watermarkFloat, strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		w.Type, w.Value = watermarkString, v
+	case []byte:
+		w.Type, w.Value = watermarkString, string(v)
+	case time.Time:
+		w.Type, w.Value = watermarkTimestamp, v.Format(time.RFC3339Nano)
+	default:
+		return nil, errors.Errorf("unsupported type %T of watermark column %s", v, column)
+	}
+	return w, nil
+}
+
+// arg returns the watermark as a query argument.
+func (w *Watermark) arg() (interface{}, error) {
+	switch w.Type {
+	case watermarkInt:
+		return strconv.ParseInt(w.Value, 10, 64)
+	case watermarkFloat:
+		return strconv.ParseFloat(w.Value, 64)
+	case watermarkString:
+		return w.Value, nil
+	case watermarkTimestamp:
+		return time.Parse(time.RFC3339Nano, w.Value)
+	default:
+		return nil, errors.Errorf("unknown watermark type %q", w.Type)
+	}
+}
+
+func (w *Watermark) equal(o *Watermark) bool {
+	if w == nil || o == nil {
+		return w == o
+	}
+	return w.Type == o.Type && w.Value == o.Value
+}
+
+// NewIncrementalSource returns a Source which polls cmd every pollInterval
+// for rows with values of column beyond the watermark in the file at
+// offsetsPath. The column may be named as it is in the results of cmd, or
+// by the name of its field.
+func NewIncrementalSource(driver, connString, cmd, separator, column, offsetsPath string, pollInterval time.Duration, log logger.Logger) (*Source, error) {
+	if offsetsPath == "" {
+		return nil, errors.New("must provide an offsets path for incremental mode")
+	}
+	conn, err := sql.Open(driver, connString)
+	if err != nil {
+		return nil, err
+	}
+	inc := &incremental{
+		conn:         conn,
+		driver:       driver,
+		expr:         strings.TrimRight(strings.TrimSpace(cmd), ";"),
+		offsetsPath:  offsetsPath,
+		pollInterval: pollInterval,
+	}
+
+	// The columns of the results are needed to find the watermark column,
+	// so get them without fetching any rows.
+	rows, err := conn.Query(fmt.Sprintf("SELECT * FROM (%s) idk_incremental WHERE 1 = 0", inc.expr))
+	if err != nil {
+		return nil, errors.Wrap(err, ": error executing query")
+	}
+	cols, err := rows.Columns()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	schema, err := schemaFromColumns(cols, log)
+	if err != nil {
+		return nil, err
+	}
+	inc.columnIdx = -1
+	for i, c := range cols {
+		if c == column || schema[i].Name() == column {
+			inc.column, inc.columnIdx = c, i
+			break
+		}
+	}
+	if inc.columnIdx < 0 {
+		return nil, errors.Errorf("watermark column %s is not in the results of the row expression", column)
+	}
+
+	if err := inc.readOffsets(); err != nil {
+		return nil, err
+	}
+	if inc.committed != nil {
+		log.Printf("resuming from %s %s", inc.column, inc.committed.Value)
+	}
+	inc.vals = make([]interface{}, len(cols))
+	for i := range inc.vals {
+		inc.vals[i] = new(interface{})
+	}
+
+	return &Source{
+		schema:      schema,
+		separator:   separator,
+		log:         log,
+		incremental: inc,
+	}, nil
+}
+
+func (inc *incremental) readOffsets() error {
+	content, err := internal.ReadFileOrURL(inc.offsetsPath, nil)
+	if err == internal.ErrFileOrURLNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "reading offsets")
+	}
+	w := &Watermark{}
+	if err := json.Unmarshal(content, w); err != nil {
+		return errors.Wrap(err, "decoding offsets")
+	}
+	if w.Column != inc.column {
+		return errors.Errorf("offsets file %s is for watermark column %s, not %s", inc.offsetsPath, w.Column, inc.column)
+	}
+	if _, err := w.arg(); err != nil {
+		return errors.Wrap(err, "decoding watermark")
+	}
+	inc.seen, inc.done, inc.committed = w, w, w
+	return nil
+}
+
+// query returns the query for the rows after the watermark, if there is
+// one, and its arguments. Identifiers and placeholders are written the way
+// the driver expects.
+func (inc *incremental) query() (string, []interface{}, error) {
+	var column, placeholder string
+	switch inc.driver {
+	case "postgres", "pgx":
+		column, placeholder = `"`+strings.ReplaceAll(inc.column, `"`, `""`)+`"`, "$1"
+	case "mysql":
+		column, placeholder = "`"+strings.ReplaceAll(inc.column, "`", "``")+"`", "?"
+	case "sqlserver", "mssql":
+		column, placeholder = "["+strings.ReplaceAll(inc.column, "]", "]]")+"]", "@p1"
+	default:
+		column, placeholder = `"`+strings.ReplaceAll(inc.column, `"`, `""`)+`"`, "?"
+	}
+	if inc.seen == nil {
+		return fmt.Sprintf("SELECT * FROM (%s) idk_incremental WHERE %s IS NOT NULL ORDER BY %[2]s", inc.expr, column), nil, nil
+	}
+	arg, err := inc.seen.arg()
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("SELECT * FROM (%s) idk_incremental WHERE %s > %s ORDER BY %[2]s", inc.expr, column, placeholder), []interface{}{arg}, nil
+}
+
+// incrementalRecord returns the next row, polling for more once every row
+// of the last poll has been delivered. The end of each poll is reported
+// with ErrFlush, so that its rows are imported and committed before
+// waiting for the next.
+func (s *Source) incrementalRecord() (idk.Record, error) {
+	inc := s.incremental
+	if s.rows == nil {
+		if wait := inc.pollInterval - time.Since(inc.lastPoll); !inc.lastPoll.IsZero() && wait > 0 {
+			time.Sleep(wait)
+		}
+		query, args, err := inc.query()
+		if err != nil {
+			return nil, err
+		}
+		inc.lastPoll = time.Now()
+		s.rows, err = inc.conn.Query(query, args...)
+		if err != nil {
+			return nil, errors.Wrap(err, "polling for rows")
+		}
+	}
+
+	if !s.rows.Next() {
+		err := s.rows.Err()
+		s.rows.Close()
+		s.rows = nil
+		if err != nil {
+			return nil, errors.Wrap(err, "reading rows")
+		}
+		// every row with the last watermark has now been delivered
+		inc.done = inc.seen
+		if inc.lastRec != nil {
+			inc.lastRec.watermark = inc.done
+			inc.lastRec = nil
+		}
+		return nil, idk.ErrFlush
+	}
+	if err := s.rows.Scan(inc.vals...); err != nil {
+		return nil, errors.Wrap(err, "scanning row")
+	}
+	w, err := newWatermark(inc.column, *inc.vals[inc.columnIdx].(*interface{}))
+	if err != nil {
+		return nil, err
+	}
+	if w != nil && !w.equal(inc.seen) {
+		inc.done, inc.seen = inc.seen, w
+	}
+	inc.lastRec = &incrementalRecord{
+		src:       s,
+		data:      s.buildRow(inc.vals),
+		watermark: inc.done,
+	}
+	return inc.lastRec, nil
+}
+
+// commit writes the watermark to the offsets file, if it has moved.
+func (inc *incremental) commit(w *Watermark) error {
+	if w == nil || w.equal(inc.committed) {
+		return nil
+	}
+	committed := *w
+	committed.CommittedTime = time.Now().UTC().Format(time.RFC3339)
+	content, err := json.Marshal(&committed)
+	if err != nil {
+		return errors.Wrap(err, "encoding offsets")
+	}
+	if err := internal.WriteFileOrURL(inc.offsetsPath, content, nil); err != nil {
+		return errors.Wrap(err, "writing offsets")
+	}
+	inc.committed = w
+	return nil
+}
+
+// incrementalRecord is a row from an incremental Source. Committing it
+// commits the watermark up to which every row had been delivered by the
+// time it was, or, if it was the last row of a poll, its own.
+type incrementalRecord struct {
+	src       *Source
+	data      []interface{}
+	watermark *Watermark
+}
+
+func (r *incrementalRecord) Commit(ctx context.Context) error {
+	return r.src.incremental.commit(r.watermark)
+}
+
+func (r *incrementalRecord) Schema() interface{} {
+	return nil
+}
+
+func (r *incrementalRecord) Data() []interface{} {
+	return r.data
+}
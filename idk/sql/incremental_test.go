@@ -0,0 +1,86 @@
+package sql
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIncrementalQuery(t *testing.T) {
+	inc := &incremental{driver: "postgres", expr: "SELECT id AS id__ID, ts AS ts__Timestamp FROM t", column: "ts__Timestamp"}
+	query, args, err := inc.query()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := `SELECT * FROM (SELECT id AS id__ID, ts AS ts__Timestamp FROM t) idk_incremental WHERE "ts__Timestamp" IS NOT NULL ORDER BY "ts__Timestamp"`; query != exp || len(args) != 0 {
+		t.Errorf("unexpected first query %s %v", query, args)
+	}
+
+	ts := time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)
+	inc.seen, err = newWatermark(inc.column, ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for driver, exp := range map[string]string{
+		"postgres":  `SELECT * FROM (SELECT id AS id__ID, ts AS ts__Timestamp FROM t) idk_incremental WHERE "ts__Timestamp" > $1 ORDER BY "ts__Timestamp"`,
+		"mysql":     "SELECT * FROM (SELECT id AS id__ID, ts AS ts__Timestamp FROM t) idk_incremental WHERE `ts__Timestamp` > ? ORDER BY `ts__Timestamp`",
+		"sqlserver": `SELECT * FROM (SELECT id AS id__ID, ts AS ts__Timestamp FROM t) idk_incremental WHERE [ts__Timestamp] > @p1 ORDER BY [ts__Timestamp]`,
+	} {
+		inc.driver = driver
+		query, args, err := inc.query()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if query != exp || !reflect.DeepEqual(args, []interface{}{ts}) {
+			t.Errorf("unexpected %s query %s %v", driver, query, args)
+		}
+	}
+}
+
+func TestWatermarkOffsets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "offsets.json")
+	inc := &incremental{column: "id__ID", offsetsPath: path}
+	if err := inc.readOffsets(); err != nil || inc.seen != nil {
+		t.Fatalf("expected no watermark without an offsets file, got %v %v", inc.seen, err)
+	}
+
+	for _, v := range []interface{}{int64(12), 1.5, "abc", []byte("abd"), time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)} {
+		w, err := newWatermark(inc.column, v)
+		if err != nil {
+			t.Fatalf("making watermark of %v: %v", v, err)
+		}
+		if err := inc.commit(w); err != nil {
+			t.Fatalf("committing %v: %v", v, err)
+		}
+
+		resumed := &incremental{column: "id__ID", offsetsPath: path}
+		if err := resumed.readOffsets(); err != nil {
+			t.Fatalf("reading offsets: %v", err)
+		}
+		if !resumed.seen.equal(w) || !resumed.done.equal(w) {
+			t.Errorf("expected to resume from %+v, got %+v", w, resumed.seen)
+		}
+		arg, err := resumed.seen.arg()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b, ok := v.([]byte); ok {
+			v = string(b)
+		}
+		if !reflect.DeepEqual(arg, v) {
+			t.Errorf("expected argument %v, got %v", v, arg)
+		}
+	}
+
+	if w, err := newWatermark(inc.column, nil); w != nil || err != nil {
+		t.Errorf("expected no watermark for null, got %v %v", w, err)
+	}
+	if _, err := newWatermark(inc.column, true); err == nil {
+		t.Errorf("expected error for unsupported type")
+	}
+	other := &incremental{column: "ts__Timestamp", offsetsPath: path}
+	if err := other.readOffsets(); err == nil {
+		t.Errorf("expected error reading offsets for another column")
+	}
+}
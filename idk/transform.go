@@ -0,0 +1,494 @@
+package idk
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+	"github.com/pkg/errors"
+)
+
+// transformConfig describes how records are transformed between the source
+// and FeatureBase. It's read from a JSON file such as:
+//
+//	{
+//	  "split": [{"field": "tags", "separator": ";"}],
+//	  "filter": "@country = 'US' AND @amount > 10.00",
+//	  "fields": [
+//	    {"name": "full_name", "expr": "@first || ' ' || @last"},
+//	    {"name": "month", "expr": "DATE_TRUNC('m', @ts)"},
+//	    {"name": "tier", "expr": "CASE WHEN @amount > 100.00 THEN 'gold' ELSE 'basic' END"}
+//	  ],
+//	  "hash": {"fields": ["email"], "keyEnv": "EMAIL_HASH_KEY"}
+//	}
+//
+// The steps are applied in that order. Expressions are SQL expressions, in
+// which @name is the value of the field with that name.
+type transformConfig struct {
+	// Split turns string fields into string array fields.
+	Split []splitTransform `json:"split,omitempty"`
+
+	// Filter is a boolean expression; records for which it isn't true are
+	// dropped. Records deleted by change records are never dropped.
+	Filter string `json:"filter,omitempty"`
+
+	// Fields are derived from expressions over the fields before them.
+	Fields []derivedField `json:"fields,omitempty"`
+
+	// Hash replaces the values of fields with their hashes.
+	Hash *hashTransform `json:"hash,omitempty"`
+}
+
+type splitTransform struct {
+	Field     string `json:"field"`
+	Separator string `json:"separator"`
+}
+
+// derivedField is a field whose values are the results of an expression.
+// Its name may be a header spec such as "day__Timestamp_s"; otherwise its
+// type follows from the type of the expression. A derived field with the
+// name of an existing field replaces it.
+type derivedField struct {
+	Name string `json:"name"`
+	Expr string `json:"expr"`
+}
+
+// hashTransform replaces values with the hex-encoded SHA-256 of their text,
+// or its HMAC if there's a key, which is better for values which are easy
+// to guess. The key is given directly or in an environment variable.
+type hashTransform struct {
+	Fields []string `json:"fields"`
+	Key    string   `json:"key,omitempty"`
+	KeyEnv string   `json:"keyEnv,omitempty"`
+}
+
+// loadTransformConfig reads and checks a transform config file.
+func loadTransformConfig(name string) (*transformConfig, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading file")
+	}
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.DisallowUnknownFields()
+	cfg := &transformConfig{}
+	if err := dec.Decode(cfg); err != nil {
+		return nil, errors.Wrap(err, "decoding")
+	}
+
+	for _, s := range cfg.Split {
+		if s.Field == "" || s.Separator == "" {
+			return nil, errors.Errorf("split needs a field and a separator: %+v", s)
+		}
+	}
+	if cfg.Filter != "" {
+		if _, err := parser.ParseExprString(cfg.Filter); err != nil {
+			return nil, errors.Wrap(err, "parsing filter")
+		}
+	}
+	for _, f := range cfg.Fields {
+		if f.Name == "" {
+			return nil, errors.Errorf("derived field has no name: %+v", f)
+		}
+		if _, err := parser.ParseExprString(f.Expr); err != nil {
+			return nil, errors.Wrapf(err, "parsing expression for %s", f.Name)
+		}
+	}
+	if h := cfg.Hash; h != nil {
+		if h.Key != "" && h.KeyEnv != "" {
+			return nil, errors.New("hash can't have both a key and a keyEnv")
+		}
+		if h.KeyEnv != "" && os.Getenv(h.KeyEnv) == "" {
+			return nil, errors.Errorf("hash key environment variable %s isn't set", h.KeyEnv)
+		}
+	}
+	return cfg, nil
+}
+
+// transformer applies a transformConfig to records of a particular schema.
+// It isn't safe for concurrent use.
+type transformer struct {
+	// schema is the schema of the transformed records.
+	schema []Field
+
+	split   []splitStep
+	filter  *rowExpr
+	derived []derivedStep
+	hash    []hashStep
+	mac     hash.Hash
+}
+
+type splitStep struct {
+	idx int
+	sep string
+}
+
+type derivedStep struct {
+	idx   int
+	field Field
+	expr  *rowExpr
+}
+
+type hashStep struct {
+	idx   int
+	from  Field
+	array bool
+}
+
+// compile works out the transformed schema for a source schema, and
+// compiles the expressions to apply to its records.
+func (c *transformConfig) compile(ctx context.Context, schema []Field, log logger.Logger) (*transformer, error) {
+	t := &transformer{
+		schema: append([]Field(nil), schema...),
+	}
+	byName := make(map[string]int, len(schema))
+	for i, f := range schema {
+		if f.Name() != "" {
+			byName[f.Name()] = i
+		}
+	}
+
+	for _, s := range c.Split {
+		i, ok := byName[s.Field]
+		if !ok {
+			return nil, errors.Errorf("split field %s isn't in the schema", s.Field)
+		}
+		sf, ok := t.schema[i].(StringField)
+		if !ok {
+			return nil, errors.Errorf("split field %s is a %T, not a StringField", s.Field, t.schema[i])
+		}
+		t.schema[i] = StringArrayField{
+			NameVal:     sf.NameVal,
+			DestNameVal: sf.DestNameVal,
+			Quantum:     sf.Quantum,
+			TTL:         sf.TTL,
+			CacheConfig: sf.CacheConfig,
+		}
+		t.split = append(t.split, splitStep{idx: i, sep: s.Separator})
+	}
+
+	if c.Filter != "" {
+		expr, err := compileRowExpr(ctx, c.Filter, t.schema)
+		if err != nil {
+			return nil, errors.Wrap(err, "compiling filter")
+		}
+		if _, ok := expr.expr.Type().(*parser.DataTypeBool); !ok {
+			return nil, errors.Errorf("filter is a %s, not a bool", expr.expr.Type().TypeDescription())
+		}
+		t.filter = expr
+	}
+
+	for _, d := range c.Fields {
+		expr, err := compileRowExpr(ctx, d.Expr, t.schema)
+		if err != nil {
+			return nil, errors.Wrapf(err, "compiling expression for %s", d.Name)
+		}
+		var field Field
+		if strings.Contains(d.Name, "__") {
+			field, err = HeaderToField(d.Name, log)
+		} else {
+			field, err = fieldForType(d.Name, expr.expr.Type())
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting derived field %s", d.Name)
+		}
+		i, ok := byName[field.Name()]
+		if ok {
+			t.schema[i] = field
+		} else {
+			i = len(t.schema)
+			byName[field.Name()] = i
+			t.schema = append(t.schema, field)
+		}
+		t.derived = append(t.derived, derivedStep{idx: i, field: field, expr: expr})
+	}
+
+	if h := c.Hash; h != nil {
+		for _, name := range h.Fields {
+			i, ok := byName[name]
+			if !ok {
+				return nil, errors.Errorf("hash field %s isn't in the schema", name)
+			}
+			step := hashStep{idx: i, from: t.schema[i]}
+			switch f := t.schema[i].(type) {
+			case StringField:
+			case StringArrayField:
+				step.array = true
+			case IDArrayField:
+				t.schema[i] = StringArrayField{NameVal: f.NameVal, DestNameVal: f.DestNameVal, Quantum: f.Quantum, TTL: f.TTL, CacheConfig: f.CacheConfig}
+				step.array = true
+			default:
+				t.schema[i] = StringField{NameVal: f.Name(), DestNameVal: f.DestName()}
+			}
+			t.hash = append(t.hash, step)
+		}
+		key := h.Key
+		if h.KeyEnv != "" {
+			key = os.Getenv(h.KeyEnv)
+		}
+		if key != "" {
+			t.mac = hmac.New(sha256.New, []byte(key))
+		} else {
+			t.mac = sha256.New()
+		}
+	}
+	return t, nil
+}
+
+// apply transforms the values of a record, returning them in the order of
+// the transformed schema. It returns false if the record is filtered out.
+// The record's own values are left alone.
+func (t *transformer) apply(data []interface{}, deleted bool) ([]interface{}, bool, error) {
+	// The source schema may have fields without values at the end.
+	row := make([]interface{}, len(t.schema))
+	copy(row, data)
+
+	for _, s := range t.split {
+		if row[s.idx] == nil {
+			continue
+		}
+		v, err := toString(row[s.idx])
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "splitting %s", t.schema[s.idx].Name())
+		}
+		var parts []string
+		for _, part := range strings.Split(v, s.sep) {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) > 0 {
+			row[s.idx] = parts
+		} else {
+			row[s.idx] = nil
+		}
+	}
+
+	if t.filter != nil && !deleted {
+		v, err := t.filter.evaluate(row)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "evaluating filter")
+		}
+		// like a WHERE clause, a null result drops the record
+		if keep, _ := v.(bool); !keep {
+			return nil, false, nil
+		}
+	}
+
+	for _, d := range t.derived {
+		v, err := d.expr.evaluate(row)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "evaluating %s", d.field.Name())
+		}
+		// the sql3 representation of an idset isn't one IDArrayField takes
+		if ids, ok := v.([]int64); ok {
+			uids := make([]uint64, len(ids))
+			for i, id := range ids {
+				uids[i] = uint64(id)
+			}
+			v = uids
+		}
+		row[d.idx] = v
+	}
+
+	for _, h := range t.hash {
+		if row[h.idx] == nil {
+			continue
+		}
+		if !h.array {
+			v, err := hashText(h.from, row[h.idx])
+			if err != nil {
+				return nil, false, errors.Wrapf(err, "hashing %s", t.schema[h.idx].Name())
+			}
+			row[h.idx] = t.sum(v)
+			continue
+		}
+		v, err := h.from.PilosafyVal(row[h.idx])
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "hashing %s", t.schema[h.idx].Name())
+		}
+		vs, err := toStringArray(v)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "hashing %s", t.schema[h.idx].Name())
+		}
+		hashed := make([]string, len(vs))
+		for i, v := range vs {
+			hashed[i] = t.sum(v)
+		}
+		row[h.idx] = hashed
+	}
+	return row, true, nil
+}
+
+// sum returns the hex-encoded hash of a value.
+func (t *transformer) sum(v string) string {
+	t.mac.Reset()
+	t.mac.Write([]byte(v))
+	return hex.EncodeToString(t.mac.Sum(nil))
+}
+
+// hashText returns the text of a value of a field to hash. Values are
+// converted as they are for expressions first, so the same value has the
+// same hash however the source represents it.
+func hashText(f Field, val interface{}) (string, error) {
+	if sqlType(f) == nil {
+		return toString(val)
+	}
+	v, err := sqlValue(f, val)
+	if err != nil {
+		return "", err
+	}
+	if ts, ok := v.(time.Time); ok {
+		return ts.UTC().Format(time.RFC3339Nano), nil
+	}
+	return toString(v)
+}
+
+// rowExpr is an expression over the fields of a record.
+type rowExpr struct {
+	expr types.PlanExpression
+
+	// The expression refers to the fields at the indexes in fields, which
+	// are its columns at the indexes in columns. refs holds those fields as
+	// they were when it was compiled, since a later step may replace them,
+	// and vars holds the values of the columns when it's evaluated.
+	fields  []int
+	columns []int
+	refs    []Field
+	vars    []interface{}
+}
+
+// compileRowExpr compiles an expression over records of a schema. Fields
+// of types which have no equivalent in SQL can't be referred to.
+func compileRowExpr(ctx context.Context, sql string, schema []Field) (*rowExpr, error) {
+	columns := make([]planner.RowColumn, 0, len(schema))
+	fieldIdx := make([]int, 0, len(schema))
+	for i, f := range schema {
+		typ := sqlType(f)
+		if f.Name() == "" || typ == nil {
+			continue
+		}
+		columns = append(columns, planner.RowColumn{Name: f.Name(), Type: typ})
+		fieldIdx = append(fieldIdx, i)
+	}
+	expr, refs, err := planner.CompileRowExpression(ctx, sql, columns)
+	if err != nil {
+		return nil, err
+	}
+	r := &rowExpr{
+		expr:    expr,
+		fields:  make([]int, len(refs)),
+		columns: refs,
+		refs:    make([]Field, len(refs)),
+		vars:    make([]interface{}, len(columns)),
+	}
+	for i, ref := range refs {
+		r.fields[i] = fieldIdx[ref]
+		r.refs[i] = schema[fieldIdx[ref]]
+	}
+	return r, nil
+}
+
+// evaluate evaluates the expression for a record. Only the fields it
+// refers to are converted.
+func (r *rowExpr) evaluate(row []interface{}) (interface{}, error) {
+	for i, idx := range r.fields {
+		v, err := sqlValue(r.refs[i], row[idx])
+		if err != nil {
+			return nil, errors.Wrapf(err, "converting %s", r.refs[i].Name())
+		}
+		r.vars[r.columns[i]] = v
+	}
+	return r.expr.Evaluate(r.vars)
+}
+
+// sqlType returns the SQL type of a field's values, or nil if there isn't
+// one.
+func sqlType(f Field) parser.ExprDataType {
+	switch f := f.(type) {
+	case IDField:
+		return parser.NewDataTypeID()
+	case BoolField:
+		return parser.NewDataTypeBool()
+	case StringField, LookupTextField:
+		return parser.NewDataTypeString()
+	case IntField:
+		if f.ForeignIndex != "" {
+			return nil
+		}
+		return parser.NewDataTypeInt()
+	case DecimalField:
+		return parser.NewDataTypeDecimal(f.Scale)
+	case FloatField:
+		return parser.NewDataTypeDouble()
+	case StringArrayField:
+		return parser.NewDataTypeStringSet()
+	case IDArrayField:
+		return parser.NewDataTypeIDSet()
+	case TimestampField:
+		return parser.NewDataTypeTimestamp()
+	}
+	return nil
+}
+
+// sqlValue converts a value of a field, as it comes from a source, to the
+// representation of its SQL type. The value is first converted as it would
+// be to ingest it, so it's interpreted the same way.
+func sqlValue(f Field, val interface{}) (interface{}, error) {
+	v, err := f.PilosafyVal(val)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	switch f := f.(type) {
+	case IDField:
+		return int64(v.(uint64)), nil
+	case DecimalField:
+		return pql.NewDecimal(v.(int64), f.Scale), nil
+	case IDArrayField:
+		ids := v.([]uint64)
+		vs := make([]int64, len(ids))
+		for i, id := range ids {
+			vs[i] = int64(id)
+		}
+		return vs, nil
+	case TimestampField:
+		return ValToTimestamp(string(f.granularity()), TimestampToVal(f.granularity(), f.epoch())+v.(int64))
+	}
+	return v, nil
+}
+
+// fieldForType returns a field for the values of a SQL type.
+func fieldForType(name string, typ parser.ExprDataType) (Field, error) {
+	switch typ := typ.(type) {
+	case *parser.DataTypeID:
+		return IDField{NameVal: name}, nil
+	case *parser.DataTypeBool:
+		return BoolField{NameVal: name}, nil
+	case *parser.DataTypeString:
+		return StringField{NameVal: name}, nil
+	case *parser.DataTypeInt:
+		return IntField{NameVal: name}, nil
+	case *parser.DataTypeDecimal:
+		return DecimalField{NameVal: name, Scale: typ.Scale}, nil
+	case *parser.DataTypeDouble:
+		return FloatField{NameVal: name}, nil
+	case *parser.DataTypeStringSet:
+		return StringArrayField{NameVal: name}, nil
+	case *parser.DataTypeIDSet:
+		return IDArrayField{NameVal: name}, nil
+	case *parser.DataTypeTimestamp:
+		return TimestampField{NameVal: name}, nil
+	}
+	return nil, errors.Errorf("expressions of type %s can't be ingested; give the field's type in its name, e.g. %s__String", typ.TypeDescription(), name)
+}
@@ -0,0 +1,197 @@
+package idk
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/logger"
+)
+
+func writeTransformConfig(t *testing.T, content string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "transforms.json")
+	if err := os.WriteFile(name, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func hexSHA256(key, v string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(v))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestTransform(t *testing.T) {
+	cfg, err := loadTransformConfig(writeTransformConfig(t, `{
+		"split": [{"field": "tags", "separator": ";"}],
+		"filter": "@amount > 10.00 AND SETCONTAINS(@tags, 'test') = false",
+		"fields": [
+			{"name": "full_name", "expr": "@first || ' ' || @last"},
+			{"name": "initial", "expr": "SUBSTRING(@first, 0, 1)"},
+			{"name": "day", "expr": "DATE_TRUNC('d', @ts)"},
+			{"name": "tier", "expr": "CASE WHEN @amount >= 100.00 THEN 'gold' ELSE 'basic' END"},
+			{"name": "year__Int", "expr": "DATETIMEPART('yy', @ts)"},
+			{"name": "first", "expr": "UPPER(@first)"}
+		],
+		"hash": {"fields": ["email", "amount"], "key": "k"}
+	}`))
+	if err != nil {
+		t.Fatalf("loading transforms: %v", err)
+	}
+
+	schema := []Field{
+		IDField{NameVal: "id"},
+		StringField{NameVal: "first"},
+		StringField{NameVal: "last"},
+		StringField{NameVal: "email", Mutex: true},
+		DecimalField{NameVal: "amount", Scale: 2},
+		StringField{NameVal: "tags"},
+		TimestampField{NameVal: "ts", Granularity: "ms"},
+		IgnoreField{},
+	}
+	tr, err := cfg.compile(context.Background(), schema, logger.NopLogger)
+	if err != nil {
+		t.Fatalf("compiling transforms: %v", err)
+	}
+	exp := []Field{
+		IDField{NameVal: "id"},
+		StringField{NameVal: "first"},
+		StringField{NameVal: "last"},
+		StringField{NameVal: "email", Mutex: true},
+		StringField{NameVal: "amount", DestNameVal: "amount"},
+		StringArrayField{NameVal: "tags"},
+		TimestampField{NameVal: "ts", Granularity: "ms"},
+		IgnoreField{},
+		StringField{NameVal: "full_name"},
+		StringField{NameVal: "initial"},
+		StringField{NameVal: "day"},
+		StringField{NameVal: "tier"},
+		IntField{NameVal: "year", DestNameVal: "year"},
+	}
+	if !reflect.DeepEqual(tr.schema, exp) {
+		t.Errorf("unexpected schema:\n%#v", tr.schema)
+	}
+
+	ts := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+	data := []interface{}{uint64(1), "ada", "lovelace", "ada@example.com", "123.45", "a; b;", ts, "x"}
+	row, keep, err := tr.apply(data, false)
+	if err != nil || !keep {
+		t.Fatalf("applying transforms: %v, %v", keep, err)
+	}
+	expRow := []interface{}{
+		uint64(1),
+		"ADA",
+		"lovelace",
+		hexSHA256("k", "ada@example.com"),
+		hexSHA256("k", "123.45"),
+		[]string{"a", "b"},
+		ts,
+		"x",
+		"ada lovelace",
+		"a",
+		"2022-03-04",
+		"gold",
+		int64(2022),
+	}
+	if !reflect.DeepEqual(row, expRow) {
+		t.Errorf("unexpected row:\n%#v", row)
+	}
+	if data[1] != "ada" || data[5] != "a; b;" {
+		t.Errorf("record's own values were changed: %v", data)
+	}
+
+	// filtered out by each half of the filter, or a null amount
+	for _, data := range [][]interface{}{
+		{uint64(2), "b", "c", nil, "9.99", nil, nil, nil},
+		{uint64(3), "b", "c", nil, "50", "x;test", nil, nil},
+		{uint64(4), "b", "c", nil, nil, nil, nil, nil},
+	} {
+		if _, keep, err := tr.apply(data, false); err != nil || keep {
+			t.Errorf("expected %v to be filtered out: %v, %v", data, keep, err)
+		}
+	}
+	// but deletes are kept
+	if row, keep, err := tr.apply([]interface{}{uint64(4), "b"}, true); err != nil || !keep {
+		t.Errorf("expected delete to be kept: %v, %v", keep, err)
+	} else if row[1] != "B" || row[8] != nil || row[11] != "basic" {
+		t.Errorf("unexpected delete row %#v", row)
+	}
+
+	if _, _, err := tr.apply([]interface{}{"notanid", "b", "c", nil, "20"}, false); err != nil {
+		t.Errorf("unreferenced fields shouldn't be converted: %v", err)
+	}
+	if _, _, err := tr.apply([]interface{}{uint64(5), "b", "c", nil, "abc"}, false); err == nil {
+		t.Errorf("expected error converting bad decimal")
+	}
+}
+
+func TestTransformErrors(t *testing.T) {
+	for _, content := range []string{
+		`{"filter": "@a ="}`,
+		`{"unknown": 1}`,
+		`{"split": [{"field": "a"}]}`,
+		`{"fields": [{"expr": "1"}]}`,
+		`{"hash": {"fields": ["a"], "key": "k", "keyEnv": "K"}}`,
+	} {
+		if _, err := loadTransformConfig(writeTransformConfig(t, content)); err == nil {
+			t.Errorf("expected error loading %s", content)
+		}
+	}
+
+	schema := []Field{StringField{NameVal: "a"}, IntField{NameVal: "b"}}
+	for _, content := range []string{
+		`{"filter": "@b + 1"}`,
+		`{"filter": "@c = 1"}`,
+		`{"split": [{"field": "b", "separator": ","}]}`,
+		`{"fields": [{"name": "c", "expr": "@a + 1"}]}`,
+		`{"hash": {"fields": ["c"]}}`,
+	} {
+		cfg, err := loadTransformConfig(writeTransformConfig(t, content))
+		if err != nil {
+			t.Errorf("loading %s: %v", content, err)
+			continue
+		}
+		if _, err := cfg.compile(context.Background(), schema, logger.NopLogger); err == nil {
+			t.Errorf("expected error compiling %s", content)
+		}
+	}
+}
+
+func TestTransformSets(t *testing.T) {
+	cfg, err := loadTransformConfig(writeTransformConfig(t, `{
+		"fields": [{"name": "all", "expr": "@ids"}, {"name": "f", "expr": "@score"}],
+		"hash": {"fields": ["ids"]}
+	}`))
+	if err != nil {
+		t.Fatalf("loading transforms: %v", err)
+	}
+	schema := []Field{IDArrayField{NameVal: "ids"}, FloatField{NameVal: "score"}}
+	tr, err := cfg.compile(context.Background(), schema, logger.NopLogger)
+	if err != nil {
+		t.Fatalf("compiling transforms: %v", err)
+	}
+	exp := []Field{StringArrayField{NameVal: "ids"}, FloatField{NameVal: "score"}, IDArrayField{NameVal: "all"}, FloatField{NameVal: "f"}}
+	if !reflect.DeepEqual(tr.schema, exp) {
+		t.Errorf("unexpected schema:\n%#v", tr.schema)
+	}
+	row, _, err := tr.apply([]interface{}{[]interface{}{int64(3), int64(4)}, 1.5}, false)
+	if err != nil {
+		t.Fatalf("applying transforms: %v", err)
+	}
+	sum := func(v string) string {
+		h := sha256.Sum256([]byte(v))
+		return hex.EncodeToString(h[:])
+	}
+	expRow := []interface{}{[]string{sum("3"), sum("4")}, 1.5, []uint64{3, 4}, 1.5}
+	if !reflect.DeepEqual(row, expRow) {
+		t.Errorf("unexpected row:\n%#v", row)
+	}
+}
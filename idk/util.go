@@ -1,7 +1,10 @@
 package idk
 
 import (
+	"encoding/json"
+
 	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/pkg/errors"
 )
 
 func scaledStringToInt(scale int64, num string) (int64, error) {
@@ -11,3 +14,58 @@ func scaledStringToInt(scale int64, num string) (int64, error) {
 	}
 	return d.ToInt64(scale), nil
 }
+
+// InferJSONField infers the field for a JSON value: strings, booleans,
+// integers and other numbers, and arrays of strings or integers, which
+// become sets. It returns nil for a value whose type can't be inferred.
+func InferJSONField(name string, v interface{}) (Field, error) {
+	switch v := v.(type) {
+	case string:
+		return StringField{NameVal: name}, nil
+	case bool:
+		return BoolField{NameVal: name}, nil
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return IntField{NameVal: name}, nil
+		}
+		return FloatField{NameVal: name}, nil
+	case []interface{}:
+		if len(v) == 0 {
+			return nil, nil
+		}
+		switch elem := v[0].(type) {
+		case string:
+			return StringArrayField{NameVal: name}, nil
+		case json.Number:
+			if _, err := elem.Int64(); err == nil {
+				return IDArrayField{NameVal: name}, nil
+			}
+		}
+		return nil, errors.Errorf("unsupported array of %v", v[0])
+	}
+	return nil, nil
+}
+
+// JSONValue converts the numbers in a JSON value for a field. Numbers for
+// decimal fields are kept as strings so that they aren't rounded through a
+// float.
+func JSONValue(field Field, v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if _, ok := field.(DecimalField); ok {
+			return string(v)
+		}
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		vals := make([]interface{}, len(v))
+		for i := range v {
+			vals[i] = JSONValue(field, v[i])
+		}
+		return vals
+	}
+	return v
+}
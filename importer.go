@@ -22,8 +22,32 @@ type Importer interface {
 	DoImport(ctx context.Context, tid dax.TableID, fld *dax.Field, shard uint64, path string, data []byte) error
 }
 
+// KeyFinder is implemented by importers which can look up keys without
+// creating those which don't exist.
+type KeyFinder interface {
+	FindTableKeys(ctx context.Context, tid dax.TableID, keys ...string) (map[string]uint64, error)
+	FindFieldKeys(ctx context.Context, tid dax.TableID, fname dax.FieldName, keys ...string) (map[string]uint64, error)
+}
+
+// ShardImport is an import into a shard of a table, one of a group applied
+// by a ShardTransactionApplier.
+type ShardImport struct {
+	TableID dax.TableID
+	Shard   uint64
+	Request *ImportRoaringShardRequest
+}
+
+// ShardTransactionApplier is implemented by importers which can apply a
+// group of shard imports, into any number of tables, all or nothing. It's
+// used to commit the writes of a sql transaction.
+type ShardTransactionApplier interface {
+	ApplyShardTransaction(ctx context.Context, imports []ShardImport) error
+}
+
 // Ensure type implements interface.
 var _ Importer = &onPremImporter{}
+var _ KeyFinder = &onPremImporter{}
+var _ ShardTransactionApplier = &onPremImporter{}
 
 // onPremImporter is a wrapper around API which implements the Importer
 // interface. This is currently only used by sql3 running locally in standard
@@ -59,6 +83,14 @@ func (i *onPremImporter) CreateFieldKeys(ctx context.Context, tid dax.TableID, f
 	return i.api.CreateFieldKeys(ctx, string(tid), string(fname), keys...)
 }
 
+func (i *onPremImporter) FindTableKeys(ctx context.Context, tid dax.TableID, keys ...string) (map[string]uint64, error) {
+	return i.api.FindIndexKeys(ctx, string(tid), keys...)
+}
+
+func (i *onPremImporter) FindFieldKeys(ctx context.Context, tid dax.TableID, fname dax.FieldName, keys ...string) (map[string]uint64, error) {
+	return i.api.FindFieldKeys(ctx, string(tid), string(fname), keys...)
+}
+
 func (i *onPremImporter) ImportRoaringBitmap(ctx context.Context, tid dax.TableID, fld *dax.Field, shard uint64, views map[string]*roaring.Bitmap, clear bool) error {
 	// This intentionally no-ops. See comment on struct.
 	return nil
@@ -3,6 +3,7 @@
 package pilosa
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math/big"
@@ -17,6 +18,7 @@ import (
 	"github.com/featurebasedb/featurebase/v3/disco"
 	"github.com/featurebasedb/featurebase/v3/pql"
 	"github.com/featurebasedb/featurebase/v3/roaring"
+	txkey "github.com/featurebasedb/featurebase/v3/short_txkey"
 	"github.com/featurebasedb/featurebase/v3/testhook"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -30,6 +32,15 @@ type Index struct {
 	owner       string
 	description string
 
+	// Record retention. Records whose retentionField is older than
+	// retentionPeriod are deleted by the server's retention job.
+	retentionField  string
+	retentionPeriod time.Duration
+
+	// primaryKey names the fields whose values make up the composite key of
+	// each record.
+	primaryKey []string
+
 	path          string
 	name          string
 	qualifiedName string
@@ -62,6 +73,11 @@ type Index struct {
 
 	// indicate that we're closing and should wrap up and not allow new actions
 	closing chan struct{}
+
+	// writeBlocks holds the fields which can't be written to while their
+	// types are altered, with the time each block expires.
+	writeBlockMu sync.Mutex
+	writeBlocks  map[string]time.Time
 }
 
 // NewIndex returns an existing (but possibly empty) instance of
@@ -77,9 +93,10 @@ func NewIndex(holder *Holder, path, name string) (*Index, error) {
 	}
 
 	idx := &Index{
-		path:   path,
-		name:   name,
-		fields: make(map[string]*Field),
+		path:        path,
+		name:        name,
+		fields:      make(map[string]*Field),
+		writeBlocks: make(map[string]time.Time),
 
 		broadcaster:    NopBroadcaster,
 		holder:         holder,
@@ -155,9 +172,12 @@ func (i *Index) Options() IndexOptions {
 
 func (i *Index) options() IndexOptions {
 	return IndexOptions{
-		Description:    i.description,
-		Keys:           i.keys,
-		TrackExistence: i.trackExistence,
+		Description:     i.description,
+		Keys:            i.keys,
+		TrackExistence:  i.trackExistence,
+		RetentionField:  i.retentionField,
+		RetentionPeriod: i.retentionPeriod,
+		PrimaryKey:      i.primaryKey,
 	}
 }
 
@@ -416,7 +436,7 @@ func (i *Index) openExistenceField() error {
 func (i *Index) setFieldBitDepths() error {
 	for name, f := range i.fields {
 		switch f.Type() {
-		case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp:
+		case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp, FieldTypeFloat:
 			// pass
 		default:
 			continue
@@ -734,7 +754,7 @@ func (i *Index) CreateFieldIfNotExistsWithOptions(name string, requestUserID str
 	if f := i.fields[name]; f != nil {
 		return f, nil
 	}
-	if opt != nil && (opt.Type == FieldTypeInt || opt.Type == FieldTypeTimestamp) {
+	if opt != nil && (opt.Type == FieldTypeInt || opt.Type == FieldTypeTimestamp || opt.Type == FieldTypeFloat) {
 		min, max := pql.MinMax(0)
 		// ensure the provided bounds are valid
 		zero := big.NewInt(0)
@@ -848,8 +868,8 @@ func (i *Index) UpdateField(ctx context.Context, name string, requestUserID stri
 	// Handle the options we know how to update, or error.
 	switch update.Option {
 	case "TTL", "ttl":
-		if cfm.Meta.Type != FieldTypeTime {
-			return nil, NewBadRequestError(errors.Errorf("can only add TTL to a 'time' type field, not '%s'", cfm.Meta.Type))
+		if cfm.Meta.TimeQuantum == "" {
+			return nil, NewBadRequestError(errors.Errorf("can only add TTL to a field with a time quantum, not '%s'", cfm.Meta.Type))
 		}
 		dur, err := time.ParseDuration(update.Value)
 		if err != nil {
@@ -1016,6 +1036,145 @@ func (i *Index) DeleteField(name string) error {
 	return i.translationSyncer.Reset()
 }
 
+// RenameField renames a field in the index. Only the field's name changes,
+// in etcd and in the keys of its bitmaps; its data is not rewritten.
+func (i *Index) RenameField(ctx context.Context, name, newName string) error {
+	if name == existenceFieldName {
+		return newNotFoundError(ErrFieldNotFound, existenceFieldName)
+	} else if err := ValidateName(newName); err != nil {
+		return errors.Wrap(err, "validating name")
+	}
+
+	f := i.Field(name)
+	if f == nil {
+		return newNotFoundError(ErrFieldNotFound, name)
+	} else if i.Field(newName) != nil {
+		return newConflictError(ErrFieldExists)
+	}
+
+	// Get the field from etcd, and store it again under its new name.
+	buf, err := i.holder.Schemator.Field(ctx, i.name, name)
+	if err != nil {
+		return errors.Wrapf(err, "getting field '%s' from etcd", name)
+	}
+	cfm, err := decodeCreateFieldMessage(i.holder.serializer, buf)
+	if err != nil {
+		return errors.Wrap(err, "decoding CreateFieldMessage")
+	} else if cfm == nil {
+		return errors.New("got nil CreateFieldMessage when decoding")
+	}
+	cfm.Field = newName
+	if err := i.persistField(ctx, cfm); err != nil {
+		return errors.Wrap(err, "persisting field")
+	}
+	for _, v := range f.views() {
+		if err := i.holder.Schemator.CreateView(ctx, i.name, newName, renamedView(v.name, name, newName)); err != nil {
+			return errors.Wrapf(err, "writing view to etcd: %s/%s", newName, v.name)
+		}
+	}
+	if err := i.holder.Schemator.DeleteField(ctx, i.name, name); err != nil {
+		return errors.Wrapf(err, "deleting field from etcd: %s/%s", i.name, name)
+	}
+
+	return i.RenameFieldLocal(name, newName)
+}
+
+// RenameFieldLocal renames a field in the local in-memory and on-disk
+// structures. It is called on every node once the new name has been
+// persisted.
+func (i *Index) RenameFieldLocal(name, newName string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	f := i.field(name)
+	if f == nil {
+		// The rename has already been applied on this node.
+		if i.field(newName) != nil {
+			return nil
+		}
+		return newNotFoundError(ErrFieldNotFound, name)
+	}
+
+	opt := f.Options()
+	cfm := &CreateFieldMessage{
+		Index:     i.name,
+		Field:     newName,
+		CreatedAt: f.CreatedAt(),
+		Owner:     f.owner,
+		Meta:      &opt,
+	}
+	shards := f.AvailableShards(false)
+
+	// The views of the renamed field are opened from the shards their
+	// fragments hold now, since views created since the index was opened
+	// aren't in fieldView2shard.
+	viewShards := make(map[string]*shardSet)
+	for _, v := range f.views() {
+		ss := newShardSet()
+		for _, frag := range v.allFragments() {
+			ss.add(frag.shard)
+		}
+		viewShards[renamedView(v.name, name, newName)] = ss
+	}
+
+	// A field's keys don't necessarily live in its directory (an in-memory
+	// translate store's don't), so they're carried over explicitly.
+	var keys *bytes.Buffer
+	if opt.Keys && f.translateStore != nil {
+		tx, err := f.translateStore.Begin(false)
+		if err != nil {
+			return errors.Wrap(err, "beginning translate transaction")
+		}
+		keys = &bytes.Buffer{}
+		_, err = tx.WriteTo(keys)
+		tx.Rollback()
+		if err != nil {
+			return errors.Wrap(err, "reading keys")
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "closing")
+	}
+
+	if err := i.holder.txf.RenameFieldInStore(i.name, name, newName); err != nil {
+		return errors.Wrap(err, "Txf.RenameFieldInStore")
+	}
+
+	// Move the field's directory, and the directories of any views which
+	// carry its name.
+	if err := os.Rename(i.fieldPath(name), i.fieldPath(newName)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "renaming field directory")
+	}
+	viewsPath := filepath.Join(i.fieldPath(newName), "views")
+	if entries, err := os.ReadDir(viewsPath); err == nil {
+		for _, e := range entries {
+			if vname := renamedView(e.Name(), name, newName); vname != e.Name() {
+				if err := os.Rename(filepath.Join(viewsPath, e.Name()), filepath.Join(viewsPath, vname)); err != nil {
+					return errors.Wrap(err, "renaming view directory")
+				}
+			}
+		}
+	}
+
+	delete(i.fields, name)
+	i.fieldView2shard.removeField(name)
+	for view, ss := range viewShards {
+		i.fieldView2shard.addViewShardSet(txkey.FieldView{Field: newName, View: view}, ss)
+	}
+
+	nf, err := i.createField(cfm)
+	if err != nil {
+		return errors.Wrap(err, "creating renamed field")
+	}
+	if keys != nil {
+		if _, err := nf.TranslateStore().ReadFrom(keys); err != nil {
+			return errors.Wrap(err, "restoring keys")
+		}
+	}
+	return nf.AddRemoteAvailableShards(shards)
+}
+
 // SetTranslatePartitions sets the cached value: translatePartitions.
 //
 // There's already logic in api_directive.go which creates a new index with
@@ -1080,6 +1239,15 @@ type IndexOptions struct {
 	TrackExistence bool   `json:"trackExistence"`
 	PartitionN     int    `json:"partitionN"`
 	Description    string `json:"description"`
+
+	// RetentionField names a timestamp field. When it is set, records whose
+	// value in that field is older than RetentionPeriod are deleted.
+	RetentionField  string        `json:"retentionField,omitempty"`
+	RetentionPeriod time.Duration `json:"retentionPeriod,omitempty"`
+
+	// PrimaryKey names the fields whose values make up the key of each
+	// record, encoded by EncodeCompositeKey. It requires Keys.
+	PrimaryKey []string `json:"primaryKey,omitempty"`
 }
 
 type importData struct {
@@ -0,0 +1,67 @@
+// Copyright 2023 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa
+
+import (
+	"context"
+	"io"
+)
+
+// Formats of the records which can be sent to POST /index/{index}/ingest.
+const (
+	IngestFormatNDJSON = "ndjson"
+	IngestFormatCSV    = "csv"
+)
+
+// MaxIngestRequestSize is the largest body a request to POST
+// /index/{index}/ingest can have. A request's records are all read before
+// any are ingested, so more should be sent in several requests.
+const MaxIngestRequestSize = 64 << 20
+
+// IngestRequest is a set of records to ingest into an index, as NDJSON
+// objects or CSV rows.
+type IngestRequest struct {
+	Index  string
+	Format string
+	Body   io.Reader
+
+	// Header gives the fields of the records in idk's header format, such
+	// as "price__Decimal_2", for those which shouldn't be taken from the
+	// index's existing fields or inferred from the records.
+	Header []string
+
+	// PrimaryKeyFields or IDField name the fields which identify a record.
+	// If neither is set, the records are given new IDs.
+	PrimaryKeyFields []string
+	IDField          string
+
+	// AuthToken is the token the request was made with, which the records
+	// are ingested with.
+	AuthToken string
+}
+
+// IngestResponse is the result of ingesting a set of records. Records which
+// couldn't be ingested are left out, and each has an error; the rest are
+// ingested.
+type IngestResponse struct {
+	Ingested uint64              `json:"ingested"`
+	Errors   []IngestRecordError `json:"errors,omitempty"`
+}
+
+// IngestRecordError is why a record couldn't be ingested.
+type IngestRecordError struct {
+	// Line is the line of the request body the record starts on.
+	Line uint64 `json:"line"`
+
+	// Field is the field whose value couldn't be ingested, if the error
+	// could be traced to one.
+	Field string `json:"field,omitempty"`
+	Error string `json:"error"`
+}
+
+// RecordIngester ingests records sent to the HTTP handler, translating keys,
+// allocating IDs, converting values and creating fields the way an idk
+// ingester does.
+type RecordIngester interface {
+	Ingest(ctx context.Context, req *IngestRequest) (*IngestResponse, error)
+}
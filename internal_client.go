@@ -5,6 +5,7 @@ package pilosa
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -25,7 +26,9 @@ import (
 	"github.com/featurebasedb/featurebase/v3/disco"
 	"github.com/featurebasedb/featurebase/v3/logger"
 	pnet "github.com/featurebasedb/featurebase/v3/net"
+	planner_types "github.com/featurebasedb/featurebase/v3/sql3/planner/types"
 	"github.com/featurebasedb/featurebase/v3/tracing"
+	"github.com/featurebasedb/featurebase/v3/wireprotocol"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
@@ -714,6 +717,14 @@ func (c *InternalClient) importNode(ctx context.Context, node *disco.Node, index
 	req.Header.Set("Accept", "application/x-protobuf")
 	req.Header.Set("X-Pilosa-Row", "roaring")
 	req.Header.Set("User-Agent", "pilosa/"+Version)
+	if opts.validated {
+		// only requests with the secret key, which other nodes have, are
+		// trusted to have been checked against the field's constraints
+		req.Header.Set(HeaderImportValidated, "true")
+		if c.secretKey != "" {
+			req.Header.Set("X-Feature-Key", c.secretKey)
+		}
+	}
 	AddAuthToken(ctx, &req.Header)
 
 	// Execute request against the host.
@@ -1075,7 +1086,7 @@ func (c *InternalClient) CreateFieldWithOptions(ctx context.Context, index, fiel
 		fieldOpt.TimeQuantum = &opt.TimeQuantum
 		ttlString := opt.TTL.String()
 		fieldOpt.TTL = &ttlString
-	case FieldTypeBool:
+	case FieldTypeBool, FieldTypeFloat:
 		// pass
 	case FieldTypeDecimal:
 		fieldOpt.Min = &opt.Min
@@ -1546,6 +1557,50 @@ func (c *InternalClient) MatchFieldKeysNode(ctx context.Context, uri *pnet.URI,
 	return matches, nil
 }
 
+func (c *InternalClient) MatchIndexKeysNode(ctx context.Context, uri *pnet.URI, index string, keyPrefix string) (matches []uint64, err error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.MatchIndexKeysNode")
+	defer span.Finish()
+
+	// Create HTTP request.
+	u := uriPathToURL(uri, fmt.Sprintf("%s/internal/translate/index/%s/keys/prefix", c.prefix(), index))
+	req, err := http.NewRequest("POST", u.String(), strings.NewReader(keyPrefix))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	// Apply headers.
+	req.Header.Set("Content-Length", strconv.Itoa(len(keyPrefix)))
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+Version)
+	AddAuthToken(ctx, &req.Header)
+
+	// Send the request.
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "executing request")
+	}
+	defer func() {
+		cerr := resp.Body.Close()
+		if cerr != nil && err == nil {
+			err = errors.Wrap(cerr, "closing response body")
+		}
+	}()
+
+	// Read the response body.
+	result, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading response")
+	}
+
+	// Decode the translations.
+	err = json.Unmarshal(result, &matches)
+	if err != nil {
+		return nil, errors.Wrap(err, "json decoding")
+	}
+
+	return matches, nil
+}
+
 func (c *InternalClient) Transactions(ctx context.Context) (map[string]*Transaction, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.Transactions")
 	defer span.Finish()
@@ -2471,6 +2526,202 @@ func (c *InternalClient) executeProtobufRequest(ctx context.Context, url string,
 }
 
 // ImportRoaringShard(ctx, node, string(tid), shard, request
+// HoldIndexes holds the given indexes on the node at uri until
+// ReleaseIndexes is called with the same id, or timeout has passed.
+func (c *InternalClient) HoldIndexes(ctx context.Context, uri *pnet.URI, id string, indexes []string, timeout time.Duration) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.HoldIndexes")
+	defer span.Finish()
+
+	buf, err := json.Marshal(&HoldIndexesRequest{Indexes: indexes, Timeout: timeout})
+	if err != nil {
+		return errors.Wrap(err, "marshalling payload")
+	}
+	u := uriPathToURL(uri, fmt.Sprintf("%s/internal/indexes/hold/%s", c.prefix(), id))
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(buf))
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+Version)
+	AddAuthToken(ctx, &req.Header)
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// ReleaseIndexes releases the indexes held on the node at uri for id.
+func (c *InternalClient) ReleaseIndexes(ctx context.Context, uri *pnet.URI, id string) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.ReleaseIndexes")
+	defer span.Finish()
+
+	u := uriPathToURL(uri, fmt.Sprintf("%s/internal/indexes/hold/%s", c.prefix(), id))
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+Version)
+	AddAuthToken(ctx, &req.Header)
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// BlockFieldWrites blocks writes to a field on the node at uri, until
+// UnblockFieldWrites is called or timeout has passed.
+func (c *InternalClient) BlockFieldWrites(ctx context.Context, uri *pnet.URI, index, field string, timeout time.Duration) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.BlockFieldWrites")
+	defer span.Finish()
+
+	buf, err := json.Marshal(&BlockFieldWriteRequest{Timeout: timeout})
+	if err != nil {
+		return errors.Wrap(err, "marshalling payload")
+	}
+	u := uriPathToURL(uri, fmt.Sprintf("%s/internal/index/%s/field/%s/write-block", c.prefix(), index, field))
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(buf))
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pilosa/"+Version)
+	AddAuthToken(ctx, &req.Header)
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// UnblockFieldWrites lets writes to a field through again on the node at
+// uri.
+func (c *InternalClient) UnblockFieldWrites(ctx context.Context, uri *pnet.URI, index, field string) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.UnblockFieldWrites")
+	defer span.Finish()
+
+	u := uriPathToURL(uri, fmt.Sprintf("%s/internal/index/%s/field/%s/write-block", c.prefix(), index, field))
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("User-Agent", "pilosa/"+Version)
+	AddAuthToken(ctx, &req.Header)
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// CursorSchema returns the schema of the rows of a cursor of the user in ctx
+// open on the node at uri, or ErrCursorNotFound if it isn't open there.
+func (c *InternalClient) CursorSchema(ctx context.Context, uri *pnet.URI, cursorID string) (planner_types.Schema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.CursorSchema")
+	defer span.Finish()
+
+	resp, err := c.cursorRequest(ctx, "GET", uriPathToURL(uri, fmt.Sprintf("%s/internal/sql/cursors/%s", c.prefix(), cursorID)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	schema, _, err := readCursorRows(resp.Body)
+	return schema, err
+}
+
+// FetchCursor returns up to n rows from a cursor of the user in ctx open on
+// the node at uri, and whether the cursor has been exhausted.
+func (c *InternalClient) FetchCursor(ctx context.Context, uri *pnet.URI, cursorID string, n int) (planner_types.Rows, bool, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.FetchCursor")
+	defer span.Finish()
+
+	u := uriPathToURL(uri, fmt.Sprintf("%s/internal/sql/cursors/%s/fetch", c.prefix(), cursorID))
+	u.RawQuery = url.Values{"n": []string{strconv.Itoa(n)}}.Encode()
+	resp, err := c.cursorRequest(ctx, "POST", u)
+	if err != nil {
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+
+	_, rows, err := readCursorRows(resp.Body)
+	if err != nil {
+		return nil, true, err
+	}
+	return rows, resp.Header.Get("X-Cursor-Done") == "true", nil
+}
+
+// CloseCursor closes a cursor of the user in ctx open on the node at uri.
+func (c *InternalClient) CloseCursor(ctx context.Context, uri *pnet.URI, cursorID string) error {
+	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.CloseCursor")
+	defer span.Finish()
+
+	resp, err := c.cursorRequest(ctx, "DELETE", uriPathToURL(uri, fmt.Sprintf("%s/internal/sql/cursors/%s", c.prefix(), cursorID)))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// cursorRequest executes a request for a cursor on behalf of the user in
+// ctx, since cursors belong to the user who opened them.
+func (c *InternalClient) cursorRequest(ctx context.Context, method string, u url.URL) (*http.Response, error) {
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	if userID, ok := fbcontext.UserID(ctx); ok {
+		req.Header.Set(HeaderRequestUserID, userID)
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	req.Header.Set("User-Agent", "pilosa/"+Version)
+	AddAuthToken(ctx, &req.Header)
+
+	resp, err := c.executeRequest(req.WithContext(ctx))
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, ErrCursorNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// readCursorRows reads the schema and the rows of a cursor written with the
+// wire protocol.
+func readCursorRows(r io.Reader) (planner_types.Schema, planner_types.Rows, error) {
+	if _, err := wireprotocol.ExpectToken(r, wireprotocol.TOKEN_SCHEMA_INFO); err != nil {
+		return nil, nil, errors.Wrap(err, "reading schema")
+	}
+	schema, err := wireprotocol.ReadSchema(r)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "reading schema")
+	}
+	rows := make(planner_types.Rows, 0)
+	for {
+		var token int16
+		if err := binary.Read(r, binary.BigEndian, &token); err != nil {
+			return nil, nil, errors.Wrap(err, "reading token")
+		}
+		switch token {
+		case wireprotocol.TOKEN_ROW:
+			row, err := wireprotocol.ReadRow(r, schema)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "reading row")
+			}
+			rows = append(rows, row)
+		case wireprotocol.TOKEN_DONE:
+			return schema, rows, nil
+		default:
+			return nil, nil, errors.Errorf("unexpected token %d", token)
+		}
+	}
+}
+
 func (c *InternalClient) ImportRoaringShard(ctx context.Context, uri *pnet.URI, index string, shard uint64, remote bool, req *ImportRoaringShardRequest) error {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.ImportRoaringShard")
 	defer span.Finish()
@@ -65,8 +65,7 @@ const (
 
 	// MetricBatchFlushDurationSeconds records the full time for
 	// RecordBatch.Flush (if splitBatchMode is in use). This includes
-	// starting and finishing a transaction, importing all data, and
-	// resetting internal structures.
+	// importing all data and resetting internal structures.
 	MetricBatchFlushDurationSeconds = "batch_flush_duration_seconds"
 
 	// MetricBatchShardImportBuildRequestsSeconds is the time it takes
@@ -763,6 +762,17 @@ var CounterQueryPercentileTotal = prometheus.NewCounterVec(
 	},
 )
 
+var CounterQueryNearestTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "pilosa",
+		Name:      "query_nearest_total",
+		Help:      "TODO",
+	},
+	[]string{
+		"index",
+	},
+)
+
 var CounterQueryDeleteTotal = prometheus.NewCounterVec(
 	prometheus.CounterOpts{
 		Namespace: "pilosa",
@@ -1029,6 +1039,7 @@ func init() {
 	prometheus.MustRegister(CounterQueryConstRowTotal)
 	prometheus.MustRegister(CounterQueryLimitTotal)
 	prometheus.MustRegister(CounterQueryPercentileTotal)
+	prometheus.MustRegister(CounterQueryNearestTotal)
 	prometheus.MustRegister(CounterQueryDeleteTotal)
 	prometheus.MustRegister(CounterQuerySortTotal)
 	prometheus.MustRegister(CounterQueryApplyTotal)
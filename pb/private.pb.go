@@ -26,6 +26,9 @@ type IndexMeta struct {
 	Keys                 bool     `protobuf:"varint,3,opt,name=Keys,proto3" json:"Keys,omitempty"`
 	TrackExistence       bool     `protobuf:"varint,4,opt,name=TrackExistence,proto3" json:"TrackExistence,omitempty"`
 	Description          string   `protobuf:"bytes,5,opt,name=Description,proto3" json:"Description,omitempty"`
+	RetentionField       string   `protobuf:"bytes,6,opt,name=RetentionField,proto3" json:"RetentionField,omitempty"`
+	RetentionPeriod      string   `protobuf:"bytes,7,opt,name=RetentionPeriod,proto3" json:"RetentionPeriod,omitempty"`
+	PrimaryKey           []string `protobuf:"bytes,8,rep,name=PrimaryKey,proto3" json:"PrimaryKey,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -85,6 +88,27 @@ func (m *IndexMeta) GetDescription() string {
 	return ""
 }
 
+func (m *IndexMeta) GetRetentionField() string {
+	if m != nil {
+		return m.RetentionField
+	}
+	return ""
+}
+
+func (m *IndexMeta) GetRetentionPeriod() string {
+	if m != nil {
+		return m.RetentionPeriod
+	}
+	return ""
+}
+
+func (m *IndexMeta) GetPrimaryKey() []string {
+	if m != nil {
+		return m.PrimaryKey
+	}
+	return nil
+}
+
 type FieldOptions struct {
 	Type                 string   `protobuf:"bytes,8,opt,name=Type,proto3" json:"Type,omitempty"`
 	CacheType            string   `protobuf:"bytes,3,opt,name=CacheType,proto3" json:"CacheType,omitempty"`
@@ -103,6 +127,13 @@ type FieldOptions struct {
 	TimeUnit             string   `protobuf:"bytes,19,opt,name=TimeUnit,proto3" json:"TimeUnit,omitempty"`
 	TTL                  string   `protobuf:"bytes,20,opt,name=TTL,proto3" json:"TTL,omitempty"`
 	TrackExistence       bool     `protobuf:"varint,21,opt,name=TrackExistence,proto3" json:"TrackExistence,omitempty"`
+	JSON                 bool     `protobuf:"varint,22,opt,name=JSON,proto3" json:"JSON,omitempty"`
+	JSONPaths            []string `protobuf:"bytes,23,rep,name=JSONPaths,proto3" json:"JSONPaths,omitempty"`
+	GeoPoint             bool     `protobuf:"varint,24,opt,name=GeoPoint,proto3" json:"GeoPoint,omitempty"`
+	BitVector            int64    `protobuf:"varint,25,opt,name=BitVector,proto3" json:"BitVector,omitempty"`
+	NotNull              bool     `protobuf:"varint,26,opt,name=NotNull,proto3" json:"NotNull,omitempty"`
+	Default              string   `protobuf:"bytes,27,opt,name=Default,proto3" json:"Default,omitempty"`
+	Check                string   `protobuf:"bytes,28,opt,name=Check,proto3" json:"Check,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -260,6 +291,55 @@ func (m *FieldOptions) GetTrackExistence() bool {
 	return false
 }
 
+func (m *FieldOptions) GetJSON() bool {
+	if m != nil {
+		return m.JSON
+	}
+	return false
+}
+
+func (m *FieldOptions) GetJSONPaths() []string {
+	if m != nil {
+		return m.JSONPaths
+	}
+	return nil
+}
+
+func (m *FieldOptions) GetGeoPoint() bool {
+	if m != nil {
+		return m.GeoPoint
+	}
+	return false
+}
+
+func (m *FieldOptions) GetBitVector() int64 {
+	if m != nil {
+		return m.BitVector
+	}
+	return 0
+}
+
+func (m *FieldOptions) GetNotNull() bool {
+	if m != nil {
+		return m.NotNull
+	}
+	return false
+}
+
+func (m *FieldOptions) GetDefault() string {
+	if m != nil {
+		return m.Default
+	}
+	return ""
+}
+
+func (m *FieldOptions) GetCheck() string {
+	if m != nil {
+		return m.Check
+	}
+	return ""
+}
+
 type ImportResponse struct {
 	Err                  string   `protobuf:"bytes,1,opt,name=Err,proto3" json:"Err,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -960,6 +1040,69 @@ func (m *DeleteFieldMessage) GetField() string {
 	return ""
 }
 
+type RenameFieldMessage struct {
+	Index                string   `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"`
+	Field                string   `protobuf:"bytes,2,opt,name=Field,proto3" json:"Field,omitempty"`
+	NewName              string   `protobuf:"bytes,3,opt,name=NewName,proto3" json:"NewName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RenameFieldMessage) Reset()         { *m = RenameFieldMessage{} }
+func (m *RenameFieldMessage) String() string { return proto.CompactTextString(m) }
+func (*RenameFieldMessage) ProtoMessage()    {}
+func (*RenameFieldMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d2a91b51c7bdc125, []int{46}
+}
+func (m *RenameFieldMessage) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RenameFieldMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RenameFieldMessage.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RenameFieldMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenameFieldMessage.Merge(m, src)
+}
+func (m *RenameFieldMessage) XXX_Size() int {
+	return m.Size()
+}
+func (m *RenameFieldMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenameFieldMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenameFieldMessage proto.InternalMessageInfo
+
+func (m *RenameFieldMessage) GetIndex() string {
+	if m != nil {
+		return m.Index
+	}
+	return ""
+}
+
+func (m *RenameFieldMessage) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *RenameFieldMessage) GetNewName() string {
+	if m != nil {
+		return m.NewName
+	}
+	return ""
+}
+
 type DeleteAvailableShardMessage struct {
 	Index                string   `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"`
 	Field                string   `protobuf:"bytes,2,opt,name=Field,proto3" json:"Field,omitempty"`
@@ -2954,122 +3097,124 @@ func init() {
 	proto.RegisterType((*ShardedIngestRequest)(nil), "pb.ShardedIngestRequest")
 	proto.RegisterMapType((map[uint64]*ShardIngestOperations)(nil), "pb.ShardedIngestRequest.OpsEntry")
 	proto.RegisterType((*DeleteDataframeMessage)(nil), "pb.DeleteDataframeMessage")
+	proto.RegisterType((*RenameFieldMessage)(nil), "pb.RenameFieldMessage")
 }
 
 func init() { proto.RegisterFile("private.proto", fileDescriptor_d2a91b51c7bdc125) }
 
 var fileDescriptor_d2a91b51c7bdc125 = []byte{
-	// 1750 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xcd, 0x6e, 0x24, 0x49,
-	0x11, 0xa6, 0x7e, 0xdc, 0x3f, 0xd1, 0x6e, 0x4f, 0x3b, 0xd7, 0x6b, 0x6a, 0xbc, 0x83, 0xd5, 0x93,
-	0xa0, 0x99, 0x66, 0x24, 0x8c, 0xf0, 0x1e, 0x16, 0xb1, 0x97, 0x1d, 0x77, 0x7b, 0x96, 0x66, 0x77,
-	0x7e, 0x36, 0xed, 0x99, 0x23, 0x28, 0x5d, 0x9d, 0xd8, 0xa5, 0xa9, 0xae, 0x6a, 0xaa, 0xaa, 0x3d,
-	0xdd, 0x7b, 0x40, 0x02, 0x09, 0xc1, 0x85, 0x3b, 0xe2, 0xc0, 0x5b, 0xf0, 0x0e, 0x5c, 0x90, 0x78,
-	0x04, 0x34, 0xdc, 0x78, 0x0a, 0x94, 0x91, 0x99, 0x55, 0xd9, 0xed, 0xb2, 0xbd, 0x58, 0x7b, 0xab,
-	0xf8, 0x22, 0x2b, 0xf2, 0x8b, 0x9f, 0x8c, 0x8c, 0x2a, 0xe8, 0xce, 0xb2, 0xe8, 0x92, 0x17, 0xe2,
-	0x60, 0x96, 0xa5, 0x45, 0x4a, 0xdc, 0xd9, 0xd9, 0xde, 0xe6, 0x6c, 0x7e, 0x16, 0x47, 0xa1, 0x42,
-	0x68, 0x04, 0xed, 0x71, 0x32, 0x11, 0x8b, 0xe7, 0xa2, 0xe0, 0x84, 0x80, 0xff, 0x85, 0x58, 0xe6,
-	0x81, 0xd7, 0x77, 0x06, 0x2d, 0x86, 0xcf, 0xe4, 0x11, 0x6c, 0x9d, 0x66, 0x3c, 0x7c, 0x7b, 0xbc,
-	0x88, 0xf2, 0x42, 0x24, 0xa1, 0x08, 0x7c, 0xd4, 0xae, 0xa1, 0xa4, 0x0f, 0x9d, 0x91, 0xc8, 0xc3,
-	0x2c, 0x9a, 0x15, 0x51, 0x9a, 0x04, 0x1b, 0x7d, 0x67, 0xd0, 0x66, 0x36, 0x44, 0xff, 0xeb, 0xc1,
-	0xe6, 0xb3, 0x48, 0xc4, 0x93, 0x97, 0x28, 0xe7, 0x72, 0xbb, 0xd3, 0xe5, 0x4c, 0x04, 0x2d, 0x5c,
-	0x8b, 0xcf, 0xe4, 0x01, 0xb4, 0x87, 0x3c, 0xbc, 0x10, 0xa8, 0xf0, 0x50, 0x51, 0x01, 0xa5, 0xf6,
-	0x24, 0xfa, 0x5a, 0xf1, 0xe8, 0xb2, 0x0a, 0x90, 0x14, 0x4e, 0xa3, 0xa9, 0xf8, 0x6a, 0xce, 0x93,
-	0x62, 0x3e, 0x35, 0x14, 0x2c, 0x88, 0xec, 0x42, 0xe3, 0x65, 0x3c, 0x79, 0x1e, 0x25, 0x41, 0xbb,
-	0xef, 0x0c, 0x3c, 0xa6, 0x25, 0x83, 0xf3, 0x45, 0x00, 0x15, 0xce, 0x17, 0x65, 0x40, 0x3a, 0xab,
-	0x01, 0x79, 0x91, 0x9e, 0x14, 0x3c, 0x99, 0xf0, 0x6c, 0xf2, 0x26, 0x12, 0xef, 0x82, 0x4d, 0x15,
-	0x90, 0x55, 0x54, 0xbe, 0x7b, 0xc4, 0x73, 0x11, 0x74, 0xd1, 0x22, 0x3e, 0x93, 0x3d, 0x68, 0x1d,
-	0x45, 0xc5, 0x48, 0xcc, 0x8a, 0x8b, 0x60, 0xab, 0xef, 0x0c, 0x7c, 0x56, 0xca, 0x64, 0x07, 0x36,
-	0x4e, 0x42, 0x1e, 0x8b, 0xe0, 0x1e, 0xbe, 0xa0, 0x04, 0x42, 0x61, 0xf3, 0x59, 0x9a, 0x89, 0xe8,
-	0x3c, 0xc1, 0x34, 0x05, 0x3d, 0x74, 0x6a, 0x05, 0x23, 0xdf, 0x03, 0x4f, 0xba, 0xb4, 0xdd, 0x77,
-	0x06, 0x9d, 0xc3, 0xce, 0xc1, 0xec, 0xec, 0x60, 0x24, 0xc2, 0x68, 0xca, 0x63, 0x26, 0x71, 0x54,
-	0xf3, 0x45, 0x40, 0xea, 0xd4, 0x7c, 0x21, 0x39, 0xc9, 0x10, 0xbd, 0x4e, 0xa2, 0x22, 0xf8, 0x00,
-	0xad, 0x97, 0x32, 0xe9, 0x81, 0x77, 0x7a, 0xfa, 0x65, 0xb0, 0x83, 0xb0, 0x7c, 0xac, 0x29, 0x87,
-	0x0f, 0xeb, 0xca, 0x81, 0x52, 0xd8, 0x1a, 0x4f, 0x67, 0x69, 0x56, 0x30, 0x91, 0xcf, 0xd2, 0x24,
-	0x17, 0xd2, 0xd6, 0x71, 0x96, 0x05, 0x8e, 0xb2, 0x75, 0x9c, 0x65, 0xf4, 0xb7, 0xd0, 0x3b, 0x8a,
-	0xd3, 0xf0, 0xed, 0x88, 0x17, 0x9c, 0x89, 0xdf, 0xcc, 0x45, 0x5e, 0xc8, 0x28, 0x28, 0x47, 0xd5,
-	0x3a, 0x25, 0x48, 0x14, 0x2b, 0x27, 0x70, 0x15, 0x8a, 0x82, 0x8c, 0x30, 0xc6, 0x5f, 0x25, 0x1a,
-	0x9f, 0x31, 0x8a, 0x17, 0x3c, 0x9b, 0x60, 0x75, 0xf8, 0x4c, 0x09, 0x12, 0xc5, 0x9d, 0xb0, 0xa2,
-	0x7c, 0xa6, 0x04, 0x3a, 0x86, 0x6d, 0x6b, 0x7f, 0x4d, 0x73, 0x17, 0x1a, 0x2c, 0x7d, 0x37, 0x1e,
-	0xe5, 0x81, 0xd3, 0xf7, 0x06, 0x3e, 0xd3, 0x12, 0x96, 0x5e, 0x1a, 0xcf, 0xa7, 0x89, 0x54, 0xb9,
-	0xa8, 0xaa, 0x00, 0x7a, 0x1f, 0x36, 0xb0, 0x0e, 0xa5, 0x97, 0xd5, 0xbb, 0xf2, 0x91, 0xfe, 0xce,
-	0x81, 0xf6, 0x73, 0xbe, 0x40, 0x22, 0x39, 0xf9, 0x04, 0x5a, 0xa6, 0x4a, 0x70, 0x51, 0xe7, 0xf0,
-	0x23, 0x99, 0x91, 0x72, 0xc1, 0x81, 0xd1, 0x1e, 0x27, 0x45, 0xb6, 0x64, 0xe5, 0xe2, 0xbd, 0x4f,
-	0xa1, 0xbb, 0xa2, 0x92, 0x3b, 0xbd, 0x15, 0x4b, 0x13, 0xcf, 0xb7, 0x62, 0x29, 0xbd, 0xbc, 0xe4,
-	0xf1, 0x5c, 0x60, 0x94, 0x7c, 0xa6, 0x84, 0x9f, 0xb9, 0x3f, 0x75, 0xe8, 0x1b, 0x20, 0xc3, 0x4c,
-	0xf0, 0x42, 0xe0, 0x26, 0xcf, 0x45, 0x9e, 0xf3, 0x73, 0x71, 0x5b, 0xac, 0x3d, 0x3b, 0xd6, 0x65,
-	0x5c, 0x5d, 0x2b, 0xae, 0xf4, 0x09, 0x90, 0x91, 0x88, 0x45, 0x21, 0x74, 0x0f, 0xb9, 0xc1, 0xae,
-	0x8c, 0x83, 0x26, 0x71, 0xfb, 0x62, 0xf2, 0x10, 0x7c, 0xd9, 0x91, 0x70, 0xb7, 0xce, 0x61, 0x57,
-	0x86, 0xa8, 0x6c, 0x53, 0x0c, 0x55, 0x98, 0x10, 0x34, 0x37, 0x79, 0x5a, 0x20, 0x57, 0x8f, 0x55,
-	0x80, 0x34, 0xfb, 0xf2, 0x5d, 0x22, 0x32, 0x5d, 0x1c, 0x4a, 0xa0, 0x7f, 0x2d, 0x39, 0xa0, 0x57,
-	0xdf, 0x30, 0x10, 0x2b, 0x45, 0xf7, 0x03, 0xcd, 0xcc, 0x43, 0x66, 0x3d, 0xc9, 0xcc, 0x6e, 0x6a,
-	0x75, 0xe4, 0xfc, 0x6f, 0x46, 0xee, 0x0f, 0x0e, 0x90, 0xd7, 0xb3, 0xc9, 0x3a, 0xb9, 0x67, 0x75,
-	0x94, 0x91, 0x69, 0xe7, 0x70, 0x57, 0x6e, 0x7f, 0x55, 0xcb, 0xea, 0x9c, 0x7c, 0x0c, 0x0d, 0x65,
-	0x5d, 0x07, 0xf5, 0x5e, 0x49, 0x5d, 0xc1, 0x4c, 0xab, 0xe9, 0xa7, 0xd0, 0xb1, 0x60, 0xec, 0x8d,
-	0xaa, 0xa7, 0xab, 0xe8, 0x68, 0x49, 0x3a, 0xf1, 0xa6, 0xac, 0xb6, 0x36, 0x53, 0x02, 0xfd, 0xcc,
-	0x54, 0xc4, 0x5d, 0x03, 0x4c, 0x43, 0xf8, 0x48, 0x59, 0x78, 0x7a, 0xc9, 0xa3, 0x98, 0x9f, 0xc5,
-	0xff, 0x57, 0xd1, 0xae, 0xe4, 0x2a, 0x80, 0x26, 0xbe, 0x3b, 0x1e, 0xe9, 0x83, 0x6f, 0x44, 0x3a,
-	0x87, 0xaa, 0x87, 0xbc, 0xe0, 0x53, 0xa1, 0xad, 0xe1, 0x73, 0x99, 0x62, 0xf7, 0xc6, 0x14, 0x4b,
-	0xff, 0x23, 0xf1, 0x4e, 0xde, 0x96, 0x1e, 0xfa, 0x2f, 0x85, 0x9b, 0x13, 0x4f, 0x7f, 0x04, 0x8d,
-	0x93, 0xf0, 0x42, 0x4c, 0x39, 0xf9, 0x3e, 0x34, 0x91, 0xb9, 0xc8, 0x75, 0x1b, 0x68, 0x97, 0x35,
-	0xce, 0x8c, 0x46, 0x56, 0x84, 0xf6, 0xaf, 0x8e, 0xe6, 0xca, 0x56, 0xee, 0x7a, 0x8d, 0x3d, 0x86,
-	0xa6, 0xe6, 0x8b, 0x55, 0x76, 0xe5, 0x10, 0x19, 0x2d, 0x79, 0x08, 0x0d, 0xf4, 0x2e, 0x0f, 0xfc,
-	0x8a, 0x08, 0x22, 0x4c, 0x2b, 0xe8, 0x31, 0x78, 0xaf, 0xd9, 0x58, 0x56, 0x02, 0xb2, 0x37, 0x34,
-	0xb4, 0x24, 0xc9, 0xfd, 0x3c, 0xcd, 0x0b, 0x1d, 0x7b, 0x7c, 0x96, 0xd8, 0xab, 0x34, 0x53, 0x07,
-	0xb3, 0xcb, 0xf0, 0x99, 0xfe, 0xc9, 0x01, 0xff, 0x45, 0x3a, 0x11, 0x64, 0x0b, 0xdc, 0xf1, 0x48,
-	0x1b, 0x71, 0xc7, 0x23, 0x72, 0x1f, 0xed, 0xeb, 0x78, 0x37, 0xe5, 0xfe, 0xaf, 0xd9, 0x98, 0xe1,
-	0x9e, 0x0f, 0xa0, 0x3d, 0xce, 0x5f, 0x65, 0xd1, 0x94, 0x67, 0x4b, 0x3d, 0x97, 0x54, 0x00, 0x76,
-	0xa5, 0x42, 0x96, 0xb4, 0xaf, 0xd2, 0x8e, 0x02, 0x79, 0x08, 0xcd, 0xcf, 0xd9, 0xab, 0xa1, 0x34,
-	0xb9, 0xb1, 0x6a, 0xd2, 0xe0, 0xf4, 0x33, 0xe8, 0x49, 0x26, 0xb8, 0xde, 0x54, 0xd6, 0x2e, 0x34,
-	0x24, 0x56, 0x32, 0xd3, 0x52, 0xb5, 0x89, 0x6b, 0x6d, 0x42, 0x9f, 0x29, 0x0b, 0xc7, 0x97, 0x22,
-	0x29, 0xac, 0xda, 0x44, 0x19, 0x0d, 0x74, 0x99, 0x12, 0xc8, 0x03, 0xe5, 0xb5, 0x76, 0xaf, 0x25,
-	0xb9, 0x48, 0x99, 0x21, 0x4a, 0x97, 0x00, 0x86, 0xc9, 0x3c, 0x2f, 0xd7, 0x3a, 0x75, 0x6b, 0x09,
-	0x35, 0xe5, 0xa3, 0xbb, 0x0f, 0x48, 0xbd, 0x42, 0x98, 0x29, 0xac, 0x1f, 0x56, 0x85, 0xa5, 0xf2,
-	0x79, 0xaf, 0xcc, 0xbb, 0xda, 0xa3, 0x2a, 0xaf, 0x0b, 0xe8, 0x58, 0x78, 0x6d, 0x8d, 0x3d, 0x2e,
-	0x8b, 0xc3, 0xad, 0x8c, 0x21, 0xa2, 0x8d, 0x69, 0xf5, 0xcd, 0xdd, 0x98, 0x46, 0xba, 0xa5, 0xdc,
-	0xb0, 0xd3, 0x00, 0xee, 0xad, 0x1e, 0x78, 0x73, 0xcb, 0xae, 0xc3, 0xb7, 0x6c, 0xf5, 0x47, 0x07,
-	0xba, 0xc3, 0x78, 0x9e, 0x17, 0x22, 0x2b, 0x63, 0xda, 0xd6, 0x40, 0x99, 0xda, 0x0a, 0xa8, 0xcf,
-	0x2e, 0xd9, 0x87, 0x0d, 0x19, 0x71, 0x75, 0xb8, 0xed, 0x44, 0x28, 0xd8, 0xca, 0x84, 0x7f, 0x5d,
-	0x26, 0xe8, 0x1b, 0x68, 0x1d, 0x9d, 0x8c, 0x3f, 0xcf, 0xd2, 0xf9, 0xac, 0xd6, 0x63, 0x33, 0xfe,
-	0xba, 0xd6, 0xf8, 0xdb, 0x53, 0xa3, 0x9c, 0xf2, 0x0a, 0xa7, 0xb7, 0x9e, 0x9a, 0xde, 0x7c, 0x8d,
-	0xf0, 0x05, 0x3d, 0x81, 0x6d, 0xe5, 0xae, 0xec, 0x38, 0x77, 0x69, 0x8b, 0x66, 0x6e, 0xf2, 0xaa,
-	0xb9, 0x49, 0x1a, 0x55, 0x5d, 0xf7, 0xdb, 0x34, 0xfa, 0x4f, 0x17, 0xb6, 0x99, 0xc8, 0xa3, 0xaf,
-	0xc5, 0x38, 0xc9, 0x8b, 0x6c, 0x1e, 0x9a, 0x8b, 0xe3, 0x17, 0xe9, 0x99, 0xce, 0x85, 0xc7, 0x94,
-	0x70, 0xf3, 0x29, 0x21, 0x14, 0x9a, 0x76, 0x13, 0xb0, 0x17, 0x18, 0x05, 0x79, 0x02, 0xcd, 0x93,
-	0x74, 0x9e, 0x85, 0x65, 0xe5, 0x63, 0xe7, 0x56, 0xfb, 0x2b, 0x05, 0x33, 0x0b, 0xc8, 0x17, 0x40,
-	0x4e, 0x33, 0x9e, 0xe4, 0x31, 0x97, 0x94, 0xcc, 0x6b, 0xad, 0x6a, 0x20, 0xb3, 0xb4, 0x2b, 0x16,
-	0x6a, 0x5e, 0x23, 0x07, 0xf6, 0x11, 0x0e, 0x9a, 0xc8, 0x6f, 0xcb, 0xf0, 0xd3, 0xe7, 0xc4, 0x3e,
-	0xe4, 0x9f, 0xac, 0x55, 0x68, 0xd0, 0xc0, 0x57, 0xb6, 0xf1, 0x32, 0xb7, 0x15, 0x6c, 0x75, 0x1d,
-	0xfd, 0xbd, 0x03, 0x9b, 0x36, 0x9b, 0x5b, 0xda, 0x45, 0x99, 0x3e, 0xf7, 0xf6, 0xf9, 0xce, 0xa4,
-	0xcf, 0xaf, 0x9b, 0xa5, 0x37, 0xec, 0x99, 0x2f, 0x85, 0xef, 0x5e, 0x13, 0x9c, 0x3b, 0xd1, 0xe9,
-	0x43, 0xe7, 0x15, 0xcf, 0x8a, 0x48, 0x1a, 0xd3, 0xf7, 0xf4, 0x06, 0xb3, 0x21, 0x2a, 0xe0, 0xfe,
-	0x95, 0x22, 0x1a, 0xa6, 0xd3, 0x99, 0xac, 0xd6, 0x3b, 0x15, 0x93, 0x6c, 0xd3, 0x59, 0x96, 0x66,
-	0x26, 0x02, 0x28, 0xd0, 0x23, 0x68, 0x9d, 0xa6, 0xb3, 0x34, 0x4e, 0xcf, 0x97, 0xb7, 0xb4, 0x8c,
-	0x00, 0x9a, 0xea, 0x6a, 0x50, 0x2d, 0xaa, 0xcd, 0x8c, 0x48, 0x3f, 0x90, 0xf5, 0x1e, 0xf2, 0x38,
-	0x9c, 0xc7, 0xbc, 0x10, 0xf8, 0x45, 0x80, 0xe0, 0x97, 0x29, 0x9f, 0xa8, 0xae, 0xa0, 0x8f, 0x16,
-	0xfd, 0x95, 0x2e, 0x40, 0x8e, 0xee, 0x58, 0x57, 0xd0, 0xd3, 0xd0, 0x9e, 0xb5, 0x94, 0x44, 0x7e,
-	0x02, 0x1d, 0x6b, 0xb5, 0x3d, 0xc0, 0x59, 0x30, 0xb3, 0xd7, 0xd0, 0xbf, 0x3b, 0x2b, 0xef, 0x5c,
-	0xb9, 0x73, 0xf5, 0x56, 0x97, 0x2a, 0x48, 0x2d, 0xa6, 0x25, 0xe9, 0xfa, 0xf1, 0x22, 0x8c, 0xe7,
-	0xb9, 0x54, 0xe9, 0x0b, 0xb7, 0x04, 0xa4, 0xeb, 0xf2, 0xe3, 0x30, 0x9d, 0x9b, 0xe1, 0xc6, 0x88,
-	0xf2, 0x33, 0x72, 0x24, 0xf8, 0x24, 0x8e, 0x12, 0x81, 0xf5, 0xe2, 0xb1, 0x52, 0x26, 0x4f, 0x54,
-	0x8f, 0x35, 0x85, 0xbe, 0xb3, 0x46, 0x1c, 0x75, 0xaa, 0xf3, 0xe6, 0x94, 0x40, 0x6f, 0x5d, 0x45,
-	0x77, 0x80, 0xa8, 0x0a, 0x78, 0x7a, 0x96, 0x66, 0xe6, 0xb6, 0xa5, 0x43, 0xd3, 0x5c, 0x64, 0xf4,
-	0x6f, 0xbb, 0xc4, 0xab, 0xc8, 0xba, 0x76, 0x64, 0xe9, 0x2f, 0x61, 0x4b, 0xcf, 0x76, 0x22, 0xc3,
-	0x82, 0x96, 0x01, 0x60, 0x22, 0x4c, 0xe5, 0x98, 0x68, 0xbe, 0xe3, 0x2a, 0x40, 0xda, 0xc1, 0x41,
-	0xd7, 0xdc, 0x4e, 0x5a, 0xc2, 0xd9, 0x28, 0x3a, 0x4f, 0xc4, 0x04, 0x6f, 0x0c, 0x8f, 0x69, 0x89,
-	0xfe, 0xd9, 0x85, 0x1d, 0x35, 0x74, 0x26, 0xe7, 0x22, 0x2f, 0xaa, 0x6d, 0x70, 0xac, 0xc6, 0xfe,
-	0x5f, 0x8e, 0xd5, 0x78, 0x03, 0x3c, 0x82, 0xad, 0x61, 0x2c, 0x78, 0x56, 0x71, 0x50, 0x1b, 0xad,
-	0xa1, 0xf2, 0xdc, 0x20, 0xa2, 0xaf, 0x67, 0x35, 0x84, 0xda, 0x10, 0x39, 0x82, 0x96, 0x76, 0xcd,
-	0x34, 0xc4, 0x47, 0x78, 0x4b, 0xd5, 0xb0, 0x31, 0xf3, 0x6d, 0xae, 0xbf, 0x3a, 0x8d, 0xb8, 0xf7,
-	0x12, 0xba, 0x2b, 0xaa, 0x9a, 0xaf, 0xce, 0x81, 0xfd, 0xd5, 0xd9, 0x39, 0x24, 0xd6, 0xb8, 0xac,
-	0xad, 0xdb, 0x5f, 0xa2, 0x43, 0xf8, 0xb0, 0x8e, 0x40, 0x4e, 0x9e, 0x80, 0x27, 0x89, 0xaa, 0x61,
-	0x38, 0xb8, 0x8e, 0x28, 0x93, 0x8b, 0xe8, 0xdf, 0x1c, 0x1d, 0x54, 0xa1, 0xf5, 0xe6, 0xef, 0xc1,
-	0xc7, 0xb6, 0x91, 0x87, 0xa5, 0x91, 0xb5, 0x65, 0x07, 0xa5, 0xa3, 0x72, 0xf5, 0xde, 0x57, 0xd0,
-	0xaa, 0x73, 0xcf, 0x57, 0xee, 0xfd, 0x78, 0xd5, 0xbd, 0xfb, 0xd7, 0x31, 0xcb, 0x6d, 0x2f, 0x0f,
-	0x60, 0x57, 0xdd, 0xa6, 0x23, 0x5e, 0xf0, 0x5f, 0x67, 0x7c, 0x2a, 0x6e, 0xbc, 0x52, 0x8f, 0x7a,
-	0xff, 0x78, 0xbf, 0xef, 0xfc, 0xeb, 0xfd, 0xbe, 0xf3, 0xef, 0xf7, 0xfb, 0xce, 0x5f, 0xfe, 0xb3,
-	0xff, 0x9d, 0xb3, 0x06, 0xfe, 0x9e, 0xfb, 0xf8, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xe2, 0x0d,
-	0x5d, 0xdb, 0xc1, 0x13, 0x00, 0x00,
+	// 1767 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x41, 0x6f, 0x1c, 0x49,
+	0x15, 0xa6, 0xbb, 0xc7, 0x9e, 0x99, 0x37, 0x1e, 0xc7, 0xae, 0xf5, 0x9a, 0x8e, 0x37, 0x58, 0x4e,
+	0x81, 0x36, 0x26, 0x12, 0x46, 0x78, 0x0f, 0x8b, 0xd8, 0xcb, 0xc6, 0x1e, 0x67, 0x19, 0x76, 0x13,
+	0x67, 0xcb, 0x4e, 0x0e, 0x1c, 0x40, 0xe5, 0x9e, 0xc2, 0x6e, 0xa5, 0xa7, 0x7b, 0xe8, 0xee, 0xb1,
+	0xc7, 0x7b, 0x40, 0x02, 0x09, 0xc1, 0x85, 0x3b, 0xe2, 0xc0, 0xbf, 0xe0, 0x3f, 0x70, 0x41, 0xe2,
+	0x27, 0xa0, 0x70, 0xe3, 0x57, 0xa0, 0xf7, 0xaa, 0xaa, 0xbb, 0x66, 0xd2, 0xb1, 0x83, 0xc5, 0xad,
+	0xde, 0xf7, 0xaa, 0x5f, 0x7d, 0xef, 0xd5, 0xab, 0x57, 0xaf, 0x1a, 0xfa, 0x93, 0x3c, 0xbe, 0x94,
+	0xa5, 0xda, 0x9b, 0xe4, 0x59, 0x99, 0x31, 0x7f, 0x72, 0xb6, 0xb5, 0x32, 0x99, 0x9e, 0x25, 0x71,
+	0xa4, 0x11, 0x1e, 0x43, 0x77, 0x98, 0x8e, 0xd4, 0xec, 0x99, 0x2a, 0x25, 0x63, 0xd0, 0xfa, 0x52,
+	0x5d, 0x17, 0x61, 0xb0, 0xe3, 0xed, 0x76, 0x04, 0x8d, 0xd9, 0xc7, 0xb0, 0x7a, 0x9a, 0xcb, 0xe8,
+	0xf5, 0xd1, 0x2c, 0x2e, 0x4a, 0x95, 0x46, 0x2a, 0x6c, 0x91, 0x76, 0x01, 0x65, 0x3b, 0xd0, 0x1b,
+	0xa8, 0x22, 0xca, 0xe3, 0x49, 0x19, 0x67, 0x69, 0xb8, 0xb4, 0xe3, 0xed, 0x76, 0x85, 0x0b, 0xf1,
+	0xff, 0x04, 0xb0, 0xf2, 0x34, 0x56, 0xc9, 0xe8, 0x98, 0xe4, 0x02, 0x97, 0x3b, 0xbd, 0x9e, 0xa8,
+	0xb0, 0x43, 0x73, 0x69, 0xcc, 0x1e, 0x40, 0xf7, 0x50, 0x46, 0x17, 0x8a, 0x14, 0x01, 0x29, 0x6a,
+	0xa0, 0xd2, 0x9e, 0xc4, 0xdf, 0x68, 0x1e, 0x7d, 0x51, 0x03, 0x48, 0xe1, 0x34, 0x1e, 0xab, 0xaf,
+	0xa7, 0x32, 0x2d, 0xa7, 0x63, 0x4b, 0xc1, 0x81, 0xd8, 0x26, 0x2c, 0x1f, 0x27, 0xa3, 0x67, 0x71,
+	0x1a, 0x76, 0x77, 0xbc, 0xdd, 0x40, 0x18, 0xc9, 0xe2, 0x72, 0x16, 0x42, 0x8d, 0xcb, 0x59, 0x15,
+	0x90, 0xde, 0x7c, 0x40, 0x9e, 0x67, 0x27, 0xa5, 0x4c, 0x47, 0x32, 0x1f, 0xbd, 0x8a, 0xd5, 0x55,
+	0xb8, 0xa2, 0x03, 0x32, 0x8f, 0xe2, 0xb7, 0x07, 0xb2, 0x50, 0x61, 0x9f, 0x2c, 0xd2, 0x98, 0x6d,
+	0x41, 0xe7, 0x20, 0x2e, 0x07, 0x6a, 0x52, 0x5e, 0x84, 0xab, 0x3b, 0xde, 0x6e, 0x4b, 0x54, 0x32,
+	0xdb, 0x80, 0xa5, 0x93, 0x48, 0x26, 0x2a, 0xbc, 0x47, 0x1f, 0x68, 0x81, 0x71, 0x58, 0x79, 0x9a,
+	0xe5, 0x2a, 0x3e, 0x4f, 0x69, 0x9b, 0xc2, 0x35, 0x72, 0x6a, 0x0e, 0x63, 0xdf, 0x81, 0x00, 0x5d,
+	0x5a, 0xdf, 0xf1, 0x76, 0x7b, 0xfb, 0xbd, 0xbd, 0xc9, 0xd9, 0xde, 0x40, 0x45, 0xf1, 0x58, 0x26,
+	0x02, 0x71, 0x52, 0xcb, 0x59, 0xc8, 0x9a, 0xd4, 0x72, 0x86, 0x9c, 0x30, 0x44, 0x2f, 0xd3, 0xb8,
+	0x0c, 0x3f, 0x20, 0xeb, 0x95, 0xcc, 0xd6, 0x20, 0x38, 0x3d, 0xfd, 0x2a, 0xdc, 0x20, 0x18, 0x87,
+	0x0d, 0xe9, 0xf0, 0x61, 0x53, 0x3a, 0x70, 0x0e, 0xab, 0xc3, 0xf1, 0x24, 0xcb, 0x4b, 0xa1, 0x8a,
+	0x49, 0x96, 0x16, 0x0a, 0x6d, 0x1d, 0xe5, 0x79, 0xe8, 0x69, 0x5b, 0x47, 0x79, 0xce, 0x7f, 0x03,
+	0x6b, 0x07, 0x49, 0x16, 0xbd, 0x1e, 0xc8, 0x52, 0x0a, 0xf5, 0xeb, 0xa9, 0x2a, 0x4a, 0x8c, 0x82,
+	0x76, 0x54, 0xcf, 0xd3, 0x02, 0xa2, 0x94, 0x39, 0xa1, 0xaf, 0x51, 0x12, 0x30, 0xc2, 0x14, 0x7f,
+	0xbd, 0xd1, 0x34, 0xa6, 0x28, 0x5e, 0xc8, 0x7c, 0x44, 0xd9, 0xd1, 0x12, 0x5a, 0x40, 0x94, 0x56,
+	0xa2, 0x8c, 0x6a, 0x09, 0x2d, 0xf0, 0x21, 0xac, 0x3b, 0xeb, 0x1b, 0x9a, 0x9b, 0xb0, 0x2c, 0xb2,
+	0xab, 0xe1, 0xa0, 0x08, 0xbd, 0x9d, 0x60, 0xb7, 0x25, 0x8c, 0x44, 0xa9, 0x97, 0x25, 0xd3, 0x71,
+	0x8a, 0x2a, 0x9f, 0x54, 0x35, 0xc0, 0xef, 0xc3, 0x12, 0xe5, 0x21, 0x7a, 0x59, 0x7f, 0x8b, 0x43,
+	0xfe, 0x5b, 0x0f, 0xba, 0xcf, 0xe4, 0x8c, 0x88, 0x14, 0xec, 0x53, 0xe8, 0xd8, 0x2c, 0xa1, 0x49,
+	0xbd, 0xfd, 0x8f, 0x70, 0x47, 0xaa, 0x09, 0x7b, 0x56, 0x7b, 0x94, 0x96, 0xf9, 0xb5, 0xa8, 0x26,
+	0x6f, 0x7d, 0x06, 0xfd, 0x39, 0x15, 0xae, 0xf4, 0x5a, 0x5d, 0xdb, 0x78, 0xbe, 0x56, 0xd7, 0xe8,
+	0xe5, 0xa5, 0x4c, 0xa6, 0x8a, 0xa2, 0xd4, 0x12, 0x5a, 0xf8, 0x89, 0xff, 0x63, 0x8f, 0xbf, 0x02,
+	0x76, 0x98, 0x2b, 0x59, 0x2a, 0x5a, 0xe4, 0x99, 0x2a, 0x0a, 0x79, 0xae, 0x6e, 0x8b, 0x75, 0xe0,
+	0xc6, 0xba, 0x8a, 0xab, 0xef, 0xc4, 0x95, 0x3f, 0x06, 0x36, 0x50, 0x89, 0x2a, 0x95, 0xa9, 0x21,
+	0x37, 0xd8, 0xc5, 0x38, 0x18, 0x12, 0xb7, 0x4f, 0x66, 0x0f, 0xa1, 0x85, 0x15, 0x89, 0x56, 0xeb,
+	0xed, 0xf7, 0x31, 0x44, 0x55, 0x99, 0x12, 0xa4, 0xa2, 0x0d, 0x21, 0x73, 0xa3, 0x27, 0x25, 0x71,
+	0x0d, 0x44, 0x0d, 0xa0, 0xd9, 0xe3, 0xab, 0x54, 0xe5, 0x26, 0x39, 0xb4, 0xc0, 0xff, 0x52, 0x71,
+	0x20, 0xaf, 0xde, 0x33, 0x10, 0x73, 0x49, 0xf7, 0x3d, 0xc3, 0x2c, 0x20, 0x66, 0x6b, 0xc8, 0xcc,
+	0x2d, 0x6a, 0x4d, 0xe4, 0x5a, 0xef, 0x47, 0xee, 0xf7, 0x1e, 0xb0, 0x97, 0x93, 0xd1, 0x22, 0xb9,
+	0xa7, 0x4d, 0x94, 0x89, 0x69, 0x6f, 0x7f, 0x13, 0x97, 0x7f, 0x5b, 0x2b, 0x9a, 0x9c, 0x7c, 0x04,
+	0xcb, 0xda, 0xba, 0x09, 0xea, 0xbd, 0x8a, 0xba, 0x86, 0x85, 0x51, 0xf3, 0xcf, 0xa0, 0xe7, 0xc0,
+	0x54, 0x1b, 0x75, 0x4d, 0xd7, 0xd1, 0x31, 0x12, 0x3a, 0xf1, 0xaa, 0xca, 0xb6, 0xae, 0xd0, 0x02,
+	0xff, 0xdc, 0x66, 0xc4, 0x5d, 0x03, 0xcc, 0x23, 0xf8, 0x48, 0x5b, 0x78, 0x72, 0x29, 0xe3, 0x44,
+	0x9e, 0x25, 0xff, 0x53, 0xd2, 0xce, 0xed, 0x55, 0x08, 0x6d, 0xfa, 0x76, 0x38, 0x30, 0x07, 0xdf,
+	0x8a, 0x7c, 0x0a, 0x75, 0x0d, 0x79, 0x2e, 0xc7, 0xca, 0x58, 0xa3, 0x71, 0xb5, 0xc5, 0xfe, 0x8d,
+	0x5b, 0x8c, 0xfe, 0xc7, 0xea, 0x0a, 0x6f, 0xcb, 0x80, 0xfc, 0x47, 0xe1, 0xe6, 0x8d, 0xe7, 0x3f,
+	0x80, 0xe5, 0x93, 0xe8, 0x42, 0x8d, 0x25, 0xfb, 0x2e, 0xb4, 0x89, 0xb9, 0x2a, 0x4c, 0x19, 0xe8,
+	0x56, 0x39, 0x2e, 0xac, 0x06, 0x33, 0xc2, 0xf8, 0xd7, 0x44, 0x73, 0x6e, 0x29, 0x7f, 0x31, 0xc7,
+	0x1e, 0x41, 0xdb, 0xf0, 0x0d, 0x97, 0x9a, 0x0e, 0x91, 0xd5, 0xb2, 0x87, 0xb0, 0x4c, 0xde, 0x15,
+	0x61, 0xab, 0x26, 0x42, 0x88, 0x30, 0x0a, 0x7e, 0x04, 0xc1, 0x4b, 0x31, 0x64, 0x9b, 0x86, 0xbd,
+	0xa5, 0x61, 0x24, 0x24, 0xf7, 0xd3, 0xac, 0x28, 0x4d, 0xec, 0x69, 0x8c, 0xd8, 0x8b, 0x2c, 0xd7,
+	0x07, 0xb3, 0x2f, 0x68, 0xcc, 0xff, 0xe8, 0x41, 0xeb, 0x79, 0x36, 0x52, 0x6c, 0x15, 0xfc, 0xe1,
+	0xc0, 0x18, 0xf1, 0x87, 0x03, 0x76, 0x9f, 0xec, 0x9b, 0x78, 0xb7, 0x71, 0xfd, 0x97, 0x62, 0x28,
+	0x68, 0xcd, 0x07, 0xd0, 0x1d, 0x16, 0x2f, 0xf2, 0x78, 0x2c, 0xf3, 0x6b, 0xd3, 0x97, 0xd4, 0x00,
+	0x55, 0xa5, 0x12, 0x53, 0xba, 0xa5, 0xb7, 0x9d, 0x04, 0xf6, 0x10, 0xda, 0x5f, 0x88, 0x17, 0x87,
+	0x68, 0x72, 0x69, 0xde, 0xa4, 0xc5, 0xf9, 0xe7, 0xb0, 0x86, 0x4c, 0x68, 0xbe, 0xcd, 0xac, 0x4d,
+	0x58, 0x46, 0xac, 0x62, 0x66, 0xa4, 0x7a, 0x11, 0xdf, 0x59, 0x84, 0x3f, 0xd5, 0x16, 0x8e, 0x2e,
+	0x55, 0x5a, 0x3a, 0xb9, 0x49, 0x32, 0x19, 0xe8, 0x0b, 0x2d, 0xb0, 0x07, 0xda, 0x6b, 0xe3, 0x5e,
+	0x07, 0xb9, 0xa0, 0x2c, 0x08, 0xe5, 0xd7, 0x00, 0x96, 0xc9, 0xb4, 0xa8, 0xe6, 0x7a, 0x4d, 0x73,
+	0x19, 0xb7, 0xe9, 0x63, 0xaa, 0x0f, 0xa0, 0x5e, 0x23, 0x66, 0x33, 0x24, 0xfb, 0x7e, 0x9d, 0x58,
+	0x7a, 0x3f, 0xef, 0x55, 0xfb, 0xae, 0xd7, 0xa8, 0xd3, 0xeb, 0x02, 0x7a, 0x0e, 0xde, 0x98, 0x63,
+	0x8f, 0xaa, 0xe4, 0xf0, 0x6b, 0x63, 0x84, 0x18, 0x63, 0x46, 0x7d, 0x73, 0x35, 0xe6, 0x31, 0xf4,
+	0x9c, 0x8f, 0x1a, 0x57, 0xda, 0x85, 0x7b, 0xf3, 0x07, 0xde, 0xde, 0xb2, 0x8b, 0xf0, 0x2d, 0x4b,
+	0xfd, 0xc1, 0x83, 0xfe, 0x61, 0x32, 0x2d, 0x4a, 0x95, 0x57, 0x31, 0xed, 0x1a, 0xa0, 0xda, 0xda,
+	0x1a, 0x68, 0xde, 0x5d, 0xb6, 0x0d, 0x4b, 0x18, 0x71, 0x7d, 0xb8, 0xdd, 0x8d, 0xd0, 0xb0, 0xb3,
+	0x13, 0xad, 0x77, 0xed, 0x04, 0x7f, 0x05, 0x9d, 0x83, 0x93, 0xe1, 0x17, 0x79, 0x36, 0x9d, 0x34,
+	0x7a, 0x6c, 0xdb, 0x5f, 0xdf, 0x69, 0x7f, 0xd7, 0x74, 0x2b, 0xa7, 0xbd, 0xc2, 0x21, 0x21, 0x72,
+	0x66, 0x4a, 0x09, 0x0e, 0xf9, 0x09, 0xac, 0x6b, 0x77, 0xb1, 0xe2, 0xdc, 0xa5, 0x2c, 0xda, 0xbe,
+	0x29, 0xa8, 0xfb, 0x26, 0x34, 0xaa, 0xab, 0xee, 0xff, 0xd3, 0xe8, 0x3f, 0x7c, 0x58, 0x17, 0xaa,
+	0x88, 0xbf, 0x51, 0xc3, 0xb4, 0x28, 0xf3, 0x69, 0x64, 0x2f, 0x8e, 0x9f, 0x65, 0x67, 0x66, 0x2f,
+	0x02, 0xa1, 0x85, 0x9b, 0x4f, 0x09, 0xe3, 0xd0, 0x76, 0x8b, 0x80, 0x3b, 0xc1, 0x2a, 0xd8, 0x63,
+	0x68, 0x9f, 0x64, 0xd3, 0x3c, 0xaa, 0x32, 0x9f, 0x2a, 0xb7, 0x5e, 0x5f, 0x2b, 0x84, 0x9d, 0xc0,
+	0xbe, 0x04, 0x76, 0x9a, 0xcb, 0xb4, 0x48, 0x24, 0x52, 0xb2, 0x9f, 0x75, 0xea, 0x86, 0xcc, 0xd1,
+	0xce, 0x59, 0x68, 0xf8, 0x8c, 0xed, 0xb9, 0x47, 0x38, 0x6c, 0x13, 0xbf, 0x55, 0xcb, 0x4f, 0xa3,
+	0xc2, 0x3d, 0xe4, 0x9f, 0x2e, 0x64, 0x68, 0xb8, 0x4c, 0x9f, 0xac, 0xd3, 0x65, 0xee, 0x2a, 0xc4,
+	0xfc, 0x3c, 0xfe, 0x3b, 0x0f, 0x56, 0x5c, 0x36, 0xb7, 0x94, 0x8b, 0x6a, 0xfb, 0xfc, 0xdb, 0xfb,
+	0x3b, 0xbb, 0x7d, 0xad, 0xa6, 0x5e, 0x7a, 0xc9, 0xed, 0xf9, 0x32, 0xf8, 0xf6, 0x3b, 0x82, 0x73,
+	0x27, 0x3a, 0x3b, 0xd0, 0x7b, 0x21, 0xf3, 0x32, 0x46, 0x63, 0xe6, 0x9e, 0x5e, 0x12, 0x2e, 0xc4,
+	0x15, 0xdc, 0x7f, 0x2b, 0x89, 0x0e, 0xb3, 0xf1, 0x04, 0xb3, 0xf5, 0x4e, 0xc9, 0x84, 0x65, 0x3a,
+	0xcf, 0xb3, 0xdc, 0x46, 0x80, 0x04, 0x7e, 0x00, 0x9d, 0xd3, 0x6c, 0x92, 0x25, 0xd9, 0xf9, 0xf5,
+	0x2d, 0x25, 0x23, 0x84, 0xb6, 0xbe, 0x1a, 0x74, 0x89, 0xea, 0x0a, 0x2b, 0xf2, 0x0f, 0x30, 0xdf,
+	0x23, 0x99, 0x44, 0xd3, 0x44, 0x96, 0x8a, 0x5e, 0x04, 0x04, 0x7e, 0x95, 0xc9, 0x91, 0xae, 0x0a,
+	0xe6, 0x68, 0xf1, 0x5f, 0x9a, 0x04, 0x94, 0xe4, 0x8e, 0x73, 0x05, 0x3d, 0x89, 0xdc, 0x5e, 0x4b,
+	0x4b, 0xec, 0x47, 0xd0, 0x73, 0x66, 0xbb, 0x0d, 0x9c, 0x03, 0x0b, 0x77, 0x0e, 0xff, 0x9b, 0x37,
+	0xf7, 0xcd, 0x5b, 0x77, 0xae, 0x59, 0xea, 0x52, 0x07, 0xa9, 0x23, 0x8c, 0x84, 0xae, 0x1f, 0xcd,
+	0xa2, 0x64, 0x5a, 0xa0, 0xca, 0x5c, 0xb8, 0x15, 0x80, 0xae, 0xe3, 0xe3, 0x30, 0x9b, 0xda, 0xe6,
+	0xc6, 0x8a, 0xf8, 0x8c, 0x1c, 0x28, 0x39, 0x4a, 0xe2, 0x54, 0x51, 0xbe, 0x04, 0xa2, 0x92, 0xd9,
+	0x63, 0x5d, 0x63, 0x6d, 0xa2, 0x6f, 0x2c, 0x10, 0x27, 0x9d, 0xae, 0xbc, 0x05, 0x67, 0xb0, 0xb6,
+	0xa8, 0xe2, 0x1b, 0xc0, 0x74, 0x06, 0x3c, 0x39, 0xcb, 0x72, 0x7b, 0xdb, 0xf2, 0x43, 0x5b, 0x5c,
+	0x30, 0xfa, 0xb7, 0x5d, 0xe2, 0x75, 0x64, 0x7d, 0x37, 0xb2, 0xfc, 0x17, 0xb0, 0x6a, 0x7a, 0x3b,
+	0x95, 0x53, 0x42, 0x63, 0x00, 0x84, 0x8a, 0x32, 0x6c, 0x13, 0xed, 0x3b, 0xae, 0x06, 0xd0, 0x0e,
+	0x35, 0xba, 0xf6, 0x76, 0x32, 0x12, 0xe2, 0x27, 0xf1, 0x79, 0xaa, 0x46, 0x74, 0x63, 0x04, 0xc2,
+	0x48, 0xfc, 0x4f, 0x3e, 0x6c, 0xe8, 0xa6, 0x33, 0x3d, 0x57, 0x45, 0x59, 0x2f, 0x43, 0x6d, 0x35,
+	0xd5, 0xff, 0xaa, 0xad, 0x46, 0x09, 0x1f, 0xd8, 0x87, 0x89, 0x92, 0x79, 0xcd, 0x41, 0x2f, 0xb4,
+	0x80, 0xe2, 0xb9, 0x21, 0xc4, 0x5c, 0xcf, 0xba, 0x09, 0x75, 0x21, 0x76, 0x00, 0x1d, 0xe3, 0x9a,
+	0x2d, 0x88, 0x1f, 0xd3, 0x2d, 0xd5, 0xc0, 0xc6, 0xf6, 0xb7, 0x85, 0x79, 0x75, 0x5a, 0x71, 0xeb,
+	0x18, 0xfa, 0x73, 0xaa, 0x86, 0x57, 0xe7, 0xae, 0xfb, 0xea, 0xec, 0xed, 0x33, 0xa7, 0x5d, 0x36,
+	0xd6, 0xdd, 0x97, 0xe8, 0x21, 0x7c, 0xd8, 0x44, 0xa0, 0x60, 0x8f, 0x21, 0x38, 0x9e, 0xe8, 0x80,
+	0xf7, 0xf6, 0xc3, 0x77, 0x11, 0x15, 0x38, 0x89, 0xff, 0xd5, 0x33, 0x41, 0x55, 0x46, 0x6f, 0xff,
+	0x1e, 0x7c, 0xe2, 0x1a, 0x79, 0x58, 0x19, 0x59, 0x98, 0xb6, 0x57, 0x39, 0x8a, 0xb3, 0xb7, 0xbe,
+	0x86, 0x4e, 0x93, 0x7b, 0x2d, 0xed, 0xde, 0x0f, 0xe7, 0xdd, 0xbb, 0xff, 0x2e, 0x66, 0x85, 0xeb,
+	0xe5, 0x1e, 0x6c, 0xea, 0xdb, 0x14, 0x7f, 0x2d, 0xfc, 0x2a, 0x97, 0x63, 0x75, 0xf3, 0xdb, 0xf8,
+	0xe7, 0x98, 0xe0, 0xa9, 0x1c, 0xdf, 0xfd, 0x59, 0x8a, 0x35, 0x49, 0x5d, 0x51, 0x8f, 0xa1, 0xab,
+	0x9a, 0x15, 0x0f, 0xd6, 0xfe, 0xfe, 0x66, 0xdb, 0xfb, 0xe7, 0x9b, 0x6d, 0xef, 0x5f, 0x6f, 0xb6,
+	0xbd, 0x3f, 0xff, 0x7b, 0xfb, 0x5b, 0x67, 0xcb, 0xf4, 0xeb, 0xef, 0x93, 0xff, 0x0e, 0x00, 0x9a,
+	0x93, 0xe1, 0xc0, 0x1d, 0x14, 0x00, 0x00,
 }
 
 func (m *IndexMeta) Marshal() (dAtA []byte, err error) {
@@ -3096,6 +3241,29 @@ func (m *IndexMeta) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.PrimaryKey) > 0 {
+		for iNdEx := len(m.PrimaryKey) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.PrimaryKey[iNdEx])
+			copy(dAtA[i:], m.PrimaryKey[iNdEx])
+			i = encodeVarintPrivate(dAtA, i, uint64(len(m.PrimaryKey[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if len(m.RetentionPeriod) > 0 {
+		i -= len(m.RetentionPeriod)
+		copy(dAtA[i:], m.RetentionPeriod)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.RetentionPeriod)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.RetentionField) > 0 {
+		i -= len(m.RetentionField)
+		copy(dAtA[i:], m.RetentionField)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.RetentionField)))
+		i--
+		dAtA[i] = 0x32
+	}
 	if len(m.Description) > 0 {
 		i -= len(m.Description)
 		copy(dAtA[i:], m.Description)
@@ -3150,6 +3318,78 @@ func (m *FieldOptions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Check) > 0 {
+		i -= len(m.Check)
+		copy(dAtA[i:], m.Check)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.Check)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xe2
+	}
+	if len(m.Default) > 0 {
+		i -= len(m.Default)
+		copy(dAtA[i:], m.Default)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.Default)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xda
+	}
+	if m.NotNull {
+		i--
+		if m.NotNull {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xd0
+	}
+	if m.BitVector != 0 {
+		i = encodeVarintPrivate(dAtA, i, uint64(m.BitVector))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc8
+	}
+	if m.GeoPoint {
+		i--
+		if m.GeoPoint {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc0
+	}
+	if len(m.JSONPaths) > 0 {
+		for iNdEx := len(m.JSONPaths) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.JSONPaths[iNdEx])
+			copy(dAtA[i:], m.JSONPaths[iNdEx])
+			i = encodeVarintPrivate(dAtA, i, uint64(len(m.JSONPaths[iNdEx])))
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0xba
+		}
+	}
+	if m.JSON {
+		i--
+		if m.JSON {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb0
+	}
 	if m.TrackExistence {
 		i--
 		if m.TrackExistence {
@@ -3871,6 +4111,54 @@ func (m *DeleteFieldMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *RenameFieldMessage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RenameFieldMessage) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RenameFieldMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.NewName) > 0 {
+		i -= len(m.NewName)
+		copy(dAtA[i:], m.NewName)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.NewName)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Field) > 0 {
+		i -= len(m.Field)
+		copy(dAtA[i:], m.Field)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.Field)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Index) > 0 {
+		i -= len(m.Index)
+		copy(dAtA[i:], m.Index)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.Index)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *DeleteAvailableShardMessage) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -5589,6 +5877,20 @@ func (m *IndexMeta) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovPrivate(uint64(l))
 	}
+	l = len(m.RetentionField)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	l = len(m.RetentionPeriod)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	if len(m.PrimaryKey) > 0 {
+		for _, s := range m.PrimaryKey {
+			l = len(s)
+			n += 1 + l + sovPrivate(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -5660,6 +5962,32 @@ func (m *FieldOptions) Size() (n int) {
 	if m.TrackExistence {
 		n += 3
 	}
+	if m.JSON {
+		n += 3
+	}
+	if len(m.JSONPaths) > 0 {
+		for _, s := range m.JSONPaths {
+			l = len(s)
+			n += 2 + l + sovPrivate(uint64(l))
+		}
+	}
+	if m.GeoPoint {
+		n += 3
+	}
+	if m.BitVector != 0 {
+		n += 2 + sovPrivate(uint64(m.BitVector))
+	}
+	if m.NotNull {
+		n += 3
+	}
+	l = len(m.Default)
+	if l > 0 {
+		n += 2 + l + sovPrivate(uint64(l))
+	}
+	l = len(m.Check)
+	if l > 0 {
+		n += 2 + l + sovPrivate(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -5934,6 +6262,30 @@ func (m *DeleteFieldMessage) Size() (n int) {
 	return n
 }
 
+func (m *RenameFieldMessage) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Index)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	l = len(m.NewName)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *DeleteAvailableShardMessage) Size() (n int) {
 	if m == nil {
 		return 0
@@ -6810,26 +7162,122 @@ func (m *IndexMeta) Unmarshal(dAtA []byte) error {
 			}
 			m.Description = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipPrivate(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthPrivate
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetentionField", wireType)
 			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RetentionField = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetentionPeriod", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RetentionPeriod = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PrimaryKey", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PrimaryKey = append(m.PrimaryKey, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPrivate(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
 	return nil
 }
 func (m *FieldOptions) Unmarshal(dAtA []byte) error {
@@ -7299,6 +7747,181 @@ func (m *FieldOptions) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.TrackExistence = bool(v != 0)
+		case 22:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JSON", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.JSON = bool(v != 0)
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JSONPaths", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JSONPaths = append(m.JSONPaths, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 24:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GeoPoint", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.GeoPoint = bool(v != 0)
+		case 25:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BitVector", wireType)
+			}
+			m.BitVector = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BitVector |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 26:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NotNull", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.NotNull = bool(v != 0)
+		case 27:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Default", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Default = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 28:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Check", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Check = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPrivate(dAtA[iNdEx:])
@@ -9025,6 +9648,153 @@ func (m *DeleteFieldMessage) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *RenameFieldMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPrivate
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RenameFieldMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RenameFieldMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Index = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NewName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPrivate(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *DeleteAvailableShardMessage) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -2671,11 +2671,12 @@ func (m *RoaringUpdate) GetClearRecords() bool {
 }
 
 type ImportRoaringShardRequest struct {
-	Remote               bool             `protobuf:"varint,1,opt,name=Remote,proto3" json:"Remote,omitempty"`
-	Views                []*RoaringUpdate `protobuf:"bytes,2,rep,name=Views,proto3" json:"Views,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Remote               bool              `protobuf:"varint,1,opt,name=Remote,proto3" json:"Remote,omitempty"`
+	Views                []*RoaringUpdate  `protobuf:"bytes,2,rep,name=Views,proto3" json:"Views,omitempty"`
+	Checkpoint           *IngestCheckpoint `protobuf:"bytes,3,opt,name=Checkpoint,proto3" json:"Checkpoint,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *ImportRoaringShardRequest) Reset()         { *m = ImportRoaringShardRequest{} }
@@ -2725,6 +2726,13 @@ func (m *ImportRoaringShardRequest) GetViews() []*RoaringUpdate {
 	return nil
 }
 
+func (m *ImportRoaringShardRequest) GetCheckpoint() *IngestCheckpoint {
+	if m != nil {
+		return m.Checkpoint
+	}
+	return nil
+}
+
 type GroupCounts struct {
 	Aggregate            string        `protobuf:"bytes,1,opt,name=Aggregate,proto3" json:"Aggregate,omitempty"`
 	Groups               []*GroupCount `protobuf:"bytes,2,rep,name=Groups,proto3" json:"Groups,omitempty"`
@@ -2874,6 +2882,124 @@ func (m *ArrowTable) GetData() []byte {
 	return nil
 }
 
+type IngestCheckpoint struct {
+	Key                  string          `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"`
+	Offsets              []*IngestOffset `protobuf:"bytes,2,rep,name=Offsets,proto3" json:"Offsets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *IngestCheckpoint) Reset()         { *m = IngestCheckpoint{} }
+func (m *IngestCheckpoint) String() string { return proto.CompactTextString(m) }
+func (*IngestCheckpoint) ProtoMessage()    {}
+func (*IngestCheckpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_413a91106d7bcce8, []int{41}
+}
+func (m *IngestCheckpoint) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *IngestCheckpoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_IngestCheckpoint.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *IngestCheckpoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IngestCheckpoint.Merge(m, src)
+}
+func (m *IngestCheckpoint) XXX_Size() int {
+	return m.Size()
+}
+func (m *IngestCheckpoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_IngestCheckpoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IngestCheckpoint proto.InternalMessageInfo
+
+func (m *IngestCheckpoint) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *IngestCheckpoint) GetOffsets() []*IngestOffset {
+	if m != nil {
+		return m.Offsets
+	}
+	return nil
+}
+
+type IngestOffset struct {
+	Source               string   `protobuf:"bytes,1,opt,name=Source,proto3" json:"Source,omitempty"`
+	Offset               uint64   `protobuf:"varint,2,opt,name=Offset,proto3" json:"Offset,omitempty"`
+	Position             string   `protobuf:"bytes,3,opt,name=Position,proto3" json:"Position,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IngestOffset) Reset()         { *m = IngestOffset{} }
+func (m *IngestOffset) String() string { return proto.CompactTextString(m) }
+func (*IngestOffset) ProtoMessage()    {}
+func (*IngestOffset) Descriptor() ([]byte, []int) {
+	return fileDescriptor_413a91106d7bcce8, []int{42}
+}
+func (m *IngestOffset) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *IngestOffset) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_IngestOffset.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *IngestOffset) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IngestOffset.Merge(m, src)
+}
+func (m *IngestOffset) XXX_Size() int {
+	return m.Size()
+}
+func (m *IngestOffset) XXX_DiscardUnknown() {
+	xxx_messageInfo_IngestOffset.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IngestOffset proto.InternalMessageInfo
+
+func (m *IngestOffset) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *IngestOffset) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *IngestOffset) GetPosition() string {
+	if m != nil {
+		return m.Position
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Row)(nil), "pb.Row")
 	proto.RegisterType((*RowMatrix)(nil), "pb.RowMatrix")
@@ -2916,128 +3042,134 @@ func init() {
 	proto.RegisterType((*GroupCounts)(nil), "pb.GroupCounts")
 	proto.RegisterType((*DataFrame)(nil), "pb.DataFrame")
 	proto.RegisterType((*ArrowTable)(nil), "pb.ArrowTable")
+	proto.RegisterType((*IngestCheckpoint)(nil), "pb.IngestCheckpoint")
+	proto.RegisterType((*IngestOffset)(nil), "pb.IngestOffset")
 }
 
 func init() { proto.RegisterFile("public.proto", fileDescriptor_413a91106d7bcce8) }
 
 var fileDescriptor_413a91106d7bcce8 = []byte{
-	// 1844 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x18, 0x4d, 0x73, 0x23, 0x47,
-	0xd5, 0xa3, 0xd1, 0xe7, 0x93, 0xec, 0xb5, 0x7b, 0x9d, 0xcd, 0x64, 0xe3, 0x38, 0xda, 0x29, 0x2a,
-	0x28, 0x18, 0x36, 0x85, 0xa1, 0x52, 0x54, 0xaa, 0x20, 0x65, 0xaf, 0xbc, 0xac, 0xca, 0xbb, 0xce,
-	0xd2, 0xda, 0x88, 0x4b, 0x2e, 0x63, 0xa9, 0x51, 0xa6, 0x18, 0x69, 0xc4, 0xcc, 0x28, 0xb2, 0x8f,
-	0x1c, 0x28, 0xf8, 0x09, 0x9c, 0xe0, 0xd7, 0x50, 0x70, 0x83, 0x23, 0x47, 0x6a, 0xf9, 0x23, 0xd4,
-	0x7b, 0xaf, 0x7b, 0xa6, 0x47, 0x92, 0xb7, 0xc2, 0x56, 0x6e, 0xfd, 0x3e, 0xfa, 0xf5, 0xfb, 0x7e,
-	0x6f, 0x06, 0x3a, 0x8b, 0xe5, 0x75, 0x14, 0x8e, 0x1f, 0x2f, 0x92, 0x38, 0x8b, 0x45, 0x65, 0x71,
-	0xed, 0xdf, 0x82, 0x2b, 0xe3, 0x95, 0xf0, 0xa0, 0xf1, 0x24, 0x8e, 0x96, 0xb3, 0x79, 0xea, 0x39,
-	0x5d, 0xb7, 0x57, 0x95, 0x06, 0x14, 0x02, 0xaa, 0x97, 0xea, 0x36, 0xf5, 0xdc, 0xae, 0xdb, 0x6b,
-	0x49, 0x3a, 0x23, 0xb7, 0x8c, 0x83, 0x24, 0x9c, 0x4f, 0xbd, 0x6a, 0xd7, 0xe9, 0x75, 0xa4, 0x01,
-	0xc5, 0x21, 0xd4, 0x06, 0xf3, 0x89, 0xba, 0xf1, 0x6a, 0x5d, 0xa7, 0xd7, 0x92, 0x0c, 0x20, 0xf6,
-	0x69, 0xa8, 0xa2, 0x89, 0x57, 0x67, 0x2c, 0x01, 0x7e, 0x0f, 0x5a, 0x32, 0x5e, 0xbd, 0x08, 0xb2,
-	0x24, 0xbc, 0x11, 0xef, 0x43, 0x55, 0xc6, 0x2b, 0x7e, 0xbd, 0x7d, 0xda, 0x78, 0xbc, 0xb8, 0x7e,
-	0x2c, 0xe3, 0x95, 0x24, 0xa4, 0x7f, 0x06, 0xad, 0x61, 0x38, 0x9d, 0xab, 0x09, 0xaa, 0xfa, 0x1e,
-	0xb8, 0x2f, 0x63, 0x64, 0x74, 0x6c, 0x46, 0xc4, 0x21, 0xe9, 0x4a, 0x4d, 0xbd, 0xca, 0x1a, 0xe9,
-	0x4a, 0x4d, 0xfd, 0x9f, 0xc1, 0x9e, 0x8c, 0x57, 0x83, 0x89, 0x9a, 0x67, 0xe1, 0x6f, 0x42, 0x95,
-	0x90, 0x61, 0xf9, 0x8b, 0x55, 0x7e, 0x28, 0x37, 0xb6, 0x52, 0x18, 0xeb, 0x3f, 0x84, 0xfa, 0xa0,
-	0xff, 0x3c, 0x4c, 0x33, 0xb1, 0x0f, 0xee, 0xa0, 0x6f, 0x2e, 0xe0, 0xd1, 0x7f, 0x02, 0x07, 0x17,
-	0x37, 0x59, 0x12, 0x8c, 0x33, 0x35, 0x19, 0xf4, 0xd9, 0x65, 0x62, 0x0f, 0x2a, 0x83, 0x3e, 0xe9,
-	0x57, 0x95, 0x95, 0x41, 0x5f, 0x1c, 0x43, 0x75, 0x14, 0x44, 0x2c, 0xb4, 0x7d, 0x0a, 0xa8, 0x16,
-	0x0b, 0x94, 0x84, 0xf7, 0x7f, 0xef, 0xc0, 0xbb, 0x96, 0x14, 0x76, 0xc8, 0x30, 0x4e, 0x32, 0x35,
-	0x11, 0xe5, 0x07, 0x98, 0xa4, 0x4d, 0x7f, 0x07, 0x05, 0x6d, 0x10, 0xe5, 0x26, 0xbf, 0x78, 0x04,
-	0x75, 0x19, 0xaf, 0x2e, 0x47, 0x46, 0x85, 0x96, 0xf6, 0xcc, 0xe5, 0x48, 0x6a, 0x82, 0xff, 0x1c,
-	0x6a, 0x74, 0xc2, 0x50, 0xa1, 0x9f, 0x8c, 0xfe, 0x0c, 0x88, 0x1f, 0x41, 0x6d, 0x14, 0x44, 0x4b,
-	0xa5, 0x5d, 0xfb, 0x6e, 0xe9, 0xe9, 0x57, 0xc1, 0x75, 0xa4, 0x88, 0x2c, 0x99, 0xcb, 0xff, 0x6a,
-	0x8b, 0xd6, 0xe2, 0x01, 0xd4, 0x29, 0xee, 0xec, 0xc0, 0x96, 0xd4, 0x90, 0xf8, 0xa4, 0x48, 0x3d,
-	0x56, 0x6f, 0xdd, 0x30, 0xa6, 0xe6, 0x19, 0xe9, 0x7f, 0x00, 0x8d, 0x4b, 0x75, 0x4b, 0x11, 0x31,
-	0xf1, 0x72, 0xac, 0x78, 0xfd, 0xd3, 0x81, 0xfb, 0x5b, 0x74, 0x13, 0xc7, 0x26, 0x7a, 0x4e, 0x39,
-	0x0a, 0xcf, 0x76, 0x28, 0x96, 0xe2, 0x51, 0x1e, 0x7b, 0x64, 0x68, 0x23, 0x83, 0x7e, 0xe6, 0xd9,
-	0x8e, 0xce, 0xfb, 0x23, 0x68, 0x9e, 0x0f, 0x07, 0xec, 0x09, 0xb7, 0xeb, 0xf4, 0xdc, 0x67, 0x3b,
-	0x32, 0xc7, 0x88, 0x87, 0xd0, 0x78, 0xb1, 0xcc, 0xd4, 0xcd, 0xa0, 0x4f, 0x55, 0x51, 0x7d, 0xb6,
-	0x23, 0x0d, 0x02, 0x6f, 0xd2, 0xf1, 0x52, 0xdd, 0x72, 0x69, 0xe0, 0x4d, 0x83, 0x11, 0x87, 0x50,
-	0x3d, 0x8f, 0xe3, 0x88, 0xca, 0xa3, 0x89, 0xaf, 0x21, 0x74, 0xde, 0xd0, 0x4e, 0xf7, 0x6f, 0xe0,
-	0xb0, 0x6c, 0x90, 0x4e, 0x34, 0x01, 0x2e, 0xca, 0x73, 0xb4, 0x3c, 0x04, 0xc4, 0x3e, 0x25, 0x5f,
-	0x45, 0xbf, 0x8f, 0xe9, 0xf7, 0x09, 0xd4, 0x49, 0x0c, 0x97, 0xf0, 0x1b, 0x82, 0xa7, 0xd9, 0xce,
-	0x5b, 0xe4, 0xdf, 0x2f, 0x92, 0x41, 0xdf, 0xff, 0xf9, 0xba, 0x2b, 0x29, 0x66, 0xe8, 0xf6, 0xab,
-	0x60, 0xa6, 0xf8, 0x65, 0x49, 0x67, 0xc4, 0xbd, 0xba, 0x5d, 0x70, 0x86, 0xb4, 0x24, 0x9d, 0xfd,
-	0x25, 0xec, 0x95, 0xaf, 0xa3, 0x32, 0x56, 0x12, 0x6c, 0x55, 0x86, 0xe8, 0x79, 0x76, 0x9c, 0xae,
-	0x67, 0x87, 0xb7, 0x79, 0x63, 0x3d, 0x41, 0x7e, 0x01, 0xd5, 0x97, 0x41, 0x98, 0x6c, 0x14, 0xe2,
-	0x3e, 0xfb, 0xcb, 0x25, 0x0d, 0x5d, 0x76, 0x7c, 0xed, 0x49, 0xbc, 0x9c, 0x67, 0xec, 0x30, 0xc9,
-	0x80, 0xff, 0x39, 0xb4, 0xf0, 0x3e, 0xdb, 0x7a, 0xc4, 0xc2, 0x74, 0xde, 0x34, 0xf1, 0x75, 0x84,
-	0x25, 0x3f, 0x91, 0x77, 0xb6, 0x8a, 0xdd, 0xd9, 0xce, 0x01, 0x90, 0x9a, 0xb2, 0x84, 0x63, 0xa8,
-	0x11, 0xa4, 0x4d, 0x2e, 0x44, 0x30, 0xfa, 0x0e, 0x19, 0x1f, 0x60, 0x27, 0xcd, 0x3e, 0xfd, 0x29,
-	0x92, 0x39, 0xe3, 0x50, 0x03, 0xd7, 0x94, 0x58, 0x0c, 0x4d, 0x76, 0x54, 0xbc, 0x2a, 0x04, 0x38,
-	0x96, 0x80, 0xa2, 0x92, 0x2b, 0x76, 0x25, 0x3f, 0xe0, 0x5e, 0x90, 0xbb, 0x41, 0x43, 0xe2, 0x43,
-	0xf3, 0x4a, 0x95, 0xec, 0xa4, 0x16, 0x41, 0xef, 0x9b, 0x07, 0xff, 0xe0, 0x00, 0xfc, 0x32, 0x89,
-	0x97, 0x0b, 0xf2, 0x91, 0xf0, 0xa1, 0x46, 0x90, 0x36, 0xaa, 0x83, 0xfc, 0x46, 0x21, 0xc9, 0xa4,
-	0xed, 0xde, 0xc5, 0x28, 0x9c, 0x4d, 0xa7, 0x5c, 0x3f, 0x12, 0x8f, 0xe2, 0x04, 0xa0, 0xaf, 0xc6,
-	0xe1, 0x2c, 0x88, 0x90, 0x50, 0x2d, 0xea, 0x4f, 0x63, 0xa5, 0x45, 0xf6, 0xff, 0xea, 0x40, 0x73,
-	0x14, 0x44, 0xb9, 0xac, 0x51, 0x10, 0x69, 0xcf, 0xe0, 0xb1, 0xfc, 0xa6, 0x6b, 0xde, 0x7c, 0x08,
-	0xcd, 0xa7, 0x51, 0x1c, 0x64, 0xc8, 0x8c, 0x0f, 0x3b, 0x32, 0x87, 0xad, 0xd7, 0x91, 0xfa, 0x86,
-	0xd7, 0x91, 0xd9, 0x87, 0xce, 0xab, 0x70, 0xa6, 0xd2, 0x2c, 0x98, 0x2d, 0x90, 0x9d, 0xc7, 0x5c,
-	0x09, 0x87, 0x9e, 0x6a, 0xe8, 0x2b, 0xdb, 0x83, 0x87, 0xd8, 0xe1, 0x38, 0x88, 0x94, 0x51, 0x92,
-	0x00, 0x71, 0x0c, 0x70, 0xa5, 0x56, 0x23, 0x95, 0xa4, 0x61, 0x3c, 0x27, 0x35, 0x9b, 0xd2, 0xc2,
-	0x60, 0xe8, 0x46, 0x41, 0x74, 0x76, 0x9d, 0xea, 0xa1, 0xab, 0x21, 0x8d, 0xc7, 0xc1, 0x57, 0xa3,
-	0x3b, 0x1a, 0xf2, 0x3f, 0x87, 0x83, 0x7e, 0x98, 0x66, 0xe1, 0x7c, 0x9c, 0xe5, 0xfa, 0x69, 0x66,
-	0xec, 0x06, 0xba, 0x0b, 0x33, 0x94, 0x97, 0x74, 0xa5, 0x28, 0x69, 0xff, 0x6f, 0x0e, 0x74, 0x7e,
-	0xb5, 0x54, 0xc9, 0xad, 0x54, 0xbf, 0x5b, 0xaa, 0x34, 0x43, 0xbd, 0x09, 0x36, 0x89, 0x46, 0x00,
-	0x8a, 0x1c, 0x7e, 0x1d, 0x24, 0x13, 0xae, 0xd0, 0xaa, 0xd4, 0x10, 0xa5, 0x9a, 0x9a, 0xc5, 0x99,
-	0x32, 0x7a, 0x31, 0x24, 0x4e, 0xa0, 0x73, 0x31, 0xbb, 0x56, 0x93, 0x89, 0x9a, 0xf4, 0x83, 0x2c,
-	0xf0, 0x9a, 0xe5, 0x91, 0x5f, 0x22, 0x8a, 0xef, 0xc1, 0xee, 0xcb, 0x44, 0xbd, 0x4a, 0x82, 0x79,
-	0x1a, 0x05, 0x99, 0x9a, 0x78, 0x2d, 0x92, 0x55, 0x46, 0x8a, 0x23, 0x68, 0xbd, 0x08, 0x6e, 0x5e,
-	0xa8, 0x59, 0x9c, 0xdc, 0x7a, 0x40, 0x4e, 0x2d, 0x10, 0xfe, 0x73, 0xd8, 0xd5, 0x66, 0xa4, 0x8b,
-	0x78, 0x9e, 0x2a, 0x4c, 0x9b, 0x8b, 0x24, 0xd1, 0x56, 0xe0, 0x51, 0x7c, 0x0c, 0x0d, 0xa9, 0xd2,
-	0x65, 0x94, 0x99, 0x36, 0x73, 0x0f, 0xd5, 0x31, 0xb7, 0x96, 0x51, 0x26, 0x0d, 0xdd, 0xff, 0x4b,
-	0x03, 0xda, 0x16, 0x21, 0x6f, 0x7c, 0xd8, 0xbc, 0x77, 0xb9, 0xf1, 0xe1, 0x22, 0x22, 0xe3, 0xd5,
-	0xc6, 0x8e, 0x82, 0xc5, 0xda, 0x01, 0xe7, 0x4a, 0x17, 0x84, 0x73, 0x55, 0xf4, 0x06, 0x77, 0x7b,
-	0x6f, 0xc0, 0xbd, 0xec, 0xeb, 0x60, 0x3e, 0x55, 0x13, 0x0a, 0x7a, 0x53, 0x1a, 0x50, 0xf4, 0x8a,
-	0x32, 0x20, 0xff, 0xea, 0x1a, 0x34, 0x38, 0x59, 0x14, 0x09, 0x97, 0x3c, 0xce, 0xbe, 0x06, 0xc7,
-	0x87, 0x21, 0xf1, 0x29, 0xec, 0x7d, 0x11, 0x4d, 0x8a, 0x9a, 0x4e, 0x75, 0x24, 0xf6, 0x50, 0x4e,
-	0x81, 0x96, 0x6b, 0x5c, 0xe2, 0xb3, 0xf5, 0x55, 0x8a, 0x62, 0xd2, 0x3e, 0x15, 0xda, 0x4e, 0x8b,
-	0x22, 0xd7, 0x97, 0xae, 0x13, 0x6b, 0x93, 0xa3, 0x40, 0xb5, 0x4f, 0x77, 0xf1, 0x5a, 0x8e, 0x94,
-	0xd6, 0xa6, 0xf7, 0xd8, 0x6e, 0xa3, 0x5e, 0x9b, 0xb8, 0xf7, 0x8c, 0x87, 0x18, 0x2b, 0xed, 0x46,
-	0x7b, 0x62, 0xf5, 0x6d, 0xaf, 0x53, 0x08, 0xcf, 0x91, 0xd2, 0xea, 0xeb, 0x5b, 0x37, 0xab, 0xdd,
-	0xff, 0x73, 0xb3, 0xfa, 0x6c, 0x7d, 0xc0, 0x79, 0x7b, 0x85, 0x2b, 0xca, 0x14, 0xb9, 0x3e, 0x0a,
-	0x4f, 0xac, 0xf5, 0xd7, 0xbb, 0x57, 0x68, 0x9b, 0x23, 0xa5, 0xb5, 0x1e, 0xff, 0x18, 0xda, 0x76,
-	0xa0, 0xf6, 0x89, 0xfd, 0x5e, 0x39, 0x50, 0xa9, 0xb4, 0x79, 0xd0, 0xc0, 0x8d, 0xf2, 0xf7, 0x0e,
-	0x0a, 0x03, 0x37, 0x88, 0x72, 0x4b, 0xbb, 0x38, 0x81, 0x16, 0x96, 0xe1, 0xd3, 0x04, 0x7b, 0x83,
-	0x28, 0x94, 0xcc, 0x91, 0xb2, 0xa0, 0x63, 0xbc, 0xce, 0x92, 0x24, 0x5e, 0xb1, 0x27, 0xee, 0x17,
-	0xf1, 0x2a, 0xb0, 0xd2, 0xe2, 0x10, 0x5f, 0xde, 0xb9, 0xf7, 0x7a, 0x87, 0x74, 0xf9, 0xfd, 0xad,
-	0x81, 0x60, 0x16, 0x79, 0xd7, 0x5d, 0xff, 0xef, 0x15, 0xd8, 0x1d, 0xcc, 0x16, 0x71, 0x92, 0x59,
-	0x7d, 0x8b, 0xbf, 0x4a, 0x9c, 0xad, 0x5f, 0x25, 0x95, 0xb5, 0xb1, 0x49, 0xfd, 0x8b, 0x1a, 0x70,
-	0x55, 0x32, 0x60, 0xd5, 0x50, 0xb5, 0x54, 0x43, 0x47, 0xd0, 0xe2, 0xad, 0x03, 0x49, 0x35, 0x22,
-	0x15, 0x08, 0xfe, 0x4e, 0x5a, 0xd1, 0x56, 0xd9, 0xa0, 0x6e, 0x6b, 0x40, 0xec, 0xf5, 0xcc, 0x46,
-	0xc4, 0x26, 0x11, 0x2d, 0x0c, 0xd2, 0xf3, 0x20, 0xa4, 0x5e, 0xbd, 0xeb, 0xf6, 0x5c, 0x69, 0x61,
-	0xc4, 0x47, 0xb0, 0x47, 0x46, 0x3c, 0x49, 0x14, 0x36, 0xc0, 0xb3, 0x8c, 0x6a, 0xd0, 0x95, 0x6b,
-	0x58, 0xe4, 0x23, 0xb3, 0x0a, 0x3e, 0xee, 0x8e, 0x6b, 0x58, 0x1a, 0x9b, 0x91, 0x0a, 0x12, 0xaa,
-	0xb2, 0xa6, 0x64, 0xc0, 0xff, 0x77, 0x05, 0x04, 0x7b, 0x92, 0x37, 0xc4, 0xef, 0xcc, 0x9d, 0x6f,
-	0x76, 0x5b, 0xd9, 0x39, 0x8d, 0x0d, 0xe7, 0x14, 0x33, 0x8c, 0x1d, 0x63, 0x66, 0x58, 0x17, 0xda,
-	0x66, 0xaa, 0x23, 0x11, 0xbd, 0xea, 0x48, 0x1b, 0x85, 0xe3, 0x7b, 0x98, 0xe1, 0x87, 0xaa, 0x66,
-	0x69, 0x91, 0xec, 0x12, 0x6e, 0x8b, 0x6b, 0xe1, 0x5b, 0xba, 0xb6, 0xfd, 0x66, 0xd7, 0x76, 0x6c,
-	0xd7, 0xfe, 0xd1, 0x81, 0xce, 0x59, 0x16, 0xcf, 0xc2, 0xb1, 0x54, 0xe3, 0x38, 0x99, 0xdc, 0xed,
-	0x54, 0x76, 0x5f, 0xc5, 0x76, 0x5f, 0x0f, 0xdc, 0xc1, 0x37, 0x89, 0x9e, 0x19, 0x0f, 0x68, 0x55,
-	0xdb, 0x88, 0x92, 0x44, 0x16, 0xf1, 0x08, 0x2a, 0x83, 0x84, 0x72, 0xb6, 0x7d, 0x7a, 0x50, 0x30,
-	0x1a, 0x9e, 0xca, 0x20, 0xf1, 0x7f, 0x08, 0x87, 0xac, 0x88, 0x21, 0xe9, 0x21, 0x79, 0x08, 0xb5,
-	0x8b, 0x24, 0x89, 0xcd, 0x98, 0x64, 0x00, 0xbf, 0x45, 0xf2, 0xb9, 0x8b, 0xc1, 0x78, 0x9b, 0x9c,
-	0xd8, 0xf6, 0x4b, 0xa1, 0x0b, 0xed, 0xab, 0x38, 0xfb, 0x75, 0x12, 0x66, 0xd4, 0x3c, 0x78, 0xd8,
-	0xd9, 0x28, 0xff, 0x63, 0x78, 0x67, 0xed, 0xe5, 0x62, 0x9a, 0x63, 0x1a, 0xb9, 0xc5, 0x67, 0xf9,
-	0x10, 0xee, 0xe7, 0xac, 0x83, 0xfe, 0x5b, 0xe9, 0xb8, 0x29, 0xf4, 0x07, 0x96, 0xe5, 0x24, 0x54,
-	0x3f, 0xbf, 0xc5, 0x1a, 0xff, 0x1c, 0x3c, 0xed, 0x4d, 0xfe, 0x2f, 0xa2, 0x35, 0x18, 0x85, 0x6a,
-	0x75, 0xd7, 0xc7, 0x13, 0xad, 0x42, 0x15, 0x5a, 0xec, 0xe8, 0xec, 0xff, 0xa9, 0x02, 0x87, 0xdb,
-	0x84, 0x14, 0x09, 0xe5, 0x58, 0x09, 0x25, 0x4e, 0xa1, 0xf6, 0x4d, 0xa8, 0x56, 0x66, 0x7f, 0x39,
-	0xb2, 0x82, 0xbd, 0xa1, 0x83, 0x64, 0x56, 0x2c, 0xa4, 0xb3, 0x71, 0x66, 0xb6, 0xcd, 0x96, 0xd4,
-	0x10, 0xbe, 0x70, 0x1e, 0xc5, 0xe3, 0xdf, 0xf2, 0x77, 0xac, 0x64, 0x60, 0x4b, 0x61, 0xd4, 0xbe,
-	0x65, 0x61, 0xd4, 0xb7, 0x16, 0x46, 0x0f, 0xee, 0x7d, 0xb9, 0x98, 0x04, 0x99, 0xba, 0xb8, 0x09,
-	0xd3, 0x4c, 0xcd, 0xc7, 0xca, 0x6b, 0x90, 0x45, 0xeb, 0x68, 0xdc, 0xa8, 0x77, 0xb5, 0x15, 0x4c,
-	0xba, 0xe3, 0x93, 0x47, 0x40, 0x15, 0xcd, 0x33, 0x4b, 0x2c, 0xb9, 0x3b, 0xf7, 0x96, 0x4b, 0xbe,
-	0xd5, 0xde, 0xda, 0x07, 0x77, 0xa8, 0x32, 0xbd, 0x48, 0xe3, 0x11, 0x5b, 0x03, 0x91, 0xb8, 0x1c,
-	0x53, 0xbd, 0xb3, 0x96, 0x70, 0xfe, 0x57, 0xf0, 0x5e, 0xc9, 0xa5, 0x54, 0x8d, 0x26, 0x2c, 0xc5,
-	0xba, 0xeb, 0x94, 0xd6, 0xdd, 0xef, 0x43, 0x6d, 0x64, 0x05, 0xe6, 0x80, 0x67, 0xbc, 0x65, 0x8c,
-	0x64, 0xba, 0x3f, 0x2c, 0xcd, 0x78, 0xec, 0x91, 0x67, 0xd3, 0x69, 0xa2, 0xa6, 0x41, 0x66, 0x92,
-	0xa5, 0x40, 0x88, 0x8f, 0xa0, 0x4e, 0xcc, 0x46, 0xec, 0xfa, 0xd2, 0xa6, 0xa9, 0xfe, 0x87, 0xd6,
-	0x00, 0xcf, 0xd3, 0xcc, 0xb1, 0xd2, 0xac, 0x6b, 0x0f, 0xed, 0x6d, 0x1c, 0xe7, 0xfb, 0xff, 0x78,
-	0x7d, 0xec, 0xfc, 0xeb, 0xf5, 0xb1, 0xf3, 0x9f, 0xd7, 0xc7, 0xce, 0x9f, 0xff, 0x7b, 0xbc, 0x73,
-	0x5d, 0xa7, 0xff, 0x87, 0x3f, 0xf9, 0x5f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x49, 0x89, 0x63, 0x75,
-	0x4f, 0x14, 0x00, 0x00,
+	// 1920 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x18, 0x4d, 0x6f, 0x1b, 0xc7,
+	0xd5, 0xcb, 0xe5, 0xe7, 0x23, 0x25, 0x4b, 0x63, 0xc6, 0xd9, 0x38, 0x8a, 0x42, 0x2f, 0x8a, 0x94,
+	0x89, 0x5b, 0x07, 0x55, 0x83, 0xa0, 0x08, 0xd0, 0x06, 0x92, 0x28, 0xd7, 0x84, 0x6c, 0x59, 0x1d,
+	0x3a, 0x2c, 0x50, 0xf4, 0xb2, 0x22, 0xc7, 0xf4, 0x22, 0x4b, 0x2e, 0xbb, 0xbb, 0x0c, 0xa5, 0x63,
+	0x0f, 0x45, 0x7b, 0xee, 0xa9, 0xa7, 0xf6, 0xd7, 0x14, 0xed, 0xad, 0x3d, 0xf6, 0x58, 0xb8, 0x7f,
+	0xa4, 0x78, 0xf3, 0x66, 0x76, 0x66, 0xc9, 0x95, 0x91, 0x06, 0xbd, 0xed, 0xfb, 0x98, 0xf7, 0xfd,
+	0xde, 0xbc, 0x59, 0xe8, 0x2c, 0x57, 0x57, 0x51, 0x38, 0x79, 0xbc, 0x4c, 0xe2, 0x2c, 0x66, 0x95,
+	0xe5, 0x95, 0x7f, 0x03, 0x2e, 0x8f, 0xd7, 0xcc, 0x83, 0xc6, 0x69, 0x1c, 0xad, 0xe6, 0x8b, 0xd4,
+	0x73, 0x7a, 0x6e, 0xbf, 0xca, 0x35, 0xc8, 0x18, 0x54, 0xcf, 0xc5, 0x4d, 0xea, 0xb9, 0x3d, 0xb7,
+	0xdf, 0xe2, 0xf2, 0x1b, 0xb9, 0x79, 0x1c, 0x24, 0xe1, 0x62, 0xe6, 0x55, 0x7b, 0x4e, 0xbf, 0xc3,
+	0x35, 0xc8, 0xba, 0x50, 0x1b, 0x2e, 0xa6, 0xe2, 0xda, 0xab, 0xf5, 0x9c, 0x7e, 0x8b, 0x13, 0x80,
+	0xd8, 0x27, 0xa1, 0x88, 0xa6, 0x5e, 0x9d, 0xb0, 0x12, 0xf0, 0xfb, 0xd0, 0xe2, 0xf1, 0xfa, 0x79,
+	0x90, 0x25, 0xe1, 0x35, 0x7b, 0x1f, 0xaa, 0x3c, 0x5e, 0x93, 0xf6, 0xf6, 0x51, 0xe3, 0xf1, 0xf2,
+	0xea, 0x31, 0x8f, 0xd7, 0x5c, 0x22, 0xfd, 0x63, 0x68, 0x8d, 0xc2, 0xd9, 0x42, 0x4c, 0xd1, 0xd4,
+	0xf7, 0xc0, 0xbd, 0x8c, 0x91, 0xd1, 0xb1, 0x19, 0x11, 0x87, 0xa4, 0x0b, 0x31, 0xf3, 0x2a, 0x1b,
+	0xa4, 0x0b, 0x31, 0xf3, 0x7f, 0x02, 0xbb, 0x3c, 0x5e, 0x0f, 0xa7, 0x62, 0x91, 0x85, 0xaf, 0x42,
+	0x91, 0x48, 0xc7, 0x72, 0x8d, 0x55, 0x52, 0x94, 0x3b, 0x5b, 0x31, 0xce, 0xfa, 0x0f, 0xa0, 0x3e,
+	0x1c, 0x3c, 0x0b, 0xd3, 0x8c, 0xed, 0x81, 0x3b, 0x1c, 0xe8, 0x03, 0xf8, 0xe9, 0x9f, 0xc2, 0xfe,
+	0xd9, 0x75, 0x96, 0x04, 0x93, 0x4c, 0x4c, 0x87, 0x03, 0x0a, 0x19, 0xdb, 0x85, 0xca, 0x70, 0x20,
+	0xed, 0xab, 0xf2, 0xca, 0x70, 0xc0, 0x0e, 0xa1, 0x3a, 0x0e, 0x22, 0x12, 0xda, 0x3e, 0x02, 0x34,
+	0x8b, 0x04, 0x72, 0x89, 0xf7, 0x7f, 0xeb, 0xc0, 0xbb, 0x96, 0x14, 0x0a, 0xc8, 0x28, 0x4e, 0x32,
+	0x31, 0x65, 0x45, 0x05, 0x44, 0x52, 0xae, 0xbf, 0x83, 0x82, 0xb6, 0x88, 0x7c, 0x9b, 0x9f, 0x3d,
+	0x84, 0x3a, 0x8f, 0xd7, 0xe7, 0x63, 0x6d, 0x42, 0x4b, 0x45, 0xe6, 0x7c, 0xcc, 0x15, 0xc1, 0x7f,
+	0x06, 0x35, 0xf9, 0x85, 0xa9, 0xc2, 0x38, 0x69, 0xfb, 0x09, 0x60, 0x3f, 0x84, 0xda, 0x38, 0x88,
+	0x56, 0x42, 0x85, 0xf6, 0xdd, 0x82, 0xea, 0x97, 0xc1, 0x55, 0x24, 0x24, 0x99, 0x13, 0x97, 0xff,
+	0xeb, 0x12, 0xab, 0xd9, 0x7d, 0xa8, 0xcb, 0xbc, 0x53, 0x00, 0x5b, 0x5c, 0x41, 0xec, 0x53, 0x53,
+	0x7a, 0x64, 0xde, 0xa6, 0x63, 0x44, 0xcd, 0x2b, 0xd2, 0xff, 0x00, 0x1a, 0xe7, 0xe2, 0x46, 0x66,
+	0x44, 0xe7, 0xcb, 0xb1, 0xf2, 0xf5, 0x0f, 0x07, 0xee, 0x95, 0xd8, 0xc6, 0x0e, 0x75, 0xf6, 0x9c,
+	0x62, 0x16, 0x9e, 0xde, 0x91, 0xb9, 0x64, 0x0f, 0xf3, 0xdc, 0x23, 0x43, 0x1b, 0x19, 0x94, 0x9a,
+	0xa7, 0x77, 0x54, 0xdd, 0x1f, 0x40, 0xf3, 0x64, 0x34, 0xa4, 0x48, 0xb8, 0x3d, 0xa7, 0xef, 0x3e,
+	0xbd, 0xc3, 0x73, 0x0c, 0x7b, 0x00, 0x8d, 0xe7, 0xab, 0x4c, 0x5c, 0x0f, 0x07, 0xb2, 0x2b, 0xaa,
+	0x4f, 0xef, 0x70, 0x8d, 0xc0, 0x93, 0xf2, 0xf3, 0x5c, 0xdc, 0x50, 0x6b, 0xe0, 0x49, 0x8d, 0x61,
+	0x5d, 0xa8, 0x9e, 0xc4, 0x71, 0x24, 0xdb, 0xa3, 0x89, 0xda, 0x10, 0x3a, 0x69, 0xa8, 0xa0, 0xfb,
+	0xd7, 0xd0, 0x2d, 0x3a, 0xa4, 0x0a, 0x8d, 0x81, 0x8b, 0xf2, 0x1c, 0x25, 0x0f, 0x01, 0xb6, 0x27,
+	0x8b, 0xaf, 0xa2, 0xf4, 0x63, 0xf9, 0x7d, 0x0a, 0x75, 0x29, 0x86, 0x5a, 0xf8, 0x2d, 0xc9, 0x53,
+	0x6c, 0x27, 0x2d, 0x19, 0xdf, 0x17, 0xc9, 0x70, 0xe0, 0xff, 0x74, 0x33, 0x94, 0x32, 0x67, 0x18,
+	0xf6, 0x8b, 0x60, 0x2e, 0x48, 0x33, 0x97, 0xdf, 0x88, 0x7b, 0x79, 0xb3, 0xa4, 0x0a, 0x69, 0x71,
+	0xf9, 0xed, 0xaf, 0x60, 0xb7, 0x78, 0x1c, 0x8d, 0xb1, 0x8a, 0xa0, 0xd4, 0x18, 0x49, 0xcf, 0xab,
+	0xe3, 0x68, 0xb3, 0x3a, 0xbc, 0xed, 0x13, 0x9b, 0x05, 0xf2, 0x33, 0xa8, 0x5e, 0x06, 0x61, 0xb2,
+	0xd5, 0x88, 0x7b, 0x14, 0x2f, 0x57, 0x5a, 0xe8, 0x52, 0xe0, 0x6b, 0xa7, 0xf1, 0x6a, 0x91, 0x51,
+	0xc0, 0x38, 0x01, 0xfe, 0x97, 0xd0, 0xc2, 0xf3, 0xe4, 0xeb, 0x01, 0x09, 0x53, 0x75, 0xd3, 0x44,
+	0xed, 0x08, 0x73, 0x52, 0x91, 0x4f, 0xb6, 0x8a, 0x3d, 0xd9, 0x4e, 0x00, 0x90, 0x9a, 0x92, 0x84,
+	0x43, 0xa8, 0x49, 0x48, 0xb9, 0x6c, 0x44, 0x10, 0xfa, 0x16, 0x19, 0x1f, 0xe0, 0x24, 0xcd, 0x3e,
+	0xff, 0x0c, 0xc9, 0x54, 0x71, 0x68, 0x81, 0xab, 0x5b, 0x2c, 0x86, 0x26, 0x05, 0x2a, 0x5e, 0x1b,
+	0x01, 0x8e, 0x25, 0xc0, 0x74, 0x72, 0xc5, 0xee, 0xe4, 0xfb, 0x34, 0x0b, 0xf2, 0x30, 0x28, 0x88,
+	0x7d, 0xa8, 0xb5, 0x54, 0x7b, 0x8e, 0x1e, 0x11, 0x52, 0xbf, 0x56, 0xf8, 0x3b, 0x07, 0xe0, 0xe7,
+	0x49, 0xbc, 0x5a, 0xca, 0x18, 0x31, 0x1f, 0x6a, 0x12, 0x52, 0x4e, 0x75, 0x90, 0x5f, 0x1b, 0xc4,
+	0x89, 0x54, 0x1e, 0x5d, 0xcc, 0xc2, 0xf1, 0x6c, 0x46, 0xfd, 0xc3, 0xf1, 0x93, 0x3d, 0x02, 0x18,
+	0x88, 0x49, 0x38, 0x0f, 0x22, 0x24, 0x54, 0x4d, 0xff, 0x29, 0x2c, 0xb7, 0xc8, 0xfe, 0x5f, 0x1c,
+	0x68, 0x8e, 0x83, 0x28, 0x97, 0x35, 0x0e, 0x22, 0x15, 0x19, 0xfc, 0x2c, 0xea, 0x74, 0xb5, 0xce,
+	0x07, 0xd0, 0x7c, 0x12, 0xc5, 0x41, 0x86, 0xcc, 0xa8, 0xd8, 0xe1, 0x39, 0x6c, 0x69, 0x47, 0xea,
+	0x5b, 0xb4, 0x23, 0xb3, 0x0f, 0x9d, 0x97, 0xe1, 0x5c, 0xa4, 0x59, 0x30, 0x5f, 0x22, 0x3b, 0x5d,
+	0x73, 0x05, 0x1c, 0x46, 0xaa, 0xa1, 0x8e, 0x94, 0x27, 0x0f, 0xb1, 0xa3, 0x49, 0x10, 0x09, 0x6d,
+	0xa4, 0x04, 0xd8, 0x21, 0xc0, 0x85, 0x58, 0x8f, 0x45, 0x92, 0x86, 0xf1, 0x42, 0x9a, 0xd9, 0xe4,
+	0x16, 0x06, 0x53, 0x37, 0x0e, 0xa2, 0xe3, 0xab, 0x54, 0x5d, 0xba, 0x0a, 0x52, 0x78, 0xbc, 0xf8,
+	0x6a, 0xf2, 0x8c, 0x82, 0xfc, 0x2f, 0x61, 0x7f, 0x10, 0xa6, 0x59, 0xb8, 0x98, 0x64, 0xb9, 0x7d,
+	0xec, 0x7e, 0x3e, 0x0d, 0xd4, 0x14, 0x26, 0x28, 0x6f, 0xe9, 0x8a, 0x69, 0x69, 0xff, 0xaf, 0x0e,
+	0x74, 0x7e, 0xb1, 0x12, 0xc9, 0x0d, 0x17, 0xbf, 0x59, 0x89, 0x34, 0x43, 0xbb, 0x25, 0xac, 0x0b,
+	0x4d, 0x02, 0x28, 0x72, 0xf4, 0x3a, 0x48, 0xa6, 0xd4, 0xa1, 0x55, 0xae, 0x20, 0xc4, 0x73, 0x31,
+	0x8f, 0x33, 0xa1, 0xed, 0x22, 0x88, 0x3d, 0x82, 0xce, 0xd9, 0xfc, 0x4a, 0x4c, 0xa7, 0x62, 0x3a,
+	0x08, 0xb2, 0xc0, 0x6b, 0x16, 0xaf, 0xfc, 0x02, 0x91, 0x7d, 0x0f, 0x76, 0x2e, 0x13, 0xf1, 0x32,
+	0x09, 0x16, 0x69, 0x14, 0x64, 0x62, 0xea, 0xb5, 0xa4, 0xac, 0x22, 0x92, 0x1d, 0x40, 0xeb, 0x79,
+	0x70, 0xfd, 0x5c, 0xcc, 0xe3, 0xe4, 0xc6, 0x03, 0x19, 0x54, 0x83, 0xf0, 0x9f, 0xc1, 0x8e, 0x72,
+	0x23, 0x5d, 0xc6, 0x8b, 0x54, 0x60, 0xd9, 0x9c, 0x25, 0x89, 0xf2, 0x02, 0x3f, 0xd9, 0xc7, 0xd0,
+	0xe0, 0x22, 0x5d, 0x45, 0x99, 0x1e, 0x33, 0x77, 0xd1, 0x1c, 0x7d, 0x6a, 0x15, 0x65, 0x5c, 0xd3,
+	0xfd, 0x3f, 0x37, 0xa0, 0x6d, 0x11, 0xf2, 0xc1, 0x87, 0xc3, 0x7b, 0x87, 0x06, 0x1f, 0x2e, 0x22,
+	0x3c, 0x5e, 0x6f, 0xed, 0x28, 0xd8, 0xac, 0x1d, 0x70, 0x2e, 0x54, 0x43, 0x38, 0x17, 0x66, 0x36,
+	0xb8, 0xe5, 0xb3, 0x01, 0xf7, 0xb2, 0xd7, 0xc1, 0x62, 0x26, 0xa6, 0x32, 0xe9, 0x4d, 0xae, 0x41,
+	0xd6, 0x37, 0x6d, 0x20, 0xe3, 0xab, 0x7a, 0x50, 0xe3, 0x78, 0x4e, 0x55, 0x2d, 0x8f, 0x77, 0x5f,
+	0x83, 0xf2, 0x43, 0x10, 0xfb, 0x1c, 0x76, 0x5f, 0x44, 0x53, 0xd3, 0xd3, 0xa9, 0xca, 0xc4, 0x2e,
+	0xca, 0x31, 0x68, 0xbe, 0xc1, 0xc5, 0xbe, 0xd8, 0x5c, 0xa5, 0x64, 0x4e, 0xda, 0x47, 0x4c, 0xf9,
+	0x69, 0x51, 0xf8, 0x06, 0x27, 0x7b, 0x64, 0x6d, 0x72, 0x32, 0x51, 0xed, 0xa3, 0x1d, 0x3c, 0x96,
+	0x23, 0xb9, 0xa1, 0xb3, 0xc7, 0xf6, 0x18, 0xf5, 0xda, 0x3d, 0x47, 0x1b, 0x67, 0xb0, 0xdc, 0xe2,
+	0x40, 0xe1, 0xf9, 0xdc, 0xf6, 0x3a, 0x46, 0x78, 0x8e, 0xe4, 0x86, 0x5e, 0xbe, 0x59, 0xed, 0xfc,
+	0x8f, 0x9b, 0xd5, 0x17, 0x9b, 0x17, 0x9c, 0xb7, 0x6b, 0x42, 0x51, 0xa4, 0xf0, 0x0d, 0x4e, 0xf6,
+	0xc8, 0x5a, 0x7f, 0xbd, 0xbb, 0xc6, 0xda, 0x1c, 0xc9, 0x0d, 0x9d, 0xfd, 0x08, 0xda, 0x76, 0xa2,
+	0xf6, 0x7a, 0x8e, 0xae, 0x51, 0x0b, 0xcd, 0x6d, 0x1e, 0x76, 0x5a, 0xd2, 0xfe, 0xde, 0xbe, 0x71,
+	0x70, 0x8b, 0xc8, 0xb7, 0xf9, 0xd1, 0x48, 0x6c, 0xc3, 0x27, 0x09, 0xce, 0x06, 0x66, 0x8c, 0xcc,
+	0x91, 0xdc, 0xd0, 0x31, 0x5f, 0xc7, 0x49, 0x12, 0xaf, 0x29, 0x12, 0xf7, 0x4c, 0xbe, 0x0c, 0x96,
+	0x5b, 0x1c, 0xec, 0xab, 0x5b, 0xf7, 0x5e, 0xaf, 0x2b, 0x0f, 0xbf, 0x5f, 0x9a, 0x08, 0x62, 0xe1,
+	0xb7, 0x9d, 0xf5, 0xff, 0x56, 0x81, 0x9d, 0xe1, 0x7c, 0x19, 0x27, 0x99, 0x35, 0xb7, 0xe8, 0x55,
+	0xe2, 0x94, 0xbe, 0x4a, 0x2a, 0x1b, 0xd7, 0xa6, 0x9c, 0x5f, 0x72, 0x00, 0x57, 0x39, 0x01, 0x56,
+	0x0f, 0x55, 0x0b, 0x3d, 0x74, 0x00, 0x2d, 0xda, 0x3a, 0x90, 0x54, 0x93, 0x24, 0x83, 0xa0, 0x77,
+	0xd2, 0x5a, 0x6e, 0x95, 0x0d, 0x39, 0x6d, 0x35, 0x88, 0xb3, 0x9e, 0xd8, 0x24, 0xb1, 0x29, 0x89,
+	0x16, 0x06, 0xe9, 0x79, 0x12, 0x52, 0xaf, 0xde, 0x73, 0xfb, 0x2e, 0xb7, 0x30, 0xec, 0x23, 0xd8,
+	0x95, 0x4e, 0x9c, 0x26, 0x02, 0x07, 0xe0, 0x71, 0x26, 0x7b, 0xd0, 0xe5, 0x1b, 0x58, 0xe4, 0x93,
+	0x6e, 0x19, 0x3e, 0x9a, 0x8e, 0x1b, 0x58, 0x79, 0x6d, 0x46, 0x22, 0x48, 0x64, 0x97, 0x35, 0x39,
+	0x01, 0xfe, 0xbf, 0x2a, 0xc0, 0x28, 0x92, 0xb4, 0x21, 0xfe, 0xdf, 0xc2, 0xf9, 0xf6, 0xb0, 0x15,
+	0x83, 0xd3, 0xd8, 0x0a, 0x8e, 0xb9, 0xc3, 0x28, 0x30, 0x0a, 0x62, 0x3d, 0x68, 0xeb, 0x5b, 0x7d,
+	0x25, 0x28, 0xaa, 0x0e, 0xb7, 0x51, 0x78, 0x7d, 0x8f, 0x32, 0x7c, 0xa8, 0x2a, 0x96, 0x96, 0x94,
+	0x5d, 0xc0, 0x95, 0x84, 0x16, 0xbe, 0x65, 0x68, 0xdb, 0x6f, 0x0f, 0x6d, 0xc7, 0x0e, 0xed, 0xef,
+	0x1d, 0xe8, 0x1c, 0x67, 0xf1, 0x3c, 0x9c, 0x70, 0x31, 0x89, 0x93, 0xe9, 0xed, 0x41, 0xa5, 0xf0,
+	0x55, 0xec, 0xf0, 0xf5, 0xc1, 0x1d, 0x7e, 0x93, 0xa8, 0x3b, 0xe3, 0xbe, 0x5c, 0xd5, 0xb6, 0xb2,
+	0xc4, 0x91, 0x85, 0x3d, 0x84, 0xca, 0x30, 0x91, 0x35, 0xdb, 0x3e, 0xda, 0x37, 0x8c, 0x9a, 0xa7,
+	0x32, 0x4c, 0xfc, 0x1f, 0x40, 0x97, 0x0c, 0xd1, 0x24, 0x75, 0x49, 0x76, 0xa1, 0x76, 0x96, 0x24,
+	0xb1, 0xbe, 0x26, 0x09, 0xc0, 0xb7, 0x48, 0x7e, 0xef, 0x62, 0x32, 0xbe, 0x4b, 0x4d, 0x94, 0xfd,
+	0x52, 0xe8, 0x41, 0xfb, 0x22, 0xce, 0x7e, 0x99, 0x84, 0x99, 0x1c, 0x1e, 0x74, 0xd9, 0xd9, 0x28,
+	0xff, 0x63, 0x78, 0x67, 0x43, 0xb3, 0xb9, 0xcd, 0x87, 0x03, 0x92, 0xa6, 0x9e, 0xe5, 0x23, 0xb8,
+	0x97, 0xb3, 0x0e, 0x07, 0xdf, 0xc9, 0xc6, 0x6d, 0xa1, 0x9f, 0x40, 0xb7, 0x28, 0x54, 0xa9, 0x2f,
+	0xf1, 0xc6, 0x3f, 0x01, 0x4f, 0x45, 0x93, 0xfe, 0x8b, 0x28, 0x0b, 0xc6, 0xa1, 0x58, 0xdf, 0xf6,
+	0x78, 0x92, 0xab, 0x50, 0x45, 0x2e, 0x76, 0xf2, 0xdb, 0xff, 0x43, 0x05, 0xba, 0x65, 0x42, 0x4c,
+	0x41, 0x39, 0x56, 0x41, 0xb1, 0x23, 0xa8, 0x7d, 0x13, 0x8a, 0xb5, 0xde, 0x5f, 0x0e, 0xac, 0x64,
+	0x6f, 0xd9, 0xc0, 0x89, 0x15, 0x1b, 0xe9, 0x78, 0x92, 0xe9, 0x6d, 0xb3, 0xc5, 0x15, 0x84, 0x1a,
+	0x4e, 0xa2, 0x78, 0xf2, 0x35, 0xbd, 0x63, 0x39, 0x01, 0x25, 0x8d, 0x51, 0xfb, 0x96, 0x8d, 0x51,
+	0x2f, 0x6d, 0x8c, 0x3e, 0xdc, 0xfd, 0x6a, 0x39, 0x0d, 0x32, 0x71, 0x76, 0x1d, 0xa6, 0x99, 0x58,
+	0x4c, 0x84, 0xd7, 0x90, 0x1e, 0x6d, 0xa2, 0x71, 0xa3, 0xde, 0x51, 0x5e, 0x10, 0xe9, 0x96, 0x27,
+	0x0f, 0x83, 0x2a, 0xba, 0xa7, 0x97, 0x58, 0xfc, 0x36, 0xd1, 0x72, 0x65, 0x6c, 0x09, 0xc0, 0xf4,
+	0x8e, 0x44, 0xa6, 0x16, 0x69, 0xfc, 0xc4, 0xd1, 0x20, 0x49, 0xd4, 0x8e, 0xa9, 0xda, 0x59, 0x0b,
+	0x38, 0xff, 0x8f, 0x0e, 0xbc, 0x57, 0x88, 0xa9, 0x6c, 0x47, 0x9d, 0x17, 0xb3, 0xef, 0x3a, 0x85,
+	0x7d, 0xf7, 0xfb, 0x50, 0x1b, 0x5b, 0x99, 0xd9, 0xa7, 0x4b, 0xde, 0xf2, 0x86, 0x13, 0x9d, 0x7d,
+	0x06, 0x70, 0xfa, 0x5a, 0x4c, 0xbe, 0x5e, 0xc6, 0xe1, 0x22, 0x93, 0xf6, 0xb6, 0x8f, 0xba, 0xf4,
+	0x10, 0x9b, 0x89, 0x34, 0x33, 0x34, 0x6e, 0xf1, 0xf9, 0xa3, 0xc2, 0x6a, 0x80, 0xa3, 0xf5, 0x78,
+	0x36, 0x4b, 0xc4, 0x2c, 0xc8, 0x74, 0x8d, 0x19, 0x04, 0xfb, 0x08, 0xea, 0x92, 0x59, 0x1b, 0xb3,
+	0xb9, 0xeb, 0x29, 0xaa, 0xff, 0xa1, 0x75, 0xef, 0xe7, 0xd5, 0xe9, 0x58, 0xd5, 0xd9, 0xb3, 0xef,
+	0xfa, 0x52, 0x8e, 0x4b, 0xd8, 0xdb, 0xb4, 0x5b, 0xbf, 0xc0, 0x1d, 0xf3, 0x02, 0xff, 0x04, 0x1a,
+	0x2f, 0x5e, 0xbd, 0x4a, 0x45, 0xbe, 0x78, 0xef, 0x19, 0x87, 0x89, 0xc0, 0x35, 0x83, 0xff, 0x2b,
+	0xe8, 0xd8, 0x04, 0xf9, 0xf0, 0x88, 0x57, 0xc9, 0x44, 0xfb, 0xa9, 0x20, 0xc4, 0x13, 0x87, 0x9a,
+	0x9a, 0x0a, 0xc2, 0x57, 0xe0, 0x65, 0x9c, 0x86, 0x56, 0xc1, 0xe7, 0xf0, 0xc9, 0xde, 0xdf, 0xdf,
+	0x1c, 0x3a, 0xff, 0x7c, 0x73, 0xe8, 0xfc, 0xfb, 0xcd, 0xa1, 0xf3, 0xa7, 0xff, 0x1c, 0xde, 0xb9,
+	0xaa, 0xcb, 0x9f, 0xa4, 0x3f, 0xfe, 0xef, 0x00, 0x26, 0x72, 0x07, 0x5a, 0x34, 0x15, 0x00, 0x00,
 }
 
 func (m *Row) Marshal() (dAtA []byte, err error) {
@@ -5494,6 +5626,18 @@ func (m *ImportRoaringShardRequest) MarshalToSizedBuffer(dAtA []byte) (int, erro
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Checkpoint != nil {
+		{
+			size, err := m.Checkpoint.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPublic(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.Views) > 0 {
 		for iNdEx := len(m.Views) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -5637,6 +5781,100 @@ func (m *ArrowTable) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *IngestCheckpoint) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *IngestCheckpoint) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *IngestCheckpoint) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Offsets) > 0 {
+		for iNdEx := len(m.Offsets) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Offsets[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPublic(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintPublic(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *IngestOffset) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *IngestOffset) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *IngestOffset) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Position) > 0 {
+		i -= len(m.Position)
+		copy(dAtA[i:], m.Position)
+		i = encodeVarintPublic(dAtA, i, uint64(len(m.Position)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Offset != 0 {
+		i = encodeVarintPublic(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Source) > 0 {
+		i -= len(m.Source)
+		copy(dAtA[i:], m.Source)
+		i = encodeVarintPublic(dAtA, i, uint64(len(m.Source)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintPublic(dAtA []byte, offset int, v uint64) int {
 	offset -= sovPublic(v)
 	base := offset
@@ -6769,6 +7007,10 @@ func (m *ImportRoaringShardRequest) Size() (n int) {
 			n += 1 + l + sovPublic(uint64(l))
 		}
 	}
+	if m.Checkpoint != nil {
+		l = m.Checkpoint.Size()
+		n += 1 + l + sovPublic(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -6829,6 +7071,51 @@ func (m *ArrowTable) Size() (n int) {
 	return n
 }
 
+func (m *IngestCheckpoint) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovPublic(uint64(l))
+	}
+	if len(m.Offsets) > 0 {
+		for _, e := range m.Offsets {
+			l = e.Size()
+			n += 1 + l + sovPublic(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *IngestOffset) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Source)
+	if l > 0 {
+		n += 1 + l + sovPublic(uint64(l))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovPublic(uint64(m.Offset))
+	}
+	l = len(m.Position)
+	if l > 0 {
+		n += 1 + l + sovPublic(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovPublic(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -13414,6 +13701,42 @@ func (m *ImportRoaringShardRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checkpoint", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPublic
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPublic
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Checkpoint == nil {
+				m.Checkpoint = &IngestCheckpoint{}
+			}
+			if err := m.Checkpoint.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPublic(dAtA[iNdEx:])
@@ -13723,6 +14046,257 @@ func (m *ArrowTable) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *IngestCheckpoint) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPublic
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: IngestCheckpoint: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: IngestCheckpoint: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPublic
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPublic
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offsets", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPublic
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPublic
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Offsets = append(m.Offsets, &IngestOffset{})
+			if err := m.Offsets[len(m.Offsets)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPublic(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPublic
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *IngestOffset) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPublic
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: IngestOffset: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: IngestOffset: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPublic
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPublic
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Source = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Position", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPublic
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPublic
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Position = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPublic(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPublic
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipPublic(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
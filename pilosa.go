@@ -55,6 +55,7 @@ var (
 	ErrQueryCancelled   = errors.New("query cancelled")
 	ErrQueryTimeout     = errors.New("query timeout")
 	ErrTooManyWrites    = errors.New("too many write commands")
+	ErrCursorNotFound   = errors.New("cursor not found")
 
 	// TODO(2.0) poorly named - used when a *node* doesn't own a shard. Probably
 	// we won't need this error at all by 2.0 though.
@@ -73,6 +74,11 @@ var (
 	ErrIntFieldWithKeys       = errors.New("int field cannot be created with 'keys=true' option")
 	ErrDecimalFieldWithKeys   = errors.New("decimal field cannot be created with 'keys=true' option")
 	ErrTimestampFieldWithKeys = errors.New("timestamp field cannot be created with 'keys=true' option")
+	ErrFloatFieldWithKeys     = errors.New("float field cannot be created with 'keys=true' option")
+	ErrFloatNaN               = errors.New("float field cannot store NaN")
+	ErrJSONFieldNotSet        = errors.New("json field must be a set field without keys")
+	ErrGeoPointFieldNotInt    = errors.New("geopoint field must be an int field")
+	ErrBitVectorFieldNotSet   = errors.New("bitvector field must be a set field without keys, with a width which is a positive multiple of 8")
 )
 
 // apiMethodNotAllowedError wraps an error value indicating that a particular
@@ -447,6 +447,18 @@ var allowField = callInfo{
 	},
 }
 
+// allowFieldTimeRange is allowField for aggregates, which can also be
+// limited to the time views of a field with a time quantum.
+var allowFieldTimeRange = callInfo{
+	allowUnknown: false,
+	prototypes: map[string]interface{}{
+		"_field": stringOrVariable,
+		"field":  stringOrVariable,
+		"from":   nil,
+		"to":     nil,
+	},
+}
+
 var callInfoByFunc = map[string]callInfo{
 	// the easy cases: things that take arbitrary inputs, because they're
 	// taking field=value cases
@@ -460,9 +472,9 @@ var callInfoByFunc = map[string]callInfo{
 	"Condition": {allowUnknown: true},
 
 	// allow only "field=X" cases with string field names
-	"Max": allowField,
-	"Min": allowField,
-	"Sum": allowField,
+	"Max": allowFieldTimeRange,
+	"Min": allowFieldTimeRange,
+	"Sum": allowFieldTimeRange,
 
 	// only take other calls, should never have "args"
 	"Difference": {allowUnknown: false},
@@ -532,6 +544,7 @@ var callInfoByFunc = map[string]callInfo{
 		prototypes: map[string]interface{}{
 			"limit":  int64(0),
 			"offset": int64(0),
+			"after":  int64(0),
 		},
 		callType: PrecallGlobal,
 	},
@@ -540,7 +553,8 @@ var callInfoByFunc = map[string]callInfo{
 	"ConstRow": {
 		allowUnknown: false,
 		prototypes: map[string]interface{}{
-			"columns": interfaceOrVariable,
+			"columns":   interfaceOrVariable,
+			"keyPrefix": "",
 		},
 		callType: PrecallGlobal,
 	},
@@ -564,6 +578,16 @@ var callInfoByFunc = map[string]callInfo{
 			"field":  stringOrVariable,
 		},
 	},
+	"Nearest": {
+		allowUnknown: false,
+		prototypes: map[string]interface{}{
+			"field":  stringOrVariable,
+			"_field": stringOrVariable,
+			"vector": stringOrVariable,
+			"k":      int64(0),
+			"filter": nil,
+		},
+	},
 	"Percentile": {
 		allowUnknown: false,
 		prototypes: map[string]interface{}{
@@ -0,0 +1,138 @@
+// Copyright 2023 Molecula Corp (DBA FeatureBase). All rights reserved.
+package querycontext
+
+import (
+	"context"
+	"sync"
+)
+
+// ScopeLock applies the QueryScope overlap rules used by a TxStore to
+// callers which don't (yet) obtain their transactions through a TxStore.
+// It doesn't hold any database transactions itself; it just ensures that
+// a caller holding an exclusive scope never runs concurrently with any
+// other caller holding an overlapping scope, exclusive or shared.
+//
+// This lets us make a group of otherwise-independent writes appear atomic
+// to readers: the writer takes an exclusive scope covering everything it
+// will write, and readers take a shared scope covering what they will
+// read. Readers can then see the data from before the writes, or after
+// them, but never part of the way through.
+//
+// Writers are preferred over readers; once a writer is waiting for a
+// scope, new readers of an overlapping scope will wait for that writer,
+// so a steady stream of readers can't starve it.
+type ScopeLock struct {
+	splitter KeySplitter
+
+	mu      sync.Mutex
+	changed chan struct{}
+	writers map[*scopeHolder]QueryScope
+	pending map[*scopeHolder]QueryScope
+	readers map[*scopeHolder]QueryScope
+}
+
+// scopeHolder is a unique token identifying one holder of a scope.
+type scopeHolder struct{}
+
+// NewScopeLock creates a ScopeLock whose scopes follow the rules of the
+// given KeySplitter. If splitter is nil, it uses an index/shard splitter,
+// which matches the way the holder divides data into RBF databases.
+func NewScopeLock(splitter KeySplitter) *ScopeLock {
+	if splitter == nil {
+		splitter = &indexShardKeySplitter{}
+	}
+	return &ScopeLock{
+		splitter: splitter,
+		changed:  make(chan struct{}),
+		writers:  make(map[*scopeHolder]QueryScope),
+		pending:  make(map[*scopeHolder]QueryScope),
+		readers:  make(map[*scopeHolder]QueryScope),
+	}
+}
+
+// Scope yields a new, empty, QueryScope which is compatible with this
+// ScopeLock.
+func (s *ScopeLock) Scope() QueryScope {
+	return s.splitter.Scope()
+}
+
+// Lock waits until no other holder has a scope overlapping the given
+// scope, then holds it exclusively. The returned function releases the
+// scope, and must be called exactly once. If ctx is canceled before the
+// scope can be obtained, Lock returns the context's error.
+func (s *ScopeLock) Lock(ctx context.Context, scope QueryScope) (func(), error) {
+	h := &scopeHolder{}
+	s.mu.Lock()
+	s.pending[h] = scope
+	for s.overlaps(scope, s.writers) || s.overlaps(scope, s.readers) {
+		if err := s.wait(ctx); err != nil {
+			delete(s.pending, h)
+			s.broadcast()
+			s.mu.Unlock()
+			return nil, err
+		}
+	}
+	delete(s.pending, h)
+	s.writers[h] = scope
+	s.mu.Unlock()
+	return func() { s.release(h) }, nil
+}
+
+// RLock waits until no writer holds, or is waiting for, a scope
+// overlapping the given scope, then holds it shared with any other
+// readers. The returned function releases the scope, and must be called
+// exactly once. If ctx is canceled before the scope can be obtained,
+// RLock returns the context's error.
+func (s *ScopeLock) RLock(ctx context.Context, scope QueryScope) (func(), error) {
+	h := &scopeHolder{}
+	s.mu.Lock()
+	for s.overlaps(scope, s.writers) || s.overlaps(scope, s.pending) {
+		if err := s.wait(ctx); err != nil {
+			s.mu.Unlock()
+			return nil, err
+		}
+	}
+	s.readers[h] = scope
+	s.mu.Unlock()
+	return func() { s.release(h) }, nil
+}
+
+// overlaps reports whether scope overlaps any of the given scopes. Call
+// only while holding s.mu.
+func (s *ScopeLock) overlaps(scope QueryScope, holders map[*scopeHolder]QueryScope) bool {
+	for _, other := range holders {
+		if other.Overlap(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// wait drops s.mu until the set of holders changes or ctx is done, then
+// reacquires it. Call only while holding s.mu.
+func (s *ScopeLock) wait(ctx context.Context) error {
+	changed := s.changed
+	s.mu.Unlock()
+	defer s.mu.Lock()
+	select {
+	case <-changed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// broadcast wakes everything currently waiting. Call only while holding
+// s.mu.
+func (s *ScopeLock) broadcast() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *ScopeLock) release(h *scopeHolder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.writers, h)
+	delete(s.readers, h)
+	s.broadcast()
+}
@@ -0,0 +1,110 @@
+// Copyright 2023 Molecula Corp (DBA FeatureBase). All rights reserved.
+package querycontext
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// tryLock attempts to obtain a scope within a short timeout, reporting
+// whether it succeeded.
+func tryLock(t *testing.T, lock func(context.Context, QueryScope) (func(), error), scope QueryScope) (func(), bool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	release, err := lock(ctx, scope)
+	if err != nil {
+		if err != context.DeadlineExceeded {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return nil, false
+	}
+	return release, true
+}
+
+func TestScopeLock(t *testing.T) {
+	sl := NewScopeLock(nil)
+
+	// readers share
+	r1, ok := tryLock(t, sl.RLock, sl.Scope().AddIndex("i"))
+	if !ok {
+		t.Fatalf("first reader should not block")
+	}
+	r2, ok := tryLock(t, sl.RLock, sl.Scope().AddIndex("i"))
+	if !ok {
+		t.Fatalf("second reader should not block")
+	}
+
+	// a writer on an unrelated index doesn't care about readers
+	w1, ok := tryLock(t, sl.Lock, sl.Scope().AddIndex("j"))
+	if !ok {
+		t.Fatalf("writer on other index should not block")
+	}
+
+	// a writer waits for overlapping readers
+	if _, ok := tryLock(t, sl.Lock, sl.Scope().AddIndex("i")); ok {
+		t.Fatalf("writer should block on overlapping readers")
+	}
+	// and readers wait for overlapping writers
+	if _, ok := tryLock(t, sl.RLock, sl.Scope().AddIndexShards("j", 3)); ok {
+		t.Fatalf("reader should block on overlapping writer")
+	}
+	w1()
+
+	r1()
+	r2()
+	w2, ok := tryLock(t, sl.Lock, sl.Scope().AddIndex("i"))
+	if !ok {
+		t.Fatalf("writer should not block once readers are done")
+	}
+
+	// a blocked reader proceeds once the writer releases.
+	done := make(chan error)
+	go func() {
+		release, err := sl.RLock(context.Background(), sl.Scope().AddIndex("i"))
+		if err == nil {
+			release()
+		}
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatalf("reader should have been blocked by writer")
+	case <-time.After(20 * time.Millisecond):
+	}
+	w2()
+	if err := <-done; err != nil {
+		t.Fatalf("blocked reader: %v", err)
+	}
+}
+
+func TestScopeLockPendingWriter(t *testing.T) {
+	sl := NewScopeLock(nil)
+	r1, _ := tryLock(t, sl.RLock, sl.Scope().AddIndex("i"))
+
+	// start a writer, which must wait for r1.
+	locked := make(chan func())
+	go func() {
+		release, err := sl.Lock(context.Background(), sl.Scope().AddIndex("i"))
+		if err != nil {
+			t.Errorf("writer: %v", err)
+		}
+		locked <- release
+	}()
+	// let the writer register itself as pending.
+	time.Sleep(10 * time.Millisecond)
+
+	// new readers now queue up behind the pending writer.
+	if _, ok := tryLock(t, sl.RLock, sl.Scope().AddIndex("i")); ok {
+		t.Fatalf("reader should wait for pending writer")
+	}
+	r1()
+	release := <-locked
+	release()
+	r2, ok := tryLock(t, sl.RLock, sl.Scope().AddIndex("i"))
+	if !ok {
+		t.Fatalf("reader should proceed after writer is done")
+	}
+	r2()
+}
@@ -0,0 +1,356 @@
+// Copyright 2023 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// queryHistoryFile is the name of the file, in the data directory, holding
+// the query history.
+const queryHistoryFile = "query-history.db"
+
+var bucketQueryHistory = []byte("queries")
+
+// QueryHistoryEntry describes a query which has finished running on a node.
+type QueryHistoryEntry struct {
+	RequestID string    `json:"requestID,omitempty"`
+	NodeID    string    `json:"nodeID"`
+	Index     string    `json:"index,omitempty"`
+	User      string    `json:"user,omitempty"`
+	PQL       string    `json:"PQL,omitempty"`
+	SQL       string    `json:"SQL,omitempty"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	// the number of rows (or results, for PQL) returned
+	RowCount int64 `json:"rowCount"`
+	// the number of shards read, summed over the calls in the query
+	ShardsScanned int64  `json:"shardsScanned"`
+	Error         string `json:"error,omitempty"`
+	// the optimized query plan, formatted in json (sql only)
+	Plan string `json:"plan,omitempty"`
+}
+
+// QueryHistoryAPI records the queries which have finished running on a node.
+type QueryHistoryAPI interface {
+	// AddQuery adds a finished query to the history. Queries are added
+	// asynchronously, so may not be listed immediately.
+	AddQuery(q QueryHistoryEntry)
+
+	// ListQueries returns the history, oldest query first.
+	ListQueries() ([]QueryHistoryEntry, error)
+}
+
+// QueryStats accumulates the resources used by a query as it runs.
+type QueryStats struct {
+	shardsScanned int64
+}
+
+type queryStatsKey struct{}
+
+// WithQueryStats returns a context which accumulates the resources used by
+// the queries executed with it into stats.
+func WithQueryStats(ctx context.Context, stats *QueryStats) context.Context {
+	return context.WithValue(ctx, queryStatsKey{}, stats)
+}
+
+// ShardsScanned returns the number of shards read so far.
+func (s *QueryStats) ShardsScanned() int64 {
+	return atomic.LoadInt64(&s.shardsScanned)
+}
+
+// addShardsScanned adds n to the shards read by the query running with ctx,
+// if it is accumulating stats.
+func addShardsScanned(ctx context.Context, n int) {
+	if stats, ok := ctx.Value(queryStatsKey{}).(*QueryStats); ok {
+		atomic.AddInt64(&stats.shardsScanned, int64(n))
+	}
+}
+
+// queryHistoryWriteInterval is the shortest time between writes of the
+// query history to disk. Queries finishing in between are written together.
+const queryHistoryWriteInterval = 100 * time.Millisecond
+
+// queryHistory holds the most recent n queries. Until it is opened, and
+// after it is closed, the history is only kept in memory. Once opened it is
+// kept in a bolt database, so that it survives a restart. Queries are added
+// to the database in batches in the background, so that adding a query
+// never waits on the disk.
+type queryHistory struct {
+	mu  sync.Mutex
+	n   int
+	mem *ringBuffer
+	db  *bolt.DB
+
+	// the queries added since the last batch was written to db
+	pending []QueryHistoryEntry
+	wake    chan struct{}
+	closing chan struct{}
+	wg      sync.WaitGroup
+
+	// writeMu is held while a batch is written, so that every query is
+	// seen by list as either pending or in db
+	writeMu sync.Mutex
+	// the number of queries in db, protected by writeMu
+	dbN int
+}
+
+func newQueryHistory(n int) *queryHistory {
+	return &queryHistory{
+		n:   n,
+		mem: newRingBuffer(n),
+	}
+}
+
+// open opens (or creates) the history database at path. Any queries already
+// held in memory are moved to the database.
+func (h *queryHistory) open(path string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.db != nil {
+		return errors.New("query history already open")
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 1 * time.Second, NoSync: true})
+	if err != nil {
+		return errors.Wrapf(err, "opening query history %s", path)
+	}
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	if err := db.Update(func(tx *bolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists(bucketQueryHistory)
+		if err != nil {
+			return err
+		}
+		h.dbN = bkt.Stats().KeyN
+
+		for _, q := range h.mem.slice() {
+			if err := h.put(bkt, q); err != nil {
+				return err
+			}
+		}
+		// the history length may have been reduced since the last time
+		// the database was open
+		return h.prune(bkt)
+	}); err != nil {
+		db.Close()
+		return errors.Wrapf(err, "initializing query history %s", path)
+	}
+
+	h.db = db
+	h.mem = newRingBuffer(h.n)
+	h.wake = make(chan struct{}, 1)
+	h.closing = make(chan struct{})
+	h.wg.Add(1)
+	go h.writeLoop(h.wake, h.closing)
+	return nil
+}
+
+// close writes any pending queries, and closes the history database.
+func (h *queryHistory) close() error {
+	h.mu.Lock()
+	if h.db == nil {
+		h.mu.Unlock()
+		return nil
+	}
+	closing := h.closing
+	h.mu.Unlock()
+
+	close(closing)
+	h.wg.Wait()
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	h.mu.Lock()
+	db, batch := h.db, h.pending
+	h.db, h.pending = nil, nil
+	h.mu.Unlock()
+
+	h.writeBatch(db, batch)
+	return errors.Wrap(db.Close(), "closing query history")
+}
+
+// add adds q to the history, dropping the oldest query if the history is
+// full. If the history is open, q is written to the database later.
+func (h *queryHistory) add(q QueryHistoryEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.n <= 0 {
+		return
+	}
+	if h.db == nil {
+		h.mem.add(q)
+		return
+	}
+	h.pending = append(h.pending, q)
+	if len(h.pending) > h.n {
+		// these would be pruned as soon as they were written
+		h.pending = append(h.pending[:0], h.pending[len(h.pending)-h.n:]...)
+	}
+	select {
+	case h.wake <- struct{}{}:
+	default:
+	}
+}
+
+// writeLoop writes the pending queries to the database whenever there are
+// some, at most once every queryHistoryWriteInterval, until closing is
+// closed.
+func (h *queryHistory) writeLoop(wake <-chan struct{}, closing <-chan struct{}) {
+	defer h.wg.Done()
+	for {
+		select {
+		case <-wake:
+		case <-closing:
+			return
+		}
+		h.write()
+
+		select {
+		case <-time.After(queryHistoryWriteInterval):
+		case <-closing:
+			return
+		}
+	}
+}
+
+// write writes the pending queries to the database.
+func (h *queryHistory) write() {
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+
+	h.mu.Lock()
+	db, batch := h.db, h.pending
+	h.pending = nil
+	h.mu.Unlock()
+
+	h.writeBatch(db, batch)
+}
+
+// writeBatch writes batch to db, and prunes the oldest queries. Callers must
+// hold writeMu.
+func (h *queryHistory) writeBatch(db *bolt.DB, batch []QueryHistoryEntry) {
+	if db == nil || len(batch) == 0 {
+		return
+	}
+	// there's nobody to report a failure to, and the queries themselves
+	// have already finished
+	_ = db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(bucketQueryHistory)
+		for _, q := range batch {
+			if err := h.put(bkt, q); err != nil {
+				return err
+			}
+		}
+		return h.prune(bkt)
+	})
+}
+
+// put writes q to bkt, keyed by the next sequence number so that queries are
+// kept in the order they were added.
+func (h *queryHistory) put(bkt *bolt.Bucket, q QueryHistoryEntry) error {
+	seq, err := bkt.NextSequence()
+	if err != nil {
+		return err
+	}
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], seq)
+
+	buf, err := json.Marshal(q)
+	if err != nil {
+		return err
+	}
+	if err := bkt.Put(key[:], buf); err != nil {
+		return err
+	}
+	h.dbN++
+	return nil
+}
+
+// prune deletes the oldest queries in bkt until there are at most h.n.
+func (h *queryHistory) prune(bkt *bolt.Bucket) error {
+	cur := bkt.Cursor()
+	for k, _ := cur.First(); k != nil && h.dbN > h.n; k, _ = cur.First() {
+		if err := cur.Delete(); err != nil {
+			return err
+		}
+		h.dbN--
+	}
+	return nil
+}
+
+// list returns the history, oldest query first.
+func (h *queryHistory) list() ([]QueryHistoryEntry, error) {
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+
+	h.mu.Lock()
+	if h.db == nil {
+		defer h.mu.Unlock()
+		return h.mem.slice(), nil
+	}
+	db := h.db
+	pending := append([]QueryHistoryEntry(nil), h.pending...)
+	h.mu.Unlock()
+
+	queries := make([]QueryHistoryEntry, 0, h.dbN+len(pending))
+	if err := db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketQueryHistory).ForEach(func(k, v []byte) error {
+			var q QueryHistoryEntry
+			if err := json.Unmarshal(v, &q); err != nil {
+				return err
+			}
+			queries = append(queries, q)
+			return nil
+		})
+	}); err != nil {
+		return nil, errors.Wrap(err, "reading query history")
+	}
+	queries = append(queries, pending...)
+	if len(queries) > h.n {
+		queries = queries[len(queries)-h.n:]
+	}
+	return queries, nil
+}
+
+// queryResultCount returns the number of rows in the results of a PQL query.
+// Results which aren't a set of rows, such as a Count, count as a single row.
+func queryResultCount(results []interface{}) int64 {
+	var n int64
+	for _, result := range results {
+		switch r := result.(type) {
+		case nil:
+		case *Row:
+			n += int64(r.Count())
+		case []*Row:
+			n += int64(len(r))
+		case *PairsField:
+			n += int64(len(r.Pairs))
+		case RowIdentifiers:
+			n += int64(len(r.Rows) + len(r.Keys))
+		case RowIDs:
+			n += int64(len(r))
+		case []GroupCount:
+			n += int64(len(r))
+		case *GroupCounts:
+			n += int64(len(r.Groups()))
+		case ExtractedTable:
+			n += int64(len(r.Columns))
+		case ExtractedIDMatrix:
+			n += int64(len(r.Columns))
+		default:
+			n++
+		}
+	}
+	return n
+}
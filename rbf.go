@@ -537,6 +537,42 @@ func (w *RbfDBWrapper) DeleteField(index, field, fieldPath string) error {
 	return tx.Commit()
 }
 
+// RenameField renames the bitmaps of a field, and of its views which carry
+// the field's name, without rewriting their pages.
+func (w *RbfDBWrapper) RenameField(index, field, newName string) error {
+	w.muDb.Lock()
+	defer w.muDb.Unlock()
+
+	tx, err := w.db.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	records, err := tx.RootRecords()
+	if err != nil {
+		return err
+	}
+	prefix := rbfFieldPrefix(index, field)
+	newPrefix := rbfFieldPrefix(index, newName)
+	for itr := records.Iterator(); !itr.Done(); {
+		name, _, _ := itr.Next()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		rest := name[len(prefix):]
+		i := strings.IndexByte(rest, '<')
+		if i < 0 {
+			return errors.Errorf("malformed bitmap key: %x", name)
+		}
+		view := renamedView(rest[:i], field, newName)
+		if err := tx.RenameBitmap(name, newPrefix+view+rest[i:]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 func (w *RbfDBWrapper) DeleteIndex(indexName string) error {
 
 	if strings.Contains(indexName, "'") {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/benbjohnson/immutable"
 	"github.com/featurebasedb/featurebase/v3/roaring"
+	"github.com/featurebasedb/featurebase/v3/shardwidth"
 	txkey "github.com/featurebasedb/featurebase/v3/short_txkey"
 	"github.com/featurebasedb/featurebase/v3/vprint"
 	"github.com/pkg/errors"
@@ -1046,6 +1047,75 @@ func (tx *Tx) GetSizeBytesWithPrefix(prefix string) (n uint64, err error) {
 	return n, nil
 }
 
+// BitmapStats returns storage statistics for every bitmap in the database,
+// ordered by bitmap name.
+func (tx *Tx) BitmapStats() ([]*BitmapStats, error) {
+	records, err := tx.RootRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*BitmapStats, 0, records.Len())
+	for itr := records.Iterator(); !itr.Done(); {
+		name, pgno, _ := itr.Next()
+
+		s, err := tx.bitmapStats(name, pgno)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// bitmapStats walks the b-tree rooted at pgno and tallies its pages and
+// containers. Leaf pages are visited in key order so rows can be counted as
+// the keys go by.
+func (tx *Tx) bitmapStats(name string, root uint32) (*BitmapStats, error) {
+	s := &BitmapStats{Name: name}
+
+	// number of container keys in a single row
+	rowShift := uint(shardwidth.Exponent - 16)
+	lastRow := uint64(0)
+
+	if err := tx.walkTree(root, 0, func(pgno, parent, typ uint32, err error) error {
+		if err != nil {
+			return err
+		}
+		s.PageN++
+		if typ != PageTypeLeaf {
+			return nil
+		}
+
+		page, _, err := tx.readPage(pgno)
+		if err != nil {
+			return err
+		}
+		for i, n := 0, readCellN(page); i < n; i++ {
+			cell := readLeafCell(page, i)
+			switch cell.Type {
+			case ContainerTypeArray:
+				s.ArrayN++
+			case ContainerTypeRLE:
+				s.RunN++
+			case ContainerTypeBitmap, ContainerTypeBitmapPtr:
+				s.BitmapN++
+			}
+			s.ContainerN++
+			s.BitN += uint64(cell.BitN)
+
+			if row := cell.Key >> rowShift; s.RowN == 0 || row != lastRow {
+				s.RowN++
+				lastRow = row
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
 // walkTree recursively iterates over a page and all its children.
 func (tx *Tx) walkTree(pgno, parent uint32, fn func(pgno, parent, typ uint32, err error) error) error {
 	// Read page and iterate over children.
@@ -2271,6 +2341,18 @@ func (tx *Tx) DebugInfo() *TxDebugInfo {
 	}
 }
 
+// BitmapStats holds storage statistics for a single bitmap.
+type BitmapStats struct {
+	Name       string
+	PageN      int    // leaf, branch & bitmap pages
+	RowN       int    // distinct rows with at least one container
+	ContainerN int    // total containers
+	ArrayN     int    // array containers
+	RunN       int    // run-length encoded containers
+	BitmapN    int    // bitmap containers
+	BitN       uint64 // set bits
+}
+
 type TxDebugInfo struct {
 	Ptr      string `json:"ptr"`
 	Writable bool   `json:"writable"`
@@ -189,6 +189,58 @@ func TestTx_Add(t *testing.T) {
 	}
 }
 
+func TestTx_BitmapStats(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+	tx := MustBegin(t, db, true)
+	defer tx.Rollback()
+
+	if err := tx.CreateBitmap("x"); err != nil {
+		t.Fatal(err)
+	} else if err := tx.CreateBitmap("y"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Row 0 gets a sparse array container; row 2 gets a run container and,
+	// in its second container, a dense bitmap container.
+	rowKey := uint64(rbf.ShardWidth >> 16)
+	bits := make([]uint64, 1024)
+	for i := range bits {
+		bits[i] = 0x5555555555555555
+	}
+	if _, err := tx.Add("x", 1, 3); err != nil {
+		t.Fatal(err)
+	} else if err := tx.PutContainer("x", 2*rowKey, roaring.NewContainerRun([]roaring.Interval16{{Start: 0, Last: 9999}})); err != nil {
+		t.Fatal(err)
+	} else if err := tx.PutContainer("x", 2*rowKey+1, roaring.NewContainerBitmap(-1, bits)); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := tx.BitmapStats()
+	if err != nil {
+		t.Fatal(err)
+	} else if got, want := len(stats), 2; got != want {
+		t.Fatalf("len(stats)=%d, want %d", got, want)
+	}
+
+	x := stats[0]
+	if x.Name != "x" {
+		t.Fatalf("unexpected name: %q", x.Name)
+	} else if x.RowN != 2 {
+		t.Fatalf("RowN=%d, want 2", x.RowN)
+	} else if x.ContainerN != 3 || x.ArrayN != 1 || x.RunN != 1 || x.BitmapN != 1 {
+		t.Fatalf("unexpected containers: %+v", x)
+	} else if x.BitN != 2+10000+32768 {
+		t.Fatalf("BitN=%d, want %d", x.BitN, 2+10000+32768)
+	} else if x.PageN != 2 {
+		t.Fatalf("PageN=%d, want 2", x.PageN)
+	}
+
+	if y := stats[1]; y.Name != "y" || y.PageN != 1 || y.RowN != 0 || y.ContainerN != 0 {
+		t.Fatalf("unexpected empty bitmap stats: %+v", y)
+	}
+}
+
 func TestTx_DeleteBitmap(t *testing.T) {
 	db := MustOpenDB(t)
 	defer MustCloseDB(t, db)
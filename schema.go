@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"sort"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 
 // Ensure type implements interface.
 var _ SchemaAPI = (*onPremSchema)(nil)
+var _ FieldAlterer = (*onPremSchema)(nil)
 
 type onPremSchema struct {
 	api *API
@@ -92,10 +94,13 @@ func (s *onPremSchema) CreateTable(ctx context.Context, tbl *dax.Table) error {
 	}
 
 	iopts := IndexOptions{
-		Keys:           keyed,
-		TrackExistence: true,
-		PartitionN:     tbl.PartitionN,
-		Description:    tbl.Description,
+		Keys:            keyed,
+		TrackExistence:  true,
+		PartitionN:      tbl.PartitionN,
+		Description:     tbl.Description,
+		RetentionField:  string(tbl.RetentionField),
+		RetentionPeriod: tbl.RetentionPeriod,
+		PrimaryKey:      primaryKeyNames(tbl.PrimaryKey),
 	}
 
 	// Add the index.
@@ -119,8 +124,29 @@ func (s *onPremSchema) CreateField(ctx context.Context, tname dax.TableName, fld
 		return errors.Wrapf(err, "creating field options from field: %s", fld.Name)
 	}
 
-	_, err = s.api.CreateField(ctx, string(tname), string(fld.Name), opts...)
-	return err
+	if _, err = s.api.CreateField(ctx, string(tname), string(fld.Name), opts...); err != nil {
+		return err
+	}
+
+	// Each indexed path of a JSON field gets a keyed set field.
+	if fld.Type == dax.BaseTypeJSON {
+		for _, path := range fld.Options.JSONPaths {
+			pname := JSONPathFieldName(string(fld.Name), path)
+			if _, err := s.api.CreateField(ctx, string(tname), pname,
+				OptFieldTypeSet(DefaultCacheType, DefaultCacheSize), OptFieldKeys()); err != nil {
+				return errors.Wrapf(err, "creating field for json path: %s", path)
+			}
+		}
+	}
+
+	// The grid cells of a geopoint field are kept in a set field.
+	if fld.Type == dax.BaseTypeGeoPoint {
+		if _, err := s.api.CreateField(ctx, string(tname), GeoCellFieldName(string(fld.Name)),
+			OptFieldTypeSet(CacheTypeNone, 0), OptFieldKeys()); err != nil {
+			return errors.Wrap(err, "creating field for geopoint cells")
+		}
+	}
+	return nil
 }
 
 func (s *onPremSchema) DeleteTable(ctx context.Context, tname dax.TableName) error {
@@ -128,7 +154,36 @@ func (s *onPremSchema) DeleteTable(ctx context.Context, tname dax.TableName) err
 }
 
 func (s *onPremSchema) DeleteField(ctx context.Context, tname dax.TableName, fname dax.FieldName) error {
-	return s.api.DeleteField(ctx, string(tname), string(fname))
+	fld := s.api.holder.Field(string(tname), string(fname))
+	if err := s.api.DeleteField(ctx, string(tname), string(fname)); err != nil {
+		return err
+	}
+
+	// Drop the fields derived from a JSON or geopoint field along with it.
+	if fld == nil {
+		return nil
+	}
+	for _, dname := range derivedFieldNames(string(fname), fld.Options()) {
+		if s.api.holder.Field(string(tname), dname) == nil {
+			continue
+		}
+		if err := s.api.DeleteField(ctx, string(tname), dname); err != nil {
+			return errors.Wrapf(err, "deleting derived field: %s", dname)
+		}
+	}
+	return nil
+}
+
+func (s *onPremSchema) AlterField(ctx context.Context, tname dax.TableName, fld *dax.Field) error {
+	opts, err := FieldOptionsFromField(fld)
+	if err != nil {
+		return errors.Wrapf(err, "creating field options from field: %s", fld.Name)
+	}
+	return s.api.AlterField(ctx, string(tname), string(fld.Name), opts...)
+}
+
+func (s *onPremSchema) RenameField(ctx context.Context, tname dax.TableName, fname, newName dax.FieldName) error {
+	return s.api.RenameField(ctx, string(tname), string(fname), string(newName))
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -151,6 +206,19 @@ func IndexInfosToTables(iis []*IndexInfo) []*dax.Table {
 	return tbls
 }
 
+// primaryKeyNames returns the names of a table's primary key fields as the
+// strings used in IndexOptions.
+func primaryKeyNames(pk []dax.FieldName) []string {
+	if len(pk) == 0 {
+		return nil
+	}
+	names := make([]string, len(pk))
+	for i := range pk {
+		names[i] = string(pk[i])
+	}
+	return names
+}
+
 // IndexInfoToTable converts a featurebase.IndexInfo to a dax.Table.
 func IndexInfoToTable(ii *IndexInfo) *dax.Table {
 	tbl := &dax.Table{
@@ -160,10 +228,16 @@ func IndexInfoToTable(ii *IndexInfo) *dax.Table {
 		Fields:     make([]*dax.Field, 0, len(ii.Fields)+1), // +1 to account for the _id field
 		PartitionN: dax.DefaultPartitionN,
 
+		RetentionField:  dax.FieldName(ii.Options.RetentionField),
+		RetentionPeriod: ii.Options.RetentionPeriod,
+
 		Description: ii.Options.Description,
 		Owner:       ii.Owner,
 		UpdatedBy:   ii.LastUpdateUser,
 	}
+	for _, pk := range ii.Options.PrimaryKey {
+		tbl.PrimaryKey = append(tbl.PrimaryKey, dax.FieldName(pk))
+	}
 
 	// Sort ii.Fields by CreatedAt before adding them to sortedFields.
 	sort.Slice(ii.Fields, func(i, j int) bool {
@@ -180,8 +254,23 @@ func IndexInfoToTable(ii *IndexInfo) *dax.Table {
 		Type: idType,
 	})
 
+	// Fields which index the paths of a JSON field, or the cells of a
+	// geopoint field, are maintained along with that field, so they aren't
+	// exposed as columns of the table. Nor is the field a column's values
+	// are rewritten into while its type is altered.
+	derivedFields := make(map[string]struct{})
+	for _, fld := range ii.Fields {
+		derivedFields[AlterFieldName(fld.Name)] = struct{}{}
+		for _, dname := range derivedFieldNames(fld.Name, fld.Options) {
+			derivedFields[dname] = struct{}{}
+		}
+	}
+
 	// Populate the rest of the fields.
 	for _, fld := range ii.Fields {
+		if _, ok := derivedFields[fld.Name]; ok {
+			continue
+		}
 		tbl.Fields = append(tbl.Fields, FieldInfoToField(fld))
 	}
 
@@ -217,6 +306,10 @@ func FieldInfoToField(fi *FieldInfo) *dax.Field {
 	case FieldTypeSet:
 		if fo.Keys {
 			fieldType = dax.BaseTypeStringSet
+		} else if fo.BitVector > 0 {
+			fieldType = dax.BaseTypeBitVector
+		} else if fo.JSON {
+			fieldType = dax.BaseTypeJSON
 		} else {
 			fieldType = dax.BaseTypeIDSet
 		}
@@ -226,16 +319,23 @@ func FieldInfoToField(fi *FieldInfo) *dax.Field {
 		min = fo.Min
 		max = fo.Max
 		fieldType = dax.BaseTypeInt
+		if fo.GeoPoint {
+			fieldType = dax.BaseTypeGeoPoint
+		}
 		foreignIndex = fo.ForeignIndex
+		timeQuantum = dax.TimeQuantum(fo.TimeQuantum)
 	case FieldTypeDecimal:
 		min = fo.Min
 		max = fo.Max
 		scale = fo.Scale
 		fieldType = dax.BaseTypeDecimal
+		timeQuantum = dax.TimeQuantum(fo.TimeQuantum)
 	case FieldTypeTimestamp:
 		epoch = featurebaseFieldOptionsToEpoch(fo)
 		timeUnit = fo.TimeUnit
 		fieldType = dax.BaseTypeTimestamp
+	case FieldTypeFloat:
+		fieldType = dax.BaseTypeDouble
 	case FieldTypeBool:
 		fieldType = dax.BaseTypeBool
 	case FieldTypeTime:
@@ -265,6 +365,11 @@ func FieldInfoToField(fi *FieldInfo) *dax.Field {
 			TTL:            fo.TTL,
 			ForeignIndex:   foreignIndex,
 			TrackExistence: fo.TrackExistence,
+			JSONPaths:      fo.JSONPaths,
+			Width:          fo.BitVector,
+			NotNull:        fo.NotNull,
+			Default:        fo.Default,
+			Check:          fo.Check,
 		},
 	}
 }
@@ -306,9 +411,12 @@ func TableToIndexInfo(tbl *dax.Table) *IndexInfo {
 		Owner:          tbl.Owner,
 		LastUpdateUser: tbl.UpdatedBy,
 		Options: IndexOptions{
-			Keys:           tbl.StringKeys(),
-			TrackExistence: true,
-			Description:    tbl.Description,
+			Keys:            tbl.StringKeys(),
+			TrackExistence:  true,
+			Description:     tbl.Description,
+			RetentionField:  string(tbl.RetentionField),
+			RetentionPeriod: tbl.RetentionPeriod,
+			PrimaryKey:      primaryKeyNames(tbl.PrimaryKey),
 		},
 		ShardWidth: ShardWidth,
 	}
@@ -350,6 +458,13 @@ func FieldToFieldInfo(fld *dax.Field) *FieldInfo {
 		base = timestampOptions.Base
 		min = timestampOptions.Min
 		max = timestampOptions.Max
+	case dax.BaseTypeDouble:
+		// the bsiGroup of a float field spans every int64
+		min, max = pql.MinMax(0)
+	case dax.BaseTypeGeoPoint:
+		// as does that of a geopoint field, since points are packed into
+		// the full width of the value
+		min, max = pql.MinMax(0)
 	}
 
 	return &FieldInfo{
@@ -369,6 +484,13 @@ func FieldToFieldInfo(fld *dax.Field) *FieldInfo {
 			TTL:            fld.Options.TTL,
 			ForeignIndex:   fld.Options.ForeignIndex,
 			TrackExistence: fld.Options.TrackExistence,
+			JSON:           fld.Type == dax.BaseTypeJSON,
+			JSONPaths:      fld.Options.JSONPaths,
+			GeoPoint:       fld.Type == dax.BaseTypeGeoPoint,
+			BitVector:      fld.Options.Width,
+			NotNull:        fld.Options.NotNull,
+			Default:        fld.Options.Default,
+			Check:          fld.Options.Check,
 		},
 		Views: nil, // TODO(tlt): do we need views populated?
 	}
@@ -401,12 +523,21 @@ func fieldToFieldType(f *dax.Field) string {
 		}
 		return "mutex"
 
-	case dax.BaseTypeIDSet, dax.BaseTypeStringSet:
+	case dax.BaseTypeJSON:
+		return FieldTypeSet
+
+	case dax.BaseTypeIDSet, dax.BaseTypeStringSet, dax.BaseTypeBitVector:
 		return "set"
 
 	case dax.BaseTypeIDSetQ, dax.BaseTypeStringSetQ:
 		return "time"
 
+	case dax.BaseTypeDouble:
+		return FieldTypeFloat
+
+	case dax.BaseTypeGeoPoint:
+		return FieldTypeInt
+
 	default:
 		return string(f.Type)
 	}
@@ -448,6 +579,11 @@ func FieldOptionsFromField(fld *dax.Field) ([]FieldOption, error) {
 	opts := []FieldOption{}
 
 	switch fld.Type {
+	case dax.BaseTypeBitVector:
+		opts = append(opts,
+			OptFieldTypeSet(CacheTypeNone, 0),
+			OptFieldBitVector(fld.Options.Width),
+		)
 	case dax.BaseTypeBool:
 		opts = append(opts,
 			OptFieldTypeBool(),
@@ -456,6 +592,10 @@ func FieldOptionsFromField(fld *dax.Field) ([]FieldOption, error) {
 		opts = append(opts,
 			OptFieldTypeDecimal(fld.Options.Scale, fld.Options.Min, fld.Options.Max),
 		)
+	case dax.BaseTypeDouble:
+		opts = append(opts,
+			OptFieldTypeFloat(),
+		)
 	case dax.BaseTypeID:
 		opts = append(opts,
 			OptFieldTypeMutex(cacheType, cacheSize),
@@ -472,11 +612,21 @@ func FieldOptionsFromField(fld *dax.Field) ([]FieldOption, error) {
 		opts = append(opts,
 			OptFieldTypeInt(fld.Options.Min.ToInt64(0), fld.Options.Max.ToInt64(0)),
 		)
+	case dax.BaseTypeGeoPoint:
+		opts = append(opts,
+			OptFieldTypeInt(math.MinInt64, math.MaxInt64),
+			OptFieldGeoPoint(),
+		)
 	case dax.BaseTypeString:
 		opts = append(opts,
 			OptFieldTypeMutex(cacheType, cacheSize),
 			OptFieldKeys(),
 		)
+	case dax.BaseTypeJSON:
+		opts = append(opts,
+			OptFieldTypeSet(CacheTypeNone, 0),
+			OptFieldJSON(fld.Options.JSONPaths...),
+		)
 	case dax.BaseTypeStringSet:
 		opts = append(opts,
 			OptFieldTypeSet(cacheType, cacheSize),
@@ -494,9 +644,24 @@ func FieldOptionsFromField(fld *dax.Field) ([]FieldOption, error) {
 	default:
 		return nil, errors.Errorf("unsupport field type: %s", fld.Type)
 	}
+	switch fld.Type {
+	case dax.BaseTypeInt, dax.BaseTypeDecimal:
+		if fld.Options.TimeQuantum != "" {
+			opts = append(opts, OptFieldTimeQuantum(TimeQuantum(fld.Options.TimeQuantum), fld.Options.TTL.String()))
+		}
+	}
 	if fld.Options.TrackExistence {
 		opts = append(opts, OptFieldTrackExistence())
 	}
+	if fld.Options.NotNull {
+		opts = append(opts, OptFieldNotNull())
+	}
+	if fld.Options.Default != "" {
+		opts = append(opts, OptFieldDefault(fld.Options.Default))
+	}
+	if fld.Options.Check != "" {
+		opts = append(opts, OptFieldCheck(fld.Options.Check))
+	}
 
 	return opts, nil
 }
@@ -18,10 +18,12 @@ import (
 
 	uuid "github.com/satori/go.uuid"
 
+	fbcontext "github.com/featurebasedb/featurebase/v3/context"
 	daxstorage "github.com/featurebasedb/featurebase/v3/dax/storage"
 	"github.com/featurebasedb/featurebase/v3/disco"
 	"github.com/featurebasedb/featurebase/v3/logger"
 	pnet "github.com/featurebasedb/featurebase/v3/net"
+	"github.com/featurebasedb/featurebase/v3/pql"
 	rbfcfg "github.com/featurebasedb/featurebase/v3/rbf/cfg"
 	"github.com/featurebasedb/featurebase/v3/roaring"
 	"github.com/featurebasedb/featurebase/v3/sql3"
@@ -70,17 +72,20 @@ type Server struct { // nolint: maligned
 	logger      logger.Logger
 	queryLogger logger.Logger
 
-	nodeID               string
-	uri                  pnet.URI
-	grpcURI              pnet.URI
-	metricInterval       time.Duration
-	diagnosticInterval   time.Duration
-	viewsRemovalInterval time.Duration
-	maxWritesPerRequest  int
-	confirmDownSleep     time.Duration
-	confirmDownRetries   int
-	syncer               holderSyncer
-	maxQueryMemory       int64
+	nodeID                    string
+	uri                       pnet.URI
+	grpcURI                   pnet.URI
+	metricInterval            time.Duration
+	diagnosticInterval        time.Duration
+	viewsRemovalInterval      time.Duration
+	retentionInterval         time.Duration
+	materializedViewsInterval time.Duration
+	cursorIdleTimeout         time.Duration
+	maxWritesPerRequest       int
+	confirmDownSleep          time.Duration
+	confirmDownRetries        int
+	syncer                    holderSyncer
+	maxQueryMemory            int64
 
 	translationSyncer      TranslationSyncer
 	resetTranslationSyncCh chan struct{}
@@ -95,6 +100,7 @@ type Server struct { // nolint: maligned
 	// Threshold for logging long-running queries
 	longQueryTime      time.Duration
 	queryHistoryLength int
+	queryHistory       *queryHistory
 
 	executionPlannerFn ExecutionPlannerFn
 
@@ -196,6 +202,36 @@ func OptServerViewsRemovalInterval(interval time.Duration) ServerOption {
 	}
 }
 
+// OptServerRetentionInterval is a functional option on Server
+// used to set how often records which have outlived their index's
+// retention period are deleted.
+func OptServerRetentionInterval(interval time.Duration) ServerOption {
+	return func(s *Server) error {
+		s.retentionInterval = interval
+		return nil
+	}
+}
+
+// OptServerMaterializedViewsInterval is a functional option on Server
+// used to set how often materialized views are checked for a scheduled
+// refresh.
+func OptServerMaterializedViewsInterval(interval time.Duration) ServerOption {
+	return func(s *Server) error {
+		s.materializedViewsInterval = interval
+		return nil
+	}
+}
+
+// OptServerCursorIdleTimeout is a functional option on Server
+// used to set how long a sql cursor can go without being fetched from
+// before it is closed.
+func OptServerCursorIdleTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) error {
+		s.cursorIdleTimeout = timeout
+		return nil
+	}
+}
+
 // OptServerLongQueryTime is a functional option on Server
 // used to set long query duration.
 func OptServerLongQueryTime(dur time.Duration) ServerOption {
@@ -488,9 +524,12 @@ func NewServer(opts ...ServerOption) (*Server, error) {
 
 		gcNotifier: NopGCNotifier,
 
-		metricInterval:       0,
-		diagnosticInterval:   0,
-		viewsRemovalInterval: time.Hour,
+		metricInterval:            0,
+		diagnosticInterval:        0,
+		viewsRemovalInterval:      time.Hour,
+		retentionInterval:         10 * time.Minute,
+		materializedViewsInterval: time.Minute,
+		cursorIdleTimeout:         10 * time.Minute,
 
 		disCo:      disco.NopDisCo,
 		noder:      disco.NewEmptyLocalNoder(),
@@ -525,6 +564,8 @@ func NewServer(opts ...ServerOption) (*Server, error) {
 		}
 	}
 
+	s.queryHistory = newQueryHistory(s.queryHistoryLength)
+
 	memTotal, err := s.systemInfo.MemTotal()
 	if err != nil {
 		return nil, errors.Wrap(err, "mem total")
@@ -705,16 +746,26 @@ func (s *Server) Open() error {
 	// bring up the background tasks for the holder.
 	s.holder.Activate()
 
+	// keep the query history alongside the data, so that it survives a restart
+	if path := s.holder.Path(); path != "" {
+		if err := s.queryHistory.open(filepath.Join(path, queryHistoryFile)); err != nil {
+			return errors.Wrap(err, "opening query history")
+		}
+	}
+
 	if err := s.noder.SetState(context.Background(), disco.NodeStateStarted); err != nil {
 		return errors.Wrap(err, "setting nodeState")
 	}
 
-	if ok := s.addToWaitGroup(3); !ok {
+	if ok := s.addToWaitGroup(6); !ok {
 		return fmt.Errorf("closing server while opening server is NOT allowed")
 	}
 	go func() { defer s.wg.Done(); s.monitorRuntime() }()
 	go func() { defer s.wg.Done(); s.monitorDiagnostics() }()
 	go func() { defer s.wg.Done(); s.monitorViewsRemoval() }()
+	go func() { defer s.wg.Done(); s.monitorRetention() }()
+	go func() { defer s.wg.Done(); s.monitorMaterializedViews() }()
+	go func() { defer s.wg.Done(); s.monitorCursors() }()
 
 	toSend := func() []Message {
 		s.holder.startMsgsMu.Lock()
@@ -838,6 +889,7 @@ func (s *Server) Close() error {
 		if s.holder != nil {
 			errh = s.holder.Close()
 		}
+		errq := s.queryHistory.close()
 		if s.serverlessStorage != nil {
 			errSS = s.serverlessStorage.RemoveAll()
 		}
@@ -861,6 +913,9 @@ func (s *Server) Close() error {
 		if errE != nil {
 			return errors.Wrap(errE, "closing executor")
 		}
+		if errq != nil {
+			return errq
+		}
 		return errors.Wrap(errSS, "unlocking all serverless storage")
 	}
 }
@@ -920,14 +975,13 @@ func (s *Server) monitorViewsRemoval() {
 func (s *Server) ViewsRemoval(ctx context.Context) {
 	for _, index := range s.holder.Indexes() {
 		for _, field := range index.Fields() {
-			if field.Options().Type == "time" {
+			if field.Options().TimeQuantum != "" {
 				if field.Options().TTL > 0 {
 					for _, view := range field.views() {
-						// view names follow the format of "standard_(time_quantum)"
-						// to get view time, we split the view.name by "_"
-						// then grab the second value (the time quantum)
-						viewName := strings.Split(view.name, "_")
-						if len(viewName) == 2 {
+						// view names follow the format of "standard_(time_quantum)",
+						// or "bsig_(field)_(time_quantum)" for int and decimal fields
+						viewName := view.name
+						if field.isTimeView(viewName) {
 							// when getting the view time, we want to grab the end date
 							// because start date will aways be older
 							viewTime, err := timeOfView(view.name, true)
@@ -954,7 +1008,7 @@ func (s *Server) ViewsRemoval(ctx context.Context) {
 						}
 					}
 				}
-				if field.Options().NoStandardView {
+				if field.Options().Type == FieldTypeTime && field.Options().NoStandardView {
 					if field.view(viewStandard) != nil {
 						// delete view "standard" if NoStandardView is true and view "standard" exists
 						for _, shard := range field.AvailableShards(true).Slice() {
@@ -991,6 +1045,195 @@ func (s *Server) ViewsRemoval(ctx context.Context) {
 	}
 }
 
+func (s *Server) monitorRetention() {
+	if s.retentionInterval <= 0 {
+		return
+	}
+	ctx := context.Background()
+	ticker := time.NewTicker(s.retentionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+			s.RetentionRemoval(ctx)
+		}
+	}
+}
+
+// RetentionRemoval deletes, from every index with a retention policy, the
+// records whose retention field is older than the index's retention period.
+// Each node removes records from the shards it holds, so replicas are
+// expired along with primaries.
+func (s *Server) RetentionRemoval(ctx context.Context) {
+	now := time.Now()
+	for _, index := range s.holder.Indexes() {
+		opt := index.Options()
+		if opt.RetentionField == "" || opt.RetentionPeriod <= 0 {
+			continue
+		}
+		if err := s.removeExpiredRecords(ctx, index, opt.RetentionField, now.Add(-opt.RetentionPeriod)); err != nil {
+			s.logger.Errorf("index: %s, retention delete: %s", index.Name(), err)
+		}
+	}
+}
+
+// removeExpiredRecords deletes the records of index, in the shards owned by
+// this node, whose value in the timestamp field fieldName is before cutoff.
+func (s *Server) removeExpiredRecords(ctx context.Context, index *Index, fieldName string, cutoff time.Time) error {
+	field := index.Field(fieldName)
+	if field == nil {
+		return newNotFoundError(ErrFieldNotFound, fieldName)
+	}
+	if field.Type() != FieldTypeTimestamp {
+		return errors.Errorf("retention field %s is of type %s, not %s", fieldName, field.Type(), FieldTypeTimestamp)
+	}
+
+	snap := s.cluster.NewSnapshot()
+	var shards []uint64
+	for _, shard := range index.AvailableShards(includeRemote).Slice() {
+		if snap.OwnsShard(s.nodeID, index.Name(), shard) {
+			shards = append(shards, shard)
+		}
+	}
+	if len(shards) == 0 {
+		return nil
+	}
+
+	// Delete(Row(<field> < <cutoff>))
+	c := &pql.Call{
+		Name: "Delete",
+		Children: []*pql.Call{{
+			Name: "Row",
+			Args: map[string]interface{}{
+				fieldName: &pql.Condition{Op: pql.LT, Value: cutoff},
+			},
+		}},
+	}
+	qcx := s.holder.Txf().NewQcx()
+	defer qcx.Abort()
+	changed, err := s.executor.executeDeleteRecords(ctx, qcx, index.Name(), c, shards, &ExecOptions{Remote: true})
+	if err != nil {
+		return err
+	}
+	if changed {
+		s.logger.Infof("retention deleted - index: %s, field: %s, before: %s", index.Name(), fieldName, cutoff.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (s *Server) monitorMaterializedViews() {
+	if s.materializedViewsInterval <= 0 {
+		return
+	}
+	ctx := context.Background()
+	ticker := time.NewTicker(s.materializedViewsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+			// only the primary refreshes, so a view isn't refreshed by every
+			// node at once
+			if s.IsPrimary() {
+				s.RefreshMaterializedViews(ctx)
+			}
+		}
+	}
+}
+
+// monitorCursors closes the sql cursors which have been idle for longer than
+// the cursor idle timeout.
+func (s *Server) monitorCursors() {
+	if s.cursorIdleTimeout <= 0 || s.SystemLayer == nil {
+		return
+	}
+	// check a few times per timeout, so that cursors don't outlive the
+	// timeout by much
+	ticker := time.NewTicker(s.cursorIdleTimeout / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+			for _, id := range s.SystemLayer.Cursors().ExpireCursors(s.cursorIdleTimeout) {
+				s.logger.Infof("cursor %s expired after being idle for %s", id, s.cursorIdleTimeout)
+			}
+		}
+	}
+}
+
+// RefreshMaterializedViews refreshes the materialized views which have a
+// refresh interval, and which haven't been refreshed within it.
+func (s *Server) RefreshMaterializedViews(ctx context.Context) {
+	if s.holder.Index("fb_materialized_views") == nil {
+		// no materialized views have been created
+		return
+	}
+
+	var due []string
+	err := s.execSQL(ctx, "SELECT name, refresh_interval, last_refresh FROM fb_materialized_views", func(row planner_types.Row) {
+		name, _ := row[0].(string)
+		interval, _ := row[1].(int64)
+		if name == "" || interval <= 0 {
+			return
+		}
+		lastRefresh, ok := row[2].(time.Time)
+		if ok && time.Since(lastRefresh) < time.Duration(interval)*time.Second {
+			return
+		}
+		due = append(due, name)
+	})
+	if err != nil {
+		s.logger.Errorf("listing materialized views: %v", err)
+		return
+	}
+
+	for _, name := range due {
+		if err := s.execSQL(ctx, "REFRESH MATERIALIZED VIEW "+name, nil); err != nil {
+			s.logger.Errorf("refreshing materialized view '%s': %v", name, err)
+		}
+	}
+}
+
+// execSQL runs a SQL statement on behalf of the server, calling fn (if
+// non-nil) for each row of its results.
+func (s *Server) execSQL(ctx context.Context, sql string, fn func(row planner_types.Row)) error {
+	requestID, err := uuid.NewV4()
+	if err != nil {
+		return errors.Wrap(err, "generating request id")
+	}
+	ctx = fbcontext.WithRequestID(ctx, requestID.String())
+
+	op, err := s.CompileExecutionPlan(ctx, sql)
+	if err != nil {
+		return err
+	}
+	if op == nil {
+		// no sql3 planner
+		return nil
+	}
+	iter, err := op.Iterator(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for {
+		row, err := iter.Next(ctx)
+		if err == planner_types.ErrNoMoreRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if fn != nil {
+			fn(row)
+		}
+	}
+}
+
 // receiveMessage represents an implementation of BroadcastHandler.
 func (s *Server) receiveMessage(m Message) error {
 	switch obj := m.(type) {
@@ -1030,6 +1273,15 @@ func (s *Server) receiveMessage(m Message) error {
 			return err
 		}
 
+	case *RenameFieldMessage:
+		idx := s.holder.Index(obj.Index)
+		if idx == nil {
+			return fmt.Errorf("local index not found: %s", obj.Index)
+		}
+		if err := idx.RenameFieldLocal(obj.Field, obj.NewName); err != nil {
+			return err
+		}
+
 	case *DeleteAvailableShardMessage:
 		f := s.holder.Field(obj.Index, obj.Field)
 		if err := f.RemoveAvailableShard(obj.ShardID); err != nil {
@@ -159,7 +159,7 @@ func isAllowed(requested []string, allowed []string) bool {
 // QuerySQL handles the SQL request and sends RowResponses to the stream.
 func (h *GRPCHandler) QuerySQL(req *pb.QuerySQLRequest, stream pb.Pilosa_QuerySQLServer) error {
 	ctx := stream.Context()
-	if uinfo, ok := authn.GetUserInfo(ctx); ok && uinfo != nil {
+	if uinfo, ok := authn.GetUserInfo(ctx); ok && uinfo != nil && req.CursorId == "" {
 		// authz
 		m := sql.NewMapper()
 		parsed, err := m.MapSQL(req.Sql)
@@ -187,7 +187,13 @@ func (h *GRPCHandler) QuerySQL(req *pb.QuerySQLRequest, stream pb.Pilosa_QuerySQ
 
 	span.SetTag("SQL Query", req.Sql)
 	start := time.Now()
-	results, err := h.execSQL(ctx, req.Sql)
+	var results pb.ToRowser
+	var err error
+	if req.PageSize > 0 || req.CursorId != "" {
+		results, err = execSQLPage(ctx, h.api, req)
+	} else {
+		results, err = h.execSQL(ctx, req.Sql)
+	}
 	duration := time.Since(start)
 	monitor.Finish(span)
 	if err != nil {
@@ -222,7 +228,7 @@ func (h *GRPCHandler) QuerySQL(req *pb.QuerySQLRequest, stream pb.Pilosa_QuerySQ
 // https://github.com/molecula/pilosa/pull/644
 func (h *GRPCHandler) QuerySQLUnary(ctx context.Context, req *pb.QuerySQLRequest) (*pb.TableResponse, error) {
 	start := time.Now()
-	if uinfo, _ := authn.GetUserInfo(ctx); uinfo != nil {
+	if uinfo, _ := authn.GetUserInfo(ctx); uinfo != nil && req.CursorId == "" {
 		// authz
 		m := sql.NewMapper()
 		parsed, err := m.MapSQL(req.Sql)
@@ -245,7 +251,13 @@ func (h *GRPCHandler) QuerySQLUnary(ctx context.Context, req *pb.QuerySQLRequest
 		}
 	}
 
-	results, err := h.execSQL(ctx, req.Sql)
+	var results pb.ToRowser
+	var err error
+	if req.PageSize > 0 || req.CursorId != "" {
+		results, err = execSQLPage(ctx, h.api, req)
+	} else {
+		results, err = h.execSQL(ctx, req.Sql)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1036,6 +1048,32 @@ func (h *GRPCHandler) Inspect(req *pb.InspectRequest, stream pb.Pilosa_InspectSe
 							&pb.ColumnResponse{ColumnVal: nil})
 					}
 
+				case "float64":
+					pql := fmt.Sprintf("FieldValue(field=%s, column=%d)", field.Name(), col)
+					query := pilosa.QueryRequest{
+						Index: req.Index,
+						Query: pql,
+					}
+					resp, err := h.api.Query(stream.Context(), &query)
+					if err != nil {
+						return errors.Wrap(err, "getting float field value for column")
+					}
+
+					if len(resp.Results) > 0 {
+						valCount, ok := resp.Results[0].(pilosa.ValCount)
+						if ok && valCount.Count == 1 {
+							rowResp.Columns = append(rowResp.Columns,
+								&pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_Float64Val{Float64Val: valCount.FloatVal}})
+							colAdded++
+						} else {
+							rowResp.Columns = append(rowResp.Columns,
+								&pb.ColumnResponse{ColumnVal: nil})
+						}
+					} else {
+						rowResp.Columns = append(rowResp.Columns,
+							&pb.ColumnResponse{ColumnVal: nil})
+					}
+
 				case "bool":
 					pql := fmt.Sprintf("Rows(%s, column=%d)", field.Name(), col)
 					query := pilosa.QueryRequest{
@@ -1364,6 +1402,32 @@ func (h *GRPCHandler) Inspect(req *pb.InspectRequest, stream pb.Pilosa_InspectSe
 							&pb.ColumnResponse{ColumnVal: nil})
 					}
 
+				case "float64":
+					pql := fmt.Sprintf("FieldValue(field=%s, column='%s')", field.Name(), col)
+					query := pilosa.QueryRequest{
+						Index: req.Index,
+						Query: pql,
+					}
+					resp, err := h.api.Query(stream.Context(), &query)
+					if err != nil {
+						return errors.Wrap(err, "getting float field value for column")
+					}
+
+					if len(resp.Results) > 0 {
+						valCount, ok := resp.Results[0].(pilosa.ValCount)
+						if ok && valCount.Count == 1 {
+							rowResp.Columns = append(rowResp.Columns,
+								&pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_Float64Val{Float64Val: valCount.FloatVal}})
+							colAdded++
+						} else {
+							rowResp.Columns = append(rowResp.Columns,
+								&pb.ColumnResponse{ColumnVal: nil})
+						}
+					} else {
+						rowResp.Columns = append(rowResp.Columns,
+							&pb.ColumnResponse{ColumnVal: nil})
+					}
+
 				case "bool":
 					pql := fmt.Sprintf("Rows(%s, column=\"%s\")", field.Name(), col)
 					query := pilosa.QueryRequest{
@@ -1449,6 +1513,8 @@ func fieldDataType(f *pilosa.Field) string {
 		return "int64"
 	case "decimal":
 		return "decimal"
+	case "float":
+		return "float64"
 	case "bool":
 		return "bool"
 	case "time":
@@ -1078,6 +1078,84 @@ func TestQuerySQL(t *testing.T) {
 	}
 }
 
+func TestQuerySQLPaged(t *testing.T) {
+	stream := &MockServerTransportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	m := test.RunCommand(t)
+	defer m.Close()
+	gh := server.NewGRPCHandler(m.API)
+
+	// paged requests are run by sql3, so they can create the table too
+	for _, sql := range []string{
+		"create table paged (_id id, v int)",
+		"insert into paged (_id, v) values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (6, 6), (7, 7), (8, 8), (9, 9), (10, 10)",
+	} {
+		if _, err := gh.QuerySQLUnary(ctx, &pb.QuerySQLRequest{Sql: sql, PageSize: 1}); err != nil {
+			t.Fatalf("sql: %s, error: %v", sql, err)
+		}
+	}
+
+	t.Run("unary", func(t *testing.T) {
+		ids := make(map[int64]struct{})
+		req := &pb.QuerySQLRequest{Sql: "select _id from paged", PageSize: 4}
+		for pages := 1; ; pages++ {
+			resp, err := gh.QuerySQLUnary(ctx, req)
+			if err != nil {
+				t.Fatalf("page %d: %v", pages, err)
+			}
+			if len(resp.Rows) > 4 {
+				t.Fatalf("page %d: expected at most 4 rows, got %d", pages, len(resp.Rows))
+			}
+			for _, row := range resp.Rows {
+				ids[row.Columns[0].GetInt64Val()] = struct{}{}
+			}
+			if resp.CursorId == "" {
+				if pages != 3 {
+					t.Fatalf("expected 3 pages, got %d", pages)
+				}
+				break
+			}
+			req = &pb.QuerySQLRequest{CursorId: resp.CursorId, PageSize: 4}
+		}
+		if len(ids) != 10 {
+			t.Fatalf("expected 10 distinct ids, got %d", len(ids))
+		}
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		mock := &mockPilosa_QuerySQLServer{ctx: context.Background()}
+		err := gh.QuerySQL(&pb.QuerySQLRequest{Sql: "select _id from paged", PageSize: 6}, mock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mock.Results) != 6 {
+			t.Fatalf("expected 6 rows, got %d", len(mock.Results))
+		}
+		cursorID := mock.Results[0].CursorId
+		if cursorID == "" {
+			t.Fatal("expected a cursor id")
+		}
+
+		mock = &mockPilosa_QuerySQLServer{ctx: context.Background()}
+		err = gh.QuerySQL(&pb.QuerySQLRequest{CursorId: cursorID, PageSize: 6}, mock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mock.Results) != 4 {
+			t.Fatalf("expected 4 rows, got %d", len(mock.Results))
+		}
+		if mock.Results[0].CursorId != "" {
+			t.Fatalf("expected no cursor id, got %s", mock.Results[0].CursorId)
+		}
+
+		// the cursor is closed once it has been read to the end
+		err = gh.QuerySQL(&pb.QuerySQLRequest{CursorId: cursorID, PageSize: 6}, mock)
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Fatalf("expected cursor not found error, got %v", err)
+		}
+	})
+}
+
 func TestQuerySQLWithError(t *testing.T) {
 
 	stream := &MockServerTransportStream{}
@@ -1382,6 +1382,22 @@ func TestHandler_Endpoints(t *testing.T) {
 	})
 }
 
+// Ensure records can't be posted to a server without a record ingester.
+func TestHandler_PostIngestUnsupported(t *testing.T) {
+	cluster := test.MustRunUnsharedCluster(t, 1)
+	defer cluster.Close()
+	cmd := cluster.GetNode(0)
+	test.Do(t, "POST", cmd.URL()+"/index/events", "")
+
+	w := httptest.NewRecorder()
+	r := test.MustNewHTTPRequest("POST", "/index/events/ingest", strings.NewReader("id\n1\n"))
+	r.Header.Set("Content-Type", "text/csv")
+	cmd.Handler.(*pilosa.Handler).Handler.ServeHTTP(w, r)
+	if w.Code != http.StatusNotImplemented {
+		t.Fatalf("unexpected status code: %d", w.Code)
+	}
+}
+
 func TestCluster_TranslateStore(t *testing.T) {
 	cluster := test.MustRunUnsharedCluster(t, 1, []server.CommandOption{
 		server.OptCommandServerOptions(
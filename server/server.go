@@ -98,6 +98,8 @@ type Command struct {
 	serverOptions []pilosa.ServerOption
 	auth          *authn.Auth
 
+	newRecordIngester func(api *pilosa.API, tls TLSConfig) pilosa.RecordIngester
+
 	// isComputeNode is set to true if this node is running as a DAX compute
 	// node.
 	isComputeNode bool
@@ -117,6 +119,16 @@ func OptCommandServerOptions(opts ...pilosa.ServerOption) CommandOption {
 	}
 }
 
+// OptCommandRecordIngester sets the function which creates the ingester
+// for records posted to the handler. Without one, posted records aren't
+// ingested.
+func OptCommandRecordIngester(fn func(api *pilosa.API, tls TLSConfig) pilosa.RecordIngester) CommandOption {
+	return func(c *Command) error {
+		c.newRecordIngester = fn
+		return nil
+	}
+}
+
 func OptCommandCloseTimeout(d time.Duration) CommandOption {
 	return func(c *Command) error {
 		c.closeTimeout = d
@@ -575,7 +587,7 @@ func (m *Command) setupServer() error {
 		fsapi := &pilosa.FeatureBaseSystemAPI{API: api}
 		imp := pilosa.NewOnPremImporter(api)
 
-		return planner.NewExecutionPlanner(e, fapi, fsapi, m.Server.SystemLayer, imp, m.logger, sql)
+		return planner.NewExecutionPlanner(e, fapi, fsapi, api.SystemLayer(), imp, m.logger, sql)
 	}
 
 	serverOptions := []pilosa.ServerOption{
@@ -712,6 +724,14 @@ func (m *Command) setupServer() error {
 		return errors.Wrap(err, "getting grpcServer")
 	}
 
+	// Records posted to the handler can only be ingested with a record
+	// ingester, which is left to the command's caller so that the server
+	// doesn't depend on idk.
+	var ingester pilosa.RecordIngester
+	if m.newRecordIngester != nil {
+		ingester = m.newRecordIngester(m.API, m.Config.TLS)
+	}
+
 	hndlr, err := pilosa.NewHandler(
 		pilosa.OptHandlerAllowedOrigins(m.Config.Handler.AllowedOrigins),
 		pilosa.OptHandlerAPI(m.API),
@@ -725,6 +745,7 @@ func (m *Command) setupServer() error {
 		pilosa.OptHandlerAuthZ(&p),
 		pilosa.OptHandlerSerializer(proto.Serializer{}),
 		pilosa.OptHandlerRoaringSerializer(proto.RoaringSerializer),
+		pilosa.OptHandlerRecordIngester(ingester),
 	)
 	if err != nil {
 		return errors.Wrap(err, "new handler")
@@ -4,11 +4,14 @@ package server
 
 import (
 	"context"
+	"time"
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/featurebasedb/featurebase/v3/pql"
 	pb "github.com/featurebasedb/featurebase/v3/proto"
 	"github.com/featurebasedb/featurebase/v3/sql"
+	planner_types "github.com/featurebasedb/featurebase/v3/sql3/planner/types"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -37,3 +40,100 @@ func execSQL(ctx context.Context, api *pilosa.API, logger logger.Logger, querySt
 	}
 	return results, errors.Wrap(err, "failed to start SQL query")
 }
+
+// execSQLPage executes a paged sql request. Paged requests are executed by the
+// sql3 engine, since it is what keeps the cursors the pages are read from.
+func execSQLPage(ctx context.Context, api *pilosa.API, req *pb.QuerySQLRequest) (*sqlPageRowser, error) {
+	schema, rows, cursorID, err := api.QuerySQLPage(ctx, req.Sql, req.CursorId, int(req.PageSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute SQL query")
+	}
+	return &sqlPageRowser{
+		schema:   schema,
+		rows:     rows,
+		cursorID: cursorID,
+	}, nil
+}
+
+// sqlPageRowser is a page of rows from a sql3 query, which implements the
+// ToRowser and ToTabler interfaces.
+type sqlPageRowser struct {
+	schema   planner_types.Schema
+	rows     planner_types.Rows
+	cursorID string
+}
+
+// ToRows implements the ToRowser interface. Every row carries the id of the
+// cursor the next page can be read from, if there is one.
+func (r *sqlPageRowser) ToRows(callback func(*pb.RowResponse) error) error {
+	headers := make([]*pb.ColumnInfo, len(r.schema))
+	for i, col := range r.schema {
+		headers[i] = &pb.ColumnInfo{
+			Name:     col.ColumnName,
+			Datatype: col.Type.TypeDescription(),
+		}
+	}
+
+	for _, row := range r.rows {
+		cols := make([]*pb.ColumnResponse, len(row))
+		for i, v := range row {
+			col, err := sqlColumnResponse(v)
+			if err != nil {
+				return err
+			}
+			cols[i] = col
+		}
+		err := callback(&pb.RowResponse{
+			Headers:  headers,
+			Columns:  cols,
+			CursorId: r.cursorID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToTable implements the ToTabler interface.
+func (r *sqlPageRowser) ToTable() (*pb.TableResponse, error) {
+	table, err := pb.RowsToTable(r, len(r.rows))
+	if err != nil {
+		return nil, err
+	}
+	table.CursorId = r.cursorID
+	return table, nil
+}
+
+// sqlColumnResponse converts a value in a sql3 row to a ColumnResponse.
+func sqlColumnResponse(v interface{}) (*pb.ColumnResponse, error) {
+	switch v := v.(type) {
+	case nil:
+		return &pb.ColumnResponse{}, nil
+	case bool:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_BoolVal{BoolVal: v}}, nil
+	case int64:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_Int64Val{Int64Val: v}}, nil
+	case uint64:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_Uint64Val{Uint64Val: v}}, nil
+	case float64:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_Float64Val{Float64Val: v}}, nil
+	case string:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_StringVal{StringVal: v}}, nil
+	case []int64:
+		vals := make([]uint64, len(v))
+		for i := range v {
+			vals[i] = uint64(v[i])
+		}
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_Uint64ArrayVal{Uint64ArrayVal: &pb.Uint64Array{Vals: vals}}}, nil
+	case []string:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_StringArrayVal{StringArrayVal: &pb.StringArray{Vals: v}}}, nil
+	case pql.Decimal:
+		value := v.Value()
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_DecimalVal{DecimalVal: &pb.Decimal{Value: value.Int64(), Scale: v.Scale}}}, nil
+	case time.Time:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_TimestampVal{TimestampVal: v.UTC().Format(time.RFC3339Nano)}}, nil
+	default:
+		return nil, errors.Errorf("unsupported field value: %v (type: %T)", v, v)
+	}
+}
@@ -283,3 +283,79 @@ func TestViewsRemovalStandard(t *testing.T) {
 		})
 	}
 }
+
+func TestRetentionRemoval(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	now := time.Now().UTC()
+	old := now.Add(-48 * time.Hour).Format(time.RFC3339)
+	recent := now.Add(-time.Hour).Format(time.RFC3339)
+
+	t.Run("Unkeyed", func(t *testing.T) {
+		index := c.Idx("u")
+		c.CreateField(t, index, pilosa.IndexOptions{TrackExistence: true, RetentionField: "ts", RetentionPeriod: 24 * time.Hour}, "ts", pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitSeconds))
+		c.CreateField(t, index, pilosa.IndexOptions{TrackExistence: true, RetentionField: "ts", RetentionPeriod: 24 * time.Hour}, "f")
+		c.Query(t, index, fmt.Sprintf(`
+			Set(1, ts='%[1]s')
+			Set(2, ts='%[2]s')
+			Set(%[3]d, ts='%[1]s')
+			Set(%[4]d, ts='%[2]s')
+			Set(%[4]d, f=1)
+			Set(5, f=1)
+		`, old, recent, pilosa.ShardWidth+1, 2*pilosa.ShardWidth+2))
+
+		for i := range c.Nodes {
+			c.GetNode(i).Server.RetentionRemoval(context.Background())
+		}
+
+		// records without a timestamp are kept
+		res := c.Query(t, index, `All()`)
+		if cols, exp := res.Results[0].(*pilosa.Row).Columns(), []uint64{2, 5, 2*pilosa.ShardWidth + 2}; !reflect.DeepEqual(cols, exp) {
+			t.Fatalf("expected %v, got %v", exp, cols)
+		}
+	})
+
+	t.Run("Keyed", func(t *testing.T) {
+		index := c.Idx("k")
+		c.CreateField(t, index, pilosa.IndexOptions{Keys: true, TrackExistence: true, RetentionField: "ts", RetentionPeriod: 24 * time.Hour}, "ts", pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitSeconds))
+		c.Query(t, index, fmt.Sprintf(`
+			Set("a", ts='%[1]s')
+			Set("b", ts='%[2]s')
+			Set("c", ts='%[1]s')
+		`, old, recent))
+
+		for i := range c.Nodes {
+			c.GetNode(i).Server.RetentionRemoval(context.Background())
+		}
+
+		res := c.Query(t, index, `All()`)
+		if keys, exp := res.Results[0].(*pilosa.Row).Keys, []string{"b"}; !reflect.DeepEqual(keys, exp) {
+			t.Fatalf("expected %v, got %v", exp, keys)
+		}
+
+		// the keys of the expired records are removed from the translate store
+		ids, err := c.GetNode(0).API.FindIndexKeys(context.Background(), index, "a", "b", "c")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := ids["b"]; len(ids) != 1 || !ok {
+			t.Fatalf("expected only key b to remain, got %v", ids)
+		}
+	})
+
+	t.Run("NotTimestamp", func(t *testing.T) {
+		index := c.Idx("n")
+		c.CreateField(t, index, pilosa.IndexOptions{TrackExistence: true, RetentionField: "ts", RetentionPeriod: 24 * time.Hour}, "ts", pilosa.OptFieldTypeInt(0, 100))
+		c.Query(t, index, `Set(1, ts=1)`)
+
+		// a retention field which isn't a timestamp deletes nothing
+		for i := range c.Nodes {
+			c.GetNode(i).Server.RetentionRemoval(context.Background())
+		}
+		res := c.Query(t, index, `Count(All())`)
+		if n := res.Results[0].(uint64); n != 1 {
+			t.Fatalf("expected 1 record, got %d", n)
+		}
+	})
+}
@@ -0,0 +1,385 @@
+// Copyright 2023 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/disco"
+	"github.com/featurebasedb/featurebase/v3/roaring"
+	"github.com/pkg/errors"
+)
+
+const (
+	// shardTransactionTimeout is how long the exclusive transaction a group
+	// of shard imports is applied under lasts if it isn't finished, such as
+	// when the node applying them dies part of the way through.
+	shardTransactionTimeout = 5 * time.Minute
+
+	// shardTransactionPoll is how often we check whether an exclusive
+	// transaction we're waiting for has become active.
+	shardTransactionPoll = 10 * time.Millisecond
+)
+
+// HoldIndexesRequest is the body of a request to hold off queries against
+// indexes on a node, for the node applying a group of shard imports.
+type HoldIndexesRequest struct {
+	Indexes []string      `json:"indexes"`
+	Timeout time.Duration `json:"timeout"`
+}
+
+// ApplyShardTransaction implements ShardTransactionApplier. The imports are
+// applied under an exclusive transaction, which keeps any other group of
+// shard imports, and any ingest which uses transactions, from running
+// anywhere in the cluster at the same time, and with queries against their
+// tables held off on every node, so that no query sees some of the imports
+// without the others.
+//
+// Before a shard is imported into, the fragments the import touches are
+// read, so that if an import fails, every shard imported into so far
+// (including the one which failed, which may have been imported into on
+// some of its replicas) can be put back as it was.
+func (i *onPremImporter) ApplyShardTransaction(ctx context.Context, imports []ShardImport) (err error) {
+	if len(imports) == 0 {
+		return nil
+	}
+
+	trns, err := i.startExclusiveTransaction(ctx)
+	if err != nil {
+		return errors.Wrap(err, "starting exclusive transaction")
+	}
+	defer func() {
+		if ferr := i.finishExclusiveTransaction(detachContext(ctx), trns.ID); ferr != nil {
+			i.api.logger().Errorf("finishing exclusive transaction %s: %v", trns.ID, ferr)
+		}
+	}()
+
+	var indexes []string
+	seen := make(map[string]struct{})
+	for _, imp := range imports {
+		if _, ok := seen[string(imp.TableID)]; !ok {
+			seen[string(imp.TableID)] = struct{}{}
+			indexes = append(indexes, string(imp.TableID))
+		}
+	}
+	release, err := i.holdIndexes(ctx, trns.ID, indexes)
+	if err != nil {
+		return errors.Wrap(err, "locking tables")
+	}
+	defer release()
+
+	// Nothing is applied if any of it writes to a field which is being
+	// altered.
+	for _, imp := range imports {
+		idx := i.api.holder.Index(string(imp.TableID))
+		if idx == nil {
+			return newNotFoundError(ErrIndexNotFound, string(imp.TableID))
+		}
+		if err := idx.checkShardImportWritable(imp.Request); err != nil {
+			return err
+		}
+	}
+
+	undos := make([]ShardImport, 0, len(imports))
+	for _, imp := range imports {
+		undo, err := i.undoShardImport(ctx, imp)
+		if err != nil {
+			return i.undo(ctx, undos, errors.Wrapf(err, "reading table '%s' shard %d", imp.TableID, imp.Shard))
+		}
+		undos = append(undos, undo)
+		if err := i.ImportRoaringShard(ctx, imp.TableID, imp.Shard, imp.Request); err != nil {
+			return i.undo(ctx, undos, errors.Wrapf(err, "applying to table '%s' shard %d", imp.TableID, imp.Shard))
+		}
+	}
+	return nil
+}
+
+// holdIndexes holds off queries against the given indexes on every node,
+// and returns the function which releases them. Since only the node
+// coordinating a query waits for its indexes, a query which is running
+// when they're held keeps its coordinator from holding them until it has
+// finished, so the imports aren't applied until no query could see part of
+// them.
+func (i *onPremImporter) holdIndexes(ctx context.Context, id string, indexes []string) (func(), error) {
+	var held []*disco.Node
+	release := func() {
+		ctx := detachContext(ctx)
+		for _, node := range held {
+			var err error
+			if node.ID == i.api.NodeID() {
+				err = i.api.ReleaseIndexes(ctx, id)
+			} else {
+				err = i.client.ReleaseIndexes(ctx, &node.URI, id)
+			}
+			if err != nil {
+				i.api.logger().Errorf("releasing tables held on node %s: %v", node.ID, err)
+			}
+		}
+	}
+	for _, node := range i.api.cluster.Nodes() {
+		var err error
+		if node.ID == i.api.NodeID() {
+			err = i.api.HoldIndexes(ctx, id, indexes, shardTransactionTimeout)
+		} else {
+			err = i.client.HoldIndexes(ctx, &node.URI, id, indexes, shardTransactionTimeout)
+		}
+		if err != nil {
+			release()
+			return nil, errors.Wrapf(err, "holding tables on node %s", node.ID)
+		}
+		held = append(held, node)
+	}
+	return release, nil
+}
+
+// undo applies the imports which undo those applied so far, in reverse
+// order, and returns err, noting whether everything was put back.
+func (i *onPremImporter) undo(ctx context.Context, undos []ShardImport, err error) error {
+	ctx = detachContext(ctx)
+	failed := false
+	for n := len(undos) - 1; n >= 0; n-- {
+		undo := undos[n]
+		if uerr := i.ImportRoaringShard(ctx, undo.TableID, undo.Shard, undo.Request); uerr != nil {
+			i.api.logger().Errorf("undoing import into table '%s' shard %d: %v", undo.TableID, undo.Shard, uerr)
+			failed = true
+		}
+	}
+	if failed {
+		return errors.Wrap(err, "transaction could not be fully rolled back (see the log)")
+	}
+	return errors.Wrap(err, "transaction rolled back")
+}
+
+// fieldView names a view of a field.
+type fieldView struct {
+	field, view string
+}
+
+// undoShardImport returns the import which puts back the fragments the given
+// import touches as they are now. For each view, it clears every record the
+// import touches, then sets the bits the fragment has now.
+func (i *onPremImporter) undoShardImport(ctx context.Context, imp ShardImport) (ShardImport, error) {
+	undo := ShardImport{
+		TableID: imp.TableID,
+		Shard:   imp.Shard,
+		Request: &ImportRoaringShardRequest{Remote: true},
+	}
+	index := i.api.holder.Index(string(imp.TableID))
+	if index == nil {
+		return undo, newNotFoundError(ErrIndexNotFound, string(imp.TableID))
+	}
+
+	var views []fieldView
+	records := make(map[fieldView]*roaring.Bitmap)
+	touch := func(fv fieldView, data []byte) error {
+		bm, ok := records[fv]
+		if !ok {
+			bm = roaring.NewBitmap()
+			records[fv] = bm
+			views = append(views, fv)
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		positions := roaring.NewBitmap()
+		if err := positions.UnmarshalBinary(data); err != nil {
+			return errors.Wrap(err, "decoding import")
+		}
+		for _, pos := range positions.Slice() {
+			bm.DirectAdd(pos % ShardWidth)
+		}
+		return nil
+	}
+
+	for _, update := range imp.Request.Views {
+		if update.Field == "" && update.ClearRecords {
+			// the records are cleared from every view of every field
+			for _, field := range index.Fields() {
+				for _, view := range field.views() {
+					if err := touch(fieldView{field: field.Name(), view: view.name}, update.Clear); err != nil {
+						return undo, err
+					}
+				}
+			}
+			continue
+		}
+		field := index.Field(update.Field)
+		if field == nil {
+			return undo, errors.Errorf("no field named '%s' found.", update.Field)
+		}
+		view, err := field.cleanupViewName(update.View)
+		if err != nil {
+			return undo, err
+		}
+		fv := fieldView{field: update.Field, view: view}
+		if err := touch(fv, update.Clear); err != nil {
+			return undo, err
+		}
+		if err := touch(fv, update.Set); err != nil {
+			return undo, err
+		}
+	}
+
+	for _, fv := range views {
+		data, err := i.fragmentData(ctx, index.Name(), fv.field, fv.view, imp.Shard)
+		if err != nil {
+			return undo, errors.Wrapf(err, "reading %s/%s", fv.field, fv.view)
+		}
+		buf := &bytes.Buffer{}
+		if _, err := records[fv].WriteTo(buf); err != nil {
+			return undo, errors.Wrap(err, "serializing records")
+		}
+		undo.Request.Views = append(undo.Request.Views, RoaringUpdate{
+			Field:        fv.field,
+			View:         fv.view,
+			Clear:        buf.Bytes(),
+			Set:          data,
+			ClearRecords: true,
+		})
+	}
+	return undo, nil
+}
+
+// fragmentData returns the roaring encoded bits of a fragment, read from
+// this node if it owns the shard, or else from the first node which does.
+// It returns nil if there's no such fragment.
+func (i *onPremImporter) fragmentData(ctx context.Context, index, field, view string, shard uint64) ([]byte, error) {
+	nodes, err := i.api.ShardNodes(ctx, index, shard)
+	if err != nil {
+		return nil, err
+	} else if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	node := nodes[0]
+	for _, n := range nodes {
+		if n.ID == i.api.NodeID() {
+			node = n
+		}
+	}
+
+	var rd io.Reader
+	if node.ID == i.api.NodeID() {
+		frag := i.api.holder.fragment(index, field, view, shard)
+		if frag == nil {
+			return nil, nil
+		}
+		buf := &bytes.Buffer{}
+		if _, err := frag.WriteTo(buf); err != nil {
+			return nil, err
+		}
+		rd = buf
+	} else {
+		rc, err := i.client.RetrieveShardFromURI(ctx, index, field, view, shard, node.URI)
+		if errors.Is(err, ErrFragmentNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		rd = rc
+	}
+
+	// the fragment is sent as a tar archive of its data and its cache
+	tr := tar.NewReader(rd)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, "reading fragment archive")
+		}
+		if hdr.Name == "data" {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// transactionClient returns a client for the primary node, which manages
+// the cluster's transactions, or nil if this node is the primary.
+func (i *onPremImporter) transactionClient() *InternalClient {
+	primary := i.api.PrimaryNode()
+	if primary == nil || primary.ID == i.api.NodeID() {
+		return nil
+	}
+	c := *i.client
+	c.defaultURI = &primary.URI
+	return &c
+}
+
+// startExclusiveTransaction starts an exclusive transaction and waits for
+// it to become active, once the transactions before it have finished.
+func (i *onPremImporter) startExclusiveTransaction(ctx context.Context) (*Transaction, error) {
+	client := i.transactionClient()
+	ticker := time.NewTicker(shardTransactionPoll)
+	defer ticker.Stop()
+
+	var trns *Transaction
+	var err error
+	for {
+		if trns == nil {
+			// another exclusive transaction may be pending or active, in
+			// which case we wait our turn to start ours
+			if client == nil {
+				trns, err = i.api.StartTransaction(ctx, "", shardTransactionTimeout, true, false)
+			} else {
+				trns, err = client.StartTransaction(ctx, "", shardTransactionTimeout, true)
+			}
+			if errors.Is(err, ErrTransactionExclusive) {
+				trns = nil
+			} else if err != nil {
+				return nil, err
+			}
+		} else {
+			if client == nil {
+				trns, err = i.api.GetTransaction(ctx, trns.ID, false)
+			} else {
+				trns, err = client.GetTransaction(ctx, trns.ID)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if trns != nil && trns.Active {
+			return trns, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if trns != nil {
+				_ = i.finishExclusiveTransaction(detachContext(ctx), trns.ID)
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (i *onPremImporter) finishExclusiveTransaction(ctx context.Context, id string) error {
+	var err error
+	if client := i.transactionClient(); client == nil {
+		_, err = i.api.FinishTransaction(ctx, id, false)
+	} else {
+		_, err = client.FinishTransaction(ctx, id)
+	}
+	return err
+}
+
+// detachedContext has the values of its parent, such as the auth token of
+// the request, but is never done. It's used to clean up after work which
+// has been cancelled.
+type detachedContext struct {
+	context.Context
+}
+
+func detachContext(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
@@ -0,0 +1,141 @@
+// Copyright 2023 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/roaring"
+	"github.com/featurebasedb/featurebase/v3/test"
+)
+
+// Ensure a group of shard imports is applied all or nothing.
+func TestOnPremImporter_ApplyShardTransaction(t *testing.T) {
+	c := test.MustRunUnsharedCluster(t, 3)
+	defer c.Close()
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "f", pilosa.OptFieldTypeDefault())
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "n", pilosa.OptFieldTypeInt(0, 100))
+
+	ctx := context.Background()
+	node := c.GetNode(0)
+	node.QueryAPI(t, &pilosa.QueryRequest{Index: c.Idx(), Query: "Set(1, f=1) Set(2, n=5) Set(3, n=7)"})
+
+	bits := func(positions ...uint64) []byte {
+		buf := &bytes.Buffer{}
+		if _, err := roaring.NewBitmap(positions...).WriteTo(buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	mustColumns := func(t *testing.T, query string, exp []uint64) {
+		t.Helper()
+		res := node.QueryAPI(t, &pilosa.QueryRequest{Index: c.Idx(), Query: query})
+		if cols := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(cols, exp) {
+			t.Fatalf("%s: expected %v, got %v", query, exp, cols)
+		}
+	}
+
+	// set f=2 for record 1, delete record 2, and add record ShardWidth+1
+	imports := []pilosa.ShardImport{
+		{
+			TableID: dax.TableID(c.Idx()),
+			Shard:   0,
+			Request: &pilosa.ImportRoaringShardRequest{
+				Remote: true,
+				Views: []pilosa.RoaringUpdate{
+					{Field: "f", View: "standard", Set: bits(2*ShardWidth + 1)},
+					{Clear: bits(2), ClearRecords: true},
+				},
+			},
+		},
+		{
+			TableID: dax.TableID(c.Idx()),
+			Shard:   1,
+			Request: &pilosa.ImportRoaringShardRequest{
+				Remote: true,
+				Views: []pilosa.RoaringUpdate{
+					{Field: "f", View: "standard", Set: bits(1*ShardWidth + 1)},
+					{Field: "missing", View: "standard", Set: bits(1)},
+				},
+			},
+		},
+	}
+
+	importer := pilosa.NewOnPremImporter(node.API)
+	err := importer.ApplyShardTransaction(ctx, imports)
+	if err == nil || !strings.Contains(err.Error(), "transaction rolled back") {
+		t.Fatalf("expected the transaction to be rolled back, got %v", err)
+	}
+	mustColumns(t, "Row(f=1)", []uint64{1})
+	mustColumns(t, "Row(f=2)", []uint64{})
+	mustColumns(t, "Row(n=5)", []uint64{2})
+	mustColumns(t, "Row(n>0)", []uint64{2, 3})
+
+	imports[1].Request.Views = imports[1].Request.Views[:1]
+	if err := importer.ApplyShardTransaction(ctx, imports); err != nil {
+		t.Fatal(err)
+	}
+	mustColumns(t, "Row(f=1)", []uint64{1, ShardWidth + 1})
+	mustColumns(t, "Row(f=2)", []uint64{1})
+	mustColumns(t, "Row(n>0)", []uint64{3})
+
+	// the exclusive transaction was finished
+	if trns, err := node.API.Transactions(ctx); err != nil {
+		t.Fatal(err)
+	} else if len(trns) != 0 {
+		t.Fatalf("expected no transactions, got %v", trns)
+	}
+}
+
+// Ensure indexes held for another node keep queries coordinated by the node
+// from running, but not the parts of queries other nodes send it.
+func TestAPI_HoldIndexes(t *testing.T) {
+	c := test.MustRunCluster(t, 2)
+	defer c.Close()
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{}, "f", pilosa.OptFieldTypeDefault())
+	c.ImportBits(t, c.Idx(), "f", [][2]uint64{{1, 1}, {1, ShardWidth + 1}, {1, 2*ShardWidth + 1}})
+
+	node0, node1 := c.GetNode(0), c.GetNode(1)
+	client := node0.Client()
+	uri := node1.API.Node().URI
+
+	query := func(node *test.Command) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err := node.API.Query(ctx, &pilosa.QueryRequest{Index: c.Idx(), Query: "Count(Row(f=1))"})
+		return err
+	}
+
+	ctx := context.Background()
+	if err := client.HoldIndexes(ctx, &uri, "t1", []string{c.Idx()}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := query(node1); err == nil {
+		t.Fatal("expected a query coordinated by a node holding its index to wait")
+	}
+	if err := query(node0); err != nil {
+		t.Fatalf("querying through another node: %v", err)
+	}
+	if err := client.ReleaseIndexes(ctx, &uri, "t1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := query(node1); err != nil {
+		t.Fatalf("querying after release: %v", err)
+	}
+
+	// a hold which isn't released expires
+	if err := client.HoldIndexes(ctx, &uri, "t2", []string{c.Idx()}, 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := query(node1); err != nil {
+		t.Fatalf("querying after the hold expired: %v", err)
+	}
+}
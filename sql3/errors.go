@@ -18,6 +18,7 @@ const (
 	ErrUnknownType           errors.Code = "ErrUnknownType"
 	ErrUnknownIdentifier     errors.Code = "ErrUnknownIdentifier"
 	ErrTopLimitCannotCoexist errors.Code = "ErrTopLimitCannotCoexist"
+	ErrInvalidOffsetValue    errors.Code = "ErrInvalidOffsetValue"
 
 	// type related errors
 	ErrTypeIncompatibleWithBitwiseOperator               errors.Code = "ErrTypeIncompatibleWithBitwiseOperator"
@@ -41,11 +42,20 @@ const (
 	ErrStringExpressionExpected                          errors.Code = "ErrStringExpressionExpected"
 	ErrSetExpressionExpected                             errors.Code = "ErrSetExpressionExpected"
 	ErrTimeQuantumExpressionExpected                     errors.Code = "ErrTimeQuantumExpressionExpected"
+	ErrJSONExpressionExpected                            errors.Code = "ErrJSONExpressionExpected"
+	ErrGeoPointExpressionExpected                        errors.Code = "ErrGeoPointExpressionExpected"
+	ErrNumericExpressionExpected                         errors.Code = "ErrNumericExpressionExpected"
+	ErrBitVectorExpressionExpected                       errors.Code = "ErrBitVectorExpressionExpected"
 	ErrSingleRowExpected                                 errors.Code = "ErrSingleRowExpected"
 
 	// decimal
 	ErrDecimalScaleExpected errors.Code = "ErrDecimalScaleExpected"
 
+	// bitvector
+	ErrBitVectorWidthExpected errors.Code = "ErrBitVectorWidthExpected"
+	ErrInvalidBitVectorWidth  errors.Code = "ErrInvalidBitVectorWidth"
+	ErrInvalidBitVector       errors.Code = "ErrInvalidBitVector"
+
 	ErrInvalidCast         errors.Code = "ErrInvalidCast"
 	ErrInvalidTypeCoercion errors.Code = "ErrInvalidTypeCoercion"
 
@@ -74,6 +84,11 @@ const (
 	ErrInsertExprTargetCountMismatch   errors.Code = "ErrInsertExprTargetCountMismatch"
 	ErrInsertMustHaveIDColumn          errors.Code = "ErrInsertMustHaveIDColumn"
 	ErrInsertMustAtLeastOneNonIDColumn errors.Code = "ErrInsertMustAtLeastOneNonIDColumn"
+	ErrInsertConflictTarget            errors.Code = "ErrInsertConflictTarget"
+	ErrInsertConflictUpdateID          errors.Code = "ErrInsertConflictUpdateID"
+	ErrInsertConflictColumnReference   errors.Code = "ErrInsertConflictColumnReference"
+	ErrInsertPrimaryKeyID              errors.Code = "ErrInsertPrimaryKeyID"
+	ErrInsertMissingPrimaryKeyColumn   errors.Code = "ErrInsertMissingPrimaryKeyColumn"
 
 	ErrDatabaseNotFound      errors.Code = "ErrDatabaseNotFound"
 	ErrDatabaseExists        errors.Code = "ErrDatabaseExists"
@@ -84,11 +99,16 @@ const (
 	ErrTableIDColumnType         errors.Code = "ErrTableIDColumnType"
 	ErrTableIDColumnConstraints  errors.Code = "ErrTableIDColumnConstraints"
 	ErrTableIDColumnAlter        errors.Code = "ErrTableIDColumnAlter"
+	ErrTableIDColumnChange       errors.Code = "ErrTableIDColumnChange"
 	ErrTableNotFound             errors.Code = "ErrTableNotFound"
 	ErrTableExists               errors.Code = "ErrTableExists"
 	ErrColumnNotFound            errors.Code = "ErrColumnNotFound"
 	ErrTableColumnNotFound       errors.Code = "ErrTableColumnNotFound"
 	ErrInvalidKeyPartitionsValue errors.Code = "ErrInvalidKeyPartitionsValue"
+	ErrInvalidRetentionColumn    errors.Code = "ErrInvalidRetentionColumn"
+	ErrPrimaryKeyIDColumnType    errors.Code = "ErrPrimaryKeyIDColumnType"
+	ErrInvalidPrimaryKeyColumn   errors.Code = "ErrInvalidPrimaryKeyColumn"
+	ErrPrimaryKeyColumnChange    errors.Code = "ErrPrimaryKeyColumnChange"
 
 	ErrTableOrViewNotFound errors.Code = "ErrTableOrViewNotFound"
 
@@ -98,8 +118,15 @@ const (
 	ErrModelExists   errors.Code = "ErrModelExists"
 	ErrModelNotFound errors.Code = "ErrModelNotFound"
 
+	ErrCursorExists   errors.Code = "ErrCursorExists"
+	ErrCursorNotFound errors.Code = "ErrCursorNotFound"
+
 	ErrBadColumnConstraint         errors.Code = "ErrBadColumnConstraint"
 	ErrConflictingColumnConstraint errors.Code = "ErrConflictingColumnConstraint"
+	ErrInvalidColumnConstraint     errors.Code = "ErrInvalidColumnConstraint"
+	ErrDefaultColumnReference      errors.Code = "ErrDefaultColumnReference"
+	ErrCheckColumnReference        errors.Code = "ErrCheckColumnReference"
+	ErrCheckNotBoolean             errors.Code = "ErrCheckNotBoolean"
 
 	// expected errors
 	ErrExpectedColumnReference         errors.Code = "ErrExpectedColumnReference"
@@ -117,6 +144,7 @@ const (
 
 	ErrInsertValueOutOfRange            errors.Code = "ErrInsertValueOutOfRange"
 	ErrUnexpectedTimeQuantumTupleLength errors.Code = "ErrUnexpectedTimeQuantumTupleLength"
+	ErrConstraintViolation              errors.Code = "ErrConstraintViolation"
 
 	// bulk insert errors
 
@@ -130,6 +158,12 @@ const (
 	ErrTypeConversionOnMap     errors.Code = "ErrTypeConversionOnMap"
 	ErrParsingJSON             errors.Code = "ErrParsingJSON"
 	ErrEvaluatingJSONPathExpr  errors.Code = "ErrEvaluatingJSONPathExpr"
+	ErrInvalidJSONPath         errors.Code = "ErrInvalidJSONPath"
+	ErrJSONDocumentTooLong     errors.Code = "ErrJSONDocumentTooLong"
+
+	// geospatial errors
+	ErrInvalidGeoPoint errors.Code = "ErrInvalidGeoPoint"
+	ErrInvalidGeoBox   errors.Code = "ErrInvalidGeoBox"
 
 	// optimizer errors
 	ErrAggregateNotAllowedInGroupBy errors.Code = "ErrIdPercentileNotAllowedInGroupBy"
@@ -185,6 +219,13 @@ func NewErrErrTopLimitCannotCoexist(line int, col int) error {
 	)
 }
 
+func NewErrInvalidOffsetValue(line int, col int, offset int64) error {
+	return errors.New(
+		ErrInvalidOffsetValue,
+		fmt.Sprintf("[%d:%d] invalid value '%d' for OFFSET (should be a non-negative integer)", line, col, offset),
+	)
+}
+
 func NewErrInternal(msg string) error {
 	preamble := "internal error"
 	_, filename, line, ok := runtime.Caller(1)
@@ -475,6 +516,34 @@ func NewErrStringExpressionExpected(line, col int) error {
 	)
 }
 
+func NewErrJSONExpressionExpected(line, col int) error {
+	return errors.New(
+		ErrJSONExpressionExpected,
+		fmt.Sprintf("[%d:%d] json or string expression expected", line, col),
+	)
+}
+
+func NewErrGeoPointExpressionExpected(line, col int) error {
+	return errors.New(
+		ErrGeoPointExpressionExpected,
+		fmt.Sprintf("[%d:%d] geopoint expression expected", line, col),
+	)
+}
+
+func NewErrNumericExpressionExpected(line, col int) error {
+	return errors.New(
+		ErrNumericExpressionExpected,
+		fmt.Sprintf("[%d:%d] integer, decimal or double expression expected", line, col),
+	)
+}
+
+func NewErrBitVectorExpressionExpected(line, col int) error {
+	return errors.New(
+		ErrBitVectorExpressionExpected,
+		fmt.Sprintf("[%d:%d] bitvector or string expression expected", line, col),
+	)
+}
+
 func NewErrSetExpressionExpected(line, col int) error {
 	return errors.New(
 		ErrSetExpressionExpected,
@@ -507,6 +576,29 @@ func NewErrDecimalScaleExpected(line, col int) error {
 	)
 }
 
+// bitvector related
+
+func NewErrBitVectorWidthExpected(line, col int) error {
+	return errors.New(
+		ErrBitVectorWidthExpected,
+		fmt.Sprintf("[%d:%d] bitvector width expected", line, col),
+	)
+}
+
+func NewErrInvalidBitVectorWidth(line, col int, width string, max int64) error {
+	return errors.New(
+		ErrInvalidBitVectorWidth,
+		fmt.Sprintf("[%d:%d] invalid bitvector width '%s': must be a multiple of 8 between 8 and %d", line, col, width, max),
+	)
+}
+
+func NewErrInvalidBitVector(line, col int, value string, width int64) error {
+	return errors.New(
+		ErrInvalidBitVector,
+		fmt.Sprintf("[%d:%d] invalid bitvector '%s': expected %d hex digits", line, col, value, width/4),
+	)
+}
+
 func NewErrInvalidTimeUnit(line, col int, unit string) error {
 	return errors.New(
 		ErrInvalidTimeUnit,
@@ -556,6 +648,41 @@ func NewErrInsertMustAtLeastOneNonIDColumn(line int, col int) error {
 	)
 }
 
+func NewErrInsertConflictTarget(line int, col int) error {
+	return errors.New(
+		ErrInsertConflictTarget,
+		fmt.Sprintf("[%d:%d] ON CONFLICT target must be the '_id' column", line, col),
+	)
+}
+
+func NewErrInsertConflictUpdateID(line int, col int) error {
+	return errors.New(
+		ErrInsertConflictUpdateID,
+		fmt.Sprintf("[%d:%d] '_id' column cannot be updated by ON CONFLICT DO UPDATE", line, col),
+	)
+}
+
+func NewErrInsertConflictColumnReference(line int, col int, columnName string) error {
+	return errors.New(
+		ErrInsertConflictColumnReference,
+		fmt.Sprintf("[%d:%d] column '%s' can only be referenced as 'excluded.%s' in ON CONFLICT DO UPDATE", line, col, columnName, columnName),
+	)
+}
+
+func NewErrInsertPrimaryKeyID(line int, col int, tableName string) error {
+	return errors.New(
+		ErrInsertPrimaryKeyID,
+		fmt.Sprintf("[%d:%d] _id column of table '%s' is made from its primary key and cannot be inserted", line, col, tableName),
+	)
+}
+
+func NewErrInsertMissingPrimaryKeyColumn(line int, col int, columnName string) error {
+	return errors.New(
+		ErrInsertMissingPrimaryKeyColumn,
+		fmt.Sprintf("[%d:%d] insert must have primary key column '%s'", line, col, columnName),
+	)
+}
+
 func NewErrDatabaseExists(line, col int, databaseName string) error {
 	return errors.New(
 		ErrDatabaseExists,
@@ -591,6 +718,13 @@ func NewErrTableIDColumnAlter(line, col int) error {
 	)
 }
 
+func NewErrTableIDColumnChange(line, col int) error {
+	return errors.New(
+		ErrTableIDColumnChange,
+		fmt.Sprintf("[%d:%d] _id column cannot be renamed or altered", line, col),
+	)
+}
+
 func NewErrDatabaseNotFound(line, col int, databaseName string) error {
 	return errors.New(
 		ErrDatabaseNotFound,
@@ -654,6 +788,34 @@ func NewErrInvalidKeyPartitionsValue(line, col int, keypartitions int64) error {
 	)
 }
 
+func NewErrInvalidRetentionColumn(line, col int, columnName string) error {
+	return errors.New(
+		ErrInvalidRetentionColumn,
+		fmt.Sprintf("[%d:%d] retention column '%s' must be of type timestamp", line, col, columnName),
+	)
+}
+
+func NewErrPrimaryKeyIDColumnType(line, col int) error {
+	return errors.New(
+		ErrPrimaryKeyIDColumnType,
+		fmt.Sprintf("[%d:%d] _id column must be of type STRING when a primary key is specified", line, col),
+	)
+}
+
+func NewErrInvalidPrimaryKeyColumn(line, col int, columnName string) error {
+	return errors.New(
+		ErrInvalidPrimaryKeyColumn,
+		fmt.Sprintf("[%d:%d] primary key column '%s' must be of type ID, INT or STRING", line, col, columnName),
+	)
+}
+
+func NewErrPrimaryKeyColumnChange(line, col int, columnName string) error {
+	return errors.New(
+		ErrPrimaryKeyColumnChange,
+		fmt.Sprintf("[%d:%d] primary key column '%s' cannot be changed", line, col, columnName),
+	)
+}
+
 func NewErrViewNotFound(line, col int, viewName string) error {
 	return errors.New(
 		ErrViewNotFound,
@@ -668,6 +830,20 @@ func NewErrViewExists(line, col int, viewName string) error {
 	)
 }
 
+func NewErrCursorNotFound(line, col int, cursorName string) error {
+	return errors.New(
+		ErrCursorNotFound,
+		fmt.Sprintf("[%d:%d] cursor '%s' not found", line, col, cursorName),
+	)
+}
+
+func NewErrCursorExists(line, col int, cursorName string) error {
+	return errors.New(
+		ErrCursorExists,
+		fmt.Sprintf("[%d:%d] cursor '%s' already exists", line, col, cursorName),
+	)
+}
+
 func NewErrModelNotFound(line, col int, viewName string) error {
 	return errors.New(
 		ErrModelNotFound,
@@ -696,6 +872,34 @@ func NewErrConflictingColumnConstraint(line, col int, token1, token2 parser.Toke
 	)
 }
 
+func NewErrInvalidColumnConstraint(line, col int, reason string) error {
+	return errors.New(
+		ErrInvalidColumnConstraint,
+		fmt.Sprintf("[%d:%d] invalid column constraint: %s", line, col, reason),
+	)
+}
+
+func NewErrDefaultColumnReference(line, col int, columnName string) error {
+	return errors.New(
+		ErrDefaultColumnReference,
+		fmt.Sprintf("[%d:%d] column reference '%s' not allowed in a DEFAULT constraint", line, col, columnName),
+	)
+}
+
+func NewErrCheckColumnReference(line, col int, columnName, constrainedColumn string) error {
+	return errors.New(
+		ErrCheckColumnReference,
+		fmt.Sprintf("[%d:%d] column '%s' can't be referenced by a constraint on column '%s'", line, col, columnName, constrainedColumn),
+	)
+}
+
+func NewErrCheckNotBoolean(line, col int, typeName string) error {
+	return errors.New(
+		ErrCheckNotBoolean,
+		fmt.Sprintf("[%d:%d] CHECK constraint must be a boolean expression, not of type '%s'", line, col, typeName),
+	)
+}
+
 // expected
 
 func NewErrExpectedColumnReference(line, col int) error {
@@ -772,6 +976,13 @@ func NewErrUnexpectedTimeQuantumTupleLength(line, col int, columnName string, ro
 	)
 }
 
+func NewErrConstraintViolation(line, col int, rowNumber int, violation string) error {
+	return errors.New(
+		ErrConstraintViolation,
+		fmt.Sprintf("[%d:%d] inserting row %d: %s", line, col, rowNumber, violation),
+	)
+}
+
 // bulk insert
 
 func NewErrReadingDatasource(line, col int, dataSource string, errorText string) error {
@@ -844,6 +1055,34 @@ func NewErrEvaluatingJSONPathExpr(line, col int, exprText string, jsonString str
 	)
 }
 
+func NewErrInvalidJSONPath(line, col int, path string) error {
+	return errors.New(
+		ErrInvalidJSONPath,
+		fmt.Sprintf("[%d:%d] invalid JSON path '%s'", line, col, path),
+	)
+}
+
+func NewErrJSONDocumentTooLong(line, col int, length, max int) error {
+	return errors.New(
+		ErrJSONDocumentTooLong,
+		fmt.Sprintf("[%d:%d] JSON document of %d bytes exceeds the maximum of %d", line, col, length, max),
+	)
+}
+
+func NewErrInvalidGeoPoint(line, col int, lat, lon float64) error {
+	return errors.New(
+		ErrInvalidGeoPoint,
+		fmt.Sprintf("[%d:%d] invalid geopoint (%v, %v): latitude must be within [-90, 90] and longitude within [-180, 180]", line, col, lat, lon),
+	)
+}
+
+func NewErrInvalidGeoBox(line, col int, minLat, maxLat float64) error {
+	return errors.New(
+		ErrInvalidGeoBox,
+		fmt.Sprintf("[%d:%d] invalid box: minimum latitude %v is greater than maximum latitude %v", line, col, minLat, maxLat),
+	)
+}
+
 // optimizer
 
 func NewErrAggregateNotAllowedInGroupBy(line, col int, aggName string) error {
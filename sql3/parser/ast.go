@@ -34,6 +34,7 @@ func (*CaseExpr) node()                 {}
 func (*CastExpr) node()                 {}
 func (*CheckConstraint) node()          {}
 func (*ColumnDefinition) node()         {}
+func (*CloseCursorStatement) node()     {}
 func (*CopyStatement) node()            {}
 func (*CommitStatement) node()          {}
 func (*CreateDatabaseStatement) node()  {}
@@ -43,6 +44,7 @@ func (*CreateFunctionStatement) node()  {}
 func (*CreateModelStatement) node()     {}
 func (*CreateViewStatement) node()      {}
 func (*DateLit) node()                  {}
+func (*DeclareCursorStatement) node()   {}
 func (*DefaultConstraint) node()        {}
 func (*DeleteStatement) node()          {}
 func (*DropDatabaseStatement) node()    {}
@@ -52,6 +54,7 @@ func (*DropFunctionStatement) node()    {}
 func (*DropViewStatement) node()        {}
 func (*DropModelStatement) node()       {}
 func (*Exists) node()                   {}
+func (*FetchStatement) node()           {}
 func (*ExplainStatement) node()         {}
 func (*ExprList) node()                 {}
 func (*FilterClause) node()             {}
@@ -63,6 +66,7 @@ func (*Ident) node()                    {}
 func (*Variable) node()                 {}
 func (*SysVariable) node()              {}
 func (*IndexedColumn) node()            {}
+func (*IndexPathsConstraint) node()     {}
 func (*InsertStatement) node()          {}
 func (*JoinClause) node()               {}
 func (*JoinOperator) node()             {}
@@ -86,6 +90,7 @@ func (*Range) node()                    {}
 func (*ReturnStatement) node()          {}
 func (*ReleaseStatement) node()         {}
 func (*ResultColumn) node()             {}
+func (*RefreshViewStatement) node()     {}
 func (*RollbackStatement) node()        {}
 func (*SavepointStatement) node()       {}
 func (*SelectStatement) node()          {}
@@ -94,6 +99,7 @@ func (*TableQueryOption) node()         {}
 func (*TableValuedFunction) node()      {}
 func (*TimeUnitConstraint) node()       {}
 func (*TimeQuantumConstraint) node()    {}
+func (*TransactionStatement) node()     {}
 func (*TupleLiteralExpr) node()         {}
 func (*Type) node()                     {}
 func (*UnaryExpr) node()                {}
@@ -106,6 +112,7 @@ func (*Window) node()                   {}
 func (*WindowDefinition) node()         {}
 func (*WithClause) node()               {}
 func (*CommentOption) node()            {}
+func (*RetentionOption) node()          {}
 
 type Statement interface {
 	Node
@@ -117,6 +124,7 @@ func (*AlterTableStatement) stmt()      {}
 func (*AlterViewStatement) stmt()       {}
 func (*AnalyzeStatement) stmt()         {}
 func (*BeginStatement) stmt()           {}
+func (*CloseCursorStatement) stmt()     {}
 func (*CopyStatement) stmt()            {}
 func (*BulkInsertStatement) stmt()      {}
 func (*ShowDatabasesStatement) stmt()   {}
@@ -130,6 +138,7 @@ func (*CreateTableStatement) stmt()     {}
 func (*CreateFunctionStatement) stmt()  {}
 func (*CreateModelStatement) stmt()     {}
 func (*CreateViewStatement) stmt()      {}
+func (*DeclareCursorStatement) stmt()   {}
 func (*DeleteStatement) stmt()          {}
 func (*DropDatabaseStatement) stmt()    {}
 func (*DropIndexStatement) stmt()       {}
@@ -139,12 +148,15 @@ func (*DropViewStatement) stmt()        {}
 func (*DropModelStatement) stmt()       {}
 func (*PredictStatement) stmt()         {}
 func (*ExplainStatement) stmt()         {}
+func (*FetchStatement) stmt()           {}
 func (*InsertStatement) stmt()          {}
 func (*ReleaseStatement) stmt()         {}
 func (*ReturnStatement) stmt()          {}
+func (*RefreshViewStatement) stmt()     {}
 func (*RollbackStatement) stmt()        {}
 func (*SavepointStatement) stmt()       {}
 func (*SelectStatement) stmt()          {}
+func (*TransactionStatement) stmt()     {}
 func (*UpdateStatement) stmt()          {}
 
 // CloneStatement returns a deep copy stmt.
@@ -164,6 +176,8 @@ func CloneStatement(stmt Statement) Statement {
 		return stmt.Clone()
 	case *BeginStatement:
 		return stmt.Clone()
+	case *CloseCursorStatement:
+		return stmt.Clone()
 	case *CommitStatement:
 		return stmt.Clone()
 	case *CreateDatabaseStatement:
@@ -176,6 +190,8 @@ func CloneStatement(stmt Statement) Statement {
 		return stmt.Clone()
 	case *CreateViewStatement:
 		return stmt.Clone()
+	case *DeclareCursorStatement:
+		return stmt.Clone()
 	case *DeleteStatement:
 		return stmt.Clone()
 	case *DropDatabaseStatement:
@@ -192,18 +208,24 @@ func CloneStatement(stmt Statement) Statement {
 		return stmt.Clone()
 	case *ExplainStatement:
 		return stmt.Clone()
+	case *FetchStatement:
+		return stmt.Clone()
 	case *InsertStatement:
 		return stmt.Clone()
 	case *BulkInsertStatement:
 		return stmt.Clone()
 	case *ReleaseStatement:
 		return stmt.Clone()
+	case *RefreshViewStatement:
+		return stmt.Clone()
 	case *RollbackStatement:
 		return stmt.Clone()
 	case *SavepointStatement:
 		return stmt.Clone()
 	case *SelectStatement:
 		return stmt.Clone()
+	case *TransactionStatement:
+		return stmt.Clone()
 	case *UpdateStatement:
 		return stmt.Clone()
 	case *ShowTablesStatement:
@@ -706,6 +728,46 @@ func (s *RollbackStatement) String() string {
 	return buf.String()
 }
 
+// TransactionStatement is a block of statements, bracketed by BEGIN and
+// COMMIT, which are applied together. If the block ends with ROLLBACK
+// rather than COMMIT, none of the statements are applied.
+type TransactionStatement struct {
+	Begin    *BeginStatement    // BEGIN statement
+	Body     []Statement        // statements within the transaction
+	Commit   *CommitStatement   // COMMIT statement (or nil if rolled back)
+	Rollback *RollbackStatement // ROLLBACK statement (or nil if committed)
+}
+
+// Clone returns a deep copy of s.
+func (s *TransactionStatement) Clone() *TransactionStatement {
+	if s == nil {
+		return s
+	}
+	other := *s
+	other.Begin = s.Begin.Clone()
+	other.Body = cloneStatements(s.Body)
+	other.Commit = s.Commit.Clone()
+	other.Rollback = s.Rollback.Clone()
+	return &other
+}
+
+// String returns the string representation of the statement.
+func (s *TransactionStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(s.Begin.String())
+	for _, stmt := range s.Body {
+		buf.WriteString("; ")
+		buf.WriteString(stmt.String())
+	}
+	buf.WriteString("; ")
+	if s.Rollback != nil {
+		buf.WriteString(s.Rollback.String())
+	} else {
+		buf.WriteString(s.Commit.String())
+	}
+	return buf.String()
+}
+
 type SavepointStatement struct {
 	Savepoint Pos    // position of SAVEPOINT keyword
 	Name      *Ident // name of savepoint
@@ -955,6 +1017,8 @@ func CloneTableOption(opt TableOption) TableOption {
 		return cons.Clone()
 	case *CommentOption:
 		return cons.Clone()
+	case *RetentionOption:
+		return cons.Clone()
 	default:
 		panic(fmt.Sprintf("invalid table option type: %T", cons))
 	}
@@ -962,6 +1026,7 @@ func CloneTableOption(opt TableOption) TableOption {
 
 func (*KeyPartitionsOption) option() {}
 func (*CommentOption) option()       {}
+func (*RetentionOption) option()     {}
 
 type KeyPartitionsOption struct {
 	KeyPartitions Pos  // position of KEYPARTITIONS keyword
@@ -999,6 +1064,31 @@ func (o *CommentOption) Clone() *CommentOption {
 	return &other
 }
 
+// RetentionOption expires the records of a table whose value in a timestamp
+// column is older than a duration.
+type RetentionOption struct {
+	Retention Pos    // position of RETENTION keyword
+	Column    *Ident // timestamp column
+	Ttl       Pos    // position of TTL keyword
+	Expr      Expr   // expression
+}
+
+func (o *RetentionOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("RETENTION ")
+	buf.WriteString(o.Column.String())
+	buf.WriteString(" TTL ")
+	buf.WriteString(o.Expr.String())
+	return buf.String()
+}
+
+func (o *RetentionOption) Clone() *RetentionOption {
+	other := *o
+	other.Column = o.Column.Clone()
+	other.Expr = CloneExpr(o.Expr)
+	return &other
+}
+
 type Constraint interface {
 	Node
 	constraint()
@@ -1015,6 +1105,7 @@ func (*MaxConstraint) constraint()         {}
 func (*CacheTypeConstraint) constraint()   {}
 func (*TimeUnitConstraint) constraint()    {}
 func (*TimeQuantumConstraint) constraint() {}
+func (*IndexPathsConstraint) constraint()  {}
 
 // CloneConstraint returns a deep copy cons.
 func CloneConstraint(cons Constraint) Constraint {
@@ -1045,6 +1136,8 @@ func CloneConstraint(cons Constraint) Constraint {
 		return cons.Clone()
 	case *TimeQuantumConstraint:
 		return cons.Clone()
+	case *IndexPathsConstraint:
+		return cons.Clone()
 	default:
 		panic(fmt.Sprintf("invalid constraint type: %T", cons))
 	}
@@ -1314,6 +1407,41 @@ func (c *TimeQuantumConstraint) String() string {
 	return buf.String()
 }
 
+type IndexPathsConstraint struct {
+	Index  Pos          // position of INDEX keyword
+	Paths  Pos          // position of PATHS keyword
+	Lparen Pos          // position of left paren
+	Exprs  []*StringLit // indexed paths
+	Rparen Pos          // position of right paren
+}
+
+// Clone returns a deep copy of c.
+func (c *IndexPathsConstraint) Clone() *IndexPathsConstraint {
+	if c == nil {
+		return c
+	}
+	other := *c
+	other.Exprs = make([]*StringLit, len(c.Exprs))
+	for i := range c.Exprs {
+		other.Exprs[i] = c.Exprs[i].Clone()
+	}
+	return &other
+}
+
+// String returns the string representation of the constraint.
+func (c *IndexPathsConstraint) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("INDEX PATHS (")
+	for i, e := range c.Exprs {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(e.String())
+	}
+	buf.WriteString(")")
+	return buf.String()
+}
+
 type CheckConstraint struct {
 	Constraint Pos    // position of CONSTRAINT keyword
 	Name       *Ident // constraint name
@@ -1614,6 +1742,9 @@ type AlterTableStatement struct {
 	Drop           Pos    // position of ADD keyword
 	DropColumn     Pos    // position of COLUMN keyword after ADD
 	DropColumnName *Ident // drop column name
+
+	AlterCol    Pos // position of ALTER keyword before a column
+	AlterColumn Pos // position of COLUMN keyword after ALTER
 }
 
 // Clone returns a deep copy of s.
@@ -1651,6 +1782,12 @@ func (s *AlterTableStatement) String() string {
 			buf.WriteString("COLUMN ")
 		}
 		buf.WriteString(s.DropColumnName.String())
+	} else if s.ColumnDef != nil && s.AlterCol.IsValid() {
+		buf.WriteString(" ALTER ")
+		if s.AlterColumn.IsValid() {
+			buf.WriteString("COLUMN ")
+		}
+		buf.WriteString(s.ColumnDef.String())
 	} else if s.ColumnDef != nil {
 		buf.WriteString(" ADD ")
 		if s.AddColumn.IsValid() {
@@ -2806,18 +2943,22 @@ func (s *DropTableStatement) String() string {
 }
 
 type CreateViewStatement struct {
-	Create      Pos    // position of CREATE keyword
-	View        Pos    // position of VIEW keyword
-	If          Pos    // position of IF keyword
-	IfNot       Pos    // position of NOT keyword after IF
-	IfNotExists Pos    // position of EXISTS keyword after IF NOT
-	Name        *Ident // view name
+	Create       Pos    // position of CREATE keyword
+	Materialized Pos    // position of MATERIALIZED keyword
+	View         Pos    // position of VIEW keyword
+	If           Pos    // position of IF keyword
+	IfNot        Pos    // position of NOT keyword after IF
+	IfNotExists  Pos    // position of EXISTS keyword after IF NOT
+	Name         *Ident // view name
 	// TODO(pok) - we'll do this later - see note in parseCompileView()
 	// Lparen      Pos              // position of column list left paren
 	// Columns     []*Ident         // column list
 	// Rparen      Pos              // position of column list right paren
-	As     Pos              // position of AS keyword
-	Select *SelectStatement // source statement
+	Refresh         Pos              // position of REFRESH keyword
+	Every           Pos              // position of EVERY keyword
+	RefreshInterval *StringLit       // refresh interval, e.g. '1h'
+	As              Pos              // position of AS keyword
+	Select          *SelectStatement // source statement
 }
 
 // Clone returns a deep copy of s.
@@ -2827,6 +2968,7 @@ func (s *CreateViewStatement) Clone() *CreateViewStatement {
 	}
 	other := *s
 	other.Name = s.Name.Clone()
+	other.RefreshInterval = s.RefreshInterval.Clone()
 	// other.Columns = cloneIdents(s.Columns)
 	other.Select = s.Select.Clone()
 	return &other
@@ -2835,12 +2977,20 @@ func (s *CreateViewStatement) Clone() *CreateViewStatement {
 // String returns the string representation of the statement.
 func (s *CreateViewStatement) String() string {
 	var buf bytes.Buffer
-	buf.WriteString("CREATE VIEW")
+	buf.WriteString("CREATE")
+	if s.Materialized.IsValid() {
+		buf.WriteString(" MATERIALIZED")
+	}
+	buf.WriteString(" VIEW")
 	if s.IfNotExists.IsValid() {
 		buf.WriteString(" IF NOT EXISTS")
 	}
 	fmt.Fprintf(&buf, " %s", s.Name.String())
 
+	if s.RefreshInterval != nil {
+		fmt.Fprintf(&buf, " REFRESH EVERY %s", s.RefreshInterval.String())
+	}
+
 	// if len(s.Columns) > 0 {
 	// 	buf.WriteString(" (")
 	// 	for i, col := range s.Columns {
@@ -2903,11 +3053,12 @@ func (s *AlterViewStatement) String() string {
 }
 
 type DropViewStatement struct {
-	Drop     Pos    // position of DROP keyword
-	View     Pos    // position of VIEW keyword
-	If       Pos    // position of IF keyword
-	IfExists Pos    // position of EXISTS keyword after IF
-	Name     *Ident // view name
+	Drop         Pos    // position of DROP keyword
+	Materialized Pos    // position of MATERIALIZED keyword
+	View         Pos    // position of VIEW keyword
+	If           Pos    // position of IF keyword
+	IfExists     Pos    // position of EXISTS keyword after IF
+	Name         *Ident // view name
 }
 
 // Clone returns a deep copy of s.
@@ -2923,7 +3074,11 @@ func (s *DropViewStatement) Clone() *DropViewStatement {
 // String returns the string representation of the statement.
 func (s *DropViewStatement) String() string {
 	var buf bytes.Buffer
-	buf.WriteString("DROP VIEW")
+	buf.WriteString("DROP")
+	if s.Materialized.IsValid() {
+		buf.WriteString(" MATERIALIZED")
+	}
+	buf.WriteString(" VIEW")
 	if s.IfExists.IsValid() {
 		buf.WriteString(" IF EXISTS")
 	}
@@ -2931,6 +3086,110 @@ func (s *DropViewStatement) String() string {
 	return buf.String()
 }
 
+// RefreshViewStatement recomputes the contents of a materialized view.
+type RefreshViewStatement struct {
+	Refresh      Pos    // position of REFRESH keyword
+	Materialized Pos    // position of MATERIALIZED keyword
+	View         Pos    // position of VIEW keyword
+	Name         *Ident // view name
+}
+
+// Clone returns a deep copy of s.
+func (s *RefreshViewStatement) Clone() *RefreshViewStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.Name = s.Name.Clone()
+	return &other
+}
+
+// String returns the string representation of the statement.
+func (s *RefreshViewStatement) String() string {
+	return fmt.Sprintf("REFRESH MATERIALIZED VIEW %s", s.Name.String())
+}
+
+// DeclareCursorStatement opens a cursor over the results of a select, so
+// they can be fetched a page at a time.
+type DeclareCursorStatement struct {
+	Declare Pos              // position of DECLARE keyword
+	Name    *Ident           // cursor name
+	Cursor  Pos              // position of CURSOR keyword
+	For     Pos              // position of FOR keyword
+	Select  *SelectStatement // the select the cursor is over
+}
+
+// Clone returns a deep copy of s.
+func (s *DeclareCursorStatement) Clone() *DeclareCursorStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.Name = s.Name.Clone()
+	other.Select = s.Select.Clone()
+	return &other
+}
+
+// String returns the string representation of the statement.
+func (s *DeclareCursorStatement) String() string {
+	return fmt.Sprintf("DECLARE %s CURSOR FOR %s", s.Name.String(), s.Select.String())
+}
+
+// FetchStatement fetches the next rows from a cursor.
+type FetchStatement struct {
+	Fetch     Pos    // position of FETCH keyword
+	Next      Pos    // position of NEXT keyword
+	CountExpr Expr   // number of rows to fetch; one if nil
+	From      Pos    // position of FROM keyword
+	Name      *Ident // cursor name
+}
+
+// Clone returns a deep copy of s.
+func (s *FetchStatement) Clone() *FetchStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.CountExpr = CloneExpr(s.CountExpr)
+	other.Name = s.Name.Clone()
+	return &other
+}
+
+// String returns the string representation of the statement.
+func (s *FetchStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("FETCH")
+	if s.Next.IsValid() {
+		buf.WriteString(" NEXT")
+	}
+	if s.CountExpr != nil {
+		fmt.Fprintf(&buf, " %s", s.CountExpr.String())
+	}
+	fmt.Fprintf(&buf, " FROM %s", s.Name.String())
+	return buf.String()
+}
+
+// CloseCursorStatement closes a cursor.
+type CloseCursorStatement struct {
+	Close Pos    // position of CLOSE keyword
+	Name  *Ident // cursor name
+}
+
+// Clone returns a deep copy of s.
+func (s *CloseCursorStatement) Clone() *CloseCursorStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.Name = s.Name.Clone()
+	return &other
+}
+
+// String returns the string representation of the statement.
+func (s *CloseCursorStatement) String() string {
+	return fmt.Sprintf("CLOSE %s", s.Name.String())
+}
+
 type DropModelStatement struct {
 	Drop     Pos    // position of DROP keyword
 	Model    Pos    // position of MODEL keyword
@@ -3444,7 +3703,7 @@ type InsertStatement struct {
 	//	Default       Pos // position of DEFAULT keyword
 	//	DefaultValues Pos // position of VALUES keyword after DEFAULT
 
-	//	UpsertClause *UpsertClause // optional upsert clause
+	UpsertClause *UpsertClause // optional upsert clause
 }
 
 // Clone returns a deep copy of s.
@@ -3459,7 +3718,7 @@ func (s *InsertStatement) Clone() *InsertStatement {
 	other.Columns = cloneIdents(s.Columns)
 	other.TupleList = cloneExprLists(s.TupleList)
 	//other.Select = s.Select.Clone()
-	//other.UpsertClause = s.UpsertClause.Clone()
+	other.UpsertClause = s.UpsertClause.Clone()
 	return &other
 }
 
@@ -3525,9 +3784,9 @@ func (s *InsertStatement) String() string {
 	}
 	//}
 
-	//if s.UpsertClause != nil {
-	//	fmt.Fprintf(&buf, " %s", s.UpsertClause.String())
-	//}
+	if s.UpsertClause != nil {
+		fmt.Fprintf(&buf, " %s", s.UpsertClause.String())
+	}
 
 	return buf.String()
 }
@@ -3910,6 +4169,9 @@ type SelectStatement struct {
 
 	Limit     Pos  // position of LIMIT keyword
 	LimitExpr Expr // LIMIT expr
+
+	Offset     Pos  // position of OFFSET keyword
+	OffsetExpr Expr // OFFSET expr
 }
 
 // Clone returns a deep copy of s.
@@ -3929,6 +4191,7 @@ func (s *SelectStatement) Clone() *SelectStatement {
 	other.Compound = s.Compound.Clone()
 	other.OrderingTerms = cloneOrderingTerms(s.OrderingTerms)
 	other.LimitExpr = CloneExpr(s.LimitExpr)
+	other.OffsetExpr = CloneExpr(s.OffsetExpr)
 	return &other
 }
 
@@ -4049,6 +4312,10 @@ func (s *SelectStatement) String() string {
 		fmt.Fprintf(&buf, " LIMIT %s", s.LimitExpr.String())
 	}
 
+	if s.Offset.IsValid() {
+		fmt.Fprintf(&buf, " OFFSET %s", s.OffsetExpr.String())
+	}
+
 	return buf.String()
 }
 
@@ -77,6 +77,15 @@ func TestAlterTableStatement_String(t *testing.T) {
 		DropColumn:     pos(0),
 		DropColumnName: &parser.Ident{Name: "bar"},
 	}, `ALTER TABLE foo DROP COLUMN bar`)
+	AssertStatementStringer(t, &parser.AlterTableStatement{
+		Name:        &parser.Ident{Name: "foo"},
+		AlterCol:    pos(0),
+		AlterColumn: pos(0),
+		ColumnDef: &parser.ColumnDefinition{
+			Name: &parser.Ident{Name: "bar"},
+			Type: &parser.Type{Name: &parser.Ident{Name: "DECIMAL"}},
+		},
+	}, `ALTER TABLE foo ALTER COLUMN bar DECIMAL`)
 }
 
 /*func TestAnalyzeStatement_String(t *testing.T) {
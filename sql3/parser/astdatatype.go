@@ -9,12 +9,16 @@ import (
 
 func IsValidTypeName(typeName string) bool {
 	switch strings.ToLower(typeName) {
-	case dax.BaseTypeBool,
+	case dax.BaseTypeBitVector,
+		dax.BaseTypeBool,
 		dax.BaseTypeDecimal,
+		dax.BaseTypeDouble,
+		dax.BaseTypeGeoPoint,
 		dax.BaseTypeID,
 		dax.BaseTypeIDSet,
 		dax.BaseTypeIDSetQ,
 		dax.BaseTypeInt,
+		dax.BaseTypeJSON,
 		dax.BaseTypeString,
 		dax.BaseTypeStringSet,
 		dax.BaseTypeStringSetQ,
@@ -44,11 +48,15 @@ func (*DataTypeVoid) exprDataType()             {}
 func (*DataTypeRange) exprDataType()            {}
 func (*DataTypeTuple) exprDataType()            {}
 func (*DataTypeSubtable) exprDataType()         {}
+func (*DataTypeBitVector) exprDataType()        {}
 func (*DataTypeBool) exprDataType()             {}
 func (*DataTypeDecimal) exprDataType()          {}
+func (*DataTypeDouble) exprDataType()           {}
+func (*DataTypeGeoPoint) exprDataType()         {}
 func (*DataTypeID) exprDataType()               {}
 func (*DataTypeIDSet) exprDataType()            {}
 func (*DataTypeIDSetQuantum) exprDataType()     {}
+func (*DataTypeJSON) exprDataType()             {}
 func (*DataTypeInt) exprDataType()              {}
 func (*DataTypeString) exprDataType()           {}
 func (*DataTypeStringSet) exprDataType()        {}
@@ -178,6 +186,30 @@ func (*DataTypeBool) TypeInfo() map[string]interface{} {
 	return nil
 }
 
+type DataTypeBitVector struct {
+	Width int64
+}
+
+func NewDataTypeBitVector(width int64) *DataTypeBitVector {
+	return &DataTypeBitVector{
+		Width: width,
+	}
+}
+
+func (*DataTypeBitVector) BaseTypeName() string {
+	return dax.BaseTypeBitVector
+}
+
+func (dt *DataTypeBitVector) TypeDescription() string {
+	return fmt.Sprintf("%s(%d)", dax.BaseTypeBitVector, dt.Width)
+}
+
+func (dt *DataTypeBitVector) TypeInfo() map[string]interface{} {
+	return map[string]interface{}{
+		"width": dt.Width,
+	}
+}
+
 type DataTypeDecimal struct {
 	Scale int64
 }
@@ -202,6 +234,44 @@ func (d *DataTypeDecimal) TypeInfo() map[string]interface{} {
 	}
 }
 
+type DataTypeDouble struct {
+}
+
+func NewDataTypeDouble() *DataTypeDouble {
+	return &DataTypeDouble{}
+}
+
+func (*DataTypeDouble) BaseTypeName() string {
+	return dax.BaseTypeDouble
+}
+
+func (dt *DataTypeDouble) TypeDescription() string {
+	return dt.BaseTypeName()
+}
+
+func (*DataTypeDouble) TypeInfo() map[string]interface{} {
+	return nil
+}
+
+type DataTypeGeoPoint struct {
+}
+
+func NewDataTypeGeoPoint() *DataTypeGeoPoint {
+	return &DataTypeGeoPoint{}
+}
+
+func (*DataTypeGeoPoint) BaseTypeName() string {
+	return dax.BaseTypeGeoPoint
+}
+
+func (dt *DataTypeGeoPoint) TypeDescription() string {
+	return dt.BaseTypeName()
+}
+
+func (*DataTypeGeoPoint) TypeInfo() map[string]interface{} {
+	return nil
+}
+
 type DataTypeID struct {
 }
 
@@ -278,6 +348,25 @@ func (*DataTypeInt) TypeInfo() map[string]interface{} {
 	return nil
 }
 
+type DataTypeJSON struct {
+}
+
+func NewDataTypeJSON() *DataTypeJSON {
+	return &DataTypeJSON{}
+}
+
+func (*DataTypeJSON) BaseTypeName() string {
+	return dax.BaseTypeJSON
+}
+
+func (dt *DataTypeJSON) TypeDescription() string {
+	return dt.BaseTypeName()
+}
+
+func (*DataTypeJSON) TypeInfo() map[string]interface{} {
+	return nil
+}
+
 type DataTypeString struct {
 }
 
@@ -119,11 +119,130 @@ func (p *Parser) parseNonExplainStatement() (Statement, error) {
 		//		return p.parseWithStatement()
 	case SHOW:
 		return p.parseShowStatement()
+	case REFRESH:
+		return p.parseRefreshViewStatement()
+	case BEGIN:
+		return p.parseTransactionStatement()
+	case DECLARE:
+		return p.parseDeclareCursorStatement()
+	case FETCH:
+		return p.parseFetchStatement()
+	case CLOSE:
+		return p.parseCloseCursorStatement()
+	case COMMIT, END, ROLLBACK:
+		return nil, &Error{Pos: p.pos, Msg: p.tok.String() + " without a preceding BEGIN"}
 	default:
 		return nil, p.errorExpected(p.pos, p.tok, "statement")
 	}
 }
 
+// parseDeclareCursorStatement parses DECLARE name CURSOR FOR select.
+func (p *Parser) parseDeclareCursorStatement() (_ *DeclareCursorStatement, err error) {
+	assert(p.peek() == DECLARE)
+
+	var stmt DeclareCursorStatement
+	stmt.Declare, _, _ = p.scan()
+
+	if stmt.Name, err = p.parseIdent("cursor name"); err != nil {
+		return &stmt, err
+	}
+
+	if p.peek() != CURSOR {
+		return &stmt, p.errorExpected(p.pos, p.tok, "CURSOR")
+	}
+	stmt.Cursor, _, _ = p.scan()
+
+	if p.peek() != FOR {
+		return &stmt, p.errorExpected(p.pos, p.tok, "FOR")
+	}
+	stmt.For, _, _ = p.scan()
+
+	if p.peek() != SELECT {
+		return &stmt, p.errorExpected(p.pos, p.tok, "SELECT")
+	}
+	if stmt.Select, err = p.parseSelectStatement(false, nil); err != nil {
+		return &stmt, err
+	}
+	return &stmt, nil
+}
+
+// parseFetchStatement parses FETCH [NEXT] [count] FROM name.
+func (p *Parser) parseFetchStatement() (_ *FetchStatement, err error) {
+	assert(p.peek() == FETCH)
+
+	var stmt FetchStatement
+	stmt.Fetch, _, _ = p.scan()
+
+	if p.peek() == NEXT {
+		stmt.Next, _, _ = p.scan()
+	}
+
+	if p.peek() != FROM {
+		if stmt.CountExpr, err = p.ParseExpr(); err != nil {
+			return &stmt, err
+		}
+	}
+
+	if p.peek() != FROM {
+		return &stmt, p.errorExpected(p.pos, p.tok, "FROM")
+	}
+	stmt.From, _, _ = p.scan()
+
+	if stmt.Name, err = p.parseIdent("cursor name"); err != nil {
+		return &stmt, err
+	}
+	return &stmt, nil
+}
+
+// parseCloseCursorStatement parses CLOSE name.
+func (p *Parser) parseCloseCursorStatement() (_ *CloseCursorStatement, err error) {
+	assert(p.peek() == CLOSE)
+
+	var stmt CloseCursorStatement
+	stmt.Close, _, _ = p.scan()
+
+	if stmt.Name, err = p.parseIdent("cursor name"); err != nil {
+		return &stmt, err
+	}
+	return &stmt, nil
+}
+
+// parseTransactionStatement parses a BEGIN statement, followed by the
+// statements in the transaction, up to and including the COMMIT or
+// ROLLBACK which ends it.
+func (p *Parser) parseTransactionStatement() (_ *TransactionStatement, err error) {
+	var stmt TransactionStatement
+	if stmt.Begin, err = p.parseBeginStatement(); err != nil {
+		return &stmt, err
+	}
+
+	for {
+		if tok := p.peek(); tok != SEMI {
+			return &stmt, p.errorExpected(p.pos, p.tok, "semicolon")
+		}
+		p.scan()
+
+		switch p.peek() {
+		case COMMIT, END:
+			stmt.Commit, err = p.parseCommitStatement()
+			return &stmt, err
+		case ROLLBACK:
+			stmt.Rollback, err = p.parseRollbackStatement()
+			return &stmt, err
+		case BEGIN:
+			return &stmt, &Error{Pos: p.pos, Msg: "transactions cannot be nested"}
+		case EOF:
+			return &stmt, p.errorExpected(p.pos, p.tok, "COMMIT or ROLLBACK")
+		}
+
+		body, err := p.parseNonExplainStatement()
+		if err != nil {
+			return &stmt, err
+		}
+		stmt.Body = append(stmt.Body, body)
+	}
+}
+
 // parseWithStatement is called only from parseNonExplainStatement as we don't
 // know what kind of statement we'll have after the CTEs (e.g. SELECT, INSERT, etc).
 /*func (p *Parser) parseWithStatement() (Statement, error) {
@@ -241,7 +360,7 @@ func (p *Parser) parseShowCreateTableStatement(showPos Pos, createPos Pos) (_ *S
 	return &stmt, nil
 }
 
-/*func (p *Parser) parseBeginStatement() (*BeginStatement, error) {
+func (p *Parser) parseBeginStatement() (*BeginStatement, error) {
 	assert(p.peek() == BEGIN)
 
 	var stmt BeginStatement
@@ -262,9 +381,9 @@ func (p *Parser) parseShowCreateTableStatement(showPos Pos, createPos Pos) (_ *S
 		stmt.Transaction, _, _ = p.scan()
 	}
 	return &stmt, nil
-}*/
+}
 
-/*func (p *Parser) parseCommitStatement() (*CommitStatement, error) {
+func (p *Parser) parseCommitStatement() (*CommitStatement, error) {
 	assert(p.peek() == COMMIT || p.peek() == END)
 
 	var stmt CommitStatement
@@ -278,9 +397,9 @@ func (p *Parser) parseShowCreateTableStatement(showPos Pos, createPos Pos) (_ *S
 		stmt.Transaction, _, _ = p.scan()
 	}
 	return &stmt, nil
-}*/
+}
 
-/*func (p *Parser) parseRollbackStatement() (_ *RollbackStatement, err error) {
+func (p *Parser) parseRollbackStatement() (_ *RollbackStatement, err error) {
 	assert(p.peek() == ROLLBACK)
 
 	var stmt RollbackStatement
@@ -302,7 +421,7 @@ func (p *Parser) parseShowCreateTableStatement(showPos Pos, createPos Pos) (_ *S
 		}
 	}
 	return &stmt, nil
-}*/
+}
 
 /*func (p *Parser) parseSavepointStatement() (_ *SavepointStatement, err error) {
 	assert(p.peek() == SAVEPOINT)
@@ -340,7 +459,7 @@ func (p *Parser) parseCreateStatement() (Statement, error) {
 		return p.parseCreateDatabaseStatement(pos)
 	case TABLE:
 		return p.parseCreateTableStatement(pos)
-	case VIEW:
+	case VIEW, MATERIALIZED:
 		return p.parseCreateViewStatement(pos)
 		/*case INDEX, UNIQUE:
 		return p.parseCreateIndexStatement(pos)*/
@@ -378,7 +497,7 @@ func (p *Parser) parseDropStatement() (Statement, error) {
 		return p.parseDropDatabaseStatement(pos)
 	case TABLE:
 		return p.parseDropTableStatement(pos)
-	case VIEW:
+	case VIEW, MATERIALIZED:
 		return p.parseDropViewStatement(pos)
 		/* case INDEX:
 		return p.parseDropIndexStatement(pos)*/
@@ -519,9 +638,9 @@ func (p *Parser) parseCreateTableStatement(createPos Pos) (_ *CreateTableStateme
 
 		if stmt.Columns, err = p.parseColumnDefinitions(); err != nil {
 			return &stmt, err
-		} /*else if stmt.Constraints, err = p.parseTableConstraints(); err != nil {
+		} else if stmt.Constraints, err = p.parseTableConstraints(); err != nil {
 			return &stmt, err
-		}*/
+		}
 
 		if p.peek() != RP {
 			return &stmt, p.errorExpected(p.pos, p.tok, "right paren")
@@ -574,6 +693,8 @@ func (p *Parser) parseTableOption() (_ TableOption, err error) {
 	switch p.peek() {
 	case KEYPARTITIONS:
 		return p.parseKeyPartitionsOption(optionPos)
+	case RETENTION:
+		return p.parseRetentionOption()
 	default:
 		assert(p.peek() == COMMENT)
 		return p.parseCommentOption()
@@ -596,6 +717,30 @@ func (p *Parser) parseCommentOption() (_ *CommentOption, err error) {
 	return &opt, nil
 }
 
+func (p *Parser) parseRetentionOption() (_ *RetentionOption, err error) {
+	assert(p.peek() == RETENTION)
+
+	var opt RetentionOption
+	opt.Retention, _, _ = p.scan()
+
+	if opt.Column, err = p.parseIdent("column name"); err != nil {
+		return &opt, err
+	}
+
+	if p.peek() != TTL {
+		return &opt, p.errorExpected(p.pos, p.tok, "TTL")
+	}
+	opt.Ttl, _, _ = p.scan()
+
+	if isLiteralToken(p.peek()) {
+		opt.Expr = p.mustParseLiteral()
+	} else {
+		return &opt, p.errorExpected(p.pos, p.tok, "literal")
+	}
+
+	return &opt, nil
+}
+
 func (p *Parser) parseKeyPartitionsOption(optionPos Pos) (_ *KeyPartitionsOption, err error) {
 	assert(p.peek() == KEYPARTITIONS)
 
@@ -646,7 +791,7 @@ func (p *Parser) parseColumnDefinition() (_ *ColumnDefinition, err error) {
 	return &col, nil
 }
 
-/*func (p *Parser) parseTableConstraints() (_ []Constraint, err error) {
+func (p *Parser) parseTableConstraints() (_ []Constraint, err error) {
 	if !isConstraintStartToken(p.peek(), true) {
 		return nil, nil
 	}
@@ -667,7 +812,7 @@ func (p *Parser) parseColumnDefinition() (_ *ColumnDefinition, err error) {
 		}
 		p.scan()
 	}
-}*/
+}
 
 func (p *Parser) parseColumnConstraints() (_ []Constraint, err error) {
 	var a []Constraint
@@ -696,6 +841,12 @@ func (p *Parser) parseConstraint(isTable bool) (_ Constraint, err error) {
 		}
 	}*/
 
+	// PRIMARY KEY is the only table constraint.
+	if isTable {
+		assert(p.peek() == PRIMARY)
+		return p.parsePrimaryKeyConstraint(constraintPos, name, isTable)
+	}
+
 	// Table constraints only use a subset of column constraints.
 	/*if isTable {
 		switch p.peek() {
@@ -715,8 +866,12 @@ func (p *Parser) parseConstraint(isTable bool) (_ Constraint, err error) {
 	switch p.peek() {
 	//case PRIMARY:
 	//	return p.parsePrimaryKeyConstraint(constraintPos, name, isTable)
-	//case NOT:
-	//	return p.parseNotNullConstraint(constraintPos, name)
+	case NOT:
+		return p.parseNotNullConstraint(constraintPos, name)
+	case CHECK:
+		return p.parseCheckConstraint(constraintPos, name)
+	case DEFAULT:
+		return p.parseDefaultConstraint(constraintPos, name)
 	case MIN:
 		return p.parseMinConstraint(constraintPos, name)
 	case MAX:
@@ -725,19 +880,17 @@ func (p *Parser) parseConstraint(isTable bool) (_ Constraint, err error) {
 		return p.parseTimeUnitConstraint(constraintPos, name)
 	case TIMEQUANTUM:
 		return p.parseTimeQuantumConstraint(constraintPos, name)
+	case INDEX:
+		return p.parseIndexPathsConstraint(constraintPos, name)
 		//case UNIQUE:
 		//	return p.parseUniqueConstraint(constraintPos, name, isTable)
-		//case CHECK:
-		//	return p.parseCheckConstraint(constraintPos, name)
-		//case DEFAULT:
-		//	return p.parseDefaultConstraint(constraintPos, name)
 	default:
 		assert(p.peek() == CACHETYPE)
 		return p.parseCacheTypeConstraint(constraintPos, name)
 	}
 }
 
-/*func (p *Parser) parsePrimaryKeyConstraint(constraintPos Pos, name *Ident, isTable bool) (_ *PrimaryKeyConstraint, err error) {
+func (p *Parser) parsePrimaryKeyConstraint(constraintPos Pos, name *Ident, isTable bool) (_ *PrimaryKeyConstraint, err error) {
 	assert(p.peek() == PRIMARY)
 
 	var cons PrimaryKeyConstraint
@@ -781,9 +934,9 @@ func (p *Parser) parseConstraint(isTable bool) (_ Constraint, err error) {
 		}
 	}
 	return &cons, nil
-}*/
+}
 
-/*func (p *Parser) parseNotNullConstraint(constraintPos Pos, name *Ident) (_ *NotNullConstraint, err error) {
+func (p *Parser) parseNotNullConstraint(constraintPos Pos, name *Ident) (_ *NotNullConstraint, err error) {
 	assert(p.peek() == NOT)
 
 	var cons NotNullConstraint
@@ -797,7 +950,7 @@ func (p *Parser) parseConstraint(isTable bool) (_ Constraint, err error) {
 	cons.Null, _, _ = p.scan()
 
 	return &cons, nil
-}*/
+}
 
 func (p *Parser) parseMinConstraint(constraintPos Pos, name *Ident) (_ *MinConstraint, err error) {
 	assert(p.peek() == MIN)
@@ -876,6 +1029,40 @@ func (p *Parser) parseTimeUnitConstraint(constraintPos Pos, name *Ident) (_ *Tim
 	return &cons, nil
 }
 
+func (p *Parser) parseIndexPathsConstraint(constraintPos Pos, name *Ident) (_ *IndexPathsConstraint, err error) {
+	assert(p.peek() == INDEX)
+
+	var cons IndexPathsConstraint
+	cons.Index, _, _ = p.scan()
+
+	if p.peek() != PATHS {
+		return &cons, p.errorExpected(p.pos, p.tok, "PATHS")
+	}
+	cons.Paths, _, _ = p.scan()
+
+	if p.peek() != LP {
+		return &cons, p.errorExpected(p.pos, p.tok, "left paren")
+	}
+	cons.Lparen, _, _ = p.scan()
+
+	for {
+		if p.peek() != STRING {
+			return &cons, p.errorExpected(p.pos, p.tok, "string literal")
+		}
+		cons.Exprs = append(cons.Exprs, p.mustParseLiteral().(*StringLit))
+
+		if p.peek() == RP {
+			break
+		} else if p.peek() != COMMA {
+			return &cons, p.errorExpected(p.pos, p.tok, "comma or right paren")
+		}
+		p.scan()
+	}
+	cons.Rparen, _, _ = p.scan()
+
+	return &cons, nil
+}
+
 func (p *Parser) parseTimeQuantumConstraint(constraintPos Pos, name *Ident) (_ *TimeQuantumConstraint, err error) {
 	assert(p.peek() == TIMEQUANTUM)
 
@@ -933,7 +1120,7 @@ func (p *Parser) parseTimeQuantumConstraint(constraintPos Pos, name *Ident) (_ *
 	return &cons, nil
 }*/
 
-/*func (p *Parser) parseCheckConstraint(constraintPos Pos, name *Ident) (_ *CheckConstraint, err error) {
+func (p *Parser) parseCheckConstraint(constraintPos Pos, name *Ident) (_ *CheckConstraint, err error) {
 	assert(p.peek() == CHECK)
 
 	var cons CheckConstraint
@@ -956,9 +1143,9 @@ func (p *Parser) parseTimeQuantumConstraint(constraintPos Pos, name *Ident) (_ *
 	cons.Rparen, _, _ = p.scan()
 
 	return &cons, nil
-}*/
+}
 
-/*func (p *Parser) parseDefaultConstraint(constraintPos Pos, name *Ident) (_ *DefaultConstraint, err error) {
+func (p *Parser) parseDefaultConstraint(constraintPos Pos, name *Ident) (_ *DefaultConstraint, err error) {
 	assert(p.peek() == DEFAULT)
 
 	var cons DefaultConstraint
@@ -968,9 +1155,11 @@ func (p *Parser) parseTimeQuantumConstraint(constraintPos Pos, name *Ident) (_ *
 	if isLiteralToken(p.peek()) {
 		cons.Expr = p.mustParseLiteral()
 	} else if p.peek() == PLUS || p.peek() == MINUS {
-		if cons.Expr, err = p.parseSignedNumber("signed number"); err != nil {
-			return &cons, err
+		opPos, op, _ := p.scan()
+		if p.peek() != INTEGER && p.peek() != FLOAT {
+			return &cons, p.errorExpected(p.pos, p.tok, "signed number")
 		}
+		cons.Expr = &UnaryExpr{OpPos: opPos, Op: op, X: p.mustParseLiteral()}
 	} else {
 		if p.peek() != LP {
 			return &cons, p.errorExpected(p.pos, p.tok, "literal value or left paren")
@@ -987,7 +1176,7 @@ func (p *Parser) parseTimeQuantumConstraint(constraintPos Pos, name *Ident) (_ *
 		cons.Rparen, _, _ = p.scan()
 	}
 	return &cons, nil
-}*/
+}
 
 /*func (p *Parser) parseForeignKeyConstraint(constraintPos Pos, name *Ident, isTable bool) (_ *ForeignKeyConstraint, err error) {
 	var cons ForeignKeyConstraint
@@ -1238,10 +1427,16 @@ func (p *Parser) parseCopyStatement() (_ *CopyStatement, err error) {
 }
 
 func (p *Parser) parseCreateViewStatement(createPos Pos) (_ *CreateViewStatement, err error) {
-	assert(p.peek() == VIEW)
+	assert(p.peek() == VIEW || p.peek() == MATERIALIZED)
 
 	var stmt CreateViewStatement
 	stmt.Create = createPos
+	if p.peek() == MATERIALIZED {
+		stmt.Materialized, _, _ = p.scan()
+		if p.peek() != VIEW {
+			return &stmt, p.errorExpected(p.pos, p.tok, "VIEW")
+		}
+	}
 	stmt.View, _, _ = p.scan()
 
 	// Parse optional "IF NOT EXISTS".
@@ -1287,6 +1482,20 @@ func (p *Parser) parseCreateViewStatement(createPos Pos) (_ *CreateViewStatement
 	// 	stmt.Rparen, _, _ = p.scan()
 	// }
 
+	// Parse optional "REFRESH EVERY interval" for materialized views.
+	if stmt.Materialized.IsValid() && p.peek() == REFRESH {
+		stmt.Refresh, _, _ = p.scan()
+		if p.peek() != EVERY {
+			return &stmt, p.errorExpected(p.pos, p.tok, "EVERY")
+		}
+		stmt.Every, _, _ = p.scan()
+
+		if p.peek() != STRING {
+			return &stmt, p.errorExpected(p.pos, p.tok, "string literal")
+		}
+		stmt.RefreshInterval = p.mustParseLiteral().(*StringLit)
+	}
+
 	// Parse "AS select-stmt"
 	if p.peek() != AS {
 		return &stmt, p.errorExpected(p.pos, p.tok, "AS")
@@ -1343,10 +1552,16 @@ func (p *Parser) parseAlterViewStatement(alterPos Pos) (_ *AlterViewStatement, e
 }
 
 func (p *Parser) parseDropViewStatement(dropPos Pos) (_ *DropViewStatement, err error) {
-	assert(p.peek() == VIEW)
+	assert(p.peek() == VIEW || p.peek() == MATERIALIZED)
 
 	var stmt DropViewStatement
 	stmt.Drop = dropPos
+	if p.peek() == MATERIALIZED {
+		stmt.Materialized, _, _ = p.scan()
+		if p.peek() != VIEW {
+			return &stmt, p.errorExpected(p.pos, p.tok, "VIEW")
+		}
+	}
 	stmt.View, _, _ = p.scan()
 
 	// Parse optional "IF EXISTS".
@@ -1365,6 +1580,28 @@ func (p *Parser) parseDropViewStatement(dropPos Pos) (_ *DropViewStatement, err
 	return &stmt, nil
 }
 
+func (p *Parser) parseRefreshViewStatement() (_ *RefreshViewStatement, err error) {
+	assert(p.peek() == REFRESH)
+
+	var stmt RefreshViewStatement
+	stmt.Refresh, _, _ = p.scan()
+
+	if p.peek() != MATERIALIZED {
+		return &stmt, p.errorExpected(p.pos, p.tok, "MATERIALIZED")
+	}
+	stmt.Materialized, _, _ = p.scan()
+
+	if p.peek() != VIEW {
+		return &stmt, p.errorExpected(p.pos, p.tok, "VIEW")
+	}
+	stmt.View, _, _ = p.scan()
+
+	if stmt.Name, err = p.parseIdent("view name"); err != nil {
+		return &stmt, err
+	}
+	return &stmt, nil
+}
+
 func (p *Parser) parseDropModelStatement(dropPos Pos) (_ *DropModelStatement, err error) {
 	assert(p.peek() == MODEL)
 
@@ -2099,16 +2336,16 @@ func (p *Parser) parseInsertStatement(withClause *WithClause) (_ *InsertStatemen
 	}
 
 	// Parse optional upsert clause.
-	//if p.peek() == ON {
-	//	if stmt.UpsertClause, err = p.parseUpsertClause(); err != nil {
-	//		return &stmt, err
-	//	}
-	//}
+	if p.peek() == ON && stmt.Insert.IsValid() {
+		if stmt.UpsertClause, err = p.parseUpsertClause(); err != nil {
+			return &stmt, err
+		}
+	}
 
 	return &stmt, nil
 }
 
-/*func (p *Parser) parseUpsertClause() (_ *UpsertClause, err error) {
+func (p *Parser) parseUpsertClause() (_ *UpsertClause, err error) {
 	assert(p.peek() == ON)
 
 	var clause UpsertClause
@@ -2191,9 +2428,9 @@ func (p *Parser) parseInsertStatement(withClause *WithClause) (_ *InsertStatemen
 	}
 
 	return &clause, nil
-}*/
+}
 
-/*func (p *Parser) parseIndexedColumn() (_ *IndexedColumn, err error) {
+func (p *Parser) parseIndexedColumn() (_ *IndexedColumn, err error) {
 	var col IndexedColumn
 	if col.X, err = p.ParseExpr(); err != nil {
 		return &col, err
@@ -2204,7 +2441,7 @@ func (p *Parser) parseInsertStatement(withClause *WithClause) (_ *InsertStatemen
 		col.Desc, _, _ = p.scan()
 	}
 	return &col, nil
-}*/
+}
 
 func (p *Parser) parseUpdateStatement(withClause *WithClause) (_ *UpdateStatement, err error) {
 	assert(p.peek() == UPDATE)
@@ -2535,6 +2772,14 @@ func (p *Parser) parseSelectStatement(compounded bool, withClause *WithClause) (
 			return &stmt, err
 		}
 	}
+
+	// Parse OFFSET clause.
+	if !compounded && p.peek() == OFFSET {
+		stmt.Offset, _, _ = p.scan()
+		if stmt.OffsetExpr, err = p.ParseExpr(); err != nil {
+			return &stmt, err
+		}
+	}
 	return &stmt, nil
 }
 
@@ -2976,6 +3221,8 @@ func (p *Parser) mustParseLiteral() Expr {
 		return &BoolLit{ValuePos: pos, Value: tok == TRUE}
 	case BLOB:
 		return &StringLit{ValuePos: pos, IsBlob: true, Value: lit}
+	case CURRENT_DATE, CURRENT_TIMESTAMP:
+		return &SysVariable{NamePos: pos, Token: tok}
 	default:
 		assert(tok == NULL)
 		return &NullLit{ValuePos: pos}
@@ -3750,8 +3997,21 @@ func (p *Parser) parseAlterTableStatement(alterPos Pos) (_ *AlterTableStatement,
 		}
 		return &stmt, nil
 
+	case ALTER:
+		// Parse "ALTER [COLUMN] column-name type [constraints]".
+		stmt.AlterCol, _, _ = p.scan()
+		if p.peek() == COLUMN {
+			stmt.AlterColumn, _, _ = p.scan()
+		} else if !isIdentToken(p.peek()) {
+			return &stmt, p.errorExpected(p.pos, p.tok, "COLUMN keyword or column name")
+		}
+		if stmt.ColumnDef, err = p.parseColumnDefinition(); err != nil {
+			return &stmt, err
+		}
+		return &stmt, nil
+
 	default:
-		return &stmt, p.errorExpected(p.pos, p.tok, "ADD, DROP or RENAME")
+		return &stmt, p.errorExpected(p.pos, p.tok, "ADD, ALTER, DROP or RENAME")
 	}
 }
 
@@ -3867,7 +4127,7 @@ func isDatabaseOptionStartToken(tok Token) bool {
 // isTableOptionStartToken returns true if tok is the initial token of a table option.
 func isTableOptionStartToken(tok Token) bool {
 	switch tok {
-	case KEYPARTITIONS, COMMENT:
+	case KEYPARTITIONS, COMMENT, RETENTION:
 		return true
 	default:
 		return false
@@ -3914,7 +4174,9 @@ func isConstraintStartToken(tok Token, isTable bool) bool {
 	//	return true // table & column
 	//case FOREIGN:
 	//	return isTable // table only
-	case MIN, MAX, TIMEUNIT, TIMEQUANTUM, CACHETYPE:
+	case PRIMARY:
+		return isTable // table only
+	case MIN, MAX, TIMEUNIT, TIMEQUANTUM, CACHETYPE, INDEX, NOT, CHECK, DEFAULT:
 		return !isTable // column only
 	default:
 		return false
@@ -257,6 +257,12 @@ func (s *Scanner) scanNumber() (Pos, Token, string) {
 		}
 	}
 
+	// A lone dot is a separator, even if what follows it looks like an
+	// exponent (as in "t.events").
+	if s.buf.String() == "." {
+		return pos, DOT, "."
+	}
+
 	// Read exponent with optional +/- sign.
 	if ch := s.peek(); ch == 'e' || ch == 'E' {
 		tok = FLOAT
@@ -285,9 +291,6 @@ func (s *Scanner) scanNumber() (Pos, Token, string) {
 	}
 
 	lit := s.buf.String()
-	if lit == "." {
-		return pos, DOT, lit
-	}
 	return pos, tok, lit
 }
 
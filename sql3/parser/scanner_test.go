@@ -147,6 +147,7 @@ func TestScanner_Scan(t *testing.T) {
 	})
 	t.Run("DOT", func(t *testing.T) {
 		AssertScan(t, ".", parser.DOT, ".")
+		AssertScan(t, ".events", parser.DOT, ".")
 	})
 	t.Run("ILLEGAL", func(t *testing.T) {
 		AssertScan(t, "^", parser.ILLEGAL, "^")
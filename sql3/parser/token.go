@@ -97,6 +97,7 @@ const (
 	CASE
 	CAST
 	CHECK
+	CLOSE
 	COLUMN
 	COLUMNS
 	COLUMNKW
@@ -108,10 +109,12 @@ const (
 	CREATE
 	CROSS
 	CURRENT
+	CURSOR
 	CURRENT_DATE
 	CURRENT_TIMESTAMP
 	DATABASE
 	DATABASES
+	DECLARE
 	DEFAULT
 	DEFERRABLE
 	DEFERRED
@@ -126,12 +129,14 @@ const (
 	END
 	EPOCH
 	ESCAPE
+	EVERY
 	EXCEPT
 	EXCLUDE
 	EXCLUSIVE
 	EXISTS
 	EXPLAIN
 	FAIL
+	FETCH
 	FILTER
 	FIRST
 	FOLLOWING
@@ -169,9 +174,11 @@ const (
 	LRU
 	MAP
 	MATCH
+	MATERIALIZED
 	MAX
 	MIN
 	MODEL
+	NEXT
 	NO
 	NOT
 	NOTBETWEEN
@@ -184,6 +191,7 @@ const (
 	NOTREGEXP
 	NULLS
 	OF
+	OFFSET
 	ON
 	OR
 	ORDER
@@ -191,6 +199,7 @@ const (
 	OUTER
 	OVER
 	PARTITION
+	PATHS
 	PLAN
 	PRAGMA
 	PRECEDING
@@ -201,6 +210,7 @@ const (
 	RANKED
 	RECURSIVE
 	REFERENCES
+	REFRESH
 	REGEXP
 	REGISTER
 	REINDEX
@@ -208,6 +218,7 @@ const (
 	RENAME
 	REPLACE
 	RESTRICT
+	RETENTION
 	RETURNS
 	RETURN
 	RIGHT
@@ -324,6 +335,7 @@ var tokens = [...]string{
 	CASE:              "CASE",
 	CAST:              "CAST",
 	CHECK:             "CHECK",
+	CLOSE:             "CLOSE",
 	COLUMN:            "COLUMN",
 	COLUMNS:           "COLUMNS",
 	COLUMNKW:          "COLUMNKW",
@@ -335,10 +347,12 @@ var tokens = [...]string{
 	CREATE:            "CREATE",
 	CROSS:             "CROSS",
 	CURRENT:           "CURRENT",
+	CURSOR:            "CURSOR",
 	CURRENT_DATE:      "CURRENT_DATE",
 	CURRENT_TIMESTAMP: "CURRENT_TIMESTAMP",
 	DATABASE:          "DATABASE",
 	DATABASES:         "DATABASES",
+	DECLARE:           "DECLARE",
 	DEFAULT:           "DEFAULT",
 	DEFERRABLE:        "DEFERRABLE",
 	DEFERRED:          "DEFERRED",
@@ -353,12 +367,14 @@ var tokens = [...]string{
 	END:               "END",
 	EPOCH:             "EPOCH",
 	ESCAPE:            "ESCAPE",
+	EVERY:             "EVERY",
 	EXCEPT:            "EXCEPT",
 	EXCLUDE:           "EXCLUDE",
 	EXCLUSIVE:         "EXCLUSIVE",
 	EXISTS:            "EXISTS",
 	EXPLAIN:           "EXPLAIN",
 	FAIL:              "FAIL",
+	FETCH:             "FETCH",
 	FILTER:            "FILTER",
 	FIRST:             "FIRST",
 	FOLLOWING:         "FOLLOWING",
@@ -396,9 +412,11 @@ var tokens = [...]string{
 	MAP:               "MAP",
 	LRU:               "LRU",
 	MATCH:             "MATCH",
+	MATERIALIZED:      "MATERIALIZED",
 	MAX:               "MAX",
 	MIN:               "MIN",
 	MODEL:             "MODEL",
+	NEXT:              "NEXT",
 	NO:                "NO",
 	NOT:               "NOT",
 	NOTBETWEEN:        "NOTBETWEEN",
@@ -411,6 +429,7 @@ var tokens = [...]string{
 	NOTREGEXP:         "NOTREGEXP",
 	NULLS:             "NULLS",
 	OF:                "OF",
+	OFFSET:            "OFFSET",
 	ON:                "ON",
 	OR:                "OR",
 	ORDER:             "ORDER",
@@ -418,6 +437,7 @@ var tokens = [...]string{
 	OUTER:             "OUTER",
 	OVER:              "OVER",
 	PARTITION:         "PARTITION",
+	PATHS:             "PATHS",
 	PLAN:              "PLAN",
 	PRAGMA:            "PRAGMA",
 	PRECEDING:         "PRECEDING",
@@ -428,6 +448,7 @@ var tokens = [...]string{
 	RANKED:            "RANKED",
 	RECURSIVE:         "RECURSIVE",
 	REFERENCES:        "REFERENCES",
+	REFRESH:           "REFRESH",
 	REGEXP:            "REGEXP",
 	REGISTER:          "REGISTER",
 	REINDEX:           "REINDEX",
@@ -435,6 +456,7 @@ var tokens = [...]string{
 	RENAME:            "RENAME",
 	REPLACE:           "REPLACE",
 	RESTRICT:          "RESTRICT",
+	RETENTION:         "RETENTION",
 	RETURNS:           "RETURNS",
 	RETURN:            "RETURN",
 	RIGHT:             "RIGHT",
@@ -48,6 +48,15 @@ func walk(v Visitor, node Node) (_ Node, err error) {
 			}
 		}
 
+	case *TransactionStatement:
+		for i := range n.Body {
+			if stmt, err := walk(v, n.Body[i]); err != nil {
+				return node, err
+			} else if stmt != nil {
+				n.Body[i] = stmt.(Statement)
+			}
+		}
+
 	case *RollbackStatement:
 		if err := walkIdent(v, &n.SavepointName); err != nil {
 			return node, err
@@ -138,6 +147,34 @@ func walk(v Visitor, node Node) (_ Node, err error) {
 			return node, err
 		}
 
+	case *RefreshViewStatement:
+		if err := walkIdent(v, &n.Name); err != nil {
+			return node, err
+		}
+
+	case *DeclareCursorStatement:
+		if err := walkIdent(v, &n.Name); err != nil {
+			return node, err
+		}
+		if n.Select != nil {
+			if _, err := walk(v, n.Select); err != nil {
+				return node, err
+			}
+		}
+
+	case *FetchStatement:
+		if err := walkExpr(v, &n.CountExpr); err != nil {
+			return node, err
+		}
+		if err := walkIdent(v, &n.Name); err != nil {
+			return node, err
+		}
+
+	case *CloseCursorStatement:
+		if err := walkIdent(v, &n.Name); err != nil {
+			return node, err
+		}
+
 	case *DropIndexStatement:
 		if err := walkIdent(v, &n.Name); err != nil {
 			return node, err
@@ -269,6 +306,16 @@ func walk(v Visitor, node Node) (_ Node, err error) {
 			}
 		}
 
+		if n.UpsertClause != nil {
+			if clause, err := walk(v, n.UpsertClause); err != nil {
+				return node, err
+			} else if clause != nil {
+				n.UpsertClause = clause.(*UpsertClause)
+			} else {
+				n.UpsertClause = nil
+			}
+		}
+
 		/*if n.Select != nil {
 			if sel, err := walk(v, n.Select); err != nil {
 				return node, err
@@ -0,0 +1,122 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"math/bits"
+	"strconv"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/pkg/errors"
+)
+
+// A bitvector is stored in a set field, with one row per bit and a further
+// row, at the width, marking that the record has a vector at all. Values are
+// written and read as hex strings, most significant bit first.
+
+// maxBitVectorWidth is the widest bitvector a column may hold.
+const maxBitVectorWidth = 4096
+
+// bitVectorWidth returns the width given in a bitvector type.
+func bitVectorWidth(typ *parser.Type) (int64, error) {
+	if typ.Scale == nil {
+		return 0, sql3.NewErrBitVectorWidthExpected(typ.Name.NamePos.Line, typ.Name.NamePos.Column)
+	}
+	width, err := strconv.ParseInt(typ.Scale.Value, 10, 64)
+	if err != nil || width < 8 || width > maxBitVectorWidth || width%8 != 0 {
+		return 0, sql3.NewErrInvalidBitVectorWidth(typ.Scale.ValuePos.Line, typ.Scale.ValuePos.Column, typ.Scale.Value, maxBitVectorWidth)
+	}
+	return width, nil
+}
+
+// bitVectorRows returns the rows to set for the bitvector s.
+func bitVectorRows(s string, width int64) ([]uint64, error) {
+	positions, err := pilosa.ParseBitVector(s, width)
+	if err != nil {
+		return nil, sql3.NewErrInvalidBitVector(0, 0, s, width)
+	}
+	return append(positions, uint64(width)), nil
+}
+
+// bitVectorFromRows returns the bitvector held in rows, or nil if the record
+// has none.
+func bitVectorFromRows(rows []uint64, width int64) interface{} {
+	for _, r := range rows {
+		if r == uint64(width) {
+			return pilosa.FormatBitVector(rows, width)
+		}
+	}
+	return nil
+}
+
+// hammingDistance returns the number of bits which differ between two hex
+// bitvectors of the same length.
+func hammingDistance(a, b string) (int64, error) {
+	if len(a) != len(b) {
+		return 0, sql3.NewErrInvalidBitVector(0, 0, b, int64(len(a))*4)
+	}
+	var d int64
+	for i := 0; i < len(a); i++ {
+		x, okx := hexNibble(a[i])
+		y, oky := hexNibble(b[i])
+		if !okx {
+			return 0, sql3.NewErrInvalidBitVector(0, 0, a, int64(len(a))*4)
+		}
+		if !oky {
+			return 0, sql3.NewErrInvalidBitVector(0, 0, b, int64(len(b))*4)
+		}
+		d += int64(bits.OnesCount8(x ^ y))
+	}
+	return d, nil
+}
+
+func hexNibble(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// bitVectorClearer remembers the records written to bitvector columns, so that
+// their previous bits can be cleared before the new ones are imported.
+type bitVectorClearer struct {
+	fields []string
+	ids    []interface{}
+}
+
+// newBitVectorClearer returns a clearer for the bitvector columns among
+// fields, or nil if there are none.
+func newBitVectorClearer(fields []*pilosa.FieldInfo) *bitVectorClearer {
+	var c *bitVectorClearer
+	for _, fld := range fields {
+		if fld == nil || fld.Options.BitVector == 0 {
+			continue
+		}
+		if c == nil {
+			c = &bitVectorClearer{}
+		}
+		c.fields = append(c.fields, fld.Name)
+	}
+	return c
+}
+
+func (c *bitVectorClearer) addRow(id interface{}) {
+	c.ids = append(c.ids, id)
+}
+
+func (c *bitVectorClearer) clear(ctx context.Context, importer pilosa.Importer, tbl *dax.Table) error {
+	if err := clearRecordFields(ctx, importer, tbl, c.ids, c.fields); err != nil {
+		return errors.Wrap(err, "clearing bitvectors")
+	}
+	c.ids = c.ids[:0]
+	return nil
+}
@@ -4,6 +4,7 @@ package planner
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/featurebasedb/featurebase/v3/dax"
@@ -19,6 +20,7 @@ const (
 	alterOpDrop
 	alterOpRename
 	alterOpSet
+	alterOpAlter
 )
 
 // compileAlterTableStatement compiles an ALTER TABLE statement into a
@@ -51,6 +53,11 @@ func (p *ExecutionPlanner) compileAlterTableStatement(ctx context.Context, stmt
 			return nil, sql3.NewErrColumnNotFound(stmt.DropColumnName.NamePos.Line, stmt.DropColumnName.NamePos.Column, columnName)
 		}
 
+		// the primary key is made from this column
+		if tbl.PrimaryKeyPosition(dax.FieldName(columnName)) >= 0 {
+			return nil, sql3.NewErrPrimaryKeyColumnChange(stmt.DropColumnName.NamePos.Line, stmt.DropColumnName.NamePos.Column, columnName)
+		}
+
 		return NewPlanOpQuery(p, NewPlanOpAlterTable(p, tableName, alterOpDrop, columnName, "", nil), p.sql), nil
 	} else if stmt.Add.IsValid() {
 		col := stmt.ColumnDef
@@ -72,7 +79,51 @@ func (p *ExecutionPlanner) compileAlterTableStatement(ctx context.Context, stmt
 	} else if stmt.Rename.IsValid() {
 		oldColumnName := strings.ToLower(parser.IdentName(stmt.OldColumnName))
 		newColumnName := strings.ToLower(parser.IdentName(stmt.NewColumnName))
+
+		// the old column must exist and the new one must not
+		found := false
+		for _, f := range tbl.Fields {
+			if strings.EqualFold(string(f.Name), oldColumnName) {
+				found = true
+			}
+			if strings.EqualFold(string(f.Name), newColumnName) {
+				return nil, sql3.NewErrDuplicateColumn(stmt.NewColumnName.NamePos.Line, stmt.NewColumnName.NamePos.Column, newColumnName)
+			}
+		}
+		if !found {
+			return nil, sql3.NewErrColumnNotFound(stmt.OldColumnName.NamePos.Line, stmt.OldColumnName.NamePos.Column, oldColumnName)
+		}
+		if tbl.PrimaryKeyPosition(dax.FieldName(oldColumnName)) >= 0 {
+			return nil, sql3.NewErrPrimaryKeyColumnChange(stmt.OldColumnName.NamePos.Line, stmt.OldColumnName.NamePos.Column, oldColumnName)
+		}
+
 		return NewPlanOpQuery(p, NewPlanOpAlterTable(p, tableName, alterOpRename, oldColumnName, newColumnName, nil), p.sql), nil
+	} else if stmt.AlterCol.IsValid() {
+		col := stmt.ColumnDef
+		columnName := strings.ToLower(parser.IdentName(col.Name))
+
+		// does this column exist
+		found := false
+		for _, f := range tbl.Fields {
+			if strings.EqualFold(string(f.Name), columnName) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, sql3.NewErrColumnNotFound(col.Name.NamePos.Line, col.Name.NamePos.Column, columnName)
+		}
+		if tbl.PrimaryKeyPosition(dax.FieldName(columnName)) >= 0 {
+			return nil, sql3.NewErrPrimaryKeyColumnChange(col.Name.NamePos.Line, col.Name.NamePos.Column, columnName)
+		}
+
+		column, err := p.compileColumn(ctx, col)
+		if err != nil {
+			return nil, err
+		}
+		alter := NewPlanOpAlterTable(p, tableName, alterOpAlter, columnName, columnName, column)
+		alter.AddWarning(fmt.Sprintf("column '%s' is rewritten in the background; progress is reported in %s", columnName, fbColumnAlterations))
+		return NewPlanOpQuery(p, alter, p.sql), nil
 	} else {
 		return nil, sql3.NewErrInternal("unhandled alter operation")
 	}
@@ -102,12 +153,40 @@ func (p *ExecutionPlanner) analyzeAlterTableStatement(stmt *parser.AlterTableSta
 			return err
 		}
 	} else if stmt.Rename.IsValid() {
+		if p.fieldAlterer == nil {
+			return sql3.NewErrUnsupported(stmt.Rename.Line, stmt.Rename.Column, false, "RENAME COLUMN")
+		}
 		//check the new and old are not the same
 		oldColumnName := strings.ToLower(parser.IdentName(stmt.OldColumnName))
 		newColumnName := strings.ToLower(parser.IdentName(stmt.NewColumnName))
 		if strings.EqualFold(oldColumnName, newColumnName) {
 			return sql3.NewErrDuplicateColumn(stmt.NewColumnName.NamePos.Line, stmt.NewColumnName.NamePos.Column, newColumnName)
 		}
+		if oldColumnName == string(dax.PrimaryKeyFieldName) {
+			return sql3.NewErrTableIDColumnChange(stmt.OldColumnName.NamePos.Line, stmt.OldColumnName.NamePos.Column)
+		}
+		if newColumnName == string(dax.PrimaryKeyFieldName) {
+			return sql3.NewErrTableIDColumnChange(stmt.NewColumnName.NamePos.Line, stmt.NewColumnName.NamePos.Column)
+		}
+	} else if stmt.AlterCol.IsValid() {
+		if p.fieldAlterer == nil {
+			return sql3.NewErrUnsupported(stmt.AlterCol.Line, stmt.AlterCol.Column, false, "ALTER COLUMN")
+		}
+		col := stmt.ColumnDef
+		columnName := strings.ToLower(parser.IdentName(col.Name))
+		typeName := parser.IdentName(col.Type.Name)
+		if !parser.IsValidTypeName(typeName) {
+			return sql3.NewErrUnknownType(col.Type.Name.NamePos.Line, col.Type.Name.NamePos.Column, typeName)
+		}
+
+		if columnName == string(dax.PrimaryKeyFieldName) {
+			return sql3.NewErrTableIDColumnChange(col.Name.NamePos.Line, col.Name.NamePos.Column)
+		}
+
+		err := p.analyzeColumn(typeName, col)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
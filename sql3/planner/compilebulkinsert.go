@@ -37,7 +37,9 @@ func (p *ExecutionPlanner) compileBulkInsertStatement(ctx context.Context, stmt
 	}
 
 	// create an options
-	options := &bulkInsertOptions{}
+	options := &bulkInsertOptions{
+		replace: stmt.Replace.IsValid(),
+	}
 
 	// data source
 	sliteral, sok := stmt.DataSource.(*parser.StringLit)
@@ -119,6 +121,9 @@ func (p *ExecutionPlanner) compileBulkInsertStatement(ctx context.Context, stmt
 			}
 		}
 	}
+	// constrained columns missing from the list are inserted as NULL
+	options.targetColumns = append(options.targetColumns, omittedConstrainedColumns(tableName, tbl, options.targetColumns)...)
+	options.primaryKey = tbl.PrimaryKey
 
 	// build the map expressions
 	options.mapExpressions = make([]*bulkInsertMapColumn, 0)
@@ -384,9 +389,25 @@ func (p *ExecutionPlanner) analyzeBulkInsertStatement(ctx context.Context, stmt
 		columnNameMap[colName] = struct{}{}
 
 		if strings.EqualFold(cm.Name, string(dax.PrimaryKeyFieldName)) {
+			// the _id of a table with a primary key is made from the
+			// primary key columns
+			if len(tbl.PrimaryKey) > 0 {
+				return sql3.NewErrInsertPrimaryKeyID(cm.NamePos.Line, cm.NamePos.Column, tableName)
+			}
 			foundID = true
 		}
 	}
+
+	if len(tbl.PrimaryKey) > 0 {
+		// check we have all of the primary key columns
+		for _, name := range tbl.PrimaryKey {
+			if _, ok := columnNameMap[string(name)]; !ok {
+				return sql3.NewErrInsertMissingPrimaryKeyColumn(stmt.ColumnsLparen.Line, stmt.ColumnsLparen.Column, string(name))
+			}
+		}
+		return nil
+	}
+
 	if !foundID {
 		return sql3.NewErrInsertMustHaveIDColumn(stmt.ColumnsRparen.Line, stmt.ColumnsRparen.Column)
 	}
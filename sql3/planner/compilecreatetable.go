@@ -4,6 +4,7 @@ package planner
 
 import (
 	"context"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -32,6 +33,7 @@ func (p *ExecutionPlanner) compileCreateTableStatement(ctx context.Context, stmt
 	// apply table options
 	keyPartitions := 0
 	description := ""
+	var retention *tableRetention
 	for _, option := range stmt.Options {
 		switch o := option.(type) {
 		case *parser.KeyPartitionsOption:
@@ -44,6 +46,25 @@ func (p *ExecutionPlanner) compileCreateTableStatement(ctx context.Context, stmt
 		case *parser.CommentOption:
 			e := o.Expr.(*parser.StringLit)
 			description = e.Value
+		case *parser.RetentionOption:
+			e := o.Expr.(*parser.StringLit)
+			period, err := time.ParseDuration(e.Value)
+			if err != nil {
+				return nil, err
+			}
+			retention = &tableRetention{
+				column: strings.ToLower(parser.IdentName(o.Column)),
+				period: period,
+			}
+		}
+	}
+
+	var primaryKey []dax.FieldName
+	for _, con := range stmt.Constraints {
+		if c, ok := con.(*parser.PrimaryKeyConstraint); ok {
+			for _, ident := range c.Columns {
+				primaryKey = append(primaryKey, dax.FieldName(strings.ToLower(parser.IdentName(ident))))
+			}
 		}
 	}
 
@@ -68,7 +89,7 @@ func (p *ExecutionPlanner) compileCreateTableStatement(ctx context.Context, stmt
 
 		columns = append(columns, column)
 	}
-	cop := NewPlanOpCreateTable(p, tableName, failIfExists, isKeyed, keyPartitions, description, columns)
+	cop := NewPlanOpCreateTable(p, tableName, failIfExists, isKeyed, keyPartitions, description, retention, primaryKey, columns)
 	if keyPartitions > 0 {
 		cop.AddWarning("The value of KEYPARTITIONS is currently ignored")
 	}
@@ -98,6 +119,10 @@ func (p *ExecutionPlanner) compileColumn(ctx context.Context, col *parser.Column
 	var timeUnit string = pilosa.TimeUnitSeconds
 	var timeQuantum pilosa.TimeQuantum
 	var ttl = "0"
+	var jsonPaths []string
+	// The NOT NULL, DEFAULT and CHECK constraints are applied after the
+	// options for the column type.
+	var constraintFos []pilosa.FieldOption
 
 	for _, con := range col.Constraints {
 		switch c := con.(type) {
@@ -179,12 +204,40 @@ func (p *ExecutionPlanner) compileColumn(ctx context.Context, col *parser.Column
 				ttl = e.Value
 			}
 
+		case *parser.IndexPathsConstraint:
+			for _, e := range c.Exprs {
+				jsonPaths = append(jsonPaths, e.Value)
+			}
+
+		case *parser.NotNullConstraint:
+			constraintFos = append(constraintFos, pilosa.OptFieldNotNull())
+
+		case *parser.DefaultConstraint:
+			expr := c.Expr.String()
+			if c.Lparen.IsValid() {
+				expr = "(" + expr + ")"
+			}
+			constraintFos = append(constraintFos, pilosa.OptFieldDefault(expr))
+
+		case *parser.CheckConstraint:
+			constraintFos = append(constraintFos, pilosa.OptFieldCheck(c.Expr.String()))
+
 		default:
 			return nil, sql3.NewErrInternalf("unhandled column constraint type '%T'", c)
 		}
 	}
 
 	switch strings.ToLower(typeName) {
+	case dax.BaseTypeBitVector:
+		width, err := bitVectorWidth(col.Type)
+		if err != nil {
+			return nil, err
+		}
+		// each bit is a row, and vectors are read whole, so a ranked cache
+		// is of no use
+		column.fos = append(column.fos, pilosa.OptFieldTypeSet(pilosa.CacheTypeNone, 0))
+		column.fos = append(column.fos, pilosa.OptFieldBitVector(width))
+
 	case dax.BaseTypeBool:
 		column.fos = append(column.fos, pilosa.OptFieldTypeBool())
 
@@ -207,6 +260,17 @@ func (p *ExecutionPlanner) compileColumn(ctx context.Context, col *parser.Column
 			min = scaledMin
 		}
 		column.fos = append(column.fos, pilosa.OptFieldTypeDecimal(scale, min, max))
+		if timeQuantum != "" {
+			column.fos = append(column.fos, pilosa.OptFieldTimeQuantum(timeQuantum, ttl))
+		}
+
+	case dax.BaseTypeDouble:
+		column.fos = append(column.fos, pilosa.OptFieldTypeFloat())
+
+	case dax.BaseTypeGeoPoint:
+		// the packed latitude and longitude use the whole int range
+		column.fos = append(column.fos, pilosa.OptFieldTypeInt(math.MinInt64, math.MaxInt64))
+		column.fos = append(column.fos, pilosa.OptFieldGeoPoint())
 
 	case dax.BaseTypeID:
 		column.fos = append(column.fos, pilosa.OptFieldTypeMutex(cacheType, cacheSize))
@@ -219,6 +283,14 @@ func (p *ExecutionPlanner) compileColumn(ctx context.Context, col *parser.Column
 
 	case dax.BaseTypeInt:
 		column.fos = append(column.fos, pilosa.OptFieldTypeInt(min.ToInt64(0), max.ToInt64(0)))
+		if timeQuantum != "" {
+			column.fos = append(column.fos, pilosa.OptFieldTimeQuantum(timeQuantum, ttl))
+		}
+
+	case dax.BaseTypeJSON:
+		// rows hold the bytes of documents, so a ranked cache is of no use
+		column.fos = append(column.fos, pilosa.OptFieldTypeSet(pilosa.CacheTypeNone, 0))
+		column.fos = append(column.fos, pilosa.OptFieldJSON(jsonPaths...))
 
 	case dax.BaseTypeString:
 		column.fos = append(column.fos, pilosa.OptFieldTypeMutex(cacheType, cacheSize))
@@ -236,6 +308,22 @@ func (p *ExecutionPlanner) compileColumn(ctx context.Context, col *parser.Column
 		column.fos = append(column.fos, pilosa.OptFieldTypeTimestamp(epoch, timeUnit))
 
 	}
+
+	if len(constraintFos) > 0 {
+		column.fos = append(column.fos, constraintFos...)
+
+		// Make sure the DEFAULT value fits the column, and that the CHECK
+		// can be evaluated against it.
+		var fo pilosa.FieldOptions
+		for _, opt := range column.fos {
+			if err := opt(&fo); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := pilosa.NewFieldConstraints(columnName, fo); err != nil {
+			return nil, sql3.NewErrInvalidColumnConstraint(col.Name.NamePos.Line, col.Name.NamePos.Column, err.Error())
+		}
+	}
 	return column, nil
 }
 
@@ -303,11 +391,84 @@ func (p *ExecutionPlanner) analyzeCreateTableStatement(stmt *parser.CreateTableS
 				return sql3.NewErrStringLiteral(o.Expr.Pos().Line, o.Expr.Pos().Column)
 			}
 
+		case *parser.RetentionOption:
+			columnName := strings.ToLower(parser.IdentName(o.Column))
+			var col *parser.ColumnDefinition
+			for _, c := range stmt.Columns {
+				if strings.ToLower(parser.IdentName(c.Name)) == columnName {
+					col = c
+					break
+				}
+			}
+			if col == nil {
+				return sql3.NewErrColumnNotFound(o.Column.NamePos.Line, o.Column.NamePos.Column, columnName)
+			}
+			if !strings.EqualFold(parser.IdentName(col.Type.Name), dax.BaseTypeTimestamp) {
+				return sql3.NewErrInvalidRetentionColumn(o.Column.NamePos.Line, o.Column.NamePos.Column, columnName)
+			}
+			literal, ok := o.Expr.(*parser.StringLit)
+			if !ok {
+				return sql3.NewErrStringLiteral(o.Expr.Pos().Line, o.Expr.Pos().Column)
+			}
+			period, err := time.ParseDuration(literal.Value)
+			if err != nil || period <= 0 {
+				return sql3.NewErrInvalidDuration(o.Expr.Pos().Line, o.Expr.Pos().Column, literal.Value)
+			}
+
 		default:
 			return sql3.NewErrInternalf("unhandled table option type '%T'", option)
 		}
 	}
 
+	//check table constraints
+	for i, con := range stmt.Constraints {
+		switch c := con.(type) {
+		case *parser.PrimaryKeyConstraint:
+			if i > 0 {
+				return sql3.NewErrUnsupported(c.Primary.Line, c.Primary.Column, false, "multiple primary keys")
+			}
+			if err := analyzePrimaryKeyConstraint(stmt, c); err != nil {
+				return err
+			}
+
+		default:
+			return sql3.NewErrInternalf("unhandled table constraint type '%T'", con)
+		}
+	}
+
+	return nil
+}
+
+// analyzePrimaryKeyConstraint checks the columns of a table's composite
+// primary key. The key parts are written to their columns as well as making
+// up the table's string _id.
+func analyzePrimaryKeyConstraint(stmt *parser.CreateTableStatement, c *parser.PrimaryKeyConstraint) error {
+	columns := make(map[string]*parser.ColumnDefinition, len(stmt.Columns))
+	for _, col := range stmt.Columns {
+		columns[strings.ToLower(parser.IdentName(col.Name))] = col
+	}
+	if id := columns[string(dax.PrimaryKeyFieldName)]; id != nil && !strings.EqualFold(parser.IdentName(id.Type.Name), dax.BaseTypeString) {
+		return sql3.NewErrPrimaryKeyIDColumnType(id.Type.Name.NamePos.Line, id.Type.Name.NamePos.Column)
+	}
+
+	seen := make(map[string]struct{}, len(c.Columns))
+	for _, ident := range c.Columns {
+		columnName := strings.ToLower(parser.IdentName(ident))
+		if _, ok := seen[columnName]; ok {
+			return sql3.NewErrDuplicateColumn(ident.NamePos.Line, ident.NamePos.Column, columnName)
+		}
+		seen[columnName] = struct{}{}
+
+		col, ok := columns[columnName]
+		if !ok || columnName == string(dax.PrimaryKeyFieldName) {
+			return sql3.NewErrColumnNotFound(ident.NamePos.Line, ident.NamePos.Column, columnName)
+		}
+		switch strings.ToLower(parser.IdentName(col.Type.Name)) {
+		case dax.BaseTypeID, dax.BaseTypeInt, dax.BaseTypeString:
+		default:
+			return sql3.NewErrInvalidPrimaryKeyColumn(ident.NamePos.Line, ident.NamePos.Column, columnName)
+		}
+	}
 	return nil
 }
 
@@ -344,6 +505,11 @@ func (p *ExecutionPlanner) analyzeColumn(typeName string, col *parser.ColumnDefi
 			handledConstraints[parser.CACHETYPE] = struct{}{}
 
 		case *parser.MinConstraint:
+			// doubles and geopoints always cover the full range, and
+			// bitvectors have none
+			if strings.EqualFold(typeName, dax.BaseTypeDouble) || strings.EqualFold(typeName, dax.BaseTypeGeoPoint) || strings.EqualFold(typeName, dax.BaseTypeBitVector) {
+				return sql3.NewErrBadColumnConstraint(col.Name.NamePos.Line, col.Name.NamePos.Column, "MIN", typeName)
+			}
 			// Make sure we have either an integer or unary type.
 			switch c.Expr.(type) {
 			case *parser.IntegerLit, *parser.UnaryExpr:
@@ -354,6 +520,11 @@ func (p *ExecutionPlanner) analyzeColumn(typeName string, col *parser.ColumnDefi
 			handledConstraints[parser.MIN] = struct{}{}
 
 		case *parser.MaxConstraint:
+			// doubles and geopoints always cover the full range, and
+			// bitvectors have none
+			if strings.EqualFold(typeName, dax.BaseTypeDouble) || strings.EqualFold(typeName, dax.BaseTypeGeoPoint) || strings.EqualFold(typeName, dax.BaseTypeBitVector) {
+				return sql3.NewErrBadColumnConstraint(col.Name.NamePos.Line, col.Name.NamePos.Column, "MAX", typeName)
+			}
 			// Make sure we have either an integer or unary type.
 			switch c.Expr.(type) {
 			case *parser.IntegerLit, *parser.UnaryExpr:
@@ -379,8 +550,10 @@ func (p *ExecutionPlanner) analyzeColumn(typeName string, col *parser.ColumnDefi
 			handledConstraints[parser.TIMEUNIT] = struct{}{}
 
 		case *parser.TimeQuantumConstraint:
-			//make sure we have one of the time quantum types
-			if !(strings.EqualFold(typeName, dax.BaseTypeStringSetQ) || strings.EqualFold(typeName, dax.BaseTypeIDSetQ)) {
+			//make sure we have one of the time quantum types, or an int or
+			//decimal, whose values can also be kept per time quantum
+			if !(strings.EqualFold(typeName, dax.BaseTypeStringSetQ) || strings.EqualFold(typeName, dax.BaseTypeIDSetQ) ||
+				strings.EqualFold(typeName, dax.BaseTypeInt) || strings.EqualFold(typeName, dax.BaseTypeDecimal)) {
 				return sql3.NewErrBadColumnConstraint(col.Name.NamePos.Line, col.Name.NamePos.Column, "TIMEQUANTUM", typeName)
 			}
 			//check the type of the expression
@@ -412,9 +585,56 @@ func (p *ExecutionPlanner) analyzeColumn(typeName string, col *parser.ColumnDefi
 
 			handledConstraints[parser.TIMEQUANTUM] = struct{}{}
 
+		case *parser.IndexPathsConstraint:
+			//make sure we have a json type
+			if !strings.EqualFold(typeName, dax.BaseTypeJSON) {
+				return sql3.NewErrBadColumnConstraint(col.Name.NamePos.Line, col.Name.NamePos.Column, "INDEX PATHS", typeName)
+			}
+			// each path must compile, and must map to a distinct field
+			columnName := strings.ToLower(parser.IdentName(col.Name))
+			fieldNames := make(map[string]struct{})
+			for _, e := range c.Exprs {
+				if _, err := compileJSONPath(e.Value); err != nil {
+					return sql3.NewErrInvalidJSONPath(e.ValuePos.Line, e.ValuePos.Column, e.Value)
+				}
+				name := pilosa.JSONPathFieldName(columnName, e.Value)
+				if _, ok := fieldNames[name]; ok {
+					return sql3.NewErrDuplicateColumn(e.ValuePos.Line, e.ValuePos.Column, name)
+				}
+				fieldNames[name] = struct{}{}
+			}
+			handledConstraints[parser.INDEX] = struct{}{}
+
+		case *parser.NotNullConstraint:
+			handledConstraints[parser.NOT] = struct{}{}
+
+		case *parser.DefaultConstraint:
+			if !typeTakesValueConstraints(typeName) {
+				return sql3.NewErrBadColumnConstraint(col.Name.NamePos.Line, col.Name.NamePos.Column, "DEFAULT", typeName)
+			}
+			handledConstraints[parser.DEFAULT] = struct{}{}
+
+		case *parser.CheckConstraint:
+			if !typeTakesValueConstraints(typeName) {
+				return sql3.NewErrBadColumnConstraint(col.Name.NamePos.Line, col.Name.NamePos.Column, "CHECK", typeName)
+			}
+			handledConstraints[parser.CHECK] = struct{}{}
+
 		default:
 			return sql3.NewErrInternalf("unhandled column constraint type '%T'", c)
 		}
 	}
 	return nil
 }
+
+// typeTakesValueConstraints returns true if columns of the type named
+// typeName hold a single scalar value, which DEFAULT and CHECK constraints
+// can apply to.
+func typeTakesValueConstraints(typeName string) bool {
+	switch strings.ToLower(typeName) {
+	case dax.BaseTypeBool, dax.BaseTypeDecimal, dax.BaseTypeDouble, dax.BaseTypeID,
+		dax.BaseTypeInt, dax.BaseTypeString, dax.BaseTypeTimestamp:
+		return true
+	}
+	return false
+}
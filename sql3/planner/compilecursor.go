@@ -0,0 +1,100 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	fbcontext "github.com/featurebasedb/featurebase/v3/context"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// compileDeclareCursorStatement compiles a DECLARE CURSOR statement into a
+// PlanOperator. The select is compiled (and optimized) now, but is not
+// executed until the cursor is declared. Cursors belong to the user declaring
+// them, so their names only need to be unique for that user.
+func (p *ExecutionPlanner) compileDeclareCursorStatement(ctx context.Context, stmt *parser.DeclareCursorStatement) (types.PlanOperator, error) {
+	cursorName := strings.ToLower(parser.IdentName(stmt.Name))
+	userID, _ := fbcontext.UserID(ctx)
+	if _, err := p.systemLayerAPI.Cursors().GetCursor(ctx, userID, cursorName); err == nil {
+		return nil, sql3.NewErrCursorExists(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, cursorName)
+	}
+
+	query, err := p.compileSelectStatement(stmt.Select, false)
+	if err != nil {
+		return nil, err
+	}
+	query, err = p.optimizePlan(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPlanOpQuery(p, NewPlanOpDeclareCursor(p, cursorName, stmt.Select.String(), query), p.sql), nil
+}
+
+// compileFetchStatement compiles a FETCH statement into a PlanOperator.
+func (p *ExecutionPlanner) compileFetchStatement(ctx context.Context, stmt *parser.FetchStatement) (types.PlanOperator, error) {
+	cursorName := strings.ToLower(parser.IdentName(stmt.Name))
+	userID, _ := fbcontext.UserID(ctx)
+	cursor, err := p.systemLayerAPI.Cursors().GetCursor(ctx, userID, cursorName)
+	if err != nil {
+		return nil, sql3.NewErrCursorNotFound(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, cursorName)
+	}
+
+	count := int64(1)
+	if stmt.CountExpr != nil {
+		// analyzer should have ensured this is an integer literal
+		lit, ok := stmt.CountExpr.(*parser.IntegerLit)
+		if !ok {
+			return nil, sql3.NewErrIntegerLiteral(stmt.CountExpr.Pos().Line, stmt.CountExpr.Pos().Column)
+		}
+		count, err = strconv.ParseInt(lit.Value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewPlanOpQuery(p, NewPlanOpFetch(p, cursorName, count, cursor.Schema), p.sql), nil
+}
+
+// compileCloseCursorStatement compiles a CLOSE statement into a PlanOperator.
+func (p *ExecutionPlanner) compileCloseCursorStatement(ctx context.Context, stmt *parser.CloseCursorStatement) (types.PlanOperator, error) {
+	cursorName := strings.ToLower(parser.IdentName(stmt.Name))
+	userID, _ := fbcontext.UserID(ctx)
+	if _, err := p.systemLayerAPI.Cursors().GetCursor(ctx, userID, cursorName); err != nil {
+		return nil, sql3.NewErrCursorNotFound(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, cursorName)
+	}
+
+	return NewPlanOpQuery(p, NewPlanOpCloseCursor(p, cursorName), p.sql), nil
+}
+
+// analyzeDeclareCursorStatement analyzes the select of a DECLARE CURSOR
+// statement.
+func (p *ExecutionPlanner) analyzeDeclareCursorStatement(ctx context.Context, stmt *parser.DeclareCursorStatement) error {
+	_, err := p.analyzeSelectStatement(ctx, stmt.Select)
+	return err
+}
+
+// analyzeFetchStatement analyzes a FETCH statement, ensuring the number of
+// rows to fetch is a positive integer literal.
+func (p *ExecutionPlanner) analyzeFetchStatement(ctx context.Context, stmt *parser.FetchStatement) error {
+	if stmt.CountExpr == nil {
+		return nil
+	}
+	lit, ok := stmt.CountExpr.(*parser.IntegerLit)
+	if !ok {
+		return sql3.NewErrIntegerLiteral(stmt.CountExpr.Pos().Line, stmt.CountExpr.Pos().Column)
+	}
+	i, err := strconv.ParseInt(lit.Value, 10, 64)
+	if err != nil {
+		return err
+	}
+	if i < 1 {
+		return sql3.NewErrIntegerLiteral(stmt.CountExpr.Pos().Line, stmt.CountExpr.Pos().Column)
+	}
+	return nil
+}
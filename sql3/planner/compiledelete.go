@@ -30,8 +30,9 @@ func (p *ExecutionPlanner) compileDeleteStatement(stmt *parser.DeleteStatement)
 
 	_, sourceIsScan := source.(*PlanOpPQLTableScan)
 
-	// no where clause and source is a scan so it's a truncate
-	if where == nil && sourceIsScan {
+	// no where clause and source is a scan so it's a truncate (unless we're
+	// in a transaction, where we stage the deletion of every record instead)
+	if where == nil && sourceIsScan && p.staging == nil {
 		delOp := NewPlanOpPQLTruncateTable(p, string(tableName))
 
 		children := []types.PlanOperator{
@@ -40,11 +41,14 @@ func (p *ExecutionPlanner) compileDeleteStatement(stmt *parser.DeleteStatement)
 		return query.WithChildren(children...)
 	}
 
-	var delOp types.PlanOperator
-
 	// if we did have a where, insert the filter op
 	if where != nil {
-		delOp = NewPlanOpPQLConstRowDelete(p, string(tableName), NewPlanOpFilter(p, where, source))
+		source = NewPlanOpFilter(p, where, source)
+	}
+
+	var delOp types.PlanOperator
+	if p.staging != nil {
+		delOp = NewPlanOpTransactionDelete(p, string(tableName), source)
 	} else {
 		delOp = NewPlanOpPQLConstRowDelete(p, string(tableName), source)
 	}
@@ -14,13 +14,24 @@ import (
 // compileDropViewStatement compiles a DROP VIEW statement into a PlanOperator.
 func (p *ExecutionPlanner) compileDropViewStatement(ctx context.Context, stmt *parser.DropViewStatement) (_ types.PlanOperator, err error) {
 	viewName := strings.ToLower(parser.IdentName(stmt.Name))
-	v, err := p.getViewByName(ctx, viewName)
+	materialized := stmt.Materialized.IsValid()
+
+	// DROP VIEW drops either kind of view, DROP MATERIALIZED VIEW only drops
+	// materialized ones
+	var v *viewSystemObject
+	if !materialized {
+		v, err = p.getViewByName(ctx, viewName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	mv, err := p.getMaterializedViewByName(ctx, viewName)
 	if err != nil {
 		return nil, err
 	}
-	if v == nil && !stmt.IfExists.IsValid() {
+	if v == nil && mv == nil && !stmt.IfExists.IsValid() {
 		return nil, sql3.NewErrViewNotFound(0, 0, viewName)
 	}
 
-	return NewPlanOpQuery(p, NewPlanOpDropView(p, stmt.IfExists.IsValid(), viewName), p.sql), nil
+	return NewPlanOpQuery(p, NewPlanOpDropView(p, stmt.IfExists.IsValid(), materialized, viewName), p.sql), nil
 }
@@ -47,13 +47,23 @@ func (p *ExecutionPlanner) compileInsertStatement(ctx context.Context, stmt *par
 		}
 	} else {
 		for idx, field := range tbl.Fields {
-			if strings.EqualFold("_exists", string(field.Name)) {
+			if !isImplicitInsertColumn(tbl, field) {
 				continue
 			}
 			targetColumns = append(targetColumns, newQualifiedRefPlanExpression(tableName, string(field.Name), idx, fieldSQLDataType(pilosa.FieldToFieldInfo(field))))
 		}
 	}
 
+	// Columns with a NOT NULL or DEFAULT constraint which have been left out
+	// of the column list are inserted as NULL, so that the constraint is
+	// applied to them.
+	var omitted int
+	if len(stmt.Columns) > 0 {
+		constrained := omittedConstrainedColumns(tableName, tbl, targetColumns)
+		targetColumns = append(targetColumns, constrained...)
+		omitted = len(constrained)
+	}
+
 	//add expressions from values list
 	for _, tuple := range stmt.TupleList {
 		tupleValues := []types.PlanExpression{}
@@ -64,10 +74,48 @@ func (p *ExecutionPlanner) compileInsertStatement(ctx context.Context, stmt *par
 			}
 			tupleValues = append(tupleValues, e)
 		}
+		for i := 0; i < omitted; i++ {
+			tupleValues = append(tupleValues, newNullLiteralPlanExpression())
+		}
 		insertValues = append(insertValues, tupleValues)
 	}
 
-	return NewPlanOpQuery(p, NewPlanOpInsert(p, tableName, targetColumns, insertValues), p.sql), nil
+	// The _id of a table with a primary key is made from the values of the
+	// primary key columns.
+	if len(tbl.PrimaryKey) > 0 {
+		targetColumns, insertValues, err = addCompositeKeyColumn(tableName, tbl.PrimaryKey, targetColumns, insertValues)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var conflict *insertConflict
+	if stmt.Replace.IsValid() {
+		conflict = &insertConflict{replace: true}
+	} else if uc := stmt.UpsertClause; uc != nil {
+		conflict = &insertConflict{doNothing: uc.DoNothing.IsValid()}
+		for _, assignment := range uc.Assignments {
+			colName := strings.ToLower(parser.IdentName(assignment.Columns[0]))
+			for idx, field := range tbl.Fields {
+				if !strings.EqualFold(colName, string(field.Name)) {
+					continue
+				}
+				conflict.updateColumns = append(conflict.updateColumns, newQualifiedRefPlanExpression(tableName, colName, idx, fieldSQLDataType(pilosa.FieldToFieldInfo(field))))
+				switch field.Type {
+				case dax.BaseTypeIDSet, dax.BaseTypeIDSetQ, dax.BaseTypeStringSet, dax.BaseTypeStringSetQ:
+					conflict.clearFields = append(conflict.clearFields, string(field.Name))
+				}
+				break
+			}
+			e, err := p.compileExpr(assignment.Expr)
+			if err != nil {
+				return nil, err
+			}
+			conflict.updateValues = append(conflict.updateValues, e)
+		}
+	}
+
+	return NewPlanOpQuery(p, NewPlanOpInsert(p, tableName, targetColumns, insertValues, conflict), p.sql), nil
 }
 
 // analyzeInsertStatement analyzes an INSERT statement and returns and error if
@@ -91,7 +139,7 @@ func (p *ExecutionPlanner) analyzeInsertStatement(ctx context.Context, stmt *par
 	if len(stmt.Columns) == 0 {
 		// Generate the list of types from the FeatureBase index.
 		for _, field := range tbl.Fields {
-			if strings.EqualFold("_exists", string(field.Name)) {
+			if !isImplicitInsertColumn(tbl, field) {
 				continue
 			}
 			typeNames = append(typeNames, fieldSQLDataType(pilosa.FieldToFieldInfo(field)))
@@ -112,6 +160,10 @@ func (p *ExecutionPlanner) analyzeInsertStatement(ctx context.Context, stmt *par
 			var typeName parser.ExprDataType
 
 			if strings.EqualFold(colName, string(dax.PrimaryKeyFieldName)) {
+				// The _id of a table with a primary key can't be given.
+				if len(tbl.PrimaryKey) > 0 {
+					return sql3.NewErrInsertPrimaryKeyID(columnIdent.NamePos.Line, columnIdent.NamePos.Column, tableName)
+				}
 				columnNameMap[string(dax.PrimaryKeyFieldName)] = struct{}{}
 
 				// Determine, from the existing table, whether the _id is of
@@ -150,14 +202,24 @@ func (p *ExecutionPlanner) analyzeInsertStatement(ctx context.Context, stmt *par
 			columnNameMap[colName] = struct{}{}
 		}
 
-		// Ensure we have an _id column.
-		if _, ok := columnNameMap[string(dax.PrimaryKeyFieldName)]; !ok {
-			return sql3.NewErrInsertMustHaveIDColumn(stmt.ColumnsLparen.Line, stmt.ColumnsLparen.Column)
-		}
+		if len(tbl.PrimaryKey) > 0 {
+			// Ensure we have all of the primary key columns, which the _id
+			// is made from.
+			for _, name := range tbl.PrimaryKey {
+				if _, ok := columnNameMap[string(name)]; !ok {
+					return sql3.NewErrInsertMissingPrimaryKeyColumn(stmt.ColumnsLparen.Line, stmt.ColumnsLparen.Column, string(name))
+				}
+			}
+		} else {
+			// Ensure we have an _id column.
+			if _, ok := columnNameMap[string(dax.PrimaryKeyFieldName)]; !ok {
+				return sql3.NewErrInsertMustHaveIDColumn(stmt.ColumnsLparen.Line, stmt.ColumnsLparen.Column)
+			}
 
-		// Ensure we have at least one more than just the _id column.
-		if len(stmt.Columns) < 2 {
-			return sql3.NewErrInsertMustAtLeastOneNonIDColumn(stmt.ColumnsLparen.Line, stmt.ColumnsLparen.Column)
+			// Ensure we have at least one more than just the _id column.
+			if len(stmt.Columns) < 2 {
+				return sql3.NewErrInsertMustAtLeastOneNonIDColumn(stmt.ColumnsLparen.Line, stmt.ColumnsLparen.Column)
+			}
 		}
 
 		// Make sure insert list and expression list have the same number of items.
@@ -185,5 +247,141 @@ func (p *ExecutionPlanner) analyzeInsertStatement(ctx context.Context, stmt *par
 		}
 	}
 
+	if stmt.UpsertClause != nil {
+		return p.analyzeUpsertClause(ctx, stmt, tbl, typeNames)
+	}
+
+	return nil
+}
+
+// analyzeUpsertClause analyzes the ON CONFLICT clause of an INSERT statement.
+// typeNames are the types of the statement's target columns.
+func (p *ExecutionPlanner) analyzeUpsertClause(ctx context.Context, stmt *parser.InsertStatement, tbl *dax.Table, typeNames []parser.ExprDataType) error {
+	clause := stmt.UpsertClause
+
+	// The only conflict there can be is on the primary key.
+	if len(clause.Columns) > 0 {
+		if len(clause.Columns) != 1 {
+			return sql3.NewErrInsertConflictTarget(clause.Lparen.Line, clause.Lparen.Column)
+		}
+		ident, ok := clause.Columns[0].X.(*parser.Ident)
+		if !ok || !strings.EqualFold(ident.Name, string(dax.PrimaryKeyFieldName)) {
+			return sql3.NewErrInsertConflictTarget(clause.Columns[0].X.Pos().Line, clause.Columns[0].X.Pos().Column)
+		}
+	}
+	if clause.WhereExpr != nil {
+		return sql3.NewErrUnsupported(clause.Where.Line, clause.Where.Column, true, "WHERE in ON CONFLICT")
+	}
+	if clause.UpdateWhereExpr != nil {
+		return sql3.NewErrUnsupported(clause.UpdateWhere.Line, clause.UpdateWhere.Column, true, "WHERE in ON CONFLICT DO UPDATE")
+	}
+
+	// Assignments may refer to the values of the proposed row.
+	scope := &upsertScope{InsertStatement: stmt, columnTypes: typeNames}
+	if len(stmt.Columns) > 0 {
+		for _, col := range stmt.Columns {
+			scope.columnNames = append(scope.columnNames, strings.ToLower(parser.IdentName(col)))
+		}
+	} else {
+		for _, field := range tbl.Fields {
+			if !isImplicitInsertColumn(tbl, field) {
+				continue
+			}
+			scope.columnNames = append(scope.columnNames, string(field.Name))
+		}
+	}
+
+	assigned := make(map[string]struct{})
+	for _, assignment := range clause.Assignments {
+		if len(assignment.Columns) != 1 {
+			return sql3.NewErrUnsupported(assignment.Lparen.Line, assignment.Lparen.Column, false, "column lists in ON CONFLICT DO UPDATE SET")
+		}
+		columnIdent := assignment.Columns[0]
+		colName := strings.ToLower(parser.IdentName(columnIdent))
+		if strings.EqualFold(colName, string(dax.PrimaryKeyFieldName)) {
+			return sql3.NewErrInsertConflictUpdateID(columnIdent.NamePos.Line, columnIdent.NamePos.Column)
+		}
+		if tbl.PrimaryKeyPosition(dax.FieldName(colName)) >= 0 {
+			return sql3.NewErrPrimaryKeyColumnChange(columnIdent.NamePos.Line, columnIdent.NamePos.Column, colName)
+		}
+
+		var typeName parser.ExprDataType
+		for _, field := range tbl.Fields {
+			if strings.EqualFold(colName, string(field.Name)) && !strings.EqualFold("_exists", string(field.Name)) {
+				typeName = fieldSQLDataType(pilosa.FieldToFieldInfo(field))
+				break
+			}
+		}
+		if typeName == nil {
+			return sql3.NewErrColumnNotFound(columnIdent.NamePos.Line, columnIdent.NamePos.Column, colName)
+		}
+		if _, found := assigned[colName]; found {
+			return sql3.NewErrDuplicateColumn(columnIdent.NamePos.Line, columnIdent.NamePos.Column, colName)
+		}
+		assigned[colName] = struct{}{}
+
+		e, err := p.analyzeExpression(ctx, assignment.Expr, scope)
+		if err != nil {
+			return err
+		}
+		if !typesAreAssignmentCompatible(typeName, e.DataType()) {
+			return sql3.NewErrTypeAssignmentIncompatible(assignment.Expr.Pos().Line, assignment.Expr.Pos().Column, e.DataType().TypeDescription(), typeName.TypeDescription())
+		}
+		assignment.Expr = e
+	}
 	return nil
 }
+
+// upsertScope is the scope of the expressions in an ON CONFLICT DO UPDATE
+// clause, which can refer to the row which was proposed for insertion as
+// excluded.<column>.
+type upsertScope struct {
+	*parser.InsertStatement
+
+	// columnNames and columnTypes describe the proposed row.
+	columnNames []string
+	columnTypes []parser.ExprDataType
+}
+
+// column returns the position in the proposed row of the named column, and
+// its type, or -1 if it isn't there.
+func (s *upsertScope) column(name string) (int, parser.ExprDataType) {
+	for i, colName := range s.columnNames {
+		if strings.EqualFold(colName, name) {
+			return i, s.columnTypes[i]
+		}
+	}
+	return -1, nil
+}
+
+// isImplicitInsertColumn returns true if field is one of the columns an
+// INSERT without a column list gives values for: all of them but _exists, and
+// _id if the table has a primary key to make it from.
+func isImplicitInsertColumn(tbl *dax.Table, field *dax.Field) bool {
+	if strings.EqualFold("_exists", string(field.Name)) {
+		return false
+	}
+	return !(field.IsPrimaryKey() && len(tbl.PrimaryKey) > 0)
+}
+
+// omittedConstrainedColumns returns references to the columns of tbl which
+// have a NOT NULL or DEFAULT constraint, but are missing from targetColumns.
+func omittedConstrainedColumns(tableName string, tbl *dax.Table, targetColumns []*qualifiedRefPlanExpression) []*qualifiedRefPlanExpression {
+	var omitted []*qualifiedRefPlanExpression
+	for idx, field := range tbl.Fields {
+		if field.IsPrimaryKey() || !(field.Options.NotNull || field.Options.Default != "") {
+			continue
+		}
+		found := false
+		for _, tc := range targetColumns {
+			if strings.EqualFold(tc.columnName, string(field.Name)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			omitted = append(omitted, newQualifiedRefPlanExpression(tableName, string(field.Name), idx, fieldSQLDataType(pilosa.FieldToFieldInfo(field))))
+		}
+	}
+	return omitted
+}
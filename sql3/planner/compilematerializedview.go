@@ -0,0 +1,345 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// materializedViewQuantumColumn is the column added to the table behind a
+// materialized view which is refreshed incrementally. It holds the start of
+// the time quantum bucket each row was computed from.
+const materializedViewQuantumColumn = "quantum_start"
+
+// materializedViewCountColumn is the column added alongside
+// materializedViewQuantumColumn, holding the number of records in the bucket
+// each row was computed from. A refresh compares these with the number of
+// records in each bucket now to find the buckets written to since.
+const materializedViewCountColumn = "quantum_records"
+
+// materializedViewTableName returns the name of the hidden table holding the
+// contents of the named materialized view.
+func materializedViewTableName(viewName string) string {
+	return "fb_mv_" + viewName
+}
+
+// compileCreateMaterializedViewStatement compiles a CREATE MATERIALIZED VIEW
+// statement into a PlanOperator.
+func (p *ExecutionPlanner) compileCreateMaterializedViewStatement(ctx context.Context, stmt *parser.CreateViewStatement) (types.PlanOperator, error) {
+	viewName := strings.ToLower(parser.IdentName(stmt.Name))
+	mv := &materializedViewSystemObject{
+		name: viewName,
+	}
+
+	if stmt.RefreshInterval != nil {
+		// already validated in analyze
+		interval, _ := time.ParseDuration(stmt.RefreshInterval.Value)
+		mv.refreshInterval = interval
+	}
+
+	// compile select, we need its schema to create the table behind the view
+	op, err := p.compileSelectStatement(stmt.Select, false)
+	if err != nil {
+		return nil, err
+	}
+	mv.statement = stmt.Select.String()
+
+	quantum, err := p.materializedViewQuantum(ctx, stmt.Select)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]*createTableField, 0, len(op.Schema())+1)
+	names := make(map[string]struct{})
+	if quantum != nil {
+		columns = append(columns, &createTableField{
+			planner:  p,
+			name:     materializedViewQuantumColumn,
+			typeName: dax.BaseTypeTimestamp,
+			fos: []pilosa.FieldOption{
+				pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitSeconds),
+			},
+		})
+		columns = append(columns, &createTableField{
+			planner:  p,
+			name:     materializedViewCountColumn,
+			typeName: dax.BaseTypeInt,
+			fos: []pilosa.FieldOption{
+				pilosa.OptFieldTypeInt(0, math.MaxInt64),
+			},
+		})
+		names[materializedViewQuantumColumn] = struct{}{}
+		names[materializedViewCountColumn] = struct{}{}
+	}
+	for i, col := range op.Schema() {
+		name := strings.ToLower(col.ColumnName)
+		if name == "" || name == string(dax.PrimaryKeyFieldName) {
+			// unnamed expressions (and _id, which the table behind the view
+			// has its own of) get a name from their position
+			name = fmt.Sprintf("column%d", i+1)
+		}
+		if _, ok := names[name]; ok {
+			return nil, sql3.NewErrDuplicateColumn(0, 0, name)
+		}
+		names[name] = struct{}{}
+
+		column, err := p.materializedViewColumn(name, col.Type)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+
+	return NewPlanOpQuery(p, NewPlanOpCreateMaterializedView(p, stmt.IfNotExists.IsValid(), mv, columns), p.sql), nil
+}
+
+// materializedViewColumn returns the column used to store values of the
+// given type in the table behind a materialized view.
+func (p *ExecutionPlanner) materializedViewColumn(name string, typ parser.ExprDataType) (*createTableField, error) {
+	column := &createTableField{
+		planner: p,
+		name:    name,
+	}
+	switch t := typ.(type) {
+	case *parser.DataTypeBitVector:
+		column.typeName = dax.BaseTypeBitVector
+		column.fos = append(column.fos, pilosa.OptFieldTypeSet(pilosa.CacheTypeNone, 0))
+		column.fos = append(column.fos, pilosa.OptFieldBitVector(t.Width))
+
+	case *parser.DataTypeBool:
+		column.typeName = dax.BaseTypeBool
+		column.fos = append(column.fos, pilosa.OptFieldTypeBool())
+
+	case *parser.DataTypeDecimal:
+		min, max := pql.MinMax(t.Scale)
+		column.typeName = dax.BaseTypeDecimal
+		column.fos = append(column.fos, pilosa.OptFieldTypeDecimal(t.Scale, min, max))
+
+	case *parser.DataTypeDouble:
+		column.typeName = dax.BaseTypeDouble
+		column.fos = append(column.fos, pilosa.OptFieldTypeFloat())
+
+	case *parser.DataTypeGeoPoint:
+		column.typeName = dax.BaseTypeGeoPoint
+		column.fos = append(column.fos, pilosa.OptFieldTypeInt(math.MinInt64, math.MaxInt64))
+		column.fos = append(column.fos, pilosa.OptFieldGeoPoint())
+
+	case *parser.DataTypeID:
+		column.typeName = dax.BaseTypeID
+		column.fos = append(column.fos, pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize))
+
+	case *parser.DataTypeIDSet, *parser.DataTypeIDSetQuantum:
+		column.typeName = dax.BaseTypeIDSet
+		column.fos = append(column.fos, pilosa.OptFieldTypeSet(pilosa.DefaultCacheType, pilosa.DefaultCacheSize))
+
+	case *parser.DataTypeInt:
+		min, max := pql.MinMax(0)
+		column.typeName = dax.BaseTypeInt
+		column.fos = append(column.fos, pilosa.OptFieldTypeInt(min.ToInt64(0), max.ToInt64(0)))
+
+	case *parser.DataTypeString:
+		column.typeName = dax.BaseTypeString
+		column.fos = append(column.fos, pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize))
+		column.fos = append(column.fos, pilosa.OptFieldKeys())
+
+	case *parser.DataTypeJSON:
+		column.typeName = dax.BaseTypeJSON
+		column.fos = append(column.fos, pilosa.OptFieldTypeSet(pilosa.CacheTypeNone, 0))
+		column.fos = append(column.fos, pilosa.OptFieldJSON())
+
+	case *parser.DataTypeStringSet, *parser.DataTypeStringSetQuantum:
+		column.typeName = dax.BaseTypeStringSet
+		column.fos = append(column.fos, pilosa.OptFieldTypeSet(pilosa.DefaultCacheType, pilosa.DefaultCacheSize))
+		column.fos = append(column.fos, pilosa.OptFieldKeys())
+
+	case *parser.DataTypeTimestamp:
+		column.typeName = dax.BaseTypeTimestamp
+		column.fos = append(column.fos, pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitMilliseconds))
+
+	default:
+		return nil, sql3.NewErrUnsupported(0, 0, false, fmt.Sprintf("materialized view columns of type '%s'", typ.TypeDescription()))
+	}
+	return column, nil
+}
+
+// compileRefreshViewStatement compiles a REFRESH MATERIALIZED VIEW statement
+// into a PlanOperator.
+func (p *ExecutionPlanner) compileRefreshViewStatement(ctx context.Context, stmt *parser.RefreshViewStatement) (types.PlanOperator, error) {
+	viewName := strings.ToLower(parser.IdentName(stmt.Name))
+	return NewPlanOpQuery(p, NewPlanOpRefreshMaterializedView(p, viewName), p.sql), nil
+}
+
+func (p *ExecutionPlanner) analyzeCreateMaterializedViewStatement(ctx context.Context, stmt *parser.CreateViewStatement) error {
+	if stmt.RefreshInterval != nil {
+		interval, err := time.ParseDuration(stmt.RefreshInterval.Value)
+		if err != nil || interval < time.Second {
+			pos := stmt.RefreshInterval.ValuePos
+			return sql3.NewErrInvalidDuration(pos.Line, pos.Column, stmt.RefreshInterval.Value)
+		}
+	}
+	return p.analyzeCreateViewStatement(ctx, stmt)
+}
+
+func (p *ExecutionPlanner) analyzeRefreshViewStatement(ctx context.Context, stmt *parser.RefreshViewStatement) error {
+	viewName := strings.ToLower(parser.IdentName(stmt.Name))
+	mv, err := p.getMaterializedViewByName(ctx, viewName)
+	if err != nil {
+		return err
+	}
+	if mv == nil {
+		return sql3.NewErrViewNotFound(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, viewName)
+	}
+	return nil
+}
+
+// materializedViewQuantum describes how a materialized view can be refreshed
+// one time quantum bucket at a time. This is possible when the view's select
+// is a GROUP BY over a single table, filtered with a RANGEQ() on one of its
+// time quantum columns which has a literal lower bound.
+//
+// Each bucket is computed by narrowing the RANGEQ() to that bucket. A refresh
+// recomputes the buckets from the one the previous refresh ran in onwards,
+// and any earlier bucket whose number of records has changed since it was
+// computed, such as when data arrives late. Changes to records already in an
+// earlier bucket which leave its number of records the same aren't picked up
+// until the view is recreated.
+type materializedViewQuantum struct {
+	// unit is the finest unit of the column's time quantum, which is the
+	// size of each bucket.
+	unit byte
+
+	// from and to are the bounds of the RANGEQ(); to is nil if the range is
+	// open ended.
+	from time.Time
+	to   *time.Time
+}
+
+// truncate returns the start of the bucket containing t.
+func (q *materializedViewQuantum) truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch q.unit {
+	case 'Y':
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	case 'M':
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case 'D':
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	default:
+		return t.Truncate(time.Hour)
+	}
+}
+
+// next returns the start of the bucket after the one starting at t.
+func (q *materializedViewQuantum) next(t time.Time) time.Time {
+	switch q.unit {
+	case 'Y':
+		return t.AddDate(1, 0, 0)
+	case 'M':
+		return t.AddDate(0, 1, 0)
+	case 'D':
+		return t.AddDate(0, 0, 1)
+	default:
+		return t.Add(time.Hour)
+	}
+}
+
+// materializedViewQuantum returns how the materialized view defined by sel
+// can be refreshed incrementally, or nil if it must be refreshed in full.
+func (p *ExecutionPlanner) materializedViewQuantum(ctx context.Context, sel *parser.SelectStatement) (*materializedViewQuantum, error) {
+	if len(sel.GroupByExprs) == 0 || sel.Compound != nil || sel.LimitExpr != nil || sel.TopExpr != nil || sel.OffsetExpr != nil {
+		return nil, nil
+	}
+	source, ok := sel.Source.(*parser.QualifiedTableName)
+	if !ok {
+		return nil, nil
+	}
+	call := findRangeQCall(sel.WhereExpr)
+	if call == nil {
+		return nil, nil
+	}
+
+	var columnName string
+	switch ref := call.Args[0].(type) {
+	case *parser.Ident:
+		columnName = parser.IdentName(ref)
+	case *parser.QualifiedRef:
+		columnName = parser.IdentName(ref.Column)
+	default:
+		return nil, nil
+	}
+
+	tbl, err := p.schemaAPI.TableByName(ctx, dax.TableName(strings.ToLower(parser.IdentName(source.Name))))
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	fld, ok := tbl.Field(dax.FieldName(strings.ToLower(columnName)))
+	if !ok || fld.Options.TimeQuantum == "" {
+		return nil, nil
+	}
+	tq := string(fld.Options.TimeQuantum)
+
+	from := rangeQBound(call.Args[1])
+	if from == nil {
+		return nil, nil
+	}
+	q := &materializedViewQuantum{
+		unit: tq[len(tq)-1],
+		from: *from,
+		to:   rangeQBound(call.Args[2]),
+	}
+	return q, nil
+}
+
+// findRangeQCall returns the RANGEQ() call which is a top level conjunct of
+// expr, or nil if there isn't one.
+func findRangeQCall(expr parser.Expr) *parser.Call {
+	switch e := expr.(type) {
+	case *parser.ParenExpr:
+		return findRangeQCall(e.X)
+	case *parser.BinaryExpr:
+		if e.Op != parser.AND {
+			return nil
+		}
+		if call := findRangeQCall(e.X); call != nil {
+			return call
+		}
+		return findRangeQCall(e.Y)
+	case *parser.Call:
+		if strings.EqualFold(parser.IdentName(e.Name), "RANGEQ") && len(e.Args) == 3 {
+			return e
+		}
+	}
+	return nil
+}
+
+// rangeQBound returns the time given by a RANGEQ() bound, or nil if it's null
+// or isn't a literal.
+func rangeQBound(expr parser.Expr) *time.Time {
+	switch e := expr.(type) {
+	case *parser.StringLit:
+		return newStringLiteralPlanExpression(e.Value).ConvertToTimestamp()
+	case *parser.IntegerLit:
+		i, err := strconv.ParseInt(e.Value, 10, 64)
+		if err != nil {
+			return nil
+		}
+		t := time.Unix(i, 0).UTC()
+		return &t
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ package planner
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
@@ -305,6 +306,15 @@ func (p *ExecutionPlanner) compileSelectStatement(stmt *parser.SelectStatement,
 		}
 	}
 
+	// handle offset - this has to sit under any top or limit
+	if stmt.Offset.IsValid() {
+		offsetExpr, err := p.compileExpr(stmt.OffsetExpr)
+		if err != nil {
+			return nil, err
+		}
+		compiledOp = NewPlanOpOffset(offsetExpr, compiledOp)
+	}
+
 	// insert the top operator if it exists, or limit - analyzer should have caught the case of both existing
 	if stmt.Top.IsValid() {
 		topExpr, err := p.compileExpr(stmt.TopExpr)
@@ -570,6 +580,50 @@ func (p *ExecutionPlanner) analyzeSource(ctx context.Context, source parser.Sour
 			return paren, nil
 		}
 
+		// then materialized views, which read from the table behind the view
+		mv, err := p.getMaterializedViewByName(ctx, objectName)
+		if err != nil {
+			return nil, err
+		}
+		if mv != nil {
+			tbl, err := p.schemaAPI.TableByName(ctx, dax.TableName(materializedViewTableName(objectName)))
+			if err != nil {
+				return nil, err
+			}
+			columns := make([]string, 0, len(tbl.Fields))
+			for _, fld := range tbl.Fields {
+				if fld.Name == dax.PrimaryKeyFieldName {
+					continue
+				}
+				columns = append(columns, string(fld.Name))
+			}
+			sel := &parser.SelectStatement{
+				Source: &parser.QualifiedTableName{
+					Name: &parser.Ident{Name: string(tbl.Name)},
+				},
+			}
+			for _, c := range columns {
+				sel.Columns = append(sel.Columns, &parser.ResultColumn{
+					Expr: &parser.Ident{Name: c},
+				})
+			}
+			expr, err := p.analyzeSelectStatement(ctx, sel)
+			if err != nil {
+				return nil, err
+			}
+			selExpr, ok := expr.(*parser.SelectStatement)
+			if !ok {
+				return nil, sql3.NewErrInternalf("unexpected analyzed type")
+			}
+
+			// rewrite as a paren source with the select
+			paren := &parser.ParenSource{
+				X:     selExpr,
+				Alias: source.Alias,
+			}
+			return paren, nil
+		}
+
 		// if we got to here, not a view, so do table stuff
 
 		// check table exists
@@ -691,6 +745,26 @@ func (p *ExecutionPlanner) analyzeSelectStatement(ctx context.Context, stmt *par
 		stmt.LimitExpr = expr
 	}
 
+	expr, err = p.analyzeExpression(ctx, stmt.OffsetExpr, stmt)
+	if err != nil {
+		return nil, err
+	}
+	if expr != nil {
+		if !(expr.IsLiteral() && typeIsInteger(expr.DataType())) {
+			return nil, sql3.NewErrIntegerLiteral(stmt.OffsetExpr.Pos().Line, stmt.OffsetExpr.Pos().Column)
+		}
+		if lit, ok := expr.(*parser.IntegerLit); ok {
+			i, err := strconv.ParseInt(lit.Value, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			if i < 0 {
+				return nil, sql3.NewErrInvalidOffsetValue(stmt.OffsetExpr.Pos().Line, stmt.OffsetExpr.Pos().Column, i)
+			}
+		}
+		stmt.OffsetExpr = expr
+	}
+
 	expr, err = p.analyzeExpression(ctx, stmt.HavingExpr, stmt)
 	if err != nil {
 		return nil, err
@@ -722,7 +796,16 @@ func (p *ExecutionPlanner) analyzeSelectStatement(ctx context.Context, stmt *par
 	}
 
 	for _, term := range stmt.OrderingTerms {
-		err := p.analyzeOrderingTermExpression(term.X, stmt)
+		// ordering by the distance to a bitvector orders by that column,
+		// if it is in the projection list
+		pos, err := p.projectedOrderingTerm(ctx, term.X, stmt)
+		if err != nil {
+			return nil, err
+		}
+		if pos > 0 {
+			term.X = &parser.IntegerLit{ValuePos: term.X.Pos(), Value: strconv.Itoa(pos)}
+		}
+		err = p.analyzeOrderingTermExpression(term.X, stmt)
 		if err != nil {
 			return nil, err
 		}
@@ -731,6 +814,26 @@ func (p *ExecutionPlanner) analyzeSelectStatement(ctx context.Context, stmt *par
 	return stmt, nil
 }
 
+// projectedOrderingTerm returns the (1 based) position of the column in the
+// projection list of stmt with the same HAMMING() call as the ordering term
+// expr, or 0 if there is none.
+func (p *ExecutionPlanner) projectedOrderingTerm(ctx context.Context, expr parser.Expr, stmt *parser.SelectStatement) (int, error) {
+	call, ok := expr.(*parser.Call)
+	if !ok || !strings.EqualFold(parser.IdentName(call.Name), "HAMMING") {
+		return 0, nil
+	}
+	analyzed, err := p.analyzeExpression(ctx, parser.CloneExpr(expr), stmt)
+	if err != nil {
+		return 0, err
+	}
+	for i, col := range stmt.Columns {
+		if col.Expr != nil && strings.EqualFold(col.Expr.String(), analyzed.String()) {
+			return i + 1, nil
+		}
+	}
+	return 0, nil
+}
+
 func (p *ExecutionPlanner) analyzeSelectStatementWildcards(stmt *parser.SelectStatement) error {
 	if !stmt.HasWildcard() {
 		return nil
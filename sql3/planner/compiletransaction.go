@@ -0,0 +1,83 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// compileTransactionStatement compiles a BEGIN ... COMMIT block into a
+// PlanOperator. Each statement in the block is compiled by a planner whose
+// writes are staged rather than applied, so that the transaction operator
+// can apply them all together when (and if) the block commits.
+func (p *ExecutionPlanner) compileTransactionStatement(ctx context.Context, stmt *parser.TransactionStatement) (types.PlanOperator, error) {
+	staging := newStagingImporter(p.importer)
+
+	bp := *p
+	bp.importer = staging
+	bp.staging = staging
+
+	body := make([]types.PlanOperator, 0, len(stmt.Body))
+	for _, s := range stmt.Body {
+		var op types.PlanOperator
+		var err error
+		switch s := s.(type) {
+		case *parser.InsertStatement:
+			op, err = bp.compileInsertStatement(ctx, s)
+		case *parser.DeleteStatement:
+			op, err = bp.compileDeleteStatement(s)
+		case *parser.UpdateStatement:
+			op, err = bp.compileUpdateStatement(ctx, s)
+		default:
+			return nil, sql3.NewErrInternalf("cannot plan statement in transaction: %T", s)
+		}
+		if err != nil {
+			return nil, err
+		}
+		op, err = bp.optimizePlan(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		// each statement is compiled with its own PlanOpQuery root, but we
+		// only want one for the whole transaction
+		body = append(body, op.(*PlanOpQuery).ChildOp)
+	}
+
+	return NewPlanOpQuery(p, NewPlanOpTransaction(p, staging, body, stmt.Rollback != nil), p.sql), nil
+}
+
+// analyzeTransactionStatement analyzes each of the statements in a
+// transaction, and ensures that they are statements we are able to stage.
+func (p *ExecutionPlanner) analyzeTransactionStatement(ctx context.Context, stmt *parser.TransactionStatement) error {
+	if _, ok := p.importer.(pilosa.ShardTransactionApplier); !ok {
+		return sql3.NewErrUnsupported(stmt.Begin.Begin.Line, stmt.Begin.Begin.Column, false, "transactions")
+	}
+	if stmt.Rollback != nil && stmt.Rollback.SavepointName != nil {
+		pos := stmt.Rollback.To
+		return sql3.NewErrUnsupported(pos.Line, pos.Column, false, "savepoints")
+	}
+	for _, s := range stmt.Body {
+		switch s := s.(type) {
+		case *parser.InsertStatement:
+			if err := p.analyzeInsertStatement(ctx, s); err != nil {
+				return err
+			}
+		case *parser.DeleteStatement:
+			if err := p.analyzeDeleteStatement(ctx, s); err != nil {
+				return err
+			}
+		case *parser.UpdateStatement:
+			if err := p.analyzeUpdateStatement(ctx, s); err != nil {
+				return err
+			}
+		default:
+			return sql3.NewErrUnsupported(0, 0, false, "statements other than INSERT, REPLACE, UPDATE and DELETE within a transaction")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"strings"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// compileUpdateStatement compiles an UPDATE statement into a PlanOperator.
+// UPDATE is only planned within a transaction, where its writes are staged
+// along with those of the transaction's other statements.
+func (p *ExecutionPlanner) compileUpdateStatement(ctx context.Context, stmt *parser.UpdateStatement) (types.PlanOperator, error) {
+	query := NewPlanOpQuery(p, NewPlanOpNullTable(), p.sql)
+
+	tableName := strings.ToLower(parser.IdentName(stmt.Table.Name))
+	tbl, err := p.schemaAPI.TableByName(ctx, dax.TableName(tableName))
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return nil, sql3.NewErrTableNotFound(stmt.Table.Name.NamePos.Line, stmt.Table.Name.NamePos.Column, tableName)
+		}
+		return nil, err
+	}
+
+	// source expression
+	source, err := p.compileSource(query, stmt.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	// handle the where clause
+	where, err := p.compileExpr(stmt.WhereExpr)
+	if err != nil {
+		return nil, err
+	}
+	if where != nil {
+		source = NewPlanOpFilter(p, where, source)
+	}
+
+	// the records are identified by their _id
+	idType := parser.ExprDataType(parser.NewDataTypeID())
+	if tbl.StringKeys() {
+		idType = parser.NewDataTypeString()
+	}
+	id := newQualifiedRefPlanExpression(tableName, string(dax.PrimaryKeyFieldName), 0, idType)
+
+	var targetColumns []*qualifiedRefPlanExpression
+	var values []types.PlanExpression
+	var clearFields []string
+	for _, assignment := range stmt.Assignments {
+		colName := strings.ToLower(parser.IdentName(assignment.Columns[0]))
+		for idx, field := range tbl.Fields {
+			if !strings.EqualFold(colName, string(field.Name)) {
+				continue
+			}
+			targetColumns = append(targetColumns, newQualifiedRefPlanExpression(tableName, colName, idx, fieldSQLDataType(pilosa.FieldToFieldInfo(field))))
+			switch field.Type {
+			case dax.BaseTypeIDSet, dax.BaseTypeIDSetQ, dax.BaseTypeStringSet, dax.BaseTypeStringSetQ:
+				clearFields = append(clearFields, string(field.Name))
+			}
+			break
+		}
+		e, err := p.compileExpr(assignment.Expr)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, e)
+	}
+
+	children := []types.PlanOperator{
+		NewPlanOpTransactionUpdate(p, tableName, id, targetColumns, values, clearFields, source),
+	}
+	return query.WithChildren(children...)
+}
+
+// analyzeUpdateStatement analyzes an UPDATE statement and returns an error if
+// anything is invalid.
+func (p *ExecutionPlanner) analyzeUpdateStatement(ctx context.Context, stmt *parser.UpdateStatement) error {
+	switch {
+	case stmt.WithClause != nil:
+		return sql3.NewErrUnsupported(stmt.WithClause.With.Line, stmt.WithClause.With.Column, false, "WITH in UPDATE")
+	case stmt.UpdateOr.IsValid():
+		return sql3.NewErrUnsupported(stmt.UpdateOr.Line, stmt.UpdateOr.Column, false, "UPDATE OR")
+	}
+
+	tableName := strings.ToLower(parser.IdentName(stmt.Table.Name))
+	tbl, err := p.schemaAPI.TableByName(ctx, dax.TableName(tableName))
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return sql3.NewErrTableNotFound(stmt.Table.Name.NamePos.Line, stmt.Table.Name.NamePos.Column, tableName)
+		}
+		return err
+	}
+
+	// the source has to stay the table itself, rather than becoming the
+	// select of a view
+	source, err := p.analyzeSource(ctx, stmt.Table, stmt)
+	if err != nil {
+		return err
+	}
+	table, ok := source.(*parser.QualifiedTableName)
+	if !ok {
+		return sql3.NewErrTableNotFound(stmt.Table.Name.NamePos.Line, stmt.Table.Name.NamePos.Column, tableName)
+	}
+	stmt.Table = table
+
+	if stmt.WhereExpr != nil {
+		expr, err := p.analyzeExpression(ctx, stmt.WhereExpr, stmt)
+		if err != nil {
+			return err
+		}
+		stmt.WhereExpr = expr
+	}
+
+	assigned := make(map[string]struct{})
+	for _, assignment := range stmt.Assignments {
+		if len(assignment.Columns) != 1 {
+			return sql3.NewErrUnsupported(assignment.Lparen.Line, assignment.Lparen.Column, false, "column lists in UPDATE SET")
+		}
+		columnIdent := assignment.Columns[0]
+		colName := strings.ToLower(parser.IdentName(columnIdent))
+		if strings.EqualFold(colName, string(dax.PrimaryKeyFieldName)) || tbl.PrimaryKeyPosition(dax.FieldName(colName)) >= 0 {
+			return sql3.NewErrPrimaryKeyColumnChange(columnIdent.NamePos.Line, columnIdent.NamePos.Column, colName)
+		}
+
+		var typeName parser.ExprDataType
+		for _, field := range tbl.Fields {
+			if strings.EqualFold(colName, string(field.Name)) && !strings.EqualFold("_exists", string(field.Name)) {
+				typeName = fieldSQLDataType(pilosa.FieldToFieldInfo(field))
+				break
+			}
+		}
+		if typeName == nil {
+			return sql3.NewErrColumnNotFound(columnIdent.NamePos.Line, columnIdent.NamePos.Column, colName)
+		}
+		if _, found := assigned[colName]; found {
+			return sql3.NewErrDuplicateColumn(columnIdent.NamePos.Line, columnIdent.NamePos.Column, colName)
+		}
+		assigned[colName] = struct{}{}
+
+		// assignments may refer to the record's existing values
+		e, err := p.analyzeExpression(ctx, assignment.Expr, stmt)
+		if err != nil {
+			return err
+		}
+		if !typesAreAssignmentCompatible(typeName, e.DataType()) {
+			return sql3.NewErrTypeAssignmentIncompatible(assignment.Expr.Pos().Line, assignment.Expr.Pos().Column, e.DataType().TypeDescription(), typeName.TypeDescription())
+		}
+		assignment.Expr = e
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"fmt"
+	"strings"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// compositeKeyPlanExpression evaluates to the _id of a record in a table
+// with a primary key, made up of the values of the primary key columns.
+type compositeKeyPlanExpression struct {
+	parts []types.PlanExpression
+}
+
+func newCompositeKeyPlanExpression(parts []types.PlanExpression) *compositeKeyPlanExpression {
+	return &compositeKeyPlanExpression{
+		parts: parts,
+	}
+}
+
+func (n *compositeKeyPlanExpression) Evaluate(currentRow []interface{}) (interface{}, error) {
+	values := make([]interface{}, len(n.parts))
+	for i, part := range n.parts {
+		v, err := part.Evaluate(currentRow)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	key, err := pilosa.EncodeCompositeKey(values...)
+	if err != nil {
+		return nil, sql3.NewErrInternalf("%s", err.Error())
+	}
+	return key, nil
+}
+
+func (n *compositeKeyPlanExpression) Type() parser.ExprDataType {
+	return parser.NewDataTypeString()
+}
+
+func (n *compositeKeyPlanExpression) String() string {
+	parts := make([]string, len(n.parts))
+	for i, part := range n.parts {
+		parts[i] = part.String()
+	}
+	return fmt.Sprintf("compositekey(%s)", strings.Join(parts, ", "))
+}
+
+func (n *compositeKeyPlanExpression) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_expr"] = fmt.Sprintf("%T", n)
+	result["description"] = n.String()
+	result["dataType"] = n.Type().TypeDescription()
+	ps := make([]interface{}, 0)
+	for _, e := range n.parts {
+		ps = append(ps, e.Plan())
+	}
+	result["parts"] = ps
+	return result
+}
+
+func (n *compositeKeyPlanExpression) Children() []types.PlanExpression {
+	return n.parts
+}
+
+func (n *compositeKeyPlanExpression) WithChildren(children ...types.PlanExpression) (types.PlanExpression, error) {
+	if len(children) != len(n.parts) {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return newCompositeKeyPlanExpression(children), nil
+}
+
+// addCompositeKeyColumn adds the _id column to the target columns of an
+// insert into a table with primaryKey, and the expression making up its value
+// to each tuple. The _id goes last, so the positions of the columns the
+// statement names are unchanged.
+func addCompositeKeyColumn(tableName string, primaryKey []dax.FieldName, targetColumns []*qualifiedRefPlanExpression, tuples [][]types.PlanExpression) ([]*qualifiedRefPlanExpression, [][]types.PlanExpression, error) {
+	positions := make([]int, len(primaryKey))
+	for k, name := range primaryKey {
+		positions[k] = -1
+		for j, col := range targetColumns {
+			if strings.EqualFold(col.columnName, string(name)) {
+				positions[k] = j
+				break
+			}
+		}
+		if positions[k] < 0 {
+			return nil, nil, sql3.NewErrInternalf("primary key column '%s' not found in insert", name)
+		}
+	}
+
+	columns := make([]*qualifiedRefPlanExpression, len(targetColumns), len(targetColumns)+1)
+	copy(columns, targetColumns)
+	columns = append(columns, newQualifiedRefPlanExpression(tableName, string(dax.PrimaryKeyFieldName), 0, parser.NewDataTypeID()))
+	for n, tuple := range tuples {
+		parts := make([]types.PlanExpression, len(positions))
+		for k, j := range positions {
+			parts[k] = tuple[j]
+		}
+		tuples[n] = append(tuple, newCompositeKeyPlanExpression(parts))
+	}
+	return columns, tuples, nil
+}
+
+// compositeKeyCondition looks for equality comparisons of primary key
+// columns of tbl with literals among the conjuncts of filter. If they cover a
+// prefix of the primary key, it returns a ConstRow call for the records whose
+// keys start with those values, which is answered by the table's translator,
+// along with the rest of the filter. Otherwise it returns filter unchanged
+// and a nil call.
+func compositeKeyCondition(tbl *dax.Table, filter types.PlanExpression) (types.PlanExpression, *pql.Call, error) {
+	if len(tbl.PrimaryKey) == 0 || filter == nil {
+		return filter, nil, nil
+	}
+
+	conjuncts := splitOnAnd(filter)
+	keyValues := make(map[int]interface{})
+	keyConjuncts := make(map[int]int)
+	for c, expr := range conjuncts {
+		binOp, ok := expr.(*binOpPlanExpression)
+		if !ok || binOp.op != parser.EQ {
+			continue
+		}
+		ref, ok := binOp.lhs.(*qualifiedRefPlanExpression)
+		lit := binOp.rhs
+		if !ok {
+			ref, ok = binOp.rhs.(*qualifiedRefPlanExpression)
+			lit = binOp.lhs
+		}
+		if !ok {
+			continue
+		}
+		pos := tbl.PrimaryKeyPosition(dax.FieldName(strings.ToLower(ref.columnName)))
+		if pos < 0 {
+			continue
+		}
+		if _, seen := keyValues[pos]; seen {
+			continue
+		}
+		switch lit.(type) {
+		case *intLiteralPlanExpression, *stringLiteralPlanExpression:
+		default:
+			continue
+		}
+		value, err := planExprToValue(lit)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyValues[pos] = value
+		keyConjuncts[pos] = c
+	}
+
+	var prefix []interface{}
+	for k := range tbl.PrimaryKey {
+		value, ok := keyValues[k]
+		if !ok {
+			break
+		}
+		prefix = append(prefix, value)
+	}
+	if len(prefix) == 0 {
+		return filter, nil, nil
+	}
+
+	// The conjuncts making up the prefix are answered by the call.
+	used := make(map[int]struct{}, len(prefix))
+	for k := range prefix {
+		used[keyConjuncts[k]] = struct{}{}
+	}
+	var rest []types.PlanExpression
+	for c, expr := range conjuncts {
+		if _, ok := used[c]; !ok {
+			rest = append(rest, expr)
+		}
+	}
+
+	call := &pql.Call{
+		Name: "ConstRow",
+		Args: map[string]interface{}{},
+		Type: pql.PrecallGlobal,
+	}
+	if len(prefix) == len(tbl.PrimaryKey) {
+		key, err := pilosa.EncodeCompositeKey(prefix...)
+		if err != nil {
+			return nil, nil, sql3.NewErrInternalf("%s", err.Error())
+		}
+		call.Args["columns"] = []interface{}{key}
+	} else {
+		keyPrefix, err := pilosa.CompositeKeyPrefix(prefix...)
+		if err != nil {
+			return nil, nil, sql3.NewErrInternalf("%s", err.Error())
+		}
+		call.Args["keyPrefix"] = keyPrefix
+	}
+	return joinExprsWithAnd(rest...), call, nil
+}
@@ -32,17 +32,25 @@ func isTableNotFoundError(err error) bool {
 type ExecutionPlanner struct {
 	executor       pilosa.Executor
 	schemaAPI      pilosa.SchemaAPI
+	fieldAlterer   pilosa.FieldAlterer
 	systemAPI      pilosa.SystemAPI
 	systemLayerAPI pilosa.SystemLayerAPI
 	importer       pilosa.Importer
 	logger         logger.Logger
 	sql            string
+
+	// staging is non-nil while compiling the statements of a transaction;
+	// writes are staged here rather than applied.
+	staging *stagingImporter
 }
 
 func NewExecutionPlanner(executor pilosa.Executor, schemaAPI pilosa.SchemaAPI, systemAPI pilosa.SystemAPI, systemLayerAPI pilosa.SystemLayerAPI, importer pilosa.Importer, logger logger.Logger, sql string) *ExecutionPlanner {
+	// fields can only be renamed or altered where the schema supports it
+	fieldAlterer, _ := schemaAPI.(pilosa.FieldAlterer)
 	return &ExecutionPlanner{
 		executor:       executor,
 		schemaAPI:      newSystemTableDefinitionsWrapper(schemaAPI),
+		fieldAlterer:   fieldAlterer,
 		systemAPI:      systemAPI,
 		systemLayerAPI: systemLayerAPI,
 		importer:       importer,
@@ -84,7 +92,11 @@ func (p *ExecutionPlanner) CompilePlan(ctx context.Context, stmt parser.Statemen
 	case *parser.CreateTableStatement:
 		rootOperator, err = p.compileCreateTableStatement(ctx, stmt)
 	case *parser.CreateViewStatement:
-		rootOperator, err = p.compileCreateViewStatement(stmt)
+		if stmt.Materialized.IsValid() {
+			rootOperator, err = p.compileCreateMaterializedViewStatement(ctx, stmt)
+		} else {
+			rootOperator, err = p.compileCreateViewStatement(stmt)
+		}
 	case *parser.AlterDatabaseStatement:
 		rootOperator, err = p.compileAlterDatabaseStatement(ctx, stmt)
 	case *parser.AlterTableStatement:
@@ -97,6 +109,8 @@ func (p *ExecutionPlanner) CompilePlan(ctx context.Context, stmt parser.Statemen
 		rootOperator, err = p.compileDropTableStatement(ctx, stmt)
 	case *parser.DropViewStatement:
 		rootOperator, err = p.compileDropViewStatement(ctx, stmt)
+	case *parser.RefreshViewStatement:
+		rootOperator, err = p.compileRefreshViewStatement(ctx, stmt)
 	case *parser.DropModelStatement:
 		rootOperator, err = p.compileDropModelStatement(stmt)
 	case *parser.InsertStatement:
@@ -109,6 +123,14 @@ func (p *ExecutionPlanner) CompilePlan(ctx context.Context, stmt parser.Statemen
 		rootOperator, err = p.compileCreateModelStatement(stmt)
 	case *parser.CreateFunctionStatement:
 		rootOperator, err = p.compileCreateFunctionStatement(stmt)
+	case *parser.TransactionStatement:
+		rootOperator, err = p.compileTransactionStatement(ctx, stmt)
+	case *parser.DeclareCursorStatement:
+		rootOperator, err = p.compileDeclareCursorStatement(ctx, stmt)
+	case *parser.FetchStatement:
+		rootOperator, err = p.compileFetchStatement(ctx, stmt)
+	case *parser.CloseCursorStatement:
+		rootOperator, err = p.compileCloseCursorStatement(ctx, stmt)
 
 	default:
 		return nil, sql3.NewErrInternalf("cannot plan statement: %T", stmt)
@@ -156,6 +178,9 @@ func (p *ExecutionPlanner) analyzePlan(ctx context.Context, stmt parser.Statemen
 	case *parser.CreateTableStatement:
 		return p.analyzeCreateTableStatement(stmt)
 	case *parser.CreateViewStatement:
+		if stmt.Materialized.IsValid() {
+			return p.analyzeCreateMaterializedViewStatement(ctx, stmt)
+		}
 		return p.analyzeCreateViewStatement(ctx, stmt)
 	case *parser.AlterDatabaseStatement:
 		return p.analyzeAlterDatabaseStatement(stmt)
@@ -169,6 +194,8 @@ func (p *ExecutionPlanner) analyzePlan(ctx context.Context, stmt parser.Statemen
 		return nil
 	case *parser.DropViewStatement:
 		return nil
+	case *parser.RefreshViewStatement:
+		return p.analyzeRefreshViewStatement(ctx, stmt)
 	case *parser.DropModelStatement:
 		return nil
 	case *parser.InsertStatement:
@@ -181,6 +208,14 @@ func (p *ExecutionPlanner) analyzePlan(ctx context.Context, stmt parser.Statemen
 		return p.analyzeCreateModelStatement(ctx, stmt)
 	case *parser.CreateFunctionStatement:
 		return p.analyzeCreateFunctionStatement(stmt)
+	case *parser.TransactionStatement:
+		return p.analyzeTransactionStatement(ctx, stmt)
+	case *parser.DeclareCursorStatement:
+		return p.analyzeDeclareCursorStatement(ctx, stmt)
+	case *parser.FetchStatement:
+		return p.analyzeFetchStatement(ctx, stmt)
+	case *parser.CloseCursorStatement:
+		return nil
 
 	default:
 		return sql3.NewErrInternalf("cannot analyze statement: %T", stmt)
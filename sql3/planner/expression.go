@@ -41,6 +41,12 @@ func coerceValue(sourceType parser.ExprDataType, targetType parser.ExprDataType,
 				return nil, sql3.NewErrInternalf("unexpected value type '%T'", value)
 			}
 			return pql.NewDecimal(val*int64(math.Pow(10, float64(t.Scale))), t.Scale), nil
+		case *parser.DataTypeDouble:
+			val, ok := value.(int64)
+			if !ok {
+				return nil, sql3.NewErrInternalf("unexpected value type '%T'", value)
+			}
+			return float64(val), nil
 		case *parser.DataTypeTimestamp:
 			val, ok := value.(int64)
 			if !ok {
@@ -64,6 +70,12 @@ func coerceValue(sourceType parser.ExprDataType, targetType parser.ExprDataType,
 				return nil, sql3.NewErrInternalf("unexpected value type '%T'", value)
 			}
 			return pql.NewDecimal(int64(val)*int64(math.Pow(10, float64(t.Scale))), t.Scale), nil
+		case *parser.DataTypeDouble:
+			val, ok := value.(int64)
+			if !ok {
+				return nil, sql3.NewErrInternalf("unexpected value type '%T'", value)
+			}
+			return float64(val), nil
 		case *parser.DataTypeTimestamp:
 			val, ok := value.(int64)
 			if !ok {
@@ -77,6 +89,18 @@ func coerceValue(sourceType parser.ExprDataType, targetType parser.ExprDataType,
 		switch targetType.(type) {
 		case *parser.DataTypeDecimal:
 			return value, nil
+		case *parser.DataTypeDouble:
+			val, ok := value.(pql.Decimal)
+			if !ok {
+				return nil, sql3.NewErrInternalf("unexpected value type '%T'", value)
+			}
+			return val.Float64(), nil
+		}
+
+	case *parser.DataTypeDouble:
+		switch targetType.(type) {
+		case *parser.DataTypeDouble:
+			return value, nil
 		}
 
 	case *parser.DataTypeString:
@@ -104,6 +128,12 @@ func coerceValue(sourceType parser.ExprDataType, targetType parser.ExprDataType,
 			return value, nil
 		}
 
+	case *parser.DataTypeBool:
+		switch targetType.(type) {
+		case *parser.DataTypeBool:
+			return value, nil
+		}
+
 	case *parser.DataTypeIDSet:
 		switch targetType.(type) {
 		case *parser.DataTypeIDSet:
@@ -258,6 +288,13 @@ func (n *unaryOpPlanExpression) plusWithTypeCheck(rhs interface{}) (interface{},
 		}
 		return nil, sql3.NewErrInternalf("unexpected incompatible types '%T", rhs)
 
+	case *parser.DataTypeDouble:
+		nr, nrok := rhs.(float64)
+		if nrok {
+			return +nr, nil
+		}
+		return nil, sql3.NewErrInternalf("unexpected incompatible types '%T", rhs)
+
 	default:
 		return nil, sql3.NewErrInternalf("unexpected type '%T", n.resultDataType)
 	}
@@ -296,6 +333,13 @@ func (n *unaryOpPlanExpression) minusWithTypeCheck(rhs interface{}) (interface{}
 		}
 		return nil, sql3.NewErrInternalf("unexpected incompatible types '%T", rhs)
 
+	case *parser.DataTypeDouble:
+		nr, nrok := rhs.(float64)
+		if nrok {
+			return -nr, nil
+		}
+		return nil, sql3.NewErrInternalf("unexpected incompatible types '%T", rhs)
+
 	default:
 		return nil, sql3.NewErrInternalf("unexpected type '%T", n.resultDataType)
 	}
@@ -542,6 +586,57 @@ func (n *binOpPlanExpression) Evaluate(currentRow []interface{}) (interface{}, e
 		}
 		return nil, sql3.NewErrInternalf("unexpected type conversion error '%T', '%T'", coercedLhs, coercedRhs)
 
+	case *parser.DataTypeDouble:
+		// if either side is nil, return nil
+		if evalLhs == nil || evalRhs == nil {
+			return nil, nil
+		}
+
+		coercedLhs, err := coerceValue(n.lhs.Type(), coercedDataType, evalLhs, parser.Pos{Line: 0, Column: 0})
+		if err != nil {
+			return nil, err
+		}
+
+		coercedRhs, err := coerceValue(n.rhs.Type(), coercedDataType, evalRhs, parser.Pos{Line: 0, Column: 0})
+		if err != nil {
+			return nil, err
+		}
+
+		nl, nlok := coercedLhs.(float64)
+		nr, nrok := coercedRhs.(float64)
+		if nlok && nrok {
+			switch n.op {
+			case parser.NE:
+				return nl != nr, nil
+			case parser.EQ:
+				return nl == nr, nil
+			case parser.LE:
+				return nl <= nr, nil
+			case parser.GE:
+				return nl >= nr, nil
+			case parser.GT:
+				return nl > nr, nil
+			case parser.LT:
+				return nl < nr, nil
+
+			case parser.PLUS:
+				return nl + nr, nil
+			case parser.MINUS:
+				return nl - nr, nil
+			case parser.STAR:
+				return nl * nr, nil
+			case parser.SLASH:
+				if nr == 0 {
+					return nil, sql3.NewErrDivideByZero(0, 0)
+				}
+				return nl / nr, nil
+
+			default:
+				return nil, sql3.NewErrInternalf("unhandled operator %d", n.op)
+			}
+		}
+		return nil, sql3.NewErrInternalf("unexpected type conversion error '%T', '%T'", coercedLhs, coercedRhs)
+
 	case *parser.DataTypeTimestamp:
 		// if either side is nil, return nil
 		if evalLhs == nil || evalRhs == nil {
@@ -879,6 +974,10 @@ func (n *casePlanExpression) Evaluate(currentRow []interface{}) (interface{}, er
 			if err != nil {
 				return nil, err
 			}
+			// a null condition isn't true
+			if evalBlock == nil {
+				continue
+			}
 			bl, blok := evalBlock.(bool)
 			if !blok {
 				return nil, sql3.NewErrInternalf("unexpected type conversion error '%t'", blok)
@@ -1249,6 +1348,37 @@ func (n *betweenOpPlanExpression) Evaluate(currentRow []interface{}) (interface{
 			}
 			return result, nil
 
+		case *parser.DataTypeDouble:
+
+			nl, nlok := evalLhs.(float64)
+			if !(nlok) {
+				return nil, sql3.NewErrInternalf("unexpected type conversion error '%t'", nlok)
+			}
+
+			crl, err := coerceValue(exprRange.lhs.Type(), sType, rangeLower, parser.Pos{Line: 0, Column: 0})
+			if err != nil {
+				return nil, err
+			}
+			rl, ok := crl.(float64)
+			if !(ok) {
+				return nil, sql3.NewErrInternalf("unexpected type conversion error '%t'", crl)
+			}
+
+			cru, err := coerceValue(exprRange.rhs.Type(), sType, rangeUpper, parser.Pos{Line: 0, Column: 0})
+			if err != nil {
+				return nil, err
+			}
+			ru, ok := cru.(float64)
+			if !(ok) {
+				return nil, sql3.NewErrInternalf("unexpected type conversion error '%t'", cru)
+			}
+
+			result := nl >= rl && nl <= ru
+			if n.op == parser.NOTBETWEEN {
+				result = !result
+			}
+			return result, nil
+
 		default:
 			return nil, sql3.NewErrInternalf("unexpected range type '%T'", sType)
 		}
@@ -1394,6 +1524,27 @@ func (n *inOpPlanExpression) Evaluate(currentRow []interface{}) (interface{}, er
 			}
 		}
 
+	case *parser.DataTypeDouble:
+		nl, nlok := evalLhs.(float64)
+		if !nlok {
+			return nil, sql3.NewErrInternalf("unable to convert lhs expression to type '%s'", n.lhs.Type().TypeDescription())
+		}
+
+		for i, lm := range listMembers {
+			clm, err := coerceValue(exprList.exprs[i].Type(), n.lhs.Type(), lm, parser.Pos{Line: 0, Column: 0})
+			if err != nil {
+				return nil, err
+			}
+			l, lok := clm.(float64)
+			if !lok {
+				return nil, sql3.NewErrInternalf("unable to convert list expression to type '%s'", n.lhs.Type().TypeDescription())
+			}
+			if nl == l {
+				result = true
+				break
+			}
+		}
+
 	case *parser.DataTypeIDSet:
 		nl, nlok := evalLhs.([]int64)
 		if !nlok {
@@ -1555,6 +1706,20 @@ func (n *callPlanExpression) Evaluate(currentRow []interface{}) (interface{}, er
 		return n.EvaluateLower(currentRow)
 	case "REPLACEALL":
 		return n.EvaluateReplaceAll(currentRow)
+	case "JSON_VALUE":
+		return n.EvaluateJSONValue(currentRow)
+	case "JSON_EXISTS":
+		return n.EvaluateJSONExists(currentRow)
+	case "ST_POINT":
+		return n.EvaluateSTPoint(currentRow)
+	case "ST_DISTANCE":
+		return n.EvaluateSTDistance(currentRow)
+	case "ST_DWITHIN":
+		return n.EvaluateSTDWithin(currentRow)
+	case "ST_WITHIN_BOX":
+		return n.EvaluateSTWithinBox(currentRow)
+	case "HAMMING":
+		return n.EvaluateHamming(currentRow)
 	case "TRIM":
 		return n.EvaluateTrim(currentRow)
 	case "RTRIM":
@@ -1966,6 +2131,46 @@ func (n *floatLiteralPlanExpression) WithChildren(children ...types.PlanExpressi
 	return n, nil
 }
 
+// doubleLiteralPlanExpression is a double literal
+type doubleLiteralPlanExpression struct {
+	value float64
+}
+
+func newDoubleLiteralPlanExpression(value float64) *doubleLiteralPlanExpression {
+	return &doubleLiteralPlanExpression{
+		value: value,
+	}
+}
+
+func (n *doubleLiteralPlanExpression) Evaluate(currentRow []interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+func (n *doubleLiteralPlanExpression) Type() parser.ExprDataType {
+	return parser.NewDataTypeDouble()
+}
+
+func (n *doubleLiteralPlanExpression) String() string {
+	return strconv.FormatFloat(n.value, 'g', -1, 64)
+}
+
+func (n *doubleLiteralPlanExpression) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_expr"] = fmt.Sprintf("%T", n)
+	result["description"] = n.String()
+	result["dataType"] = n.Type().TypeDescription()
+	result["value"] = n.value
+	return result
+}
+
+func (n *doubleLiteralPlanExpression) Children() []types.PlanExpression {
+	return []types.PlanExpression{}
+}
+
+func (n *doubleLiteralPlanExpression) WithChildren(children ...types.PlanExpression) (types.PlanExpression, error) {
+	return n, nil
+}
+
 // boolLiteralPlanExpression is a bool literal
 type boolLiteralPlanExpression struct {
 	value bool
@@ -2183,6 +2388,8 @@ func (n *castPlanExpression) Evaluate(currentRow []interface{}) (interface{}, er
 			return nl > 0, nil
 		case *parser.DataTypeDecimal:
 			return pql.NewDecimal(nl*int64(math.Pow(10, float64(tt.Scale))), tt.Scale), nil
+		case *parser.DataTypeDouble:
+			return float64(nl), nil
 		case *parser.DataTypeString:
 			return fmt.Sprintf("%d", nl), nil
 		case *parser.DataTypeTimestamp:
@@ -2202,6 +2409,8 @@ func (n *castPlanExpression) Evaluate(currentRow []interface{}) (interface{}, er
 			return nl > 0, nil
 		case *parser.DataTypeDecimal:
 			return pql.NewDecimal(nl*int64(math.Pow(10, float64(tt.Scale))), tt.Scale), nil
+		case *parser.DataTypeDouble:
+			return float64(nl), nil
 		case *parser.DataTypeString:
 			return fmt.Sprintf("%d", nl), nil
 		case *parser.DataTypeTimestamp:
@@ -2234,10 +2443,31 @@ func (n *castPlanExpression) Evaluate(currentRow []interface{}) (interface{}, er
 		switch n.targetType.(type) {
 		case *parser.DataTypeDecimal:
 			return nl, nil
+		case *parser.DataTypeDouble:
+			return nl.Float64(), nil
 		case *parser.DataTypeString:
 			return fmt.Sprintf("%v", nl), nil
 		}
 
+	case *parser.DataTypeDouble:
+		nl, nlok := evalLhs.(float64)
+		if !nlok {
+			return nil, sql3.NewErrInternalf("unable to cast expression of type '%T' to type '%T'", n.lhs.Type(), n.targetType)
+		}
+		switch tt := n.targetType.(type) {
+		case *parser.DataTypeDouble:
+			return nl, nil
+		case *parser.DataTypeInt:
+			if math.IsNaN(nl) || nl < math.MinInt64 || nl >= math.MaxInt64 {
+				return nil, sql3.NewErrInvalidCast(0, 0, fmt.Sprintf("%v", nl), n.targetType.TypeDescription())
+			}
+			return int64(nl), nil
+		case *parser.DataTypeDecimal:
+			return pql.FromFloat64WithScale(nl, int(tt.Scale))
+		case *parser.DataTypeString:
+			return strconv.FormatFloat(nl, 'g', -1, 64), nil
+		}
+
 	case *parser.DataTypeIDSet:
 		nl, nlok := evalLhs.([]int64)
 		if !nlok {
@@ -2273,6 +2503,14 @@ func (n *castPlanExpression) Evaluate(currentRow []interface{}) (interface{}, er
 			}
 			return i, nil
 
+		case *parser.DataTypeDouble:
+			f, err := strconv.ParseFloat(nl, 64)
+			if err != nil {
+				// TODO(pok) need to push location into here
+				return nil, sql3.NewErrInvalidCast(0, 0, nl, n.targetType.TypeDescription())
+			}
+			return f, nil
+
 		case *parser.DataTypeDecimal:
 			castValue, err := pql.ParseDecimal(nl)
 			if err != nil {
@@ -2288,6 +2526,13 @@ func (n *castPlanExpression) Evaluate(currentRow []interface{}) (interface{}, er
 		case *parser.DataTypeString:
 			return nl, nil
 
+		case *parser.DataTypeJSON:
+			doc, err := normalizeJSON(nl)
+			if err != nil {
+				return nil, sql3.NewErrInvalidCast(0, 0, nl, n.targetType.TypeDescription())
+			}
+			return doc, nil
+
 		case *parser.DataTypeTimestamp:
 			if tm, err := time.ParseInLocation(time.RFC3339Nano, nl, time.UTC); err == nil {
 				return tm, nil
@@ -2300,6 +2545,16 @@ func (n *castPlanExpression) Evaluate(currentRow []interface{}) (interface{}, er
 			}
 		}
 
+	case *parser.DataTypeJSON:
+		nl, nlok := evalLhs.(string)
+		if !nlok {
+			return nil, sql3.NewErrInternalf("unable to cast expression of type '%T' to type '%T'", n.lhs.Type(), n.targetType)
+		}
+		switch n.targetType.(type) {
+		case *parser.DataTypeJSON, *parser.DataTypeString:
+			return nl, nil
+		}
+
 	case *parser.DataTypeStringSet:
 		nl, nlok := evalLhs.([]string)
 		if !nlok {
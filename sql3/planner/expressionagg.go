@@ -326,6 +326,20 @@ func (m *aggregateSum) Update(ctx context.Context, row types.Row) error {
 		dsum = dsum + val
 		m.sum = dsum
 
+	case *parser.DataTypeDouble:
+		val, ok := v.(float64)
+		if !ok {
+			return sql3.NewErrInternalf("unexpected type conversion '%T'", v)
+		}
+		var dsum float64
+		if m.sum != nil {
+			dsum, ok = m.sum.(float64)
+			if !ok {
+				return sql3.NewErrInternalf("unexpected type conversion '%T'", m.sum)
+			}
+		}
+		m.sum = dsum + val
+
 	default:
 		return sql3.NewErrInternalf("unhandled aggregate expression datatype '%T'", dataType)
 	}
@@ -347,6 +361,16 @@ func (m *aggregateSum) Eval(ctx context.Context) (interface{}, error) {
 			return nil, sql3.NewErrInternalf("unexpected type conversion '%T'", m.sum)
 		}
 		return dsum, nil
+
+	case *parser.DataTypeDouble:
+		if m.sum == nil {
+			return nil, nil
+		}
+		dsum, ok := m.sum.(float64)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unexpected type conversion '%T'", m.sum)
+		}
+		return dsum, nil
 	default:
 		return nil, sql3.NewErrInternalf("unhandled aggregate expression datatype '%T'", m.expr.Type())
 	}
@@ -479,6 +503,21 @@ func (a *aggregateAvg) Update(ctx context.Context, row types.Row) error {
 		default:
 			return sql3.NewErrInternalf("unhandled aggregate expression datatype '%T'", dataType)
 		}
+
+	case *parser.DataTypeDouble:
+		thisVal, ok := v.(float64)
+		if !ok {
+			return sql3.NewErrInternalf("unexpected type conversion '%T'", v)
+		}
+		var aggVal float64
+		if a.sum != nil {
+			aggVal, ok = a.sum.(float64)
+			if !ok {
+				return sql3.NewErrInternalf("unexpected type conversion '%T'", a.sum)
+			}
+		}
+		a.sum = aggVal + thisVal
+
 	default:
 		return sql3.NewErrInternalf("unhandled aggregate expression datatype '%T'", returnType)
 	}
@@ -513,6 +552,16 @@ func (a *aggregateAvg) Eval(ctx context.Context) (interface{}, error) {
 		}
 		return pql.DivideDecimal(sum, count), nil
 
+	case *parser.DataTypeDouble:
+		if a.rows == 0 {
+			return float64(0), nil
+		}
+		sum, ok := a.sum.(float64)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unexpected type conversion '%T'", a.sum)
+		}
+		return sum / float64(a.rows), nil
+
 	default:
 		return nil, sql3.NewErrInternalf("unhandled aggregate expression datatype '%T'", returnType)
 	}
@@ -643,6 +692,21 @@ func (m *aggregateMin) Update(ctx context.Context, row types.Row) error {
 			m.val = thisVal
 		}
 
+	case *parser.DataTypeDouble:
+		thisVal, ok := v.(float64)
+		if !ok {
+			return sql3.NewErrInternalf("unexpected type conversion '%T'", v)
+		}
+
+		aggVal, ok := m.val.(float64)
+		if !ok {
+			return sql3.NewErrInternalf("unexpected type conversion '%T'", v)
+		}
+
+		if thisVal < aggVal {
+			m.val = thisVal
+		}
+
 	case *parser.DataTypeString:
 		thisVal, ok := v.(string)
 		if !ok {
@@ -792,6 +856,21 @@ func (m *aggregateMax) Update(ctx context.Context, row types.Row) error {
 			m.val = thisVal
 		}
 
+	case *parser.DataTypeDouble:
+		thisVal, ok := v.(float64)
+		if !ok {
+			return sql3.NewErrInternalf("unexpected type conversion '%T'", v)
+		}
+
+		aggVal, ok := m.val.(float64)
+		if !ok {
+			return sql3.NewErrInternalf("unexpected type conversion '%T'", v)
+		}
+
+		if thisVal > aggVal {
+			m.val = thisVal
+		}
+
 	case *parser.DataTypeString:
 		thisVal, ok := v.(string)
 		if !ok {
@@ -86,6 +86,24 @@ func (p *ExecutionPlanner) analyzeExpression(ctx context.Context, expr parser.Ex
 		case *parser.InsertStatement:
 			return nil, sql3.NewErrColumnNotFound(e.NamePos.Line, e.NamePos.Column, e.Name)
 
+		case *constraintScope:
+			// a CHECK refers to its own column, which is the only variable in
+			// the row it's evaluated over
+			if !sc.check {
+				return nil, sql3.NewErrDefaultColumnReference(e.NamePos.Line, e.NamePos.Column, e.Name)
+			}
+			if !strings.EqualFold(e.Name, sc.column) {
+				return nil, sql3.NewErrCheckColumnReference(e.NamePos.Line, e.NamePos.Column, e.Name, sc.column)
+			}
+			return p.analyzeExpression(ctx, &parser.Variable{NamePos: e.NamePos, Name: "@" + sc.column}, sc.BulkInsertStatement)
+
+		case *upsertScope:
+			// the existing record's values can't be referred to
+			if idx, _ := sc.column(e.Name); idx >= 0 {
+				return nil, sql3.NewErrInsertConflictColumnReference(e.NamePos.Line, e.NamePos.Column, e.Name)
+			}
+			return nil, sql3.NewErrColumnNotFound(e.NamePos.Line, e.NamePos.Column, e.Name)
+
 		case *parser.DeleteStatement:
 
 			// go find the first ident in the source that matches
@@ -109,6 +127,27 @@ func (p *ExecutionPlanner) analyzeExpression(ctx context.Context, expr parser.Ex
 			}
 			return p.analyzeExpression(ctx, ident, scope)
 
+		case *parser.UpdateStatement:
+			oc, err := sc.Table.OutputColumnNamed(e.Name)
+			if err != nil {
+				return nil, err
+			} else if oc == nil {
+				return nil, sql3.NewErrColumnNotFound(e.NamePos.Line, e.NamePos.Column, e.Name)
+			}
+
+			ident := &parser.QualifiedRef{
+				Table: &parser.Ident{
+					Name:    oc.TableName,
+					NamePos: e.NamePos,
+				},
+				Column: &parser.Ident{
+					Name:    oc.ColumnName,
+					NamePos: e.NamePos,
+				},
+				ColumnIndex: oc.ColumnIndex,
+			}
+			return p.analyzeExpression(ctx, ident, scope)
+
 		default:
 			return nil, sql3.NewErrInternalf("unhandled scope type '%T'", sc)
 		}
@@ -132,6 +171,8 @@ func (p *ExecutionPlanner) analyzeExpression(ctx context.Context, expr parser.Ex
 				}
 			}
 			return nil, sql3.NewErrUnknownIdentifier(e.NamePos.Line, e.NamePos.Column, varname)
+		case *constraintScope:
+			return nil, sql3.NewErrUnknownIdentifier(e.NamePos.Line, e.NamePos.Column, e.VarName())
 		default:
 			return nil, sql3.NewErrInternalf("unhandled scope type '%T'", sc)
 		}
@@ -264,6 +305,30 @@ func (p *ExecutionPlanner) analyzeExpression(ctx context.Context, expr parser.Ex
 			}
 			return nil, sql3.NewErrColumnNotFound(e.Column.NamePos.Line, e.Column.NamePos.Column, e.Column.Name)
 
+		case *parser.UpdateStatement:
+			oc, err := sc.Table.OutputColumnNamed(e.Column.Name)
+			if err != nil {
+				return nil, err
+			}
+			if oc != nil {
+				e.RefDataType = oc.Datatype
+				e.ColumnIndex = oc.ColumnIndex
+				return e, nil
+			}
+			return nil, sql3.NewErrColumnNotFound(e.Column.NamePos.Line, e.Column.NamePos.Column, e.Column.Name)
+
+		case *upsertScope:
+			idx, dataType := sc.column(e.Column.Name)
+			if idx < 0 {
+				return nil, sql3.NewErrColumnNotFound(e.Column.NamePos.Line, e.Column.NamePos.Column, e.Column.Name)
+			}
+			if !strings.EqualFold(e.Table.Name, "excluded") {
+				return nil, sql3.NewErrInsertConflictColumnReference(e.Column.NamePos.Line, e.Column.NamePos.Column, e.Column.Name)
+			}
+			e.RefDataType = dataType
+			e.ColumnIndex = idx
+			return e, nil
+
 		default:
 			return nil, sql3.NewErrInternalf("unhandled scope type '%T'", sc)
 		}
@@ -384,6 +449,8 @@ func (p *ExecutionPlanner) analyzeUnaryExpression(ctx context.Context, expr *par
 				return nil, sql3.NewErrInternalf("unexpected data type")
 			}
 			expr.ResultDataType = fd
+		} else if typeIsDouble(x.DataType()) {
+			expr.ResultDataType = x.DataType()
 		} else {
 			return nil, sql3.NewErrInternalf("unexpected unary expression type: %T", x.DataType())
 		}
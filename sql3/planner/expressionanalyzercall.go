@@ -54,7 +54,7 @@ func (p *ExecutionPlanner) analyzeCallExpression(ctx context.Context, call *pars
 		}
 
 		//make sure the ref is sum-able
-		if !(typeIsInteger(call.Args[0].DataType()) || typeIsDecimal(call.Args[0].DataType())) {
+		if !(typeIsInteger(call.Args[0].DataType()) || typeIsDecimal(call.Args[0].DataType()) || typeIsDouble(call.Args[0].DataType())) {
 			return nil, sql3.NewErrIntOrDecimalExpressionExpected(call.Args[0].Pos().Line, call.Args[0].Pos().Column)
 		}
 
@@ -78,11 +78,15 @@ func (p *ExecutionPlanner) analyzeCallExpression(ctx context.Context, call *pars
 		}
 
 		//make sure the ref is avg-able
-		if !(typeIsInteger(call.Args[0].DataType()) || typeIsDecimal(call.Args[0].DataType())) {
+		if !(typeIsInteger(call.Args[0].DataType()) || typeIsDecimal(call.Args[0].DataType()) || typeIsDouble(call.Args[0].DataType())) {
 			return nil, sql3.NewErrIntOrDecimalExpressionExpected(call.Args[0].Pos().Line, call.Args[0].Pos().Column)
 		}
 
-		call.ResultDataType = parser.NewDataTypeDecimal(4)
+		if typeIsDouble(call.Args[0].DataType()) {
+			call.ResultDataType = parser.NewDataTypeDouble()
+		} else {
+			call.ResultDataType = parser.NewDataTypeDecimal(4)
+		}
 
 	case "PERCENTILE":
 		// can't do an percentile on a *
@@ -106,7 +110,7 @@ func (p *ExecutionPlanner) analyzeCallExpression(ctx context.Context, call *pars
 		}
 
 		//make sure the ref is percentilable-able
-		if !(typeIsInteger(ref.DataType()) || typeIsDecimal(ref.DataType()) || typeIsTimestamp(ref.DataType())) {
+		if !(typeIsInteger(ref.DataType()) || typeIsDecimal(ref.DataType()) || typeIsDouble(ref.DataType()) || typeIsTimestamp(ref.DataType())) {
 			return nil, sql3.NewErrIntOrDecimalOrTimestampExpressionExpected(ref.Table.NamePos.Line, ref.Table.NamePos.Column)
 		}
 
@@ -207,7 +211,7 @@ func (p *ExecutionPlanner) analyzeCallExpression(ctx context.Context, call *pars
 		}
 
 		// make sure the ref is min/max-able
-		if !(typeIsInteger(call.Args[0].DataType()) || typeIsDecimal(call.Args[0].DataType()) || typeIsTimestamp(call.Args[0].DataType()) || typeIsString(call.Args[0].DataType())) {
+		if !(typeIsInteger(call.Args[0].DataType()) || typeIsDecimal(call.Args[0].DataType()) || typeIsDouble(call.Args[0].DataType()) || typeIsTimestamp(call.Args[0].DataType()) || typeIsString(call.Args[0].DataType())) {
 			return nil, sql3.NewErrIntOrDecimalOrTimestampOrStringExpressionExpected(call.Args[0].Pos().Line, call.Args[0].Pos().Column)
 		}
 
@@ -325,6 +329,20 @@ func (p *ExecutionPlanner) analyzeCallExpression(ctx context.Context, call *pars
 		return p.analyzeFunctionDateTimeName(call, scope)
 	case "DATE_TRUNC":
 		return p.analyzeFunctionDateTrunc(call, scope)
+	case "JSON_VALUE":
+		return p.analyseFunctionJSONValue(call, scope)
+	case "JSON_EXISTS":
+		return p.analyseFunctionJSONExists(call, scope)
+	case "ST_POINT":
+		return p.analyseFunctionSTPoint(call, scope)
+	case "ST_DISTANCE":
+		return p.analyseFunctionSTDistance(call, scope)
+	case "ST_DWITHIN":
+		return p.analyseFunctionSTDWithin(call, scope)
+	case "ST_WITHIN_BOX":
+		return p.analyseFunctionSTWithinBox(call, scope)
+	case "HAMMING":
+		return p.analyseFunctionHamming(call, scope)
 	// time quantum funtions
 	case "RANGEQ":
 		return p.analyzeFunctionRangeQ(call, scope)
@@ -12,8 +12,41 @@ import (
 	"github.com/featurebasedb/featurebase/v3/sql3"
 	"github.com/featurebasedb/featurebase/v3/sql3/parser"
 	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
 )
 
+// generatePQLFilterCall returns the pql call which filters records by filter
+// and by the time quantum filters of a table scan. A time quantum filter
+// normally only limits the values returned for its column, but when the
+// records are aggregated rather than returned it filters the records.
+func (p *ExecutionPlanner) generatePQLFilterCall(ctx context.Context, filter types.PlanExpression, timeQuantumFilters []types.PlanExpression) (*pql.Call, error) {
+	cond, err := p.generatePQLCallFromExpr(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, tqf := range timeQuantumFilters {
+		rows, err := p.generatePQLCallFromExpr(ctx, tqf)
+		if err != nil {
+			return nil, err
+		}
+		records := &pql.Call{
+			Name:     "UnionRows",
+			Children: []*pql.Call{rows},
+			Type:     pql.PrecallGlobal,
+		}
+		if cond == nil {
+			cond = records
+			continue
+		}
+		cond = &pql.Call{
+			Name:     "Intersect",
+			Children: []*pql.Call{cond, records},
+		}
+	}
+	return cond, nil
+}
+
 // generatePQLCallFromExpr returns a *pql.Call tree for a given plan expression
 func (p *ExecutionPlanner) generatePQLCallFromExpr(ctx context.Context, expr types.PlanExpression) (_ *pql.Call, err error) {
 	if expr == nil {
@@ -96,6 +129,9 @@ func (p *ExecutionPlanner) generatePQLCallFromExpr(ctx context.Context, expr typ
 			}
 			return call, nil
 
+		case "ST_DWITHIN", "ST_WITHIN_BOX":
+			return p.generatePQLCallFromGeoFilter(ctx, expr)
+
 		case "RANGEQ":
 			col := expr.args[0].(*qualifiedRefPlanExpression)
 
@@ -287,6 +323,10 @@ func (p *ExecutionPlanner) generatePQLCallFromBinaryExpr(ctx context.Context, ex
 		}, nil
 
 	case parser.EQ:
+		if call, ok := expr.lhs.(*callPlanExpression); ok && strings.EqualFold(call.name, "JSON_VALUE") {
+			return p.generatePQLCallFromJSONValue(ctx, call, expr.rhs)
+		}
+
 		lhs, ok := expr.lhs.(*qualifiedRefPlanExpression)
 		if !ok {
 			return nil, sql3.NewErrInternalf("unexpected lhs %T", expr.lhs)
@@ -378,6 +418,21 @@ func (p *ExecutionPlanner) generatePQLCallFromBinaryExpr(ctx context.Context, ex
 				},
 			}, nil
 
+		case *parser.DataTypeDouble:
+			val, err := pqlValueToFloat64(pqlValue)
+			if err != nil {
+				return nil, err
+			}
+			return &pql.Call{
+				Name: "Row",
+				Args: map[string]interface{}{
+					lhs.columnName: &pql.Condition{
+						Op:    pql.EQ,
+						Value: val,
+					},
+				},
+			}, nil
+
 		default:
 			return nil, sql3.NewErrInternalf("unsupported type for binary expression: %v (%T)", typ, typ)
 		}
@@ -441,6 +496,21 @@ func (p *ExecutionPlanner) generatePQLCallFromBinaryExpr(ctx context.Context, ex
 				},
 			}, nil
 
+		case *parser.DataTypeDouble:
+			val, err := pqlValueToFloat64(pqlValue)
+			if err != nil {
+				return nil, err
+			}
+			return &pql.Call{
+				Name: "Row",
+				Args: map[string]interface{}{
+					lhs.columnName: &pql.Condition{
+						Op:    pql.NEQ,
+						Value: val,
+					},
+				},
+			}, nil
+
 		default:
 			return nil, sql3.NewErrInternalf("unsupported type for binary expression: %v (%T)", typ, typ)
 		}
@@ -473,7 +543,27 @@ func (p *ExecutionPlanner) generatePQLCallFromBinaryExpr(ctx context.Context, ex
 			}, nil
 
 		case *parser.DataTypeID:
-			return nil, sql3.NewErrUnsupported(0, 0, false, "range queries on id typed columns")
+			// records after a given _id can be read a page at a time, since
+			// a table scan returns them in _id order
+			if !strings.EqualFold(lhs.columnName, string(dax.PrimaryKeyFieldName)) || (op != parser.GT && op != parser.GE) {
+				return nil, sql3.NewErrUnsupported(0, 0, false, "range queries on id typed columns")
+			}
+			after, ok := pqlValue.(int64)
+			if !ok {
+				return nil, sql3.NewErrInternalf("unexpected type '%T", pqlValue)
+			}
+			if op == parser.GE {
+				after--
+			}
+			if after < 0 {
+				return &pql.Call{Name: "All"}, nil
+			}
+			return &pql.Call{
+				Name:     "Limit",
+				Children: []*pql.Call{{Name: "All"}},
+				Args:     map[string]interface{}{"after": after},
+				Type:     pql.PrecallGlobal,
+			}, nil
 
 		case *parser.DataTypeString:
 			return nil, sql3.NewErrUnsupported(0, 0, false, "range queries on string typed columns")
@@ -515,6 +605,25 @@ func (p *ExecutionPlanner) generatePQLCallFromBinaryExpr(ctx context.Context, ex
 				},
 			}, nil
 
+		case *parser.DataTypeDouble:
+			pqlOp, err := sqlToPQLOp(op)
+			if err != nil {
+				return nil, err
+			}
+			val, err := pqlValueToFloat64(pqlValue)
+			if err != nil {
+				return nil, err
+			}
+			return &pql.Call{
+				Name: "Row",
+				Args: map[string]interface{}{
+					lhs.columnName: &pql.Condition{
+						Op:    pqlOp,
+						Value: val,
+					},
+				},
+			}, nil
+
 		default:
 			return nil, sql3.NewErrInternalf("unsupported type for binary expression: %v (%T)", typ, typ)
 		}
@@ -555,7 +664,7 @@ func (p *ExecutionPlanner) generatePQLCallFromBinaryExpr(ctx context.Context, ex
 					},
 				},
 			}, nil
-		case *parser.DataTypeInt, *parser.DataTypeDecimal, *parser.DataTypeTimestamp:
+		case *parser.DataTypeInt, *parser.DataTypeDecimal, *parser.DataTypeDouble, *parser.DataTypeTimestamp:
 			return &pql.Call{
 				Name: "Row",
 				Args: map[string]interface{}{
@@ -566,6 +675,22 @@ func (p *ExecutionPlanner) generatePQLCallFromBinaryExpr(ctx context.Context, ex
 				},
 			}, nil
 
+		case *parser.DataTypeBitVector:
+			// a record has a bitvector if the row after its bits is set
+			call := &pql.Call{
+				Name: "Row",
+				Args: map[string]interface{}{
+					lhs.columnName: typ.Width,
+				},
+			}
+			if op == parser.IS {
+				return &pql.Call{
+					Name:     "Not",
+					Children: []*pql.Call{call},
+				}, nil
+			}
+			return call, nil
+
 		default:
 			return nil, sql3.NewErrInvalidTypeInFilterExpression(0, 0, typ.TypeDescription(), "is/is not null")
 		}
@@ -578,6 +703,117 @@ func (p *ExecutionPlanner) generatePQLCallFromBinaryExpr(ctx context.Context, ex
 	}
 }
 
+// generatePQLCallFromJSONValue returns a Row() call for JSON_VALUE(col, path)
+// = value, provided path is one of the indexed paths of col.
+func (p *ExecutionPlanner) generatePQLCallFromJSONValue(ctx context.Context, call *callPlanExpression, rhs types.PlanExpression) (*pql.Call, error) {
+	if len(call.args) != 2 {
+		return nil, sql3.NewErrInternalf("unexpected argument count %d", len(call.args))
+	}
+	ref, ok := call.args[0].(*qualifiedRefPlanExpression)
+	if !ok {
+		return nil, sql3.NewErrInternalf("unexpected json document %T", call.args[0])
+	}
+	path, ok := call.args[1].(*stringLiteralPlanExpression)
+	if !ok {
+		return nil, sql3.NewErrInternalf("unexpected json path %T", call.args[1])
+	}
+	value, ok := rhs.(*stringLiteralPlanExpression)
+	if !ok {
+		return nil, sql3.NewErrInternalf("unexpected rhs %T", rhs)
+	}
+
+	tbl, err := p.schemaAPI.TableByName(ctx, dax.TableName(ref.tableName))
+	if err != nil {
+		return nil, sql3.NewErrTableNotFound(0, 0, ref.tableName)
+	}
+	fld, ok := tbl.Field(dax.FieldName(ref.columnName))
+	if !ok || fld.Type != dax.BaseTypeJSON {
+		return nil, sql3.NewErrInternalf("column '%s' is not a json column", ref.columnName)
+	}
+	for _, indexed := range fld.Options.JSONPaths {
+		if indexed == path.value {
+			return &pql.Call{
+				Name: "Row",
+				Args: map[string]interface{}{
+					pilosa.JSONPathFieldName(ref.columnName, indexed): value.value,
+				},
+			}, nil
+		}
+	}
+	return nil, sql3.NewErrInternalf("json path '%s' is not indexed", path.value)
+}
+
+// generatePQLCallFromGeoFilter returns a Union() of the cells covering the
+// area given to ST_DWITHIN or ST_WITHIN_BOX. The cells cover more than the
+// area itself, so the result is a superset of the matching records and the
+// filter has to be applied again to the records returned.
+func (p *ExecutionPlanner) generatePQLCallFromGeoFilter(ctx context.Context, call *callPlanExpression) (*pql.Call, error) {
+	ref, ok := call.args[0].(*qualifiedRefPlanExpression)
+	if !ok || !typeIsGeoPoint(ref.Type()) {
+		return nil, sql3.NewErrInternalf("unexpected geopoint argument %T", call.args[0])
+	}
+
+	// the remaining arguments have to be known up front
+	args := make([]float64, 0, len(call.args)-1)
+	for _, arg := range call.args[1:] {
+		var refersToRow bool
+		InspectExpression(arg, func(e types.PlanExpression) bool {
+			switch e.(type) {
+			case *qualifiedRefPlanExpression, *subqueryPlanExpression:
+				refersToRow = true
+				return false
+			}
+			return true
+		})
+		if refersToRow {
+			return nil, sql3.NewErrInternalf("geo filter argument is not a constant")
+		}
+		eval, err := arg.Evaluate(nil)
+		if err != nil {
+			return nil, err
+		}
+		if eval == nil {
+			return nil, sql3.NewErrInternalf("geo filter argument is null")
+		}
+		f, err := geoFloat(eval)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, f)
+	}
+
+	var minLat, minLon, maxLat, maxLon float64
+	switch strings.ToUpper(call.name) {
+	case "ST_DWITHIN":
+		if !validGeoPoint(args[0], args[1]) {
+			return nil, sql3.NewErrInvalidGeoPoint(0, 0, args[0], args[1])
+		}
+		if args[2] < 0 {
+			return nil, sql3.NewErrValueOutOfRange(0, 0, args[2])
+		}
+		minLat, minLon, maxLat, maxLon = geoCircleBox(args[0], args[1], args[2])
+	default:
+		if err := validateGeoBox(args[0], args[1], args[2], args[3]); err != nil {
+			return nil, err
+		}
+		minLat, minLon, maxLat, maxLon = args[0], args[1], args[2], args[3]
+	}
+
+	cellField := pilosa.GeoCellFieldName(ref.columnName)
+	union := &pql.Call{
+		Name: "Union",
+	}
+	for _, cell := range geoBoxCells(minLat, minLon, maxLat, maxLon) {
+		union.Children = append(union.Children, &pql.Call{
+			Name: "Row",
+			Args: map[string]interface{}{
+				cellField: cell,
+			},
+		})
+	}
+	return union, nil
+}
+
 // sqlToPQLOp converts a parser operation token to PQL.
 func sqlToPQLOp(op parser.Token) (pql.Token, error) {
 	switch op {
@@ -615,7 +851,22 @@ func planExprToValue(expr types.PlanExpression) (interface{}, error) {
 			return nil, err
 		}
 		return f, nil
+	case *doubleLiteralPlanExpression:
+		return expr.value, nil
 	default:
 		return nil, sql3.NewErrInternalf("cannot convert SQL expression %T to a literal value", expr)
 	}
 }
+
+// pqlValueToFloat64 converts a literal value to a float64 for filtering a
+// double column.
+func pqlValueToFloat64(value interface{}) (float64, error) {
+	switch val := value.(type) {
+	case float64:
+		return val, nil
+	case int64:
+		return float64(val), nil
+	default:
+		return 0, sql3.NewErrInternalf("unexpected type '%T", value)
+	}
+}
@@ -36,6 +36,9 @@ func fieldSQLDataType(f *pilosa.FieldInfo) parser.ExprDataType {
 
 	switch f.Options.Type {
 	case pilosa.FieldTypeInt:
+		if f.Options.GeoPoint {
+			return parser.NewDataTypeGeoPoint()
+		}
 		return parser.NewDataTypeInt()
 
 	case pilosa.FieldTypeMutex:
@@ -46,6 +49,12 @@ func fieldSQLDataType(f *pilosa.FieldInfo) parser.ExprDataType {
 		}
 
 	case pilosa.FieldTypeSet:
+		if f.Options.BitVector > 0 {
+			return parser.NewDataTypeBitVector(f.Options.BitVector)
+		}
+		if f.Options.JSON {
+			return parser.NewDataTypeJSON()
+		}
 		if f.Options.Keys {
 			return parser.NewDataTypeStringSet()
 		} else {
@@ -58,6 +67,9 @@ func fieldSQLDataType(f *pilosa.FieldInfo) parser.ExprDataType {
 	case pilosa.FieldTypeDecimal:
 		return parser.NewDataTypeDecimal(f.Options.Scale)
 
+	case pilosa.FieldTypeFloat:
+		return parser.NewDataTypeDouble()
+
 	case pilosa.FieldTypeTime:
 		if f.Options.Keys {
 			return parser.NewDataTypeStringSetQuantum()
@@ -77,6 +89,13 @@ func fieldSQLDataType(f *pilosa.FieldInfo) parser.ExprDataType {
 func dataTypeFromParserType(typ *parser.Type) (parser.ExprDataType, error) {
 	typeName := parser.IdentName(typ.Name)
 	switch strings.ToLower(typeName) {
+	case dax.BaseTypeBitVector:
+		width, err := bitVectorWidth(typ)
+		if err != nil {
+			return nil, err
+		}
+		return parser.NewDataTypeBitVector(width), nil
+
 	case dax.BaseTypeBool:
 		return parser.NewDataTypeBool(), nil
 
@@ -90,6 +109,12 @@ func dataTypeFromParserType(typ *parser.Type) (parser.ExprDataType, error) {
 		}
 		return parser.NewDataTypeDecimal(int64(scale)), nil
 
+	case dax.BaseTypeDouble:
+		return parser.NewDataTypeDouble(), nil
+
+	case dax.BaseTypeGeoPoint:
+		return parser.NewDataTypeGeoPoint(), nil
+
 	case dax.BaseTypeID:
 		return parser.NewDataTypeID(), nil
 
@@ -99,6 +124,9 @@ func dataTypeFromParserType(typ *parser.Type) (parser.ExprDataType, error) {
 	case dax.BaseTypeInt:
 		return parser.NewDataTypeInt(), nil
 
+	case dax.BaseTypeJSON:
+		return parser.NewDataTypeJSON(), nil
+
 	case dax.BaseTypeString:
 		return parser.NewDataTypeString(), nil
 
@@ -127,7 +155,7 @@ func typeIsCompatibleWithLogicalOperator(testType parser.ExprDataType) bool {
 func typeIsCompatibleWithEqualityOperator(testType parser.ExprDataType) bool {
 	switch testType.(type) {
 	case *parser.DataTypeID, *parser.DataTypeInt,
-		*parser.DataTypeDecimal, *parser.DataTypeBool,
+		*parser.DataTypeDecimal, *parser.DataTypeDouble, *parser.DataTypeBool,
 		*parser.DataTypeString, *parser.DataTypeTimestamp,
 		*parser.DataTypeIDSet, *parser.DataTypeStringSet:
 		return true
@@ -139,7 +167,7 @@ func typeIsCompatibleWithEqualityOperator(testType parser.ExprDataType) bool {
 // returns true if type is compatible with comparison operators (<, <=, >, >=)
 func typeIsCompatibleWithComparisonOperator(testType parser.ExprDataType) bool {
 	switch testType.(type) {
-	case *parser.DataTypeID, *parser.DataTypeInt, *parser.DataTypeDecimal, *parser.DataTypeTimestamp:
+	case *parser.DataTypeID, *parser.DataTypeInt, *parser.DataTypeDecimal, *parser.DataTypeDouble, *parser.DataTypeTimestamp:
 		return true
 	default:
 		return false
@@ -151,7 +179,7 @@ func typeIsCompatibleWithArithmeticOperator(testType parser.ExprDataType, op par
 	switch testType.(type) {
 	case *parser.DataTypeID, *parser.DataTypeInt:
 		return true
-	case *parser.DataTypeDecimal:
+	case *parser.DataTypeDecimal, *parser.DataTypeDouble:
 		return op != parser.REM
 	default:
 		return false
@@ -193,6 +221,10 @@ func typesAreRangeComparable(testTypeL parser.ExprDataType, testTypeR parser.Exp
 				// change subscript type to be decimal
 				rhsType.SubscriptType = lhsType
 				return true, nil
+			case *parser.DataTypeDouble:
+				// change subscript type to be double
+				rhsType.SubscriptType = lhsType
+				return true, nil
 
 			default:
 				return false, sql3.NewErrInternalf("unhandled rhs type '%T' for lhs type '%T'", rhsType, lhsType)
@@ -202,6 +234,19 @@ func typesAreRangeComparable(testTypeL parser.ExprDataType, testTypeR parser.Exp
 			switch lhsType := testTypeL.(type) {
 			case *parser.DataTypeDecimal:
 				return true, nil
+			case *parser.DataTypeDouble:
+				// change subscript type to be double
+				rhsType.SubscriptType = lhsType
+				return true, nil
+
+			default:
+				return false, sql3.NewErrInternalf("unhandled rhs type '%T' for lhs type '%T'", rhsType, lhsType)
+			}
+
+		case *parser.DataTypeDouble:
+			switch lhsType := testTypeL.(type) {
+			case *parser.DataTypeDouble:
+				return true, nil
 
 			default:
 				return false, sql3.NewErrInternalf("unhandled rhs type '%T' for lhs type '%T'", rhsType, lhsType)
@@ -262,6 +307,18 @@ func typesAreAssignmentCompatible(targetType parser.ExprDataType, sourceType par
 			return false
 		}
 
+	case *parser.DataTypeBitVector:
+		// a bitvector can be given as a string of hex digits, which is checked
+		// when it is assigned
+		switch source := sourceType.(type) {
+		case *parser.DataTypeBitVector:
+			return source.Width == lhs.Width
+		case *parser.DataTypeString:
+			return true
+		default:
+			return false
+		}
+
 	case *parser.DataTypeID:
 		switch sourceType.(type) {
 		case *parser.DataTypeInt:
@@ -331,6 +388,28 @@ func typesAreAssignmentCompatible(targetType parser.ExprDataType, sourceType par
 		default:
 			return false
 		}
+	case *parser.DataTypeJSON:
+		switch sourceType.(type) {
+		case *parser.DataTypeJSON, *parser.DataTypeString:
+			return true
+		default:
+			return false
+		}
+
+	case *parser.DataTypeGeoPoint:
+		switch source := sourceType.(type) {
+		case *parser.DataTypeGeoPoint:
+			return true
+		case *parser.DataTypeTuple:
+			// a geopoint can be given as a (latitude, longitude) tuple
+			if len(source.Members) != 2 {
+				return false
+			}
+			return typeIsNumeric(source.Members[0]) && typeIsNumeric(source.Members[1])
+		default:
+			return false
+		}
+
 	case *parser.DataTypeDecimal:
 		switch rhs := sourceType.(type) {
 		case *parser.DataTypeDecimal:
@@ -342,6 +421,14 @@ func typesAreAssignmentCompatible(targetType parser.ExprDataType, sourceType par
 			return false
 		}
 
+	case *parser.DataTypeDouble:
+		switch sourceType.(type) {
+		case *parser.DataTypeDouble, *parser.DataTypeDecimal, *parser.DataTypeInt, *parser.DataTypeID:
+			return true
+		default:
+			return false
+		}
+
 	case *parser.DataTypeTimestamp:
 		switch sourceType.(type) {
 		case *parser.DataTypeTimestamp:
@@ -372,7 +459,7 @@ func typesAreAssignmentCompatible(targetType parser.ExprDataType, sourceType par
 // returns true if the type can be used as a range subscript
 func typeCanBeUsedInRange(testType parser.ExprDataType) bool {
 	switch testType.(type) {
-	case *parser.DataTypeID, *parser.DataTypeInt, *parser.DataTypeTimestamp, *parser.DataTypeDecimal:
+	case *parser.DataTypeID, *parser.DataTypeInt, *parser.DataTypeTimestamp, *parser.DataTypeDecimal, *parser.DataTypeDouble:
 		return true
 	default:
 		return false
@@ -394,6 +481,9 @@ func typesOfRangeBoundsAreTheSame(testTypeL parser.ExprDataType, testTypeR parse
 			// 'widen' both to decimal
 			return true, testTypeR
 
+		case *parser.DataTypeDouble:
+			return true, testTypeR
+
 		default:
 			return false, nil
 		}
@@ -430,6 +520,18 @@ func typesOfRangeBoundsAreTheSame(testTypeL parser.ExprDataType, testTypeR parse
 		case *parser.DataTypeDecimal:
 			return true, testTypeL
 
+		case *parser.DataTypeDouble:
+			return true, testTypeR
+
+		default:
+			return false, nil
+		}
+
+	case *parser.DataTypeDouble:
+		switch testTypeR.(type) {
+		case *parser.DataTypeInt, *parser.DataTypeID, *parser.DataTypeDecimal, *parser.DataTypeDouble:
+			return true, testTypeL
+
 		default:
 			return false, nil
 		}
@@ -498,6 +600,15 @@ func typeIsInteger(testType parser.ExprDataType) bool {
 }
 
 // returns true if the type is string
+func typeIsJSON(testType parser.ExprDataType) bool {
+	switch testType.(type) {
+	case *parser.DataTypeJSON:
+		return true
+	default:
+		return false
+	}
+}
+
 func typeIsString(testType parser.ExprDataType) bool {
 	switch testType.(type) {
 	case *parser.DataTypeString:
@@ -536,10 +647,50 @@ func typeIsDecimal(testType parser.ExprDataType) bool {
 	}
 }
 
+// returns true if the type is a double
+func typeIsDouble(testType parser.ExprDataType) bool {
+	switch testType.(type) {
+	case *parser.DataTypeDouble:
+		return true
+	default:
+		return false
+	}
+}
+
+// returns true if the type is an integer, decimal or double
+func typeIsNumeric(testType parser.ExprDataType) bool {
+	switch testType.(type) {
+	case *parser.DataTypeID, *parser.DataTypeInt, *parser.DataTypeDecimal, *parser.DataTypeDouble:
+		return true
+	default:
+		return false
+	}
+}
+
+// returns true if the type is a bitvector
+func typeIsBitVector(testType parser.ExprDataType) bool {
+	switch testType.(type) {
+	case *parser.DataTypeBitVector:
+		return true
+	default:
+		return false
+	}
+}
+
+// returns true if the type is a geopoint
+func typeIsGeoPoint(testType parser.ExprDataType) bool {
+	switch testType.(type) {
+	case *parser.DataTypeGeoPoint:
+		return true
+	default:
+		return false
+	}
+}
+
 // returns true if the type is bit-sliced
 func typeIsBSI(testType parser.ExprDataType) bool {
 	switch testType.(type) {
-	case *parser.DataTypeInt, *parser.DataTypeDecimal, *parser.DataTypeTimestamp:
+	case *parser.DataTypeInt, *parser.DataTypeDecimal, *parser.DataTypeDouble, *parser.DataTypeTimestamp:
 		return true
 	default:
 		return false
@@ -549,7 +700,7 @@ func typeIsBSI(testType parser.ExprDataType) bool {
 // returns true if we can sort on a type
 func typeCanBeSortedOn(testType parser.ExprDataType) bool {
 	switch testType.(type) {
-	case *parser.DataTypeStringSet, *parser.DataTypeIDSet:
+	case *parser.DataTypeStringSet, *parser.DataTypeIDSet, *parser.DataTypeGeoPoint, *parser.DataTypeBitVector:
 		return false
 	default:
 		return true
@@ -567,6 +718,8 @@ func typesAreComparable(testTypeL parser.ExprDataType, testTypeR parser.ExprData
 			return true
 		case *parser.DataTypeDecimal:
 			return true
+		case *parser.DataTypeDouble:
+			return true
 
 		}
 
@@ -578,6 +731,8 @@ func typesAreComparable(testTypeL parser.ExprDataType, testTypeR parser.ExprData
 			return true
 		case *parser.DataTypeDecimal:
 			return true
+		case *parser.DataTypeDouble:
+			return true
 
 		}
 
@@ -589,6 +744,14 @@ func typesAreComparable(testTypeL parser.ExprDataType, testTypeR parser.ExprData
 			return true
 		case *parser.DataTypeDecimal:
 			return true
+		case *parser.DataTypeDouble:
+			return true
+		}
+
+	case *parser.DataTypeDouble:
+		switch testTypeR.(type) {
+		case *parser.DataTypeID, *parser.DataTypeInt, *parser.DataTypeDecimal, *parser.DataTypeDouble:
+			return true
 		}
 
 	case *parser.DataTypeBool:
@@ -641,6 +804,9 @@ func typesCoercedForArithmeticOperator(testTypeL parser.ExprDataType, testTypeR
 
 		case *parser.DataTypeDecimal:
 			return rhsType, nil
+
+		case *parser.DataTypeDouble:
+			return rhsType, nil
 		}
 
 	case *parser.DataTypeID:
@@ -650,6 +816,9 @@ func typesCoercedForArithmeticOperator(testTypeL parser.ExprDataType, testTypeR
 
 		case *parser.DataTypeInt:
 			return testTypeR, nil
+
+		case *parser.DataTypeDouble:
+			return testTypeR, nil
 		}
 
 	case *parser.DataTypeDecimal:
@@ -665,6 +834,15 @@ func typesCoercedForArithmeticOperator(testTypeL parser.ExprDataType, testTypeR
 				return lhsType, nil
 			}
 			return rhsType, nil
+
+		case *parser.DataTypeDouble:
+			return rhsType, nil
+		}
+
+	case *parser.DataTypeDouble:
+		switch testTypeR.(type) {
+		case *parser.DataTypeInt, *parser.DataTypeID, *parser.DataTypeDecimal, *parser.DataTypeDouble:
+			return testTypeL, nil
 		}
 
 	}
@@ -721,6 +899,8 @@ func typeCoerceType(testTypeL parser.ExprDataType, testTypeR parser.ExprDataType
 			return testTypeL, nil
 		case *parser.DataTypeDecimal:
 			return testTypeR, nil
+		case *parser.DataTypeDouble:
+			return testTypeR, nil
 		}
 
 	case *parser.DataTypeDecimal:
@@ -731,6 +911,15 @@ func typeCoerceType(testTypeL parser.ExprDataType, testTypeR parser.ExprDataType
 			return testTypeL, nil
 		case *parser.DataTypeID:
 			return testTypeL, nil
+		case *parser.DataTypeDouble:
+			return testTypeR, nil
+
+		}
+
+	case *parser.DataTypeDouble:
+		switch testTypeR.(type) {
+		case *parser.DataTypeDouble, *parser.DataTypeDecimal, *parser.DataTypeInt, *parser.DataTypeID:
+			return testTypeL, nil
 
 		}
 
@@ -740,6 +929,8 @@ func typeCoerceType(testTypeL parser.ExprDataType, testTypeR parser.ExprDataType
 			return testTypeL, nil
 		case *parser.DataTypeInt:
 			return testTypeR, nil
+		case *parser.DataTypeDouble:
+			return testTypeR, nil
 
 		}
 
@@ -785,6 +976,7 @@ func typesCanBeCast(sourceType parser.ExprDataType, targetType parser.ExprDataTy
 		case *parser.DataTypeInt,
 			*parser.DataTypeBool,
 			*parser.DataTypeDecimal,
+			*parser.DataTypeDouble,
 			*parser.DataTypeID,
 			*parser.DataTypeString,
 			*parser.DataTypeTimestamp:
@@ -803,7 +995,16 @@ func typesCanBeCast(sourceType parser.ExprDataType, targetType parser.ExprDataTy
 		switch tt := targetType.(type) {
 		case *parser.DataTypeDecimal:
 			return tt.Scale >= st.Scale
-		case *parser.DataTypeString:
+		case *parser.DataTypeDouble, *parser.DataTypeString:
+			return true
+		}
+
+	case *parser.DataTypeDouble:
+		switch targetType.(type) {
+		case *parser.DataTypeDouble,
+			*parser.DataTypeInt,
+			*parser.DataTypeDecimal,
+			*parser.DataTypeString:
 			return true
 		}
 
@@ -812,6 +1013,7 @@ func typesCanBeCast(sourceType parser.ExprDataType, targetType parser.ExprDataTy
 		case *parser.DataTypeInt,
 			*parser.DataTypeBool,
 			*parser.DataTypeDecimal,
+			*parser.DataTypeDouble,
 			*parser.DataTypeID:
 			return true
 		case *parser.DataTypeString:
@@ -829,11 +1031,20 @@ func typesCanBeCast(sourceType parser.ExprDataType, targetType parser.ExprDataTy
 		case *parser.DataTypeInt,
 			*parser.DataTypeBool,
 			*parser.DataTypeDecimal,
+			*parser.DataTypeDouble,
 			*parser.DataTypeID,
+			*parser.DataTypeJSON,
 			*parser.DataTypeString,
 			*parser.DataTypeTimestamp:
 			return true
 		}
+
+	case *parser.DataTypeJSON:
+		switch targetType.(type) {
+		case *parser.DataTypeJSON, *parser.DataTypeString:
+			return true
+		}
+
 	case *parser.DataTypeStringSet:
 		switch targetType.(type) {
 		case *parser.DataTypeStringSet, *parser.DataTypeString:
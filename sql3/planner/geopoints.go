@@ -0,0 +1,323 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+	"github.com/pkg/errors"
+)
+
+// A geopoint is stored in an int field, with the latitude and longitude in
+// degrees scaled by 1e7 and packed into the high and low 32 bits. That keeps
+// about a centimetre of precision.
+const geoPointScale = 1e7
+
+// geoEarthRadius is the mean radius of the earth in meters.
+const geoEarthRadius = 6371008.8
+
+// geoCellBits holds the number of bits of latitude and longitude for each
+// level of cells that a point is indexed in, from coarsest to finest. At 20
+// bits, a cell is about 19m high.
+var geoCellBits = []uint{4, 6, 8, 10, 12, 14, 16, 18, 20}
+
+// geoMaxCoverCells is the number of cells a covering may use before a coarser
+// level is used instead.
+const geoMaxCoverCells = 64
+
+// packGeoPoint returns the int value stored for a point.
+func packGeoPoint(lat, lon float64) int64 {
+	latE7 := int32(math.Round(lat * geoPointScale))
+	lonE7 := int32(math.Round(lon * geoPointScale))
+	return int64(latE7)<<32 | int64(uint32(lonE7))
+}
+
+// unpackGeoPoint returns the latitude and longitude of a stored point.
+func unpackGeoPoint(v int64) (float64, float64) {
+	lat := float64(int32(v>>32)) / geoPointScale
+	lon := float64(int32(uint32(v))) / geoPointScale
+	return lat, lon
+}
+
+// newGeoPointLiteralPlanExpression returns a (latitude, longitude) tuple
+// literal which can be assigned to a geopoint column.
+func newGeoPointLiteralPlanExpression(lat, lon float64) types.PlanExpression {
+	members := []types.PlanExpression{
+		newDoubleLiteralPlanExpression(lat),
+		newDoubleLiteralPlanExpression(lon),
+	}
+	return newExprTupleLiteralPlanExpression(members, parser.NewDataTypeTuple([]parser.ExprDataType{
+		parser.NewDataTypeDouble(),
+		parser.NewDataTypeDouble(),
+	}))
+}
+
+// validGeoPoint returns true if lat and lon are within range.
+func validGeoPoint(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// geoFloat returns a numeric sql value as a float64.
+func geoFloat(v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case pql.Decimal:
+		return v.Float64(), nil
+	default:
+		return 0, sql3.NewErrUnexpectedTypeConversion(0, 0, v)
+	}
+}
+
+// geoPointFromValue returns the latitude and longitude of a geopoint value,
+// which is either a []float64 or a tuple of two numbers.
+func geoPointFromValue(v interface{}) (float64, float64, error) {
+	var lat, lon float64
+	switch v := v.(type) {
+	case []float64:
+		if len(v) != 2 {
+			return 0, 0, sql3.NewErrUnexpectedTypeConversion(0, 0, v)
+		}
+		lat, lon = v[0], v[1]
+	case []interface{}:
+		if len(v) != 2 {
+			return 0, 0, sql3.NewErrUnexpectedTypeConversion(0, 0, v)
+		}
+		var err error
+		if lat, err = geoFloat(v[0]); err != nil {
+			return 0, 0, err
+		}
+		if lon, err = geoFloat(v[1]); err != nil {
+			return 0, 0, err
+		}
+	default:
+		return 0, 0, sql3.NewErrUnexpectedTypeConversion(0, 0, v)
+	}
+	if !validGeoPoint(lat, lon) {
+		return 0, 0, sql3.NewErrInvalidGeoPoint(0, 0, lat, lon)
+	}
+	return lat, lon, nil
+}
+
+// parseGeoPointString parses a point written as "latitude,longitude".
+func parseGeoPointString(s string) ([]float64, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return nil, errors.Errorf("expected 'latitude,longitude', got '%s'", s)
+	}
+	point := make([]float64, 2)
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, err
+		}
+		point[i] = f
+	}
+	if !validGeoPoint(point[0], point[1]) {
+		return nil, sql3.NewErrInvalidGeoPoint(0, 0, point[0], point[1])
+	}
+	return point, nil
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// geoDistance returns the great circle distance in meters between two points.
+func geoDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := toRadians(lat1), toRadians(lat2)
+	dPhi := phi2 - phi1
+	dLambda := toRadians(lon2 - lon1)
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * geoEarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// geoInBox returns true if the point is within the box. A box with minLon
+// greater than maxLon crosses the antimeridian.
+func geoInBox(lat, lon, minLat, minLon, maxLat, maxLon float64) bool {
+	if lat < minLat || lat > maxLat {
+		return false
+	}
+	if minLon <= maxLon {
+		return lon >= minLon && lon <= maxLon
+	}
+	return lon >= minLon || lon <= maxLon
+}
+
+// geoCircleBox returns a box which contains every point within meters of the
+// point at lat, lon.
+func geoCircleBox(lat, lon, meters float64) (minLat, minLon, maxLat, maxLon float64) {
+	r := meters / geoEarthRadius
+	phi, lambda := toRadians(lat), toRadians(lon)
+	minPhi, maxPhi := phi-r, phi+r
+
+	// widen by a little so that rounding never excludes a point on the edge
+	const pad = 1e-9
+
+	// a circle around a pole includes every longitude
+	if minPhi <= -math.Pi/2 || maxPhi >= math.Pi/2 || math.Sin(r) >= math.Cos(phi) {
+		return math.Max(-90, toDegrees(minPhi)-pad), -180, math.Min(90, toDegrees(maxPhi)+pad), 180
+	}
+
+	dLambda := math.Asin(math.Sin(r) / math.Cos(phi))
+	minLambda, maxLambda := lambda-dLambda, lambda+dLambda
+	if minLambda < -math.Pi {
+		minLambda += 2 * math.Pi
+	}
+	if maxLambda > math.Pi {
+		maxLambda -= 2 * math.Pi
+	}
+
+	return toDegrees(minPhi) - pad, toDegrees(minLambda) - pad, toDegrees(maxPhi) + pad, toDegrees(maxLambda) + pad
+}
+
+// geoCellIndex returns the position of the cell at the given number of bits
+// holding a latitude or longitude, which ranges between -max and max.
+func geoCellIndex(deg, max float64, bits uint) uint64 {
+	n := uint64(1) << bits
+	i := math.Floor((deg + max) / (2 * max) * float64(n))
+	if i < 0 {
+		return 0
+	}
+	if uint64(i) >= n {
+		return n - 1
+	}
+	return uint64(i)
+}
+
+// geoCellKey returns the key of a cell. The cells of a point are stored in a
+// keyed set field, so that only the cells which hold points take up rows.
+func geoCellKey(bits uint, latIdx, lonIdx uint64) string {
+	return strconv.FormatUint(uint64(bits), 10) + ":" +
+		strconv.FormatUint(latIdx, 10) + ":" +
+		strconv.FormatUint(lonIdx, 10)
+}
+
+// geoPointCells returns the cells containing a point, one per level.
+func geoPointCells(lat, lon float64) []string {
+	cells := make([]string, len(geoCellBits))
+	for level, bits := range geoCellBits {
+		cells[level] = geoCellKey(bits, geoCellIndex(lat, 90, bits), geoCellIndex(lon, 180, bits))
+	}
+	return cells
+}
+
+// geoBoxCells returns a set of cells covering the box. The finest level
+// covering the box in at most geoMaxCoverCells cells is used, falling back to
+// the coarsest level for large boxes.
+func geoBoxCells(minLat, minLon, maxLat, maxLon float64) []string {
+	lonRanges := [][2]float64{{minLon, maxLon}}
+	if minLon > maxLon {
+		lonRanges = [][2]float64{{minLon, 180}, {-180, maxLon}}
+	}
+
+	cover := func(bits uint) []string {
+		lat0, lat1 := geoCellIndex(minLat, 90, bits), geoCellIndex(maxLat, 90, bits)
+		var cells []string
+		for _, lr := range lonRanges {
+			lon0, lon1 := geoCellIndex(lr[0], 180, bits), geoCellIndex(lr[1], 180, bits)
+			for i := lat0; i <= lat1; i++ {
+				for j := lon0; j <= lon1; j++ {
+					cells = append(cells, geoCellKey(bits, i, j))
+				}
+			}
+		}
+		return cells
+	}
+
+	count := func(bits uint) uint64 {
+		n := geoCellIndex(maxLat, 90, bits) - geoCellIndex(minLat, 90, bits) + 1
+		var m uint64
+		for _, lr := range lonRanges {
+			m += geoCellIndex(lr[1], 180, bits) - geoCellIndex(lr[0], 180, bits) + 1
+		}
+		return n * m
+	}
+
+	for level := len(geoCellBits) - 1; level > 0; level-- {
+		if count(geoCellBits[level]) <= geoMaxCoverCells {
+			return cover(geoCellBits[level])
+		}
+	}
+	return cover(geoCellBits[0])
+}
+
+// geoPointIndexer keeps the cell fields of geopoint columns up to date as
+// records are inserted.
+type geoPointIndexer struct {
+	// columns maps the position of each geopoint column in the row values to
+	// the position of its cell field.
+	columns map[int]int
+	fields  []string
+	ids     []interface{}
+}
+
+// newGeoPointIndexer returns an indexer for the geopoint columns among
+// fields, along with fields extended by their cell fields. The returned
+// indexer is nil if there are no geopoint columns.
+func newGeoPointIndexer(fields []*pilosa.FieldInfo) (*geoPointIndexer, []*pilosa.FieldInfo) {
+	var idx *geoPointIndexer
+	extended := fields
+	for pos, fld := range fields {
+		if fld == nil || !fld.Options.GeoPoint {
+			continue
+		}
+		if idx == nil {
+			idx = &geoPointIndexer{columns: make(map[int]int)}
+			extended = append([]*pilosa.FieldInfo{}, fields...)
+		}
+		name := pilosa.GeoCellFieldName(fld.Name)
+		idx.columns[pos] = len(extended)
+		idx.fields = append(idx.fields, name)
+		extended = append(extended, &pilosa.FieldInfo{
+			Name: name,
+			Options: pilosa.FieldOptions{
+				Type:      pilosa.FieldTypeSet,
+				Keys:      true,
+				CacheType: pilosa.CacheTypeNone,
+			},
+		})
+	}
+	return idx, extended
+}
+
+// setRow populates the cell fields from the packed geopoints in values, and
+// remembers the record so its previous cells can be cleared.
+func (x *geoPointIndexer) setRow(id interface{}, values []interface{}) {
+	x.ids = append(x.ids, id)
+	for pos, cellPos := range x.columns {
+		v, ok := values[pos].(int64)
+		if !ok {
+			values[cellPos] = nil
+			continue
+		}
+		values[cellPos] = geoPointCells(unpackGeoPoint(v))
+	}
+}
+
+// clear removes the cells of every record passed to setRow. Like indexed
+// JSON paths, cells live in a set field and must be cleared before the new
+// ones are imported.
+func (x *geoPointIndexer) clear(ctx context.Context, importer pilosa.Importer, tbl *dax.Table) error {
+	if err := clearRecordFields(ctx, importer, tbl, x.ids, x.fields); err != nil {
+		return errors.Wrap(err, "clearing geopoint cells")
+	}
+	x.ids = x.ids[:0]
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+)
+
+func (p *ExecutionPlanner) analyseFunctionHamming(call *parser.Call, scope parser.Statement) (parser.Expr, error) {
+	if len(call.Args) != 2 {
+		return nil, sql3.NewErrCallParameterCountMismatch(call.Rparen.Line, call.Rparen.Column, call.Name.Name, 2, len(call.Args))
+	}
+	for _, arg := range call.Args {
+		typ := arg.DataType()
+		if typeIsVoid(typ) {
+			continue
+		}
+		if !typeIsBitVector(typ) && !typeIsString(typ) {
+			return nil, sql3.NewErrBitVectorExpressionExpected(arg.Pos().Line, arg.Pos().Column)
+		}
+	}
+
+	call.ResultDataType = parser.NewDataTypeInt()
+
+	return call, nil
+}
+
+// EvaluateHamming returns the number of bits which differ between two
+// bitvectors.
+func (n *callPlanExpression) EvaluateHamming(currentRow []interface{}) (interface{}, error) {
+	vals := make([]string, 2)
+	for i, arg := range n.args {
+		eval, err := arg.Evaluate(currentRow)
+		if err != nil {
+			return nil, err
+		}
+		if eval == nil {
+			return nil, nil
+		}
+		s, ok := eval.(string)
+		if !ok {
+			return nil, sql3.NewErrUnexpectedTypeConversion(0, 0, eval)
+		}
+		vals[i] = s
+	}
+	return hammingDistance(vals[0], vals[1])
+}
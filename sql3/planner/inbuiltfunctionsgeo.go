@@ -0,0 +1,168 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+)
+
+// analyseGeoArgs checks the argument count of a geo function, that the
+// arguments at the positions in points are geopoints and the rest are numbers.
+func (p *ExecutionPlanner) analyseGeoArgs(call *parser.Call, count int, points ...int) error {
+	if len(call.Args) != count {
+		return sql3.NewErrCallParameterCountMismatch(call.Rparen.Line, call.Rparen.Column, call.Name.Name, count, len(call.Args))
+	}
+	isPoint := make(map[int]bool)
+	for _, i := range points {
+		isPoint[i] = true
+	}
+	for i, arg := range call.Args {
+		typ := arg.DataType()
+		if typeIsVoid(typ) {
+			continue
+		}
+		if isPoint[i] {
+			if !typeIsGeoPoint(typ) {
+				return sql3.NewErrGeoPointExpressionExpected(arg.Pos().Line, arg.Pos().Column)
+			}
+		} else if !typeIsNumeric(typ) {
+			return sql3.NewErrNumericExpressionExpected(arg.Pos().Line, arg.Pos().Column)
+		}
+	}
+	return nil
+}
+
+func (p *ExecutionPlanner) analyseFunctionSTPoint(call *parser.Call, scope parser.Statement) (parser.Expr, error) {
+	if err := p.analyseGeoArgs(call, 2); err != nil {
+		return nil, err
+	}
+
+	call.ResultDataType = parser.NewDataTypeGeoPoint()
+
+	return call, nil
+}
+
+func (p *ExecutionPlanner) analyseFunctionSTDistance(call *parser.Call, scope parser.Statement) (parser.Expr, error) {
+	if err := p.analyseGeoArgs(call, 2, 0, 1); err != nil {
+		return nil, err
+	}
+
+	call.ResultDataType = parser.NewDataTypeDouble()
+
+	return call, nil
+}
+
+func (p *ExecutionPlanner) analyseFunctionSTDWithin(call *parser.Call, scope parser.Statement) (parser.Expr, error) {
+	if err := p.analyseGeoArgs(call, 4, 0); err != nil {
+		return nil, err
+	}
+
+	call.ResultDataType = parser.NewDataTypeBool()
+
+	return call, nil
+}
+
+func (p *ExecutionPlanner) analyseFunctionSTWithinBox(call *parser.Call, scope parser.Statement) (parser.Expr, error) {
+	if err := p.analyseGeoArgs(call, 5, 0); err != nil {
+		return nil, err
+	}
+
+	call.ResultDataType = parser.NewDataTypeBool()
+
+	return call, nil
+}
+
+// evaluateGeoArgs evaluates the arguments of a geo function. Geopoint
+// arguments are returned as their latitude and longitude, so each returned
+// point takes two places. A null argument is reported as ok == false.
+func (n *callPlanExpression) evaluateGeoArgs(currentRow []interface{}) (args []float64, ok bool, err error) {
+	for _, arg := range n.args {
+		eval, err := arg.Evaluate(currentRow)
+		if err != nil {
+			return nil, false, err
+		}
+		if eval == nil {
+			return nil, false, nil
+		}
+		if typeIsGeoPoint(arg.Type()) {
+			lat, lon, err := geoPointFromValue(eval)
+			if err != nil {
+				return nil, false, err
+			}
+			args = append(args, lat, lon)
+			continue
+		}
+		f, err := geoFloat(eval)
+		if err != nil {
+			return nil, false, err
+		}
+		args = append(args, f)
+	}
+	return args, true, nil
+}
+
+// EvaluateSTPoint returns the geopoint at a latitude and longitude.
+func (n *callPlanExpression) EvaluateSTPoint(currentRow []interface{}) (interface{}, error) {
+	args, ok, err := n.evaluateGeoArgs(currentRow)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if !validGeoPoint(args[0], args[1]) {
+		return nil, sql3.NewErrInvalidGeoPoint(0, 0, args[0], args[1])
+	}
+	return []float64{args[0], args[1]}, nil
+}
+
+// EvaluateSTDistance returns the distance in meters between two geopoints.
+func (n *callPlanExpression) EvaluateSTDistance(currentRow []interface{}) (interface{}, error) {
+	args, ok, err := n.evaluateGeoArgs(currentRow)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return geoDistance(args[0], args[1], args[2], args[3]), nil
+}
+
+// EvaluateSTDWithin returns whether a geopoint is within a distance in meters
+// of a latitude and longitude.
+func (n *callPlanExpression) EvaluateSTDWithin(currentRow []interface{}) (interface{}, error) {
+	args, ok, err := n.evaluateGeoArgs(currentRow)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if !validGeoPoint(args[2], args[3]) {
+		return nil, sql3.NewErrInvalidGeoPoint(0, 0, args[2], args[3])
+	}
+	if args[4] < 0 {
+		return nil, sql3.NewErrValueOutOfRange(0, 0, args[4])
+	}
+	return geoDistance(args[0], args[1], args[2], args[3]) <= args[4], nil
+}
+
+// EvaluateSTWithinBox returns whether a geopoint is within the box given by
+// its minimum and maximum latitude and longitude. A minimum longitude greater
+// than the maximum describes a box crossing the antimeridian.
+func (n *callPlanExpression) EvaluateSTWithinBox(currentRow []interface{}) (interface{}, error) {
+	args, ok, err := n.evaluateGeoArgs(currentRow)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if err := validateGeoBox(args[2], args[3], args[4], args[5]); err != nil {
+		return nil, err
+	}
+	return geoInBox(args[0], args[1], args[2], args[3], args[4], args[5]), nil
+}
+
+// validateGeoBox checks the corners of a box.
+func validateGeoBox(minLat, minLon, maxLat, maxLon float64) error {
+	if !validGeoPoint(minLat, minLon) {
+		return sql3.NewErrInvalidGeoPoint(0, 0, minLat, minLon)
+	}
+	if !validGeoPoint(maxLat, maxLon) {
+		return sql3.NewErrInvalidGeoPoint(0, 0, maxLat, maxLon)
+	}
+	if minLat > maxLat {
+		return sql3.NewErrInvalidGeoBox(0, 0, minLat, maxLat)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+)
+
+// analyseJSONPathArgs checks the (document, path) arguments shared by the
+// json functions.
+func (p *ExecutionPlanner) analyseJSONPathArgs(call *parser.Call) error {
+	if len(call.Args) != 2 {
+		return sql3.NewErrCallParameterCountMismatch(call.Rparen.Line, call.Rparen.Column, call.Name.Name, 2, len(call.Args))
+	}
+
+	// the document can be a json column or a string holding json
+	if !typeIsJSON(call.Args[0].DataType()) && !typeIsString(call.Args[0].DataType()) && !typeIsVoid(call.Args[0].DataType()) {
+		return sql3.NewErrJSONExpressionExpected(call.Args[0].Pos().Line, call.Args[0].Pos().Column)
+	}
+
+	// path
+	if !typeIsString(call.Args[1].DataType()) && !typeIsVoid(call.Args[1].DataType()) {
+		return sql3.NewErrStringExpressionExpected(call.Args[1].Pos().Line, call.Args[1].Pos().Column)
+	}
+	// literal paths can be checked up front
+	if lit, ok := call.Args[1].(*parser.StringLit); ok {
+		if _, err := compileJSONPath(lit.Value); err != nil {
+			return sql3.NewErrInvalidJSONPath(lit.ValuePos.Line, lit.ValuePos.Column, lit.Value)
+		}
+	}
+	return nil
+}
+
+func (p *ExecutionPlanner) analyseFunctionJSONValue(call *parser.Call, scope parser.Statement) (parser.Expr, error) {
+	if err := p.analyseJSONPathArgs(call); err != nil {
+		return nil, err
+	}
+
+	call.ResultDataType = parser.NewDataTypeString()
+
+	return call, nil
+}
+
+func (p *ExecutionPlanner) analyseFunctionJSONExists(call *parser.Call, scope parser.Statement) (parser.Expr, error) {
+	if err := p.analyseJSONPathArgs(call); err != nil {
+		return nil, err
+	}
+
+	call.ResultDataType = parser.NewDataTypeBool()
+
+	return call, nil
+}
+
+// evaluateJSONPathArgs evaluates the document and path arguments and returns
+// the value at the path. A nil document or path is reported as ok == false.
+func (n *callPlanExpression) evaluateJSONPathArgs(currentRow []interface{}) (value interface{}, found bool, ok bool, err error) {
+	docEval, err := n.args[0].Evaluate(currentRow)
+	if err != nil {
+		return nil, false, false, err
+	}
+	pathEval, err := n.args[1].Evaluate(currentRow)
+	if err != nil {
+		return nil, false, false, err
+	}
+	if docEval == nil || pathEval == nil {
+		return nil, false, false, nil
+	}
+	doc, isString := docEval.(string)
+	if !isString {
+		return nil, false, false, sql3.NewErrUnexpectedTypeConversion(0, 0, docEval)
+	}
+	path, isString := pathEval.(string)
+	if !isString {
+		return nil, false, false, sql3.NewErrUnexpectedTypeConversion(0, 0, pathEval)
+	}
+	eval, err := compileJSONPath(path)
+	if err != nil {
+		return nil, false, false, sql3.NewErrInvalidJSONPath(0, 0, path)
+	}
+	value, found, err = evalJSONPath(context.Background(), doc, eval)
+	if err != nil {
+		return nil, false, false, err
+	}
+	return value, found, true, nil
+}
+
+// EvaluateJSONValue returns the scalar found at a path in a json document as
+// a string, or null if the path is absent or selects an object or array.
+func (n *callPlanExpression) EvaluateJSONValue(currentRow []interface{}) (interface{}, error) {
+	value, found, ok, err := n.evaluateJSONPathArgs(currentRow)
+	if err != nil || !ok || !found {
+		return nil, err
+	}
+	s, ok := jsonScalarString(value)
+	if !ok {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// EvaluateJSONExists returns whether a path is present in a json document.
+func (n *callPlanExpression) EvaluateJSONExists(currentRow []interface{}) (interface{}, error) {
+	_, found, ok, err := n.evaluateJSONPathArgs(currentRow)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return found, nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"strings"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+	"github.com/pkg/errors"
+)
+
+// insertConflict says what an INSERT does with rows whose _id is already in
+// the table, which otherwise have their values merged into the record.
+type insertConflict struct {
+	// replace clears all of an existing record's values before the row is
+	// written (REPLACE INTO).
+	replace bool
+
+	// doNothing skips the row (ON CONFLICT DO NOTHING).
+	doNothing bool
+
+	// updateColumns are written with updateValues instead of the row (ON
+	// CONFLICT DO UPDATE SET). The values are evaluated against the row as
+	// it was proposed, which is what excluded.<column> refers to.
+	updateColumns []*qualifiedRefPlanExpression
+	updateValues  []types.PlanExpression
+
+	// clearFields are the set columns among updateColumns, whose existing
+	// values have to be cleared for the update to replace them.
+	clearFields []string
+}
+
+// split divides tuples, the values of targetColumns, into those whose _id is
+// new to tbl and those whose _id is already there. The row numbers of each
+// are split along with them.
+func (c *insertConflict) split(ctx context.Context, p *ExecutionPlanner, tbl *dax.Table, targetColumns []*qualifiedRefPlanExpression, tuples [][]types.PlanExpression, rowNumbers []int) (newTuples [][]types.PlanExpression, newNumbers []int, conflicting [][]types.PlanExpression, conflictingNumbers []int, err error) {
+	posID := idColumnPosition(targetColumns)
+	ids := make([]interface{}, len(tuples))
+	for n, tuple := range tuples {
+		id, err := tuple[posID].Evaluate(nil)
+		if err != nil {
+			return nil, nil, nil, nil, errors.Wrapf(err, "evaluating record id: %v", tuple[posID])
+		}
+		switch v := id.(type) {
+		case int64:
+			if v >= 0 {
+				id = uint64(v)
+			}
+		case []byte:
+			id = string(v)
+		}
+		ids[n] = id
+	}
+
+	existing, err := p.existingRecords(ctx, tbl, ids)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	for n, tuple := range tuples {
+		if _, ok := existing[ids[n]]; ok {
+			conflicting = append(conflicting, tuple)
+			conflictingNumbers = append(conflictingNumbers, rowNumbers[n])
+			continue
+		}
+		newTuples = append(newTuples, tuple)
+		newNumbers = append(newNumbers, rowNumbers[n])
+	}
+	return newTuples, newNumbers, conflicting, conflictingNumbers, nil
+}
+
+// updateTuples returns the target columns and tuples which write the DO
+// UPDATE SET values for each of the conflicting tuples.
+func (c *insertConflict) updateTuples(targetColumns []*qualifiedRefPlanExpression, conflicting [][]types.PlanExpression) ([]*qualifiedRefPlanExpression, [][]types.PlanExpression, error) {
+	posID := idColumnPosition(targetColumns)
+	columns := append([]*qualifiedRefPlanExpression{targetColumns[posID]}, c.updateColumns...)
+
+	updates := make([][]types.PlanExpression, 0, len(conflicting))
+	for _, tuple := range conflicting {
+		proposed := make([]interface{}, len(tuple))
+		for j, expr := range tuple {
+			v, err := expr.Evaluate(nil)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "evaluating tuple value: %v", expr)
+			}
+			proposed[j] = v
+		}
+		update := make([]types.PlanExpression, 0, len(columns))
+		update = append(update, tuple[posID])
+		for _, expr := range c.updateValues {
+			update = append(update, &boundPlanExpression{PlanExpression: expr, row: proposed})
+		}
+		updates = append(updates, update)
+	}
+	return columns, updates, nil
+}
+
+// updateClearer returns a clearer for the set columns being updated, or nil
+// if there are none.
+func (c *insertConflict) updateClearer() *recordClearer {
+	if len(c.clearFields) == 0 {
+		return nil
+	}
+	return &recordClearer{fields: c.clearFields}
+}
+
+// existingRecords returns those of ids which are records in tbl.
+func (p *ExecutionPlanner) existingRecords(ctx context.Context, tbl *dax.Table, ids []interface{}) (map[interface{}]struct{}, error) {
+	existing := make(map[interface{}]struct{})
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	call := &pql.Call{
+		Name: "ConstRow",
+		Args: map[string]interface{}{
+			"columns": ids,
+		},
+		Type: pql.PrecallGlobal,
+	}
+	resp, err := p.executor.Execute(ctx, tbl, &pql.Query{Calls: []*pql.Call{call}}, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding existing records")
+	}
+	if len(resp.Results) != 1 {
+		return nil, sql3.NewErrInternalf("unexpected result count %d", len(resp.Results))
+	}
+	row, ok := resp.Results[0].(*pilosa.Row)
+	if !ok {
+		return nil, sql3.NewErrInternalf("unexpected result type '%T'", resp.Results[0])
+	}
+	if tbl.StringKeys() {
+		for _, k := range row.Keys {
+			existing[k] = struct{}{}
+		}
+	} else {
+		for _, id := range row.Columns() {
+			existing[id] = struct{}{}
+		}
+	}
+	return existing, nil
+}
+
+// idColumnPosition returns the position of the _id column in targetColumns.
+func idColumnPosition(targetColumns []*qualifiedRefPlanExpression) int {
+	for j, col := range targetColumns {
+		if strings.EqualFold(col.columnName, string(dax.PrimaryKeyFieldName)) {
+			return j
+		}
+	}
+	return 0
+}
+
+// boundPlanExpression evaluates an expression against a fixed row, whatever
+// row it's given.
+type boundPlanExpression struct {
+	types.PlanExpression
+	row []interface{}
+}
+
+func (e *boundPlanExpression) Evaluate(currentRow []interface{}) (interface{}, error) {
+	return e.PlanExpression.Evaluate(e.row)
+}
+
+// recordClearer remembers the records being replaced, so that their existing
+// values can be cleared before the new ones are imported.
+type recordClearer struct {
+	fields []string
+	ids    []interface{}
+}
+
+// newReplaceClearer returns a clearer for all of the records' values. An
+// update with no field clears the records from every field.
+func newReplaceClearer() *recordClearer {
+	return &recordClearer{fields: []string{""}}
+}
+
+func (c *recordClearer) addRow(id interface{}) {
+	c.ids = append(c.ids, id)
+}
+
+func (c *recordClearer) clear(ctx context.Context, importer pilosa.Importer, tbl *dax.Table) error {
+	if err := clearRecordFields(ctx, importer, tbl, c.ids, c.fields); err != nil {
+		return errors.Wrap(err, "clearing existing records")
+	}
+	c.ids = c.ids[:0]
+	return nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/pkg/errors"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+)
+
+// compileJSONPath compiles a path such as '$.a.b' or '$.tags[0]'. Only paths
+// rooted at the document ('$') are accepted.
+func compileJSONPath(path string) (gval.Evaluable, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, errors.Errorf("path must start with '$'")
+	}
+	return jsonpath.New(path)
+}
+
+// normalizeJSON checks that doc is a JSON document no longer, once compacted,
+// than pilosa.MaxJSONDocumentLength, and returns it compacted.
+func normalizeJSON(doc string) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(doc)); err != nil {
+		return "", sql3.NewErrParsingJSON(0, 0, doc, err.Error())
+	}
+	if buf.Len() > pilosa.MaxJSONDocumentLength {
+		return "", sql3.NewErrJSONDocumentTooLong(0, 0, buf.Len(), pilosa.MaxJSONDocumentLength)
+	}
+	return buf.String(), nil
+}
+
+// evalJSONPath returns the value found at path in doc. The boolean is false
+// if the path does not exist in the document.
+func evalJSONPath(ctx context.Context, doc string, path gval.Evaluable) (interface{}, bool, error) {
+	var v interface{}
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, false, sql3.NewErrParsingJSON(0, 0, doc, err.Error())
+	}
+	// evaluation fails for unknown keys, out of range indexes and for
+	// selecting into scalars; all of which mean the path is absent
+	result, err := path(ctx, v)
+	if err != nil {
+		return nil, false, nil
+	}
+	return result, true, nil
+}
+
+// jsonScalarString returns the string form of a JSON scalar. The boolean is
+// false for null, objects and arrays.
+func jsonScalarString(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		if v {
+			return "true", true
+		}
+		return "false", true
+	default:
+		return "", false
+	}
+}
+
+// jsonPathKeys returns the keys under which a value found at an indexed path
+// is stored. Only scalars are indexed, matching what JSON_VALUE returns, so
+// that a filter gives the same result whether or not it is pushed down.
+func jsonPathKeys(v interface{}) []string {
+	if s, ok := jsonScalarString(v); ok {
+		return []string{s}
+	}
+	return nil
+}
+
+// jsonColumn is a JSON column written by an insert, along with the fields
+// which index its paths.
+type jsonColumn struct {
+	// pos is the position of the JSON column in the row values, and first
+	// the position of the field for its first indexed path.
+	pos   int
+	first int
+
+	name   string
+	paths  []gval.Evaluable
+	fields []string
+}
+
+// jsonColumnWriter turns the documents written to JSON columns into the rows
+// which store them, and keeps the fields which index their paths up to date.
+type jsonColumnWriter struct {
+	columns []jsonColumn
+}
+
+// newJSONColumnWriter returns a writer for the JSON columns among fields,
+// along with fields extended by the fields for their indexed paths. The
+// returned writer is nil if none of the fields is a JSON column.
+func newJSONColumnWriter(fields []*pilosa.FieldInfo) (*jsonColumnWriter, []*pilosa.FieldInfo, error) {
+	var w *jsonColumnWriter
+	extended := fields
+	for pos, fld := range fields {
+		if fld == nil || !fld.Options.JSON {
+			continue
+		}
+		if w == nil {
+			w = &jsonColumnWriter{}
+			extended = append([]*pilosa.FieldInfo{}, fields...)
+		}
+		col := jsonColumn{
+			pos:   pos,
+			first: len(extended),
+			name:  fld.Name,
+		}
+		for _, path := range fld.Options.JSONPaths {
+			eval, err := compileJSONPath(path)
+			if err != nil {
+				return nil, nil, sql3.NewErrInvalidJSONPath(0, 0, path)
+			}
+			name := pilosa.JSONPathFieldName(fld.Name, path)
+			col.paths = append(col.paths, eval)
+			col.fields = append(col.fields, name)
+			extended = append(extended, &pilosa.FieldInfo{
+				Name: name,
+				Options: pilosa.FieldOptions{
+					Type:           pilosa.FieldTypeSet,
+					Keys:           true,
+					CacheType:      pilosa.DefaultCacheType,
+					CacheSize:      pilosa.DefaultCacheSize,
+					TrackExistence: true,
+				},
+			})
+		}
+		w.columns = append(w.columns, col)
+	}
+	return w, extended, nil
+}
+
+// setRow populates the values of the path fields from the JSON documents in
+// values, and replaces each document with the rows which store it.
+func (w *jsonColumnWriter) setRow(ctx context.Context, values []interface{}) error {
+	for _, col := range w.columns {
+		doc, ok := values[col.pos].(string)
+		for i, path := range col.paths {
+			if !ok {
+				values[col.first+i] = nil
+				continue
+			}
+			v, found, err := evalJSONPath(ctx, doc, path)
+			if err != nil {
+				return err
+			}
+			if !found {
+				values[col.first+i] = nil
+				continue
+			}
+			values[col.first+i] = jsonPathKeys(v)
+		}
+		if ok {
+			values[col.pos] = pilosa.JSONDocumentRows(doc)
+		}
+	}
+	return nil
+}
+
+// replacedFields returns the fields written by w, whose values replace the
+// ones their records held. The batch clears each record's old document and
+// path values in the same request as it sets the new ones, since set fields
+// otherwise add to, rather than replace, the values of a record.
+func (w *jsonColumnWriter) replacedFields() []string {
+	var fields []string
+	for _, col := range w.columns {
+		fields = append(fields, col.name)
+		fields = append(fields, col.fields...)
+	}
+	return fields
+}
+
+// jsonFromRows returns the JSON document held in rows, or nil if the record
+// has none.
+func jsonFromRows(rows []uint64) (interface{}, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	doc, err := pilosa.JSONDocumentFromRows(rows)
+	if err != nil {
+		return nil, sql3.NewErrInternalf("%s", err.Error())
+	}
+	return doc, nil
+}
@@ -8,7 +8,6 @@ import (
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/dax"
-	"github.com/featurebasedb/featurebase/v3/sql3"
 	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
 )
 
@@ -69,6 +68,7 @@ func (p *PlanOpAlterTable) Iterator(ctx context.Context, row types.Row) (types.R
 		tableName:     p.tableName,
 		columnDef:     p.columnDef,
 		oldColumnName: p.oldColumnName,
+		newColumnName: p.newColumnName,
 	}, nil
 }
 
@@ -82,6 +82,7 @@ type alterTableRowIter struct {
 	tableName     string
 	columnDef     *createTableField
 	oldColumnName string
+	newColumnName string
 }
 
 var _ types.RowIterator = (*alterTableRowIter)(nil)
@@ -111,7 +112,19 @@ func (i *alterTableRowIter) Next(ctx context.Context) (types.Row, error) {
 		}
 
 	case alterOpRename:
-		return nil, sql3.NewErrInternal("column rename is unimplemented")
+		err := i.planner.fieldAlterer.RenameField(ctx, dax.TableName(i.tableName), dax.FieldName(i.oldColumnName), dax.FieldName(i.newColumnName))
+		if err != nil {
+			return nil, err
+		}
+
+	case alterOpAlter:
+		fld, err := pilosa.FieldFromFieldOptions(dax.FieldName(i.columnDef.name), i.columnDef.fos...)
+		if err != nil {
+			return nil, err
+		}
+		if err := i.planner.fieldAlterer.AlterField(ctx, dax.TableName(i.tableName), fld); err != nil {
+			return nil, err
+		}
 
 	}
 	return nil, types.ErrNoMoreRows
@@ -23,6 +23,7 @@ import (
 	"github.com/apache/arrow/go/v10/parquet/file"
 	"github.com/apache/arrow/go/v10/parquet/pqarrow"
 	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
 	"github.com/featurebasedb/featurebase/v3/pql"
 	"github.com/featurebasedb/featurebase/v3/sql3"
 	"github.com/featurebasedb/featurebase/v3/sql3/parser"
@@ -51,10 +52,17 @@ type bulkInsertOptions struct {
 	allowMissingValues bool
 	// input specifier (FILE is the only one right now)
 	input string
+	// whether existing records are cleared before they're written (BULK
+	// REPLACE)
+	replace bool
 
 	// target columns
 	targetColumns []*qualifiedRefPlanExpression
 
+	// the primary key columns of the table, whose values make up the _id of
+	// each row
+	primaryKey []dax.FieldName
+
 	// transformations
 	transformExpressions []types.PlanExpression
 
@@ -329,7 +337,7 @@ func (i *bulkInsertSourceCSVRowIter) Next(ctx context.Context) (types.Row, error
 				result[idx] = time.Unix(intVal, 0).UTC()
 			}
 
-		case *parser.DataTypeString:
+		case *parser.DataTypeString, *parser.DataTypeJSON, *parser.DataTypeBitVector:
 			result[idx] = evalValue
 
 		case *parser.DataTypeBool:
@@ -346,6 +354,20 @@ func (i *bulkInsertSourceCSVRowIter) Next(ctx context.Context) (types.Row, error
 			}
 			result[idx] = dval
 
+		case *parser.DataTypeDouble:
+			fval, err := strconv.ParseFloat(evalValue, 64)
+			if err != nil {
+				return nil, sql3.NewErrTypeConversionOnMap(0, 0, evalValue, mapColumn.colType.TypeDescription())
+			}
+			result[idx] = fval
+
+		case *parser.DataTypeGeoPoint:
+			point, err := parseGeoPointString(evalValue)
+			if err != nil {
+				return nil, sql3.NewErrTypeConversionOnMap(0, 0, evalValue, mapColumn.colType.TypeDescription())
+			}
+			result[idx] = point
+
 		default:
 			return nil, sql3.NewErrInternalf("unhandled type '%T'", mapColumn.colType)
 		}
@@ -394,7 +416,7 @@ func (i *bulkInsertLineRowIter) Next(ctx context.Context) (types.Row, error) {
 		}
 		i.currentBatch = append(i.currentBatch, row)
 		if len(i.currentBatch) >= i.options.batchSize {
-			err := processBatch(ctx, i.planner, i.tableName, i.currentBatch, i.options)
+			err := processBatch(ctx, i.planner, i.tableName, i.currentBatch, i.linesRead-len(i.currentBatch), i.options)
 			if err != nil {
 				return nil, err
 			}
@@ -407,7 +429,7 @@ func (i *bulkInsertLineRowIter) Next(ctx context.Context) (types.Row, error) {
 		}
 	}
 	if len(i.currentBatch) > 0 {
-		err := processBatch(ctx, i.planner, i.tableName, i.currentBatch, i.options)
+		err := processBatch(ctx, i.planner, i.tableName, i.currentBatch, i.linesRead-len(i.currentBatch), i.options)
 		if err != nil {
 			return nil, err
 		}
@@ -679,7 +701,15 @@ func (i *bulkInsertSourceNDJsonRowIter) Next(ctx context.Context) (types.Row, er
 						return nil, sql3.NewErrInternalf("unhandled type '%T'", evalValue)
 					}
 
-				case *parser.DataTypeString:
+				case *parser.DataTypeJSON:
+					// whatever the path selects becomes the document
+					doc, err := json.Marshal(evalValue)
+					if err != nil {
+						return nil, sql3.NewErrTypeConversionOnMap(0, 0, evalValue, mapColumn.colType.TypeDescription())
+					}
+					result[idx] = string(doc)
+
+				case *parser.DataTypeString, *parser.DataTypeBitVector:
 					switch v := evalValue.(type) {
 					case json.Number:
 						result[idx] = v.String()
@@ -756,6 +786,65 @@ func (i *bulkInsertSourceNDJsonRowIter) Next(ctx context.Context) (types.Row, er
 						return nil, sql3.NewErrInternalf("unhandled type '%T'", evalValue)
 					}
 
+				case *parser.DataTypeDouble:
+					switch v := evalValue.(type) {
+					case json.Number:
+						f, err := v.Float64()
+						if err != nil {
+							return nil, sql3.NewErrTypeConversionOnMap(0, 0, v, mapColumn.colType.TypeDescription())
+						}
+						result[idx] = f
+
+					case string:
+						// try to parse from a string
+						f, err := strconv.ParseFloat(v, 64)
+						if err != nil {
+							return nil, sql3.NewErrTypeConversionOnMap(0, 0, v, mapColumn.colType.TypeDescription())
+						}
+						result[idx] = f
+
+					case []interface{}, bool, interface{}:
+						return nil, sql3.NewErrTypeConversionOnMap(0, 0, v, mapColumn.colType.TypeDescription())
+
+					default:
+						return nil, sql3.NewErrInternalf("unhandled type '%T'", evalValue)
+					}
+
+				case *parser.DataTypeGeoPoint:
+					switch v := evalValue.(type) {
+					case []interface{}:
+						// [latitude, longitude]
+						if len(v) != 2 {
+							return nil, sql3.NewErrTypeConversionOnMap(0, 0, v, mapColumn.colType.TypeDescription())
+						}
+						point := make([]float64, 2)
+						for i, m := range v {
+							n, ok := m.(json.Number)
+							if !ok {
+								return nil, sql3.NewErrTypeConversionOnMap(0, 0, v, mapColumn.colType.TypeDescription())
+							}
+							f, err := n.Float64()
+							if err != nil {
+								return nil, sql3.NewErrTypeConversionOnMap(0, 0, v, mapColumn.colType.TypeDescription())
+							}
+							point[i] = f
+						}
+						result[idx] = point
+
+					case string:
+						point, err := parseGeoPointString(v)
+						if err != nil {
+							return nil, sql3.NewErrTypeConversionOnMap(0, 0, v, mapColumn.colType.TypeDescription())
+						}
+						result[idx] = point
+
+					case json.Number, bool, interface{}:
+						return nil, sql3.NewErrTypeConversionOnMap(0, 0, v, mapColumn.colType.TypeDescription())
+
+					default:
+						return nil, sql3.NewErrInternalf("unhandled type '%T'", evalValue)
+					}
+
 				default:
 					return nil, sql3.NewErrInternalf("unhandled type '%T'", mapColumn.colType)
 				}
@@ -802,7 +891,7 @@ func (i *bulkInsertNDJsonRowIter) Next(ctx context.Context) (types.Row, error) {
 		}
 		i.currentBatch = append(i.currentBatch, row)
 		if len(i.currentBatch) >= i.options.batchSize {
-			err := processBatch(ctx, i.planner, i.tableName, i.currentBatch, i.options)
+			err := processBatch(ctx, i.planner, i.tableName, i.currentBatch, i.linesRead-len(i.currentBatch), i.options)
 			if err != nil {
 				return nil, err
 			}
@@ -815,7 +904,7 @@ func (i *bulkInsertNDJsonRowIter) Next(ctx context.Context) (types.Row, error) {
 		}
 	}
 	if len(i.currentBatch) > 0 {
-		err := processBatch(ctx, i.planner, i.tableName, i.currentBatch, i.options)
+		err := processBatch(ctx, i.planner, i.tableName, i.currentBatch, i.linesRead-len(i.currentBatch), i.options)
 		if err != nil {
 			return nil, err
 		}
@@ -869,7 +958,7 @@ func processColumnValue(rawValue interface{}, targetType parser.ExprDataType) (t
 		}
 		return newTimestampLiteralPlanExpression(tval), nil
 
-	case *parser.DataTypeString:
+	case *parser.DataTypeString, *parser.DataTypeJSON, *parser.DataTypeBitVector:
 		sval, ok := rawValue.(string)
 		if !ok {
 			return nil, sql3.NewErrInternalf("unable to convert '%s", rawValue)
@@ -890,12 +979,28 @@ func processColumnValue(rawValue interface{}, targetType parser.ExprDataType) (t
 		}
 		return newFloatLiteralPlanExpression(fmt.Sprintf("%f", dval.Float64())), nil
 
+	case *parser.DataTypeDouble:
+		fval, ok := rawValue.(float64)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unable to convert '%s", rawValue)
+		}
+		return newDoubleLiteralPlanExpression(fval), nil
+
+	case *parser.DataTypeGeoPoint:
+		point, ok := rawValue.([]float64)
+		if !ok || len(point) != 2 {
+			return nil, sql3.NewErrInternalf("unable to convert '%s", rawValue)
+		}
+		return newGeoPointLiteralPlanExpression(point[0], point[1]), nil
+
 	default:
 		return nil, sql3.NewErrInternalf("unhandled type '%T'", targetType)
 	}
 }
 
-func processBatch(ctx context.Context, planner *ExecutionPlanner, tableName string, currentBatch [][]interface{}, options *bulkInsertOptions) error {
+// processBatch inserts the rows in currentBatch. rowOffset is the number of
+// rows which were read before the batch.
+func processBatch(ctx context.Context, planner *ExecutionPlanner, tableName string, currentBatch [][]interface{}, rowOffset int, options *bulkInsertOptions) error {
 	insertValues := [][]types.PlanExpression{}
 
 	// we're going to take a different path if transforms are specified
@@ -926,6 +1031,9 @@ func processBatch(ctx context.Context, planner *ExecutionPlanner, tableName stri
 				}
 				tupleValues = append(tupleValues, tupleExpr)
 			}
+			for len(tupleValues) < len(options.targetColumns) {
+				tupleValues = append(tupleValues, newNullLiteralPlanExpression())
+			}
 			insertValues = append(insertValues, tupleValues)
 		}
 	} else {
@@ -944,15 +1052,31 @@ func processBatch(ctx context.Context, planner *ExecutionPlanner, tableName stri
 				}
 				tupleValues = append(tupleValues, tupleExpr)
 			}
+			for len(tupleValues) < len(options.targetColumns) {
+				tupleValues = append(tupleValues, newNullLiteralPlanExpression())
+			}
 			insertValues = append(insertValues, tupleValues)
 		}
 	}
 
+	targetColumns := options.targetColumns
+	if len(options.primaryKey) > 0 {
+		var err error
+		targetColumns, insertValues, err = addCompositeKeyColumn(tableName, options.primaryKey, targetColumns, insertValues)
+		if err != nil {
+			return err
+		}
+	}
+
 	insert := &insertRowIter{
 		planner:       planner,
 		tableName:     tableName,
-		targetColumns: options.targetColumns,
+		targetColumns: targetColumns,
 		insertValues:  insertValues,
+		rowOffset:     rowOffset,
+	}
+	if options.replace {
+		insert.conflict = &insertConflict{replace: true}
 	}
 
 	_, err := insert.Next(ctx)
@@ -1162,7 +1286,7 @@ func (i *bulkInsertSourceParquetRowIter) Next(ctx context.Context) (types.Row, e
 				}
 			}
 
-		case *parser.DataTypeString:
+		case *parser.DataTypeString, *parser.DataTypeJSON, *parser.DataTypeBitVector:
 			if stringVal, ok := evalValue.(string); ok {
 				result[idx] = stringVal
 			} else {
@@ -1181,6 +1305,25 @@ func (i *bulkInsertSourceParquetRowIter) Next(ctx context.Context) (types.Row, e
 			} else {
 				return nil, sql3.NewErrTypeConversionOnMap(0, 0, evalValue, mapColumn.colType.TypeDescription())
 			}
+		case *parser.DataTypeDouble:
+			switch v := evalValue.(type) {
+			case float64:
+				result[idx] = v
+			case float32:
+				result[idx] = float64(v)
+			default:
+				return nil, sql3.NewErrTypeConversionOnMap(0, 0, evalValue, mapColumn.colType.TypeDescription())
+			}
+		case *parser.DataTypeGeoPoint:
+			stringVal, ok := evalValue.(string)
+			if !ok {
+				return nil, sql3.NewErrTypeConversionOnMap(0, 0, evalValue, mapColumn.colType.TypeDescription())
+			}
+			point, err := parseGeoPointString(stringVal)
+			if err != nil {
+				return nil, sql3.NewErrTypeConversionOnMap(0, 0, evalValue, mapColumn.colType.TypeDescription())
+			}
+			result[idx] = point
 		default:
 			return nil, sql3.NewErrInternalf("unhandled type '%T'", mapColumn.colType)
 		}
@@ -196,7 +196,21 @@ func (i *copyIterator) Next(ctx context.Context) (types.Row, error) {
 					}
 					irow[i] = newFloatLiteralPlanExpression(val.String())
 
-				case *parser.DataTypeString:
+				case *parser.DataTypeDouble:
+					val, ok := row[i].(float64)
+					if !ok {
+						return nil, sql3.NewErrInternalf("unexpected type '%T'", row[i])
+					}
+					irow[i] = newDoubleLiteralPlanExpression(val)
+
+				case *parser.DataTypeGeoPoint:
+					val, ok := row[i].([]float64)
+					if !ok || len(val) != 2 {
+						return nil, sql3.NewErrInternalf("unexpected type '%T'", row[i])
+					}
+					irow[i] = newGeoPointLiteralPlanExpression(val[0], val[1])
+
+				case *parser.DataTypeString, *parser.DataTypeBitVector:
 					val, ok := row[i].(string)
 					if !ok {
 						return nil, sql3.NewErrInternalf("unexpected type '%T'", row[i])
@@ -411,7 +425,7 @@ func (i *remoteCopyIterator) Next(ctx context.Context) (types.Row, error) {
 					}
 					fmt.Fprintf(&rowBuf, "%d", val)
 
-				case *parser.DataTypeString:
+				case *parser.DataTypeString, *parser.DataTypeBitVector:
 					val, ok := row[i].(string)
 					if !ok {
 						return nil, sql3.NewErrInternalf("unexpected type '%T'", row[i])
@@ -5,6 +5,7 @@ package planner
 import (
 	"context"
 	"fmt"
+	"time"
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/dax"
@@ -21,12 +22,21 @@ type PlanOpCreateTable struct {
 	isKeyed       bool
 	keyPartitions int
 	description   string
+	retention     *tableRetention
+	primaryKey    []dax.FieldName
 	columns       []*createTableField
 	warnings      []string
 }
 
+// tableRetention is the retention policy of a table; records whose value in
+// column is older than period are deleted.
+type tableRetention struct {
+	column string
+	period time.Duration
+}
+
 // NewPlanOpCreateTable returns a new PlanOpCreateTable planoperator
-func NewPlanOpCreateTable(p *ExecutionPlanner, tableName string, failIfExists bool, isKeyed bool, keyPartitions int, description string, columns []*createTableField) *PlanOpCreateTable {
+func NewPlanOpCreateTable(p *ExecutionPlanner, tableName string, failIfExists bool, isKeyed bool, keyPartitions int, description string, retention *tableRetention, primaryKey []dax.FieldName, columns []*createTableField) *PlanOpCreateTable {
 	return &PlanOpCreateTable{
 		planner:       p,
 		tableName:     tableName,
@@ -35,6 +45,8 @@ func NewPlanOpCreateTable(p *ExecutionPlanner, tableName string, failIfExists bo
 		keyPartitions: keyPartitions,
 		columns:       columns,
 		description:   description,
+		retention:     retention,
+		primaryKey:    primaryKey,
 		warnings:      make([]string, 0),
 	}
 }
@@ -76,6 +88,8 @@ func (p *PlanOpCreateTable) Iterator(ctx context.Context, row types.Row) (types.
 		keyPartitions: p.keyPartitions,
 		columns:       p.columns,
 		description:   p.description,
+		retention:     p.retention,
+		primaryKey:    p.primaryKey,
 	}, nil
 }
 
@@ -90,6 +104,8 @@ type createTableRowIter struct {
 	isKeyed       bool
 	keyPartitions int
 	description   string
+	retention     *tableRetention
+	primaryKey    []dax.FieldName
 	columns       []*createTableField
 }
 
@@ -129,6 +145,11 @@ func (i *createTableRowIter) Next(ctx context.Context) (types.Row, error) {
 
 		Description: i.description,
 	}
+	if i.retention != nil {
+		tbl.RetentionField = dax.FieldName(i.retention.column)
+		tbl.RetentionPeriod = i.retention.period
+	}
+	tbl.PrimaryKey = i.primaryKey
 
 	if err := i.planner.schemaAPI.CreateTable(ctx, tbl); err != nil {
 		if _, ok := errors.Cause(err).(pilosa.ConflictError); ok {
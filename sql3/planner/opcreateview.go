@@ -111,6 +111,18 @@ func (i *createViewIter) Next(ctx context.Context) (types.Row, error) {
 		return nil, sql3.NewErrViewExists(0, 0, i.view.name)
 	}
 
+	// and that it isn't the name of a materialized view
+	mv, err := i.planner.getMaterializedViewByName(ctx, i.view.name)
+	if err != nil {
+		return nil, err
+	}
+	if mv != nil {
+		if i.ifNotExists {
+			return nil, types.ErrNoMoreRows
+		}
+		return nil, sql3.NewErrViewExists(0, 0, i.view.name)
+	}
+
 	// now store the view into fb_views
 	err = i.planner.insertView(ctx, i.view)
 	if err != nil {
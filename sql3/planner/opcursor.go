@@ -0,0 +1,282 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"fmt"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	fbcontext "github.com/featurebasedb/featurebase/v3/context"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+	uuid "github.com/satori/go.uuid"
+)
+
+// PlanOpDeclareCursor plan operator to declare a cursor over a query.
+type PlanOpDeclareCursor struct {
+	planner    *ExecutionPlanner
+	cursorName string
+	sql        string
+	query      types.PlanOperator
+	warnings   []string
+}
+
+func NewPlanOpDeclareCursor(p *ExecutionPlanner, cursorName string, sql string, query types.PlanOperator) *PlanOpDeclareCursor {
+	return &PlanOpDeclareCursor{
+		planner:    p,
+		cursorName: cursorName,
+		sql:        sql,
+		query:      query,
+		warnings:   make([]string, 0),
+	}
+}
+
+func (p *PlanOpDeclareCursor) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["cursorName"] = p.cursorName
+	result["query"] = p.query.Plan()
+	return result
+}
+
+func (p *PlanOpDeclareCursor) String() string {
+	return ""
+}
+
+func (p *PlanOpDeclareCursor) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpDeclareCursor) Warnings() []string {
+	var w []string
+	w = append(w, p.warnings...)
+	w = append(w, p.query.Warnings()...)
+	return w
+}
+
+func (p *PlanOpDeclareCursor) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpDeclareCursor) Children() []types.PlanOperator {
+	return []types.PlanOperator{}
+}
+
+func (p *PlanOpDeclareCursor) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	return &declareCursorRowIter{
+		planner:    p.planner,
+		cursorName: p.cursorName,
+		sql:        p.sql,
+		query:      p.query,
+	}, nil
+}
+
+func (p *PlanOpDeclareCursor) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	if len(children) != 0 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return p, nil
+}
+
+type declareCursorRowIter struct {
+	planner    *ExecutionPlanner
+	cursorName string
+	sql        string
+	query      types.PlanOperator
+}
+
+var _ types.RowIterator = (*declareCursorRowIter)(nil)
+
+func (i *declareCursorRowIter) Next(ctx context.Context) (types.Row, error) {
+	// the cursor's query is a request of its own, separate from this one
+	requestID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	ctx = fbcontext.WithRequestID(ctx, requestID.String())
+
+	cursor, err := pilosa.NewCursor(ctx, i.cursorName, i.sql, i.query.Schema(), func(ctx context.Context) (types.RowIterator, error) {
+		return i.query.Iterator(ctx, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := i.planner.systemLayerAPI.Cursors().AddCursor(cursor); err != nil {
+		cursor.Close()
+		return nil, sql3.NewErrCursorExists(0, 0, i.cursorName)
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+// PlanOpFetch plan operator to fetch rows from a cursor.
+type PlanOpFetch struct {
+	planner    *ExecutionPlanner
+	cursorName string
+	count      int64
+	schema     types.Schema
+	warnings   []string
+}
+
+func NewPlanOpFetch(p *ExecutionPlanner, cursorName string, count int64, schema types.Schema) *PlanOpFetch {
+	return &PlanOpFetch{
+		planner:    p,
+		cursorName: cursorName,
+		count:      count,
+		schema:     schema,
+		warnings:   make([]string, 0),
+	}
+}
+
+func (p *PlanOpFetch) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["_schema"] = p.Schema().Plan()
+	result["cursorName"] = p.cursorName
+	result["count"] = p.count
+	return result
+}
+
+func (p *PlanOpFetch) String() string {
+	return ""
+}
+
+func (p *PlanOpFetch) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpFetch) Warnings() []string {
+	return p.warnings
+}
+
+func (p *PlanOpFetch) Schema() types.Schema {
+	return p.schema
+}
+
+func (p *PlanOpFetch) Children() []types.PlanOperator {
+	return []types.PlanOperator{}
+}
+
+func (p *PlanOpFetch) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	return &fetchRowIter{
+		planner:    p.planner,
+		cursorName: p.cursorName,
+		count:      p.count,
+	}, nil
+}
+
+func (p *PlanOpFetch) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	if len(children) != 0 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return p, nil
+}
+
+type fetchRowIter struct {
+	planner    *ExecutionPlanner
+	cursorName string
+	count      int64
+
+	rows types.Rows
+}
+
+var _ types.RowIterator = (*fetchRowIter)(nil)
+
+func (i *fetchRowIter) Next(ctx context.Context) (types.Row, error) {
+	if i.rows == nil {
+		cursors := i.planner.systemLayerAPI.Cursors()
+		userID, _ := fbcontext.UserID(ctx)
+		cursor, err := cursors.GetCursor(ctx, userID, i.cursorName)
+		if err != nil {
+			// the cursor may have expired since this statement was compiled
+			return nil, sql3.NewErrCursorNotFound(0, 0, i.cursorName)
+		}
+		rows, done, err := cursor.Fetch(int(i.count))
+		if err != nil {
+			return nil, err
+		}
+		// the cursor is exhausted, so there is no point keeping it around
+		if done {
+			_ = cursors.CloseCursor(ctx, userID, i.cursorName)
+		}
+		i.rows = rows
+	}
+
+	if len(i.rows) == 0 {
+		return nil, types.ErrNoMoreRows
+	}
+	row := i.rows[0]
+	i.rows = i.rows[1:]
+	return row, nil
+}
+
+// PlanOpCloseCursor plan operator to close a cursor.
+type PlanOpCloseCursor struct {
+	planner    *ExecutionPlanner
+	cursorName string
+	warnings   []string
+}
+
+func NewPlanOpCloseCursor(p *ExecutionPlanner, cursorName string) *PlanOpCloseCursor {
+	return &PlanOpCloseCursor{
+		planner:    p,
+		cursorName: cursorName,
+		warnings:   make([]string, 0),
+	}
+}
+
+func (p *PlanOpCloseCursor) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["cursorName"] = p.cursorName
+	return result
+}
+
+func (p *PlanOpCloseCursor) String() string {
+	return ""
+}
+
+func (p *PlanOpCloseCursor) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpCloseCursor) Warnings() []string {
+	return p.warnings
+}
+
+func (p *PlanOpCloseCursor) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpCloseCursor) Children() []types.PlanOperator {
+	return []types.PlanOperator{}
+}
+
+func (p *PlanOpCloseCursor) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	return &closeCursorRowIter{
+		planner:    p.planner,
+		cursorName: p.cursorName,
+	}, nil
+}
+
+func (p *PlanOpCloseCursor) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	if len(children) != 0 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return p, nil
+}
+
+type closeCursorRowIter struct {
+	planner    *ExecutionPlanner
+	cursorName string
+}
+
+var _ types.RowIterator = (*closeCursorRowIter)(nil)
+
+func (i *closeCursorRowIter) Next(ctx context.Context) (types.Row, error) {
+	userID, _ := fbcontext.UserID(ctx)
+	if err := i.planner.systemLayerAPI.Cursors().CloseCursor(ctx, userID, i.cursorName); err != nil {
+		return nil, sql3.NewErrCursorNotFound(0, 0, i.cursorName)
+	}
+	return nil, types.ErrNoMoreRows
+}
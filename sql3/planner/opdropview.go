@@ -12,18 +12,20 @@ import (
 
 // PlanOpDropView plan operator to drop a view.
 type PlanOpDropView struct {
-	planner  *ExecutionPlanner
-	viewName string
-	ifExists bool
-	warnings []string
+	planner      *ExecutionPlanner
+	viewName     string
+	ifExists     bool
+	materialized bool
+	warnings     []string
 }
 
-func NewPlanOpDropView(p *ExecutionPlanner, ifExists bool, viewName string) *PlanOpDropView {
+func NewPlanOpDropView(p *ExecutionPlanner, ifExists bool, materialized bool, viewName string) *PlanOpDropView {
 	return &PlanOpDropView{
-		planner:  p,
-		viewName: viewName,
-		ifExists: ifExists,
-		warnings: make([]string, 0),
+		planner:      p,
+		viewName:     viewName,
+		ifExists:     ifExists,
+		materialized: materialized,
+		warnings:     make([]string, 0),
 	}
 }
 
@@ -32,6 +34,7 @@ func (p *PlanOpDropView) Plan() map[string]interface{} {
 	result["_op"] = fmt.Sprintf("%T", p)
 	result["viewName"] = p.viewName
 	result["isExists"] = p.ifExists
+	result["materialized"] = p.materialized
 	return result
 }
 
@@ -57,9 +60,10 @@ func (p *PlanOpDropView) Children() []types.PlanOperator {
 
 func (p *PlanOpDropView) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
 	return &dropViewRowIter{
-		planner:  p.planner,
-		ifExists: p.ifExists,
-		viewName: p.viewName,
+		planner:      p.planner,
+		ifExists:     p.ifExists,
+		materialized: p.materialized,
+		viewName:     p.viewName,
 	}, nil
 }
 
@@ -68,9 +72,10 @@ func (p *PlanOpDropView) WithChildren(children ...types.PlanOperator) (types.Pla
 }
 
 type dropViewRowIter struct {
-	planner  *ExecutionPlanner
-	ifExists bool
-	viewName string
+	planner      *ExecutionPlanner
+	ifExists     bool
+	materialized bool
+	viewName     string
 }
 
 var _ types.RowIterator = (*dropViewRowIter)(nil)
@@ -82,18 +87,33 @@ func (i *dropViewRowIter) Next(ctx context.Context) (types.Row, error) {
 	}
 
 	// check in the views table to see if it exists
-	v, err := i.planner.getViewByName(ctx, i.viewName)
+	if !i.materialized {
+		v, err := i.planner.getViewByName(ctx, i.viewName)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			err = i.planner.deleteView(ctx, i.viewName)
+			if err != nil {
+				return nil, err
+			}
+			return nil, types.ErrNoMoreRows
+		}
+	}
+
+	// then in the materialized views table
+	mv, err := i.planner.getMaterializedViewByName(ctx, i.viewName)
 	if err != nil {
 		return nil, err
 	}
-	if v == nil {
+	if mv == nil {
 		if i.ifExists {
 			return nil, types.ErrNoMoreRows
 		}
 		return nil, sql3.NewErrViewNotFound(0, 0, i.viewName)
 	}
 
-	err = i.planner.deleteView(ctx, i.viewName)
+	err = i.planner.dropMaterializedView(ctx, i.viewName)
 	if err != nil {
 		return nil, err
 	}
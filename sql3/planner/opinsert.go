@@ -3,6 +3,7 @@
 package planner
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	fbbatch "github.com/featurebasedb/featurebase/v3/batch"
 	"github.com/featurebasedb/featurebase/v3/dax"
 	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/roaring"
 	"github.com/featurebasedb/featurebase/v3/sql3"
 	"github.com/featurebasedb/featurebase/v3/sql3/parser"
 	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
@@ -24,15 +26,17 @@ type PlanOpInsert struct {
 	tableName     string
 	targetColumns []*qualifiedRefPlanExpression
 	insertValues  [][]types.PlanExpression
+	conflict      *insertConflict
 	warnings      []string
 }
 
-func NewPlanOpInsert(p *ExecutionPlanner, tableName string, targetColumns []*qualifiedRefPlanExpression, insertValues [][]types.PlanExpression) *PlanOpInsert {
+func NewPlanOpInsert(p *ExecutionPlanner, tableName string, targetColumns []*qualifiedRefPlanExpression, insertValues [][]types.PlanExpression, conflict *insertConflict) *PlanOpInsert {
 	return &PlanOpInsert{
 		planner:       p,
 		tableName:     tableName,
 		targetColumns: targetColumns,
 		insertValues:  insertValues,
+		conflict:      conflict,
 		warnings:      make([]string, 0),
 	}
 }
@@ -48,6 +52,16 @@ func (p *PlanOpInsert) Plan() map[string]interface{} {
 	}
 	result["targetColumns"] = ps
 	result["insertTupleCount"] = len(p.insertValues)
+	if c := p.conflict; c != nil {
+		switch {
+		case c.replace:
+			result["onConflict"] = "replace"
+		case c.doNothing:
+			result["onConflict"] = "nothing"
+		default:
+			result["onConflict"] = "update"
+		}
+	}
 	return result
 }
 
@@ -77,11 +91,12 @@ func (p *PlanOpInsert) Iterator(ctx context.Context, row types.Row) (types.RowIt
 		tableName:     p.tableName,
 		targetColumns: p.targetColumns,
 		insertValues:  p.insertValues,
+		conflict:      p.conflict,
 	}, nil
 }
 
 func (p *PlanOpInsert) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
-	return NewPlanOpInsert(p.planner, p.tableName, p.targetColumns, p.insertValues), nil
+	return NewPlanOpInsert(p.planner, p.tableName, p.targetColumns, p.insertValues, p.conflict), nil
 }
 
 type insertRowIter struct {
@@ -89,11 +104,98 @@ type insertRowIter struct {
 	tableName     string
 	targetColumns []*qualifiedRefPlanExpression
 	insertValues  [][]types.PlanExpression
+
+	// rowOffset is added to the row numbers reported in errors, for when
+	// the rows being inserted are part of a larger set.
+	rowOffset int
+
+	// conflict, if not nil, says what to do with rows whose _id is already
+	// in the table. Otherwise their values are merged into the record.
+	conflict *insertConflict
 }
 
 var _ types.RowIterator = (*insertRowIter)(nil)
 
 func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
+	tname := dax.TableName(i.tableName)
+	tbl, err := i.planner.schemaAPI.TableByName(ctx, tname)
+	if err != nil {
+		return nil, sql3.NewErrTableNotFound(0, 0, i.tableName)
+	}
+
+	tuples := i.insertValues
+	rowNumbers := make([]int, len(tuples))
+	for n := range rowNumbers {
+		rowNumbers[n] = n
+	}
+
+	// Every row is checked before anything is cleared or imported, so that
+	// a bad row leaves the table as it was.
+	var inserts []*preparedInsert
+	if i.conflict != nil && !i.conflict.replace {
+		var conflicting [][]types.PlanExpression
+		var conflictingNumbers []int
+		tuples, rowNumbers, conflicting, conflictingNumbers, err = i.conflict.split(ctx, i.planner, tbl, i.targetColumns, tuples, rowNumbers)
+		if err != nil {
+			return nil, err
+		}
+		if len(conflicting) > 0 && len(i.conflict.updateColumns) > 0 {
+			targetColumns, updates, err := i.conflict.updateTuples(i.targetColumns, conflicting)
+			if err != nil {
+				return nil, err
+			}
+			update, err := i.prepare(ctx, tbl, targetColumns, updates, conflictingNumbers)
+			if err != nil {
+				return nil, err
+			}
+			update.clearer = i.conflict.updateClearer()
+			inserts = append(inserts, update)
+		}
+	}
+	if len(tuples) > 0 {
+		insert, err := i.prepare(ctx, tbl, i.targetColumns, tuples, rowNumbers)
+		if err != nil {
+			return nil, err
+		}
+		if i.conflict != nil && i.conflict.replace {
+			insert.clearer = newReplaceClearer()
+		}
+		inserts = append(inserts, insert)
+	}
+
+	var count int
+	for _, insert := range inserts {
+		n, err := insert.importRows(ctx, i.planner.importer, tbl)
+		if err != nil {
+			return nil, err
+		}
+		count += n
+	}
+
+	// update the counter for inserts
+	pilosa.PerfCounterSQLInsertsSec.Add(int64(count))
+
+	return nil, types.ErrNoMoreRows
+}
+
+// preparedInsert is a batch of rows which have been checked and are ready to
+// be imported, along with whatever has to be cleared before they are.
+type preparedInsert struct {
+	batch     *fbbatch.Batch
+	geoIdx    *geoPointIndexer
+	bvClearer *bitVectorClearer
+
+	// ids are the record ids of the rows in batch.
+	ids []interface{}
+
+	// clearer, if not nil, clears existing values of the records before
+	// they're imported.
+	clearer *recordClearer
+}
+
+// prepare adds tuples, the values of targetColumns, to a new batch. The
+// number of each tuple, for error messages, is in rowNumbers.
+func (i *insertRowIter) prepare(ctx context.Context, tbl *dax.Table, targetColumns []*qualifiedRefPlanExpression, tuples [][]types.PlanExpression, rowNumbers []int) (*preparedInsert, error) {
 	// posID is the position of the "_id" column in both the targetColumns and
 	// values lists.
 	var posID int
@@ -109,15 +211,15 @@ func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
 	// VALUES positions (0,2,3) to row.Values (0, 1, 2). Note, the _id position
 	// (shown as 1* in the example above) isn't used because we handle it
 	// separately.
-	posVals := make([]int, len(i.targetColumns))
+	posVals := make([]int, len(targetColumns))
 
 	var foundPosID bool
-	for j := range i.targetColumns {
+	for j := range targetColumns {
 		if foundPosID {
 			posVals[j] = j - 1
 			continue
 		}
-		if strings.EqualFold(i.targetColumns[j].columnName, string(dax.PrimaryKeyFieldName)) {
+		if strings.EqualFold(targetColumns[j].columnName, string(dax.PrimaryKeyFieldName)) {
 			posID = j
 			foundPosID = true
 		}
@@ -127,17 +229,12 @@ func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
 	// batchSize is currently set to the size of the entire
 	// VALUES list. In the future we may want to break this up into smaller
 	// batches.
-	batchSize := len(i.insertValues)
+	batchSize := len(tuples)
 
 	// idxInfoBase is the full IndexInfo stored in the schema. The instance of
 	// IndexInfo used in the import (and created below) will be based on the
 	// information from idxInfoBase, but the fields may be a limited subset, and
 	// may be in a different order.
-	tname := dax.TableName(i.tableName)
-	tbl, err := i.planner.schemaAPI.TableByName(ctx, tname)
-	if err != nil {
-		return nil, sql3.NewErrTableNotFound(0, 0, i.tableName)
-	}
 	idxInfoBase := pilosa.TableToIndexInfo(tbl)
 
 	// idxInfo is a subset of idxInfoBase, containing only those fields included
@@ -147,13 +244,13 @@ func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
 		Name:       idxInfoBase.Name,
 		CreatedAt:  idxInfoBase.CreatedAt,
 		Options:    idxInfoBase.Options,
-		Fields:     make([]*pilosa.FieldInfo, len(i.targetColumns)-1),
+		Fields:     make([]*pilosa.FieldInfo, len(targetColumns)-1),
 		ShardWidth: idxInfoBase.ShardWidth,
 	}
 
-	// Set up Fields based on i.targetColumns.
+	// Set up Fields based on targetColumns.
 	var counter int
-	for ii, targetColumn := range i.targetColumns {
+	for ii, targetColumn := range targetColumns {
 		// Skip the "_id" column.
 		if ii == posID {
 			continue
@@ -162,9 +259,23 @@ func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
 		counter++
 	}
 
-	batch, err := fbbatch.NewBatch(i.planner.importer, batchSize, tbl, idxInfo.Fields,
-		fbbatch.OptUseShardTransactionalEndpoint(true),
-	)
+	// JSON columns store a row per byte of their documents, which replace
+	// the old ones, and write to an additional field per indexed path, which
+	// follow the target columns in the batch.
+	jsonWriter, batchFields, err := newJSONColumnWriter(idxInfo.Fields)
+	if err != nil {
+		return nil, err
+	}
+	// Likewise, geopoint columns write the cells containing each point.
+	geoIdx, batchFields := newGeoPointIndexer(batchFields)
+	// And bitvector columns set a row per bit, which replace the old ones.
+	bvClearer := newBitVectorClearer(batchFields)
+
+	batchOpts := []fbbatch.BatchOption{fbbatch.OptUseShardTransactionalEndpoint(true)}
+	if jsonWriter != nil {
+		batchOpts = append(batchOpts, fbbatch.OptReplaceFields(jsonWriter.replacedFields()...))
+	}
+	batch, err := fbbatch.NewBatch(i.planner.importer, batchSize, tbl, batchFields, batchOpts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "setting up batch")
 	}
@@ -178,9 +289,12 @@ func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
 
 	// Initialize row.Values to the size of the target columns, but exclude the
 	// record ID ("_id") since that's stored in row.ID.
-	row.Values = make([]interface{}, len(i.targetColumns)-1)
+	row.Values = make([]interface{}, len(batchFields))
+
+	ids := make([]interface{}, 0, len(tuples))
+	for n, tuple := range tuples {
+		rowNumber := rowNumbers[n]
 
-	for rowNumber, tuple := range i.insertValues {
 		// Evaluate and set the record ID.
 		if eval, err := tuple[posID].Evaluate(nil); err != nil {
 			return nil, errors.Wrapf(err, "evaluating record id: %v", tuple[posID])
@@ -252,6 +366,24 @@ func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
 						uint64s[i] = uint64(v[i])
 					}
 					row.Values[posVals[idx]] = uint64s
+				case string:
+					if opts.BitVector > 0 {
+						rows, err := bitVectorRows(v, opts.BitVector)
+						if err != nil {
+							return nil, err
+						}
+						row.Values[posVals[idx]] = rows
+						break
+					}
+					// JSON documents are checked and stored compacted
+					if opts.JSON {
+						doc, err := normalizeJSON(v)
+						if err != nil {
+							return nil, err
+						}
+						v = doc
+					}
+					row.Values[posVals[idx]] = v
 				default:
 					row.Values[posVals[idx]] = eval
 				}
@@ -294,7 +426,7 @@ func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
 					row.Time = qrowTime
 
 					// second member must be a set of the correct type
-					targetCol := i.targetColumns[idx]
+					targetCol := targetColumns[idx]
 
 					switch targetCol.Type().(type) {
 					case *parser.DataTypeStringSetQuantum:
@@ -326,6 +458,15 @@ func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
 				}
 
 			case pilosa.FieldTypeInt:
+				// geopoints are stored packed into a single int
+				if opts.GeoPoint && eval != nil {
+					lat, lon, err := geoPointFromValue(eval)
+					if err != nil {
+						return nil, err
+					}
+					row.Values[posVals[idx]] = packGeoPoint(lat, lon)
+					break
+				}
 				if eval != nil {
 					v, ok := eval.(int64)
 					if !ok {
@@ -351,6 +492,18 @@ func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
 				}
 				row.Values[posVals[idx]] = eval
 
+			case pilosa.FieldTypeFloat:
+				// ints and decimals are assignable to a double column, so
+				// make sure batch.Add sees a float64
+				if eval != nil {
+					cval, err := coerceValue(iv.Type(), parser.NewDataTypeDouble(), eval, parser.Pos{Line: 0, Column: 0})
+					if err != nil {
+						return nil, err
+					}
+					eval = cval
+				}
+				row.Values[posVals[idx]] = eval
+
 			case pilosa.FieldTypeTimestamp:
 				switch v := eval.(type) {
 
@@ -426,6 +579,20 @@ func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
 			}
 		}
 
+		if jsonWriter != nil {
+			if err := jsonWriter.setRow(ctx, row.Values); err != nil {
+				return nil, err
+			}
+		}
+		if geoIdx != nil {
+			geoIdx.setRow(row.ID, row.Values)
+		}
+		if bvClearer != nil {
+			bvClearer.addRow(row.ID)
+		}
+
+		ids = append(ids, row.ID)
+
 		if err := batch.Add(row); err != nil {
 			// Breaking here on ErrBatchNowFull is only valid because we are
 			// explicity setting the batch size to the number of tuples in the
@@ -436,18 +603,116 @@ func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
 			if err == fbbatch.ErrBatchNowFull {
 				break
 			}
+			var cerr *pilosa.ConstraintError
+			if errors.As(err, &cerr) {
+				return nil, sql3.NewErrConstraintViolation(0, 0, i.rowOffset+rowNumber+1, cerr.Error())
+			}
 			return nil, errors.Wrap(err, "adding record")
 		}
 	}
 
-	count := batch.Len()
+	return &preparedInsert{
+		batch:     batch,
+		geoIdx:    geoIdx,
+		bvClearer: bvClearer,
+		ids:       ids,
+	}, nil
+}
+
+// importRows clears whatever has to be cleared, and then imports the batch,
+// returning the number of rows imported.
+func (pi *preparedInsert) importRows(ctx context.Context, importer pilosa.Importer, tbl *dax.Table) (int, error) {
+	count := pi.batch.Len()
 
-	if err := batch.Import(); err != nil {
-		return nil, errors.Wrap(err, "importing batch")
+	if pi.clearer != nil {
+		for _, id := range pi.ids {
+			pi.clearer.addRow(id)
+		}
+		if err := pi.clearer.clear(ctx, importer, tbl); err != nil {
+			return 0, err
+		}
 	}
 
-	// update the counter for inserts
-	pilosa.PerfCounterSQLInsertsSec.Add(int64(count))
+	// Previous cells and bitvectors have to be cleared first; the batch only
+	// adds to set fields. (It replaces documents and the values of indexed
+	// paths itself.)
+	if pi.geoIdx != nil {
+		if err := pi.geoIdx.clear(ctx, importer, tbl); err != nil {
+			return 0, err
+		}
+	}
+	if pi.bvClearer != nil {
+		if err := pi.bvClearer.clear(ctx, importer, tbl); err != nil {
+			return 0, err
+		}
+	}
 
-	return nil, types.ErrNoMoreRows
+	if err := pi.batch.Import(); err != nil {
+		return 0, errors.Wrap(err, "importing batch")
+	}
+	return count, nil
+}
+
+// clearRecordFields clears the values of the records identified by ids in
+// each of the set fields named by fields; an empty field name stands for all
+// of the table's fields. Record ids may be keys, which are translated (and
+// created, if need be) first.
+func clearRecordFields(ctx context.Context, importer pilosa.Importer, tbl *dax.Table, ids []interface{}, fields []string) error {
+	var recs []uint64
+	var keys []string
+	for _, id := range ids {
+		switch id := id.(type) {
+		case string:
+			keys = append(keys, id)
+		case []byte:
+			keys = append(keys, string(id))
+		case uint64:
+			recs = append(recs, id)
+		default:
+			return sql3.NewErrInternalf("unexpected _id type '%T'", id)
+		}
+	}
+	if len(keys) > 0 {
+		translated, err := importer.CreateTableKeys(ctx, tbl.ID, keys...)
+		if err != nil {
+			return errors.Wrap(err, "translating keys")
+		}
+		for _, k := range keys {
+			recs = append(recs, translated[k])
+		}
+	}
+
+	byShard := make(map[uint64]*roaring.Bitmap)
+	for _, id := range recs {
+		shard := id / pilosa.ShardWidth
+		bm, ok := byShard[shard]
+		if !ok {
+			bm = roaring.NewBitmap()
+			byShard[shard] = bm
+		}
+		bm.DirectAdd(id % pilosa.ShardWidth)
+	}
+	for shard, bm := range byShard {
+		buf := &bytes.Buffer{}
+		if _, err := bm.WriteTo(buf); err != nil {
+			return errors.Wrap(err, "serializing bitmap")
+		}
+		clear := buf.Bytes()
+		updates := make([]pilosa.RoaringUpdate, 0, len(fields))
+		for _, fld := range fields {
+			updates = append(updates, pilosa.RoaringUpdate{
+				Field:        fld,
+				View:         "standard",
+				Clear:        clear,
+				ClearRecords: true,
+			})
+		}
+		if err := importer.ImportRoaringShard(ctx, tbl.ID, shard, &pilosa.ImportRoaringShardRequest{
+			Remote: true,
+			Views:  updates,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
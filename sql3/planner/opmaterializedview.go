@@ -0,0 +1,612 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+	"github.com/pkg/errors"
+)
+
+// PlanOpCreateMaterializedView implements the CREATE MATERIALIZED VIEW
+// operator. It creates the hidden table which holds the contents of the
+// view, records the view in fb_materialized_views and populates it.
+type PlanOpCreateMaterializedView struct {
+	planner     *ExecutionPlanner
+	view        *materializedViewSystemObject
+	columns     []*createTableField
+	ifNotExists bool
+	warnings    []string
+}
+
+func NewPlanOpCreateMaterializedView(planner *ExecutionPlanner, ifNotExists bool, view *materializedViewSystemObject, columns []*createTableField) *PlanOpCreateMaterializedView {
+	return &PlanOpCreateMaterializedView{
+		planner:     planner,
+		view:        view,
+		columns:     columns,
+		ifNotExists: ifNotExists,
+		warnings:    make([]string, 0),
+	}
+}
+
+func (p *PlanOpCreateMaterializedView) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpCreateMaterializedView) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	return &createMaterializedViewIter{
+		planner:     p.planner,
+		view:        p.view,
+		columns:     p.columns,
+		ifNotExists: p.ifNotExists,
+	}, nil
+}
+
+func (p *PlanOpCreateMaterializedView) Children() []types.PlanOperator {
+	return []types.PlanOperator{}
+}
+
+func (p *PlanOpCreateMaterializedView) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	if len(children) != 0 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return NewPlanOpCreateMaterializedView(p.planner, p.ifNotExists, p.view, p.columns), nil
+}
+
+func (p *PlanOpCreateMaterializedView) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["_schema"] = p.Schema().Plan()
+	result["view"] = p.view.name
+	result["refreshInterval"] = p.view.refreshInterval.String()
+	return result
+}
+
+func (p *PlanOpCreateMaterializedView) String() string {
+	return ""
+}
+
+func (p *PlanOpCreateMaterializedView) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpCreateMaterializedView) Warnings() []string {
+	var w []string
+	w = append(w, p.warnings...)
+	return w
+}
+
+type createMaterializedViewIter struct {
+	planner     *ExecutionPlanner
+	view        *materializedViewSystemObject
+	columns     []*createTableField
+	ifNotExists bool
+}
+
+var _ types.RowIterator = (*createMaterializedViewIter)(nil)
+
+func (i *createMaterializedViewIter) Next(ctx context.Context) (types.Row, error) {
+	err := i.planner.checkAccess(ctx, i.view.name, accessTypeCreateObject)
+	if err != nil {
+		return nil, err
+	}
+
+	// make sure we have no existing table, view or materialized view named
+	// the same as our view
+	tbl, err := i.planner.schemaAPI.TableByName(ctx, dax.TableName(i.view.name))
+	if err != nil {
+		if !isTableNotFoundError(err) {
+			return nil, err
+		}
+	}
+	if tbl != nil {
+		if i.ifNotExists {
+			return nil, types.ErrNoMoreRows
+		}
+		return nil, sql3.NewErrTableExists(0, 0, i.view.name)
+	}
+
+	v, err := i.planner.getViewByName(ctx, i.view.name)
+	if err != nil {
+		return nil, err
+	}
+	mv, err := i.planner.getMaterializedViewByName(ctx, i.view.name)
+	if err != nil {
+		return nil, err
+	}
+	if v != nil || mv != nil {
+		if i.ifNotExists {
+			return nil, types.ErrNoMoreRows
+		}
+		return nil, sql3.NewErrViewExists(0, 0, i.view.name)
+	}
+
+	// create the table behind the view
+	iter := &createTableRowIter{
+		planner:       i.planner,
+		tableName:     materializedViewTableName(i.view.name),
+		failIfExists:  true,
+		isKeyed:       true,
+		keyPartitions: 0,
+		columns:       i.columns,
+		description:   fmt.Sprintf("contents of materialized view %s", i.view.name),
+	}
+	if _, err := iter.Next(ctx); err != nil && err != types.ErrNoMoreRows {
+		return nil, err
+	}
+
+	if err := i.planner.insertMaterializedView(ctx, i.view); err != nil {
+		return nil, err
+	}
+
+	// populate the view; if we can't, don't leave a half created view behind
+	if err := i.planner.refreshMaterializedView(ctx, i.view); err != nil {
+		if derr := i.planner.dropMaterializedView(ctx, i.view.name); derr != nil {
+			i.planner.logger.Errorf("dropping materialized view '%s' after failed refresh: %v", i.view.name, derr)
+		}
+		return nil, err
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+// dropMaterializedView removes a materialized view and the table behind it.
+func (p *ExecutionPlanner) dropMaterializedView(ctx context.Context, name string) error {
+	if err := p.deleteMaterializedView(ctx, name); err != nil {
+		return err
+	}
+	err := p.schemaAPI.DeleteTable(ctx, dax.TableName(materializedViewTableName(name)))
+	if err != nil && !isTableNotFoundError(err) {
+		return err
+	}
+	return nil
+}
+
+// PlanOpRefreshMaterializedView implements the REFRESH MATERIALIZED VIEW
+// operator.
+type PlanOpRefreshMaterializedView struct {
+	planner  *ExecutionPlanner
+	viewName string
+	warnings []string
+}
+
+func NewPlanOpRefreshMaterializedView(planner *ExecutionPlanner, viewName string) *PlanOpRefreshMaterializedView {
+	return &PlanOpRefreshMaterializedView{
+		planner:  planner,
+		viewName: viewName,
+		warnings: make([]string, 0),
+	}
+}
+
+func (p *PlanOpRefreshMaterializedView) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpRefreshMaterializedView) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	return &refreshMaterializedViewIter{
+		planner:  p.planner,
+		viewName: p.viewName,
+	}, nil
+}
+
+func (p *PlanOpRefreshMaterializedView) Children() []types.PlanOperator {
+	return []types.PlanOperator{}
+}
+
+func (p *PlanOpRefreshMaterializedView) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	if len(children) != 0 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return NewPlanOpRefreshMaterializedView(p.planner, p.viewName), nil
+}
+
+func (p *PlanOpRefreshMaterializedView) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["_schema"] = p.Schema().Plan()
+	result["view"] = p.viewName
+	return result
+}
+
+func (p *PlanOpRefreshMaterializedView) String() string {
+	return ""
+}
+
+func (p *PlanOpRefreshMaterializedView) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpRefreshMaterializedView) Warnings() []string {
+	var w []string
+	w = append(w, p.warnings...)
+	return w
+}
+
+type refreshMaterializedViewIter struct {
+	planner  *ExecutionPlanner
+	viewName string
+}
+
+var _ types.RowIterator = (*refreshMaterializedViewIter)(nil)
+
+func (i *refreshMaterializedViewIter) Next(ctx context.Context) (types.Row, error) {
+	err := i.planner.checkAccess(ctx, i.viewName, accessTypeWriteData)
+	if err != nil {
+		return nil, err
+	}
+
+	mv, err := i.planner.getMaterializedViewByName(ctx, i.viewName)
+	if err != nil {
+		return nil, err
+	}
+	if mv == nil {
+		return nil, sql3.NewErrViewNotFound(0, 0, i.viewName)
+	}
+
+	if err := i.planner.refreshMaterializedView(ctx, mv); err != nil {
+		return nil, err
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+// refreshMaterializedView recomputes the contents of a materialized view. The
+// new contents replace the old in a single transaction, so readers of the
+// view never see it partially refreshed.
+func (p *ExecutionPlanner) refreshMaterializedView(ctx context.Context, mv *materializedViewSystemObject) error {
+	ast, err := parser.NewParser(strings.NewReader(mv.statement)).ParseStatement()
+	if err != nil {
+		return err
+	}
+	sel, ok := ast.(*parser.SelectStatement)
+	if !ok {
+		return sql3.NewErrInternalf("unexpected ast type")
+	}
+
+	tableName := materializedViewTableName(mv.name)
+	tbl, err := p.schemaAPI.TableByName(ctx, dax.TableName(tableName))
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return sql3.NewErrTableNotFound(0, 0, tableName)
+		}
+		return err
+	}
+	_, incremental := tbl.Field(materializedViewQuantumColumn)
+
+	var quantum *materializedViewQuantum
+	if incremental {
+		if quantum, err = p.materializedViewQuantum(ctx, sel); err != nil {
+			return err
+		}
+		if quantum == nil {
+			return sql3.NewErrInternalf("materialized view '%s' can no longer be refreshed by time quantum", mv.name)
+		}
+	}
+
+	// the refresh time is taken before running any queries, so that anything
+	// written while we're refreshing is picked up next time
+	now := time.Now().UTC()
+
+	// read the rows the view has now; those recomputed are replaced
+	columns := []string{string(dax.PrimaryKeyFieldName)}
+	if quantum != nil {
+		columns = append(columns, materializedViewQuantumColumn, materializedViewCountColumn)
+	}
+	scan := &tableScanRowIter{
+		planner:   p,
+		tableName: tableName,
+		columns:   columns,
+	}
+	var existing types.Rows
+	for {
+		row, err := scan.Next(ctx)
+		if err == types.ErrNoMoreRows {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		existing = append(existing, row)
+	}
+
+	var keep func(row types.Row) bool
+	var insertValues [][]types.PlanExpression
+	if quantum == nil {
+		rows, err := p.materializedViewRows(ctx, sel)
+		if err != nil {
+			return err
+		}
+		for n, row := range rows {
+			values, err := materializedViewRowValues(strconv.Itoa(n), row)
+			if err != nil {
+				return err
+			}
+			insertValues = append(insertValues, values)
+		}
+		keep = func(row types.Row) bool { return false }
+	} else {
+		// the number of records each bucket was computed from
+		counts := make(map[time.Time]int64)
+		for _, row := range existing {
+			bucket, ok := row[1].(time.Time)
+			if !ok {
+				continue
+			}
+			count, _ := row[2].(int64)
+			counts[bucket.UTC()] = count
+		}
+
+		start := quantum.truncate(quantum.from)
+		end := now
+		if quantum.to != nil && quantum.to.Before(end) {
+			end = *quantum.to
+		}
+		// every bucket with data is recomputed, as is any which had data when
+		// last computed; a bucket from before the last refresh can have its
+		// records changed in place without its number of records changing,
+		// so that number can't be relied on to tell whether it's stale
+		buckets, err := p.materializedViewBuckets(ctx, sel, quantum, counts, start, end)
+		if err != nil {
+			return err
+		}
+
+		recomputed := make(map[time.Time]struct{})
+		for _, b := range buckets {
+			recomputed[b.start] = struct{}{}
+			if b.count == 0 {
+				continue
+			}
+			rows, err := p.materializedViewRows(ctx, quantum.narrow(sel, b.start))
+			if err != nil {
+				return err
+			}
+			for n, row := range rows {
+				key := fmt.Sprintf("%d-%d", b.start.Unix(), n)
+				values, err := materializedViewRowValues(key, append(types.Row{b.start, b.count}, row...))
+				if err != nil {
+					return err
+				}
+				insertValues = append(insertValues, values)
+			}
+		}
+		keep = func(row types.Row) bool {
+			bucket, ok := row[1].(time.Time)
+			if !ok {
+				return false
+			}
+			_, ok = recomputed[bucket.UTC()]
+			return !ok
+		}
+	}
+
+	// stage the removal of the rows being recomputed, and the insert of their
+	// replacements
+	staging := newStagingImporter(p.importer)
+	sp := *p
+	sp.importer = staging
+	sp.staging = staging
+
+	var stale types.Rows
+	for _, row := range existing {
+		if !keep(row) {
+			stale = append(stale, row[:1])
+		}
+	}
+	del := &transactionDeleteRowIter{
+		planner:   &sp,
+		childIter: &rowsIter{rows: stale},
+		tableName: tableName,
+	}
+	if _, err := del.Next(ctx); err != nil && err != types.ErrNoMoreRows {
+		return err
+	}
+
+	if len(insertValues) > 0 {
+		targetColumns := make([]*qualifiedRefPlanExpression, len(tbl.Fields))
+		for n, fld := range tbl.Fields {
+			targetColumns[n] = newQualifiedRefPlanExpression(tableName, string(fld.Name), n, fieldSQLDataType(pilosa.FieldToFieldInfo(fld)))
+		}
+		ins := &insertRowIter{
+			planner:       &sp,
+			tableName:     tableName,
+			targetColumns: targetColumns,
+			insertValues:  insertValues,
+		}
+		if _, err := ins.Next(ctx); err != nil && err != types.ErrNoMoreRows {
+			return err
+		}
+	}
+
+	apply := &transactionRowIter{
+		planner: p,
+		staging: staging,
+	}
+	if _, err := apply.Next(ctx); err != nil && err != types.ErrNoMoreRows {
+		return errors.Wrapf(err, "refreshing materialized view '%s'", mv.name)
+	}
+
+	return p.updateMaterializedViewLastRefresh(ctx, mv.name, now)
+}
+
+// materializedViewRows runs the select statement of a materialized view and
+// returns its results.
+func (p *ExecutionPlanner) materializedViewRows(ctx context.Context, sel *parser.SelectStatement) (types.Rows, error) {
+	op, err := p.CompilePlan(ctx, sel)
+	if err != nil {
+		return nil, err
+	}
+	// skip the query operator, this isn't a request of its own
+	query, ok := op.(*PlanOpQuery)
+	if !ok {
+		return nil, sql3.NewErrInternalf("unexpected operator type '%T'", op)
+	}
+	iter, err := query.ChildOp.Iterator(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var rows types.Rows
+	for {
+		row, err := iter.Next(ctx)
+		if err == types.ErrNoMoreRows {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// materializedViewBucket is a time quantum bucket of a materialized view,
+// and the number of records in it.
+type materializedViewBucket struct {
+	start time.Time
+	count int64
+}
+
+// materializedViewBuckets returns the buckets between start and end which
+// need to be computed: those with data matching the filter of sel, as well as
+// any which had data when they were last computed according to counts.
+//
+// Rather than count each bucket, ranges of buckets are counted and halved
+// until the buckets are found, which keeps the refresh of a view over a long
+// time range with a fine quantum, most of whose buckets are empty, cheap.
+func (p *ExecutionPlanner) materializedViewBuckets(ctx context.Context, sel *parser.SelectStatement, quantum *materializedViewQuantum, counts map[time.Time]int64, start, end time.Time) ([]materializedViewBucket, error) {
+	if !start.Before(end) {
+		return nil, nil
+	}
+
+	// count the records matching the filter within [start, end)
+	count := &parser.SelectStatement{
+		Columns: []*parser.ResultColumn{{
+			Expr: &parser.Call{Name: &parser.Ident{Name: "COUNT"}, Star: parser.Pos{Line: 1, Column: 1}},
+		}},
+		Source:    parser.CloneSource(sel.Source),
+		WhereExpr: parser.CloneExpr(sel.WhereExpr),
+	}
+	rows, err := p.materializedViewRows(ctx, quantum.narrowRange(count, start, end))
+	if err != nil {
+		return nil, err
+	}
+	var n int64
+	if len(rows) > 0 {
+		n, _ = rows[0][0].(int64)
+	}
+	var was int64
+	for bucket, c := range counts {
+		if !bucket.Before(start) && bucket.Before(end) {
+			was += c
+		}
+	}
+	if n == 0 && was == 0 {
+		return nil, nil
+	}
+
+	next := quantum.next(start)
+	if !next.Before(end) {
+		return []materializedViewBucket{{start: start, count: n}}, nil
+	}
+
+	// split the range in two on a bucket boundary
+	mid := quantum.truncate(start.Add(end.Sub(start) / 2))
+	if !mid.After(start) {
+		mid = next
+	}
+	lower, err := p.materializedViewBuckets(ctx, sel, quantum, counts, start, mid)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := p.materializedViewBuckets(ctx, sel, quantum, counts, mid, end)
+	if err != nil {
+		return nil, err
+	}
+	return append(lower, upper...), nil
+}
+
+// narrow returns a copy of sel whose RANGEQ() is limited to the bucket
+// starting at bucket.
+func (q *materializedViewQuantum) narrow(sel *parser.SelectStatement, bucket time.Time) *parser.SelectStatement {
+	return q.narrowRange(sel.Clone(), bucket, q.next(bucket))
+}
+
+// narrowRange limits the RANGEQ() of sel to [from, to), as well as to its
+// original bounds.
+func (q *materializedViewQuantum) narrowRange(sel *parser.SelectStatement, from, to time.Time) *parser.SelectStatement {
+	if from.Before(q.from) {
+		from = q.from
+	}
+	if q.to != nil && q.to.Before(to) {
+		to = *q.to
+	}
+	call := findRangeQCall(sel.WhereExpr)
+	call.Args[1] = &parser.IntegerLit{Value: strconv.FormatInt(from.Unix(), 10)}
+	call.Args[2] = &parser.IntegerLit{Value: strconv.FormatInt(to.Unix(), 10)}
+	return sel
+}
+
+// materializedViewRowValues returns the values to insert into the table
+// behind a materialized view for a row of its results.
+func materializedViewRowValues(key string, row types.Row) ([]types.PlanExpression, error) {
+	values := make([]types.PlanExpression, 0, len(row)+1)
+	values = append(values, newStringLiteralPlanExpression(key))
+	for _, v := range row {
+		var expr types.PlanExpression
+		switch v := v.(type) {
+		case nil:
+			expr = newNullLiteralPlanExpression()
+		case int64:
+			expr = newIntLiteralPlanExpression(v)
+		case bool:
+			expr = newBoolLiteralPlanExpression(v)
+		case string:
+			expr = newStringLiteralPlanExpression(v)
+		case time.Time:
+			expr = newTimestampLiteralPlanExpression(v)
+		case pql.Decimal:
+			expr = newFloatLiteralPlanExpression(v.String())
+		case float64:
+			expr = newDoubleLiteralPlanExpression(v)
+		case []int64:
+			members := make([]types.PlanExpression, len(v))
+			for n := range v {
+				members[n] = newIntLiteralPlanExpression(v[n])
+			}
+			expr = newExprSetLiteralPlanExpression(members, parser.NewDataTypeIDSet())
+		case []string:
+			members := make([]types.PlanExpression, len(v))
+			for n := range v {
+				members[n] = newStringLiteralPlanExpression(v[n])
+			}
+			expr = newExprSetLiteralPlanExpression(members, parser.NewDataTypeStringSet())
+		default:
+			return nil, sql3.NewErrInternalf("unexpected materialized view value type '%T'", v)
+		}
+		values = append(values, expr)
+	}
+	return values, nil
+}
+
+// rowsIter iterates over rows which are already in memory.
+type rowsIter struct {
+	rows types.Rows
+}
+
+var _ types.RowIterator = (*rowsIter)(nil)
+
+func (i *rowsIter) Next(ctx context.Context) (types.Row, error) {
+	if len(i.rows) == 0 {
+		return nil, types.ErrNoMoreRows
+	}
+	row := i.rows[0]
+	i.rows = i.rows[1:]
+	return row, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// PlanOpOffset implements the OFFSET operator
+type PlanOpOffset struct {
+	ChildOp  types.PlanOperator
+	expr     types.PlanExpression
+	warnings []string
+}
+
+func NewPlanOpOffset(expr types.PlanExpression, child types.PlanOperator) *PlanOpOffset {
+	return &PlanOpOffset{
+		ChildOp:  child,
+		expr:     expr,
+		warnings: make([]string, 0),
+	}
+}
+
+func (p *PlanOpOffset) Schema() types.Schema {
+	return p.ChildOp.Schema()
+}
+
+func (p *PlanOpOffset) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	iter, err := p.ChildOp.Iterator(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	return newOffsetIter(p.expr, iter), nil
+}
+
+func (p *PlanOpOffset) Children() []types.PlanOperator {
+	return []types.PlanOperator{
+		p.ChildOp,
+	}
+}
+
+func (p *PlanOpOffset) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	if len(children) != 1 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return NewPlanOpOffset(p.expr, children[0]), nil
+}
+
+func (p *PlanOpOffset) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["_schema"] = p.Schema().Plan()
+	result["expr"] = p.expr
+	result["child"] = p.ChildOp.Plan()
+	return result
+}
+
+func (p *PlanOpOffset) String() string {
+	return ""
+}
+
+func (p *PlanOpOffset) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpOffset) Warnings() []string {
+	var w []string
+	w = append(w, p.warnings...)
+	w = append(w, p.ChildOp.Warnings()...)
+	return w
+}
+
+type offsetIter struct {
+	child types.RowIterator
+	expr  types.PlanExpression
+
+	hasStarted *struct{}
+}
+
+func newOffsetIter(expr types.PlanExpression, child types.RowIterator) *offsetIter {
+	return &offsetIter{
+		child: child,
+		expr:  expr,
+	}
+}
+
+func (i *offsetIter) Next(ctx context.Context) (types.Row, error) {
+	if i.hasStarted == nil {
+		offsetEval, err := i.expr.Evaluate(nil)
+		if err != nil {
+			return nil, err
+		}
+		offsetValue, ok := offsetEval.(int64)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unexpected offset expression result type %T", offsetEval)
+		}
+		i.hasStarted = &struct{}{}
+
+		// skip over the first offsetValue rows
+		for j := int64(0); j < offsetValue; j++ {
+			_, err := i.child.Next(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return i.child.Next(ctx)
+}
@@ -257,8 +257,8 @@ func (s *OrderBySorter) Less(i, j int) bool {
 			return true
 
 		case *parser.DataTypeID:
-			avInt, aok := av.(uint64)
-			bvInt, bok := bv.(uint64)
+			avInt, aok := orderByIDValue(av)
+			bvInt, bok := orderByIDValue(bv)
 			if !(aok && bok) {
 				s.LastError = sql3.NewErrInternalf("unexpected type conversion result")
 				return false
@@ -304,6 +304,18 @@ func (s *OrderBySorter) Less(i, j int) bool {
 			}
 			return true
 
+		case *parser.DataTypeDouble:
+			avDouble, aok := av.(float64)
+			bvDouble, bok := bv.(float64)
+			if !(aok && bok) {
+				s.LastError = sql3.NewErrInternalf("unexpected type conversion result")
+				return false
+			}
+			if avDouble > bvDouble {
+				return false
+			}
+			return true
+
 		case *parser.DataTypeTimestamp:
 			avTime, aok := av.(time.Time)
 			bvTime, bok := bv.(time.Time)
@@ -324,3 +336,15 @@ func (s *OrderBySorter) Less(i, j int) bool {
 
 	return false
 }
+
+// orderByIDValue returns the value of an id, which can be either an int64 (as
+// it is when read from a table) or a uint64.
+func orderByIDValue(v interface{}) (uint64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return uint64(v), true
+	case uint64:
+		return v, true
+	}
+	return 0, false
+}
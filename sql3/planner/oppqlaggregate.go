@@ -21,6 +21,10 @@ type PlanOpPQLAggregate struct {
 	filter    types.PlanExpression
 	aggregate types.Aggregable
 
+	// timeQuantumFilters are the time quantum filters of the table scan this
+	// operator replaced
+	timeQuantumFilters []types.PlanExpression
+
 	warnings []string
 }
 
@@ -42,6 +46,13 @@ func (p *PlanOpPQLAggregate) Plan() map[string]interface{} {
 	if p.filter != nil {
 		result["filter"] = p.filter.Plan()
 	}
+	if len(p.timeQuantumFilters) > 0 {
+		tqfilters := make([]interface{}, len(p.timeQuantumFilters))
+		for i, f := range p.timeQuantumFilters {
+			tqfilters[i] = f.Plan()
+		}
+		result["timeQuantumFilters"] = tqfilters
+	}
 	result["aggregate"] = p.aggregate.String()
 	return result
 
@@ -76,22 +87,26 @@ func (p *PlanOpPQLAggregate) Children() []types.PlanOperator {
 
 func (p *PlanOpPQLAggregate) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
 	return &pqlAggregateRowIter{
-		planner:   p.planner,
-		tableName: p.tableName,
-		filter:    p.filter,
-		aggregate: p.aggregate,
+		planner:            p.planner,
+		tableName:          p.tableName,
+		filter:             p.filter,
+		timeQuantumFilters: p.timeQuantumFilters,
+		aggregate:          p.aggregate,
 	}, nil
 }
 
 func (p *PlanOpPQLAggregate) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
-	return NewPlanOpPQLAggregate(p.planner, p.tableName, p.aggregate, p.filter), nil
+	op := NewPlanOpPQLAggregate(p.planner, p.tableName, p.aggregate, p.filter)
+	op.timeQuantumFilters = p.timeQuantumFilters
+	return op, nil
 }
 
 type pqlAggregateRowIter struct {
-	planner   *ExecutionPlanner
-	tableName string
-	filter    types.PlanExpression
-	aggregate types.Aggregable
+	planner            *ExecutionPlanner
+	tableName          string
+	filter             types.PlanExpression
+	timeQuantumFilters []types.PlanExpression
+	aggregate          types.Aggregable
 
 	resultValue interface{}
 }
@@ -109,7 +124,7 @@ func (i *pqlAggregateRowIter) Next(ctx context.Context) (types.Row, error) {
 			return nil, err
 		}
 
-		cond, err = i.planner.generatePQLCallFromExpr(ctx, i.filter)
+		cond, err = i.planner.generatePQLFilterCall(ctx, i.filter, i.timeQuantumFilters)
 		if err != nil {
 			return nil, err
 		}
@@ -141,7 +156,7 @@ func (i *pqlAggregateRowIter) Next(ctx context.Context) (types.Row, error) {
 				// if the data type of the expression supports an existence bitmap for
 				// the underlying FeatureBase data type use it to eliminate nulls from the aggregate
 				switch expr.dataType.(type) {
-				case *parser.DataTypeInt, *parser.DataTypeTimestamp, *parser.DataTypeDecimal:
+				case *parser.DataTypeInt, *parser.DataTypeTimestamp, *parser.DataTypeDecimal, *parser.DataTypeDouble:
 					cond = &pql.Call{
 						Name: "Row",
 						Args: map[string]interface{}{
@@ -160,7 +175,7 @@ func (i *pqlAggregateRowIter) Next(ctx context.Context) (types.Row, error) {
 				// if the data type of the expression supports an existence bitmap for
 				// the underlying FeatureBase data type use it to eliminate nulls from the aggregate
 				switch expr.dataType.(type) {
-				case *parser.DataTypeInt, *parser.DataTypeTimestamp, *parser.DataTypeDecimal:
+				case *parser.DataTypeInt, *parser.DataTypeTimestamp, *parser.DataTypeDecimal, *parser.DataTypeDouble:
 					cond = &pql.Call{
 						Name: "Row",
 						Args: map[string]interface{}{
@@ -288,6 +303,18 @@ func (i *pqlAggregateRowIter) Next(ctx context.Context) (types.Row, error) {
 					i.resultValue = *actualResult.DecimalVal
 				}
 
+			case *parser.DataTypeDouble:
+				_, isAvg := i.aggregate.(*avgPlanExpression)
+				if isAvg {
+					if actualResult.Count == 0 {
+						i.resultValue = nil
+					} else {
+						i.resultValue = actualResult.FloatVal / float64(actualResult.Count)
+					}
+				} else {
+					i.resultValue = actualResult.FloatVal
+				}
+
 			case *parser.DataTypeTimestamp:
 				i.resultValue = actualResult.TimestampVal
 
@@ -84,27 +84,7 @@ func (p *PlanOpPQLConstRowDelete) WithChildren(children ...types.PlanOperator) (
 func (p *PlanOpPQLConstRowDelete) Expressions() []types.PlanExpression {
 	// since we have to do const row lookups, we should always reference the _id column in the
 	// table we are deleting from
-
-	tbl, err := p.planner.schemaAPI.TableByName(context.Background(), dax.TableName(p.tableName))
-	if err != nil {
-		return []types.PlanExpression{}
-	}
-
-	var colType parser.ExprDataType
-	if tbl.StringKeys() {
-		colType = parser.NewDataTypeString()
-	} else {
-		colType = parser.NewDataTypeID()
-	}
-
-	return []types.PlanExpression{
-		&qualifiedRefPlanExpression{
-			tableName:   p.tableName,
-			columnIndex: 0,
-			dataType:    colType,
-			columnName:  string(dax.PrimaryKeyFieldName),
-		},
-	}
+	return p.planner.primaryKeyExpressions(p.tableName)
 }
 
 func (p *PlanOpPQLConstRowDelete) WithUpdatedExpressions(exprs ...types.PlanExpression) (types.PlanOperator, error) {
@@ -169,3 +149,28 @@ func (i *constRowDeleteRowIter) Next(ctx context.Context) (types.Row, error) {
 	}
 	return nil, types.ErrNoMoreRows
 }
+
+// primaryKeyExpressions returns an expression referencing the _id column of
+// the given table, for operators which work on whole records.
+func (p *ExecutionPlanner) primaryKeyExpressions(tableName string) []types.PlanExpression {
+	tbl, err := p.schemaAPI.TableByName(context.Background(), dax.TableName(tableName))
+	if err != nil {
+		return []types.PlanExpression{}
+	}
+
+	var colType parser.ExprDataType
+	if tbl.StringKeys() {
+		colType = parser.NewDataTypeString()
+	} else {
+		colType = parser.NewDataTypeID()
+	}
+
+	return []types.PlanExpression{
+		&qualifiedRefPlanExpression{
+			tableName:   tableName,
+			columnIndex: 0,
+			dataType:    colType,
+			columnName:  string(dax.PrimaryKeyFieldName),
+		},
+	}
+}
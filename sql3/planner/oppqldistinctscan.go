@@ -264,6 +264,21 @@ func (i *distinctScanRowIter) Next(ctx context.Context) (types.Row, error) {
 			}
 			row[0] = pql.NewDecimal(val, t.Scale)
 
+		case *parser.DataTypeDouble:
+			val, ok := result.(int64)
+			if !ok {
+				return nil, sql3.NewErrInternalf("unexpected type for column value '%T'", result)
+			}
+			row[0] = pilosa.ValToFloat(val)
+
+		case *parser.DataTypeGeoPoint:
+			val, ok := result.(int64)
+			if !ok {
+				return nil, sql3.NewErrInternalf("unexpected type for column value '%T'", result)
+			}
+			lat, lon := unpackGeoPoint(val)
+			row[0] = []float64{lat, lon}
+
 		case *parser.DataTypeIDSet:
 			val, ok := result.(int64)
 			if !ok {
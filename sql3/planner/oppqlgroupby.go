@@ -22,6 +22,10 @@ type PlanOpPQLGroupBy struct {
 	aggregate    types.Aggregable
 	groupByExprs []types.PlanExpression
 
+	// timeQuantumFilters are the time quantum filters of the table scan this
+	// operator replaced
+	timeQuantumFilters []types.PlanExpression
+
 	warnings []string
 }
 
@@ -44,6 +48,13 @@ func (p *PlanOpPQLGroupBy) Plan() map[string]interface{} {
 	if p.filter != nil {
 		result["filter"] = p.filter.Plan()
 	}
+	if len(p.timeQuantumFilters) > 0 {
+		tqfilters := make([]interface{}, len(p.timeQuantumFilters))
+		for i, f := range p.timeQuantumFilters {
+			tqfilters[i] = f.Plan()
+		}
+		result["timeQuantumFilters"] = tqfilters
+	}
 	result["aggregate"] = p.aggregate.String()
 	ps := make([]interface{}, 0)
 	for _, e := range p.groupByExprs {
@@ -95,11 +106,12 @@ func (p *PlanOpPQLGroupBy) Children() []types.PlanOperator {
 
 func (p *PlanOpPQLGroupBy) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
 	return &pqlGroupByRowIter{
-		planner:        p.planner,
-		tableName:      p.tableName,
-		groupByColumns: p.groupByExprs,
-		aggregate:      p.aggregate,
-		filter:         p.filter,
+		planner:            p.planner,
+		tableName:          p.tableName,
+		groupByColumns:     p.groupByExprs,
+		aggregate:          p.aggregate,
+		filter:             p.filter,
+		timeQuantumFilters: p.timeQuantumFilters,
 	}, nil
 }
 
@@ -111,11 +123,12 @@ func (p *PlanOpPQLGroupBy) WithChildren(children ...types.PlanOperator) (types.P
 // it provides rows consisting of the group by columns in the order they
 // were specified and lastly the aggregate
 type pqlGroupByRowIter struct {
-	planner        *ExecutionPlanner
-	tableName      string
-	groupByColumns []types.PlanExpression
-	filter         types.PlanExpression
-	aggregate      types.Aggregable
+	planner            *ExecutionPlanner
+	tableName          string
+	groupByColumns     []types.PlanExpression
+	filter             types.PlanExpression
+	timeQuantumFilters []types.PlanExpression
+	aggregate          types.Aggregable
 
 	result []pilosa.GroupCount
 }
@@ -133,7 +146,7 @@ func (i *pqlGroupByRowIter) Next(ctx context.Context) (types.Row, error) {
 			return nil, err
 		}
 
-		cond, err = i.planner.generatePQLCallFromExpr(ctx, i.filter)
+		cond, err = i.planner.generatePQLFilterCall(ctx, i.filter, i.timeQuantumFilters)
 		if err != nil {
 			return nil, err
 		}
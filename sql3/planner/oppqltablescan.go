@@ -28,10 +28,21 @@ type PlanOpPQLTableScan struct {
 	filter             types.PlanExpression
 	timeQuantumFilters []types.PlanExpression
 	topExpr            types.PlanExpression
+	offsetExpr         types.PlanExpression
+	nearest            *nearestScan
 	hints              []*TableQueryHint
 	warnings           []string
 }
 
+// nearestScan limits a table scan to the k records with the bitvectors in
+// column nearest to vector.
+type nearestScan struct {
+	column string
+	width  int64
+	vector string
+	k      int64
+}
+
 func NewPlanOpPQLTableScan(p *ExecutionPlanner, tableName string, columns []string, hints []*TableQueryHint) *PlanOpPQLTableScan {
 	return &PlanOpPQLTableScan{
 		planner:            p,
@@ -52,9 +63,19 @@ func (p *PlanOpPQLTableScan) Plan() map[string]interface{} {
 	if p.topExpr != nil {
 		result["topExpr"] = p.topExpr.Plan()
 	}
+	if p.offsetExpr != nil {
+		result["offsetExpr"] = p.offsetExpr.Plan()
+	}
 	if p.filter != nil {
 		result["filter"] = p.filter.Plan()
 	}
+	if p.nearest != nil {
+		result["nearest"] = map[string]interface{}{
+			"column": p.nearest.column,
+			"vector": p.nearest.vector,
+			"k":      p.nearest.k,
+		}
+	}
 	tqfilters := make([]map[string]interface{}, len(p.timeQuantumFilters))
 	for i, f := range p.timeQuantumFilters {
 		tqfilters[i] = f.Plan()
@@ -130,6 +151,8 @@ func (p *PlanOpPQLTableScan) Iterator(ctx context.Context, row types.Row) (types
 		predicate:          p.filter,
 		timeQuantumFilters: p.timeQuantumFilters,
 		topExpr:            p.topExpr,
+		offsetExpr:         p.offsetExpr,
+		nearest:            p.nearest,
 	}, nil
 }
 
@@ -164,6 +187,8 @@ type tableScanRowIter struct {
 	predicate          types.PlanExpression
 	timeQuantumFilters []types.PlanExpression
 	topExpr            types.PlanExpression
+	offsetExpr         types.PlanExpression
+	nearest            *nearestScan
 
 	result    []pilosa.ExtractedTableColumn
 	rowWidth  int
@@ -207,27 +232,63 @@ func (i *tableScanRowIter) Next(ctx context.Context) (types.Row, error) {
 
 		var cond *pql.Call
 
-		cond, err = i.planner.generatePQLCallFromExpr(ctx, i.predicate)
+		// Conditions on a prefix of a primary key are answered by the
+		// table's translator.
+		predicate, keyCond, err := compositeKeyCondition(table, i.predicate)
 		if err != nil {
 			return nil, err
 		}
+
+		cond, err = i.planner.generatePQLCallFromExpr(ctx, predicate)
+		if err != nil {
+			return nil, err
+		}
+		if keyCond != nil {
+			if cond == nil {
+				cond = keyCond
+			} else {
+				cond = &pql.Call{Name: "Intersect", Children: []*pql.Call{keyCond, cond}}
+			}
+		}
 		if cond == nil {
 			cond = &pql.Call{Name: "All"}
 		}
 
-		if i.topExpr != nil {
-			_, ok := i.topExpr.(*intLiteralPlanExpression)
-			if !ok {
-				return nil, sql3.NewErrInternalf("unexpected top expression type: %T", i.topExpr)
-			}
-			pqlValue, err := planExprToValue(i.topExpr)
+		if i.nearest != nil {
+			cond, err = i.nearestCondition(ctx, table, cond)
 			if err != nil {
 				return nil, err
 			}
+		}
+
+		if i.topExpr != nil || i.offsetExpr != nil {
+			args := make(map[string]interface{})
+			if i.topExpr != nil {
+				_, ok := i.topExpr.(*intLiteralPlanExpression)
+				if !ok {
+					return nil, sql3.NewErrInternalf("unexpected top expression type: %T", i.topExpr)
+				}
+				pqlValue, err := planExprToValue(i.topExpr)
+				if err != nil {
+					return nil, err
+				}
+				args["limit"] = pqlValue
+			}
+			if i.offsetExpr != nil {
+				_, ok := i.offsetExpr.(*intLiteralPlanExpression)
+				if !ok {
+					return nil, sql3.NewErrInternalf("unexpected offset expression type: %T", i.offsetExpr)
+				}
+				pqlValue, err := planExprToValue(i.offsetExpr)
+				if err != nil {
+					return nil, err
+				}
+				args["offset"] = pqlValue
+			}
 			cond = &pql.Call{
 				Name:     "Limit",
 				Children: []*pql.Call{cond},
-				Args:     map[string]interface{}{"limit": pqlValue},
+				Args:     args,
 				Type:     pql.PrecallGlobal,
 			}
 		}
@@ -319,7 +380,7 @@ func (i *tableScanRowIter) Next(ctx context.Context) (types.Row, error) {
 					row[mappedColIdx] = int64(result.Column.ID)
 				}
 			} else {
-				switch mappedColumn.dataType.(type) {
+				switch dt := mappedColumn.dataType.(type) {
 				case *parser.DataTypeIDSet:
 					val, ok := result.Rows[mappedSrcColIdx].([]uint64)
 					if !ok {
@@ -342,6 +403,35 @@ func (i *tableScanRowIter) Next(ctx context.Context) (types.Row, error) {
 						row[mappedColIdx] = val
 					}
 
+				case *parser.DataTypeBitVector:
+					val, ok := result.Rows[mappedSrcColIdx].([]uint64)
+					if !ok {
+						return nil, sql3.NewErrInternalf("unexpected type for column value '%T'", result.Rows[mappedSrcColIdx])
+					}
+					row[mappedColIdx] = bitVectorFromRows(val, dt.Width)
+
+				case *parser.DataTypeJSON:
+					val, ok := result.Rows[mappedSrcColIdx].([]uint64)
+					if !ok {
+						return nil, sql3.NewErrInternalf("unexpected type for column value '%T'", result.Rows[mappedSrcColIdx])
+					}
+					doc, err := jsonFromRows(val)
+					if err != nil {
+						return nil, err
+					}
+					row[mappedColIdx] = doc
+
+				case *parser.DataTypeGeoPoint:
+					switch val := result.Rows[mappedSrcColIdx].(type) {
+					case int64:
+						lat, lon := unpackGeoPoint(val)
+						row[mappedColIdx] = []float64{lat, lon}
+					case nil:
+						row[mappedColIdx] = nil
+					default:
+						return nil, sql3.NewErrInternalf("unexpected type for column value '%T'", val)
+					}
+
 				default:
 					row[mappedColIdx] = result.Rows[mappedSrcColIdx]
 				}
@@ -354,3 +444,65 @@ func (i *tableScanRowIter) Next(ctx context.Context) (types.Row, error) {
 	}
 	return nil, types.ErrNoMoreRows
 }
+
+// nearestCondition returns the condition for the records to read when only
+// the nearest vectors are wanted. Records without a vector sort before them,
+// so up to k of those are read as well.
+func (i *tableScanRowIter) nearestCondition(ctx context.Context, tbl *dax.Table, cond *pql.Call) (*pql.Call, error) {
+	nearest := &pql.Call{
+		Name: "Nearest",
+		Args: map[string]interface{}{
+			"field":  i.nearest.column,
+			"vector": i.nearest.vector,
+			"k":      i.nearest.k,
+		},
+	}
+	if cond.Name != "All" {
+		nearest.Args["filter"] = cond.Clone()
+	}
+	queryResponse, err := i.planner.executor.Execute(ctx, tbl, &pql.Query{Calls: []*pql.Call{nearest}}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	pairs, ok := queryResponse.Results[0].(*pilosa.PairsField)
+	if !ok {
+		return nil, sql3.NewErrInternalf("unexpected Nearest() result type: %T", queryResponse.Results[0])
+	}
+	columns := make([]interface{}, len(pairs.Pairs))
+	for j, pair := range pairs.Pairs {
+		if tbl.StringKeys() {
+			columns[j] = pair.Key
+		} else {
+			columns[j] = pair.ID
+		}
+	}
+
+	return &pql.Call{
+		Name: "Union",
+		Children: []*pql.Call{
+			{
+				Name: "ConstRow",
+				Args: map[string]interface{}{"columns": columns},
+				Type: pql.PrecallGlobal,
+			},
+			{
+				Name: "Limit",
+				Children: []*pql.Call{{
+					Name: "Intersect",
+					Children: []*pql.Call{
+						cond,
+						{
+							Name: "Not",
+							Children: []*pql.Call{{
+								Name: "Row",
+								Args: map[string]interface{}{i.nearest.column: i.nearest.width},
+							}},
+						},
+					},
+				}},
+				Args: map[string]interface{}{"limit": i.nearest.k},
+				Type: pql.PrecallGlobal,
+			},
+		},
+	}, nil
+}
@@ -4,13 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"time"
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
 	fbcontext "github.com/featurebasedb/featurebase/v3/context"
 	"github.com/featurebasedb/featurebase/v3/sql3"
 	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
-	"github.com/pkg/errors"
 )
 
 // PlanOpQuery is a query - this is the root node of an execution plan
@@ -43,12 +43,15 @@ func (p *PlanOpQuery) Child() types.PlanOperator {
 }
 
 func (p *PlanOpQuery) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
-	iter, err := p.ChildOp.Iterator(ctx, row)
+	// some iterators hang on to the context they are created with, so it
+	// needs to be accumulating stats too
+	stats := &pilosa.QueryStats{}
+	iter, err := p.ChildOp.Iterator(pilosa.WithQueryStats(ctx, stats), row)
 	if err != nil {
 		return nil, err
 	}
 
-	return newQueryIterator(p.planner.systemLayerAPI.ExecutionRequests(), p, iter), nil
+	return newQueryIterator(p.planner.systemLayerAPI.ExecutionRequests(), p, iter, stats), nil
 }
 
 func (p *PlanOpQuery) Children() []types.PlanOperator {
@@ -94,20 +97,32 @@ func (p *PlanOpQuery) String() string {
 	return ""
 }
 
+// queryIterator tracks the execution of a query, and adds it to the query
+// history once it finishes: when its rows have been read to the end, when
+// reading them fails, or when it is closed before all of them are read.
 type queryIterator struct {
 	requests pilosa.ExecutionRequestsAPI
 	query    *PlanOpQuery
 
 	child types.RowIterator
+	stats *pilosa.QueryStats
 
 	hasStarted *struct{}
+	done       bool
+	requestId  string
+	userId     string
+	start      time.Time
+	rowCount   int64
 }
 
-func newQueryIterator(requests pilosa.ExecutionRequestsAPI, query *PlanOpQuery, child types.RowIterator) *queryIterator {
+var _ io.Closer = (*queryIterator)(nil)
+
+func newQueryIterator(requests pilosa.ExecutionRequestsAPI, query *PlanOpQuery, child types.RowIterator, stats *pilosa.QueryStats) *queryIterator {
 	return &queryIterator{
 		requests: requests,
 		query:    query,
 		child:    child,
+		stats:    stats,
 	}
 }
 
@@ -122,23 +137,59 @@ func (i *queryIterator) Next(ctx context.Context) (types.Row, error) {
 		userId := ""
 		userId, _ = fbcontext.UserID(ctx)
 
-		i.requests.AddRequest(requestId, userId, time.Now(), i.query.sql)
+		i.requestId = requestId
+		i.userId = userId
+		i.start = time.Now()
+		i.requests.AddRequest(requestId, userId, i.start, i.query.sql)
 		i.hasStarted = &struct{}{}
 	}
 
-	row, err := i.child.Next(ctx)
-	if err != nil {
+	row, err := i.child.Next(pilosa.WithQueryStats(ctx, i.stats))
+	if err == nil {
+		i.rowCount++
+	} else if !i.done {
 		// either error or no more rows, either way update the request
-		requestId, ok := fbcontext.RequestID(ctx)
-		if !ok {
-			return nil, errors.Wrapf(sql3.NewErrInternalf("unable to get request id from context"), "next on child: %s", err)
-		}
-
-		plan, err := json.MarshalIndent(i.query.Plan(), "", "    ")
-		if err != nil {
-			i.query.planner.logger.Infof("marshal indent: %s", err)
-		}
-		i.requests.UpdateRequest(requestId, time.Now(), "complete", "", 0, "", 0, 0, 0, 0, 0, string(plan))
+		plan := i.finish(err)
+		i.requests.UpdateRequest(i.requestId, time.Now(), "complete", "", 0, "", 0, 0, 0, 0, 0, plan)
 	}
 	return row, err
 }
+
+// Close adds the query to the query history if it was started but its rows
+// weren't read to the end. Whoever is reading them is responsible for the
+// state of its request, such as a cursor which has expired.
+func (i *queryIterator) Close() error {
+	if i.hasStarted != nil && !i.done {
+		i.finish(nil)
+	}
+	return nil
+}
+
+// finish marks the query as done, adds it to the query history of this node,
+// and returns its plan formatted in json. err is the error the query ended
+// with, which is nil or types.ErrNoMoreRows if it didn't fail.
+func (i *queryIterator) finish(err error) string {
+	i.done = true
+
+	plan, merr := json.MarshalIndent(i.query.Plan(), "", "    ")
+	if merr != nil {
+		i.query.planner.logger.Infof("marshal indent: %s", merr)
+	}
+
+	entry := pilosa.QueryHistoryEntry{
+		RequestID:     i.requestId,
+		NodeID:        i.query.planner.systemAPI.NodeID(),
+		User:          i.userId,
+		SQL:           i.query.sql,
+		Start:         i.start,
+		End:           time.Now(),
+		RowCount:      i.rowCount,
+		ShardsScanned: i.stats.ShardsScanned(),
+		Plan:          string(plan),
+	}
+	if err != nil && err != types.ErrNoMoreRows {
+		entry.Error = err.Error()
+	}
+	i.query.planner.systemAPI.QueryHistory().AddQuery(entry)
+	return string(plan)
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/dax"
@@ -28,6 +29,15 @@ const (
 	fbClusterNodes = "fb_cluster_nodes"
 
 	fbTableDDL = "fb_table_ddl"
+
+	fbTableStorage    = "fb_table_storage"
+	fbFragmentStorage = "fb_fragment_storage"
+	fbRBFFiles        = "fb_rbf_files"
+	fbTranslateStores = "fb_translate_stores"
+
+	fbQueryHistory = "fb_query_history"
+
+	fbColumnAlterations = "fb_column_alterations"
 )
 
 type systemTable struct {
@@ -304,6 +314,308 @@ var systemTables = systemTableMap{
 		},
 		requiresFanout: true,
 	},
+
+	fbTableStorage: {
+		name: fbTableStorage,
+		schema: types.Schema{
+			&types.PlannerColumn{
+				RelationName: fbTableStorage,
+				ColumnName:   "nodeid",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbTableStorage,
+				ColumnName:   "table_name",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbTableStorage,
+				ColumnName:   "disk_usage",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbTableStorage,
+				ColumnName:   "shard_count",
+				Type:         parser.NewDataTypeInt(),
+			},
+		},
+		requiresFanout: true,
+	},
+
+	fbFragmentStorage: {
+		name: fbFragmentStorage,
+		schema: types.Schema{
+			&types.PlannerColumn{
+				RelationName: fbFragmentStorage,
+				ColumnName:   "nodeid",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbFragmentStorage,
+				ColumnName:   "table_name",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbFragmentStorage,
+				ColumnName:   "field_name",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbFragmentStorage,
+				ColumnName:   "view_name",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbFragmentStorage,
+				ColumnName:   "shard",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbFragmentStorage,
+				ColumnName:   "row_count",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbFragmentStorage,
+				ColumnName:   "bit_count",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbFragmentStorage,
+				ColumnName:   "container_count",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbFragmentStorage,
+				ColumnName:   "array_containers",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbFragmentStorage,
+				ColumnName:   "run_containers",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbFragmentStorage,
+				ColumnName:   "bitmap_containers",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbFragmentStorage,
+				ColumnName:   "size_bytes",
+				Type:         parser.NewDataTypeInt(),
+			},
+		},
+		requiresFanout: true,
+	},
+
+	fbRBFFiles: {
+		name: fbRBFFiles,
+		schema: types.Schema{
+			&types.PlannerColumn{
+				RelationName: fbRBFFiles,
+				ColumnName:   "nodeid",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbRBFFiles,
+				ColumnName:   "table_name",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbRBFFiles,
+				ColumnName:   "shard",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbRBFFiles,
+				ColumnName:   "file_path",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbRBFFiles,
+				ColumnName:   "file_bytes",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbRBFFiles,
+				ColumnName:   "wal_bytes",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbRBFFiles,
+				ColumnName:   "open_tx_count",
+				Type:         parser.NewDataTypeInt(),
+			},
+		},
+		requiresFanout: true,
+	},
+
+	fbTranslateStores: {
+		name: fbTranslateStores,
+		schema: types.Schema{
+			&types.PlannerColumn{
+				RelationName: fbTranslateStores,
+				ColumnName:   "nodeid",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbTranslateStores,
+				ColumnName:   "table_name",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbTranslateStores,
+				ColumnName:   "field_name",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbTranslateStores,
+				ColumnName:   "partition_id",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbTranslateStores,
+				ColumnName:   "file_path",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbTranslateStores,
+				ColumnName:   "size_bytes",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbTranslateStores,
+				ColumnName:   "max_id",
+				Type:         parser.NewDataTypeInt(),
+			},
+		},
+		requiresFanout: true,
+	},
+
+	fbQueryHistory: {
+		name: fbQueryHistory,
+		schema: types.Schema{
+			&types.PlannerColumn{
+				RelationName: fbQueryHistory,
+				ColumnName:   "nodeid",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbQueryHistory,
+				ColumnName:   "request_id",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbQueryHistory,
+				ColumnName:   "user",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbQueryHistory,
+				ColumnName:   "table_name",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbQueryHistory,
+				ColumnName:   "start_time",
+				Type:         parser.NewDataTypeTimestamp(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbQueryHistory,
+				ColumnName:   "end_time",
+				Type:         parser.NewDataTypeTimestamp(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbQueryHistory,
+				ColumnName:   "elapsed_time",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbQueryHistory,
+				ColumnName:   "row_count",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbQueryHistory,
+				ColumnName:   "shards_scanned",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbQueryHistory,
+				ColumnName:   "error",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbQueryHistory,
+				ColumnName:   "sql",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbQueryHistory,
+				ColumnName:   "pql",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbQueryHistory,
+				ColumnName:   "plan",
+				Type:         parser.NewDataTypeString(),
+			},
+		},
+		requiresFanout: true,
+	},
+
+	fbColumnAlterations: {
+		name: fbColumnAlterations,
+		schema: types.Schema{
+			&types.PlannerColumn{
+				RelationName: fbColumnAlterations,
+				ColumnName:   "nodeid",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbColumnAlterations,
+				ColumnName:   "table_name",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbColumnAlterations,
+				ColumnName:   "column_name",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbColumnAlterations,
+				ColumnName:   "status",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbColumnAlterations,
+				ColumnName:   "shards_done",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbColumnAlterations,
+				ColumnName:   "shards_total",
+				Type:         parser.NewDataTypeInt(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbColumnAlterations,
+				ColumnName:   "error",
+				Type:         parser.NewDataTypeString(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbColumnAlterations,
+				ColumnName:   "start_time",
+				Type:         parser.NewDataTypeTimestamp(),
+			},
+			&types.PlannerColumn{
+				RelationName: fbColumnAlterations,
+				ColumnName:   "end_time",
+				Type:         parser.NewDataTypeTimestamp(),
+			},
+		},
+		requiresFanout: true,
+	},
 }
 
 // PlanOpSystemTable handles system tables
@@ -370,6 +682,30 @@ func (p *PlanOpSystemTable) Iterator(ctx context.Context, row types.Row) (types.
 		return &fbPerformanceCountersRowIter{
 			planner: p.planner,
 		}, nil
+	case fbTableStorage:
+		return &fbTableStorageRowIter{
+			planner: p.planner,
+		}, nil
+	case fbFragmentStorage:
+		return &fbFragmentStorageRowIter{
+			planner: p.planner,
+		}, nil
+	case fbRBFFiles:
+		return &fbRBFFilesRowIter{
+			planner: p.planner,
+		}, nil
+	case fbTranslateStores:
+		return &fbTranslateStoresRowIter{
+			planner: p.planner,
+		}, nil
+	case fbQueryHistory:
+		return &fbQueryHistoryRowIter{
+			planner: p.planner,
+		}, nil
+	case fbColumnAlterations:
+		return &fbColumnAlterationsRowIter{
+			planner: p.planner,
+		}, nil
 	default:
 		return nil, sql3.NewErrInternalf("unable to find system table '%s'", p.table.name)
 	}
@@ -595,6 +931,15 @@ func generateTableDDL(tbl *dax.Table, newName string) string {
 			}
 			// TODO(pok) how do we get epoch out of col?
 
+		case *parser.DataTypeJSON:
+			if len(col.Options.JSONPaths) > 0 {
+				paths := make([]string, len(col.Options.JSONPaths))
+				for i, path := range col.Options.JSONPaths {
+					paths[i] = "'" + strings.ReplaceAll(path, "'", "''") + "'"
+				}
+				fmt.Fprintf(&buf, " index paths (%s)", strings.Join(paths, ", "))
+			}
+
 		}
 	}
 	buf.WriteString(");")
@@ -671,3 +1016,239 @@ func (i *fbPerformanceCountersRowIter) Next(ctx context.Context) (types.Row, err
 	}
 	return nil, types.ErrNoMoreRows
 }
+
+type fbTableStorageRowIter struct {
+	planner *ExecutionPlanner
+	result  []pilosa.TableStorageInfo
+}
+
+var _ types.RowIterator = (*fbTableStorageRowIter)(nil)
+
+func (i *fbTableStorageRowIter) Next(ctx context.Context) (types.Row, error) {
+	if i.result == nil {
+		var err error
+		i.result, err = i.planner.systemAPI.TableStorage(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nodeId := i.planner.systemAPI.NodeID()
+	if len(i.result) > 0 {
+		n := i.result[0]
+		row := []interface{}{
+			nodeId,
+			n.Index,
+			n.DiskUsage,
+			n.ShardN,
+		}
+		// Move to next result element.
+		i.result = i.result[1:]
+		return row, nil
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+type fbFragmentStorageRowIter struct {
+	planner *ExecutionPlanner
+	result  []pilosa.FragmentStorageInfo
+}
+
+var _ types.RowIterator = (*fbFragmentStorageRowIter)(nil)
+
+func (i *fbFragmentStorageRowIter) Next(ctx context.Context) (types.Row, error) {
+	if i.result == nil {
+		var err error
+		i.result, err = i.planner.systemAPI.FragmentStorage(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nodeId := i.planner.systemAPI.NodeID()
+	if len(i.result) > 0 {
+		n := i.result[0]
+		row := []interface{}{
+			nodeId,
+			n.Index,
+			n.Field,
+			n.View,
+			int64(n.Shard),
+			n.RowN,
+			n.BitN,
+			n.ContainerN,
+			n.ArrayContainerN,
+			n.RunContainerN,
+			n.BitmapContainerN,
+			n.Size,
+		}
+		// Move to next result element.
+		i.result = i.result[1:]
+		return row, nil
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+type fbRBFFilesRowIter struct {
+	planner *ExecutionPlanner
+	result  []pilosa.RBFFileInfo
+}
+
+var _ types.RowIterator = (*fbRBFFilesRowIter)(nil)
+
+func (i *fbRBFFilesRowIter) Next(ctx context.Context) (types.Row, error) {
+	if i.result == nil {
+		var err error
+		i.result, err = i.planner.systemAPI.RBFFiles(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nodeId := i.planner.systemAPI.NodeID()
+	if len(i.result) > 0 {
+		n := i.result[0]
+		row := []interface{}{
+			nodeId,
+			n.Index,
+			int64(n.Shard),
+			n.Path,
+			n.Size,
+			n.WALSize,
+			n.TxN,
+		}
+		// Move to next result element.
+		i.result = i.result[1:]
+		return row, nil
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+type fbTranslateStoresRowIter struct {
+	planner *ExecutionPlanner
+	result  []pilosa.TranslateStoreInfo
+}
+
+var _ types.RowIterator = (*fbTranslateStoresRowIter)(nil)
+
+func (i *fbTranslateStoresRowIter) Next(ctx context.Context) (types.Row, error) {
+	if i.result == nil {
+		var err error
+		i.result, err = i.planner.systemAPI.TranslateStores(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nodeId := i.planner.systemAPI.NodeID()
+	if len(i.result) > 0 {
+		n := i.result[0]
+		// index stores have no field, and field stores have no partition
+		var fieldName, partitionID interface{}
+		if n.Field != "" {
+			fieldName = n.Field
+		} else {
+			partitionID = int64(n.PartitionID)
+		}
+		row := []interface{}{
+			nodeId,
+			n.Index,
+			fieldName,
+			partitionID,
+			n.Path,
+			n.Size,
+			int64(n.MaxID),
+		}
+		// Move to next result element.
+		i.result = i.result[1:]
+		return row, nil
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+type fbQueryHistoryRowIter struct {
+	planner *ExecutionPlanner
+	result  []pilosa.QueryHistoryEntry
+}
+
+var _ types.RowIterator = (*fbQueryHistoryRowIter)(nil)
+
+func (i *fbQueryHistoryRowIter) Next(ctx context.Context) (types.Row, error) {
+	if i.result == nil {
+		var err error
+		i.result, err = i.planner.systemAPI.QueryHistory().ListQueries()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(i.result) > 0 {
+		n := i.result[0]
+		// fields which don't apply to a query (an sql query has no
+		// pql, for example) are null rather than empty
+		row := []interface{}{
+			n.NodeID,
+			nullIfEmpty(n.RequestID),
+			nullIfEmpty(n.User),
+			nullIfEmpty(n.Index),
+			n.Start,
+			n.End,
+			n.End.Sub(n.Start).Microseconds(),
+			n.RowCount,
+			n.ShardsScanned,
+			nullIfEmpty(n.Error),
+			nullIfEmpty(n.SQL),
+			nullIfEmpty(n.PQL),
+			nullIfEmpty(n.Plan),
+		}
+		// Move to next result element.
+		i.result = i.result[1:]
+		return row, nil
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+type fbColumnAlterationsRowIter struct {
+	planner *ExecutionPlanner
+	result  []pilosa.FieldAlteration
+}
+
+var _ types.RowIterator = (*fbColumnAlterationsRowIter)(nil)
+
+func (i *fbColumnAlterationsRowIter) Next(ctx context.Context) (types.Row, error) {
+	if i.result == nil {
+		i.result = i.planner.systemAPI.FieldAlterations()
+	}
+
+	nodeId := i.planner.systemAPI.NodeID()
+	if len(i.result) > 0 {
+		n := i.result[0]
+		// an alteration which is still running has no end time
+		var end interface{}
+		if !n.End.IsZero() {
+			end = n.End
+		}
+		row := []interface{}{
+			nodeId,
+			n.Index,
+			n.Field,
+			n.Status,
+			n.ShardsDone,
+			n.ShardsTotal,
+			nullIfEmpty(n.Error),
+			n.Start,
+			end,
+		}
+		// Move to next result element.
+		i.result = i.result[1:]
+		return row, nil
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,805 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/roaring"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+	"github.com/pkg/errors"
+)
+
+// PlanOpTransaction plan operator handles a BEGIN ... COMMIT block. The
+// operators for the statements in the block write to a staging importer
+// rather than to the database, and keys they create are only looked up;
+// once they have all run, the keys are created and the staged writes are
+// applied together by the importer, while queries against the affected
+// tables are held off, so that readers see either none of the transaction
+// or all of it. If applying them fails, those applied are undone.
+//
+// Statements in a transaction don't see the writes of earlier statements
+// in the same transaction, since nothing is applied until COMMIT.
+type PlanOpTransaction struct {
+	planner  *ExecutionPlanner
+	staging  *stagingImporter
+	body     []types.PlanOperator
+	rollback bool
+	warnings []string
+}
+
+func NewPlanOpTransaction(p *ExecutionPlanner, staging *stagingImporter, body []types.PlanOperator, rollback bool) *PlanOpTransaction {
+	return &PlanOpTransaction{
+		planner:  p,
+		staging:  staging,
+		body:     body,
+		rollback: rollback,
+		warnings: make([]string, 0),
+	}
+}
+
+func (p *PlanOpTransaction) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["_schema"] = p.Schema().Plan()
+	result["rollback"] = p.rollback
+	ps := make([]interface{}, 0, len(p.body))
+	for _, op := range p.body {
+		ps = append(ps, op.Plan())
+	}
+	result["body"] = ps
+	return result
+}
+
+func (p *PlanOpTransaction) String() string {
+	return ""
+}
+
+func (p *PlanOpTransaction) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpTransaction) Warnings() []string {
+	var w []string
+	w = append(w, p.warnings...)
+	for _, op := range p.body {
+		w = append(w, op.Warnings()...)
+	}
+	return w
+}
+
+func (p *PlanOpTransaction) Schema() types.Schema {
+	return types.Schema{}
+}
+
+// Children returns no children; the body operators have already been
+// optimized individually and are not part of the plan tree.
+func (p *PlanOpTransaction) Children() []types.PlanOperator {
+	return []types.PlanOperator{}
+}
+
+func (p *PlanOpTransaction) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	return &transactionRowIter{
+		planner:  p.planner,
+		staging:  p.staging,
+		body:     p.body,
+		rollback: p.rollback,
+	}, nil
+}
+
+func (p *PlanOpTransaction) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	if len(children) != 0 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return p, nil
+}
+
+type transactionRowIter struct {
+	planner  *ExecutionPlanner
+	staging  *stagingImporter
+	body     []types.PlanOperator
+	rollback bool
+}
+
+var _ types.RowIterator = (*transactionRowIter)(nil)
+
+func (i *transactionRowIter) Next(ctx context.Context) (types.Row, error) {
+	// run each of the statements to completion, which stages their writes
+	for _, op := range i.body {
+		iter, err := op.Iterator(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		for {
+			_, err := iter.Next(ctx)
+			if err == types.ErrNoMoreRows {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if i.rollback {
+		return nil, types.ErrNoMoreRows
+	}
+
+	imports, err := i.staging.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	applier, ok := i.staging.target.(pilosa.ShardTransactionApplier)
+	if !ok {
+		return nil, sql3.NewErrInternalf("importer cannot apply transactions")
+	}
+	if err := applier.ApplyShardTransaction(ctx, imports); err != nil {
+		return nil, errors.Wrap(err, "committing transaction")
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+// PlanOpTransactionDelete plan operator stages the deletion of the records
+// produced by its child, as part of a transaction.
+type PlanOpTransactionDelete struct {
+	planner   *ExecutionPlanner
+	ChildOp   types.PlanOperator
+	tableName string
+	warnings  []string
+}
+
+func NewPlanOpTransactionDelete(p *ExecutionPlanner, tableName string, child types.PlanOperator) *PlanOpTransactionDelete {
+	return &PlanOpTransactionDelete{
+		planner:   p,
+		ChildOp:   child,
+		tableName: tableName,
+		warnings:  make([]string, 0),
+	}
+}
+
+func (p *PlanOpTransactionDelete) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["_schema"] = p.Schema().Plan()
+	result["child"] = p.ChildOp.Plan()
+	result["tableName"] = p.tableName
+	return result
+}
+
+func (p *PlanOpTransactionDelete) String() string {
+	return ""
+}
+
+func (p *PlanOpTransactionDelete) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpTransactionDelete) Warnings() []string {
+	return p.warnings
+}
+
+func (p *PlanOpTransactionDelete) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpTransactionDelete) Children() []types.PlanOperator {
+	return []types.PlanOperator{
+		p.ChildOp,
+	}
+}
+
+func (p *PlanOpTransactionDelete) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	childIter, err := p.ChildOp.Iterator(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transactionDeleteRowIter{
+		planner:   p.planner,
+		childIter: childIter,
+		tableName: p.tableName,
+	}, nil
+}
+
+func (p *PlanOpTransactionDelete) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	if len(children) != 1 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return NewPlanOpTransactionDelete(p.planner, p.tableName, children[0]), nil
+}
+
+func (p *PlanOpTransactionDelete) Expressions() []types.PlanExpression {
+	return p.planner.primaryKeyExpressions(p.tableName)
+}
+
+func (p *PlanOpTransactionDelete) WithUpdatedExpressions(exprs ...types.PlanExpression) (types.PlanOperator, error) {
+	// just return ourselves
+	return p, nil
+}
+
+type transactionDeleteRowIter struct {
+	planner   *ExecutionPlanner
+	childIter types.RowIterator
+	tableName string
+}
+
+var _ types.RowIterator = (*transactionDeleteRowIter)(nil)
+
+func (i *transactionDeleteRowIter) Next(ctx context.Context) (types.Row, error) {
+	err := i.planner.checkAccess(ctx, i.tableName, accessTypeWriteData)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err := i.planner.schemaAPI.TableByName(ctx, dax.TableName(i.tableName))
+	if err != nil {
+		return nil, sql3.NewErrTableNotFound(0, 0, i.tableName)
+	}
+
+	var ids []uint64
+	var keys []string
+	for {
+		row, err := i.childIter.Next(ctx)
+		if err == types.ErrNoMoreRows {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch id := row[0].(type) {
+		case string:
+			keys = append(keys, id)
+		case int64:
+			ids = append(ids, uint64(id))
+		default:
+			return nil, sql3.NewErrInternalf("unexpected _id type '%T'", row[0])
+		}
+	}
+
+	if len(keys) > 0 {
+		// the keys came from the table itself, so this only looks them up
+		translated, err := i.planner.importer.CreateTableKeys(ctx, tbl.ID, keys...)
+		if err != nil {
+			return nil, errors.Wrap(err, "translating keys")
+		}
+		for _, k := range keys {
+			ids = append(ids, translated[k])
+		}
+	}
+
+	// build a bitmap of the records to delete in each shard
+	byShard := make(map[uint64]*roaring.Bitmap)
+	for _, id := range ids {
+		shard := id / pilosa.ShardWidth
+		bm, ok := byShard[shard]
+		if !ok {
+			bm = roaring.NewBitmap()
+			byShard[shard] = bm
+		}
+		bm.DirectAdd(id % pilosa.ShardWidth)
+	}
+	for shard, bm := range byShard {
+		buf := &bytes.Buffer{}
+		if _, err := bm.WriteTo(buf); err != nil {
+			return nil, errors.Wrap(err, "serializing bitmap")
+		}
+		err := i.planner.staging.ImportRoaringShard(ctx, tbl.ID, shard, &pilosa.ImportRoaringShardRequest{
+			Remote: true,
+			Views: []pilosa.RoaringUpdate{{
+				Clear:        buf.Bytes(),
+				ClearRecords: true,
+			}},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+// PlanOpTransactionUpdate plan operator stages new values for the records
+// produced by its child, as part of a transaction.
+type PlanOpTransactionUpdate struct {
+	planner       *ExecutionPlanner
+	ChildOp       types.PlanOperator
+	tableName     string
+	id            types.PlanExpression
+	targetColumns []*qualifiedRefPlanExpression
+	values        []types.PlanExpression
+	clearFields   []string
+	warnings      []string
+}
+
+func NewPlanOpTransactionUpdate(p *ExecutionPlanner, tableName string, id types.PlanExpression, targetColumns []*qualifiedRefPlanExpression, values []types.PlanExpression, clearFields []string, child types.PlanOperator) *PlanOpTransactionUpdate {
+	return &PlanOpTransactionUpdate{
+		planner:       p,
+		ChildOp:       child,
+		tableName:     tableName,
+		id:            id,
+		targetColumns: targetColumns,
+		values:        values,
+		clearFields:   clearFields,
+		warnings:      make([]string, 0),
+	}
+}
+
+func (p *PlanOpTransactionUpdate) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["_schema"] = p.Schema().Plan()
+	result["child"] = p.ChildOp.Plan()
+	result["tableName"] = p.tableName
+	ps := make([]interface{}, 0)
+	for _, e := range p.values {
+		ps = append(ps, e.Plan())
+	}
+	result["values"] = ps
+	return result
+}
+
+func (p *PlanOpTransactionUpdate) String() string {
+	return ""
+}
+
+func (p *PlanOpTransactionUpdate) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpTransactionUpdate) Warnings() []string {
+	return p.warnings
+}
+
+func (p *PlanOpTransactionUpdate) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpTransactionUpdate) Children() []types.PlanOperator {
+	return []types.PlanOperator{
+		p.ChildOp,
+	}
+}
+
+func (p *PlanOpTransactionUpdate) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	childIter, err := p.ChildOp.Iterator(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transactionUpdateRowIter{
+		planner:       p.planner,
+		childIter:     childIter,
+		tableName:     p.tableName,
+		id:            p.id,
+		targetColumns: p.targetColumns,
+		values:        p.values,
+		clearFields:   p.clearFields,
+	}, nil
+}
+
+func (p *PlanOpTransactionUpdate) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	if len(children) != 1 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return NewPlanOpTransactionUpdate(p.planner, p.tableName, p.id, p.targetColumns, p.values, p.clearFields, children[0]), nil
+}
+
+// Expressions returns the reference to the records' _id, followed by the
+// values they're given.
+func (p *PlanOpTransactionUpdate) Expressions() []types.PlanExpression {
+	return append([]types.PlanExpression{p.id}, p.values...)
+}
+
+func (p *PlanOpTransactionUpdate) WithUpdatedExpressions(exprs ...types.PlanExpression) (types.PlanOperator, error) {
+	if len(exprs) != len(p.targetColumns)+1 {
+		return nil, sql3.NewErrInternalf("unexpected number of exprs '%d'", len(exprs))
+	}
+	return NewPlanOpTransactionUpdate(p.planner, p.tableName, exprs[0], p.targetColumns, exprs[1:], p.clearFields, p.ChildOp), nil
+}
+
+type transactionUpdateRowIter struct {
+	planner       *ExecutionPlanner
+	childIter     types.RowIterator
+	tableName     string
+	id            types.PlanExpression
+	targetColumns []*qualifiedRefPlanExpression
+	values        []types.PlanExpression
+	clearFields   []string
+}
+
+var _ types.RowIterator = (*transactionUpdateRowIter)(nil)
+
+func (i *transactionUpdateRowIter) Next(ctx context.Context) (types.Row, error) {
+	err := i.planner.checkAccess(ctx, i.tableName, accessTypeWriteData)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err := i.planner.schemaAPI.TableByName(ctx, dax.TableName(i.tableName))
+	if err != nil {
+		return nil, sql3.NewErrTableNotFound(0, 0, i.tableName)
+	}
+
+	// each record is written with its _id and the values it's given, which
+	// are worked out from its existing ones
+	targetColumns := append([]*qualifiedRefPlanExpression{
+		newQualifiedRefPlanExpression(i.tableName, string(dax.PrimaryKeyFieldName), 0, parser.NewDataTypeID()),
+	}, i.targetColumns...)
+	var tuples [][]types.PlanExpression
+	var rowNumbers []int
+	for {
+		row, err := i.childIter.Next(ctx)
+		if err == types.ErrNoMoreRows {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		tuple := make([]types.PlanExpression, 0, len(targetColumns))
+		tuple = append(tuple, &boundPlanExpression{PlanExpression: i.id, row: row})
+		for _, expr := range i.values {
+			tuple = append(tuple, &boundPlanExpression{PlanExpression: expr, row: row})
+		}
+		tuples = append(tuples, tuple)
+		rowNumbers = append(rowNumbers, len(rowNumbers))
+	}
+	if len(tuples) == 0 {
+		return nil, types.ErrNoMoreRows
+	}
+
+	ri := &insertRowIter{
+		planner:   i.planner,
+		tableName: i.tableName,
+	}
+	update, err := ri.prepare(ctx, tbl, targetColumns, tuples, rowNumbers)
+	if err != nil {
+		return nil, err
+	}
+	if len(i.clearFields) > 0 {
+		update.clearer = &recordClearer{fields: i.clearFields}
+	}
+	if _, err := update.importRows(ctx, i.planner.importer, tbl); err != nil {
+		return nil, err
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+const (
+	// provisionalRecordID is the first of the IDs which stand in for the
+	// records of table keys which don't exist yet while a transaction is
+	// staged. It's far beyond any ID which is allocated.
+	provisionalRecordID = 1 << 62
+
+	// provisionalRowID is likewise the first of the row IDs which stand in
+	// for field keys which don't exist yet. It leaves room for a shard's
+	// columns after it without overflowing the position of a bit.
+	provisionalRowID = 1 << 40
+)
+
+// stagingImporter is an Importer which holds on to the shard imports it is
+// given rather than applying them, so that they can be applied later as
+// part of a transaction. Keys are looked up rather than created; those
+// which don't exist yet are given provisional IDs, which are replaced once
+// the keys are created as the transaction commits. Everything else is
+// passed through to the target importer.
+type stagingImporter struct {
+	pilosa.Importer
+	target pilosa.Importer
+
+	mu       sync.Mutex
+	order    []dax.TableID
+	requests map[dax.TableID]map[uint64]*pilosa.ImportRoaringShardRequest
+
+	tableKeys map[dax.TableID]*provisionalKeys
+	fieldKeys map[dax.TableID]map[dax.FieldName]*provisionalKeys
+}
+
+func newStagingImporter(target pilosa.Importer) *stagingImporter {
+	return &stagingImporter{
+		Importer:  target,
+		target:    target,
+		requests:  make(map[dax.TableID]map[uint64]*pilosa.ImportRoaringShardRequest),
+		tableKeys: make(map[dax.TableID]*provisionalKeys),
+		fieldKeys: make(map[dax.TableID]map[dax.FieldName]*provisionalKeys),
+	}
+}
+
+// provisionalKeys are keys which don't exist yet, with the IDs standing in
+// for them until they're created.
+type provisionalKeys struct {
+	base uint64
+	keys []string
+	ids  map[string]uint64
+}
+
+func newProvisionalKeys(base uint64) *provisionalKeys {
+	return &provisionalKeys{
+		base: base,
+		ids:  make(map[string]uint64),
+	}
+}
+
+// id returns the provisional ID of the key, giving it the next one if it
+// doesn't have one yet.
+func (k *provisionalKeys) id(key string) uint64 {
+	id, ok := k.ids[key]
+	if !ok {
+		id = k.base + uint64(len(k.keys))
+		k.ids[key] = id
+		k.keys = append(k.keys, key)
+	}
+	return id
+}
+
+// CreateTableKeys looks up the keys, giving those which don't exist yet
+// provisional IDs.
+func (s *stagingImporter) CreateTableKeys(ctx context.Context, tid dax.TableID, keys ...string) (map[string]uint64, error) {
+	finder, ok := s.target.(pilosa.KeyFinder)
+	if !ok {
+		return nil, sql3.NewErrInternalf("importer cannot look up keys")
+	}
+	found, err := finder.FindTableKeys(ctx, tid, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pk, ok := s.tableKeys[tid]
+	if !ok {
+		pk = newProvisionalKeys(provisionalRecordID)
+		s.tableKeys[tid] = pk
+	}
+	return withProvisionalIDs(found, pk, keys), nil
+}
+
+// CreateFieldKeys looks up the keys, giving those which don't exist yet
+// provisional IDs.
+func (s *stagingImporter) CreateFieldKeys(ctx context.Context, tid dax.TableID, fname dax.FieldName, keys ...string) (map[string]uint64, error) {
+	finder, ok := s.target.(pilosa.KeyFinder)
+	if !ok {
+		return nil, sql3.NewErrInternalf("importer cannot look up keys")
+	}
+	found, err := finder.FindFieldKeys(ctx, tid, fname, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fields, ok := s.fieldKeys[tid]
+	if !ok {
+		fields = make(map[dax.FieldName]*provisionalKeys)
+		s.fieldKeys[tid] = fields
+	}
+	pk, ok := fields[fname]
+	if !ok {
+		pk = newProvisionalKeys(provisionalRowID)
+		fields[fname] = pk
+	}
+	return withProvisionalIDs(found, pk, keys), nil
+}
+
+// withProvisionalIDs adds the provisional IDs of the keys which weren't
+// found.
+func withProvisionalIDs(found map[string]uint64, pk *provisionalKeys, keys []string) map[string]uint64 {
+	ids := make(map[string]uint64, len(keys))
+	for k, id := range found {
+		ids[k] = id
+	}
+	for _, k := range keys {
+		if _, ok := ids[k]; !ok {
+			ids[k] = pk.id(k)
+		}
+	}
+	return ids
+}
+
+// ImportRoaringShard stages the request, appending its updates to any
+// which are already staged for the same shard, so that they are applied
+// in the order they were made.
+func (s *stagingImporter) ImportRoaringShard(ctx context.Context, tid dax.TableID, shard uint64, request *pilosa.ImportRoaringShardRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	shards, ok := s.requests[tid]
+	if !ok {
+		shards = make(map[uint64]*pilosa.ImportRoaringShardRequest)
+		s.requests[tid] = shards
+		s.order = append(s.order, tid)
+	}
+	staged, ok := shards[shard]
+	if !ok {
+		staged = &pilosa.ImportRoaringShardRequest{Remote: request.Remote}
+		shards[shard] = staged
+	}
+	staged.Views = append(staged.Views, request.Views...)
+	return nil
+}
+
+// resolve creates the keys which were given provisional IDs, and returns
+// the staged imports with the provisional IDs replaced, in the order in
+// which their tables were first written, and by shard.
+func (s *stagingImporter) resolve(ctx context.Context) ([]pilosa.ShardImport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := make(map[dax.TableID]map[uint64]uint64)
+	for tid, pk := range s.tableKeys {
+		created, err := s.target.CreateTableKeys(ctx, tid, pk.keys...)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating keys")
+		}
+		records[tid] = createdIDs(pk, created)
+	}
+	rows := make(map[dax.TableID]map[string]map[uint64]uint64)
+	for tid, fields := range s.fieldKeys {
+		rows[tid] = make(map[string]map[uint64]uint64)
+		for fname, pk := range fields {
+			created, err := s.target.CreateFieldKeys(ctx, tid, fname, pk.keys...)
+			if err != nil {
+				return nil, errors.Wrapf(err, "creating keys for field '%s'", fname)
+			}
+			rows[tid][string(fname)] = createdIDs(pk, created)
+		}
+	}
+
+	var imports []pilosa.ShardImport
+	for _, tid := range s.order {
+		staged := s.requests[tid]
+		shards := make([]uint64, 0, len(staged))
+		for shard := range staged {
+			shards = append(shards, shard)
+		}
+		sort.Slice(shards, func(i, j int) bool { return shards[i] < shards[j] })
+
+		// Provisional records are in shards after all of the others, so
+		// their updates are added to the real shards after those which
+		// were staged for them.
+		resolved := make(map[uint64]*pilosa.ImportRoaringShardRequest)
+		var order []uint64
+		add := func(shard uint64, update pilosa.RoaringUpdate) {
+			req, ok := resolved[shard]
+			if !ok {
+				req = &pilosa.ImportRoaringShardRequest{Remote: true}
+				resolved[shard] = req
+				order = append(order, shard)
+			}
+			req.Views = append(req.Views, update)
+		}
+		for _, shard := range shards {
+			for _, update := range staged[shard].Views {
+				fieldRows := rows[tid][update.Field]
+				if shard < provisionalRecordID/pilosa.ShardWidth && len(fieldRows) == 0 {
+					add(shard, update)
+					continue
+				}
+				updates, err := resolveUpdate(update, shard, records[tid], fieldRows)
+				if err != nil {
+					return nil, err
+				}
+				for _, u := range updates {
+					add(u.shard, u.update)
+				}
+			}
+		}
+		sort.Slice(order, func(i, j int) bool { return order[i] < order[j] })
+		for _, shard := range order {
+			imports = append(imports, pilosa.ShardImport{
+				TableID: tid,
+				Shard:   shard,
+				Request: resolved[shard],
+			})
+		}
+	}
+	return imports, nil
+}
+
+// createdIDs maps the provisional IDs of keys to the IDs they were created
+// with.
+func createdIDs(pk *provisionalKeys, created map[string]uint64) map[uint64]uint64 {
+	ids := make(map[uint64]uint64, len(pk.keys))
+	for _, k := range pk.keys {
+		ids[pk.ids[k]] = created[k]
+	}
+	return ids
+}
+
+// shardUpdate is an update of a view in a shard.
+type shardUpdate struct {
+	shard  uint64
+	update pilosa.RoaringUpdate
+}
+
+// resolveUpdate replaces the provisional record and row IDs in an update
+// of the given shard, which may move its bits to other shards.
+func resolveUpdate(update pilosa.RoaringUpdate, shard uint64, records, rows map[uint64]uint64) ([]shardUpdate, error) {
+	clear, err := resolveBits(update.Clear, shard, records, rows)
+	if err != nil {
+		return nil, err
+	}
+	set, err := resolveBits(update.Set, shard, records, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var shards []uint64
+	for s := range clear {
+		shards = append(shards, s)
+	}
+	for s := range set {
+		if _, ok := clear[s]; !ok {
+			shards = append(shards, s)
+		}
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i] < shards[j] })
+
+	updates := make([]shardUpdate, 0, len(shards))
+	for _, s := range shards {
+		u := update
+		if u.Clear, err = bitmapBytes(clear[s]); err != nil {
+			return nil, err
+		}
+		if u.Set, err = bitmapBytes(set[s]); err != nil {
+			return nil, err
+		}
+		updates = append(updates, shardUpdate{shard: s, update: u})
+	}
+	return updates, nil
+}
+
+// resolveBits decodes the bits of an update of the given shard, replaces
+// their provisional record and row IDs, and returns them by shard.
+func resolveBits(data []byte, shard uint64, records, rows map[uint64]uint64) (map[uint64]*roaring.Bitmap, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	bm := roaring.NewBitmap()
+	if err := bm.UnmarshalBinary(data); err != nil {
+		return nil, errors.Wrap(err, "decoding staged bits")
+	}
+	byShard := make(map[uint64]*roaring.Bitmap)
+	for _, pos := range bm.Slice() {
+		row, rec := pos/pilosa.ShardWidth, shard*pilosa.ShardWidth+pos%pilosa.ShardWidth
+		if rec >= provisionalRecordID {
+			id, ok := records[rec]
+			if !ok {
+				return nil, sql3.NewErrInternalf("no key for provisional record %d", rec)
+			}
+			rec = id
+		}
+		if row >= provisionalRowID {
+			id, ok := rows[row]
+			if !ok {
+				return nil, sql3.NewErrInternalf("no key for provisional row %d", row)
+			}
+			row = id
+		}
+		s := rec / pilosa.ShardWidth
+		out, ok := byShard[s]
+		if !ok {
+			out = roaring.NewBitmap()
+			byShard[s] = out
+		}
+		out.DirectAdd(row*pilosa.ShardWidth + rec%pilosa.ShardWidth)
+	}
+	return byShard, nil
+}
+
+// bitmapBytes returns the roaring encoding of bm, or nil if it's nil.
+func bitmapBytes(bm *roaring.Bitmap) ([]byte, error) {
+	if bm == nil {
+		return nil, nil
+	}
+	buf := &bytes.Buffer{}
+	if _, err := bm.WriteTo(buf); err != nil {
+		return nil, errors.Wrap(err, "serializing bitmap")
+	}
+	return buf.Bytes(), nil
+}
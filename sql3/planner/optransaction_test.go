@@ -0,0 +1,115 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/roaring"
+)
+
+// keyImporter is an importer with existing keys, which records the keys it
+// is asked to create.
+type keyImporter struct {
+	pilosa.Importer
+
+	tableKeys map[string]uint64
+	fieldKeys map[string]uint64
+	created   []string
+}
+
+func (k *keyImporter) FindTableKeys(ctx context.Context, tid dax.TableID, keys ...string) (map[string]uint64, error) {
+	return findKeys(k.tableKeys, keys), nil
+}
+
+func (k *keyImporter) FindFieldKeys(ctx context.Context, tid dax.TableID, fname dax.FieldName, keys ...string) (map[string]uint64, error) {
+	return findKeys(k.fieldKeys, keys), nil
+}
+
+func (k *keyImporter) CreateTableKeys(ctx context.Context, tid dax.TableID, keys ...string) (map[string]uint64, error) {
+	k.created = append(k.created, keys...)
+	return findKeys(k.tableKeys, keys), nil
+}
+
+func (k *keyImporter) CreateFieldKeys(ctx context.Context, tid dax.TableID, fname dax.FieldName, keys ...string) (map[string]uint64, error) {
+	k.created = append(k.created, keys...)
+	return findKeys(k.fieldKeys, keys), nil
+}
+
+func findKeys(ids map[string]uint64, keys []string) map[string]uint64 {
+	found := make(map[string]uint64)
+	for _, k := range keys {
+		if id, ok := ids[k]; ok {
+			found[k] = id
+		}
+	}
+	return found
+}
+
+func TestStagingImporter_ProvisionalKeys(t *testing.T) {
+	ctx := context.Background()
+	const sw = pilosa.ShardWidth
+	target := &keyImporter{
+		tableKeys: map[string]uint64{"old": 5},
+		fieldKeys: map[string]uint64{"a": 1},
+	}
+	staging := newStagingImporter(target)
+
+	records, err := staging.CreateTableKeys(ctx, "t", "old", "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := staging.CreateFieldKeys(ctx, "t", "f", "a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records["old"] != 5 || records["new"] != provisionalRecordID || rows["a"] != 1 || rows["b"] != provisionalRowID {
+		t.Fatalf("unexpected IDs: %v %v", records, rows)
+	}
+	if len(target.created) != 0 {
+		t.Fatalf("expected no keys to be created while staging, got %v", target.created)
+	}
+
+	bits := func(positions ...uint64) []byte {
+		b, err := bitmapBytes(roaring.NewBitmap(positions...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	newShard := uint64(provisionalRecordID / sw)
+	for shard, update := range map[uint64]pilosa.RoaringUpdate{
+		0:        {Field: "f", Set: bits(1*sw+5, provisionalRowID*sw+5)},
+		newShard: {Field: "f", Set: bits(provisionalRowID * sw), Clear: bits(0)},
+	} {
+		if err := staging.ImportRoaringShard(ctx, "t", shard, &pilosa.ImportRoaringShardRequest{Remote: true, Views: []pilosa.RoaringUpdate{update}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the keys are created with these IDs
+	target.tableKeys["new"] = 3*sw + 7
+	target.fieldKeys["b"] = 2
+	imports, err := staging.resolve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(target.created, []string{"new", "b"}) {
+		t.Fatalf("expected the new keys to be created, got %v", target.created)
+	}
+	exp := []pilosa.ShardImport{
+		{TableID: "t", Shard: 0, Request: &pilosa.ImportRoaringShardRequest{Remote: true, Views: []pilosa.RoaringUpdate{
+			{Field: "f", Set: bits(1*sw+5, 2*sw+5)},
+		}}},
+		{TableID: "t", Shard: 3, Request: &pilosa.ImportRoaringShardRequest{Remote: true, Views: []pilosa.RoaringUpdate{
+			{Field: "f", Set: bits(2*sw + 7), Clear: bits(7)},
+		}}},
+	}
+	if !reflect.DeepEqual(imports, exp) {
+		t.Fatalf("expected %+v, got %+v", exp, imports)
+	}
+}
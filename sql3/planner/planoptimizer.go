@@ -59,6 +59,14 @@ var optimizerFunctions = []OptimizerFunc{
 	// based on the child operator for a projection
 	fixProjectionReferences,
 
+	// if the query orders a table scan by the distance to a bitvector
+	// and takes the top of it, find the nearest vectors first
+	pushdownPQLNearest,
+
+	// if the query orders a table scan by _id, drop the order by, since
+	// the scan returns records in that order already
+	removeOrderByIDOverTableScan,
+
 	// if the query has one TableScanOperator then push the top
 	// expression down into that operator
 	pushdownPQLTop,
@@ -389,11 +397,17 @@ func pushdownFiltersToFilterableRelations(ctx context.Context, a *ExecutionPlann
 
 	tableFilters := make([]types.PlanExpression, 0)
 	timeQantumFilters := make([]types.PlanExpression, 0)
+	preFilters := make([]types.PlanExpression, 0)
 	// can the filters be pushed down?
 	for _, tf := range availableFilters {
 		// try and generate a pql call graph, if we can't we can't push the filter down
 		_, err := a.generatePQLCallFromExpr(ctx, tf)
 		if err == nil {
+			// does this only narrow down the records?
+			if exprIsPreFilter(tf) {
+				preFilters = append(preFilters, tf)
+				continue
+			}
 			// is this a time quantum call?
 			call, ok := tf.(*callPlanExpression)
 			if ok {
@@ -410,16 +424,25 @@ func pushdownFiltersToFilterableRelations(ctx context.Context, a *ExecutionPlann
 			}
 		}
 	}
+	// pre-filters are pushed down but never marked as handled, so they are
+	// still found when the filters are pushed down again; to avoid replacing
+	// the filters already pushed down to a relation, they only go along with
+	// other filters or to a relation with no filter
+	if len(tableFilters) == 0 && relationHasFilter(tableNode) {
+		preFilters = preFilters[:0]
+	}
+
 	// did we end up with any filters?
-	if len(tableFilters)+len(timeQantumFilters) == 0 {
+	if len(tableFilters)+len(timeQantumFilters)+len(preFilters) == 0 {
 		return tableNode, true, nil
 	}
 
 	var err error
-	var newOp types.PlanOperator
+	var newOp types.PlanOperator = tableNode
 	//deal with the filters
-	if len(tableFilters) > 0 {
+	if len(tableFilters)+len(preFilters) > 0 {
 		filters.markFiltersHandled(tableFilters...)
+		tableFilters = append(tableFilters, preFilters...)
 		// fix the field refs
 		tableFilters, _, err = fixFieldRefIndexesOnExpressions(ctx, scope, a, tableNode.Schema(), tableFilters...)
 		if err != nil {
@@ -448,6 +471,39 @@ func pushdownFiltersToFilterableRelations(ctx context.Context, a *ExecutionPlann
 	return newOp, false, nil
 }
 
+// exprIsPreFilter returns true if the pql for expr may match records which do
+// not satisfy expr. Such a filter can be pushed down to narrow the records
+// read, but must also still be applied to them.
+func exprIsPreFilter(expr types.PlanExpression) bool {
+	result := false
+	InspectExpression(expr, func(e types.PlanExpression) bool {
+		if call, ok := e.(*callPlanExpression); ok {
+			switch strings.ToUpper(call.name) {
+			case "ST_DWITHIN", "ST_WITHIN_BOX":
+				result = true
+				return false
+			}
+		}
+		return true
+	})
+	return result
+}
+
+// relationHasFilter returns true if filters have been pushed down to the
+// relation.
+func relationHasFilter(n types.PlanOperator) bool {
+	switch rel := n.(type) {
+	case *PlanOpRelAlias:
+		return relationHasFilter(rel.ChildOp)
+	case *PlanOpPQLTableScan:
+		return rel.filter != nil
+	case *PlanOpPQLDistinctScan:
+		return rel.filter != nil
+	default:
+		return false
+	}
+}
+
 func pushdownFiltersToAboveRelation(ctx context.Context, a *ExecutionPlanner, tableNode types.PlanOperator, scope *OptimizerScope, filters *filterSet) (types.PlanOperator, bool, error) {
 	var table types.IdentifiableByName
 
@@ -733,7 +789,9 @@ func tryToReplaceGroupByWithPQLAggregate(ctx context.Context, a *ExecutionPlanne
 						return n, false, sql3.NewErrInternalf("unexpected aggregate function arg type '%T'", agg)
 					}
 
-					ops = append(ops, NewPlanOpPQLAggregate(a, table.tableName, aggregable, table.filter))
+					op := NewPlanOpPQLAggregate(a, table.tableName, aggregable, table.filter)
+					op.timeQuantumFilters = table.timeQuantumFilters
+					ops = append(ops, op)
 				}
 				newOp := NewPlanOpPQLMultiAggregate(a, ops)
 				lenOps := len(ops)
@@ -819,6 +877,11 @@ func tryToReplaceDistinctWithPQLDistinct(ctx context.Context, a *ExecutionPlanne
 			// if it is a set type, check to see if we have query hint that tells us to flatten on this column
 			s := thisNode.Schema()
 			switch s[0].Type.(type) {
+			case *parser.DataTypeBitVector, *parser.DataTypeJSON:
+				// the distinct rows of a bitvector or a JSON document are its
+				// bits or bytes, not its values
+				return thisNode, true, nil
+
 			case *parser.DataTypeIDSet, *parser.DataTypeStringSet:
 				found := false
 				for _, h := range thisNode.hints {
@@ -934,6 +997,21 @@ func tryToReplaceGroupByWithPQLGroupBy(ctx context.Context, a *ExecutionPlanner,
 				}
 			}
 
+			// PQL GroupBy can't group on or aggregate double columns, and
+			// would group geopoints by their packed value, bitvectors by
+			// their bits and JSON documents by their bytes
+			for _, gbc := range thisNode.GroupByExprs {
+				if typeIsDouble(gbc.Type()) || typeIsGeoPoint(gbc.Type()) || typeIsBitVector(gbc.Type()) || typeIsJSON(gbc.Type()) {
+					return thisNode, true, nil
+				}
+			}
+			for _, agg := range thisNode.Aggregates {
+				aggregable, ok := agg.(types.Aggregable)
+				if ok && aggregable.FirstChildExpr() != nil && typeIsDouble(aggregable.FirstChildExpr().Type()) {
+					return thisNode, true, nil
+				}
+			}
+
 			// get the type of the _id column for this table
 			pkType, err := table.PrimaryKeyType()
 			if err != nil {
@@ -959,7 +1037,9 @@ func tryToReplaceGroupByWithPQLGroupBy(ctx context.Context, a *ExecutionPlanner,
 					aggregable = newAgg.(types.Aggregable)
 				}
 
-				ops = append(ops, NewPlanOpPQLGroupBy(a, table.tableName, thisNode.GroupByExprs, table.filter, aggregable))
+				op := NewPlanOpPQLGroupBy(a, table.tableName, thisNode.GroupByExprs, table.filter, aggregable)
+				op.timeQuantumFilters = table.timeQuantumFilters
+				ops = append(ops, op)
 			}
 
 			// use a multi group by if more than 1 aggregate
@@ -1012,7 +1092,25 @@ func pushdownPQLTop(ctx context.Context, a *ExecutionPlanner, n types.PlanOperat
 	if len(tables) == 1 {
 		return TransformPlanOp(n, func(node types.PlanOperator) (types.PlanOperator, bool, error) {
 			switch n := node.(type) {
+			case *PlanOpOffset:
+				// only push down the offset if it sits directly over the projection on the table
+				projection, ok := n.ChildOp.(*PlanOpProjection)
+				if !ok || projection.ChildOp != tables[0] {
+					return n, true, nil
+				}
+				table := tables[0]
+				//set the offsetExpr for the PlanOpTableScan
+				table.offsetExpr = n.expr
+				//return the child of the offset node to eliminate it
+				return n.ChildOp, false, nil
 			case *PlanOpTop:
+				// if an offset could not be pushed down, the top can't be
+				// either, and nor can it be past anything but the projection
+				// on the table, such as an order by
+				projection, ok := n.ChildOp.(*PlanOpProjection)
+				if !ok || projection.ChildOp != tables[0] {
+					return n, true, nil
+				}
 				table := tables[0]
 				//set the topExpr for the PlanOpTableScan
 				table.topExpr = n.expr
@@ -1026,6 +1124,102 @@ func pushdownPQLTop(ctx context.Context, a *ExecutionPlanner, n types.PlanOperat
 	return n, true, nil
 }
 
+// pushdownPQLNearest looks for a top over an order by on the HAMMING() distance
+// between a bitvector column and a constant, over a projection on a table
+// scan. The scan then only reads the nearest records, which it finds from the
+// bit-planes with PQL Nearest(). The top and order by stay where they are.
+func pushdownPQLNearest(ctx context.Context, a *ExecutionPlanner, n types.PlanOperator, scope *OptimizerScope) (types.PlanOperator, bool, error) {
+	return TransformPlanOp(n, func(node types.PlanOperator) (types.PlanOperator, bool, error) {
+		top, ok := node.(*PlanOpTop)
+		if !ok {
+			return node, true, nil
+		}
+		k, ok := top.expr.(*intLiteralPlanExpression)
+		if !ok || k.value < 0 {
+			return node, true, nil
+		}
+		orderBy, ok := top.ChildOp.(*PlanOpOrderBy)
+		if !ok || len(orderBy.orderByFields) != 1 || orderBy.orderByFields[0].Order != orderByAsc {
+			return node, true, nil
+		}
+		ref, ok := orderBy.orderByFields[0].Expr.(*qualifiedRefPlanExpression)
+		if !ok {
+			return node, true, nil
+		}
+		projection, ok := orderBy.ChildOp.(*PlanOpProjection)
+		if !ok {
+			return node, true, nil
+		}
+		table, ok := projection.ChildOp.(*PlanOpPQLTableScan)
+		if !ok || table.nearest != nil || len(table.timeQuantumFilters) > 0 {
+			return node, true, nil
+		}
+
+		// find the projected distance being ordered on
+		var call *callPlanExpression
+		for _, pj := range projection.Projections {
+			if !strings.EqualFold(pj.String(), ref.columnName) {
+				continue
+			}
+			if alias, ok := pj.(*aliasPlanExpression); ok {
+				pj = alias.expr
+			}
+			call, _ = pj.(*callPlanExpression)
+			break
+		}
+		if call == nil || !strings.EqualFold(call.name, "HAMMING") {
+			return node, true, nil
+		}
+		var column *qualifiedRefPlanExpression
+		var vector *stringLiteralPlanExpression
+		for _, arg := range call.args {
+			switch arg := arg.(type) {
+			case *qualifiedRefPlanExpression:
+				column = arg
+			case *stringLiteralPlanExpression:
+				vector = arg
+			}
+		}
+		if column == nil || vector == nil || !typeIsBitVector(column.Type()) {
+			return node, true, nil
+		}
+
+		table.nearest = &nearestScan{
+			column: column.columnName,
+			width:  column.Type().(*parser.DataTypeBitVector).Width,
+			vector: vector.value,
+			k:      k.value,
+		}
+		return node, false, nil
+	})
+}
+
+// removeOrderByIDOverTableScan removes an ascending order by on the _id of a
+// table with id keys, where it is directly over the table scan. A table scan
+// returns records in _id order anyway, and without the order by a top can be
+// pushed down into the scan, which makes keyset pagination (WHERE _id > last
+// ORDER BY _id LIMIT n) read only the page of records it returns.
+func removeOrderByIDOverTableScan(ctx context.Context, a *ExecutionPlanner, n types.PlanOperator, scope *OptimizerScope) (types.PlanOperator, bool, error) {
+	return TransformPlanOp(n, func(node types.PlanOperator) (types.PlanOperator, bool, error) {
+		orderBy, ok := node.(*PlanOpOrderBy)
+		if !ok || len(orderBy.orderByFields) != 1 || orderBy.orderByFields[0].Order != orderByAsc {
+			return node, true, nil
+		}
+		ref, ok := orderBy.orderByFields[0].Expr.(*qualifiedRefPlanExpression)
+		if !ok || !strings.EqualFold(ref.columnName, string(dax.PrimaryKeyFieldName)) {
+			return node, true, nil
+		}
+		if _, ok := ref.Type().(*parser.DataTypeID); !ok {
+			return node, true, nil
+		}
+		table, ok := orderBy.ChildOp.(*PlanOpPQLTableScan)
+		if !ok || table.nearest != nil {
+			return node, true, nil
+		}
+		return table, false, nil
+	})
+}
+
 // fixes references for a projection op depending on child
 func fixProjectionReferences(ctx context.Context, a *ExecutionPlanner, n types.PlanOperator, scope *OptimizerScope) (types.PlanOperator, bool, error) {
 	return TransformPlanOp(n, func(node types.PlanOperator) (types.PlanOperator, bool, error) {
@@ -1189,6 +1383,20 @@ func fixFieldRefs(ctx context.Context, a *ExecutionPlanner, n types.PlanOperator
 			newNode.warnings = append(newNode.warnings, thisNode.warnings...)
 			return newNode, aggregateSame && groupBySame, nil
 
+		case *PlanOpTransactionUpdate:
+			// fix references for the _id and the expressions assigned to the records
+			schema := thisNode.ChildOp.Schema()
+			expressions := thisNode.Expressions()
+			fixed, same, err := fixFieldRefIndexesOnExpressions(ctx, scope, a, schema, expressions...)
+			if err != nil {
+				return nil, true, err
+			}
+			newNode, err := thisNode.WithUpdatedExpressions(fixed...)
+			if err != nil {
+				return nil, true, err
+			}
+			return newNode, same, nil
+
 		case *PlanOpPQLMultiGroupBy:
 
 			schema := thisNode.operators[0].Schema()
@@ -0,0 +1,156 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"strconv"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+func init() {
+	// The core package enforces DEFAULT and CHECK constraints, but can't
+	// import the planner to compile them.
+	pilosa.CompileConstraintExpr = compileConstraintExpr
+}
+
+// RowColumn is a named, typed value in the rows a row expression is
+// evaluated over.
+type RowColumn struct {
+	Name string
+	Type parser.ExprDataType
+}
+
+// CompileRowExpression compiles an expression to be evaluated over rows of
+// the given columns, outside of any query. As in the TRANSFORM clause of
+// BULK INSERT, the columns are referred to as variables, so @name is the
+// value of the column with that name. The values in the rows must be of
+// the types they're given in a BULK INSERT map: int64 for ints and IDs,
+// pql.Decimal, float64, string, bool, time.Time, []string and []int64.
+//
+// It returns the expression, and the indexes of the columns it refers to.
+func CompileRowExpression(ctx context.Context, sql string, columns []RowColumn) (types.PlanExpression, []int, error) {
+	expr, err := parser.ParseExprString(sql)
+	if err != nil {
+		return nil, nil, err
+	}
+	if expr == nil {
+		return nil, nil, sql3.NewErrInternalf("empty expression")
+	}
+
+	compiled, err := compileRowExpression(ctx, sql, expr, rowExpressionScope(columns))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var refs []int
+	seen := make(map[int]bool)
+	InspectExpression(compiled, func(e types.PlanExpression) bool {
+		if v, ok := e.(*variableRefPlanExpression); ok && !seen[v.variableIndex] {
+			seen[v.variableIndex] = true
+			refs = append(refs, v.variableIndex)
+		}
+		return true
+	})
+	return compiled, refs, nil
+}
+
+// rowExpressionScope declares columns the way the map of a BULK INSERT would
+// declare them, so that variables are resolved as they are there.
+func rowExpressionScope(columns []RowColumn) *parser.BulkInsertStatement {
+	scope := &parser.BulkInsertStatement{}
+	for _, c := range columns {
+		typ := &parser.Type{Name: &parser.Ident{Name: c.Type.BaseTypeName()}}
+		if d, ok := c.Type.(*parser.DataTypeDecimal); ok {
+			typ.Scale = &parser.IntegerLit{Value: strconv.FormatInt(d.Scale, 10)}
+		}
+		scope.MapList = append(scope.MapList, &parser.BulkInsertMapDefinition{
+			Name: &parser.Ident{Name: c.Name},
+			Type: typ,
+		})
+	}
+	return scope
+}
+
+// compileRowExpression analyzes and compiles expr, parsed from sql, in scope.
+func compileRowExpression(ctx context.Context, sql string, expr parser.Expr, scope parser.Statement) (types.PlanExpression, error) {
+	// The planner has no schema, since nothing the expression can refer to
+	// is in one.
+	p := &ExecutionPlanner{
+		logger: logger.NopLogger,
+		sql:    sql,
+	}
+	expr, err := p.analyzeExpression(ctx, expr, scope)
+	if err != nil {
+		return nil, err
+	}
+	return p.compileExpr(expr)
+}
+
+// constraintScope is the scope of the DEFAULT or CHECK constraint of a
+// column. A CHECK can refer to the column, which it's evaluated against as
+// the only variable in the row; a DEFAULT can't refer to any column.
+type constraintScope struct {
+	*parser.BulkInsertStatement
+
+	column string
+	check  bool
+}
+
+// constraintExpr is a DEFAULT or CHECK expression compiled by
+// compileConstraintExpr.
+type constraintExpr struct {
+	expr types.PlanExpression
+
+	// columnType is the type of the column, which the value of a DEFAULT
+	// is coerced to. It's nil for a CHECK.
+	columnType parser.ExprDataType
+}
+
+// Evaluate implements pilosa.ConstraintExpr.
+func (c *constraintExpr) Evaluate(val interface{}) (interface{}, error) {
+	v, err := c.expr.Evaluate([]interface{}{val})
+	if err != nil || v == nil || c.columnType == nil {
+		return v, err
+	}
+	return coerceValue(c.expr.Type(), c.columnType, v, parser.Pos{})
+}
+
+// compileConstraintExpr compiles the DEFAULT or CHECK expression sql of the
+// column named field. It implements pilosa.CompileConstraintExpr.
+func compileConstraintExpr(sql string, field string, opts pilosa.FieldOptions, check bool) (pilosa.ConstraintExpr, error) {
+	expr, err := parser.ParseExprString(sql)
+	if err != nil {
+		return nil, err
+	}
+	if expr == nil {
+		return nil, sql3.NewErrInternalf("empty expression")
+	}
+
+	columnType := fieldSQLDataType(&pilosa.FieldInfo{Name: field, Options: opts})
+	scope := &constraintScope{
+		BulkInsertStatement: rowExpressionScope([]RowColumn{{Name: field, Type: columnType}}),
+		column:              field,
+		check:               check,
+	}
+	compiled, err := compileRowExpression(context.Background(), sql, expr, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if check {
+		if typ := compiled.Type(); !typeIsBool(typ) && !typeIsVoid(typ) {
+			return nil, sql3.NewErrCheckNotBoolean(expr.Pos().Line, expr.Pos().Column, typ.TypeDescription())
+		}
+		return &constraintExpr{expr: compiled}, nil
+	}
+	if !typesAreAssignmentCompatible(columnType, compiled.Type()) {
+		return nil, sql3.NewErrTypeAssignmentIncompatible(expr.Pos().Line, expr.Pos().Column, compiled.Type().TypeDescription(), columnType.TypeDescription())
+	}
+	return &constraintExpr{expr: compiled, columnType: columnType}, nil
+}
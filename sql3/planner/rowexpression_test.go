@@ -0,0 +1,86 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+)
+
+func TestCompileRowExpression(t *testing.T) {
+	columns := []RowColumn{
+		{Name: "first", Type: parser.NewDataTypeString()},
+		{Name: "last", Type: parser.NewDataTypeString()},
+		{Name: "amount", Type: parser.NewDataTypeDecimal(2)},
+		{Name: "ts", Type: parser.NewDataTypeTimestamp()},
+		{Name: "tags", Type: parser.NewDataTypeStringSet()},
+	}
+	row := []interface{}{
+		"ada",
+		"lovelace",
+		pql.NewDecimal(12345, 2),
+		time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC),
+		[]string{"x", "y"},
+	}
+
+	tests := []struct {
+		sql  string
+		want interface{}
+		typ  string
+		refs []int
+	}{
+		{sql: "@first || ' ' || @last", want: "ada lovelace", typ: "string", refs: []int{0, 1}},
+		{sql: "SUBSTRING(@last, 0, 4)", want: "love", typ: "string", refs: []int{1}},
+		{sql: "DATE_TRUNC('yy', @ts)", want: "2022", typ: "string", refs: []int{3}},
+		{sql: "CASE WHEN @amount > 100.00 THEN 'gold' ELSE 'basic' END", want: "gold", typ: "string", refs: []int{2}},
+		{sql: "@amount > 200.00 OR @first = 'ada'", want: true, typ: "bool", refs: []int{2, 0}},
+		{sql: "SETCONTAINS(@tags, 'z')", want: false, typ: "bool", refs: []int{4}},
+		{sql: "DATETIMEPART('yy', @ts)", want: int64(2022), typ: "int", refs: []int{3}},
+		{sql: "'constant'", want: "constant", typ: "string", refs: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.sql, func(t *testing.T) {
+			expr, refs, err := CompileRowExpression(context.Background(), test.sql, columns)
+			if err != nil {
+				t.Fatalf("compiling: %v", err)
+			}
+			if !reflect.DeepEqual(refs, test.refs) {
+				t.Errorf("expected refs %v, got %v", test.refs, refs)
+			}
+			if typ := expr.Type().TypeDescription(); typ != test.typ {
+				t.Errorf("expected type %s, got %s", test.typ, typ)
+			}
+			got, err := expr.Evaluate(row)
+			if err != nil {
+				t.Fatalf("evaluating: %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v (%[1]T), got %v (%[2]T)", test.want, got)
+			}
+		})
+	}
+
+	// a null condition in a CASE isn't true
+	expr, _, err := CompileRowExpression(context.Background(), "CASE WHEN @amount > 100.00 THEN 'gold' ELSE 'basic' END", columns)
+	if err != nil {
+		t.Fatalf("compiling: %v", err)
+	}
+	if got, err := expr.Evaluate(make([]interface{}, len(columns))); err != nil || got != "basic" {
+		t.Errorf("expected basic for null amount, got %v, %v", got, err)
+	}
+
+	for _, sql := range []string{
+		"@missing = 1",
+		"@first +",
+		"@first + 1",
+	} {
+		if _, _, err := CompileRowExpression(context.Background(), sql, columns); err == nil {
+			t.Errorf("expected error compiling %q", sql)
+		}
+	}
+}
@@ -5,6 +5,7 @@ package planner
 import (
 	"context"
 	"encoding/json"
+	"math"
 	"time"
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
@@ -19,6 +20,13 @@ type viewSystemObject struct {
 	statement string
 }
 
+type materializedViewSystemObject struct {
+	name            string
+	statement       string
+	refreshInterval time.Duration
+	lastRefresh     *time.Time
+}
+
 type functionSystemObject struct {
 	name     string
 	language string
@@ -261,6 +269,247 @@ func (p *ExecutionPlanner) deleteView(ctx context.Context, viewName string) erro
 	return nil
 }
 
+func (p *ExecutionPlanner) ensureMaterializedViewsSystemTableExists(ctx context.Context) error {
+	_, err := p.schemaAPI.TableByName(ctx, "fb_materialized_views")
+	if err != nil {
+		if !isTableNotFoundError(err) {
+			return err
+		}
+
+		//  create table fb_materialized_views (
+		// 		_id string
+		//		name string
+		//		statement string
+		//		refresh_interval int -- seconds, 0 if only refreshed on demand
+		//		last_refresh timestamp
+		//		owner string
+		//		updated_by string
+		//		created_at timestamp
+		//		updated_at timestamp
+		//  );
+
+		// if it doesn't, create it by making the appropriate iterator
+		iter := &createTableRowIter{
+			planner:       p,
+			tableName:     "fb_materialized_views",
+			failIfExists:  false,
+			isKeyed:       true,
+			keyPartitions: 0,
+			columns: []*createTableField{
+				{
+					planner:  p,
+					name:     "name",
+					typeName: dax.BaseTypeString,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize),
+						pilosa.OptFieldKeys(),
+					},
+				},
+				{
+					planner:  p,
+					name:     "statement",
+					typeName: dax.BaseTypeString,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize),
+						pilosa.OptFieldKeys(),
+					},
+				},
+				{
+					planner:  p,
+					name:     "refresh_interval",
+					typeName: dax.BaseTypeInt,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeInt(0, math.MaxInt64),
+					},
+				},
+				{
+					planner:  p,
+					name:     "last_refresh",
+					typeName: dax.BaseTypeTimestamp,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitSeconds),
+					},
+				},
+				{
+					planner:  p,
+					name:     "owner",
+					typeName: dax.BaseTypeString,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize),
+						pilosa.OptFieldKeys(),
+					},
+				},
+				{
+					planner:  p,
+					name:     "updated_by",
+					typeName: dax.BaseTypeString,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize),
+						pilosa.OptFieldKeys(),
+					},
+				},
+				{
+					planner:  p,
+					name:     "created_at",
+					typeName: dax.BaseTypeTimestamp,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitSeconds),
+					},
+				},
+				{
+					planner:  p,
+					name:     "updated_at",
+					typeName: dax.BaseTypeTimestamp,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitSeconds),
+					},
+				},
+			},
+			description: "system table for materialized views",
+		}
+		// call next on our iterator to create the table
+		_, err := iter.Next(ctx)
+		if err != nil && err != types.ErrNoMoreRows {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *ExecutionPlanner) getMaterializedViewByName(ctx context.Context, name string) (*materializedViewSystemObject, error) {
+	err := p.ensureMaterializedViewsSystemTableExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := &tableScanRowIter{
+		planner:   p,
+		tableName: "fb_materialized_views",
+		columns:   []string{"name", "statement", "refresh_interval", "last_refresh"},
+		predicate: newBinOpPlanExpression(
+			newQualifiedRefPlanExpression("fb_materialized_views", string(dax.PrimaryKeyFieldName), 0, parser.NewDataTypeString()),
+			parser.EQ,
+			newStringLiteralPlanExpression(name),
+			parser.NewDataTypeBool(),
+		),
+		topExpr: nil,
+	}
+
+	row, err := iter.Next(ctx)
+	if err != nil {
+		if err == types.ErrNoMoreRows {
+			// materialized view does not exist
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	mv := &materializedViewSystemObject{
+		name:      row[0].(string),
+		statement: row[1].(string),
+	}
+	if interval, ok := row[2].(int64); ok {
+		mv.refreshInterval = time.Duration(interval) * time.Second
+	}
+	if lastRefresh, ok := row[3].(time.Time); ok {
+		mv.lastRefresh = &lastRefresh
+	}
+	return mv, nil
+}
+
+func (p *ExecutionPlanner) insertMaterializedView(ctx context.Context, mv *materializedViewSystemObject) error {
+	err := p.ensureMaterializedViewsSystemTableExists(ctx)
+	if err != nil {
+		return err
+	}
+
+	createTime := time.Now().UTC()
+
+	iter := &insertRowIter{
+		planner:   p,
+		tableName: "fb_materialized_views",
+		targetColumns: []*qualifiedRefPlanExpression{
+			newQualifiedRefPlanExpression("fb_materialized_views", string(dax.PrimaryKeyFieldName), 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_materialized_views", "name", 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_materialized_views", "statement", 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_materialized_views", "refresh_interval", 0, parser.NewDataTypeInt()),
+			newQualifiedRefPlanExpression("fb_materialized_views", "owner", 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_materialized_views", "updated_by", 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_materialized_views", "created_at", 0, parser.NewDataTypeTimestamp()),
+			newQualifiedRefPlanExpression("fb_materialized_views", "updated_at", 0, parser.NewDataTypeTimestamp()),
+		},
+		insertValues: [][]types.PlanExpression{
+			{
+				newStringLiteralPlanExpression(mv.name),
+				newStringLiteralPlanExpression(mv.name),
+				newStringLiteralPlanExpression(mv.statement),
+				newIntLiteralPlanExpression(int64(mv.refreshInterval / time.Second)),
+				newStringLiteralPlanExpression(""),
+				newStringLiteralPlanExpression(""),
+				newTimestampLiteralPlanExpression(createTime),
+				newTimestampLiteralPlanExpression(createTime),
+			},
+		},
+	}
+	_, err = iter.Next(ctx)
+	if err != nil && err != types.ErrNoMoreRows {
+		return err
+	}
+	return nil
+}
+
+// updateMaterializedViewLastRefresh records the time at which the
+// materialized view was last refreshed.
+func (p *ExecutionPlanner) updateMaterializedViewLastRefresh(ctx context.Context, name string, lastRefresh time.Time) error {
+	err := p.ensureMaterializedViewsSystemTableExists(ctx)
+	if err != nil {
+		return err
+	}
+
+	iter := &insertRowIter{
+		planner:   p,
+		tableName: "fb_materialized_views",
+		targetColumns: []*qualifiedRefPlanExpression{
+			newQualifiedRefPlanExpression("fb_materialized_views", string(dax.PrimaryKeyFieldName), 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_materialized_views", "last_refresh", 0, parser.NewDataTypeTimestamp()),
+		},
+		insertValues: [][]types.PlanExpression{
+			{
+				newStringLiteralPlanExpression(name),
+				newTimestampLiteralPlanExpression(lastRefresh),
+			},
+		},
+	}
+	_, err = iter.Next(ctx)
+	if err != nil && err != types.ErrNoMoreRows {
+		return err
+	}
+	return nil
+}
+
+func (p *ExecutionPlanner) deleteMaterializedView(ctx context.Context, name string) error {
+	err := p.ensureMaterializedViewsSystemTableExists(ctx)
+	if err != nil {
+		return err
+	}
+
+	iter := &filteredDeleteRowIter{
+		planner:   p,
+		tableName: "fb_materialized_views",
+		filter: newBinOpPlanExpression(
+			newQualifiedRefPlanExpression("fb_materialized_views", string(dax.PrimaryKeyFieldName), 0, parser.NewDataTypeString()),
+			parser.EQ,
+			newStringLiteralPlanExpression(name),
+			parser.NewDataTypeBool(),
+		),
+	}
+	_, err = iter.Next(ctx)
+	if err != nil && err != types.ErrNoMoreRows {
+		return err
+	}
+	return nil
+}
+
 func (p *ExecutionPlanner) ensureFunctionsSystemTableExists() error {
 	_, err := p.schemaAPI.TableByName(context.Background(), "fb_functions")
 	if err != nil {
@@ -361,6 +610,12 @@ func (p *ExecutionPlanner) ensureFunctionsSystemTableExists() error {
 }
 
 func (p *ExecutionPlanner) getFunctionByName(name string) (*functionSystemObject, error) {
+	// a planner compiling row expressions has no schema, and so no
+	// user-defined functions
+	if p.schemaAPI == nil {
+		return nil, nil
+	}
+
 	err := p.ensureFunctionsSystemTableExists()
 	if err != nil {
 		return nil, err
@@ -4,6 +4,7 @@ package sql3_test
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -19,6 +20,7 @@ import (
 	"github.com/apache/arrow/go/v10/parquet"
 	"github.com/apache/arrow/go/v10/parquet/pqarrow"
 	pilosa "github.com/featurebasedb/featurebase/v3"
+	fbcontext "github.com/featurebasedb/featurebase/v3/context"
 	"github.com/featurebasedb/featurebase/v3/dax"
 	"github.com/featurebasedb/featurebase/v3/pql"
 	sql_test "github.com/featurebasedb/featurebase/v3/sql3/test"
@@ -146,6 +148,245 @@ func TestPlanner_SystemTableFanout(t *testing.T) {
 	})
 }
 
+func TestPlanner_QueryHistory(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	server := c.GetNode(0).Server
+
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, server, `create table histtbl (_id id, n int)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, server, `insert into histtbl (_id, n) values (1, 10), (2, 20), (3, 30)`); err != nil {
+		t.Fatal(err)
+	}
+	const query = `select _id, n from histtbl where n > 10`
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, server, query); err != nil {
+		t.Fatal(err)
+	}
+
+	// queries are added to the history asynchronously, so wait for it
+	var results [][]interface{}
+	for deadline := time.Now().Add(5 * time.Second); len(results) == 0; {
+		var err error
+		results, _, _, err = sql_test.MustQueryRows(t, nil, server, `select row_count, shards_scanned, error from fb_query_history where sql = '`+strings.ReplaceAll(query, "'", "''")+`'`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("query not found in history")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if diff := cmp.Diff([][]interface{}{
+		{int64(2), int64(1), nil},
+	}, results); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// a query closed before its rows are read to the end is added too
+	const partial = `select _id, n from histtbl where n < 30`
+	ctx := fbcontext.WithRequestID(context.Background(), "partial-request")
+	op, err := server.CompileExecutionPlan(ctx, partial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iter, err := op.Iterator(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := iter.Next(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := iter.(io.Closer).Close(); err != nil {
+		t.Fatal(err)
+	}
+	results = nil
+	for deadline := time.Now().Add(5 * time.Second); len(results) == 0; {
+		results, _, _, err = sql_test.MustQueryRows(t, nil, server, `select request_id, row_count from fb_query_history where sql = '`+strings.ReplaceAll(partial, "'", "''")+`'`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("closed query not found in history")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if diff := cmp.Diff([][]interface{}{
+		{"partial-request", int64(1)},
+	}, results); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+// Ensure cursors belong to the user who declared them.
+func TestPlanner_CursorsPerUser(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	server := c.GetNode(0).Server
+
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, server, `create table cursortbl (_id id, n int)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, server, `insert into cursortbl (_id, n) values (1, 10), (2, 20)`); err != nil {
+		t.Fatal(err)
+	}
+
+	alice := fbcontext.WithUserID(context.Background(), "alice")
+	bob := fbcontext.WithUserID(context.Background(), "bob")
+
+	// both users can declare a cursor with the same name
+	if _, _, _, err := sql_test.MustQueryRows(t, alice, server, `declare c cursor for select n from cursortbl where _id = 1`); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := sql_test.MustQueryRows(t, bob, server, `declare c cursor for select n from cursortbl where _id = 2`); err != nil {
+		t.Fatal(err)
+	}
+
+	// and each fetches from their own
+	for ctx, exp := range map[context.Context]int64{alice: 10, bob: 20} {
+		results, _, _, err := sql_test.MustQueryRows(t, ctx, server, `fetch next from c`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([][]interface{}{{exp}}, results); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+
+	// a user without a cursor of that name can't fetch from or close it
+	carol := fbcontext.WithUserID(context.Background(), "carol")
+	if _, _, _, err := sql_test.MustQueryRows(t, carol, server, `close c`); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected cursor not found, got %v", err)
+	}
+}
+
+func TestPlanner_CursorsAcrossNodes(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, c.GetNode(0).Server, `create table cursornodes (_id id, n int)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, c.GetNode(0).Server, `insert into cursornodes (_id, n) values (1, 10), (2, 20), (3, 30)`); err != nil {
+		t.Fatal(err)
+	}
+
+	// a cursor declared on one node can be fetched from through the others
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, c.GetNode(0).Server, `declare c cursor for select n from cursornodes order by n`); err != nil {
+		t.Fatal(err)
+	}
+	for i, exp := range [][][]interface{}{{{int64(10)}, {int64(20)}}, {{int64(30)}}} {
+		results, _, _, err := sql_test.MustQueryRows(t, nil, c.GetNode(i+1).Server, `fetch next 2 from c`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(exp, results); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+
+	// it is closed once it's been read to the end
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, c.GetNode(0).Server, `fetch next from c`); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected cursor not found, got %v", err)
+	}
+
+	// and can be closed through another node too
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, c.GetNode(1).Server, `declare d cursor for select n from cursornodes`); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, c.GetNode(2).Server, `close d`); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, c.GetNode(1).Server, `fetch next from d`); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected cursor not found, got %v", err)
+	}
+}
+
+func TestPlanner_StorageSystemTables(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	server := c.GetNode(0).Server
+
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, server, `create table storagetbl (_id id, s string, n int)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := sql_test.MustQueryRows(t, nil, server, `insert into storagetbl (_id, s, n) values (1, 'a', 10), (2, 'b', 20), (3, 'a', 30)`); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("FragmentStorage", func(t *testing.T) {
+		results, columns, _, err := sql_test.MustQueryRows(t, nil, server, `select field_name, view_name, shard, row_count, bit_count, container_count, array_containers, run_containers, bitmap_containers from fb_fragment_storage where table_name = 'storagetbl' and field_name = 's' order by view_name`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff([]*pilosa.WireQueryField{
+			wireQueryFieldString("field_name"),
+			wireQueryFieldString("view_name"),
+			wireQueryFieldInt("shard"),
+			wireQueryFieldInt("row_count"),
+			wireQueryFieldInt("bit_count"),
+			wireQueryFieldInt("container_count"),
+			wireQueryFieldInt("array_containers"),
+			wireQueryFieldInt("run_containers"),
+			wireQueryFieldInt("bitmap_containers"),
+		}, columns); diff != "" {
+			t.Fatal(diff)
+		}
+
+		// string fields track non-null values in an existence view, which is
+		// a single run of the three records
+		if diff := cmp.Diff([][]interface{}{
+			{"s", "existence", int64(0), int64(1), int64(3), int64(1), int64(0), int64(1), int64(0)},
+			{"s", "standard", int64(0), int64(2), int64(3), int64(2), int64(2), int64(0), int64(0)},
+		}, results); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("RBFFiles", func(t *testing.T) {
+		results, _, _, err := sql_test.MustQueryRows(t, nil, server, `select shard, file_bytes, wal_bytes from fb_rbf_files where table_name = 'storagetbl'`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("unexpected result set length: %d", len(results))
+		}
+		if results[0][0] != int64(0) || results[0][1].(int64) <= 0 || results[0][2].(int64) < 0 {
+			t.Fatalf("unexpected rbf file row: %v", results[0])
+		}
+	})
+
+	t.Run("TranslateStores", func(t *testing.T) {
+		results, _, _, err := sql_test.MustQueryRows(t, nil, server, `select field_name, partition_id, max_id from fb_translate_stores where table_name = 'storagetbl'`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([][]interface{}{
+			{"s", nil, int64(2)},
+		}, results); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("TableStorage", func(t *testing.T) {
+		results, _, _, err := sql_test.MustQueryRows(t, nil, server, `select disk_usage, shard_count from fb_table_storage where table_name = 'storagetbl'`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("unexpected result set length: %d", len(results))
+		}
+		if results[0][0].(int64) <= 0 || results[0][1] != int64(1) {
+			t.Fatalf("unexpected table storage row: %v", results[0])
+		}
+	})
+}
+
 func TestPlanner_Show(t *testing.T) {
 	c := test.MustRunCluster(t, 1)
 	defer c.Close()
@@ -2774,6 +3015,30 @@ func TestPlanner_SelectTop(t *testing.T) {
 			t.Fatal(diff)
 		}
 	})
+
+	t.Run("SelectKeysetPage", func(t *testing.T) {
+		sql := fmt.Sprintf(`select _id, a from %j where _id > 1 order by _id limit 1`, c)
+		results, _, _, err := sql_test.MustQueryRows(t, nil, c.GetNode(0).Server, sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([][]interface{}{
+			{int64(2), int64(20)},
+		}, results); diff != "" {
+			t.Fatal(diff)
+		}
+
+		// the page is read by the table scan, rather than by ordering
+		// every record after the last one
+		op, err := c.GetNode(0).API.CompilePlan(context.Background(), sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plan := fmt.Sprintf("%v", op.Plan())
+		if strings.Contains(plan, "PlanOpOrderBy") || !strings.Contains(plan, "topExpr") {
+			t.Fatalf("expected the top to be pushed down into the table scan: %s", plan)
+		}
+	})
 }
 
 // helpers
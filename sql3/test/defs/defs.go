@@ -38,10 +38,18 @@ var TableTests []TableTest = []TableTest{
 
 	subqueryTests,
 	viewTests,
+	materializedViewTests,
 
 	topLimitTests,
+	cursorTests,
 
 	deleteTests,
+	transactionTests,
+	doubleTests,
+	jsonTests,
+	geoPointTests,
+	bitVectorTests,
+	bitVectorKeyedTests,
 
 	setLiteralTests,
 	setFunctionTests,
@@ -189,6 +197,10 @@ var TableTests []TableTest = []TableTest{
 	// create table tests
 	createTable,
 	alterTable,
+	alterTableColumns,
+	columnConstraints,
+	insertConflicts,
+	compositeKeys,
 
 	// joins
 	joinTestsUsers,
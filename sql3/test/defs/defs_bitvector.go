@@ -0,0 +1,263 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+package defs
+
+// bitvector column tests
+var bitVectorTests = TableTest{
+	name: "bitvector_tests",
+	Table: tbl(
+		"bitvecs",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("v", fldTypeBitVector16),
+			srcHdr("g", fldTypeInt),
+		),
+		srcRows(
+			srcRow(int64(1), "ff00", int64(1)),
+			srcRow(int64(2), "FF01", int64(1)),
+			srcRow(int64(3), "0f00", int64(2)),
+			srcRow(int64(4), "00ff", int64(2)),
+			srcRow(int64(5), nil, int64(1)),
+			srcRow(int64(6), "fe00", int64(2)),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			SQLs: sqls(
+				"select _id, v from bitvecs where _id in (1, 2, 5)",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("v", fldTypeBitVector16),
+			),
+			ExpRows: rows(
+				row(int64(1), "ff00"),
+				row(int64(2), "ff01"),
+				row(int64(5), nil),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id, hamming(v, 'ff00') as d from bitvecs",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("d", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(1), int64(0)),
+				row(int64(2), int64(1)),
+				row(int64(3), int64(4)),
+				row(int64(4), int64(16)),
+				row(int64(5), nil),
+				row(int64(6), int64(1)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from bitvecs where hamming(v, 'ff00') <= 1",
+				"select _id from bitvecs where hamming('ff00', v) < 2",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+				row(int64(2)),
+				row(int64(6)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from bitvecs where v is null",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(5)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// the nearest vectors are found from the bit-planes, and only
+			// they are read
+			SQLs: sqls(
+				"select _id, hamming(v, 'ff01') as d from bitvecs where v is not null order by d limit 3",
+				"select _id, hamming(v, 'ff01') as d from bitvecs where v is not null order by 2 limit 3",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("d", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(2), int64(0)),
+				row(int64(1), int64(1)),
+				row(int64(6), int64(2)),
+			),
+			Compare: CompareExactOrdered,
+			PlanCheck: func(jplan []byte) error {
+				return operatorPresentAtPath(jplan, "$.child.child.child.child.nearest.column", "v")
+			},
+		},
+		{
+			SQLs: sqls(
+				"select _id, hamming(v, 'ff01') from bitvecs where g = 2 order by hamming(v, 'ff01') limit 2",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(6), int64(2)),
+				row(int64(3), int64(5)),
+			),
+			Compare: CompareExactOrdered,
+		},
+		{
+			// records without a vector sort first
+			SQLs: sqls(
+				"select _id, hamming(v, 'ff01') as d from bitvecs order by d limit 2",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("d", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(5), nil),
+				row(int64(2), int64(0)),
+			),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"insert into bitvecs (_id, v) values (1, null), (4, 'ff01'), (5, '0000')",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id, v from bitvecs where _id in (1, 4, 5)",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("v", fldTypeBitVector16),
+			),
+			ExpRows: rows(
+				row(int64(1), nil),
+				row(int64(4), "ff01"),
+				row(int64(5), "0000"),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from bitvecs where hamming(v, 'ff01') = 0",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(2)),
+				row(int64(4)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"insert into bitvecs (_id, v) values (7, 'ff0')",
+				"insert into bitvecs (_id, v) values (7, 'fg00')",
+			),
+			ExpErr: "invalid bitvector",
+		},
+		{
+			SQLs: sqls(
+				"select _id from bitvecs where hamming(g, 'ff00') = 0",
+			),
+			ExpErr: "bitvector or string expression expected",
+		},
+		{
+			SQLs: sqls(
+				"select _id from bitvecs order by v",
+			),
+			ExpErr: "unable to sort a column of type 'bitvector(16)'",
+		},
+		{
+			SQLs: sqls(
+				"create table bitvecs_bad (_id id, v bitvector(12))",
+				"create table bitvecs_bad (_id id, v bitvector(8192))",
+			),
+			ExpErr: "invalid bitvector width",
+		},
+		{
+			SQLs: sqls(
+				"create table bitvecs_bad (_id id, v bitvector)",
+			),
+			ExpErr: "bitvector width expected",
+		},
+		{
+			SQLs: sqls(
+				"create table bitvecs_bad (_id id, v bitvector(8) min 0)",
+			),
+			ExpErr: "'MIN' constraint cannot be applied to a column of type 'bitvector'",
+		},
+	},
+}
+
+// bitvector columns in a table with string keys
+var bitVectorKeyedTests = TableTest{
+	name: "bitvector_keyed_tests",
+	Table: tbl(
+		"bitvecs_keyed",
+		srcHdrs(
+			srcHdr("_id", fldTypeString),
+			srcHdr("v", fldTypeBitVector16),
+		),
+		srcRows(
+			srcRow("a", "ff00"),
+			srcRow("b", "ff01"),
+			srcRow("c", "0f00"),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			SQLs: sqls(
+				"select _id, hamming(v, '0f01') as d from bitvecs_keyed order by d limit 2",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeString),
+				hdr("d", fldTypeInt),
+			),
+			ExpRows: rows(
+				row("c", int64(1)),
+				row("b", int64(4)),
+			),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"insert into bitvecs_keyed (_id, v) values ('a', '0f01')",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id, v from bitvecs_keyed where hamming(v, '0f01') = 0",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeString),
+				hdr("v", fldTypeBitVector16),
+			),
+			ExpRows: rows(
+				row("a", "0f01"),
+			),
+			Compare: CompareExactUnordered,
+		},
+	},
+}
@@ -0,0 +1,223 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+package defs
+
+// composite primary key tests
+var compositeKeys = TableTest{
+	name: "compositeKeys",
+	SQLTests: []SQLTest{
+		{
+			name: "createTable",
+			SQLs: sqls(
+				"create table ck (_id string, region string, num id, n int, primary key (region, num))",
+			),
+		},
+		{
+			name: "insert",
+			SQLs: sqls(
+				"insert into ck (region, num, n) values ('east', 1, 10), ('east', 2, 20), ('west', 1, 30)",
+				"insert into ck values ('east|x', 1, 40)",
+			),
+		},
+		{
+			name: "selectAll",
+			SQLs: sqls(
+				"select _id, region, num, n from ck",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeString),
+				hdr("region", fldTypeString),
+				hdr("num", fldTypeID),
+				hdr("n", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(string("east|1"), string("east"), int64(1), int64(10)),
+				row(string("east|2"), string("east"), int64(2), int64(20)),
+				row(string("west|1"), string("west"), int64(1), int64(30)),
+				row(string(`east\|x|1`), string("east|x"), int64(1), int64(40)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "selectPrefix",
+			SQLs: sqls(
+				"select region, num, n from ck where region = 'east'",
+			),
+			ExpHdrs: hdrs(
+				hdr("region", fldTypeString),
+				hdr("num", fldTypeID),
+				hdr("n", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(string("east"), int64(1), int64(10)),
+				row(string("east"), int64(2), int64(20)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "selectPrefixAndFilter",
+			SQLs: sqls(
+				"select region, num, n from ck where n > 10 and region = 'east'",
+			),
+			ExpHdrs: hdrs(
+				hdr("region", fldTypeString),
+				hdr("num", fldTypeID),
+				hdr("n", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(string("east"), int64(2), int64(20)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "selectFullKey",
+			SQLs: sqls(
+				"select region, num, n from ck where num = 1 and region = 'west'",
+			),
+			ExpHdrs: hdrs(
+				hdr("region", fldTypeString),
+				hdr("num", fldTypeID),
+				hdr("n", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(string("west"), int64(1), int64(30)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// a condition on a later key column alone is an ordinary filter
+			name: "selectNotPrefix",
+			SQLs: sqls(
+				"select region, num, n from ck where num = 1",
+			),
+			ExpHdrs: hdrs(
+				hdr("region", fldTypeString),
+				hdr("num", fldTypeID),
+				hdr("n", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(string("east"), int64(1), int64(10)),
+				row(string("west"), int64(1), int64(30)),
+				row(string("east|x"), int64(1), int64(40)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "selectMissingKey",
+			SQLs: sqls(
+				"select region, num, n from ck where region = 'north'",
+			),
+			ExpHdrs: hdrs(
+				hdr("region", fldTypeString),
+				hdr("num", fldTypeID),
+				hdr("n", fldTypeInt),
+			),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "upsert",
+			SQLs: sqls(
+				"insert into ck (region, num, n) values ('east', 1, 11), ('south', 3, 50) on conflict do update set n = excluded.n + 100",
+			),
+		},
+		{
+			name: "selectUpsert",
+			SQLs: sqls(
+				"select _id, n from ck where region = 'east' or region = 'south'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeString),
+				hdr("n", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(string("east|1"), int64(111)),
+				row(string("east|2"), int64(20)),
+				row(string("south|3"), int64(50)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "bulkInsert",
+			SQLs: sqls(
+				`bulk insert into ck (region, num, n)
+					map (0 STRING, 1 ID, 2 INT)
+					from x'north,7,70'
+					with
+						format 'CSV'
+						input 'STREAM';`,
+			),
+		},
+		{
+			name: "selectBulkInsert",
+			SQLs: sqls(
+				"select _id, region, num, n from ck where region = 'north'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeString),
+				hdr("region", fldTypeString),
+				hdr("num", fldTypeID),
+				hdr("n", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(string("north|7"), string("north"), int64(7), int64(70)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "insertID",
+			SQLs: sqls(
+				"insert into ck (_id, region, num, n) values ('a', 'east', 1, 1)",
+			),
+			ExpErr: "_id column of table 'ck' is made from its primary key and cannot be inserted",
+		},
+		{
+			name: "insertMissingKeyColumn",
+			SQLs: sqls(
+				"insert into ck (region, n) values ('east', 1)",
+			),
+			ExpErr: "insert must have primary key column 'num'",
+		},
+		{
+			name: "upsertKeyColumn",
+			SQLs: sqls(
+				"insert into ck (region, num, n) values ('east', 1, 1) on conflict do update set region = 'west'",
+			),
+			ExpErr: "primary key column 'region' cannot be changed",
+		},
+		{
+			name: "dropKeyColumn",
+			SQLs: sqls(
+				"alter table ck drop column num",
+			),
+			ExpErr: "primary key column 'num' cannot be changed",
+		},
+		{
+			name: "renameKeyColumn",
+			SQLs: sqls(
+				"alter table ck rename column region to area",
+			),
+			ExpErr: "primary key column 'region' cannot be changed",
+		},
+		{
+			name: "createKeyIDNotString",
+			SQLs: sqls(
+				"create table ckbad (_id id, a string, primary key (a))",
+			),
+			ExpErr: "_id column must be of type STRING when a primary key is specified",
+		},
+		{
+			name: "createKeyColumnType",
+			SQLs: sqls(
+				"create table ckbad (_id string, a stringset, primary key (a))",
+			),
+			ExpErr: "primary key column 'a' must be of type ID, INT or STRING",
+		},
+		{
+			name: "createKeyColumnNotFound",
+			SQLs: sqls(
+				"create table ckbad (_id string, a string, primary key (a, b))",
+			),
+			ExpErr: "column 'b' not found",
+		},
+	},
+}
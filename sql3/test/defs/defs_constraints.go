@@ -0,0 +1,175 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+package defs
+
+import (
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/pql"
+)
+
+// NOT NULL, DEFAULT and CHECK column constraint tests
+var columnConstraints = TableTest{
+	name: "columnConstraints",
+	SQLTests: []SQLTest{
+		{
+			name: "createTableWithConstraints",
+			SQLs: sqls(
+				`create table cons (
+					_id id,
+					i int not null check (i between 0 and 100),
+					d decimal(2) default 1.5 check (d > 0),
+					s string default 'none' check (s in ('none', 'some', 'many')),
+					b bool default true,
+					n int default -1,
+					t timestamp default '2023-01-01T00:00:00Z' check (t >= '2020-01-01')
+				)`,
+			),
+		},
+		{
+			// omitted columns take their defaults
+			name: "insertDefaults",
+			SQLs: sqls(
+				"insert into cons (_id, i) values (1, 10)",
+			),
+		},
+		{
+			name: "selectDefaults",
+			SQLs: sqls(
+				"select _id, i, d, s, b, n, t from cons where _id = 1",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("i", fldTypeInt),
+				hdr("d", fldTypeDecimal2),
+				hdr("s", fldTypeString),
+				hdr("b", fldTypeBool),
+				hdr("n", fldTypeInt),
+				hdr("t", fldTypeTimestamp),
+			),
+			ExpRows: rows(
+				row(int64(1), int64(10), pql.NewDecimal(150, 2), "none", true, int64(-1), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// an explicit null takes the default too
+			name: "insertNullDefault",
+			SQLs: sqls(
+				"insert into cons (_id, i, s) values (2, 20, null)",
+			),
+		},
+		{
+			name: "selectNullDefault",
+			SQLs: sqls(
+				"select _id, s from cons where _id = 2",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("s", fldTypeString),
+			),
+			ExpRows: rows(
+				row(int64(2), "none"),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "insertNotNullOmitted",
+			SQLs: sqls(
+				"insert into cons (_id, s) values (3, 'some')",
+			),
+			ExpErr: "inserting row 1: column 'i' violates NOT NULL constraint",
+		},
+		{
+			name: "insertNotNullExplicit",
+			SQLs: sqls(
+				"insert into cons (_id, i) values (3, 30), (4, null)",
+			),
+			ExpErr: "inserting row 2: column 'i' violates NOT NULL constraint",
+		},
+		{
+			name: "insertCheckInt",
+			SQLs: sqls(
+				"insert into cons (_id, i) values (5, 101)",
+			),
+			ExpErr: "inserting row 1: column 'i' violates CHECK constraint (i BETWEEN 0 AND 100)",
+		},
+		{
+			name: "insertCheckDecimal",
+			SQLs: sqls(
+				"insert into cons (_id, i, d) values (5, 50, -0.5)",
+			),
+			ExpErr: "column 'd' violates CHECK constraint (d > 0)",
+		},
+		{
+			name: "insertCheckString",
+			SQLs: sqls(
+				"insert into cons (_id, i, s) values (5, 50, 'few')",
+			),
+			ExpErr: "column 's' violates CHECK constraint (s IN ('none', 'some', 'many'))",
+		},
+		{
+			name: "insertCheckTimestamp",
+			SQLs: sqls(
+				"insert into cons (_id, i, t) values (5, 50, '2019-12-31T00:00:00Z')",
+			),
+			ExpErr: "column 't' violates CHECK constraint (t >= '2020-01-01')",
+		},
+		{
+			// rejected rows leave nothing behind
+			name: "selectAfterRejectedInserts",
+			SQLs: sqls(
+				"select _id from cons",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+				row(int64(2)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "defaultWrongType",
+			SQLs: sqls(
+				"create table cons_bad (_id id, i int default 'a')",
+			),
+			ExpErr: "an expression of type 'string' cannot be assigned to type 'int'",
+		},
+		{
+			name: "defaultColumnReference",
+			SQLs: sqls(
+				"create table cons_bad (_id id, i int, j int default (i + 1))",
+			),
+			ExpErr: "column reference 'i' not allowed",
+		},
+		{
+			name: "checkOtherColumn",
+			SQLs: sqls(
+				"create table cons_bad (_id id, i int, j int check (i > j))",
+			),
+			ExpErr: "column 'i' can't be referenced by a constraint on column 'j'",
+		},
+		{
+			name: "checkWrongType",
+			SQLs: sqls(
+				"create table cons_bad (_id id, i int check (i > 'a'))",
+			),
+			ExpErr: "operator '>' incompatible with type 'string'",
+		},
+		{
+			name: "checkNotBoolean",
+			SQLs: sqls(
+				"create table cons_bad (_id id, i int check (i + 1))",
+			),
+			ExpErr: "must be a boolean expression",
+		},
+		{
+			name: "checkOnSet",
+			SQLs: sqls(
+				"create table cons_bad (_id id, s stringset check (s = 'a'))",
+			),
+			ExpErr: "'CHECK' constraint cannot be applied to a column of type 'stringset'",
+		},
+	},
+}
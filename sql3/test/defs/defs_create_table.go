@@ -44,6 +44,41 @@ var createTable = TableTest{
 				"create table bar (_id id, i1 int) comment 'this should work'",
 			),
 		},
+		{
+			name: "retentionColumnNotFound",
+			SQLs: sqls(
+				"create table foo (_id id, ts timestamp) retention t1 ttl '24h'",
+			),
+			ExpErr: "column 't1' not found",
+		},
+		{
+			name: "retentionColumnNotTimestamp",
+			SQLs: sqls(
+				"create table foo (_id id, i1 int) retention i1 ttl '24h'",
+			),
+			ExpErr: "retention column 'i1' must be of type timestamp",
+		},
+		{
+			name: "retentionBadDuration",
+			SQLs: sqls(
+				"create table foo (_id id, ts timestamp) retention ts ttl '90d'",
+				"create table foo (_id id, ts timestamp) retention ts ttl '0s'",
+			),
+			ExpErr: "is not a valid time duration",
+		},
+		{
+			name: "retentionNoTTL",
+			SQLs: sqls(
+				"create table foo (_id id, ts timestamp) retention ts '24h'",
+			),
+			ExpErr: "expected TTL",
+		},
+		{
+			name: "retention",
+			SQLs: sqls(
+				"create table retained (_id string, ts timestamp, i1 int) retention ts ttl '2160h' comment 'expires after 90 days'",
+			),
+		},
 	},
 }
 
@@ -80,3 +115,81 @@ var alterTable = TableTest{
 		},
 	},
 }
+
+var alterTableColumns = TableTest{
+	name: "alterTableColumns",
+	Table: tbl(
+		"alter_columns_test",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("a_int", fldTypeInt),
+			srcHdr("i2", fldTypeInt),
+			srcHdr("s", fldTypeStringSet),
+		),
+		srcRows(
+			srcRow(int64(1), int64(10), int64(100), []string{"a", "b"}),
+			srcRow(int64(2), int64(-20), int64(200), []string{"c"}),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			name: "alterTableRenameColumn",
+			SQLs: sqls(
+				"alter table alter_columns_test rename column a_int to b_int",
+			),
+		},
+		{
+			// the renamed column keeps its values
+			name: "alterTableSelectRenamedColumn",
+			SQLs: sqls(
+				"select _id, b_int from alter_columns_test",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("b_int", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(1), int64(10)),
+				row(int64(2), int64(-20)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "alterTableRenameToExistingColumn",
+			SQLs: sqls(
+				"alter table alter_columns_test rename column b_int to i2",
+			),
+			ExpErr: "duplicate column 'i2'",
+		},
+		{
+			name: "alterTableRenameNonExistentColumn",
+			SQLs: sqls(
+				"alter table alter_columns_test rename column a_int to c_int",
+			),
+			ExpErr: "column 'a_int' not found",
+		},
+		{
+			name: "alterTableRenameID",
+			SQLs: sqls(
+				"alter table alter_columns_test rename column _id to id",
+				"alter table alter_columns_test rename column i2 to _id",
+				"alter table alter_columns_test alter column _id string",
+			),
+			ExpErr: "_id column cannot be renamed or altered",
+		},
+		{
+			name: "alterTableAlterNonExistentColumn",
+			SQLs: sqls(
+				"alter table alter_columns_test alter column c_int decimal(2)",
+			),
+			ExpErr: "column 'c_int' not found",
+		},
+		{
+			name: "alterTableAlterUnsupportedType",
+			SQLs: sqls(
+				"alter table alter_columns_test alter column s int",
+			),
+			ExpErr: "a field of type set can't be altered to int",
+		},
+	},
+}
@@ -0,0 +1,165 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+package defs
+
+// cursor tests
+var cursorTests = TableTest{
+	name: "cursor_tests",
+	Table: tbl(
+		"cursor_tbl",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("val", fldTypeInt, "min 0", "max 1000"),
+		),
+		srcRows(
+			srcRow(int64(1), int64(10)),
+			srcRow(int64(2), int64(20)),
+			srcRow(int64(3), int64(30)),
+			srcRow(int64(4), int64(40)),
+			srcRow(int64(5), int64(50)),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			SQLs: sqls(
+				"declare c1 cursor for select _id, val from cursor_tbl;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"declare c1 cursor for select _id from cursor_tbl;",
+			),
+			ExpErr: "cursor 'c1' already exists",
+		},
+		{
+			SQLs: sqls(
+				"fetch next 2 from c1;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("val", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(1), int64(10)),
+				row(int64(2), int64(20)),
+			),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"fetch 2 from c1;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("val", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(3), int64(30)),
+				row(int64(4), int64(40)),
+			),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"fetch next from c1;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("val", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(5), int64(50)),
+			),
+			Compare: CompareExactOrdered,
+		},
+		{
+			// the cursor is closed once it has been read to the end
+			SQLs: sqls(
+				"fetch next from c1;",
+				"close c1;",
+			),
+			ExpErr: "cursor 'c1' not found",
+		},
+		{
+			SQLs: sqls(
+				"fetch next 0 from c1;",
+			),
+			ExpErr: "integer literal expected",
+		},
+		{
+			SQLs: sqls(
+				"declare c2 cursor for select _id from cursor_tbl where val > 10 limit 2 offset 1;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"fetch next 10 from c2;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(3)),
+				row(int64(4)),
+			),
+			Compare: CompareExactOrdered,
+		},
+		{
+			// keyset pagination, carrying on from the last _id of the previous page
+			SQLs: sqls(
+				"select _id, val from cursor_tbl where _id > 2 order by _id limit 2;",
+				"select _id, val from cursor_tbl order by _id limit 2 offset 2;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("val", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(3), int64(30)),
+				row(int64(4), int64(40)),
+			),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"select _id, val from cursor_tbl where _id < 4 order by _id desc limit 2;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("val", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(3), int64(30)),
+				row(int64(2), int64(20)),
+			),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"declare c3 cursor for select _id from cursor_tbl;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"close c3;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"fetch next from c3;",
+			),
+			ExpErr: "cursor 'c3' not found",
+		},
+	},
+}
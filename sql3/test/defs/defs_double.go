@@ -0,0 +1,143 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+package defs
+
+// double tests
+var doubleTests = TableTest{
+	name: "double_tests",
+	Table: tbl(
+		"doubles",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("d", fldTypeDouble),
+			srcHdr("i", fldTypeInt),
+		),
+		srcRows(
+			srcRow(int64(1), float64(-2.5), int64(1)),
+			srcRow(int64(2), float64(0.25), int64(2)),
+			srcRow(int64(3), float64(1.5), int64(3)),
+			srcRow(int64(4), nil, int64(4)),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			SQLs: sqls(
+				"select _id, d from doubles",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("d", fldTypeDouble),
+			),
+			ExpRows: rows(
+				row(int64(1), float64(-2.5)),
+				row(int64(2), float64(0.25)),
+				row(int64(3), float64(1.5)),
+				row(int64(4), nil),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from doubles where d > 0",
+				"select _id from doubles where d >= 0.25",
+				"select _id from doubles where d between 0.1 and 2",
+				"select _id from doubles where d in (0.25, 1.5)",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(2)),
+				row(int64(3)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from doubles where d is null",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(4)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// arithmetic with an int promotes to double
+			SQLs: sqls(
+				"select _id, d * i as di from doubles where _id = 3",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("di", fldTypeDouble),
+			),
+			ExpRows: rows(
+				row(int64(3), float64(4.5)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select cast(d as int) as di, cast(i as double) as id from doubles where _id = 1",
+			),
+			ExpHdrs: hdrs(
+				hdr("di", fldTypeInt),
+				hdr("id", fldTypeDouble),
+			),
+			ExpRows: rows(
+				row(int64(-2), float64(1)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select sum(d) as s, min(d) as mn, max(d) as mx, avg(d) as av from doubles",
+			),
+			ExpHdrs: hdrs(
+				hdr("s", fldTypeDouble),
+				hdr("mn", fldTypeDouble),
+				hdr("mx", fldTypeDouble),
+				hdr("av", fldTypeDouble),
+			),
+			ExpRows: rows(
+				row(float64(-0.75), float64(-2.5), float64(1.5), float64(-0.25)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// group by can't be pushed down to PQL for doubles
+			SQLs: sqls(
+				"select d, count(*) as c from doubles where d < 1 group by d",
+			),
+			ExpHdrs: hdrs(
+				hdr("d", fldTypeDouble),
+				hdr("c", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(float64(-2.5), int64(1)),
+				row(float64(0.25), int64(1)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from doubles order by d desc limit 2",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(3)),
+				row(int64(2)),
+			),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"create table double_bad (_id id, d double min 0)",
+			),
+			ExpErr: "'MIN' constraint cannot be applied to a column of type 'double'",
+		},
+	},
+}
@@ -0,0 +1,190 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+package defs
+
+// geopoint column tests
+var geoPointTests = TableTest{
+	name: "geopoint_tests",
+	Table: tbl(
+		"geo_points",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("loc", fldTypeGeoPoint),
+			srcHdr("i", fldTypeInt),
+		),
+		srcRows(
+			srcRow(int64(1), []float64{37.7955, -122.3937}, int64(1)),
+			srcRow(int64(2), []float64{37.8024, -122.4058}, int64(2)),
+			srcRow(int64(3), []float64{37.8044, -122.2712}, int64(3)),
+			srcRow(int64(4), []float64{-18.1416, 178.4419}, int64(4)),
+			srcRow(int64(5), []float64{-13.8333, -171.75}, int64(5)),
+			srcRow(int64(6), nil, int64(6)),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			SQLs: sqls(
+				"select _id, loc from geo_points where i < 3 or i = 6",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("loc", fldTypeGeoPoint),
+			),
+			ExpRows: rows(
+				row(int64(1), []float64{37.7955, -122.3937}),
+				row(int64(2), []float64{37.8024, -122.4058}),
+				row(int64(6), nil),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from geo_points where st_dwithin(loc, 37.7955, -122.3937, 2000)",
+				"select _id from geo_points where st_distance(loc, st_point(37.7955, -122.3937)) < 2000",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+				row(int64(2)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// the cells narrow down the records read, and the distance is
+			// then checked for each of them
+			SQLs: sqls(
+				"select _id from geo_points where st_dwithin(loc, 37.7955, -122.3937, 15000)",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+				row(int64(2)),
+				row(int64(3)),
+			),
+			Compare: CompareExactUnordered,
+			PlanCheck: func(jplan []byte) error {
+				if err := operatorPresentAtPath(jplan, "$.child.child._op", "*planner.PlanOpFilter"); err != nil {
+					return err
+				}
+				return operatorPresentAtPath(jplan, "$.child.child.child.filter.name", "st_dwithin")
+			},
+		},
+		{
+			SQLs: sqls(
+				"select _id from geo_points where st_within_box(loc, 37.79, -122.41, 37.81, -122.39)",
+				"select _id from geo_points where st_within_box(loc, 37.79, -122.41, 37.81, -122.39) and i < 5",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+				row(int64(2)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// a box and a circle crossing the antimeridian
+			SQLs: sqls(
+				"select _id from geo_points where st_within_box(loc, -20, 170, -10, -170)",
+				"select _id from geo_points where st_dwithin(loc, -16, 180, 1000000)",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(4)),
+				row(int64(5)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select st_distance(loc, st_point(37.7955, -122.3937)) as d, st_dwithin(loc, 0, 0, 1) as w from geo_points where _id in (1, 6)",
+			),
+			ExpHdrs: hdrs(
+				hdr("d", fldTypeDouble),
+				hdr("w", fldTypeBool),
+			),
+			ExpRows: rows(
+				row(float64(0), false),
+				row(nil, nil),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// moving a point replaces its cells
+			SQLs: sqls(
+				"insert into geo_points (_id, loc) values (3, st_point(37.8, -122.4)), (7, {0, 0})",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from geo_points where st_dwithin(loc, 37.7955, -122.3937, 2000)",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+				row(int64(2)),
+				row(int64(3)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from geo_points where st_dwithin(loc, 37.8044, -122.2712, 1000) or st_within_box(loc, -1, -1, 1, 1)",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(7)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"insert into geo_points (_id, loc) values (8, {91, 0})",
+			),
+			ExpErr: "invalid geopoint",
+		},
+		{
+			SQLs: sqls(
+				"insert into geo_points (_id, loc) values (8, 'here')",
+			),
+			ExpErr: "an expression of type 'string' cannot be assigned to type 'geopoint'",
+		},
+		{
+			SQLs: sqls(
+				"select _id from geo_points where st_dwithin(i, 0, 0, 10)",
+			),
+			ExpErr: "geopoint expression expected",
+		},
+		{
+			SQLs: sqls(
+				"select _id from geo_points where st_dwithin(loc, 'a', 0, 10)",
+			),
+			ExpErr: "integer, decimal or double expression expected",
+		},
+		{
+			SQLs: sqls(
+				"select _id from geo_points where st_within_box(loc, 10, 0, -10, 1)",
+			),
+			ExpErr: "invalid box",
+		},
+		{
+			SQLs: sqls(
+				"create table geo_bad (_id id, loc geopoint min 0)",
+			),
+			ExpErr: "'MIN' constraint cannot be applied to a column of type 'geopoint'",
+		},
+	},
+}
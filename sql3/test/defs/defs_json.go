@@ -0,0 +1,197 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+package defs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// json column tests
+var jsonTests = TableTest{
+	name: "json_tests",
+	Table: tbl(
+		"json_docs",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("doc", fldTypeJSON, "index paths ('$.country', '$.plan.tier')"),
+			srcHdr("i", fldTypeInt),
+		),
+		srcRows(
+			srcRow(int64(1), string(`{"country":"us","plan":{"tier":"pro"},"n":3}`), int64(1)),
+			srcRow(int64(2), string(`{"country":"uk","plan":{"tier":"free"},"tags":["a","b"]}`), int64(2)),
+			srcRow(int64(3), string(`{"country": "us", "plan": {"tier": "free"}}`), int64(3)),
+			srcRow(int64(4), nil, int64(4)),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			// documents are stored compacted
+			SQLs: sqls(
+				"select _id, doc from json_docs where _id = 3",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("doc", fldTypeJSON),
+			),
+			ExpRows: rows(
+				row(int64(3), string(`{"country":"us","plan":{"tier":"free"}}`)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id, json_value(doc, '$.country') as c, json_value(doc, '$.n') as n, json_value(doc, '$.plan') as p, json_exists(doc, '$.tags') as t from json_docs",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("c", fldTypeString),
+				hdr("n", fldTypeString),
+				hdr("p", fldTypeString),
+				hdr("t", fldTypeBool),
+			),
+			ExpRows: rows(
+				row(int64(1), string("us"), string("3"), nil, false),
+				row(int64(2), string("uk"), nil, nil, true),
+				row(int64(3), string("us"), nil, nil, false),
+				row(int64(4), nil, nil, nil, nil),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// filters on indexed paths are pushed down; others are evaluated
+			// per row
+			SQLs: sqls(
+				"select _id from json_docs where json_value(doc, '$.country') = 'us'",
+				"select _id from json_docs where json_value(doc, '$.country') = 'us' and i > 0",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+				row(int64(3)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from json_docs where json_value(doc, '$.plan.tier') = 'free' and json_value(doc, '$.country') = 'uk'",
+				"select _id from json_docs where json_value(doc, '$.tags[1]') = 'b'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(2)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select json_value('{\"a\":{\"b\":true}}', '$.a.b') as b, cast(doc as string) as s from json_docs where _id = 1",
+			),
+			ExpHdrs: hdrs(
+				hdr("b", fldTypeString),
+				hdr("s", fldTypeString),
+			),
+			ExpRows: rows(
+				row(string("true"), string(`{"country":"us","plan":{"tier":"pro"},"n":3}`)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// replacing a document replaces its indexed values
+			SQLs: sqls(
+				`insert into json_docs (_id, doc) values (1, '{"country":"ca","plan":{"tier":"pro"}}')`,
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// the new document, which is shorter, leaves nothing of the old
+			SQLs: sqls(
+				"select _id, doc from json_docs where _id = 1 or _id = 4",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("doc", fldTypeJSON),
+			),
+			ExpRows: rows(
+				row(int64(1), string(`{"country":"ca","plan":{"tier":"pro"}}`)),
+				row(int64(4), nil),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from json_docs where json_value(doc, '$.country') = 'us'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(3)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from json_docs where json_value(doc, '$.country') = 'ca'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+			),
+			Compare: CompareExactUnordered,
+			PlanCheck: func(jplan []byte) error {
+				return operatorPresentAtPath(jplan, "$.child.child._op", "*planner.PlanOpPQLTableScan")
+			},
+		},
+		{
+			SQLs: sqls(
+				`insert into json_docs (_id, doc) values (5, '{"country":')`,
+			),
+			ExpErr: "unable to parse JSON",
+		},
+		{
+			// each byte of a document takes a row, so their length is capped
+			SQLs: sqls(
+				fmt.Sprintf(`insert into json_docs (_id, doc) values (5, '{"s":"%s"}')`, strings.Repeat("a", 1<<16)),
+			),
+			ExpErr: "JSON document of 65544 bytes exceeds the maximum of 65536",
+		},
+		{
+			SQLs: sqls(
+				"select json_value(doc, 'country') from json_docs",
+			),
+			ExpErr: "invalid JSON path 'country'",
+		},
+		{
+			SQLs: sqls(
+				"select json_value(i, '$.a') from json_docs",
+			),
+			ExpErr: "json or string expression expected",
+		},
+		{
+			SQLs: sqls(
+				"create table json_bad (_id id, s string index paths ('$.a'))",
+			),
+			ExpErr: "'INDEX PATHS' constraint cannot be applied to a column of type 'string'",
+		},
+		{
+			SQLs: sqls(
+				"create table json_bad (_id id, doc json index paths ('$.a', '$.a['))",
+			),
+			ExpErr: "invalid JSON path",
+		},
+		{
+			SQLs: sqls(
+				"create table json_bad (_id id, doc json index paths ('$.a', '$.A'))",
+			),
+			ExpErr: "duplicate column 'doc__a'",
+		},
+	},
+}
@@ -0,0 +1,281 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+package defs
+
+import "time"
+
+// materialized view tests
+var materializedViewTests = TableTest{
+	name: "materialized_view_tests",
+	Table: tbl(
+		"mv_sales",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("region", fldTypeString),
+			srcHdr("amount", fldTypeInt, "min 0", "max 1000"),
+			srcHdr("events", fldTypeStringSetQ, "timequantum 'YMD'"),
+		),
+		srcRows(
+			srcRow(int64(1), string("east"), int64(10), nil),
+			srcRow(int64(2), string("east"), int64(20), nil),
+			srcRow(int64(3), string("west"), int64(30), nil),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			SQLs: sqls(
+				"create materialized view mv_totals refresh every '1h' as select region, sum(amount) as total, count(*) from mv_sales group by region;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select region, total, column3 from mv_totals;",
+				"select * from mv_totals;",
+			),
+			ExpHdrs: hdrs(
+				hdr("region", fldTypeString),
+				hdr("total", fldTypeInt),
+				hdr("column3", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(string("east"), int64(30), int64(2)),
+				row(string("west"), int64(30), int64(1)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"insert into mv_sales (_id, region, amount) values (4, 'west', 5);",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// the view isn't changed until it's refreshed
+			SQLs: sqls(
+				"select region, total from mv_totals;",
+			),
+			ExpHdrs: hdrs(
+				hdr("region", fldTypeString),
+				hdr("total", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(string("east"), int64(30)),
+				row(string("west"), int64(30)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"refresh materialized view mv_totals;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select region, total from mv_totals where total > 30;",
+			),
+			ExpHdrs: hdrs(
+				hdr("region", fldTypeString),
+				hdr("total", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(string("west"), int64(35)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"create materialized view mv_totals as select region from mv_sales;",
+				"create view mv_totals as select region from mv_sales;",
+			),
+			ExpErr: "view 'mv_totals' already exists",
+		},
+		{
+			SQLs: sqls(
+				"create materialized view if not exists mv_totals as select region from mv_sales;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"create materialized view mv_sales as select region from mv_sales;",
+			),
+			ExpErr: "table or view 'mv_sales' already exists",
+		},
+		{
+			SQLs: sqls(
+				"create materialized view mv_bad refresh every 'often' as select region from mv_sales;",
+			),
+			ExpErr: "'often' is not a valid time duration",
+		},
+		{
+			SQLs: sqls(
+				"refresh materialized view mv_missing;",
+			),
+			ExpErr: "view 'mv_missing' not found",
+		},
+		{
+			// a GROUP BY filtered on a time quantum is stored one bucket of the
+			// quantum at a time
+			SQLs: sqls(
+				"insert into mv_sales (_id, region, events) values (5, 'east', {'2022-01-01T10:00:00Z', ['click']}), (6, 'east', {'2022-01-01T11:00:00Z', ['view']}), (7, 'west', {'2022-01-02T10:00:00Z', ['click']}), (8, 'east', {'2022-01-05T10:00:00Z', ['click']});",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// aggregates filter records by time quantum
+			SQLs: sqls(
+				"select region, count(*) from mv_sales where rangeq(events, '2022-01-02T00:00:00Z', '2022-01-03T00:00:00Z') group by region;",
+			),
+			ExpHdrs: hdrs(
+				hdr("region", fldTypeString),
+				hdr("", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(string("west"), int64(1)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"create materialized view mv_daily as select region, count(*) as events from mv_sales where rangeq(events, '2022-01-01T00:00:00Z', '2022-01-04T00:00:00Z') group by region;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select quantum_start, region, events from mv_daily;",
+			),
+			ExpHdrs: hdrs(
+				hdr("quantum_start", fldTypeTimestamp),
+				hdr("region", fldTypeString),
+				hdr("events", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), string("east"), int64(2)),
+				row(time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), string("west"), int64(1)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"insert into mv_sales (_id, region, events) values (9, 'west', {'2022-01-01T12:00:00Z', ['click']});",
+				"refresh materialized view mv_daily;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// late data in a bucket before the last refresh is picked up
+			SQLs: sqls(
+				"select quantum_start, quantum_records, region, events from mv_daily;",
+			),
+			ExpHdrs: hdrs(
+				hdr("quantum_start", fldTypeTimestamp),
+				hdr("quantum_records", fldTypeInt),
+				hdr("region", fldTypeString),
+				hdr("events", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), int64(3), string("east"), int64(2)),
+				row(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), int64(3), string("west"), int64(1)),
+				row(time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), int64(1), string("west"), int64(1)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"delete from mv_sales where _id = 7;",
+				"refresh materialized view mv_daily;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// as is the removal of all the data in a bucket
+			SQLs: sqls(
+				"select quantum_start, region, events from mv_daily;",
+			),
+			ExpHdrs: hdrs(
+				hdr("quantum_start", fldTypeTimestamp),
+				hdr("region", fldTypeString),
+				hdr("events", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), string("east"), int64(2)),
+				row(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), string("west"), int64(1)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"insert into mv_sales (_id, region) values (9, 'east');",
+				"refresh materialized view mv_daily;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// as is a change to a record in an earlier bucket which leaves
+			// its number of records the same
+			SQLs: sqls(
+				"select quantum_start, quantum_records, region, events from mv_daily;",
+			),
+			ExpHdrs: hdrs(
+				hdr("quantum_start", fldTypeTimestamp),
+				hdr("quantum_records", fldTypeInt),
+				hdr("region", fldTypeString),
+				hdr("events", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), int64(3), string("east"), int64(3)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"drop materialized view mv_daily;",
+				"drop view mv_totals;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select * from mv_totals;",
+			),
+			ExpErr: "table or view 'mv_totals' not found",
+		},
+		{
+			SQLs: sqls(
+				"create view mv_plain as select region from mv_sales;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// DROP MATERIALIZED VIEW doesn't drop plain views
+			SQLs: sqls(
+				"drop materialized view mv_plain;",
+			),
+			ExpErr: "view 'mv_plain' not found",
+		},
+	},
+}
@@ -120,5 +120,59 @@ var topLimitTests = TableTest{
 			),
 			ExpErr: "TOP and LIMIT cannot cannot be used at the same time",
 		},
+		{
+			SQLs: sqls(
+				"select _id, id1 from skills limit 1 offset 1;",
+				"select _id, id1 from skills offset 1;",
+				"select top(1) _id, id1 from skills offset 1;",
+				"select _id, id1 from skills order by _id desc limit 1 offset 0;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("id1", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(2), int64(288)),
+			),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from skills order by _id desc offset 1;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+			),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"select count(*) from skills offset 1;",
+			),
+			ExpHdrs: hdrs(
+				hdr("", fldTypeInt),
+			),
+			ExpRows: rows(),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from skills limit 1 offset 2;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(),
+			Compare: CompareExactOrdered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from skills offset 'a';",
+			),
+			ExpErr: "integer literal expected",
+		},
 	},
 }
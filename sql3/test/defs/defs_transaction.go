@@ -0,0 +1,175 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+package defs
+
+// transaction tests
+var transactionTests = TableTest{
+	name: "transaction_tests",
+	Table: tbl(
+		"txn_orders",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("customer", fldTypeString),
+			srcHdr("amount", fldTypeInt, "min 0", "max 1000"),
+			srcHdr("tags", fldTypeStringSet),
+		),
+		srcRows(
+			srcRow(int64(1), string("acme"), int64(10), []string{"a"}),
+			srcRow(int64(2), string("acme"), int64(20), []string{"b"}),
+			srcRow(int64(3), string("initech"), int64(30), []string{"c"}),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			// replace a customer's rows
+			SQLs: sqls(
+				"begin; delete from txn_orders where customer = 'acme'; insert into txn_orders (_id, customer, amount) values (4, 'acme', 40); commit;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id, customer, amount, tags from txn_orders;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("customer", fldTypeString),
+				hdr("amount", fldTypeInt),
+				hdr("tags", fldTypeStringSet),
+			),
+			ExpRows: rows(
+				row(int64(3), string("initech"), int64(30), []string{"c"}),
+				row(int64(4), string("acme"), int64(40), nil),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// deleting and re-inserting the same record leaves only the new values
+			SQLs: sqls(
+				"begin transaction; delete from txn_orders where _id = 3; insert into txn_orders (_id, customer) values (3, 'initrode'); end transaction;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id, customer, amount, tags from txn_orders where _id = 3;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("customer", fldTypeString),
+				hdr("amount", fldTypeInt),
+				hdr("tags", fldTypeStringSet),
+			),
+			ExpRows: rows(
+				row(int64(3), string("initrode"), nil, nil),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// nothing is applied on rollback
+			SQLs: sqls(
+				"begin; delete from txn_orders; insert into txn_orders (_id, customer) values (5, 'hooli'); rollback;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from txn_orders;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(3)),
+				row(int64(4)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// updated values can be worked out from the existing ones, and
+			// a set is replaced rather than added to
+			SQLs: sqls(
+				"begin; insert into txn_orders (_id, customer, amount, tags) values (6, 'globex', 60, ['d']); update txn_orders set amount = amount + 5, tags = ['e', 'f'] where customer = 'acme'; commit;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id, customer, amount, tags from txn_orders;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("customer", fldTypeString),
+				hdr("amount", fldTypeInt),
+				hdr("tags", fldTypeStringSet),
+			),
+			ExpRows: rows(
+				row(int64(3), string("initrode"), nil, nil),
+				row(int64(4), string("acme"), int64(45), []string{"e", "f"}),
+				row(int64(6), string("globex"), int64(60), []string{"d"}),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// an update without a where clause changes every record
+			SQLs: sqls(
+				"begin; update txn_orders set customer = 'hooli'; commit;",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id, customer from txn_orders;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("customer", fldTypeString),
+			),
+			ExpRows: rows(
+				row(int64(3), string("hooli")),
+				row(int64(4), string("hooli")),
+				row(int64(6), string("hooli")),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"begin; select * from txn_orders; commit;",
+			),
+			ExpErr: "statements other than INSERT, REPLACE, UPDATE and DELETE within a transaction are not supported",
+		},
+		{
+			SQLs: sqls(
+				"begin; update txn_orders set _id = 1; commit;",
+			),
+			ExpErr: "primary key column '_id' cannot be changed",
+		},
+		{
+			SQLs: sqls(
+				"begin; update txn_orders set amount = 'ten'; commit;",
+			),
+			ExpErr: "an expression of type 'string' cannot be assigned to type 'int'",
+		},
+		{
+			SQLs: sqls(
+				"begin; update txn_orders set nope = 1; commit;",
+			),
+			ExpErr: "column 'nope' not found",
+		},
+		{
+			SQLs: sqls(
+				"commit;",
+			),
+			ExpErr: "COMMIT without a preceding BEGIN",
+		},
+	},
+}
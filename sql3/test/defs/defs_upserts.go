@@ -0,0 +1,207 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+package defs
+
+// ON CONFLICT and REPLACE INTO tests
+var insertConflicts = TableTest{
+	name: "insertConflicts",
+	SQLTests: []SQLTest{
+		{
+			name: "createTables",
+			SQLs: sqls(
+				"create table ups (_id string, n int, s string, tags stringset)",
+				"create table upsi (_id id, n int, ids idset)",
+			),
+		},
+		{
+			// without a conflict clause, set values are merged
+			name: "insertMerge",
+			SQLs: sqls(
+				"insert into ups values ('a', 1, 'x', ['p', 'q'])",
+				"insert into ups (_id, n, tags) values ('a', 2, ['r'])",
+			),
+		},
+		{
+			name: "selectMerge",
+			SQLs: sqls(
+				"select _id, n, s, tags from ups",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeString),
+				hdr("n", fldTypeInt),
+				hdr("s", fldTypeString),
+				hdr("tags", fldTypeStringSet),
+			),
+			ExpRows: rows(
+				row(string("a"), int64(2), string("x"), []string{"p", "q", "r"}),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "insertDoNothing",
+			SQLs: sqls(
+				"insert into ups (_id, n, tags) values ('a', 3, ['z']), ('b', 4, ['y']) on conflict do nothing",
+			),
+		},
+		{
+			name: "selectDoNothing",
+			SQLs: sqls(
+				"select _id, n, s, tags from ups",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeString),
+				hdr("n", fldTypeInt),
+				hdr("s", fldTypeString),
+				hdr("tags", fldTypeStringSet),
+			),
+			ExpRows: rows(
+				row(string("a"), int64(2), string("x"), []string{"p", "q", "r"}),
+				row(string("b"), int64(4), nil, []string{"y"}),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// conflicting rows only write the assigned columns, replacing
+			// set values; new rows are inserted as they are
+			name: "insertDoUpdate",
+			SQLs: sqls(
+				"insert into ups (_id, n, s, tags) values ('a', 5, 'new', ['w']), ('c', 6, 'c', ['v']) on conflict (_id) do update set tags = excluded.tags, n = excluded.n + 10",
+			),
+		},
+		{
+			name: "selectDoUpdate",
+			SQLs: sqls(
+				"select _id, n, s, tags from ups",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeString),
+				hdr("n", fldTypeInt),
+				hdr("s", fldTypeString),
+				hdr("tags", fldTypeStringSet),
+			),
+			ExpRows: rows(
+				row(string("a"), int64(15), string("x"), []string{"w"}),
+				row(string("b"), int64(4), nil, []string{"y"}),
+				row(string("c"), int64(6), string("c"), []string{"v"}),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// replace clears everything the record had
+			name: "replace",
+			SQLs: sqls(
+				"replace into ups (_id, n) values ('a', 7), ('d', 8)",
+			),
+		},
+		{
+			name: "selectReplace",
+			SQLs: sqls(
+				"select _id, n, s, tags from ups",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeString),
+				hdr("n", fldTypeInt),
+				hdr("s", fldTypeString),
+				hdr("tags", fldTypeStringSet),
+			),
+			ExpRows: rows(
+				row(string("a"), int64(7), nil, nil),
+				row(string("b"), int64(4), nil, []string{"y"}),
+				row(string("c"), int64(6), string("c"), []string{"v"}),
+				row(string("d"), int64(8), nil, nil),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "insertDoNothingID",
+			SQLs: sqls(
+				"insert into upsi values (1, 1, [1])",
+				"insert into upsi values (1, 2, [2]), (2, 3, [3]) on conflict do nothing",
+				"insert into upsi values (2, 4, [4]) on conflict do update set ids = excluded.ids",
+			),
+		},
+		{
+			name: "selectID",
+			SQLs: sqls(
+				"select _id, n, ids from upsi",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("n", fldTypeInt),
+				hdr("ids", fldTypeIDSet),
+			),
+			ExpRows: rows(
+				row(int64(1), int64(1), []int64{1}),
+				row(int64(2), int64(3), []int64{4}),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "bulkReplace",
+			SQLs: sqls(
+				`bulk replace into upsi (_id, n)
+					map (0 ID, 1 INT)
+					from x'1,10'
+					with
+						format 'CSV'
+						input 'STREAM';`,
+			),
+		},
+		{
+			name: "selectBulkReplace",
+			SQLs: sqls(
+				"select _id, n, ids from upsi where _id = 1",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("n", fldTypeInt),
+				hdr("ids", fldTypeIDSet),
+			),
+			ExpRows: rows(
+				row(int64(1), int64(10), nil),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "conflictTargetNotID",
+			SQLs: sqls(
+				"insert into ups (_id, n) values ('a', 1) on conflict (n) do nothing",
+			),
+			ExpErr: "ON CONFLICT target must be the '_id' column",
+		},
+		{
+			name: "updateID",
+			SQLs: sqls(
+				"insert into ups (_id, n) values ('a', 1) on conflict do update set _id = 'z'",
+			),
+			ExpErr: "'_id' column cannot be updated by ON CONFLICT DO UPDATE",
+		},
+		{
+			name: "updateExistingReference",
+			SQLs: sqls(
+				"insert into ups (_id, n) values ('a', 1) on conflict do update set n = n + 1",
+			),
+			ExpErr: "column 'n' can only be referenced as 'excluded.n' in ON CONFLICT DO UPDATE",
+		},
+		{
+			name: "updateUnknownColumn",
+			SQLs: sqls(
+				"insert into ups (_id, n) values ('a', 1) on conflict do update set n = excluded.s",
+			),
+			ExpErr: "column 's' not found",
+		},
+		{
+			name: "updateWrongType",
+			SQLs: sqls(
+				"insert into ups (_id, n, s) values ('a', 1, 'x') on conflict do update set n = excluded.s",
+			),
+			ExpErr: "an expression of type 'string' cannot be assigned to type 'int'",
+		},
+		{
+			name: "replaceOnConflict",
+			SQLs: sqls(
+				"replace into ups (_id, n) values ('a', 1) on conflict do nothing",
+			),
+			ExpErr: "expected semicolon or EOF, found 'ON'",
+		},
+	},
+}
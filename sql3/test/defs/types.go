@@ -4,6 +4,7 @@ package defs
 import (
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -15,6 +16,15 @@ import (
 // fldType constants are providing a map of a defined test type to the
 // featurebase.WireQueryField.
 var (
+	fldTypeBitVector16 featurebase.WireQueryField = featurebase.WireQueryField{
+		Type:     dax.BaseTypeBitVector + "(16)",
+		BaseType: dax.BaseTypeBitVector,
+		TypeInfo: map[string]interface{}{"width": int64(16)},
+	}
+	fldTypeGeoPoint featurebase.WireQueryField = featurebase.WireQueryField{
+		Type:     dax.BaseTypeGeoPoint,
+		BaseType: dax.BaseTypeGeoPoint,
+	}
 	fldTypeID featurebase.WireQueryField = featurebase.WireQueryField{
 		Type:     dax.BaseTypeID,
 		BaseType: dax.BaseTypeID,
@@ -40,6 +50,14 @@ var (
 		BaseType: dax.BaseTypeDecimal,
 		TypeInfo: map[string]interface{}{"scale": int64(2)},
 	}
+	fldTypeDouble featurebase.WireQueryField = featurebase.WireQueryField{
+		Type:     dax.BaseTypeDouble,
+		BaseType: dax.BaseTypeDouble,
+	}
+	fldTypeJSON featurebase.WireQueryField = featurebase.WireQueryField{
+		Type:     dax.BaseTypeJSON,
+		BaseType: dax.BaseTypeJSON,
+	}
 	fldTypeString featurebase.WireQueryField = featurebase.WireQueryField{
 		Type:     dax.BaseTypeString,
 		BaseType: dax.BaseTypeString,
@@ -225,6 +243,13 @@ func (sr sourceRows) insertTuples(t *testing.T) string {
 				sb.WriteString(fmt.Sprintf("%d", v))
 			case float64:
 				sb.WriteString(fmt.Sprintf("%.2f", v))
+			case []float64:
+				// a geopoint, as a (latitude, longitude) tuple
+				strs := make([]string, len(v))
+				for i := range v {
+					strs[i] = strconv.FormatFloat(v[i], 'f', -1, 64)
+				}
+				sb.WriteString("{" + strings.Join(strs, ",") + "}")
 			case []int64:
 				if v == nil {
 					sb.WriteString("NULL")
@@ -0,0 +1,238 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package pilosa
+
+import (
+	"context"
+	"sort"
+
+	"github.com/featurebasedb/featurebase/v3/rbf"
+	txkey "github.com/featurebasedb/featurebase/v3/short_txkey"
+	"github.com/pkg/errors"
+)
+
+// TableStorageInfo describes the disk space used by an index on the local
+// node.
+type TableStorageInfo struct {
+	Index string
+	// the number of bytes used by all of the files under the index directory
+	DiskUsage int64
+	// the number of shards with an open RBF database
+	ShardN int64
+}
+
+// FragmentStorageInfo describes the physical storage used by a single
+// fragment (a view of a field in a shard) on the local node.
+type FragmentStorageInfo struct {
+	Index string
+	Field string
+	View  string
+	Shard uint64
+	// the number of rows with at least one bit set
+	RowN int64
+	// the number of bits set
+	BitN int64
+	// the number of roaring containers, in total and by type
+	ContainerN       int64
+	ArrayContainerN  int64
+	RunContainerN    int64
+	BitmapContainerN int64
+	// the number of bytes in the RBF pages holding the fragment
+	Size int64
+}
+
+// RBFFileInfo describes the RBF database holding a shard of an index on the
+// local node.
+type RBFFileInfo struct {
+	Index string
+	Shard uint64
+	Path  string
+	// the size of the data file and the write-ahead log, in bytes
+	Size    int64
+	WALSize int64
+	// the number of open transactions
+	TxN int64
+}
+
+// TranslateStoreInfo describes a key translation store on the local node.
+// Index stores have an empty Field, and field stores have a PartitionID of -1.
+type TranslateStoreInfo struct {
+	Index       string
+	Field       string
+	PartitionID int
+	Path        string
+	// the size of the store, in bytes
+	Size  int64
+	MaxID uint64
+}
+
+// rbfShardDBs returns the open RBF databases on the local node, ordered by
+// index and shard.
+func (api *API) rbfShardDBs() []*DBShard {
+	per := api.holder.Txf().dbPerShard
+	per.Mu.Lock()
+	dbs := make([]*DBShard, 0, len(per.Flatmap))
+	for _, dbShard := range per.Flatmap {
+		if _, ok := dbShard.W.(*RbfDBWrapper); ok {
+			dbs = append(dbs, dbShard)
+		}
+	}
+	per.Mu.Unlock()
+
+	sort.Slice(dbs, func(i, j int) bool {
+		if dbs[i].Index != dbs[j].Index {
+			return dbs[i].Index < dbs[j].Index
+		}
+		return dbs[i].Shard < dbs[j].Shard
+	})
+	return dbs
+}
+
+// TableStorage returns the disk usage of each index on the local node.
+func (api *API) TableStorage(ctx context.Context) ([]TableStorageInfo, error) {
+	shardN := make(map[string]int64)
+	for _, dbShard := range api.rbfShardDBs() {
+		shardN[dbShard.Index]++
+	}
+
+	var infos []TableStorageInfo
+	for _, idx := range api.holder.Indexes() {
+		usage, err := GetDiskUsage(idx.Path())
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting disk usage for index %s", idx.Name())
+		}
+		infos = append(infos, TableStorageInfo{
+			Index:     idx.Name(),
+			DiskUsage: usage.Usage,
+			ShardN:    shardN[idx.Name()],
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Index < infos[j].Index })
+	return infos, nil
+}
+
+// FragmentStorage returns storage statistics for every fragment on the local
+// node. Each RBF database is read in its own read-only transaction, so the
+// statistics for a shard are consistent, but different shards may be read at
+// slightly different points in time.
+func (api *API) FragmentStorage(ctx context.Context) ([]FragmentStorageInfo, error) {
+	var infos []FragmentStorageInfo
+	for _, dbShard := range api.rbfShardDBs() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		db := dbShard.W.(*RbfDBWrapper).db
+		stats, err := rbfBitmapStats(db)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading storage statistics for index %s shard %d", dbShard.Index, dbShard.Shard)
+		}
+		for _, s := range stats {
+			field, view := txkey.SplitPrefix([]byte(s.Name))
+			infos = append(infos, FragmentStorageInfo{
+				Index:            dbShard.Index,
+				Field:            field,
+				View:             view,
+				Shard:            dbShard.Shard,
+				RowN:             int64(s.RowN),
+				BitN:             int64(s.BitN),
+				ContainerN:       int64(s.ContainerN),
+				ArrayContainerN:  int64(s.ArrayN),
+				RunContainerN:    int64(s.RunN),
+				BitmapContainerN: int64(s.BitmapN),
+				Size:             int64(s.PageN) * rbf.PageSize,
+			})
+		}
+	}
+	return infos, nil
+}
+
+// rbfBitmapStats returns the statistics for each bitmap in db.
+func rbfBitmapStats(db *rbf.DB) ([]*rbf.BitmapStats, error) {
+	tx, err := db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	return tx.BitmapStats()
+}
+
+// RBFFiles returns the size of each RBF database on the local node.
+func (api *API) RBFFiles(ctx context.Context) ([]RBFFileInfo, error) {
+	var infos []RBFFileInfo
+	for _, dbShard := range api.rbfShardDBs() {
+		db := dbShard.W.(*RbfDBWrapper).db
+		size, err := db.Size()
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting size of %s", db.Path)
+		}
+		walSize := db.WALSize()
+		infos = append(infos, RBFFileInfo{
+			Index:   dbShard.Index,
+			Shard:   dbShard.Shard,
+			Path:    db.Path,
+			Size:    size - walSize,
+			WALSize: walSize,
+			TxN:     int64(db.TxN()),
+		})
+	}
+	return infos, nil
+}
+
+// TranslateStores returns the size of each key translation store on the local
+// node.
+func (api *API) TranslateStores(ctx context.Context) ([]TranslateStoreInfo, error) {
+	var infos []TranslateStoreInfo
+	for _, idx := range api.holder.Indexes() {
+		for partitionID := 0; partitionID < api.holder.partitionN; partitionID++ {
+			if store := idx.TranslateStore(partitionID); store != nil {
+				info, err := translateStoreInfo(store, idx.Name(), "")
+				if err != nil {
+					return nil, err
+				}
+				infos = append(infos, info)
+			}
+		}
+		for _, fld := range idx.Fields() {
+			if !fld.Keys() {
+				continue
+			}
+			if store := fld.TranslateStore(); store != nil {
+				info, err := translateStoreInfo(store, idx.Name(), fld.Name())
+				if err != nil {
+					return nil, err
+				}
+				infos = append(infos, info)
+			}
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Index != infos[j].Index {
+			return infos[i].Index < infos[j].Index
+		}
+		if infos[i].Field != infos[j].Field {
+			return infos[i].Field < infos[j].Field
+		}
+		return infos[i].PartitionID < infos[j].PartitionID
+	})
+	return infos, nil
+}
+
+func translateStoreInfo(store TranslateStore, index, field string) (TranslateStoreInfo, error) {
+	maxID, err := store.MaxID()
+	if err != nil {
+		return TranslateStoreInfo{}, errors.Wrapf(err, "getting max id of translate store for %s/%s", index, field)
+	}
+	info := TranslateStoreInfo{
+		Index:       index,
+		Field:       field,
+		PartitionID: store.PartitionID(),
+		MaxID:       maxID,
+	}
+	// only the bolt store knows where it lives and how big it is
+	if bs, ok := store.(*BoltTranslateStore); ok {
+		info.Path = bs.Path
+		info.Size = bs.Size()
+	}
+	return info, nil
+}
@@ -1,8 +1,16 @@
 package pilosa
 
 import (
+	"context"
+	"io"
 	"strings"
+	"sync"
 	"time"
+
+	fbcontext "github.com/featurebasedb/featurebase/v3/context"
+	pnet "github.com/featurebasedb/featurebase/v3/net"
+	planner_types "github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+	"github.com/pkg/errors"
 )
 
 // ExecutionRequest holds data about an (sql) execution request
@@ -44,7 +52,7 @@ type ExecutionRequest struct {
 // Copy returns a copy of the ExecutionRequest passed
 func (e *ExecutionRequest) Copy() ExecutionRequest {
 	var elapsedTime time.Duration
-	if !strings.EqualFold(e.Status, "complete") {
+	if !strings.EqualFold(e.Status, "complete") && !strings.EqualFold(e.Status, "expired") {
 		elapsedTime = time.Since(e.StartTime)
 	} else {
 		elapsedTime = e.EndTime.Sub(e.StartTime)
@@ -93,7 +101,307 @@ type ExecutionRequestsAPI interface {
 	GetRequest(requestID string) (ExecutionRequest, error)
 }
 
+// Cursor holds a partially read (sql) result set so that it can be fetched a
+// page at a time. A cursor belongs to the execution request that produced it,
+// and that request stays 'running' until the cursor is exhausted or closed.
+type Cursor struct {
+	mu sync.Mutex
+
+	// the id of the cursor
+	ID string
+	// the id of the request the cursor is reading from
+	RequestID string
+	// the id of the user
+	UserID string
+	// the sql for the cursor
+	SQL string
+	// the schema of the rows returned by the cursor
+	Schema planner_types.Schema
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	iter   planner_types.RowIterator
+
+	// a cursor open on another node is read from that node, rather than
+	// from iter
+	remote *remoteCursor
+
+	// the next row, read ahead so we know when the cursor is exhausted
+	next     planner_types.Row
+	done     bool
+	rowCount int64
+	lastUsed time.Time
+}
+
+// NewCursor returns a cursor named id over the rows of the iterator returned
+// by open. The iterator is opened with a context which is detached from ctx,
+// so that the cursor can be read from by later requests.
+func NewCursor(ctx context.Context, id string, sql string, schema planner_types.Schema, open func(ctx context.Context) (planner_types.RowIterator, error)) (*Cursor, error) {
+	requestID, _ := fbcontext.RequestID(ctx)
+	userID, _ := fbcontext.UserID(ctx)
+
+	cctx, cancel := context.WithCancel(fbcontext.Detach(ctx))
+	iter, err := open(cctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &Cursor{
+		ID:        id,
+		RequestID: requestID,
+		UserID:    userID,
+		SQL:       sql,
+		Schema:    schema,
+		ctx:       cctx,
+		cancel:    cancel,
+		iter:      iter,
+		lastUsed:  time.Now(),
+	}, nil
+}
+
+// newCursorID returns the id of the cursor holding the rest of the result set
+// of the request requestID.
+func newCursorID(requestID string) string {
+	return "cursor_" + strings.ReplaceAll(requestID, "-", "")
+}
+
+// Fetch returns up to n rows from the cursor, and whether the cursor has been
+// exhausted.
+func (c *Cursor) Fetch(n int) (planner_types.Rows, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastUsed = time.Now()
+
+	if c.remote != nil {
+		rows, done, err := c.remote.client.FetchCursor(c.remote.ctx, c.remote.uri, c.ID, n)
+		if err != nil {
+			return nil, true, err
+		}
+		c.done = done
+		c.rowCount += int64(len(rows))
+		return rows, done, nil
+	}
+
+	rows := make(planner_types.Rows, 0)
+	for len(rows) < n || (c.next == nil && !c.done) {
+		if c.next != nil {
+			rows = append(rows, c.next)
+			c.next = nil
+			continue
+		}
+		if c.done {
+			break
+		}
+		row, err := c.iter.Next(c.ctx)
+		if err == planner_types.ErrNoMoreRows {
+			c.done = true
+			c.cancel()
+			continue
+		}
+		if err != nil {
+			c.done = true
+			c.cancel()
+			return nil, true, err
+		}
+		c.next = row
+	}
+	c.rowCount += int64(len(rows))
+	return rows, c.done, nil
+}
+
+// Page returns an iterator over the next n rows of the cursor.
+func (c *Cursor) Page(n int) planner_types.RowIterator {
+	return &cursorPageIterator{cursor: c, n: n}
+}
+
+// Done returns true if the cursor has been exhausted.
+func (c *Cursor) Done() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.done
+}
+
+// Close releases the resources held by the cursor. Closing a cursor open on
+// another node only releases this node's handle on it.
+func (c *Cursor) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done = true
+	c.next = nil
+	if c.remote != nil {
+		return
+	}
+	c.cancel()
+	if closer, ok := c.iter.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+// Info returns a summary of the state of the cursor.
+func (c *Cursor) Info() CursorInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CursorInfo{
+		ID:        c.ID,
+		RequestID: c.RequestID,
+		UserID:    c.UserID,
+		RowCount:  c.rowCount,
+		LastUsed:  c.lastUsed,
+		IdleTime:  time.Since(c.lastUsed),
+	}
+}
+
+type cursorPageIterator struct {
+	cursor *Cursor
+	n      int
+	rows   planner_types.Rows
+}
+
+func (i *cursorPageIterator) Next(ctx context.Context) (planner_types.Row, error) {
+	if i.rows == nil {
+		rows, _, err := i.cursor.Fetch(i.n)
+		if err != nil {
+			return nil, err
+		}
+		i.rows = rows
+	}
+	if len(i.rows) == 0 {
+		return nil, planner_types.ErrNoMoreRows
+	}
+	row := i.rows[0]
+	i.rows = i.rows[1:]
+	return row, nil
+}
+
+// CursorInfo holds data about an open (sql) cursor
+type CursorInfo struct {
+	// the id of the cursor
+	ID string
+	// the id of the request the cursor is reading from
+	RequestID string
+	// the id of the user
+	UserID string
+	// the number of rows fetched from the cursor so far
+	RowCount int64
+	// time the cursor was last fetched from
+	LastUsed time.Time
+	// the time since the cursor was last fetched from
+	IdleTime time.Duration
+}
+
+// CursorsAPI defines the API for keeping track of open (sql) cursors. Cursors
+// belong to the user who opened them, so the same cursor id used by two users
+// refers to two different cursors, and a user can't read or close another's.
+type CursorsAPI interface {
+	// add a cursor, it is an error if a cursor with the same id is open for
+	// the same user
+	AddCursor(cursor *Cursor) error
+
+	// get an open cursor of the user
+	GetCursor(ctx context.Context, userID string, cursorID string) (*Cursor, error)
+
+	// close a cursor of the user and mark its request complete
+	CloseCursor(ctx context.Context, userID string, cursorID string) error
+
+	// close all the cursors which have not been used for the idle duration
+	// and mark their requests expired, returning the ids of those cursors
+	ExpireCursors(idle time.Duration) []string
+
+	// list the open cursors
+	ListCursors() []CursorInfo
+}
+
 // SystemLayerAPI defines an api to allow access to internal FeatureBase state
 type SystemLayerAPI interface {
 	ExecutionRequests() ExecutionRequestsAPI
+	Cursors() CursorsAPI
+}
+
+// remoteCursor is where a cursor open on another node is read from.
+type remoteCursor struct {
+	ctx    context.Context
+	client *InternalClient
+	uri    *pnet.URI
+}
+
+// clusterCursors are the cursors open on any node of the cluster. Cursors
+// stay on the node they were opened on, but one which isn't open on this
+// node is looked for on the others, so that it can be fetched from and
+// closed through whichever node a request is sent to.
+type clusterCursors struct {
+	// the cursors open on this node
+	CursorsAPI
+
+	api *API
+}
+
+// GetCursor returns an open cursor of the user. A cursor open on another node
+// is read from that node.
+func (c *clusterCursors) GetCursor(ctx context.Context, userID string, cursorID string) (*Cursor, error) {
+	cursor, err := c.CursorsAPI.GetCursor(ctx, userID, cursorID)
+	if err == nil {
+		return cursor, nil
+	}
+	client := c.api.server.defaultClient
+	for _, node := range c.api.cluster.Nodes() {
+		if node.ID == c.api.NodeID() {
+			continue
+		}
+		uri := node.URI
+		schema, rerr := client.CursorSchema(ctx, &uri, cursorID)
+		if errors.Is(rerr, ErrCursorNotFound) {
+			continue
+		} else if rerr != nil {
+			err = errors.Wrapf(rerr, "looking for cursor %s on node %s", cursorID, node.ID)
+			continue
+		}
+		return &Cursor{
+			ID:       cursorID,
+			UserID:   userID,
+			Schema:   schema,
+			lastUsed: time.Now(),
+			remote: &remoteCursor{
+				ctx:    ctx,
+				client: client,
+				uri:    &uri,
+			},
+		}, nil
+	}
+	return nil, err
+}
+
+// CloseCursor closes a cursor of the user, on whichever node it is open.
+func (c *clusterCursors) CloseCursor(ctx context.Context, userID string, cursorID string) error {
+	err := c.CursorsAPI.CloseCursor(ctx, userID, cursorID)
+	if err == nil {
+		return nil
+	}
+	client := c.api.server.defaultClient
+	for _, node := range c.api.cluster.Nodes() {
+		if node.ID == c.api.NodeID() {
+			continue
+		}
+		rerr := client.CloseCursor(ctx, &node.URI, cursorID)
+		if errors.Is(rerr, ErrCursorNotFound) {
+			continue
+		} else if rerr != nil {
+			err = errors.Wrapf(rerr, "closing cursor %s on node %s", cursorID, node.ID)
+			continue
+		}
+		return nil
+	}
+	return err
+}
+
+// clusterSystemLayer is a SystemLayerAPI whose cursors are those open on any
+// node of the cluster.
+type clusterSystemLayer struct {
+	SystemLayerAPI
+
+	cursors CursorsAPI
+}
+
+func (l *clusterSystemLayer) Cursors() CursorsAPI {
+	return l.cursors
 }
@@ -0,0 +1,132 @@
+package systemlayer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+)
+
+// Cursors is an internal struct that keeps the open sql cursors. Each cursor is
+// also tracked as an execution request, so that open cursors are visible
+// alongside the requests that are running.
+type Cursors struct {
+	sync.RWMutex
+	requests pilosa.ExecutionRequestsAPI
+	cursors  map[cursorKey]*pilosa.Cursor
+}
+
+// cursorKey identifies a cursor; cursor ids are case insensitive, and are
+// only unique per user.
+type cursorKey struct {
+	userID string
+	id     string
+}
+
+func newCursorKey(userID string, cursorID string) cursorKey {
+	return cursorKey{userID: userID, id: strings.ToLower(cursorID)}
+}
+
+// Ensure type implements interface.
+var _ pilosa.CursorsAPI = (*Cursors)(nil)
+
+func NewCursorsAPI(requests pilosa.ExecutionRequestsAPI) *Cursors {
+	return &Cursors{
+		requests: requests,
+		cursors:  make(map[cursorKey]*pilosa.Cursor),
+	}
+}
+
+// AddCursor adds a new cursor to the Cursors struct
+func (c *Cursors) AddCursor(cursor *pilosa.Cursor) error {
+	c.Lock()
+	defer c.Unlock()
+
+	key := newCursorKey(cursor.UserID, cursor.ID)
+	if _, ok := c.cursors[key]; ok {
+		return fmt.Errorf("cursor %s already exists", cursor.ID)
+	}
+	c.cursors[key] = cursor
+
+	// the request may already be tracked if rows have been read from the
+	// cursor, in which case this is a no-op
+	_ = c.requests.AddRequest(cursor.RequestID, cursor.UserID, time.Now(), cursor.SQL)
+	return nil
+}
+
+// GetCursor returns an open cursor of the user
+func (c *Cursors) GetCursor(ctx context.Context, userID string, cursorID string) (*pilosa.Cursor, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	cursor, ok := c.cursors[newCursorKey(userID, cursorID)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", pilosa.ErrCursorNotFound, cursorID)
+	}
+	return cursor, nil
+}
+
+// CloseCursor closes a cursor of the user and removes it from the Cursors
+// struct
+func (c *Cursors) CloseCursor(ctx context.Context, userID string, cursorID string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	key := newCursorKey(userID, cursorID)
+	cursor, ok := c.cursors[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", pilosa.ErrCursorNotFound, cursorID)
+	}
+	c.closeCursor(key, cursor, "complete")
+	return nil
+}
+
+// ExpireCursors closes all the cursors that have been idle for longer than idle
+func (c *Cursors) ExpireCursors(idle time.Duration) []string {
+	c.Lock()
+	defer c.Unlock()
+
+	expired := make([]string, 0)
+	for key, cursor := range c.cursors {
+		if cursor.Info().IdleTime < idle {
+			continue
+		}
+		c.closeCursor(key, cursor, "expired")
+		expired = append(expired, cursor.ID)
+	}
+	sort.Strings(expired)
+	return expired
+}
+
+// closeCursor closes cursor and, if it had not been read to the end, updates
+// its request with status. Callers must hold the lock.
+func (c *Cursors) closeCursor(key cursorKey, cursor *pilosa.Cursor, status string) {
+	if !cursor.Done() {
+		info := cursor.Info()
+		_ = c.requests.UpdateRequest(info.RequestID, time.Now(), status, "", 0, "", 0, 0, 0, 0, info.RowCount, "")
+	}
+	cursor.Close()
+	delete(c.cursors, key)
+}
+
+// ListCursors returns information about the open cursors
+func (c *Cursors) ListCursors() []pilosa.CursorInfo {
+	c.RLock()
+	defer c.RUnlock()
+
+	result := make([]pilosa.CursorInfo, 0, len(c.cursors))
+	for _, cursor := range c.cursors {
+		result = append(result, cursor.Info())
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ID != result[j].ID {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].UserID < result[j].UserID
+	})
+	return result
+}
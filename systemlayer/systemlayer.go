@@ -7,14 +7,21 @@ import pilosa "github.com/featurebasedb/featurebase/v3"
 // internal state (Buffer Pool?)
 type SystemLayer struct {
 	executionRequests pilosa.ExecutionRequestsAPI
+	cursors           pilosa.CursorsAPI
 }
 
 func NewSystemLayer() *SystemLayer {
+	executionRequests := NewExecutionRequestsAPI()
 	return &SystemLayer{
-		executionRequests: NewExecutionRequestsAPI(),
+		executionRequests: executionRequests,
+		cursors:           NewCursorsAPI(executionRequests),
 	}
 }
 
 func (e *SystemLayer) ExecutionRequests() pilosa.ExecutionRequestsAPI {
 	return e.executionRequests
 }
+
+func (e *SystemLayer) Cursors() pilosa.CursorsAPI {
+	return e.cursors
+}
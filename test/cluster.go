@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"reflect"
 	"sort"
 	"strings"
 	"sync"
@@ -458,7 +459,7 @@ func (c *ShareableCluster) CreateField(t testing.TB, index string, iopts pilosa.
 			t.Fatalf("getting index: %v", err)
 		}
 	}
-	if idx.Options() != iopts {
+	if !reflect.DeepEqual(idx.Options(), iopts) {
 		t.Logf("existing index options:\n%v\ndon't match given opts:\n%v\n in pilosa/test.Cluster.CreateField", idx.Options(), iopts)
 	}
 
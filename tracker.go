@@ -17,13 +17,17 @@ type ActiveQueryStatus struct {
 }
 
 type PastQueryStatus struct {
-	PQL       string        `json:"PQL"`
-	SQL       string        `json:"SQL,omitempty"`
-	Node      string        `json:"nodeID"`
-	Index     string        `json:"index"`
-	Start     time.Time     `json:"start"`
-	Runtime   time.Duration `json:"runtime"` // deprecated
-	RuntimeNs time.Duration `json:"runtimeNanoseconds"`
+	PQL           string        `json:"PQL"`
+	SQL           string        `json:"SQL,omitempty"`
+	Node          string        `json:"nodeID"`
+	Index         string        `json:"index"`
+	User          string        `json:"user,omitempty"`
+	Start         time.Time     `json:"start"`
+	Runtime       time.Duration `json:"runtime"` // deprecated
+	RuntimeNs     time.Duration `json:"runtimeNanoseconds"`
+	RowCount      int64         `json:"rowCount"`
+	ShardsScanned int64         `json:"shardsScanned"`
+	Error         string        `json:"error,omitempty"`
 }
 
 type activeQuery struct {
@@ -31,35 +35,24 @@ type activeQuery struct {
 	SQL     string
 	node    string
 	index   string
+	user    string
 	started time.Time
 }
 
-type pastQuery struct {
-	PQL     string
-	SQL     string
-	node    string
-	index   string
-	started time.Time
-	runtime time.Duration
-}
-
-type queryStatusUpdate struct {
-	q       *activeQuery
-	end     bool
-	endTime time.Time
-}
-
+// queryTracker keeps the queries running on a node, and adds them to the
+// query history once they finish. Starting and finishing a query only takes
+// a lock briefly; the history is written to disk in the background.
 type queryTracker struct {
-	updates chan<- queryStatusUpdate
-	checks  chan<- chan<- []*activeQuery
-	history *ringBuffer
-
-	wg   sync.WaitGroup
-	stop chan struct{}
+	mu      sync.Mutex
+	active  map[*activeQuery]struct{}
+	history *queryHistory
 }
 
+// Ensure type implements interface.
+var _ QueryHistoryAPI = (*queryTracker)(nil)
+
 type ringBuffer struct {
-	queries []pastQuery
+	queries []QueryHistoryEntry
 	start   int
 	count   int
 	mu      sync.Mutex
@@ -68,14 +61,14 @@ type ringBuffer struct {
 // newRingBuffer initializes an empty RingBuffer of specified capacity.
 func newRingBuffer(n int) *ringBuffer {
 	return &ringBuffer{
-		queries: make([]pastQuery, n),
+		queries: make([]QueryHistoryEntry, n),
 		start:   0,
 		count:   0,
 	}
 }
 
 // add adds a new element to the queue, overwriting the oldest if it is already full.
-func (b *ringBuffer) add(q pastQuery) {
+func (b *ringBuffer) add(q QueryHistoryEntry) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -89,70 +82,68 @@ func (b *ringBuffer) add(q pastQuery) {
 }
 
 // slice returns the contents of the RingBuffer, in insertion order.
-func (b *ringBuffer) slice() []pastQuery {
+func (b *ringBuffer) slice() []QueryHistoryEntry {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	return append(b.queries[b.start:b.count], b.queries[0:b.start]...)
 }
 
-func newQueryTracker(historyLength int) *queryTracker {
-	done := make(chan struct{})
-	updates := make(chan queryStatusUpdate, 128)
-	checks := make(chan chan<- []*activeQuery)
-	history := newRingBuffer(historyLength)
-	tracker := &queryTracker{
-		updates: updates,
-		checks:  checks,
+func newQueryTracker(history *queryHistory) *queryTracker {
+	return &queryTracker{
+		active:  make(map[*activeQuery]struct{}),
 		history: history,
-		stop:    done,
 	}
-	tracker.wg.Add(1)
-	go func() {
-		defer tracker.wg.Done()
-
-		activeQueries := make(map[*activeQuery]struct{})
-
-		for {
-			select {
-			case update := <-updates:
-				if update.end {
-					pq := pastQuery{update.q.PQL, update.q.SQL, update.q.node, update.q.index, update.q.started, update.endTime.Sub(update.q.started)}
-					tracker.history.add(pq)
-					delete(activeQueries, update.q)
-				} else {
-					activeQueries[update.q] = struct{}{}
-				}
-			case check := <-checks:
-				out := make([]*activeQuery, len(activeQueries))
-				i := 0
-				for q := range activeQueries {
-					out[i] = q
-					i++
-				}
-				check <- out
-				close(check)
-			case <-done:
-				return
-			}
-		}
-	}()
-	return tracker
 }
 
-func (t *queryTracker) Start(pql, sql, nodeID, index string, start time.Time) *activeQuery {
-	q := &activeQuery{pql, sql, nodeID, index, start}
-	t.updates <- queryStatusUpdate{q, false, time.Time{}}
+func (t *queryTracker) Start(pql, sql, nodeID, index, user string, start time.Time) *activeQuery {
+	q := &activeQuery{pql, sql, nodeID, index, user, start}
+	t.mu.Lock()
+	t.active[q] = struct{}{}
+	t.mu.Unlock()
 	return q
 }
 
-func (t *queryTracker) Finish(q *activeQuery) {
-	t.updates <- queryStatusUpdate{q, true, time.Now()}
+// Finish marks q as finished, and adds it to the query history.
+func (t *queryTracker) Finish(q *activeQuery, rowCount, shardsScanned int64, err error) {
+	t.mu.Lock()
+	delete(t.active, q)
+	t.mu.Unlock()
+
+	entry := QueryHistoryEntry{
+		NodeID:        q.node,
+		Index:         q.index,
+		User:          q.user,
+		PQL:           q.PQL,
+		SQL:           q.SQL,
+		Start:         q.started,
+		End:           time.Now(),
+		RowCount:      rowCount,
+		ShardsScanned: shardsScanned,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	t.history.add(entry)
+}
+
+// AddQuery adds a query which was not started with Start to the query
+// history.
+func (t *queryTracker) AddQuery(entry QueryHistoryEntry) {
+	t.history.add(entry)
+}
+
+// ListQueries returns the query history, oldest query first.
+func (t *queryTracker) ListQueries() ([]QueryHistoryEntry, error) {
+	return t.history.list()
 }
 
 func (t *queryTracker) ActiveQueries() []ActiveQueryStatus {
-	ch := make(chan []*activeQuery, 1)
-	t.checks <- ch
-	queries := <-ch
+	t.mu.Lock()
+	queries := make([]*activeQuery, 0, len(t.active))
+	for q := range t.active {
+		queries = append(queries, q)
+	}
+	t.mu.Unlock()
 	sort.Slice(queries, func(i, j int) bool {
 		switch {
 		case queries[i].started.Before(queries[j].started):
@@ -175,17 +166,15 @@ func (t *queryTracker) ActiveQueries() []ActiveQueryStatus {
 	return out
 }
 
-func (t *queryTracker) PastQueries() []PastQueryStatus {
-	queries := t.history.slice()
+func (t *queryTracker) PastQueries() ([]PastQueryStatus, error) {
+	queries, err := t.history.list()
+	if err != nil {
+		return nil, err
+	}
 	out := make([]PastQueryStatus, len(queries))
 	for i, v := range queries {
-		out[i] = PastQueryStatus{v.PQL, v.SQL, v.node, v.index, v.started, v.runtime, v.runtime}
+		runtime := v.End.Sub(v.Start)
+		out[i] = PastQueryStatus{v.PQL, v.SQL, v.NodeID, v.Index, v.User, v.Start, runtime, runtime, v.RowCount, v.ShardsScanned, v.Error}
 	}
-	return out
-
-}
-
-func (t *queryTracker) Stop() {
-	close(t.stop)
-	t.wg.Wait()
+	return out, nil
 }
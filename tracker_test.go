@@ -4,6 +4,8 @@ package pilosa
 
 import (
 	"fmt"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -39,19 +41,18 @@ func TestRingBuffer(t *testing.T) {
 				t.Fatalf("test[%d], buffer[%d] expected querystring '%s', found '%s'", k, n, tests[k].queries[n], q.PQL)
 			}
 		}
-		buffer.add(pastQuery{PQL: fmt.Sprintf("%d", k)})
+		buffer.add(QueryHistoryEntry{PQL: fmt.Sprintf("%d", k)})
 	}
 }
 
 func TestQueryTracker(t *testing.T) {
-	tracker := newQueryTracker(5)
-	defer tracker.Stop()
+	tracker := newQueryTracker(newQueryHistory(5))
 
 	if queries := tracker.ActiveQueries(); len(queries) > 0 {
 		t.Fatalf("expected no active queries; found %v", queries)
 	}
 
-	qs := tracker.Start("test query", "test SQL", "node0", "i", time.Now())
+	qs := tracker.Start("test query", "test SQL", "node0", "i", "", time.Now())
 
 	var queries []ActiveQueryStatus
 	for len(queries) < 1 {
@@ -61,9 +62,71 @@ func TestQueryTracker(t *testing.T) {
 		t.Fatalf("unexpected queries: %v", queries)
 	}
 
-	tracker.Finish(qs)
+	tracker.Finish(qs, 3, 2, nil)
 
 	for len(queries) > 0 {
 		queries = tracker.ActiveQueries()
 	}
+
+	var past []PastQueryStatus
+	for len(past) < 1 {
+		var err error
+		if past, err = tracker.PastQueries(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if past[0].PQL != "test query" || past[0].RowCount != 3 || past[0].ShardsScanned != 2 {
+		t.Fatalf("unexpected past queries: %v", past)
+	}
+}
+
+func TestQueryHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), queryHistoryFile)
+
+	listPQL := func(h *queryHistory) []string {
+		t.Helper()
+		queries, err := h.list()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pql := make([]string, len(queries))
+		for i, q := range queries {
+			pql[i] = q.PQL
+		}
+		return pql
+	}
+	mustAdd := func(h *queryHistory, pql string) {
+		t.Helper()
+		h.add(QueryHistoryEntry{PQL: pql})
+	}
+
+	// queries added before the history is opened are kept
+	h := newQueryHistory(3)
+	mustAdd(h, "0")
+	if err := h.open(path); err != nil {
+		t.Fatal(err)
+	}
+	for _, pql := range []string{"1", "2", "3"} {
+		mustAdd(h, pql)
+	}
+	if got, exp := listPQL(h), []string{"1", "2", "3"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+	if err := h.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the history survives being reopened, and is pruned if it has shrunk
+	h = newQueryHistory(2)
+	if err := h.open(path); err != nil {
+		t.Fatal(err)
+	}
+	defer h.close()
+	if got, exp := listPQL(h), []string{"2", "3"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+	mustAdd(h, "4")
+	if got, exp := listPQL(h), []string{"3", "4"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
 }
@@ -242,3 +242,94 @@ func TestAPI_ImportAtomicRecord(t *testing.T) {
 		t.Fatal("IRA bit should have been cleared")
 	}
 }
+
+func TestAPI_ImportAtomicRecordConflict(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	api := c.GetNode(0).API
+	ctx := context.Background()
+	index := c.Idx()
+
+	if _, err := api.CreateIndex(ctx, index, pilosa.IndexOptions{TrackExistence: true}); err != nil {
+		t.Fatalf("creating index: %v", err)
+	}
+	if _, err := api.CreateField(ctx, index, "val", pilosa.OptFieldTypeInt(-1000, 1000)); err != nil {
+		t.Fatalf("creating field: %v", err)
+	}
+	for _, name := range []string{"tags", "other"} {
+		if _, err := api.CreateField(ctx, index, name); err != nil {
+			t.Fatalf("creating field: %v", err)
+		}
+	}
+
+	record := func(col uint64, val int64, tag uint64) *pilosa.AtomicRecord {
+		return &pilosa.AtomicRecord{
+			Index: index,
+			Ivr: []*pilosa.ImportValueRequest{
+				{Index: index, Field: "val", ColumnIDs: []uint64{col}, Values: []int64{val}},
+			},
+			Ir: []*pilosa.ImportRequest{
+				{Index: index, Field: "tags", ColumnIDs: []uint64{col}, RowIDs: []uint64{tag}},
+			},
+		}
+	}
+	importRecord := func(ar *pilosa.AtomicRecord, conflict pilosa.ImportConflict) {
+		t.Helper()
+		qcx := api.Txf().NewQcx()
+		if err := api.ImportAtomicRecord(ctx, qcx, ar, pilosa.OptImportOptionsConflict(conflict)); err != nil {
+			qcx.Abort()
+			t.Fatalf("importing record: %v", err)
+		}
+		if err := qcx.Finish(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectColumns := func(query string, exp ...uint64) {
+		t.Helper()
+		res, err := api.Query(ctx, &pilosa.QueryRequest{Index: index, Query: query})
+		if err != nil {
+			t.Fatalf("querying %s: %v", query, err)
+		}
+		if got := res.Results[0].(*pilosa.Row).Columns(); fmt.Sprint(got) != fmt.Sprint(exp) {
+			t.Fatalf("%s: expected %v, got %v", query, exp, got)
+		}
+	}
+
+	// record 5 starts out with val=1, tags=1 and other=7
+	first := record(5, 1, 1)
+	first.Ir = append(first.Ir, &pilosa.ImportRequest{Index: index, Field: "other", ColumnIDs: []uint64{5}, RowIDs: []uint64{7}})
+	importRecord(first, pilosa.ImportConflictMerge)
+
+	// nothing leaves record 5 alone, but imports record 6
+	ar := record(5, 2, 2)
+	ar.Ivr[0].ColumnIDs, ar.Ivr[0].Values = []uint64{5, 6}, []int64{2, 3}
+	ar.Ir[0].ColumnIDs, ar.Ir[0].RowIDs = []uint64{5, 6}, []uint64{2, 3}
+	importRecord(ar, pilosa.ImportConflictDoNothing)
+	expectColumns("Row(val == 1)", 5)
+	expectColumns("Row(val == 3)", 6)
+	expectColumns("Row(tags=2)")
+	expectColumns("Row(tags=3)", 6)
+
+	// update replaces the values of the fields being imported
+	importRecord(record(5, 4, 4), pilosa.ImportConflictUpdate)
+	expectColumns("Row(val == 4)", 5)
+	expectColumns("Row(tags=1)")
+	expectColumns("Row(tags=4)", 5)
+	expectColumns("Row(other=7)", 5)
+
+	// replace clears the whole record first
+	ar = record(5, 0, 5)
+	ar.Ivr = nil
+	importRecord(ar, pilosa.ImportConflictReplace)
+	expectColumns("Row(val != null)", 6)
+	expectColumns("Row(tags=4)")
+	expectColumns("Row(tags=5)", 5)
+	expectColumns("Row(other=7)")
+	expectColumns("All()", 5, 6)
+
+	// merge, the default, accumulates set values
+	importRecord(record(5, 8, 6), pilosa.ImportConflictMerge)
+	expectColumns("Row(tags=5)", 5)
+	expectColumns("Row(tags=6)", 5)
+}
@@ -479,6 +479,10 @@ func (f *TxFactory) DeleteFieldFromStore(index, field, fieldPath string) (err er
 	return f.dbPerShard.DeleteFieldFromStore(index, field, fieldPath)
 }
 
+func (f *TxFactory) RenameFieldInStore(index, field, newName string) (err error) {
+	return f.dbPerShard.RenameFieldInStore(index, field, newName)
+}
+
 func (f *TxFactory) DeleteFragmentFromStore(
 	index, field, view string, shard uint64, frag *fragment,
 ) (err error) {
@@ -32,6 +32,20 @@ const (
 	viewExistence = "existence"
 )
 
+// renamedView returns the name view takes when its field is renamed from
+// field to newName. Only BSI views carry the field's name.
+func renamedView(view, field, newName string) string {
+	prefix := viewBSIGroupPrefix + field
+	if !strings.HasPrefix(view, prefix) {
+		return view
+	}
+	suffix := view[len(prefix):]
+	if suffix != "" && suffix[0] != '_' {
+		return view
+	}
+	return viewBSIGroupPrefix + newName + suffix
+}
+
 // view represents a container for field data.
 type view struct {
 	mu            sync.RWMutex
@@ -155,6 +155,32 @@ func (s *WireQueryResponse) UnmarshalJSONTyped(in []byte, typed bool) error {
 					s.Data[i][j] = dec
 				}
 
+			case dax.BaseTypeDouble:
+				if jn, ok := s.Data[i][j].(json.Number); ok {
+					f, err := jn.Float64()
+					if err != nil {
+						return errors.Wrap(err, "parsing double")
+					}
+					s.Data[i][j] = f
+				}
+
+			case dax.BaseTypeGeoPoint:
+				if src, ok := s.Data[i][j].([]interface{}); ok {
+					val := make([]float64, len(src))
+					for k := range src {
+						v, ok := src[k].(json.Number)
+						if !ok {
+							return errors.Errorf("unexpected geopoint member type %T", src[k])
+						}
+						f, err := v.Float64()
+						if err != nil {
+							return errors.Wrap(err, "parsing geopoint")
+						}
+						val[k] = f
+					}
+					s.Data[i][j] = val
+				}
+
 			case dax.BaseTypeStringSet:
 				if src, ok := s.Data[i][j].([]interface{}); ok {
 					if typed {
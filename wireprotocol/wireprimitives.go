@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"io"
+	"math"
 	"time"
 
 	"github.com/featurebasedb/featurebase/v3/errors"
@@ -36,6 +37,10 @@ const (
 	TYPE_IDSET     int8 = 0x06
 	TYPE_STRING    int8 = 0x07
 	TYPE_STRINGSET int8 = 0x08
+	TYPE_DOUBLE    int8 = 0x09
+	TYPE_JSON      int8 = 0x0A
+	TYPE_GEOPOINT  int8 = 0x0B
+	TYPE_BITVECTOR int8 = 0x0C
 )
 
 func ExpectToken(reader io.Reader, token int16) (int16, error) {
@@ -97,6 +102,9 @@ func WriteSchema(schema types.Schema) ([]byte, error) {
 			writeInt8(writer, TYPE_DECIMAL)
 			writeInt8(writer, int8(ty.Scale))
 
+		case *parser.DataTypeDouble:
+			writeInt8(writer, TYPE_DOUBLE)
+
 		case *parser.DataTypeTimestamp:
 			writeInt8(writer, TYPE_TIMESTAMP)
 
@@ -109,6 +117,16 @@ func WriteSchema(schema types.Schema) ([]byte, error) {
 		case *parser.DataTypeStringSet:
 			writeInt8(writer, TYPE_STRINGSET)
 
+		case *parser.DataTypeJSON:
+			writeInt8(writer, TYPE_JSON)
+
+		case *parser.DataTypeGeoPoint:
+			writeInt8(writer, TYPE_GEOPOINT)
+
+		case *parser.DataTypeBitVector:
+			writeInt8(writer, TYPE_BITVECTOR)
+			writeInt16(writer, int16(ty.Width))
+
 		default:
 			return []byte{}, errors.Errorf("unexpected type '%T'", s.Type)
 		}
@@ -168,6 +186,9 @@ func ReadSchema(reader io.Reader) (types.Schema, error) {
 			}
 			dataType = parser.NewDataTypeDecimal(int64(scale))
 
+		case TYPE_DOUBLE:
+			dataType = parser.NewDataTypeDouble()
+
 		case TYPE_TIMESTAMP:
 			dataType = parser.NewDataTypeTimestamp()
 
@@ -179,6 +200,20 @@ func ReadSchema(reader io.Reader) (types.Schema, error) {
 
 		case TYPE_STRINGSET:
 			dataType = parser.NewDataTypeStringSet()
+
+		case TYPE_JSON:
+			dataType = parser.NewDataTypeJSON()
+
+		case TYPE_GEOPOINT:
+			dataType = parser.NewDataTypeGeoPoint()
+
+		case TYPE_BITVECTOR:
+			var width int16
+			err = binary.Read(reader, binary.BigEndian, &width)
+			if err != nil {
+				return nil, err
+			}
+			dataType = parser.NewDataTypeBitVector(int64(width))
 		}
 
 		schema = append(schema, &types.PlannerColumn{
@@ -269,6 +304,31 @@ func WriteRow(row types.Row, schema types.Schema) ([]byte, error) {
 				writeInt64(writer, v.ToInt64(v.Scale))
 			}
 
+		case *parser.DataTypeDouble:
+			if val == nil {
+				writeInt8(writer, 0)
+			} else {
+				writeInt8(writer, 8)
+				v, ok := row[i].(float64)
+				if !ok {
+					return []byte{}, errors.Errorf("unexpected type '%T'", row[i])
+				}
+				writeInt64(writer, int64(math.Float64bits(v)))
+			}
+
+		case *parser.DataTypeGeoPoint:
+			if val == nil {
+				writeInt8(writer, 0)
+			} else {
+				writeInt8(writer, 16)
+				v, ok := row[i].([]float64)
+				if !ok || len(v) != 2 {
+					return []byte{}, errors.Errorf("unexpected type '%T'", row[i])
+				}
+				writeInt64(writer, int64(math.Float64bits(v[0])))
+				writeInt64(writer, int64(math.Float64bits(v[1])))
+			}
+
 		case *parser.DataTypeBool:
 			if val == nil {
 				writeInt8(writer, 0)
@@ -311,7 +371,7 @@ func WriteRow(row types.Row, schema types.Schema) ([]byte, error) {
 				}
 			}
 
-		case *parser.DataTypeString:
+		case *parser.DataTypeString, *parser.DataTypeJSON, *parser.DataTypeBitVector:
 			if val == nil {
 				writeInt16(writer, 0)
 			} else {
@@ -385,6 +445,40 @@ func ReadRow(reader io.Reader, schema types.Schema) (types.Row, error) {
 				row[idx] = pql.NewDecimal(value, t.Scale)
 			}
 
+		case *parser.DataTypeDouble:
+			var len int8
+			err := binary.Read(reader, binary.BigEndian, &len)
+			if err != nil {
+				return nil, err
+			}
+			if len == 0 {
+				row[idx] = nil
+			} else {
+				var value uint64
+				err := binary.Read(reader, binary.BigEndian, &value)
+				if err != nil {
+					return nil, err
+				}
+				row[idx] = math.Float64frombits(value)
+			}
+
+		case *parser.DataTypeGeoPoint:
+			var len int8
+			err := binary.Read(reader, binary.BigEndian, &len)
+			if err != nil {
+				return nil, err
+			}
+			if len == 0 {
+				row[idx] = nil
+			} else {
+				var value [2]uint64
+				err := binary.Read(reader, binary.BigEndian, &value)
+				if err != nil {
+					return nil, err
+				}
+				row[idx] = []float64{math.Float64frombits(value[0]), math.Float64frombits(value[1])}
+			}
+
 		case *parser.DataTypeBool:
 			var len int8
 			err := binary.Read(reader, binary.BigEndian, &len)
@@ -440,7 +534,7 @@ func ReadRow(reader io.Reader, schema types.Schema) (types.Row, error) {
 				row[idx] = set
 			}
 
-		case *parser.DataTypeString:
+		case *parser.DataTypeString, *parser.DataTypeJSON, *parser.DataTypeBitVector:
 			var len int16
 			err := binary.Read(reader, binary.BigEndian, &len)
 			if err != nil {
@@ -47,6 +47,22 @@ func TestWireProtocol_Schema(t *testing.T) {
 			ColumnName: "col8",
 			Type:       parser.NewDataTypeTimestamp(),
 		},
+		&types.PlannerColumn{
+			ColumnName: "col9",
+			Type:       parser.NewDataTypeDouble(),
+		},
+		&types.PlannerColumn{
+			ColumnName: "col10",
+			Type:       parser.NewDataTypeJSON(),
+		},
+		&types.PlannerColumn{
+			ColumnName: "col11",
+			Type:       parser.NewDataTypeGeoPoint(),
+		},
+		&types.PlannerColumn{
+			ColumnName: "col12",
+			Type:       parser.NewDataTypeBitVector(256),
+		},
 	}
 
 	b, err := wireprotocol.WriteSchema(s)
@@ -105,6 +121,22 @@ func TestWireProtocol_Row(t *testing.T) {
 			ColumnName: "col8",
 			Type:       parser.NewDataTypeTimestamp(),
 		},
+		&types.PlannerColumn{
+			ColumnName: "col9",
+			Type:       parser.NewDataTypeDouble(),
+		},
+		&types.PlannerColumn{
+			ColumnName: "col10",
+			Type:       parser.NewDataTypeJSON(),
+		},
+		&types.PlannerColumn{
+			ColumnName: "col11",
+			Type:       parser.NewDataTypeGeoPoint(),
+		},
+		&types.PlannerColumn{
+			ColumnName: "col12",
+			Type:       parser.NewDataTypeBitVector(256),
+		},
 	}
 
 	r := types.Row{
@@ -116,6 +148,10 @@ func TestWireProtocol_Row(t *testing.T) {
 		[]int64{10, 20},
 		bool(false),
 		time.Now().UTC(),
+		float64(-12.5),
+		string(`{"a":{"b":1}}`),
+		[]float64{37.7749295, -122.4194155},
+		string("00ff00ff00ff00ff00ff00ff00ff00ff00ff00ff00ff00ff00ff00ff00ff00ff"),
 	}
 
 	b, err := wireprotocol.WriteRow(r, s)